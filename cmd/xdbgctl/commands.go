@@ -102,6 +102,8 @@ func createCommands() []*cli.Command {
 		createShortcutCommand("limit", "查看限流器状态", "[name]"),
 		createShortcutCommand("cache", "查看缓存统计", "[name]"),
 		createShortcutCommand("config", "查看运行时配置", ""),
+		createShortcutCommand("locks", "查看持有的分布式锁", "[key-prefix]"),
+		createShortcutCommand("permits", "查看持有的信号量许可", "[resource]"),
 	}
 }
 
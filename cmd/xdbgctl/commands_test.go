@@ -325,7 +325,7 @@ func TestCreateCommands(t *testing.T) {
 	}
 
 	expected := []string{"toggle", "disable", "exec", "status", "interactive",
-		"setlog", "stack", "freemem", "pprof", "breaker", "limit", "cache", "config"}
+		"setlog", "stack", "freemem", "pprof", "breaker", "limit", "cache", "config", "locks", "permits"}
 	for _, name := range expected {
 		if !names[name] {
 			t.Errorf("missing command %q", name)
@@ -129,6 +129,8 @@ func createApp() *cli.Command {
   limit [名称]        查看限流器状态
   cache [名称]        查看缓存统计
   config              查看运行时配置
+  locks [key前缀]     查看持有的分布式锁
+  permits [资源]      查看持有的信号量许可
   exit                关闭调试服务`,
 	}
 }
@@ -86,12 +86,15 @@ func createHTTPClient(cfg *Config, options *Options) (*HTTPClient, error) {
 		observer = xmetrics.NoopObserver{}
 	}
 
+	endpoints := newEndpointPool(cfg, options.Logger)
+
 	if options.HTTPClient != nil {
 		return &HTTPClient{
-			client:   options.HTTPClient,
-			baseURL:  cfg.Host,
-			timeout:  cfg.Timeout,
-			observer: observer,
+			client:    options.HTTPClient,
+			baseURL:   cfg.Host,
+			timeout:   cfg.Timeout,
+			observer:  observer,
+			endpoints: endpoints,
 		}, nil
 	}
 
@@ -105,12 +108,14 @@ func createHTTPClient(cfg *Config, options *Options) (*HTTPClient, error) {
 		}
 	}
 
-	return NewHTTPClient(HTTPClientConfig{
+	httpClient := NewHTTPClient(HTTPClientConfig{
 		BaseURL:   cfg.Host,
 		Timeout:   cfg.Timeout,
 		TLSConfig: tlsConfig,
 		Observer:  observer,
-	}), nil
+	})
+	httpClient.endpoints = endpoints
+	return httpClient, nil
 }
 
 // resolvedDefaults 保存从 Options/Config 解析后的运行时默认值。
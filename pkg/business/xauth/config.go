@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 )
@@ -34,6 +35,13 @@ const (
 
 	// DefaultSaaSClientID SaaS 环境默认客户端 ID。
 	DefaultSaaSClientID = "ngsoc"
+
+	// DefaultFailoverThreshold 端点连续失败多少次后熔断并切换到下一个端点。
+	DefaultFailoverThreshold = 3
+
+	// DefaultFailoverCooldown 端点熔断后的冷却时间。
+	// 到期后放行探测请求，探测成功即切回该端点。
+	DefaultFailoverCooldown = 30 * time.Second
 )
 
 // =============================================================================
@@ -105,6 +113,19 @@ type Config struct {
 	// 例如：https://auth.example.com
 	Host string
 
+	// FallbackHosts 备用认证服务地址（可选），按优先级排列。
+	// 格式要求与 Host 相同。配置后启用端点故障转移：Host 为主端点，
+	// 主端点熔断时依次切换到备用端点，主端点恢复后自动切回。
+	FallbackHosts []string
+
+	// FailoverThreshold 端点连续失败多少次后熔断（仅配置 FallbackHosts 时生效）。
+	// 默认 3。
+	FailoverThreshold int
+
+	// FailoverCooldown 端点熔断后的冷却时间（仅配置 FallbackHosts 时生效）。
+	// 冷却结束后放行探测请求，成功则恢复该端点。默认 30 秒。
+	FailoverCooldown time.Duration
+
 	// AllowInsecure 允许使用 http:// 非加密连接。
 	// 设计决策: 默认强制 HTTPS——认证服务传输 Bearer Token 和客户端凭据，
 	// 明文 HTTP 会暴露这些敏感信息。仅在开发/测试环境中启用此选项。
@@ -165,9 +186,18 @@ func (c *Config) Validate() error {
 		return ErrNilConfig
 	}
 
-	if err := c.validateHost(); err != nil {
+	if err := c.validateHost(c.Host); err != nil {
 		return err
 	}
+	for _, host := range c.FallbackHosts {
+		if err := c.validateHost(host); err != nil {
+			return fmt.Errorf("fallback host %q: %w", host, err)
+		}
+	}
+
+	if c.FailoverThreshold < 0 || c.FailoverCooldown < 0 {
+		return ErrInvalidFailover
+	}
 
 	if c.Timeout < 0 {
 		return ErrInvalidTimeout
@@ -180,9 +210,9 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-// validateHost 校验 Host 格式和协议安全性。
-func (c *Config) validateHost() error {
-	host := strings.TrimSpace(c.Host)
+// validateHost 校验 Host（或备用地址）格式和协议安全性。
+func (c *Config) validateHost(host string) error {
+	host = strings.TrimSpace(host)
 	if host == "" {
 		return ErrMissingHost
 	}
@@ -219,6 +249,14 @@ func (c *Config) ApplyDefaults() {
 		c.PlatformDataCacheTTL = DefaultPlatformDataCacheTTL
 	}
 
+	if c.FailoverThreshold == 0 {
+		c.FailoverThreshold = DefaultFailoverThreshold
+	}
+
+	if c.FailoverCooldown == 0 {
+		c.FailoverCooldown = DefaultFailoverCooldown
+	}
+
 	if c.ClientID == "" {
 		c.ClientID = getDefaultClientID()
 	}
@@ -238,6 +276,7 @@ func (c *Config) Clone() *Config {
 	}
 
 	clone := *c
+	clone.FallbackHosts = slices.Clone(c.FallbackHosts)
 	if c.TLS != nil {
 		tlsCopy := *c.TLS
 		clone.TLS = &tlsCopy
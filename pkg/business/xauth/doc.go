@@ -55,6 +55,33 @@
 // 绝对 URL 必须使用 HTTPS（除非 AllowInsecure=true），
 // 防止 Bearer Token 通过明文 HTTP 泄露。
 //
+// # 多端点故障转移
+//
+// Config.FallbackHosts 配置备用认证服务地址后启用故障转移，Config.Host 为主端点：
+//   - 每个端点独立一个 xbreaker 熔断器，连续 FailoverThreshold（默认 3）次
+//     连接失败或 5xx 后熔断，熔断中的端点被跳过，请求按优先级落到下一个端点
+//   - 4xx 等业务错误说明端点可用，直接返回，不切换端点
+//   - 熔断 FailoverCooldown（默认 30s）后进入半开状态，下一次请求作为探测；
+//     每次请求都从主端点开始尝试，主端点探测成功即自动切回
+//   - 所有端点均不可用时返回 ErrNoAvailableEndpoint（错误链包含最后一个端点的错误）
+//
+// 健康跟踪是被动的（基于真实请求结果），不额外发起探测请求；
+// HTTPClient.Endpoints 返回各端点当前状态，状态变化会记录日志。
+// 仅相对路径请求参与故障转移，绝对 URL 请求直接发往指定主机。
+//
+// # 错误分类与重试建议
+//
+// Classify 将认证失败归类为 ErrorKind，调用方据此选择策略：
+//   - KindNetwork / KindServer / KindUnavailable / KindRateLimited：可重试
+//   - KindCredential（401/403、Token 无效）/ KindInvalidRequest / KindCanceled：不应重试
+//
+// RetryDelay 返回建议的重试延迟：服务端返回 Retry-After（429/503）时使用其给出的时间
+// （同时记录在 APIError.RetryAfter），否则按分类取默认值；不可重试时返回 0。
+//
+//	if kind := xauth.Classify(err); kind.Retryable() {
+//	    time.Sleep(xauth.RetryDelay(err))
+//	}
+//
 // # 传输安全
 //
 // Config.Host 必须包含有效的 scheme 和主机名（如 "https://auth.example.com"），
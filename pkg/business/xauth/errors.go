@@ -1,8 +1,11 @@
 package xauth
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"time"
 )
 
 // =============================================================================
@@ -34,6 +37,9 @@ var (
 	// Host 必须包含协议和主机名，例如 "https://auth.example.com"。
 	ErrInvalidHost = errors.New("xauth: invalid host: must include scheme and host (e.g., https://auth.example.com)")
 
+	// ErrInvalidFailover 表示故障转移配置（FailoverThreshold/FailoverCooldown）无效。
+	ErrInvalidFailover = errors.New("xauth: invalid failover config")
+
 	// ErrNilRedisClient 表示 Redis 客户端为 nil。
 	ErrNilRedisClient = errors.New("xauth: nil redis client")
 
@@ -92,6 +98,10 @@ var (
 	// 默认限制为 10MB，超过此限制的响应会被拒绝而非截断。
 	ErrResponseTooLarge = errors.New("xauth: response body exceeds maximum size limit")
 
+	// ErrNoAvailableEndpoint 表示所有认证服务端点均不可用（故障或熔断中）。
+	// 错误链中包含最后一个端点的错误。
+	ErrNoAvailableEndpoint = errors.New("xauth: no available endpoint")
+
 	// ErrUnauthorized 表示认证失败（401）。
 	ErrUnauthorized = errors.New("xauth: unauthorized")
 
@@ -103,6 +113,9 @@ var (
 
 	// ErrServerError 表示服务端错误（5xx）。
 	ErrServerError = errors.New("xauth: server error")
+
+	// ErrRateLimited 表示请求被认证服务限流（429）。
+	ErrRateLimited = errors.New("xauth: rate limited")
 )
 
 // =============================================================================
@@ -206,6 +219,7 @@ func (e *PermanentError) Retryable() bool {
 //   - nil 错误：不需要重试（视为成功）
 //   - 实现 RetryableError 接口：根据 Retryable() 返回值判断
 //   - ErrServerError：可重试
+//   - ErrRateLimited：可重试
 //   - ErrRequestFailed：可重试
 //   - 其他错误：默认不可重试
 func IsRetryable(err error) bool {
@@ -219,7 +233,7 @@ func IsRetryable(err error) bool {
 	}
 
 	// 特定错误类型的可重试判断
-	if errors.Is(err, ErrServerError) || errors.Is(err, ErrRequestFailed) {
+	if errors.Is(err, ErrServerError) || errors.Is(err, ErrRateLimited) || errors.Is(err, ErrRequestFailed) {
 		return true
 	}
 
@@ -245,6 +259,9 @@ type APIError struct {
 	Code       int
 	Message    string
 	Err        error
+
+	// RetryAfter 服务端通过 Retry-After 响应头给出的重试等待时间，未给出时为 0。
+	RetryAfter time.Duration
 }
 
 // NewAPIError 创建 API 错误。
@@ -268,9 +285,9 @@ func (e *APIError) Unwrap() error {
 }
 
 // Retryable 判断 API 错误是否可重试。
-// 5xx 错误视为可重试，4xx 错误视为不可重试。
+// 5xx 与 429 错误视为可重试，其余 4xx 错误视为不可重试。
 func (e *APIError) Retryable() bool {
-	return e.StatusCode >= 500
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
 }
 
 // Is 实现 errors.Is 接口。
@@ -284,8 +301,177 @@ func (e *APIError) Is(target error) bool {
 		return target == ErrForbidden
 	case e.StatusCode == 404:
 		return target == ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return target == ErrRateLimited
 	case e.StatusCode >= 500:
 		return target == ErrServerError
 	}
 	return false
 }
+
+// =============================================================================
+// 错误分类
+// =============================================================================
+
+// ErrorKind 认证失败原因分类，由 [Classify] 返回。
+type ErrorKind int
+
+const (
+	// KindUnknown 无法归类的错误，不可重试。
+	KindUnknown ErrorKind = iota
+
+	// KindCanceled 调用方取消了请求（context.Canceled），不可重试。
+	KindCanceled
+
+	// KindNetwork 网络故障（连接失败、超时、连接重置等），可重试。
+	KindNetwork
+
+	// KindServer 认证服务故障（5xx），可重试。
+	KindServer
+
+	// KindUnavailable 所有认证服务端点均不可用（故障或熔断中），可重试。
+	KindUnavailable
+
+	// KindRateLimited 被认证服务限流（429），可重试，应遵循服务端给出的等待时间。
+	KindRateLimited
+
+	// KindCredential 凭证无效或权限不足（401/403、Token 无效、缺少 Token/API Key），不可重试。
+	KindCredential
+
+	// KindInvalidRequest 请求或配置错误（其余 4xx、参数缺失、响应格式无效），不可重试。
+	KindInvalidRequest
+)
+
+// String 返回分类名称。
+func (k ErrorKind) String() string {
+	switch k {
+	case KindUnknown:
+		return "unknown"
+	case KindCanceled:
+		return "canceled"
+	case KindNetwork:
+		return "network"
+	case KindServer:
+		return "server"
+	case KindUnavailable:
+		return "unavailable"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindCredential:
+		return "credential"
+	case KindInvalidRequest:
+		return "invalid_request"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// Retryable 报告该类错误是否值得重试。
+func (k ErrorKind) Retryable() bool {
+	switch k {
+	case KindNetwork, KindServer, KindUnavailable, KindRateLimited:
+		return true
+	default:
+		return false
+	}
+}
+
+// 各类可重试错误的默认建议重试延迟。
+const (
+	// DefaultNetworkRetryDelay 网络故障的建议重试延迟。
+	DefaultNetworkRetryDelay = 200 * time.Millisecond
+
+	// DefaultServerRetryDelay 认证服务故障或端点不可用的建议重试延迟。
+	DefaultServerRetryDelay = time.Second
+
+	// DefaultRateLimitedRetryDelay 被限流且服务端未给出 Retry-After 时的建议重试延迟。
+	DefaultRateLimitedRetryDelay = time.Second
+)
+
+// Classify 返回错误的失败原因分类，nil 错误返回 KindUnknown。
+//
+// 设计决策: 按错误链从具体到笼统依次判断：调用方取消优先（取消后重试没有意义），
+// 其次 APIError 的状态码（服务端给出的结论最可靠），然后是端点不可用和网络错误，
+// 最后是库内凭证与参数哨兵错误。Classify 与 [IsRetryable] 相互独立：
+// 后者保持对 [RetryableError] 的兼容，自定义错误实现 Retryable 时以其为准。
+func Classify(err error) ErrorKind {
+	if err == nil {
+		return KindUnknown
+	}
+	if errors.Is(err, context.Canceled) {
+		return KindCanceled
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return classifyStatus(apiErr.StatusCode)
+	}
+
+	switch {
+	case errors.Is(err, ErrNoAvailableEndpoint):
+		return KindUnavailable
+	case errors.Is(err, ErrServerError):
+		return KindServer
+	case errors.Is(err, ErrRateLimited):
+		return KindRateLimited
+	case isNetworkError(err):
+		return KindNetwork
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrForbidden),
+		errors.Is(err, ErrTokenInvalid), errors.Is(err, ErrMissingToken),
+		errors.Is(err, ErrMissingAPIKey), errors.Is(err, ErrRefreshTokenNotFound):
+		return KindCredential
+	case errors.Is(err, ErrNilRequest), errors.Is(err, ErrMissingTenantID),
+		errors.Is(err, ErrNotFound), errors.Is(err, ErrResponseInvalid),
+		errors.Is(err, ErrResponseTooLarge):
+		return KindInvalidRequest
+	}
+	return KindUnknown
+}
+
+// classifyStatus 按 HTTP 状态码分类。
+func classifyStatus(status int) ErrorKind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return KindRateLimited
+	case status >= http.StatusInternalServerError:
+		return KindServer
+	case status == http.StatusUnauthorized, status == http.StatusForbidden:
+		return KindCredential
+	case status >= http.StatusBadRequest:
+		return KindInvalidRequest
+	}
+	return KindUnknown
+}
+
+// isNetworkError 判断是否为网络层错误：HTTP 客户端发送失败时包装为 TemporaryError，
+// 调用方也可能直接返回 ErrRequestFailed 或超时错误。
+func isNetworkError(err error) bool {
+	var tempErr *TemporaryError
+	return errors.As(err, &tempErr) ||
+		errors.Is(err, ErrRequestFailed) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+// RetryDelay 返回错误的建议重试延迟，不可重试的错误返回 0。
+//
+// 服务端通过 Retry-After 给出等待时间时（429/503）优先使用，
+// 否则按分类使用 DefaultNetworkRetryDelay / DefaultServerRetryDelay / DefaultRateLimitedRetryDelay。
+// 返回值是单次重试的建议下限，多次重试的退避增长由调用方决定。
+func RetryDelay(err error) time.Duration {
+	kind := Classify(err)
+	if !kind.Retryable() {
+		return 0
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	switch kind {
+	case KindNetwork:
+		return DefaultNetworkRetryDelay
+	case KindRateLimited:
+		return DefaultRateLimitedRetryDelay
+	default:
+		return DefaultServerRetryDelay
+	}
+}
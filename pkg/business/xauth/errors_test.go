@@ -1,8 +1,11 @@
 package xauth
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -191,6 +194,12 @@ func TestAPIError(t *testing.T) {
 		assert.False(t, err.Retryable(), "4xx error should not be retryable")
 	})
 
+	t.Run("retryable - 429", func(t *testing.T) {
+		err := NewAPIError(429, 0, "")
+		assert.True(t, err.Retryable(), "429 error should be retryable")
+		assert.ErrorIs(t, err, ErrRateLimited)
+	})
+
 	t.Run("Is - unauthorized", func(t *testing.T) {
 		err := NewAPIError(401, 0, "")
 		assert.ErrorIs(t, err, ErrUnauthorized, "401 error should match ErrUnauthorized")
@@ -222,3 +231,64 @@ func TestAPIError(t *testing.T) {
 		assert.Nil(t, err.Unwrap(), "Unwrap should return nil")
 	})
 }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorKind
+	}{
+		{"nil", nil, KindUnknown},
+		{"unknown", errors.New("boom"), KindUnknown},
+		{"canceled", NewTemporaryError(fmt.Errorf("xauth: request failed: %w", context.Canceled)), KindCanceled},
+		{"network", NewTemporaryError(errors.New("connection refused")), KindNetwork},
+		{"deadline", fmt.Errorf("wrap: %w", context.DeadlineExceeded), KindNetwork},
+		{"request failed", ErrRequestFailed, KindNetwork},
+		{"5xx", NewAPIError(503, 0, ""), KindServer},
+		{"server error sentinel", ErrServerError, KindServer},
+		{"429", NewAPIError(429, 0, ""), KindRateLimited},
+		{"401", NewAPIError(401, 0, ""), KindCredential},
+		{"403", NewAPIError(403, 0, ""), KindCredential},
+		{"token invalid", fmt.Errorf("verify: %w", ErrTokenInvalid), KindCredential},
+		{"missing api key", ErrMissingAPIKey, KindCredential},
+		{"400", NewAPIError(400, 0, ""), KindInvalidRequest},
+		{"missing tenant", ErrMissingTenantID, KindInvalidRequest},
+		{"no endpoint", fmt.Errorf("%w: %w", ErrNoAvailableEndpoint, NewAPIError(502, 0, "")), KindServer},
+		{"no endpoint network", fmt.Errorf("%w: %w", ErrNoAvailableEndpoint, NewTemporaryError(errors.New("dial"))), KindUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	retryable := map[ErrorKind]bool{
+		KindUnknown:        false,
+		KindCanceled:       false,
+		KindNetwork:        true,
+		KindServer:         true,
+		KindUnavailable:    true,
+		KindRateLimited:    true,
+		KindCredential:     false,
+		KindInvalidRequest: false,
+	}
+	for kind, want := range retryable {
+		assert.Equal(t, want, kind.Retryable(), kind.String())
+	}
+	assert.Equal(t, "rate_limited", KindRateLimited.String())
+	assert.Equal(t, "ErrorKind(99)", ErrorKind(99).String())
+}
+
+func TestRetryDelay(t *testing.T) {
+	limited := NewAPIError(429, 0, "")
+	limited.RetryAfter = 5 * time.Second
+
+	assert.Zero(t, RetryDelay(nil))
+	assert.Zero(t, RetryDelay(NewAPIError(401, 0, "")), "credential errors are not retried")
+	assert.Equal(t, DefaultNetworkRetryDelay, RetryDelay(NewTemporaryError(errors.New("reset"))))
+	assert.Equal(t, DefaultServerRetryDelay, RetryDelay(NewAPIError(500, 0, "")))
+	assert.Equal(t, DefaultRateLimitedRetryDelay, RetryDelay(NewAPIError(429, 0, "")))
+	assert.Equal(t, 5*time.Second, RetryDelay(fmt.Errorf("wrap: %w", limited)))
+}
@@ -0,0 +1,175 @@
+package xauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/omeyang/xkit/pkg/resilience/xbreaker"
+)
+
+// =============================================================================
+// 多端点故障转移
+// =============================================================================
+
+// EndpointStatus 认证服务端点的健康状态。
+type EndpointStatus struct {
+	// Host 端点地址。
+	Host string
+
+	// Primary 是否为主端点（Config.Host）。
+	Primary bool
+
+	// State 端点熔断器状态：Closed 健康，Open 熔断中，HalfOpen 冷却结束等待探测。
+	State xbreaker.State
+}
+
+// endpoint 单个认证服务端点及其熔断器。
+type endpoint struct {
+	host    string
+	breaker *xbreaker.Breaker
+}
+
+// endpointPool 按优先级排列的端点集合（首个为主端点）。
+//
+// 设计决策: 健康跟踪采用被动方式——复用 xbreaker 按端点统计连续失败，
+// 不额外启动主动探测 goroutine。熔断冷却结束后 gobreaker 进入半开状态，
+// 下一次请求即作为探测；由于每次请求都从主端点开始尝试，
+// 主端点探测成功后流量自然切回，无需额外的"切回"状态机。
+type endpointPool struct {
+	endpoints []*endpoint
+	logger    *slog.Logger
+}
+
+// newEndpointPool 根据配置创建端点集合，未配置 FallbackHosts 时返回 nil（不启用故障转移）。
+func newEndpointPool(cfg *Config, logger *slog.Logger) *endpointPool {
+	if len(cfg.FallbackHosts) == 0 {
+		return nil
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	threshold := cfg.FailoverThreshold
+	if threshold <= 0 {
+		threshold = DefaultFailoverThreshold
+	}
+	cooldown := cfg.FailoverCooldown
+	if cooldown <= 0 {
+		cooldown = DefaultFailoverCooldown
+	}
+
+	hosts := append([]string{cfg.Host}, cfg.FallbackHosts...)
+	p := &endpointPool{
+		endpoints: make([]*endpoint, 0, len(hosts)),
+		logger:    logger,
+	}
+	for _, host := range hosts {
+		p.endpoints = append(p.endpoints, &endpoint{
+			host: host,
+			breaker: xbreaker.NewBreaker("xauth:"+host,
+				xbreaker.WithTripPolicy(xbreaker.NewConsecutiveFailures(uint32(threshold))), //nolint:gosec // G115: threshold 已由 Validate 保证非负
+				xbreaker.WithTimeout(cooldown),
+				xbreaker.WithOnStateChange(p.logStateChange),
+			),
+		})
+	}
+	return p
+}
+
+// logStateChange 记录端点熔断器状态变化。
+func (p *endpointPool) logStateChange(name string, from, to xbreaker.State) {
+	level := slog.LevelInfo
+	if to == xbreaker.StateOpen {
+		level = slog.LevelWarn
+	}
+	p.logger.Log(context.Background(), level, "xauth endpoint state changed",
+		slog.String("endpoint", name),
+		slog.String("from", from.String()),
+		slog.String("to", to.String()),
+	)
+}
+
+// do 按优先级依次在端点上执行 send，直到某个端点给出非故障结果。
+//
+// 连接失败和 5xx 视为端点故障，计入熔断统计并尝试下一个端点；
+// 4xx 等业务错误说明端点可用，直接返回，不切换端点。
+// 熔断中的端点被跳过。所有端点均不可用时返回 ErrNoAvailableEndpoint 包装的最后一个错误。
+func (p *endpointPool) do(ctx context.Context, body any, send func(baseURL string, body any) error) error {
+	// 设计决策: io.Reader 只能读取一次，切换端点前需要重放，因此先读入内存。
+	// 认证请求体很小，缓冲开销可忽略。
+	if r, ok := body.(io.Reader); ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("xauth: read request body failed: %w", err)
+		}
+		body = data
+	}
+
+	var lastErr error
+	for i, ep := range p.endpoints {
+		var sendErr error
+		err := ep.breaker.Do(ctx, func() error {
+			sendErr = send(ep.host, body)
+			if isEndpointFailure(ctx, sendErr) {
+				return sendErr
+			}
+			return nil
+		})
+		if err == nil {
+			return sendErr
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return err
+		}
+		lastErr = err
+		if i+1 < len(p.endpoints) {
+			p.logger.Debug("xauth endpoint unavailable, failing over",
+				slog.String("endpoint", ep.host),
+				slog.String("next", p.endpoints[i+1].host),
+				slog.Any("error", err),
+			)
+		}
+	}
+	return fmt.Errorf("%w: %w", ErrNoAvailableEndpoint, lastErr)
+}
+
+// statuses 返回所有端点的当前状态。
+func (p *endpointPool) statuses() []EndpointStatus {
+	out := make([]EndpointStatus, 0, len(p.endpoints))
+	for i, ep := range p.endpoints {
+		out = append(out, EndpointStatus{
+			Host:    ep.host,
+			Primary: i == 0,
+			State:   ep.breaker.State(),
+		})
+	}
+	return out
+}
+
+// hasHost 判断 host（不含 scheme）是否属于任一端点。
+func (p *endpointPool) hasHost(host string) bool {
+	for _, ep := range p.endpoints {
+		if hostMatches(ep.host, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEndpointFailure 判断请求错误是否说明端点不可用。
+// 调用方取消/超时导致的错误不计入端点故障。
+func isEndpointFailure(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var tempErr *TemporaryError
+	return errors.As(err, &tempErr)
+}
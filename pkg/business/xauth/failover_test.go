@@ -0,0 +1,152 @@
+package xauth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/pkg/resilience/xbreaker"
+)
+
+// failoverServer 返回一个可切换健康状态的测试服务器及其请求计数。
+func failoverServer(t *testing.T, healthy *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"echo":"` + string(body) + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func newFailoverHTTPClient(t *testing.T, cfg *Config) *HTTPClient {
+	t.Helper()
+	cfg.AllowInsecure = true
+	cfg.ApplyDefaults()
+	require.NoError(t, cfg.Validate())
+	c, err := createHTTPClient(cfg, defaultOptions())
+	require.NoError(t, err)
+	return c
+}
+
+func TestFailover_SwitchesAndRecovers(t *testing.T) {
+	var primaryHealthy, backupHealthy atomic.Bool
+	backupHealthy.Store(true)
+	primary, primaryHits := failoverServer(t, &primaryHealthy)
+	backup, backupHits := failoverServer(t, &backupHealthy)
+
+	c := newFailoverHTTPClient(t, &Config{
+		Host:              primary.URL,
+		FallbackHosts:     []string{backup.URL},
+		FailoverThreshold: 2,
+		FailoverCooldown:  50 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	var resp struct{ Echo string }
+	for range 3 {
+		require.NoError(t, c.Post(ctx, "/x", nil, strings.NewReader("hi"), &resp))
+		assert.Equal(t, "hi", resp.Echo, "body is replayed on failover")
+	}
+	assert.Equal(t, int32(2), primaryHits.Load(), "primary skipped once tripped")
+	assert.Equal(t, int32(3), backupHits.Load())
+
+	statuses := c.Endpoints()
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Primary)
+	assert.Equal(t, xbreaker.StateOpen, statuses[0].State)
+	assert.Equal(t, xbreaker.StateClosed, statuses[1].State)
+
+	// 主端点恢复，冷却结束后的首个请求探测成功即切回
+	primaryHealthy.Store(true)
+	time.Sleep(80 * time.Millisecond)
+	require.NoError(t, c.Get(ctx, "/x", nil, &resp))
+	assert.Equal(t, int32(3), primaryHits.Load())
+	assert.Equal(t, int32(3), backupHits.Load())
+	assert.Equal(t, xbreaker.StateClosed, c.Endpoints()[0].State)
+}
+
+func TestFailover_ClientErrorDoesNotFailover(t *testing.T) {
+	var backupHealthy atomic.Bool
+	backupHealthy.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(primary.Close)
+	backup, backupHits := failoverServer(t, &backupHealthy)
+
+	c := newFailoverHTTPClient(t, &Config{Host: primary.URL, FallbackHosts: []string{backup.URL}})
+
+	err := c.Get(context.Background(), "/x", nil, nil)
+	require.ErrorIs(t, err, ErrUnauthorized)
+	assert.Zero(t, backupHits.Load())
+	assert.Equal(t, xbreaker.StateClosed, c.Endpoints()[0].State)
+}
+
+func TestFailover_AllEndpointsDown(t *testing.T) {
+	var down atomic.Bool
+	primary, _ := failoverServer(t, &down)
+	backup, _ := failoverServer(t, &down)
+
+	c := newFailoverHTTPClient(t, &Config{Host: primary.URL, FallbackHosts: []string{backup.URL}})
+
+	err := c.Get(context.Background(), "/x", nil, nil)
+	require.ErrorIs(t, err, ErrNoAvailableEndpoint)
+	require.ErrorIs(t, err, ErrServerError)
+}
+
+func TestFailover_DisabledWithoutFallbackHosts(t *testing.T) {
+	c := newFailoverHTTPClient(t, &Config{Host: "http://auth.example.com"})
+	assert.Nil(t, c.endpoints)
+	assert.Nil(t, c.Endpoints())
+}
+
+func TestFailover_DoAllowsFallbackHost(t *testing.T) {
+	c := newFailoverHTTPClient(t, &Config{
+		Host:          "http://primary.example.com",
+		FallbackHosts: []string{"http://backup.example.com"},
+	})
+	req, err := http.NewRequest(http.MethodGet, "http://backup.example.com/x", nil)
+	require.NoError(t, err)
+	require.NoError(t, c.validateRequestHost(req))
+
+	req, err = http.NewRequest(http.MethodGet, "http://evil.example.com/x", nil)
+	require.NoError(t, err)
+	require.Error(t, c.validateRequestHost(req))
+}
+
+func TestConfig_ValidateFailover(t *testing.T) {
+	cfg := &Config{Host: "https://a.example.com", FallbackHosts: []string{"http://b.example.com"}}
+	require.ErrorIs(t, cfg.Validate(), ErrInsecureHost)
+
+	cfg = &Config{Host: "https://a.example.com", FallbackHosts: []string{"b.example.com"}}
+	require.ErrorIs(t, cfg.Validate(), ErrInvalidHost)
+
+	cfg = &Config{Host: "https://a.example.com", FailoverThreshold: -1}
+	require.ErrorIs(t, cfg.Validate(), ErrInvalidFailover)
+
+	cfg = &Config{Host: "https://a.example.com", FallbackHosts: []string{"https://b.example.com"}}
+	cfg.ApplyDefaults()
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, DefaultFailoverThreshold, cfg.FailoverThreshold)
+	assert.Equal(t, DefaultFailoverCooldown, cfg.FailoverCooldown)
+
+	clone := cfg.Clone()
+	clone.FallbackHosts[0] = "https://c.example.com"
+	assert.Equal(t, "https://b.example.com", cfg.FallbackHosts[0], "Clone copies FallbackHosts")
+}
@@ -44,20 +44,20 @@ func FuzzConfigValidateHost(f *testing.F) {
 	f.Fuzz(func(t *testing.T, host string) {
 		// 严格模式：必须 https
 		strict := &Config{Host: host}
-		err := strict.validateHost()
+		err := strict.validateHost(strict.Host)
 		if err != nil && !isAllowed(err) {
 			t.Fatalf("strict: unexpected error type for host=%q: %v", host, err)
 		}
 
 		// 宽松模式：允许 http
 		loose := &Config{Host: host, AllowInsecure: true}
-		err = loose.validateHost()
+		err = loose.validateHost(loose.Host)
 		if err != nil && !isAllowed(err) {
 			t.Fatalf("loose: unexpected error type for host=%q: %v", host, err)
 		}
 
 		// 关键不变量：strict 通过则 loose 必通过
-		if strict.validateHost() == nil && loose.validateHost() != nil {
+		if strict.validateHost(strict.Host) == nil && loose.validateHost(loose.Host) != nil {
 			t.Fatalf("loose stricter than strict for host=%q", host)
 		}
 	})
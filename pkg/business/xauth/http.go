@@ -10,6 +10,7 @@ import (
 	"maps"
 	"net/http"
 	neturl "net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,6 +33,9 @@ type HTTPClient struct {
 	baseURL  string
 	timeout  time.Duration
 	observer xmetrics.Observer
+
+	// endpoints 多端点故障转移（仅配置 FallbackHosts 时非 nil）
+	endpoints *endpointPool
 }
 
 // HTTPClientConfig HTTP 客户端配置。
@@ -138,12 +142,27 @@ func (c *HTTPClient) validateRequestHost(req *http.Request) error {
 	if err != nil || base.Host == "" {
 		return nil
 	}
-	if !strings.EqualFold(req.URL.Host, base.Host) {
+	if !strings.EqualFold(req.URL.Host, base.Host) && (c.endpoints == nil || !c.endpoints.hasHost(req.URL.Host)) {
 		return fmt.Errorf("xauth: request host %q not in allow-list (base %q)", req.URL.Host, base.Host)
 	}
 	return nil
 }
 
+// hostMatches 判断 baseURL 的主机部分是否与 host 一致（大小写不敏感）。
+func hostMatches(baseURL, host string) bool {
+	u, err := neturl.Parse(baseURL)
+	return err == nil && u.Host != "" && strings.EqualFold(u.Host, host)
+}
+
+// Endpoints 返回各认证服务端点的健康状态（主端点在前）。
+// 未配置 Config.FallbackHosts（未启用故障转移）时返回 nil。
+func (c *HTTPClient) Endpoints() []EndpointStatus {
+	if c.endpoints == nil {
+		return nil
+	}
+	return c.endpoints.statuses()
+}
+
 // Get 发送 GET 请求。
 func (c *HTTPClient) Get(ctx context.Context, path string, headers map[string]string, response any) error {
 	return c.request(ctx, http.MethodGet, path, headers, nil, response)
@@ -157,14 +176,28 @@ func (c *HTTPClient) Post(ctx context.Context, path string, headers map[string]s
 // request 发送 HTTP 请求。
 // path 可以是相对路径（如 "/api/token"）或完整 URL（如 "https://host.com/api/token"）。
 // 如果 path 是完整 URL，则直接使用；否则与 baseURL 拼接。
+// 启用故障转移时，相对路径按端点优先级依次尝试（见 endpointPool.do）。
 func (c *HTTPClient) request(
 	ctx context.Context,
 	method, path string,
 	headers map[string]string,
 	body, response any,
 ) error {
-	url := c.buildURL(path)
+	if c.endpoints == nil || isAbsoluteURL(path) {
+		return c.send(ctx, method, c.buildURL(path), headers, body, response)
+	}
+	return c.endpoints.do(ctx, body, func(baseURL string, body any) error {
+		return c.send(ctx, method, baseURL+path, headers, body, response)
+	})
+}
 
+// send 向完整 URL 发送一次 HTTP 请求。
+func (c *HTTPClient) send(
+	ctx context.Context,
+	method, url string,
+	headers map[string]string,
+	body, response any,
+) error {
 	// 开始 HTTP 请求观测
 	// 使用 sanitizeURL 去除查询参数，避免高基数问题
 	ctx, span := xmetrics.Start(ctx, c.observer, xmetrics.SpanOptions{
@@ -288,7 +321,7 @@ func (c *HTTPClient) handleResponse(resp *http.Response, response any) error {
 	}
 
 	if resp.StatusCode >= 400 {
-		return c.parseAPIError(resp.StatusCode, respBody)
+		return c.parseAPIError(resp.StatusCode, resp.Header, respBody)
 	}
 
 	if response != nil && len(respBody) > 0 {
@@ -300,15 +333,31 @@ func (c *HTTPClient) handleResponse(resp *http.Response, response any) error {
 	return nil
 }
 
-// parseAPIError 解析 API 错误响应。
-func (c *HTTPClient) parseAPIError(statusCode int, respBody []byte) error {
+// parseAPIError 解析 API 错误响应，并记录 Retry-After 响应头给出的重试等待时间。
+func (c *HTTPClient) parseAPIError(statusCode int, header http.Header, respBody []byte) error {
 	var apiResp struct {
 		Code    int    `json:"code"`
 		Message string `json:"message"`
 	}
 	// 解析失败不影响错误处理，apiResp 使用零值
 	_ = json.Unmarshal(respBody, &apiResp) //nolint:errcheck // 解析失败使用零值即可
-	return NewAPIError(statusCode, apiResp.Code, apiResp.Message)
+	apiErr := NewAPIError(statusCode, apiResp.Code, apiResp.Message)
+	apiErr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+	return apiErr
+}
+
+// parseRetryAfter 解析 Retry-After 响应头（秒数或 HTTP 日期），无效或已过期时返回 0。
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0)
+	}
+	return 0
 }
 
 // RequestWithAuth 发送带认证的请求。
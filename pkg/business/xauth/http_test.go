@@ -167,6 +167,27 @@ func TestHTTPClient_Post(t *testing.T) {
 	})
 }
 
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"empty", "", 0},
+		{"seconds", "5", 5 * time.Second},
+		{"negative seconds", "-5", 0},
+		{"http date", now.Add(10 * time.Second).Format(http.TimeFormat), 10 * time.Second},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"invalid", "soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseRetryAfter(tt.value, now))
+		})
+	}
+}
+
 func TestHTTPClient_Request_Errors(t *testing.T) {
 	ctx := context.Background()
 
@@ -212,6 +233,22 @@ func TestHTTPClient_Request_Errors(t *testing.T) {
 		assert.True(t, apiErr.Retryable(), "5xx error should be retryable")
 	})
 
+	t.Run("429 with Retry-After", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := NewHTTPClient(HTTPClientConfig{BaseURL: server.URL})
+
+		err := client.Get(ctx, "/test", nil, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrRateLimited)
+		assert.Equal(t, KindRateLimited, Classify(err))
+		assert.Equal(t, 3*time.Second, RetryDelay(err))
+	})
+
 	t.Run("network error", func(t *testing.T) {
 		client := NewHTTPClient(HTTPClientConfig{
 			BaseURL: "http://localhost:1", // Invalid port
@@ -0,0 +1,212 @@
+package xconf
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// AccessRecord 敏感配置键的一次读取记录。
+type AccessRecord struct {
+	// Key 被读取的敏感键（WithAccessAudit 中标记的键）。
+	Key string
+
+	// Path 实际请求的路径。按父路径整段读取（如 Unmarshal("db")）时为父路径，
+	// 读取整个配置时为空字符串。
+	Path string
+
+	// Func 发起读取的函数全名（xconf 包外的第一个调用方）。
+	Func string
+
+	// Caller 发起读取的源码位置，格式为 "file:line"。
+	Caller string
+
+	// Time 读取时间。
+	Time time.Time
+}
+
+// AccessAuditFunc 接收敏感配置键的访问审计记录。
+// 在读取路径上同步调用，必须并发安全且足够轻量，需要 I/O 时应自行异步投递。
+type AccessAuditFunc func(rec AccessRecord)
+
+// WithAccessAudit 启用敏感配置键的访问审计。
+//
+// 通过 [View] 的读取方法或 Unmarshal 读取 keys 中的键时调用 fn，记录读取的键与调用方位置，
+// 用于合规审计和排查"某配置被谁读取"。keys 的匹配规则：
+//   - 精确读取该键，或读取其下的子键（标记 "secrets" 时读取 "secrets.token" 同样审计）
+//   - 读取其父路径（Unmarshal("db") 会读到标记的 "db.password"）或整个配置
+//
+// 设计决策: 仅审计标记的键，未命中时只做字符串前缀比较，不采集调用栈，
+// 避免对全部配置读取付出 runtime.Callers 的开销。
+// Client() 返回的 koanf 实例直接读取底层数据，不经过审计；需要审计的代码路径
+// 应使用 NewView 返回的只读视图或 Unmarshal。
+//
+// fn 为 nil 时 New/NewFromBytes 返回 ErrNilAccessAudit；未提供 keys 时不审计任何读取。
+// fn 中的 panic 会被捕获并通过 slog.Error 记录，不影响读取结果。
+func WithAccessAudit(fn AccessAuditFunc, keys ...string) Option {
+	return func(o *options) {
+		o.accessAudit = fn
+		o.auditKeys = append(o.auditKeys[:0:0], keys...)
+		o.accessAuditSet = true
+	}
+}
+
+// View 配置的只读视图。
+//
+// View 只暴露读取方法，可以交给不应修改配置的模块使用（koanf 实例的 Set/Load/Delete
+// 会改变全局配置）。每次读取都基于最新的配置快照，Reload 后自动生效；
+// Get 返回的 map/slice 是副本，修改它们不会影响配置。
+//
+// 配置了 WithAccessAudit 时，读取敏感键会记录访问审计。
+type View struct {
+	cfg Config
+}
+
+// NewView 返回 cfg 的只读视图。
+// cfg 为 nil 时返回 ErrNilConfig。非 xconf 创建的 Config 实现（如 mock）不记录访问审计。
+func NewView(cfg Config) (*View, error) {
+	if cfg == nil {
+		return nil, ErrNilConfig
+	}
+	if rv := reflect.ValueOf(cfg); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, fmt.Errorf("%w: typed-nil %T", ErrNilConfig, cfg)
+	}
+	return &View{cfg: cfg}, nil
+}
+
+// Get 返回 key 对应的原始值，不存在时返回 nil。
+// key 为空字符串时返回整个配置。
+func (v *View) Get(key string) any {
+	v.audit(key)
+	return v.cfg.Client().Get(key)
+}
+
+// String 返回 key 对应的字符串值，不存在时返回空字符串。
+func (v *View) String(key string) string {
+	v.audit(key)
+	return v.cfg.Client().String(key)
+}
+
+// Strings 返回 key 对应的字符串切片，不存在时返回空切片。
+func (v *View) Strings(key string) []string {
+	v.audit(key)
+	return v.cfg.Client().Strings(key)
+}
+
+// Int 返回 key 对应的整数值，不存在或无法转换时返回 0。
+func (v *View) Int(key string) int {
+	v.audit(key)
+	return v.cfg.Client().Int(key)
+}
+
+// Int64 返回 key 对应的 int64 值，不存在或无法转换时返回 0。
+func (v *View) Int64(key string) int64 {
+	v.audit(key)
+	return v.cfg.Client().Int64(key)
+}
+
+// Float64 返回 key 对应的浮点值，不存在或无法转换时返回 0。
+func (v *View) Float64(key string) float64 {
+	v.audit(key)
+	return v.cfg.Client().Float64(key)
+}
+
+// Bool 返回 key 对应的布尔值，不存在或无法转换时返回 false。
+func (v *View) Bool(key string) bool {
+	v.audit(key)
+	return v.cfg.Client().Bool(key)
+}
+
+// Duration 返回 key 对应的时长，支持 "10s" 形式的字符串和纳秒数值。
+func (v *View) Duration(key string) time.Duration {
+	v.audit(key)
+	return v.cfg.Client().Duration(key)
+}
+
+// Exists 报告 key 是否存在。只检查键、不读取值，不记录访问审计。
+func (v *View) Exists(key string) bool {
+	return v.cfg.Client().Exists(key)
+}
+
+// Keys 返回全部叶子键。只列出键、不读取值，不记录访问审计。
+func (v *View) Keys() []string {
+	return v.cfg.Client().Keys()
+}
+
+// Unmarshal 将指定路径的配置反序列化到目标结构体，语义同 Config.Unmarshal。
+func (v *View) Unmarshal(path string, target any) error {
+	return v.cfg.Unmarshal(path, target)
+}
+
+// audit 对 xconf 创建的配置记录访问审计。
+func (v *View) audit(path string) {
+	if kc, ok := v.cfg.(*koanfConfig); ok {
+		kc.auditAccess(path)
+	}
+}
+
+// auditAccess 读取 path 覆盖敏感键时记录访问审计。
+func (c *koanfConfig) auditAccess(path string) {
+	o := c.opts
+	if o.accessAudit == nil || len(o.auditKeys) == 0 {
+		return
+	}
+	var matched []string
+	for _, key := range o.auditKeys {
+		if pathCovers(path, key, o.delim) {
+			matched = append(matched, key)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	fn, caller := externalCaller()
+	now := time.Now()
+	for _, key := range matched {
+		safeAudit(o.accessAudit, AccessRecord{Key: key, Path: path, Func: fn, Caller: caller, Time: now})
+	}
+}
+
+// pathCovers 报告读取 path 是否会读到 key：两者相同、path 是 key 的父路径（含整个配置），
+// 或 path 位于 key 之下。
+func pathCovers(path, key, delim string) bool {
+	return path == "" || path == key ||
+		strings.HasPrefix(key, path+delim) ||
+		strings.HasPrefix(path, key+delim)
+}
+
+// xconfPkgPrefix xconf 包内函数全名的前缀，用于在调用栈中跳过包内帧。
+var xconfPkgPrefix = reflect.TypeFor[koanfConfig]().PkgPath() + "."
+
+// externalCaller 返回调用栈中 xconf 包外的第一个调用方。
+//
+// 设计决策: 逐帧跳过 xconf 包内函数而非固定 skip 层数，View、Unmarshal、MustUnmarshal
+// 等入口的调用深度不同。包内 _test.go 中的函数视为外部调用方。
+func externalCaller() (fn, caller string) {
+	var pcs [16]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, xconfPkgPrefix) || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.Function, fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "", ""
+		}
+	}
+}
+
+// safeAudit 调用审计函数并捕获 panic，避免审计故障影响配置读取。
+func safeAudit(fn AccessAuditFunc, rec AccessRecord) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("xconf: access audit func panicked", "key", rec.Key, "panic", r)
+		}
+	}()
+	fn(rec)
+}
@@ -0,0 +1,201 @@
+package xconf
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const accessTestYAML = `
+db:
+  host: localhost
+  password: s3cret
+  timeout: 3s
+secrets:
+  token: abc
+  ttl: 60
+tags: [a, b]
+debug: true
+ratio: 0.5
+`
+
+// accessRecorder 收集访问审计记录
+type accessRecorder struct {
+	mu      sync.Mutex
+	records []AccessRecord
+}
+
+func (r *accessRecorder) audit(rec AccessRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+}
+
+// take 返回并清空已收集的记录
+func (r *accessRecorder) take() []AccessRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.records
+	r.records = nil
+	return out
+}
+
+// keysOf 返回记录中的 Key/Path 对
+func keysOf(records []AccessRecord) [][2]string {
+	out := make([][2]string, 0, len(records))
+	for _, rec := range records {
+		out = append(out, [2]string{rec.Key, rec.Path})
+	}
+	return out
+}
+
+func newAuditedView(t *testing.T, rec *accessRecorder) (Config, *View) {
+	t.Helper()
+	cfg, err := NewFromBytes([]byte(accessTestYAML), FormatYAML,
+		WithAccessAudit(rec.audit, "db.password", "secrets"))
+	require.NoError(t, err)
+	v, err := NewView(cfg)
+	require.NoError(t, err)
+	return cfg, v
+}
+
+func TestView_Getters(t *testing.T) {
+	_, v := newAuditedView(t, &accessRecorder{})
+
+	assert.Equal(t, "localhost", v.String("db.host"))
+	assert.Equal(t, 60, v.Int("secrets.ttl"))
+	assert.Equal(t, int64(60), v.Int64("secrets.ttl"))
+	assert.Equal(t, 0.5, v.Float64("ratio"))
+	assert.True(t, v.Bool("debug"))
+	assert.Equal(t, 3*time.Second, v.Duration("db.timeout"))
+	assert.Equal(t, []string{"a", "b"}, v.Strings("tags"))
+	assert.True(t, v.Exists("db.host"))
+	assert.False(t, v.Exists("db.missing"))
+	assert.Contains(t, v.Keys(), "secrets.token")
+	assert.Nil(t, v.Get("db.missing"))
+
+	var db struct {
+		Host string `koanf:"host"`
+	}
+	require.NoError(t, v.Unmarshal("db", &db))
+	assert.Equal(t, "localhost", db.Host)
+}
+
+func TestView_GetReturnsCopy(t *testing.T) {
+	_, v := newAuditedView(t, &accessRecorder{})
+
+	m, ok := v.Get("db").(map[string]any)
+	require.True(t, ok)
+	m["host"] = "changed"
+	assert.Equal(t, "localhost", v.String("db.host"))
+}
+
+func TestView_FollowsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("name: v1\n"), 0o600))
+	cfg, err := New(path)
+	require.NoError(t, err)
+	v, err := NewView(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", v.String("name"))
+
+	require.NoError(t, os.WriteFile(path, []byte("name: v2\n"), 0o600))
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, "v2", v.String("name"))
+}
+
+func TestAccessAudit_SensitiveKeysOnly(t *testing.T) {
+	rec := &accessRecorder{}
+	_, v := newAuditedView(t, rec)
+
+	_ = v.String("db.host")
+	_ = v.Exists("db.password")
+	_ = v.Keys()
+	assert.Empty(t, rec.take(), "non-sensitive reads and key listing are not audited")
+
+	_ = v.String("db.password")
+	records := rec.take()
+	require.Len(t, records, 1)
+	assert.Equal(t, "db.password", records[0].Key)
+	assert.Equal(t, "db.password", records[0].Path)
+	assert.Contains(t, records[0].Func, "TestAccessAudit_SensitiveKeysOnly")
+	assert.Contains(t, records[0].Caller, "access_test.go:")
+	assert.WithinDuration(t, time.Now(), records[0].Time, time.Second)
+}
+
+func TestAccessAudit_PathMatching(t *testing.T) {
+	rec := &accessRecorder{}
+	cfg, v := newAuditedView(t, rec)
+
+	// 读取被标记子树下的键
+	_ = v.Int("secrets.ttl")
+	assert.Equal(t, [][2]string{{"secrets", "secrets.ttl"}}, keysOf(rec.take()))
+
+	// 按父路径整段读取
+	_ = v.Get("db")
+	assert.Equal(t, [][2]string{{"db.password", "db"}}, keysOf(rec.take()))
+
+	var out map[string]any
+	require.NoError(t, cfg.Unmarshal("", &out))
+	assert.Equal(t, [][2]string{{"db.password", ""}, {"secrets", ""}}, keysOf(rec.take()))
+
+	// 前缀相同但不是子键
+	_ = v.Get("db.pass")
+	assert.Empty(t, rec.take())
+}
+
+func TestAccessAudit_UnmarshalCaller(t *testing.T) {
+	rec := &accessRecorder{}
+	cfg, _ := newAuditedView(t, rec)
+
+	var db struct {
+		Password string `koanf:"password"`
+	}
+	MustUnmarshal(cfg, "db", &db)
+	assert.Equal(t, "s3cret", db.Password)
+
+	records := rec.take()
+	require.Len(t, records, 1)
+	assert.Contains(t, records[0].Func, "TestAccessAudit_UnmarshalCaller", "xconf frames are skipped")
+	assert.Contains(t, records[0].Caller, "access_test.go:")
+}
+
+func TestAccessAudit_PanicRecovered(t *testing.T) {
+	cfg, err := NewFromBytes([]byte(accessTestYAML), FormatYAML,
+		WithAccessAudit(func(AccessRecord) { panic("audit failure") }, "db.password"))
+	require.NoError(t, err)
+	v, err := NewView(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "s3cret", v.String("db.password"))
+}
+
+func TestAccessAudit_NoKeys(t *testing.T) {
+	called := false
+	cfg, err := NewFromBytes([]byte(accessTestYAML), FormatYAML,
+		WithAccessAudit(func(AccessRecord) { called = true }))
+	require.NoError(t, err)
+
+	var out map[string]any
+	require.NoError(t, cfg.Unmarshal("", &out))
+	assert.False(t, called)
+}
+
+func TestAccessAudit_NilFunc(t *testing.T) {
+	_, err := NewFromBytes([]byte(accessTestYAML), FormatYAML, WithAccessAudit(nil, "db.password"))
+	assert.ErrorIs(t, err, ErrNilAccessAudit)
+}
+
+func TestNewView_NilConfig(t *testing.T) {
+	_, err := NewView(nil)
+	assert.ErrorIs(t, err, ErrNilConfig)
+
+	var kc *koanfConfig
+	_, err = NewView(kc)
+	assert.ErrorIs(t, err, ErrNilConfig)
+}
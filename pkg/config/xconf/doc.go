@@ -4,7 +4,10 @@
 //
 // xconf 定位为最小化配置加载器，负责文件/字节数据的加载、反序列化和热重载。
 // 不负责配置治理（必选字段校验、默认值注入、环境变量覆盖），
-// 这些能力由上层业务框架按需实现。
+// 这些能力由上层业务框架按需实现。可选的变量插值（WithInterpolation）
+// 只在加载时替换显式写出的 ${...} 引用，不属于覆盖机制。
+// ExportSchema 导出的 schema 用于文档生成，其 Validate 只检查必填键是否存在，
+// 不做值校验。
 //
 // xconf 采用与 xcache/xmq 相同的设计模式：
 //   - 工厂函数：New, NewFromBytes
@@ -41,6 +44,53 @@
 //
 //	xconf.MustUnmarshal(cfg, "database", &dbConfig) // 失败时 panic
 //
+// # 变量插值
+//
+// WithInterpolation 启用加载时的 ${...} 引用解析（默认关闭），New/NewFromBytes/Reload 均生效：
+//
+//	# config.yaml
+//	base:
+//	  dir: ${HOME}/app          # 环境变量
+//	log:
+//	  dir: ${base.dir}/logs     # 其他配置键，被引用值中的引用会递归解析
+//	  level: ${LOG_LEVEL:-info} # 未定义时使用默认值
+//
+//	cfg, err := xconf.New("config.yaml", xconf.WithInterpolation())
+//
+// 引用先按配置键路径查找，不存在时回退到环境变量。
+// 未定义且无默认值返回 ErrUndefinedVariable，循环引用返回 ErrInterpolationCycle
+// （错误信息包含引用链，如 "a -> b -> a"）；Reload 失败时保留旧配置。
+//
+// # 配置 schema 导出
+//
+// ExportSchema 从配置结构体（含标签）导出全部配置键、类型、默认值与是否必填，
+// 用于生成配置文档和做必填项校验，便于团队维护大型配置：
+//
+//	s, err := xconf.ExportSchema(DefaultConfig())
+//	doc := s.Markdown()            // Markdown 表格
+//	raw, err := s.JSONSchema()     // JSON Schema，可交给编辑器或外部校验工具
+//	err = s.Validate(cfg)          // 必填键缺失时返回 ErrMissingRequired
+//
+// 默认值取自传入结构体的非零字段值（其次为 default 标签），必填由 required:"true"
+// 或 validate:"required" 标签声明，说明取自 desc 标签。schema 只读取标签，
+// 不改变 Unmarshal 行为，也不注入默认值。
+//
+// # 只读视图与访问审计
+//
+// NewView 返回配置的只读视图（String/Int/Duration/Get/Unmarshal 等），可交给不应修改配置的模块；
+// 每次读取基于最新快照，Reload 后自动生效。
+//
+// WithAccessAudit 对标记的敏感键记录访问审计（读取的键、调用方函数与源码位置），
+// 用于合规和排查"某配置被谁读取"：
+//
+//	cfg, err := xconf.New("config.yaml",
+//	    xconf.WithAccessAudit(func(rec xconf.AccessRecord) {
+//	        auditLog.Info("config access", "key", rec.Key, "caller", rec.Caller)
+//	    }, "db.password", "secrets"))
+//
+// 审计覆盖 View 的读取方法与 Unmarshal，读取敏感键的父路径或子键同样记录；
+// 未命中敏感键的读取只做前缀比较，不采集调用栈。Client() 直接访问 koanf 实例，不经过审计。
+//
 // # 配置监视
 //
 // 支持文件变更监视和自动重载（基于 fsnotify）。
@@ -41,6 +41,24 @@ var (
 	// ErrNilOption 表示传入了 nil 的配置选项函数。
 	ErrNilOption = errors.New("xconf: nil option")
 
+	// ErrUndefinedVariable 表示插值引用的配置键和环境变量都不存在，且未提供默认值。
+	ErrUndefinedVariable = errors.New("xconf: undefined interpolation variable")
+
+	// ErrInterpolationCycle 表示插值引用存在循环（如 a 引用 b、b 又引用 a）。
+	ErrInterpolationCycle = errors.New("xconf: interpolation cycle detected")
+
 	// ErrNilWatchOption 表示传入了 nil 的监视器配置选项函数。
 	ErrNilWatchOption = errors.New("xconf: nil watch option")
+
+	// ErrInvalidSchemaTarget 表示 ExportSchema 的参数不是结构体或结构体指针。
+	ErrInvalidSchemaTarget = errors.New("xconf: schema target must be a struct or pointer to struct")
+
+	// ErrMissingRequired 表示配置缺少 schema 中声明的必填键。
+	ErrMissingRequired = errors.New("xconf: missing required config keys")
+
+	// ErrNilConfig 表示传入的 Config 为 nil。
+	ErrNilConfig = errors.New("xconf: nil config")
+
+	// ErrNilAccessAudit 表示 WithAccessAudit 传入了 nil 的审计函数。
+	ErrNilAccessAudit = errors.New("xconf: nil access audit func")
 )
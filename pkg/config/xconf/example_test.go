@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/omeyang/xkit/pkg/config/xconf"
 )
@@ -251,3 +252,29 @@ func ExampleNewFromBytes_json() {
 	// api.endpoint: https://api.example.com
 	// api.timeout: 30
 }
+
+// ExampleExportSchema 演示从配置结构体导出配置文档。
+func ExampleExportSchema() {
+	type Server struct {
+		Host    string        `koanf:"host" required:"true" desc:"监听地址"`
+		Port    int           `koanf:"port"`
+		Timeout time.Duration `koanf:"timeout"`
+	}
+	type Config struct {
+		Server Server `koanf:"server"`
+	}
+
+	s, err := xconf.ExportSchema(Config{Server: Server{Port: 8080, Timeout: 5 * time.Second}})
+	if err != nil {
+		fmt.Printf("failed to export schema: %v\n", err)
+		return
+	}
+	fmt.Print(s.Markdown())
+
+	// Output:
+	// | 配置项 | 类型 | 默认值 | 必填 | 说明 |
+	// | --- | --- | --- | --- | --- |
+	// | `server.host` | string |  | 是 | 监听地址 |
+	// | `server.port` | integer | `8080` |  |  |
+	// | `server.timeout` | duration | `5s` |  |  |
+}
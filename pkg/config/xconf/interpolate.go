@@ -0,0 +1,159 @@
+package xconf
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// 变量插值语法。
+const (
+	refOpen       = "${"
+	refClose      = "}"
+	refDefaultSep = ":-"
+)
+
+// interpolator 解析配置值中的 ${...} 引用。
+//
+// 引用解析顺序：先查找配置键路径（如 ${app.name}），不存在时再查找环境变量（如 ${HOME}）。
+// 被引用的配置值本身也可以包含引用，按需递归解析并缓存结果。
+type interpolator struct {
+	k        *koanf.Koanf
+	resolved map[string]any  // 已解析的键值（按扁平键路径）
+	visiting map[string]bool // 当前解析链上的键，用于循环检测
+	stack    []string        // 当前解析链，用于循环错误信息
+}
+
+// interpolate 解析 k 中所有字符串值的 ${...} 引用并原地替换。
+//
+// 先解析全部键、再统一写回，写回过程中不会读到部分替换的值。
+// 任一引用解析失败时 k 保持不变。
+func interpolate(k *koanf.Koanf) error {
+	ip := &interpolator{
+		k:        k,
+		resolved: make(map[string]any),
+		visiting: make(map[string]bool),
+	}
+
+	keys := k.Keys()
+	for _, key := range keys {
+		if _, err := ip.resolveKey(key); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if err := k.Set(key, ip.resolved[key]); err != nil {
+			return fmt.Errorf("%w: set %q: %w", ErrParseFailed, key, err)
+		}
+	}
+	return nil
+}
+
+// resolveKey 返回键解析后的值，检测循环引用。
+func (ip *interpolator) resolveKey(key string) (any, error) {
+	if v, ok := ip.resolved[key]; ok {
+		return v, nil
+	}
+	if ip.visiting[key] {
+		chain := append(slices.Clone(ip.stack[slices.Index(ip.stack, key):]), key)
+		return nil, fmt.Errorf("%w: %s", ErrInterpolationCycle, strings.Join(chain, " -> "))
+	}
+
+	ip.visiting[key] = true
+	ip.stack = append(ip.stack, key)
+	v, err := ip.resolveValue(key, ip.k.Get(key))
+	ip.stack = ip.stack[:len(ip.stack)-1]
+	delete(ip.visiting, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ip.resolved[key] = v
+	return v, nil
+}
+
+// resolveValue 解析单个值，字符串和数组元素中的引用会被替换，其他类型原样返回。
+func (ip *interpolator) resolveValue(key string, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return ip.resolveString(key, val)
+	case []any:
+		out := make([]any, len(val))
+		for i, elem := range val {
+			r, err := ip.resolveValue(key, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// resolveString 替换字符串中的引用。
+//
+// 整个字符串恰好是一个引用（如 "${server.port}"）时保留被引用值的原始类型；
+// 嵌入在其他文本中时按 fmt.Sprint 格式化。
+// 未闭合的 "${" 按字面量保留，"$${" 转义为字面量 "${"。
+func (ip *interpolator) resolveString(key, s string) (any, error) {
+	if !strings.Contains(s, refOpen) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	rest := s
+	for {
+		i := strings.Index(rest, refOpen)
+		if i < 0 {
+			b.WriteString(rest)
+			break
+		}
+		// "$${" 转义
+		if i > 0 && rest[i-1] == '$' {
+			b.WriteString(rest[:i-1])
+			b.WriteString(refOpen)
+			rest = rest[i+len(refOpen):]
+			continue
+		}
+		end := strings.Index(rest[i+len(refOpen):], refClose)
+		if end < 0 {
+			b.WriteString(rest)
+			break
+		}
+		expr := rest[i+len(refOpen) : i+len(refOpen)+end]
+		v, err := ip.lookup(key, expr)
+		if err != nil {
+			return nil, err
+		}
+		// 整个字符串就是一个引用：保留原始类型
+		if i == 0 && len(expr)+len(refOpen)+len(refClose) == len(s) {
+			return v, nil
+		}
+		b.WriteString(rest[:i])
+		b.WriteString(fmt.Sprint(v))
+		rest = rest[i+len(refOpen)+end+len(refClose):]
+	}
+	return b.String(), nil
+}
+
+// lookup 解析引用表达式 name 或 name:-default。
+func (ip *interpolator) lookup(key, expr string) (any, error) {
+	name, def, hasDef := strings.Cut(expr, refDefaultSep)
+	if name != "" {
+		if ip.k.Exists(name) {
+			return ip.resolveKey(name)
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v, nil
+		}
+	}
+	if hasDef {
+		return def, nil
+	}
+	return nil, fmt.Errorf("%w: ${%s} referenced by %q", ErrUndefinedVariable, expr, key)
+}
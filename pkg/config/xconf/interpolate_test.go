@@ -0,0 +1,126 @@
+package xconf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpolation(t *testing.T) {
+	t.Setenv("XCONF_TEST_HOME", "/home/xconf")
+
+	data := []byte(`
+base:
+  dir: ${XCONF_TEST_HOME}/app
+  port: 8080
+log:
+  dir: ${base.dir}/logs
+  file: ${log.dir}/app.log
+server:
+  port: ${base.port}
+  addr: "0.0.0.0:${base.port}"
+  hosts:
+    - ${base.dir}
+    - static
+misc:
+  fallback: ${XCONF_TEST_UNSET:-/tmp}
+  empty_default: "${XCONF_TEST_UNSET:-}"
+  escaped: "$${NOT_A_REF}"
+  unclosed: "${oops"
+`)
+	cfg, err := NewFromBytes(data, FormatYAML, WithInterpolation())
+	require.NoError(t, err)
+	k := cfg.Client()
+
+	assert.Equal(t, "/home/xconf/app", k.String("base.dir"))
+	assert.Equal(t, "/home/xconf/app/logs", k.String("log.dir"))
+	assert.Equal(t, "/home/xconf/app/logs/app.log", k.String("log.file"))
+	assert.Equal(t, 8080, k.Int("server.port"))
+	assert.Equal(t, "0.0.0.0:8080", k.String("server.addr"))
+	assert.Equal(t, []string{"/home/xconf/app", "static"}, k.Strings("server.hosts"))
+	assert.Equal(t, "/tmp", k.String("misc.fallback"))
+	assert.Empty(t, k.String("misc.empty_default"))
+	assert.Equal(t, "${NOT_A_REF}", k.String("misc.escaped"))
+	assert.Equal(t, "${oops", k.String("misc.unclosed"))
+
+	var s struct {
+		Port int `koanf:"port"`
+	}
+	require.NoError(t, cfg.Unmarshal("server", &s))
+	assert.Equal(t, 8080, s.Port)
+}
+
+func TestInterpolation_ConfigKeyTakesPrecedence(t *testing.T) {
+	t.Setenv("name", "from-env")
+
+	cfg, err := NewFromBytes([]byte(`{"name": "from-config", "greeting": "hi ${name}"}`), FormatJSON, WithInterpolation())
+	require.NoError(t, err)
+	assert.Equal(t, "hi from-config", cfg.Client().String("greeting"))
+}
+
+func TestInterpolation_Disabled(t *testing.T) {
+	cfg, err := NewFromBytes([]byte(`dir: ${HOME}/logs`), FormatYAML)
+	require.NoError(t, err)
+	assert.Equal(t, "${HOME}/logs", cfg.Client().String("dir"))
+}
+
+func TestInterpolation_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "undefined variable",
+			data:    "dir: ${XCONF_TEST_UNDEFINED}/logs",
+			wantErr: ErrUndefinedVariable,
+			wantMsg: `"dir"`,
+		},
+		{
+			name:    "empty reference",
+			data:    "dir: ${}",
+			wantErr: ErrUndefinedVariable,
+		},
+		{
+			name:    "self reference",
+			data:    "a: ${a}",
+			wantErr: ErrInterpolationCycle,
+			wantMsg: "a -> a",
+		},
+		{
+			name:    "indirect cycle",
+			data:    "a: x${b}\nb: ${c}\nc: ${a}",
+			wantErr: ErrInterpolationCycle,
+			wantMsg: "a -> b -> c -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFromBytes([]byte(tt.data), FormatYAML, WithInterpolation())
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Contains(t, err.Error(), tt.wantMsg)
+		})
+	}
+}
+
+func TestInterpolation_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("base: v1\nname: ${base}-svc\n"), 0o600))
+
+	cfg, err := New(path, WithInterpolation())
+	require.NoError(t, err)
+	assert.Equal(t, "v1-svc", cfg.Client().String("name"))
+
+	require.NoError(t, os.WriteFile(path, []byte("base: v2\nname: ${base}-svc\n"), 0o600))
+	require.NoError(t, cfg.Reload())
+	assert.Equal(t, "v2-svc", cfg.Client().String("name"))
+
+	// 插值失败时保留旧配置
+	require.NoError(t, os.WriteFile(path, []byte("name: ${missing}\n"), 0o600))
+	require.ErrorIs(t, cfg.Reload(), ErrUndefinedVariable)
+	assert.Equal(t, "v2-svc", cfg.Client().String("name"))
+}
@@ -61,13 +61,9 @@ func New(path string, opts ...Option) (Config, error) {
 		return nil, fmt.Errorf("%w: %w", ErrLoadFailed, err)
 	}
 
-	k := koanf.New(o.delim)
-
-	// 空数据时创建空配置，与 NewFromBytes 行为一致
-	if len(data) > 0 {
-		if err := loadData(k, data, format); err != nil {
-			return nil, err
-		}
+	k, err := parseConfig(data, format, o)
+	if err != nil {
+		return nil, err
 	}
 
 	cfg := &koanfConfig{
@@ -97,13 +93,9 @@ func NewFromBytes(data []byte, format Format, opts ...Option) (Config, error) {
 		return nil, err
 	}
 
-	k := koanf.New(o.delim)
-
-	// 空数据时创建空配置，与 New 行为一致
-	if len(data) > 0 {
-		if err := loadData(k, data, format); err != nil {
-			return nil, err
-		}
+	k, err := parseConfig(data, format, o)
+	if err != nil {
+		return nil, err
 	}
 
 	cfg := &koanfConfig{
@@ -134,6 +126,7 @@ func (c *koanfConfig) Unmarshal(path string, target any) error {
 		return fmt.Errorf("%w: target must be a non-nil pointer, got %T", ErrUnmarshalFailed, target)
 	}
 
+	c.auditAccess(path)
 	k := c.k.Load()
 
 	if err := k.UnmarshalWithConf(path, target, koanf.UnmarshalConf{
@@ -162,13 +155,9 @@ func (c *koanfConfig) Reload() error {
 		return fmt.Errorf("%w: %w", ErrLoadFailed, err)
 	}
 
-	newK := koanf.New(c.opts.delim)
-
-	// 空数据时创建空配置，与 New/NewFromBytes 行为一致
-	if len(data) > 0 {
-		if err := loadData(newK, data, c.format); err != nil {
-			return err
-		}
+	newK, err := parseConfig(data, c.format, c.opts)
+	if err != nil {
+		return err
 	}
 
 	c.k.Store(newK)
@@ -245,6 +234,25 @@ func isValidFormat(format Format) bool {
 	}
 }
 
+// parseConfig 解析配置数据为新的 koanf 实例，New/NewFromBytes/Reload 共用。
+//
+// 空数据时创建空配置，三者行为一致。启用插值时在解析后统一替换引用。
+func parseConfig(data []byte, format Format, o *options) (*koanf.Koanf, error) {
+	k := koanf.New(o.delim)
+	if len(data) == 0 {
+		return k, nil
+	}
+	if err := loadData(k, data, format); err != nil {
+		return nil, err
+	}
+	if o.interpolate {
+		if err := interpolate(k); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
 // loadData 加载数据到 koanf 实例。
 //
 // 设计决策: default 分支使用 panic 而非返回错误。
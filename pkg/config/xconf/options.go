@@ -7,6 +7,18 @@ type options struct {
 
 	// tag 结构体标签名，用于 Unmarshal，默认为 "koanf"。
 	tag string
+
+	// interpolate 加载时是否解析 ${...} 变量引用，默认关闭。
+	interpolate bool
+
+	// accessAudit 敏感键访问审计函数，nil 表示不审计。
+	accessAudit AccessAuditFunc
+
+	// auditKeys 需要审计访问的敏感键。
+	auditKeys []string
+
+	// accessAuditSet 标记是否调用过 WithAccessAudit，用于校验 nil 审计函数。
+	accessAuditSet bool
 }
 
 // Option 定义配置选项函数类型。
@@ -28,6 +40,9 @@ func (o *options) validate() error {
 	if o.tag == "" {
 		return ErrInvalidTag
 	}
+	if o.accessAuditSet && o.accessAudit == nil {
+		return ErrNilAccessAudit
+	}
 	return nil
 }
 
@@ -46,3 +61,18 @@ func WithTag(tag string) Option {
 		o.tag = tag
 	}
 }
+
+// WithInterpolation 启用变量插值。
+// 加载（含 Reload）时解析字符串值中的 ${...} 引用并替换：
+//   - ${key.path}：引用其他配置值，被引用值中的引用会递归解析
+//   - ${VAR}：配置键不存在时回退到环境变量
+//   - ${name:-default}：配置键和环境变量都不存在时使用默认值
+//   - $${：转义为字面量 ${
+//
+// 整个值恰好是一个引用时保留被引用值的类型（如 port: ${base.port} 仍为数字）。
+// 引用未定义且无默认值时返回 ErrUndefinedVariable，循环引用返回 ErrInterpolationCycle。
+func WithInterpolation() Option {
+	return func(o *options) {
+		o.interpolate = true
+	}
+}
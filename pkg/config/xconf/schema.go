@@ -0,0 +1,417 @@
+package xconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 配置 schema 支持的字段类型。
+const (
+	SchemaTypeString   = "string"
+	SchemaTypeInteger  = "integer"
+	SchemaTypeNumber   = "number"
+	SchemaTypeBoolean  = "boolean"
+	SchemaTypeDuration = "duration"
+	SchemaTypeArray    = "array"
+	SchemaTypeObject   = "object"
+	SchemaTypeAny      = "any"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// SchemaField 描述一个配置项。
+type SchemaField struct {
+	// Key 完整配置键，按 WithDelim 分隔符拼接（如 "server.port"）。
+	Key string `json:"key"`
+
+	// Type 字段类型，取值为 SchemaType* 常量。
+	Type string `json:"type"`
+
+	// ItemType 数组元素类型，仅 Type 为 array 时有效。
+	ItemType string `json:"item_type,omitempty"`
+
+	// Default 默认值：优先取传入结构体中该字段的非零值，其次取 default 标签。
+	// default 标签按字段类型转换（integer 为 int64/uint64，number 为 float64，boolean 为 bool），
+	// 无法转换时保留原字符串。time.Duration 以 String() 形式表示（如 "5s"）。无默认值时为 nil。
+	Default any `json:"default,omitempty"`
+
+	// Required 是否必填，由 required:"true" 标签或 validate 标签中的 required 决定。
+	Required bool `json:"required"`
+
+	// Description 字段说明，取自 desc 标签。
+	Description string `json:"description,omitempty"`
+}
+
+// Schema 配置结构体导出的 schema。
+type Schema struct {
+	// Fields 全部叶子配置项，按结构体字段声明顺序排列。
+	// 嵌套结构体展开为多个带路径前缀的配置项，不单独列出。
+	Fields []SchemaField
+
+	delim string
+}
+
+// ExportSchema 从配置结构体导出 schema，列出所有配置键、类型、默认值与是否必填。
+//
+// cfg 为结构体或结构体指针，通常传入填充了默认值的配置实例，
+// 字段中的非零值即作为默认值导出。opts 中 WithTag/WithDelim 决定键名与分隔符，
+// 应与加载配置时保持一致。支持的标签：
+//   - koanf（或 WithTag 指定的标签）：键名，"-" 表示忽略；",squash" 将嵌入结构体展开到父级
+//   - default：无法通过结构体值表达的默认值（如零值本身就是合法配置时的说明）
+//   - required:"true" 或 validate:"required"：必填
+//   - desc：字段说明
+//
+// 设计决策: schema 只用于生成文档和校验键是否存在，不做默认值注入。
+// 与包定位一致（见包文档"设计理念"），默认值由业务在 Unmarshal 前预填结构体实现，
+// ExportSchema 读取同一个结构体即可保证文档与代码一致。
+//
+// 结构体数组只导出为 array（ItemType 为 object），不展开元素字段。
+func ExportSchema(cfg any, opts ...Option) (*Schema, error) {
+	o, err := applyOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v = reflect.Zero(v.Type().Elem())
+			continue
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: got %T", ErrInvalidSchemaTarget, cfg)
+	}
+
+	s := &Schema{delim: o.delim}
+	w := schemaWalker{tag: o.tag, delim: o.delim, visiting: make(map[reflect.Type]bool)}
+	w.walk(v, "", &s.Fields)
+	return s, nil
+}
+
+// Validate 检查 cfg 中是否存在全部必填配置键。
+// 缺失时返回 ErrMissingRequired，错误信息列出全部缺失的键。
+func (s *Schema) Validate(cfg Config) error {
+	if cfg == nil {
+		return fmt.Errorf("%w: nil config", ErrMissingRequired)
+	}
+	k := cfg.Client()
+	var missing []string
+	for _, f := range s.Fields {
+		if f.Required && !k.Exists(f.Key) {
+			missing = append(missing, f.Key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// JSONSchema 导出 JSON Schema（draft 2020-12），可交给外部校验工具或编辑器使用。
+//
+// duration 类型导出为 {"type": "string", "format": "duration"}，any 类型不限制类型。
+// 含必填字段的嵌套 object 同样出现在父级 required 中，整段配置缺失时也能校验出来。
+func (s *Schema) JSONSchema() ([]byte, error) {
+	root := newJSONObject()
+	for _, f := range s.Fields {
+		parts := strings.Split(f.Key, s.delim)
+		obj := root
+		for _, p := range parts[:len(parts)-1] {
+			if f.Required {
+				obj.require(p)
+			}
+			obj = obj.child(p)
+		}
+		name := parts[len(parts)-1]
+		obj.props[name] = jsonSchemaProperty(f)
+		if f.Required {
+			obj.require(name)
+		}
+	}
+	doc := root.build()
+	doc["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// Markdown 导出 Markdown 表格形式的配置文档。
+func (s *Schema) Markdown() string {
+	var b strings.Builder
+	b.WriteString("| 配置项 | 类型 | 默认值 | 必填 | 说明 |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, f := range s.Fields {
+		typ := f.Type
+		if f.ItemType != "" {
+			typ += "<" + f.ItemType + ">"
+		}
+		def := ""
+		if f.Default != nil {
+			def = "`" + fmt.Sprint(f.Default) + "`"
+		}
+		required := ""
+		if f.Required {
+			required = "是"
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s | %s |\n",
+			f.Key, typ, def, required, escapeMarkdownCell(f.Description))
+	}
+	return b.String()
+}
+
+// =============================================================================
+// 内部实现
+// =============================================================================
+
+// schemaWalker 递归遍历配置结构体。
+type schemaWalker struct {
+	tag      string
+	delim    string
+	visiting map[reflect.Type]bool // 防止自引用类型（如链表节点）无限递归
+}
+
+// walk 遍历结构体字段，叶子字段追加到 out。
+func (w *schemaWalker) walk(v reflect.Value, prefix string, out *[]SchemaField) {
+	t := v.Type()
+	if w.visiting[t] {
+		return
+	}
+	w.visiting[t] = true
+	defer delete(w.visiting, t)
+
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, squash, skip := w.fieldName(sf)
+		if skip {
+			continue
+		}
+
+		fv := v.Field(i)
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+			if fv.IsNil() {
+				fv = reflect.Zero(ft)
+			} else {
+				fv = fv.Elem()
+			}
+		}
+
+		if ft.Kind() == reflect.Struct && !isTextType(ft) {
+			if squash {
+				w.walk(fv, prefix, out)
+			} else {
+				w.walk(fv, prefix+name+w.delim, out)
+			}
+			continue
+		}
+
+		field := SchemaField{
+			Key:         prefix + name,
+			Type:        schemaType(ft),
+			Required:    isRequired(sf.Tag),
+			Description: sf.Tag.Get("desc"),
+		}
+		if field.Type == SchemaTypeArray {
+			field.ItemType = schemaType(ft.Elem())
+		}
+		field.Default = defaultValue(fv, sf.Tag, field.Type)
+		*out = append(*out, field)
+	}
+}
+
+// fieldName 解析字段的配置键名。
+// 与 mapstructure 行为一致：标签缺失或名称为空时使用字段名。
+func (w *schemaWalker) fieldName(sf reflect.StructField) (name string, squash, skip bool) {
+	tag := sf.Tag.Get(w.tag)
+	if tag == "-" {
+		return "", false, true
+	}
+	name, rest, _ := strings.Cut(tag, ",")
+	for opt := range strings.SplitSeq(rest, ",") {
+		if opt == "squash" {
+			squash = true
+		}
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	return name, squash, false
+}
+
+// isTextType 判断结构体类型是否以文本形式表示（如 time.Time），此类类型不展开字段。
+func isTextType(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(reflect.TypeFor[interface{ UnmarshalText([]byte) error }]())
+}
+
+// schemaType 返回类型对应的 schema 类型。
+func schemaType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return SchemaTypeDuration
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return SchemaTypeBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return SchemaTypeInteger
+	case reflect.Float32, reflect.Float64:
+		return SchemaTypeNumber
+	case reflect.String:
+		return SchemaTypeString
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return SchemaTypeString
+		}
+		return SchemaTypeArray
+	case reflect.Map:
+		return SchemaTypeObject
+	case reflect.Struct:
+		if isTextType(t) {
+			return SchemaTypeString
+		}
+		return SchemaTypeObject
+	default:
+		return SchemaTypeAny
+	}
+}
+
+// isRequired 判断字段是否必填。
+func isRequired(tag reflect.StructTag) bool {
+	if tag.Get("required") == "true" {
+		return true
+	}
+	for rule := range strings.SplitSeq(tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultValue 返回字段默认值：结构体中的非零值优先，其次为按 typ 转换后的 default 标签。
+func defaultValue(v reflect.Value, tag reflect.StructTag, typ string) any {
+	if v.IsValid() && !v.IsZero() {
+		if d, ok := v.Interface().(time.Duration); ok {
+			return d.String()
+		}
+		return v.Interface()
+	}
+	if def, ok := tag.Lookup("default"); ok {
+		return parseDefault(def, typ)
+	}
+	return nil
+}
+
+// parseDefault 将 default 标签转换为 typ 对应的值，无法转换时返回原字符串。
+func parseDefault(def, typ string) any {
+	switch typ {
+	case SchemaTypeInteger:
+		if n, err := strconv.ParseInt(def, 10, 64); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(def, 10, 64); err == nil {
+			return n
+		}
+	case SchemaTypeNumber:
+		if f, err := strconv.ParseFloat(def, 64); err == nil {
+			return f
+		}
+	case SchemaTypeBoolean:
+		if b, err := strconv.ParseBool(def); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// escapeMarkdownCell 转义表格单元格中的竖线与换行。
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// jsonObject JSON Schema 中的 object 节点。
+// 属性使用 map 存储，encoding/json 按 key 排序输出，生成结果稳定。
+type jsonObject struct {
+	props    map[string]any
+	children map[string]*jsonObject
+	required []string
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{props: make(map[string]any), children: make(map[string]*jsonObject)}
+}
+
+// child 返回名为 name 的子 object，不存在时创建。
+func (o *jsonObject) child(name string) *jsonObject {
+	c, ok := o.children[name]
+	if !ok {
+		c = newJSONObject()
+		o.children[name] = c
+	}
+	return c
+}
+
+// require 将 name 加入 required，已存在时忽略。
+func (o *jsonObject) require(name string) {
+	if !slices.Contains(o.required, name) {
+		o.required = append(o.required, name)
+	}
+}
+
+// build 生成 JSON Schema 文档。
+// 设计决策: JSON Schema 只在父 object 存在时才校验其 properties 下的 required，
+// 因此含必填字段的子 object 由 JSONSchema 加入父级 required，整段配置缺失时同样报错。
+func (o *jsonObject) build() map[string]any {
+	props := make(map[string]any, len(o.props)+len(o.children))
+	for k, v := range o.props {
+		props[k] = v
+	}
+	for k, c := range o.children {
+		props[k] = c.build()
+	}
+	doc := map[string]any{"type": SchemaTypeObject, "properties": props}
+	if len(o.required) > 0 {
+		doc["required"] = o.required
+	}
+	return doc
+}
+
+// jsonSchemaProperty 将配置项转换为 JSON Schema 属性。
+func jsonSchemaProperty(f SchemaField) map[string]any {
+	p := jsonSchemaType(f.Type)
+	if f.Type == SchemaTypeArray {
+		p["items"] = jsonSchemaType(f.ItemType)
+	}
+	if f.Default != nil {
+		p["default"] = f.Default
+	}
+	if f.Description != "" {
+		p["description"] = f.Description
+	}
+	return p
+}
+
+// jsonSchemaType 返回 schema 类型对应的 JSON Schema 类型声明。
+func jsonSchemaType(typ string) map[string]any {
+	switch typ {
+	case SchemaTypeDuration:
+		return map[string]any{"type": SchemaTypeString, "format": "duration"}
+	case SchemaTypeAny:
+		return map[string]any{}
+	default:
+		return map[string]any{"type": typ}
+	}
+}
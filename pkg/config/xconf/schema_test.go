@@ -0,0 +1,203 @@
+package xconf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestConfig struct {
+	Server           schemaTestServer `koanf:"server"`
+	DB               *schemaTestDB    `koanf:"db"`
+	SchemaTestCommon `koanf:",squash"`
+	Tags             []string          `koanf:"tags" desc:"标签 | 多个"`
+	Labels           map[string]string `koanf:"labels"`
+	Extra            any               `koanf:"extra"`
+	Started          time.Time         `koanf:"started"`
+	Ignored          string            `koanf:"-"`
+	NoTag            int
+	internal         int
+	Next             *schemaTestConfig `koanf:"next"`
+}
+
+type schemaTestServer struct {
+	Host    string        `koanf:"host" required:"true" desc:"监听地址"`
+	Port    int           `koanf:"port" default:"8080"`
+	Timeout time.Duration `koanf:"timeout"`
+	Ratio   float64       `koanf:"ratio"`
+}
+
+type schemaTestDB struct {
+	DSN string `koanf:"dsn" validate:"omitempty,required"`
+}
+
+type SchemaTestCommon struct {
+	Debug bool `koanf:"debug"`
+}
+
+func TestExportSchema(t *testing.T) {
+	cfg := schemaTestConfig{Server: schemaTestServer{Timeout: 5 * time.Second, Port: 9090}}
+	s, err := ExportSchema(&cfg)
+	require.NoError(t, err)
+
+	byKey := make(map[string]SchemaField)
+	keys := make([]string, 0, len(s.Fields))
+	for _, f := range s.Fields {
+		byKey[f.Key] = f
+		keys = append(keys, f.Key)
+	}
+	assert.Equal(t, []string{
+		"server.host", "server.port", "server.timeout", "server.ratio",
+		"db.dsn", "debug", "tags", "labels", "extra", "started", "NoTag",
+	}, keys, "declaration order, squash flattened, '-' / unexported / self-reference skipped")
+
+	assert.Equal(t, SchemaField{Key: "server.host", Type: SchemaTypeString, Required: true, Description: "监听地址"}, byKey["server.host"])
+	assert.Equal(t, 9090, byKey["server.port"].Default, "struct value takes precedence over default tag")
+	assert.Equal(t, "5s", byKey["server.timeout"].Default)
+	assert.Equal(t, SchemaTypeDuration, byKey["server.timeout"].Type)
+	assert.Equal(t, SchemaTypeNumber, byKey["server.ratio"].Type)
+	assert.True(t, byKey["db.dsn"].Required, "nil pointer struct is expanded")
+	assert.Equal(t, SchemaTypeBoolean, byKey["debug"].Type)
+	assert.Equal(t, SchemaTypeArray, byKey["tags"].Type)
+	assert.Equal(t, SchemaTypeString, byKey["tags"].ItemType)
+	assert.Equal(t, SchemaTypeObject, byKey["labels"].Type)
+	assert.Equal(t, SchemaTypeAny, byKey["extra"].Type)
+	assert.Equal(t, SchemaTypeString, byKey["started"].Type, "text types are not expanded")
+	assert.Nil(t, byKey["NoTag"].Default)
+
+	// default 标签在零值时生效
+	s, err = ExportSchema(schemaTestServer{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8080), s.Fields[1].Default)
+}
+
+func TestParseDefault(t *testing.T) {
+	assert.Equal(t, int64(-1), parseDefault("-1", SchemaTypeInteger))
+	assert.Equal(t, uint64(18446744073709551615), parseDefault("18446744073709551615", SchemaTypeInteger))
+	assert.Equal(t, 0.5, parseDefault("0.5", SchemaTypeNumber))
+	assert.Equal(t, true, parseDefault("true", SchemaTypeBoolean))
+	assert.Equal(t, "5s", parseDefault("5s", SchemaTypeDuration))
+	assert.Equal(t, "auto", parseDefault("auto", SchemaTypeInteger), "unparsable tag is kept as string")
+}
+
+func TestExportSchema_Options(t *testing.T) {
+	type cfg struct {
+		Inner struct {
+			Name string `yaml:"name"`
+		} `yaml:"inner"`
+	}
+	s, err := ExportSchema((*cfg)(nil), WithTag("yaml"), WithDelim("/"))
+	require.NoError(t, err)
+	require.Len(t, s.Fields, 1)
+	assert.Equal(t, "inner/name", s.Fields[0].Key)
+
+	_, err = ExportSchema(cfg{}, WithTag(""))
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	_, err = ExportSchema(42)
+	assert.ErrorIs(t, err, ErrInvalidSchemaTarget)
+	_, err = ExportSchema(nil)
+	assert.ErrorIs(t, err, ErrInvalidSchemaTarget)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := ExportSchema(schemaTestConfig{})
+	require.NoError(t, err)
+
+	cfg, err := NewFromBytes([]byte("server:\n  port: 80\n"), FormatYAML)
+	require.NoError(t, err)
+	err = s.Validate(cfg)
+	require.ErrorIs(t, err, ErrMissingRequired)
+	assert.Contains(t, err.Error(), "server.host, db.dsn")
+
+	cfg, err = NewFromBytes([]byte("server:\n  host: 0.0.0.0\ndb:\n  dsn: x\n"), FormatYAML)
+	require.NoError(t, err)
+	assert.NoError(t, s.Validate(cfg))
+
+	assert.ErrorIs(t, s.Validate(nil), ErrMissingRequired)
+}
+
+func TestSchema_JSONSchema(t *testing.T) {
+	s, err := ExportSchema(schemaTestConfig{Server: schemaTestServer{Timeout: time.Second}})
+	require.NoError(t, err)
+	raw, err := s.JSONSchema()
+	require.NoError(t, err)
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(raw, &doc))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", doc["$schema"])
+	props := doc["properties"].(map[string]any)
+	server := props["server"].(map[string]any)
+	assert.Equal(t, "object", server["type"])
+	assert.Equal(t, []any{"host"}, server["required"])
+	assert.Equal(t, []any{"server", "db"}, doc["required"], "objects with required descendants are required by the parent")
+
+	serverProps := server["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "format": "duration", "default": "1s"}, serverProps["timeout"])
+	assert.Equal(t, map[string]any{"type": "integer", "default": 8080.0}, serverProps["port"])
+	assert.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "description": "标签 | 多个"}, props["tags"])
+	assert.Equal(t, map[string]any{}, props["extra"])
+
+	again, err := s.JSONSchema()
+	require.NoError(t, err)
+	assert.Equal(t, string(raw), string(again), "output is deterministic")
+}
+
+func TestSchema_JSONSchemaMissingSection(t *testing.T) {
+	type cfg struct {
+		Server schemaTestServer `koanf:"server"`
+		Log    struct {
+			Level string `koanf:"level"`
+		} `koanf:"log"`
+	}
+	s, err := ExportSchema(cfg{})
+	require.NoError(t, err)
+	raw, err := s.JSONSchema()
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(raw, &schema))
+
+	assert.Equal(t, []any{"server"}, schema["required"], "sections without required fields stay optional")
+	assert.Equal(t, []string{"server"}, missingRequired(schema, map[string]any{"log": map[string]any{}}),
+		"whole section missing is reported")
+	assert.Equal(t, []string{"server.host"}, missingRequired(schema, map[string]any{"server": map[string]any{"port": 80}}))
+	assert.Empty(t, missingRequired(schema, map[string]any{"server": map[string]any{"host": "0.0.0.0"}}))
+}
+
+// missingRequired 按 JSON Schema 的 required 语义检查 doc：只校验已存在 object 的 required 属性。
+func missingRequired(schema, doc map[string]any) []string {
+	var missing []string
+	var check func(prefix string, schema, doc map[string]any)
+	check = func(prefix string, schema, doc map[string]any) {
+		required, _ := schema["required"].([]any)
+		for _, name := range required {
+			if _, ok := doc[name.(string)]; !ok {
+				missing = append(missing, prefix+name.(string))
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name, p := range props {
+			sub, ok := doc[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			check(prefix+name+".", p.(map[string]any), sub)
+		}
+	}
+	check("", schema, doc)
+	return missing
+}
+
+func TestSchema_Markdown(t *testing.T) {
+	s, err := ExportSchema(schemaTestConfig{})
+	require.NoError(t, err)
+	md := s.Markdown()
+	lines := strings.Split(strings.TrimSpace(md), "\n")
+	require.Len(t, lines, 2+len(s.Fields))
+	assert.Equal(t, "| `server.host` | string |  | 是 | 监听地址 |", lines[2])
+	assert.Equal(t, "| `server.port` | integer | `8080` |  |  |", lines[3])
+	assert.Contains(t, md, "| `tags` | array<string> |  |  | 标签 \\| 多个 |")
+}
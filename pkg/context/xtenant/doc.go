@@ -147,6 +147,17 @@
 //
 //   - 默认行为：仅传播上游已有的追踪字段，不自动生成
 //
+// 租户限流选项：
+//
+//   - WithTenantLimiter(l) / WithGRPCTenantLimiter(l):
+//     以提取到的 TenantID 调用 TenantLimiter，被限流时返回 429/ResourceExhausted，
+//     并通过 Retry-After 头 / retry-after trailer 告知重试间隔。
+//     执行顺序为"提取 → 校验 → 注入 context → 限流"，校验失败的请求不消耗租户配额；
+//     TenantID 为空的请求不限流；限流器返回错误时 fail-open 并记录 warn 日志。
+//   - WithLimiterLogger(l) / WithGRPCLimiterLogger(l):
+//     指定 fail-open 告警使用的 *slog.Logger，默认 slog.Default()
+//     xlimit.NewTenantLimiter 提供基于 xlimit 的实现（xtenant 不直接依赖 xlimit，避免循环依赖）
+//
 // # 线程安全
 //
 // 线程安全语义按 API 类型分别定义：
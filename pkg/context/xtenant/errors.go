@@ -24,4 +24,7 @@ var (
 
 	// ErrEmptyTenantName 租户名称为空
 	ErrEmptyTenantName = errors.New("xtenant: empty tenant_name")
+
+	// ErrTenantRateLimited 租户请求被限流
+	ErrTenantRateLimited = errors.New("xtenant: tenant rate limited")
 )
@@ -2,6 +2,7 @@ package xtenant
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 
 	"github.com/omeyang/xkit/pkg/context/xctx"
@@ -131,6 +132,8 @@ type grpcInterceptorConfig struct {
 	requireTenant   bool
 	requireTenantID bool
 	ensureTrace     bool
+	limiter         TenantLimiter
+	limiterLogger   *slog.Logger
 }
 
 // WithGRPCRequireTenant 设置是否要求租户信息必须存在
@@ -175,6 +178,29 @@ func WithGRPCEnsureTrace() GRPCInterceptorOption {
 	}
 }
 
+// WithGRPCTenantLimiter 启用按租户限流
+//
+// 租户信息提取并校验通过后，以 TenantID 调用 limiter 执行限流，
+// 被限流时返回 ResourceExhausted 错误，并通过 retry-after trailer 告知重试间隔。
+// 校验先于限流：租户信息不合法的请求直接返回 InvalidArgument，不消耗租户配额；
+// TenantID 为空的请求不执行限流。
+// limiter 为 nil 时不启用限流。
+func WithGRPCTenantLimiter(limiter TenantLimiter) GRPCInterceptorOption {
+	return func(cfg *grpcInterceptorConfig) {
+		cfg.limiter = limiter
+	}
+}
+
+// WithGRPCLimiterLogger 设置限流器故障时 fail-open 告警使用的日志器
+//
+// 默认使用 slog.Default()。传入 slog.New(slog.DiscardHandler) 可关闭该告警。
+// logger 为 nil 时保持默认。
+func WithGRPCLimiterLogger(logger *slog.Logger) GRPCInterceptorOption {
+	return func(cfg *grpcInterceptorConfig) {
+		cfg.limiterLogger = logger
+	}
+}
+
 // GRPCUnaryServerInterceptorWithOptions 返回带选项的 gRPC 一元拦截器。
 func GRPCUnaryServerInterceptorWithOptions(opts ...GRPCInterceptorOption) grpc.UnaryServerInterceptor {
 	cfg := &grpcInterceptorConfig{}
@@ -442,9 +468,24 @@ func injectTenantToContext(ctx context.Context, cfg *grpcInterceptorConfig) (con
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	// 租户限流（在校验和注入之后，限流器可从 ctx 读取完整的租户和追踪信息）
+	if decision, denied := checkTenantLimit(ctx, cfg.limiter, cfg.limiterLogger, info.TenantID); denied {
+		return nil, grpcTenantLimitError(ctx, decision)
+	}
+
 	return ctx, nil
 }
 
+// grpcTenantLimitError 创建租户限流错误并设置 retry-after trailer
+func grpcTenantLimitError(ctx context.Context, decision LimitDecision) error {
+	if decision.RetryAfter > 0 {
+		// SetTrailer 失败仅表示 transport 不可用，不影响限流语义
+		//nolint:errcheck // trailer 设置失败时仍返回限流错误
+		grpc.SetTrailer(ctx, metadata.Pairs("retry-after", decision.retryAfterSeconds()))
+	}
+	return status.Error(codes.ResourceExhausted, ErrTenantRateLimited.Error())
+}
+
 // validateGRPCTenantInfo 验证租户信息
 func validateGRPCTenantInfo(info TenantInfo, cfg *grpcInterceptorConfig) error {
 	if cfg.requireTenant {
@@ -2,6 +2,7 @@ package xtenant
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -117,6 +118,8 @@ type middlewareConfig struct {
 	requireTenant   bool
 	requireTenantID bool
 	ensureTrace     bool
+	limiter         TenantLimiter
+	limiterLogger   *slog.Logger
 }
 
 // WithRequireTenant 设置是否要求租户信息必须存在
@@ -161,6 +164,29 @@ func WithEnsureTrace() MiddlewareOption {
 	}
 }
 
+// WithTenantLimiter 启用按租户限流
+//
+// 租户信息提取并校验通过后，以 TenantID 调用 limiter 执行限流，
+// 被限流时返回 429 并设置 Retry-After 头。
+// 校验先于限流：租户信息不合法的请求直接返回 400，不消耗租户配额；
+// TenantID 为空的请求不执行限流。
+// limiter 为 nil 时不启用限流。
+func WithTenantLimiter(limiter TenantLimiter) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.limiter = limiter
+	}
+}
+
+// WithLimiterLogger 设置限流器故障时 fail-open 告警使用的日志器
+//
+// 默认使用 slog.Default()。传入 slog.New(slog.DiscardHandler) 可关闭该告警。
+// logger 为 nil 时保持默认。
+func WithLimiterLogger(logger *slog.Logger) MiddlewareOption {
+	return func(cfg *middlewareConfig) {
+		cfg.limiterLogger = logger
+	}
+}
+
 // HTTPMiddlewareWithOptions 返回带选项的 HTTP 中间件。
 func HTTPMiddlewareWithOptions(opts ...MiddlewareOption) func(http.Handler) http.Handler {
 	cfg := &middlewareConfig{}
@@ -179,6 +205,13 @@ func HTTPMiddlewareWithOptions(opts ...MiddlewareOption) func(http.Handler) http
 				http.Error(w, err.Error(), code)
 				return
 			}
+			if decision, denied := checkTenantLimit(ctx, cfg.limiter, cfg.limiterLogger, TenantID(ctx)); denied {
+				if decision.RetryAfter > 0 {
+					w.Header().Set("Retry-After", decision.retryAfterSeconds())
+				}
+				http.Error(w, ErrTenantRateLimited.Error(), http.StatusTooManyRequests)
+				return
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
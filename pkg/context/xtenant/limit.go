@@ -0,0 +1,68 @@
+package xtenant
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// =============================================================================
+// 租户限流集成
+// =============================================================================
+
+// TenantLimiter 按租户执行限流决策。
+//
+// 中间件/拦截器在完成租户提取和校验后，以提取到的租户 ID 调用 AllowTenant，
+// 业务无需手动拼接限流 key。xlimit.NewTenantLimiter 提供基于 xlimit.Limiter 的实现。
+//
+// 设计决策: 以接口而非直接依赖 xlimit 实现集成。xlimit 已依赖 xtenant
+// （KeyFromContext、默认 Header 名称），反向导入会产生循环依赖；
+// 接口也允许接入其他限流实现。
+//
+// 错误语义：err 非 nil 表示限流器自身故障，中间件 fail-open 放行并记录 warn 日志
+// （日志器由 WithLimiterLogger/WithGRPCLimiterLogger 指定）；
+// 需要在后端故障时拒绝请求的实现应返回 Allowed=false 且 err 为 nil。
+type TenantLimiter interface {
+	AllowTenant(ctx context.Context, tenantID string) (LimitDecision, error)
+}
+
+// LimitDecision 租户限流决策
+type LimitDecision struct {
+	// Allowed 是否放行
+	Allowed bool
+
+	// RetryAfter 建议重试等待时间（仅在 Allowed=false 时有意义）
+	RetryAfter time.Duration
+}
+
+// retryAfterSeconds 返回 Retry-After 秒数，向上取整避免亚秒级等待被截断为 0
+func (d LimitDecision) retryAfterSeconds() string {
+	return strconv.FormatInt(int64(math.Ceil(d.RetryAfter.Seconds())), 10)
+}
+
+// checkTenantLimit 执行租户限流检查
+//
+// 未配置限流器或租户 ID 为空时直接放行：未携带租户的请求不属于任何租户配额，
+// 是否允许此类请求由 WithRequireTenant/WithRequireTenantID 决定。
+// 返回的 denied 为 true 表示请求被限流。logger 为 nil 时使用 slog.Default()。
+func checkTenantLimit(ctx context.Context, limiter TenantLimiter, logger *slog.Logger, tenantID string) (decision LimitDecision, denied bool) {
+	if limiter == nil || tenantID == "" {
+		return LimitDecision{Allowed: true}, false
+	}
+
+	decision, err := limiter.AllowTenant(ctx, tenantID)
+	if err != nil {
+		// fail-open：限流器故障不阻塞业务请求，记录告警便于发现后端异常
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.WarnContext(ctx, "xtenant: tenant limiter fail-open due to error",
+			slog.String("error", err.Error()),
+			slog.String("tenant_id", tenantID),
+		)
+		return LimitDecision{Allowed: true}, false
+	}
+	return decision, !decision.Allowed
+}
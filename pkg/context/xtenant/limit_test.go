@@ -0,0 +1,198 @@
+package xtenant_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/pkg/context/xtenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTenantLimiter 每个租户最多放行 limit 次
+type fakeTenantLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	counts map[string]int
+	calls  []string
+	err    error
+}
+
+func newFakeTenantLimiter(limit int) *fakeTenantLimiter {
+	return &fakeTenantLimiter{limit: limit, counts: make(map[string]int)}
+}
+
+func (l *fakeTenantLimiter) AllowTenant(ctx context.Context, tenantID string) (xtenant.LimitDecision, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, tenantID)
+	if l.err != nil {
+		return xtenant.LimitDecision{}, l.err
+	}
+	// 限流器可从 ctx 读取已注入的租户信息
+	if xtenant.TenantID(ctx) != tenantID {
+		return xtenant.LimitDecision{}, errors.New("tenant not injected before limiting")
+	}
+	l.counts[tenantID]++
+	if l.counts[tenantID] > l.limit {
+		return xtenant.LimitDecision{RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return xtenant.LimitDecision{Allowed: true}, nil
+}
+
+func (l *fakeTenantLimiter) callCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.calls)
+}
+
+// =============================================================================
+// HTTP 租户限流测试
+// =============================================================================
+
+func TestHTTPMiddlewareWithOptions_TenantLimiter(t *testing.T) {
+	okHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func(h http.Handler, tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		if tenantID != "" {
+			req.Header.Set(xtenant.HeaderTenantID, tenantID)
+		}
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("超出配额返回429", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(1)
+		wrapped := xtenant.HTTPMiddlewareWithOptions(xtenant.WithTenantLimiter(limiter))(okHandler)
+
+		assert.Equal(t, http.StatusOK, serve(wrapped, "t1").Code)
+
+		rr := serve(wrapped, "t1")
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("Retry-After"))
+		assert.Contains(t, rr.Body.String(), xtenant.ErrTenantRateLimited.Error())
+
+		assert.Equal(t, http.StatusOK, serve(wrapped, "t2").Code, "other tenants have own quota")
+	})
+
+	t.Run("校验失败不消耗配额", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(1)
+		wrapped := xtenant.HTTPMiddlewareWithOptions(
+			xtenant.WithRequireTenant(),
+			xtenant.WithTenantLimiter(limiter),
+		)(okHandler)
+
+		// 缺少 TenantName，校验失败
+		assert.Equal(t, http.StatusBadRequest, serve(wrapped, "t1").Code)
+		assert.Zero(t, limiter.callCount())
+	})
+
+	t.Run("无租户ID不限流", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(0)
+		wrapped := xtenant.HTTPMiddlewareWithOptions(xtenant.WithTenantLimiter(limiter))(okHandler)
+
+		assert.Equal(t, http.StatusOK, serve(wrapped, "").Code)
+		assert.Zero(t, limiter.callCount())
+	})
+
+	t.Run("限流器错误时放行", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(0)
+		limiter.err = errors.New("backend down")
+		var buf bytes.Buffer
+		wrapped := xtenant.HTTPMiddlewareWithOptions(
+			xtenant.WithTenantLimiter(limiter),
+			xtenant.WithLimiterLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		)(okHandler)
+
+		assert.Equal(t, http.StatusOK, serve(wrapped, "t1").Code)
+		assert.Equal(t, 1, limiter.callCount())
+		assert.Contains(t, buf.String(), "tenant limiter fail-open")
+		assert.Contains(t, buf.String(), "error=\"backend down\"")
+		assert.Contains(t, buf.String(), "tenant_id=t1")
+	})
+
+	t.Run("nil限流器不启用", func(t *testing.T) {
+		wrapped := xtenant.HTTPMiddlewareWithOptions(xtenant.WithTenantLimiter(nil))(okHandler)
+		assert.Equal(t, http.StatusOK, serve(wrapped, "t1").Code)
+	})
+}
+
+// =============================================================================
+// gRPC 租户限流测试
+// =============================================================================
+
+func TestGRPCInterceptor_TenantLimiter(t *testing.T) {
+	handler := func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	}
+	incoming := func(tenantID string) context.Context {
+		return metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(xtenant.MetaTenantID, tenantID))
+	}
+
+	t.Run("一元拦截器超出配额返回ResourceExhausted", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(1)
+		interceptor := xtenant.GRPCUnaryServerInterceptorWithOptions(xtenant.WithGRPCTenantLimiter(limiter))
+
+		_, err := interceptor(incoming("t1"), "req", &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+
+		_, err = interceptor(incoming("t1"), "req", &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), xtenant.ErrTenantRateLimited.Error())
+	})
+
+	t.Run("流式拦截器超出配额返回ResourceExhausted", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(0)
+		interceptor := xtenant.GRPCStreamServerInterceptorWithOptions(xtenant.WithGRPCTenantLimiter(limiter))
+
+		stream := &mockServerStream{ctx: incoming("t1")}
+		err := interceptor(nil, stream, &grpc.StreamServerInfo{}, func(any, grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+
+	t.Run("校验失败不消耗配额", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(1)
+		interceptor := xtenant.GRPCUnaryServerInterceptorWithOptions(
+			xtenant.WithGRPCRequireTenant(),
+			xtenant.WithGRPCTenantLimiter(limiter),
+		)
+
+		_, err := interceptor(incoming("t1"), "req", &grpc.UnaryServerInfo{}, handler)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Zero(t, limiter.callCount())
+	})
+
+	t.Run("限流器错误时放行", func(t *testing.T) {
+		limiter := newFakeTenantLimiter(0)
+		limiter.err = errors.New("backend down")
+		var buf bytes.Buffer
+		interceptor := xtenant.GRPCUnaryServerInterceptorWithOptions(
+			xtenant.WithGRPCTenantLimiter(limiter),
+			xtenant.WithGRPCLimiterLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		)
+
+		resp, err := interceptor(incoming("t1"), "req", &grpc.UnaryServerInfo{}, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+		assert.Contains(t, buf.String(), "tenant limiter fail-open")
+		assert.Contains(t, buf.String(), "tenant_id=t1")
+	})
+}
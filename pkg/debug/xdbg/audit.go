@@ -37,6 +37,9 @@ const (
 
 	// AuditEventCommandForbidden 命令被禁止。
 	AuditEventCommandForbidden AuditEvent = "COMMAND_FORBIDDEN"
+
+	// AuditEventLogSamplingRestore 临时日志采样调整到期或服务停止，恢复原配置。
+	AuditEventLogSamplingRestore AuditEvent = "LOG_SAMPLING_RESTORE"
 )
 
 // AuditRecord 审计记录。
@@ -0,0 +1,213 @@
+package xdbg
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultLogSampleDuration 临时调整日志采样的默认有效期。
+	defaultLogSampleDuration = 10 * time.Minute
+
+	// maxLogSampleDuration 临时调整日志采样的最长有效期。
+	maxLogSampleDuration = time.Hour
+)
+
+// logSampleSnapshot 调整前的日志采样配置。
+type logSampleSnapshot struct {
+	paused     bool
+	first      int
+	thereafter int
+}
+
+// logSampleCommand logsample 命令。
+//
+// 设计决策: 所有调整都是临时的，到期后自动恢复为首次调整前的配置，Server.Stop 时同样恢复。
+// 排查时临时关闭采样会放大日志量，若依赖人工恢复，遗忘的代价是持续的日志风暴；
+// 连续多次调整只刷新有效期，恢复目标始终是首次调整前的快照。
+type logSampleCommand struct {
+	server *Server
+
+	mu       sync.Mutex
+	original *logSampleSnapshot // 非 nil 表示存在未恢复的临时调整
+	deadline time.Time
+	timer    *time.Timer
+	gen      uint64 // 每次调整递增，防止已被替换的定时器回调误恢复
+}
+
+func newLogSampleCommand(s *Server) *logSampleCommand {
+	return &logSampleCommand{server: s}
+}
+
+func (c *logSampleCommand) Name() string {
+	return "logsample"
+}
+
+func (c *logSampleCommand) Help() string {
+	return "查看/临时调整日志采样 (logsample [off [时长] | rate <first> <thereafter> [时长] | restore])"
+}
+
+func (c *logSampleCommand) Execute(_ context.Context, args []string) (string, error) {
+	sampler := c.server.opts.LogSampler
+	if sampler == nil {
+		return "", fmt.Errorf("日志采样控制未配置")
+	}
+
+	if len(args) == 0 {
+		return c.show(sampler), nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "off":
+		d, err := parseLogSampleDuration(args[1:])
+		if err != nil {
+			return "", err
+		}
+		if err := c.apply(sampler, d, func() error {
+			sampler.SetPaused(true)
+			return nil
+		}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("日志采样已暂停，%s 后自动恢复", d), nil
+	case "rate":
+		if len(args) < 3 {
+			return "", fmt.Errorf("用法: logsample rate <first> <thereafter> [时长]")
+		}
+		first, err1 := strconv.Atoi(args[1])
+		thereafter, err2 := strconv.Atoi(args[2])
+		if err1 != nil || err2 != nil || first < 0 || thereafter < 0 {
+			return "", fmt.Errorf("无效的采样速率: %s %s", args[1], args[2])
+		}
+		d, err := parseLogSampleDuration(args[3:])
+		if err != nil {
+			return "", err
+		}
+		if err := c.apply(sampler, d, func() error {
+			return sampler.SetRate(first, thereafter)
+		}); err != nil {
+			return "", fmt.Errorf("设置采样速率失败: %w", err)
+		}
+		first, thereafter = sampler.Rate()
+		return fmt.Sprintf("日志采样速率已调整为 first=%d thereafter=%d，%s 后自动恢复", first, thereafter, d), nil
+	case "restore":
+		if !c.Restore("manual") {
+			return "没有需要恢复的临时调整", nil
+		}
+		return "日志采样已恢复", nil
+	default:
+		return "", fmt.Errorf("未知的子命令: %s", args[0])
+	}
+}
+
+// Restore 恢复首次调整前的日志采样配置，没有临时调整时返回 false。
+// reason 记录到审计日志（manual、expired、server-stop）。
+func (c *logSampleCommand) Restore(reason string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.restoreLocked(reason)
+}
+
+// apply 记录调整前的快照后执行 change，并刷新自动恢复定时器。
+func (c *logSampleCommand) apply(sampler LogSampler, d time.Duration, change func() error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fresh := c.original == nil
+	if fresh {
+		first, thereafter := sampler.Rate()
+		c.original = &logSampleSnapshot{paused: sampler.Paused(), first: first, thereafter: thereafter}
+	}
+	if err := change(); err != nil {
+		if fresh {
+			c.original = nil
+		}
+		return err
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.gen++
+	gen := c.gen
+	c.deadline = time.Now().Add(d)
+	c.timer = time.AfterFunc(d, func() { c.expire(gen) })
+	return nil
+}
+
+// expire 定时器到期回调，仅恢复由同一次调整启动的定时器。
+func (c *logSampleCommand) expire(gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	c.restoreLocked("expired")
+}
+
+// restoreLocked 恢复快照并记录审计，调用方必须持有 mu。
+func (c *logSampleCommand) restoreLocked(reason string) bool {
+	if c.original == nil {
+		return false
+	}
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.gen++
+
+	orig := c.original
+	c.original = nil
+	sampler := c.server.opts.LogSampler
+	err := sampler.SetRate(orig.first, orig.thereafter)
+	sampler.SetPaused(orig.paused)
+
+	c.server.audit(AuditEventLogSamplingRestore, nil, c.Name(), []string{reason}, 0, err)
+	return true
+}
+
+// show 输出当前采样状态及待恢复的临时调整。
+func (c *logSampleCommand) show(sampler LogSampler) string {
+	first, thereafter := sampler.Rate()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "日志采样: %s\n", samplingStateText(sampler.Paused()))
+	fmt.Fprintf(&sb, "  速率:     first=%d thereafter=%d\n", first, thereafter)
+	fmt.Fprintf(&sb, "  已抑制:   %d\n", sampler.Suppressed())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.original != nil {
+		fmt.Fprintf(&sb, "  临时调整: %s 后恢复为 %s first=%d thereafter=%d\n",
+			formatRemaining(time.Now(), c.deadline), samplingStateText(c.original.paused),
+			c.original.first, c.original.thereafter)
+	}
+	return sb.String()
+}
+
+// samplingStateText 返回采样状态的展示文本。
+func samplingStateText(paused bool) string {
+	if paused {
+		return "已暂停"
+	}
+	return "运行中"
+}
+
+// parseLogSampleDuration 解析可选的有效期参数，缺省为 defaultLogSampleDuration。
+func parseLogSampleDuration(args []string) (time.Duration, error) {
+	if len(args) == 0 {
+		return defaultLogSampleDuration, nil
+	}
+	d, err := time.ParseDuration(args[0])
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("无效的时长: %s", args[0])
+	}
+	if d > maxLogSampleDuration {
+		return 0, fmt.Errorf("时长不能超过 %s", maxLogSampleDuration)
+	}
+	return d, nil
+}
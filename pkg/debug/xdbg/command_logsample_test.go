@@ -0,0 +1,205 @@
+//go:build !windows
+
+package xdbg
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/pkg/observability/xlog"
+)
+
+// xlog.SamplingHandler 可直接作为 LogSampler 注入
+var _ LogSampler = (*xlog.SamplingHandler)(nil)
+
+// mockLogSampler 测试用的日志采样控制。
+type mockLogSampler struct {
+	mu         sync.Mutex
+	paused     bool
+	first      int
+	thereafter int
+}
+
+func (s *mockLogSampler) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+func (s *mockLogSampler) SetPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = paused
+}
+
+func (s *mockLogSampler) Rate() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.first, s.thereafter
+}
+
+func (s *mockLogSampler) SetRate(first, thereafter int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.first, s.thereafter = first, thereafter
+	return nil
+}
+
+func (s *mockLogSampler) Suppressed() uint64 { return 42 }
+
+func newLogSampleServer(t *testing.T, sampler LogSampler) (*Server, *logSampleCommand, *[]AuditEvent) {
+	t.Helper()
+	events := &[]AuditEvent{}
+	srv, err := New(
+		WithBackgroundMode(true),
+		WithAuditLogger(&testAuditLogger{logs: events}),
+		WithLogSampler(sampler),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, srv.registry.Get("logsample"))
+	return srv, srv.logSampleCmd, events
+}
+
+func TestLogSampleCommand_Show(t *testing.T) {
+	sampler := &mockLogSampler{first: 100}
+	_, cmd, _ := newLogSampleServer(t, sampler)
+
+	output, err := cmd.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "日志采样: 运行中")
+	assert.Contains(t, output, "first=100 thereafter=0")
+	assert.Contains(t, output, "已抑制:   42")
+	assert.NotContains(t, output, "临时调整")
+}
+
+func TestLogSampleCommand_OffAndRestore(t *testing.T) {
+	sampler := &mockLogSampler{first: 100, thereafter: 10}
+	_, cmd, events := newLogSampleServer(t, sampler)
+	ctx := context.Background()
+
+	output, err := cmd.Execute(ctx, []string{"off", "5m"})
+	require.NoError(t, err)
+	assert.Contains(t, output, "5m0s 后自动恢复")
+	assert.True(t, sampler.Paused())
+
+	// 再次调整只刷新有效期，恢复目标仍是首次调整前的配置
+	_, err = cmd.Execute(ctx, []string{"rate", "1000", "0"})
+	require.NoError(t, err)
+	output, err = cmd.Execute(ctx, nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "已暂停")
+	assert.Contains(t, output, "first=1000 thereafter=0")
+	assert.Contains(t, output, "后恢复为 运行中 first=100 thereafter=10")
+
+	output, err = cmd.Execute(ctx, []string{"restore"})
+	require.NoError(t, err)
+	assert.Equal(t, "日志采样已恢复", output)
+	assert.False(t, sampler.Paused())
+	first, thereafter := sampler.Rate()
+	assert.Equal(t, 100, first)
+	assert.Equal(t, 10, thereafter)
+	assert.Equal(t, []AuditEvent{AuditEventLogSamplingRestore}, *events)
+
+	output, err = cmd.Execute(ctx, []string{"restore"})
+	require.NoError(t, err)
+	assert.Equal(t, "没有需要恢复的临时调整", output)
+}
+
+func TestLogSampleCommand_Expire(t *testing.T) {
+	sampler := &mockLogSampler{first: 100}
+	_, cmd, _ := newLogSampleServer(t, sampler)
+
+	_, err := cmd.Execute(context.Background(), []string{"off", "20ms"})
+	require.NoError(t, err)
+	require.True(t, sampler.Paused())
+
+	assert.Eventually(t, func() bool { return !sampler.Paused() }, time.Second, 5*time.Millisecond)
+	assert.False(t, cmd.Restore("manual"), "expired adjustment already restored")
+}
+
+func TestLogSampleCommand_StaleTimerIgnored(t *testing.T) {
+	sampler := &mockLogSampler{first: 100}
+	_, cmd, _ := newLogSampleServer(t, sampler)
+
+	_, err := cmd.Execute(context.Background(), []string{"off", "1h"})
+	require.NoError(t, err)
+	cmd.mu.Lock()
+	stale := cmd.gen - 1
+	cmd.mu.Unlock()
+
+	cmd.expire(stale)
+	assert.True(t, sampler.Paused())
+	require.True(t, cmd.Restore("manual"))
+}
+
+func TestLogSampleCommand_StopRestores(t *testing.T) {
+	sampler := &mockLogSampler{first: 100}
+	srv, cmd, _ := newLogSampleServer(t, sampler)
+
+	_, err := cmd.Execute(context.Background(), []string{"off"})
+	require.NoError(t, err)
+	require.True(t, sampler.Paused())
+
+	require.NoError(t, srv.Stop())
+	assert.False(t, sampler.Paused())
+}
+
+func TestLogSampleCommand_InvalidArgs(t *testing.T) {
+	sampler := &mockLogSampler{first: 100}
+	_, cmd, _ := newLogSampleServer(t, sampler)
+	ctx := context.Background()
+
+	for _, args := range [][]string{
+		{"off", "abc"},
+		{"off", "-1m"},
+		{"off", "2h"},
+		{"rate", "1"},
+		{"rate", "x", "1"},
+		{"rate", "-1", "1"},
+		{"rate", "1", "1", "0s"},
+		{"unknown"},
+	} {
+		_, err := cmd.Execute(ctx, args)
+		assert.Error(t, err, "args %v", args)
+	}
+	assert.False(t, sampler.Paused())
+	assert.False(t, cmd.Restore("manual"), "failed commands leave no adjustment")
+}
+
+func TestLogSampleCommand_XlogSampler(t *testing.T) {
+	logger, cleanup, err := xlog.New().
+		SetOutput(io.Discard).
+		SetSampling(xlog.SamplingConfig{First: 5}).
+		Build()
+	require.NoError(t, err)
+	defer func() { _ = cleanup() }()
+	h, ok := xlog.SamplerOf(logger)
+	require.True(t, ok)
+
+	_, cmd, _ := newLogSampleServer(t, h)
+	_, err = cmd.Execute(context.Background(), []string{"rate", "50", "10", "1m"})
+	require.NoError(t, err)
+	first, thereafter := h.Rate()
+	assert.Equal(t, 50, first)
+	assert.Equal(t, 10, thereafter)
+
+	require.True(t, cmd.Restore("manual"))
+	first, thereafter = h.Rate()
+	assert.Equal(t, 5, first)
+	assert.Equal(t, 0, thereafter)
+}
+
+func TestLogSampleCommand_NotRegisteredWithoutSampler(t *testing.T) {
+	srv, err := New(WithBackgroundMode(true), WithAuditLogger(NewNoopAuditLogger()))
+	require.NoError(t, err)
+	assert.Nil(t, srv.registry.Get("logsample"))
+
+	_, err = newLogSampleCommand(srv).Execute(context.Background(), nil)
+	require.Error(t, err)
+}
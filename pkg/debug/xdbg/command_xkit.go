@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	// 设计决策: 依赖 xjson 而非 encoding/json。xjson.PrettyE 提供带错误返回的格式化，
 	// 且 xjson 是项目内纯工具包（无外部依赖），依赖范围可控。
@@ -31,6 +32,22 @@ func (s *Server) registerXkitCommands() {
 	if s.opts.ConfigProvider != nil {
 		s.registry.Register(newConfigCommand(s))
 	}
+
+	// 分布式锁命令
+	if s.opts.LockProvider != nil {
+		s.registry.Register(newLocksCommand(s))
+	}
+
+	// 信号量许可命令
+	if s.opts.SemaphoreProvider != nil {
+		s.registry.Register(newPermitsCommand(s))
+	}
+
+	// 日志采样命令，保存引用用于在 Stop 时恢复临时调整
+	if s.opts.LogSampler != nil {
+		s.logSampleCmd = newLogSampleCommand(s)
+		s.registry.Register(s.logSampleCmd)
+	}
 }
 
 // breakerCommand breaker 命令。
@@ -308,3 +325,121 @@ func (c *configCommand) Execute(_ context.Context, _ []string) (string, error) {
 
 	return s, nil
 }
+
+// locksCommand locks 命令。
+type locksCommand struct {
+	server *Server
+}
+
+func newLocksCommand(s *Server) *locksCommand {
+	return &locksCommand{server: s}
+}
+
+func (c *locksCommand) Name() string {
+	return "locks"
+}
+
+func (c *locksCommand) Help() string {
+	return "查看当前进程持有的分布式锁 (locks [key前缀])"
+}
+
+func (c *locksCommand) Execute(_ context.Context, args []string) (string, error) {
+	provider := c.server.opts.LockProvider
+	if provider == nil {
+		return "", fmt.Errorf("锁查询未配置")
+	}
+
+	var prefix string
+	if len(args) > 0 {
+		prefix = args[0]
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	count := 0
+	for _, l := range provider.HeldLocks() {
+		if !strings.HasPrefix(l.Key, prefix) {
+			continue
+		}
+		count++
+		fmt.Fprintf(&sb, "  %-30s [%s/%s] held=%s remaining=%s\n",
+			l.Key, l.Backend, l.Mode, formatElapsed(now, l.AcquiredAt), formatRemaining(now, l.ExpiresAt))
+	}
+	if count == 0 {
+		return "当前进程未持有锁", nil
+	}
+
+	return fmt.Sprintf("持有的锁 (%d):\n", count) + sb.String(), nil
+}
+
+// permitsCommand permits 命令。
+type permitsCommand struct {
+	server *Server
+}
+
+func newPermitsCommand(s *Server) *permitsCommand {
+	return &permitsCommand{server: s}
+}
+
+func (c *permitsCommand) Name() string {
+	return "permits"
+}
+
+func (c *permitsCommand) Help() string {
+	return "查看当前进程持有的信号量许可 (permits [resource])"
+}
+
+func (c *permitsCommand) Execute(_ context.Context, args []string) (string, error) {
+	provider := c.server.opts.SemaphoreProvider
+	if provider == nil {
+		return "", fmt.Errorf("信号量查询未配置")
+	}
+
+	var resource string
+	if len(args) > 0 {
+		resource = args[0]
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	count, slots := 0, 0
+	for _, p := range provider.HeldPermits() {
+		if resource != "" && p.Resource != resource {
+			continue
+		}
+		count++
+		slots += max(p.Count, 1)
+		tenant := p.TenantID
+		if tenant == "" {
+			tenant = "-"
+		}
+		fmt.Fprintf(&sb, "  %-20s [%s] id=%s tenant=%s count=%d held=%s remaining=%s\n",
+			p.Resource, p.Type, p.ID, tenant, max(p.Count, 1),
+			formatElapsed(now, p.AcquiredAt), formatRemaining(now, p.ExpiresAt))
+	}
+	if count == 0 {
+		return "当前进程未持有许可", nil
+	}
+
+	return fmt.Sprintf("持有的许可 (%d, 名额 %d):\n", count, slots) + sb.String(), nil
+}
+
+// formatElapsed 格式化自 since 起经过的时间（秒级精度）。
+func formatElapsed(now, since time.Time) string {
+	if since.IsZero() {
+		return "-"
+	}
+	return now.Sub(since).Round(time.Second).String()
+}
+
+// formatRemaining 格式化距 deadline 的剩余时间，零值表示由后端自动续期。
+func formatRemaining(now, deadline time.Time) string {
+	if deadline.IsZero() {
+		return "auto"
+	}
+	d := deadline.Sub(now)
+	if d <= 0 {
+		return "已过期"
+	}
+	return d.Round(time.Millisecond).String()
+}
@@ -6,6 +6,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -122,6 +123,20 @@ func (p *mockConfigProvider) Dump() map[string]any {
 	return p.config
 }
 
+// mockLockProvider 测试用的持有锁查询。
+type mockLockProvider []LockInfo
+
+func (p mockLockProvider) HeldLocks() []LockInfo {
+	return p
+}
+
+// mockSemaphoreProvider 测试用的持有许可查询。
+type mockSemaphoreProvider []PermitInfo
+
+func (p mockSemaphoreProvider) HeldPermits() []PermitInfo {
+	return p
+}
+
 func TestBreakerCommand_List(t *testing.T) {
 	registry := newMockBreakerRegistry()
 	registry.addBreaker(&BreakerInfo{
@@ -566,6 +581,8 @@ func TestXkitCommandsConditionalRegistration(t *testing.T) {
 	a.False(srv1.registry.Has("limit"), "limit should not be registered")
 	a.False(srv1.registry.Has("cache"), "cache should not be registered")
 	a.False(srv1.registry.Has("config"), "config should not be registered")
+	a.False(srv1.registry.Has("locks"), "locks should not be registered")
+	a.False(srv1.registry.Has("permits"), "permits should not be registered")
 
 	// 配置所有 xkit 组件
 	srv2, err := New(
@@ -575,6 +592,8 @@ func TestXkitCommandsConditionalRegistration(t *testing.T) {
 		WithLimiterRegistry(newMockLimiterRegistry()),
 		WithCacheRegistry(newMockCacheRegistry()),
 		WithConfigProvider(newMockConfigProvider()),
+		WithLockProvider(mockLockProvider(nil)),
+		WithSemaphoreProvider(mockSemaphoreProvider(nil)),
 	)
 	require.NoError(t, err, "New() with all xkit components")
 
@@ -583,6 +602,8 @@ func TestXkitCommandsConditionalRegistration(t *testing.T) {
 	a.True(srv2.registry.Has("limit"), "limit should be registered")
 	a.True(srv2.registry.Has("cache"), "cache should be registered")
 	a.True(srv2.registry.Has("config"), "config should be registered")
+	a.True(srv2.registry.Has("locks"), "locks should be registered")
+	a.True(srv2.registry.Has("permits"), "permits should be registered")
 }
 
 func TestBreakerCommand_EmptyList(t *testing.T) {
@@ -732,3 +753,77 @@ func TestCacheCommand_ZeroHitsAndMisses(t *testing.T) {
 	require.NoError(t, err)
 	assert.Contains(t, output, "0.0%")
 }
+
+func TestLocksCommand(t *testing.T) {
+	now := time.Now()
+	provider := mockLockProvider{
+		{Key: "lock:job:a", Backend: "redis", Mode: "mutex", AcquiredAt: now.Add(-time.Minute), ExpiresAt: now.Add(30 * time.Second)},
+		{Key: "lock:job:b", Backend: "redis", Mode: "write", AcquiredAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Second)},
+		{Key: "lock:other", Backend: "etcd", Mode: "mutex", AcquiredAt: now},
+	}
+	srv, err := New(
+		WithBackgroundMode(true),
+		WithAuditLogger(NewNoopAuditLogger()),
+		WithLockProvider(provider),
+	)
+	require.NoError(t, err)
+	cmd := srv.registry.Get("locks")
+	require.NotNil(t, cmd)
+
+	output, err := cmd.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "持有的锁 (3)")
+	assert.Contains(t, output, "[redis/mutex] held=1m0s")
+	assert.Contains(t, output, "已过期")
+	assert.Contains(t, output, "[etcd/mutex] held=0s remaining=auto")
+
+	output, err = cmd.Execute(context.Background(), []string{"lock:job:"})
+	require.NoError(t, err)
+	assert.Contains(t, output, "持有的锁 (2)")
+	assert.NotContains(t, output, "lock:other")
+
+	output, err = cmd.Execute(context.Background(), []string{"missing"})
+	require.NoError(t, err)
+	assert.Equal(t, "当前进程未持有锁", output)
+}
+
+func TestPermitsCommand(t *testing.T) {
+	now := time.Now()
+	provider := mockSemaphoreProvider{
+		{ID: "p1", Resource: "export", Type: "distributed", Count: 2, TenantID: "t1", AcquiredAt: now, ExpiresAt: now.Add(time.Minute)},
+		{ID: "p2", Resource: "import", Type: "local", AcquiredAt: now, ExpiresAt: now.Add(time.Minute)},
+	}
+	srv, err := New(
+		WithBackgroundMode(true),
+		WithAuditLogger(NewNoopAuditLogger()),
+		WithSemaphoreProvider(provider),
+	)
+	require.NoError(t, err)
+	cmd := srv.registry.Get("permits")
+	require.NotNil(t, cmd)
+
+	output, err := cmd.Execute(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Contains(t, output, "持有的许可 (2, 名额 3)")
+	assert.Contains(t, output, "id=p1 tenant=t1 count=2")
+	assert.Contains(t, output, "id=p2 tenant=- count=1")
+
+	output, err = cmd.Execute(context.Background(), []string{"import"})
+	require.NoError(t, err)
+	assert.Contains(t, output, "持有的许可 (1, 名额 1)")
+	assert.NotContains(t, output, "export")
+
+	output, err = cmd.Execute(context.Background(), []string{"missing"})
+	require.NoError(t, err)
+	assert.Equal(t, "当前进程未持有许可", output)
+}
+
+func TestLocksPermitsCommand_NoProvider(t *testing.T) {
+	srv, err := New(WithBackgroundMode(true), WithAuditLogger(NewNoopAuditLogger()))
+	require.NoError(t, err)
+
+	_, err = newLocksCommand(srv).Execute(context.Background(), nil)
+	require.Error(t, err)
+	_, err = newPermitsCommand(srv).Execute(context.Background(), nil)
+	require.Error(t, err)
+}
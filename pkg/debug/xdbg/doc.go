@@ -59,6 +59,31 @@
 //
 // 通过 Option 注入 xkit 组件，启用对应的调试命令。
 //
+// locks/permits 命令列出当前进程持有的分布式锁和信号量许可，用于排查
+// "任务为何阻塞"（等锁）或"配额为何满"。数据来自 xdlock Factory.Held 与
+// xsemaphore Semaphore.Held，通过 WithLockProvider/WithSemaphoreProvider 注入适配器：
+//
+//	type lockProvider struct{ f xdlock.Factory }
+//
+//	func (p lockProvider) HeldLocks() []xdbg.LockInfo {
+//	    held := p.f.Held()
+//	    out := make([]xdbg.LockInfo, 0, len(held))
+//	    for _, l := range held {
+//	        out = append(out, xdbg.LockInfo(l))
+//	    }
+//	    return out
+//	}
+//
+// logsample 命令查看和临时调整日志采样，用于排查时临时放开被采样丢弃的日志。
+// 通过 WithLogSampler 注入，xlog 启用采样时可直接使用 xlog.SamplerOf 返回的 handler：
+//
+//	if h, ok := xlog.SamplerOf(logger); ok {
+//	    opts = append(opts, xdbg.WithLogSampler(h))
+//	}
+//
+// 调整均为临时的（默认 10 分钟，最长 1 小时），到期、执行 logsample restore
+// 或 Server 停止时恢复为首次调整前的配置，并记录 LOG_SAMPLING_RESTORE 审计事件。
+//
 // # 客户端工具
 //
 // xdbgctl 是配套的客户端工具，支持单命令模式和交互模式。
@@ -1,5 +1,7 @@
 package xdbg
 
+import "time"
+
 // Leveler 日志级别控制器接口。
 // 此接口与 xlog.Leveler 兼容。
 type Leveler interface {
@@ -120,3 +122,78 @@ type ConfigProvider interface {
 	// 实现方应确保返回值不包含敏感信息（密码、密钥等）。
 	Dump() map[string]any
 }
+
+// LockInfo 当前进程持有的分布式锁信息。
+type LockInfo struct {
+	// Key 锁的完整 key。
+	Key string
+
+	// Backend 锁后端（如 redis, etcd）。
+	Backend string
+
+	// Mode 锁模式（如 mutex, read, write）。
+	Mode string
+
+	// AcquiredAt 获取时间。
+	AcquiredAt time.Time
+
+	// ExpiresAt 过期时间，零值表示由后端自动续期（如 etcd Session）。
+	ExpiresAt time.Time
+}
+
+// LockProvider 持有锁查询接口。
+// 此接口与 xdlock 兼容（Factory.Held）。
+type LockProvider interface {
+	// HeldLocks 返回当前进程持有的锁。
+	HeldLocks() []LockInfo
+}
+
+// PermitInfo 当前进程持有的信号量许可信息。
+type PermitInfo struct {
+	// ID 许可 ID。
+	ID string
+
+	// Resource 资源名称。
+	Resource string
+
+	// TenantID 租户 ID。
+	TenantID string
+
+	// Type 许可类型（如 distributed, local, noop）。
+	Type string
+
+	// Count 许可包含的名额数。
+	Count int
+
+	// AcquiredAt 获取时间。
+	AcquiredAt time.Time
+
+	// ExpiresAt 过期时间。
+	ExpiresAt time.Time
+}
+
+// SemaphoreProvider 持有许可查询接口。
+// 此接口与 xsemaphore 兼容（Semaphore.Held）。
+type SemaphoreProvider interface {
+	// HeldPermits 返回当前进程持有的许可。
+	HeldPermits() []PermitInfo
+}
+
+// LogSampler 日志采样控制接口（用于 logsample 命令）。
+// 此接口与 xlog.SamplingHandler 兼容，可通过 xlog.SamplerOf 获取后直接注入。
+type LogSampler interface {
+	// Paused 报告采样是否已暂停。
+	Paused() bool
+
+	// SetPaused 暂停或恢复采样，暂停期间全部日志直接输出。
+	SetPaused(paused bool)
+
+	// Rate 返回当前的采样速率：每个窗口内每条消息无条件输出 first 条，之后每 thereafter 条输出 1 条。
+	Rate() (first, thereafter int)
+
+	// SetRate 调整采样速率。
+	SetRate(first, thereafter int) error
+
+	// Suppressed 返回被抑制的日志总数。
+	Suppressed() uint64
+}
@@ -107,6 +107,15 @@ type options struct {
 	// ConfigProvider 配置提供者（用于 config 命令）。
 	ConfigProvider ConfigProvider
 
+	// LockProvider 持有锁查询（用于 locks 命令）。
+	LockProvider LockProvider
+
+	// SemaphoreProvider 持有许可查询（用于 permits 命令）。
+	SemaphoreProvider SemaphoreProvider
+
+	// LogSampler 日志采样控制（用于 logsample 命令）。
+	LogSampler LogSampler
+
 	// Transport 自定义传输层（可选）。
 	// 用于测试或自定义传输实现。
 	Transport Transport
@@ -289,6 +298,30 @@ func WithConfigProvider(provider ConfigProvider) Option {
 	}
 }
 
+// WithLockProvider 设置持有锁查询。
+// 用于 locks 命令。
+func WithLockProvider(provider LockProvider) Option {
+	return func(o *options) {
+		o.LockProvider = provider
+	}
+}
+
+// WithSemaphoreProvider 设置持有许可查询。
+// 用于 permits 命令。
+func WithSemaphoreProvider(provider SemaphoreProvider) Option {
+	return func(o *options) {
+		o.SemaphoreProvider = provider
+	}
+}
+
+// WithLogSampler 设置日志采样控制。
+// 用于 logsample 命令，通常传入 xlog.SamplerOf 返回的 *xlog.SamplingHandler。
+func WithLogSampler(sampler LogSampler) Option {
+	return func(o *options) {
+		o.LogSampler = sampler
+	}
+}
+
 // WithTransport 设置自定义传输层。
 // 用于测试或自定义传输实现。
 //
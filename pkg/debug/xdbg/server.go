@@ -33,6 +33,9 @@ type Server struct {
 
 	// pprofCmd 保存 pprof 命令引用，用于在 Stop 时清理资源
 	pprofCmd *pprofCommand
+
+	// logSampleCmd 保存 logsample 命令引用，用于在 Stop 时恢复临时调整的日志采样
+	logSampleCmd *logSampleCommand
 }
 
 // New 创建调试服务器。
@@ -137,6 +140,9 @@ func (s *Server) Stop() error {
 	// 清理 pprof 资源
 	s.cleanupPprof()
 
+	// 恢复临时调整的日志采样
+	s.restoreLogSampling()
+
 	// 关闭传输层和触发器
 	closeErr := s.closeTransportAndTrigger()
 
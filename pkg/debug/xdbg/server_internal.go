@@ -16,6 +16,13 @@ func (s *Server) cleanupPprof() {
 	}
 }
 
+// restoreLogSampling 恢复 logsample 命令临时调整的日志采样。
+func (s *Server) restoreLogSampling() {
+	if s.logSampleCmd != nil {
+		s.logSampleCmd.Restore("server-stop")
+	}
+}
+
 // closeTransportAndTrigger 关闭传输层和触发器，返回聚合错误。
 func (s *Server) closeTransportAndTrigger() error {
 	var errs []error
@@ -0,0 +1,208 @@
+package xcron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// CatchUpPolicy 错过调度时间时的补偿策略，通过 [WithCatchUp] 设置。
+type CatchUpPolicy int
+
+const (
+	// SkipMissed 跳过错过的执行，等待下一个调度时间（默认行为）。
+	SkipMissed CatchUpPolicy = iota
+
+	// RunOnce 检测到错过调度时间后，在下一个时机补偿执行一次。
+	// 无论错过多少次，都只合并为一次执行。
+	RunOnce
+
+	// RunAll 检测到错过调度时间后，按错过的次数逐次补偿执行。
+	// 单次补偿最多执行 [MaxCatchUpRuns] 次，超出部分丢弃。
+	RunAll
+)
+
+// MaxCatchUpRuns RunAll 策略单次补偿的最大执行次数。
+//
+// 设计决策: 停机时间很长或调度很密（如 "@every 1s" 停机一天）时，错过次数可达数万，
+// 全部补跑会长时间占用锁并冲击下游。超出上限的部分丢弃并记录告警日志。
+const MaxCatchUpRuns = 100
+
+// String 返回策略名称。
+func (p CatchUpPolicy) String() string {
+	switch p {
+	case SkipMissed:
+		return "skip_missed"
+	case RunOnce:
+		return "run_once"
+	case RunAll:
+		return "run_all"
+	default:
+		return fmt.Sprintf("CatchUpPolicy(%d)", int(p))
+	}
+}
+
+// RunRecorder 记录任务上次执行时间，由 [Locker] 实现可选提供。
+//
+// [WithCatchUp] 通过此接口在锁后端持久化上次执行时间，使多副本共享同一份记录：
+// 任一副本执行后，其他副本（包括重启后的副本）都能据此判断是否错过了调度。
+// [RedisLocker] 与 [K8sLocker] 实现了此接口；未实现的 Locker 退化为进程内记录，
+// 进程重启后记录丢失，因此只能补偿"执行期间错过"的调度。
+type RunRecorder interface {
+	// LastRun 返回 key 的上次执行时间，无记录时返回零值。
+	LastRun(ctx context.Context, key string) (time.Time, error)
+
+	// RecordRun 记录 key 在 at 时刻开始的一次执行已成功完成。
+	RecordRun(ctx context.Context, key string, at time.Time) error
+}
+
+// memoryRunRecorder 进程内 RunRecorder，用于未实现 RunRecorder 的 Locker。
+type memoryRunRecorder struct {
+	mu   sync.Mutex
+	runs map[string]time.Time
+}
+
+func newMemoryRunRecorder() *memoryRunRecorder {
+	return &memoryRunRecorder{runs: make(map[string]time.Time)}
+}
+
+// LastRun 实现 [RunRecorder]。
+func (r *memoryRunRecorder) LastRun(_ context.Context, key string) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.runs[key], nil
+}
+
+// RecordRun 实现 [RunRecorder]。
+func (r *memoryRunRecorder) RecordRun(_ context.Context, key string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[key] = at
+	return nil
+}
+
+// catchUpState 单个任务的补偿执行状态，仅在 RunOnce / RunAll 策略下创建。
+type catchUpState struct {
+	policy   CatchUpPolicy
+	schedule cron.Schedule
+	location *time.Location
+	recorder RunRecorder
+}
+
+// missedSince 判断 since 之后、now 之前是否存在已到期的调度时间。
+func (c *catchUpState) missedSince(since, now time.Time) bool {
+	return !c.schedule.Next(since.In(c.location)).After(now)
+}
+
+// missedRuns 返回 since 之后、now 之前已到期的调度次数，最多统计到 limit。
+func (c *catchUpState) missedRuns(since, now time.Time, limit int) int {
+	n := 0
+	for next := c.schedule.Next(since.In(c.location)); n < limit && !next.After(now); next = c.schedule.Next(next) {
+		n++
+	}
+	return n
+}
+
+// pendingRuns 返回按策略需要补偿的执行次数：RunOnce 至多 1 次，RunAll 为错过的次数。
+// truncated 表示错过次数超过 [MaxCatchUpRuns]，超出部分被丢弃。
+func (c *catchUpState) pendingRuns(since, now time.Time) (n int, truncated bool) {
+	if c.policy != RunAll {
+		if c.missedSince(since, now) {
+			return 1, false
+		}
+		return 0, false
+	}
+	n = c.missedRuns(since, now, MaxCatchUpRuns+1)
+	if n > MaxCatchUpRuns {
+		return MaxCatchUpRuns, true
+	}
+	return n, false
+}
+
+// recordRun 记录成功执行的开始时间。记录失败只影响后续补偿判断，不影响任务结果。
+// 设计决策: 与 safeTryLock 一致，RunRecorder 可能是第三方 Locker 实现，panic 转为日志。
+func (w *jobWrapper) recordRun(ctx context.Context, at time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logError(ctx, "RunRecorder.RecordRun panicked", "job", w.opts.name, "panic", r)
+		}
+	}()
+	recordCtx, cancel := context.WithTimeout(ctx, w.opts.lockTimeout)
+	defer cancel()
+	if err := w.catchUp.recorder.RecordRun(recordCtx, w.opts.name, at); err != nil {
+		w.logWarn(ctx, "failed to record last run", "job", w.opts.name, "error", err)
+	}
+}
+
+// lastRun 读取上次执行时间，失败或 panic 时返回零值。
+func (w *jobWrapper) lastRun(ctx context.Context) (last time.Time) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logError(ctx, "RunRecorder.LastRun panicked", "job", w.opts.name, "panic", r)
+			last = time.Time{}
+		}
+	}()
+	lookupCtx, cancel := context.WithTimeout(ctx, w.opts.lockTimeout)
+	defer cancel()
+	last, err := w.catchUp.recorder.LastRun(lookupCtx, w.opts.name)
+	if err != nil {
+		w.logWarn(ctx, "failed to read last run", "job", w.opts.name, "error", err)
+		return time.Time{}
+	}
+	return last
+}
+
+// catchUpMissed 注册任务时检查是否错过了调度（如所有副本停机期间），错过则按策略补偿执行。
+//
+// 无执行记录（首次部署）时不补偿。补偿执行同样需要获取锁，
+// 多个副本同时启动时只有一个副本执行，其余副本按锁竞争跳过。
+func (w *jobWrapper) catchUpMissed() {
+	ctx := w.runContext()
+	last := w.lastRun(ctx)
+	if last.IsZero() {
+		return
+	}
+	n, truncated := w.catchUp.pendingRuns(last, time.Now())
+	if n == 0 {
+		return
+	}
+	w.logWarn(ctx, "missed scheduled run detected, catching up",
+		"job", w.opts.name, "last_run", last, "policy", w.catchUp.policy.String(), "runs", n)
+	if truncated {
+		w.logWarn(ctx, "too many missed runs, dropping the rest",
+			"job", w.opts.name, "max_catch_up_runs", MaxCatchUpRuns)
+	}
+	if w.catchUp.policy != RunAll {
+		w.Run()
+		return
+	}
+	if w.state.paused.Load() || !w.waitJitter() {
+		return
+	}
+	w.runMissed(n)
+}
+
+// runMissed 逐次执行 n 次补偿，调度器停止、上下文取消或任务暂停时提前结束。
+//
+// 设计决策: 补偿执行之间不再检测新的错过，避免持续超时的任务无限补偿；
+// 某次补偿未获取到锁（其他副本正在执行）时停止，剩余补偿交由持锁者处理。
+func (w *jobWrapper) runMissed(n int) {
+	ctx := w.runContext()
+	for range n {
+		if ctx.Err() != nil || w.stopped() || w.state.paused.Load() {
+			return
+		}
+		_, lockState, _ := w.runOnce()
+		if lockState != lockStateAcquired && lockState != lockStateNone {
+			return
+		}
+	}
+}
+
+// stopped 报告调度器是否已停止。
+func (w *jobWrapper) stopped() bool {
+	return w.stopCtx != nil && w.stopCtx.Err() != nil
+}
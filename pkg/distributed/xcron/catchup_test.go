@@ -0,0 +1,346 @@
+package xcron
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// intervalSchedule 测试用固定间隔调度，不受 cron.Every 最小 1 秒的限制。
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+func TestCatchUpPolicy_String(t *testing.T) {
+	assert.Equal(t, "skip_missed", SkipMissed.String())
+	assert.Equal(t, "run_once", RunOnce.String())
+	assert.Equal(t, "run_all", RunAll.String())
+	assert.Equal(t, "CatchUpPolicy(9)", CatchUpPolicy(9).String())
+}
+
+func TestCatchUpState_MissedSince(t *testing.T) {
+	c := &catchUpState{schedule: intervalSchedule(time.Hour), location: time.UTC}
+	now := time.Now()
+
+	assert.False(t, c.missedSince(now.Add(-30*time.Minute), now))
+	assert.True(t, c.missedSince(now.Add(-time.Hour), now))
+	assert.True(t, c.missedSince(now.Add(-5*time.Hour), now))
+}
+
+func TestCatchUpState_PendingRuns(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name          string
+		policy        CatchUpPolicy
+		since         time.Time
+		want          int
+		wantTruncated bool
+	}{
+		{"run once not missed", RunOnce, now.Add(-30 * time.Minute), 0, false},
+		{"run once coalesces", RunOnce, now.Add(-5 * time.Hour), 1, false},
+		{"run all not missed", RunAll, now.Add(-30 * time.Minute), 0, false},
+		{"run all counts each", RunAll, now.Add(-5*time.Hour - time.Minute), 5, false},
+		{"run all capped", RunAll, now.Add(-1000 * time.Hour), MaxCatchUpRuns, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &catchUpState{policy: tt.policy, schedule: intervalSchedule(time.Hour), location: time.UTC}
+			n, truncated := c.pendingRuns(tt.since, now)
+			assert.Equal(t, tt.want, n)
+			assert.Equal(t, tt.wantTruncated, truncated)
+		})
+	}
+}
+
+func TestMemoryRunRecorder(t *testing.T) {
+	ctx := context.Background()
+	r := newMemoryRunRecorder()
+
+	last, err := r.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	at := time.Now()
+	require.NoError(t, r.RecordRun(ctx, "job", at))
+	last, err = r.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.Equal(t, at, last)
+}
+
+func TestRedisLocker_RunRecorder(t *testing.T) {
+	ctx := context.Background()
+	locker, mr := setupRedisLocker(t)
+
+	last, err := locker.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	at := time.Unix(1700000000, 123)
+	require.NoError(t, locker.RecordRun(ctx, "job", at))
+	assert.True(t, mr.Exists("xcron:lock:job:last-run"))
+	assert.Equal(t, time.Duration(0), mr.TTL("xcron:lock:job:last-run"))
+
+	last, err = locker.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(last))
+
+	t.Run("redis error", func(t *testing.T) {
+		mr.SetError("boom")
+		defer mr.SetError("")
+		_, err := locker.LastRun(ctx, "job")
+		assert.Error(t, err)
+		assert.Error(t, locker.RecordRun(ctx, "job", at))
+	})
+}
+
+func TestK8sLocker_RunRecorder(t *testing.T) {
+	ctx := context.Background()
+	locker, err := NewK8sLocker(K8sLockerOptions{
+		Client:    fake.NewSimpleClientset(),
+		Namespace: "default",
+		Identity:  "pod-1",
+	})
+	require.NoError(t, err)
+
+	last, err := locker.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero(), "missing lease means no record")
+
+	assert.Error(t, locker.RecordRun(ctx, "job", time.Now()), "record requires the lease to exist")
+
+	handle, err := locker.TryLock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+
+	last, err = locker.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, last.IsZero(), "lease without annotation means no record")
+
+	at := time.Now()
+	require.NoError(t, locker.RecordRun(ctx, "job", at))
+	require.NoError(t, handle.Renew(ctx, time.Minute))
+	require.NoError(t, handle.Unlock(ctx))
+
+	last, err = locker.LastRun(ctx, "job")
+	require.NoError(t, err)
+	assert.True(t, at.Equal(last), "annotation must survive renew and unlock")
+
+	t.Run("invalid annotation", func(t *testing.T) {
+		leases := locker.client.CoordinationV1().Leases("default")
+		lease, err := leases.Get(ctx, locker.leaseName("job"), metav1.GetOptions{})
+		require.NoError(t, err)
+		lease.Annotations[k8sLastRunAnnotation] = "not-a-time"
+		_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		_, err = locker.LastRun(ctx, "job")
+		assert.Error(t, err)
+	})
+}
+
+func TestWithCatchUp_RequiresName(t *testing.T) {
+	s := New()
+	_, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithCatchUp(RunOnce))
+	assert.ErrorIs(t, err, ErrMissingName)
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithCatchUp(RunAll))
+	assert.ErrorIs(t, err, ErrMissingName)
+}
+
+func TestWithCatchUp_RunsOnceAfterDowntime(t *testing.T) {
+	ctx := context.Background()
+	locker, _ := setupRedisLocker(t)
+	// 上次执行在 3 小时前，期间错过了多次整点调度
+	require.NoError(t, locker.RecordRun(ctx, "sync", time.Now().Add(-3*time.Hour)))
+
+	var runs atomic.Int32
+	s := New(WithLocker(locker))
+	_, err := s.AddFunc("@every 1h", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithName("sync"), WithCatchUp(RunOnce))
+	require.NoError(t, err)
+	// Stop 会取消补偿执行的上下文，需等待补偿完成后再停止
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	<-s.Stop().Done()
+
+	assert.Equal(t, int32(1), runs.Load(), "multiple missed runs coalesce into one")
+	last, err := locker.LastRun(ctx, "sync")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), last, 5*time.Second)
+}
+
+func TestWithCatchUp_RunAllAfterDowntime(t *testing.T) {
+	ctx := context.Background()
+	locker, _ := setupRedisLocker(t)
+	// 上次成功执行在 3 小时零 1 分前，期间错过 3 次调度
+	require.NoError(t, locker.RecordRun(ctx, "bill", time.Now().Add(-3*time.Hour-time.Minute)))
+
+	var runs atomic.Int32
+	s := New(WithLocker(locker))
+	_, err := s.AddFunc("@every 1h", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithName("bill"), WithCatchUp(RunAll))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return runs.Load() == 3 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	<-s.Stop().Done()
+
+	assert.Equal(t, int32(3), runs.Load(), "each missed run is executed")
+}
+
+func TestWithCatchUp_FailedRunNotRecorded(t *testing.T) {
+	ctx := context.Background()
+	locker, _ := setupRedisLocker(t)
+	last := time.Now().Add(-3 * time.Hour).Truncate(time.Millisecond)
+	require.NoError(t, locker.RecordRun(ctx, "sync", last))
+
+	var runs atomic.Int32
+	s := New(WithLocker(locker))
+	_, err := s.AddFunc("@every 1h", func(context.Context) error {
+		runs.Add(1)
+		return errors.New("downstream unavailable")
+	}, WithName("sync"), WithCatchUp(RunOnce))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return runs.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	<-s.Stop().Done()
+
+	got, err := locker.LastRun(ctx, "sync")
+	require.NoError(t, err)
+	assert.True(t, last.Equal(got), "failed run keeps the last successful run time")
+}
+
+func TestWithCatchUp_NoCatchUp(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		lastRun time.Time
+		opts    []JobOption
+	}{
+		{"no record", time.Time{}, []JobOption{WithCatchUp(RunOnce)}},
+		{"not missed", time.Now().Add(-10 * time.Minute), []JobOption{WithCatchUp(RunOnce)}},
+		{"skip missed policy", time.Now().Add(-3 * time.Hour), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locker, _ := setupRedisLocker(t)
+			if !tt.lastRun.IsZero() {
+				require.NoError(t, locker.RecordRun(ctx, "sync", tt.lastRun))
+			}
+
+			var runs atomic.Int32
+			s := New(WithLocker(locker))
+			opts := append([]JobOption{WithName("sync")}, tt.opts...)
+			_, err := s.AddFunc("@every 1h", func(context.Context) error {
+				runs.Add(1)
+				return nil
+			}, opts...)
+			require.NoError(t, err)
+			time.Sleep(100 * time.Millisecond)
+			<-s.Stop().Done()
+
+			assert.Equal(t, int32(0), runs.Load())
+		})
+	}
+}
+
+func TestWithCatchUp_SkipsWhenLockHeld(t *testing.T) {
+	ctx := context.Background()
+	locker, _ := setupRedisLocker(t)
+	require.NoError(t, locker.RecordRun(ctx, "sync", time.Now().Add(-3*time.Hour)))
+	handle, err := locker.TryLock(ctx, "sync", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+
+	var runs atomic.Int32
+	s := New(WithLocker(locker))
+	_, err = s.AddFunc("@every 1h", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithName("sync"), WithCatchUp(RunOnce))
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	<-s.Stop().Done()
+
+	assert.Equal(t, int32(0), runs.Load(), "another replica is catching up")
+}
+
+func TestJobWrapper_CatchUpAfterOverrun(t *testing.T) {
+	var runs atomic.Int32
+	job := JobFunc(func(context.Context) error {
+		// 每次执行都超过调度间隔
+		runs.Add(1)
+		time.Sleep(60 * time.Millisecond)
+		return nil
+	})
+
+	opts := defaultJobOptions()
+	opts.name = "slow"
+	w := newJobWrapper(job, NoopLocker(), nil, nil, opts)
+	w.catchUp = &catchUpState{
+		schedule: intervalSchedule(20 * time.Millisecond),
+		location: time.Local,
+		recorder: newMemoryRunRecorder(),
+	}
+
+	w.Run()
+	assert.Equal(t, int32(2), runs.Load(), "catch-up runs once and does not cascade")
+
+	last, err := w.catchUp.recorder.LastRun(context.Background(), "slow")
+	require.NoError(t, err)
+	assert.False(t, last.IsZero())
+}
+
+func TestJobWrapper_RunAllAfterOverrun(t *testing.T) {
+	var runs atomic.Int32
+	job := JobFunc(func(context.Context) error {
+		// 首次执行错过 3 次调度，补偿执行不再级联
+		if runs.Add(1) == 1 {
+			time.Sleep(70 * time.Millisecond)
+		}
+		return nil
+	})
+
+	opts := defaultJobOptions()
+	opts.name = "slow"
+	w := newJobWrapper(job, NoopLocker(), nil, nil, opts)
+	w.catchUp = &catchUpState{
+		policy:   RunAll,
+		schedule: intervalSchedule(20 * time.Millisecond),
+		location: time.Local,
+		recorder: newMemoryRunRecorder(),
+	}
+
+	w.Run()
+	assert.Equal(t, int32(4), runs.Load())
+}
+
+func TestJobWrapper_NoCatchUpWithoutOverrun(t *testing.T) {
+	var runs atomic.Int32
+	opts := defaultJobOptions()
+	opts.name = "fast"
+	w := newJobWrapper(JobFunc(func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}), NoopLocker(), nil, nil, opts)
+	w.catchUp = &catchUpState{
+		schedule: intervalSchedule(time.Hour),
+		location: time.Local,
+		recorder: newMemoryRunRecorder(),
+	}
+
+	w.Run()
+	assert.Equal(t, int32(1), runs.Load())
+}
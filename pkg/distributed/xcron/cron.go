@@ -22,6 +22,15 @@ var ErrMissingName = errors.New("xcron: job with distributed locker must have a
 // 重复名称会导致两个逻辑不同的任务共享同一把锁和统计数据，互相干扰。
 var ErrDuplicateJobName = errors.New("xcron: duplicate job name, each job must have a unique name within the same scheduler")
 
+// ErrJobNotFound 表示按名称查找的任务未注册。
+var ErrJobNotFound = errors.New("xcron: job not found")
+
+// ErrLockHeld 表示手动触发时锁被其他实例持有（任务正在其他副本上执行），本次未执行。
+var ErrLockHeld = errors.New("xcron: job lock is held by another instance")
+
+// ErrJobRunning 表示手动触发时任务启用了 WithSkipIfRunning 且本实例上一次执行尚未结束，本次未执行。
+var ErrJobRunning = errors.New("xcron: job is still running on this instance")
+
 // cronScheduler 基于 robfig/cron/v3 的调度器实现
 type cronScheduler struct {
 	cron   *cron.Cron
@@ -30,8 +39,9 @@ type cronScheduler struct {
 	logger Logger
 	stats  *Stats // 执行统计
 
-	mu       sync.Mutex       // 保护 jobNames 的并发读写
-	jobNames map[string]JobID // 已注册的任务名 → EntryID，用于唯一性校验和 Remove 时释放
+	mu       sync.Mutex         // 保护 jobNames 的并发读写
+	jobNames map[string]JobID   // 已注册的任务名 → EntryID，用于唯一性校验和 Remove 时释放
+	memRuns  *memoryRunRecorder // Locker 未实现 RunRecorder 时的进程内执行记录
 
 	immediateWg     sync.WaitGroup     // 追踪 WithImmediate 启动的立即执行任务
 	immediateCtx    context.Context    // 立即执行任务的可取消上下文
@@ -78,6 +88,7 @@ func New(opts ...SchedulerOption) Scheduler {
 		logger:          options.logger,
 		stats:           newStats(),
 		jobNames:        make(map[string]JobID),
+		memRuns:         newMemoryRunRecorder(),
 		immediateCtx:    immediateCtx,
 		immediateCancel: immediateCancel,
 	}
@@ -121,6 +132,12 @@ func (s *cronScheduler) AddJob(spec string, job Job, opts ...JobOption) (JobID,
 		locker = s.locker
 	}
 
+	// 使用与底层 cron 相同的解析器，补偿策略需要 Schedule 计算错过的调度时间
+	schedule, err := s.opts.parser.Parse(spec)
+	if err != nil {
+		return 0, fmt.Errorf("xcron: failed to add job: %w", err)
+	}
+
 	// 持有 mu 保护 validateJobName → cron.AddJob → jobNames 写入的完整序列，
 	// 防止并发 AddJob/Remove 导致 map 竞态（fatal: concurrent map writes）。
 	s.mu.Lock()
@@ -133,13 +150,21 @@ func (s *cronScheduler) AddJob(spec string, job Job, opts ...JobOption) (JobID,
 
 	// 创建包装器
 	wrapper := newJobWrapper(job, locker, s.logger, s.stats, jobOpts)
+	wrapper.observer = s.opts.observer
+	wrapper.onResult = s.opts.onResult
+	wrapper.spec = spec
+	wrapper.stopCtx = s.immediateCtx
+	if jobOpts.catchUp != SkipMissed {
+		wrapper.catchUp = &catchUpState{
+			policy:   jobOpts.catchUp,
+			schedule: schedule,
+			location: s.opts.location,
+			recorder: s.runRecorder(locker),
+		}
+	}
 
 	// 添加到底层 cron
-	id, err := s.cron.AddJob(spec, wrapper)
-	if err != nil {
-		s.mu.Unlock()
-		return 0, fmt.Errorf("xcron: failed to add job: %w", err)
-	}
+	id := s.cron.Schedule(schedule, wrapper)
 
 	// 注册任务名
 	if jobOpts.name != "" {
@@ -158,16 +183,34 @@ func (s *cronScheduler) AddJob(spec string, job Job, opts ...JobOption) (JobID,
 			w.baseCtx = s.immediateCtx
 			w.Run()
 		})
+	} else if wrapper.catchUp != nil {
+		s.immediateWg.Go(func() {
+			w := *wrapper
+			w.baseCtx = s.immediateCtx
+			w.catchUpMissed()
+		})
 	}
 
 	return id, nil
 }
 
+// runRecorder 返回任务的执行记录存储：Locker 实现了 RunRecorder 时使用锁后端，
+// 否则使用调度器内的进程内记录。
+func (s *cronScheduler) runRecorder(locker Locker) RunRecorder {
+	if rr, ok := locker.(RunRecorder); ok {
+		return rr
+	}
+	return s.memRuns
+}
+
 // validateJobName 校验任务名：分布式锁场景必须设置任务名，且名称不可重复。
 func (s *cronScheduler) validateJobName(jobOpts *jobOptions, locker Locker) error {
 	// 设计决策: 配置了分布式锁但未设置任务名时 fail-fast 返回错误，
 	// 而非静默降级跳过加锁。静默降级在多副本场景下会导致重复执行。
 	if jobOpts.name == "" {
+		if jobOpts.catchUp != SkipMissed {
+			return ErrMissingName
+		}
 		if _, isNoop := locker.(noopIndicator); !isNoop {
 			return ErrMissingName
 		}
@@ -200,6 +243,89 @@ func (s *cronScheduler) Remove(id JobID) {
 	s.cron.Remove(id)
 }
 
+// RemoveByName 按任务名移除任务，任务未注册时返回 [ErrJobNotFound]。
+func (s *cronScheduler) RemoveByName(name string) error {
+	s.mu.Lock()
+	id, ok := s.jobNames[name]
+	if ok {
+		delete(s.jobNames, name)
+	}
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	s.cron.Remove(id)
+	return nil
+}
+
+// Pause 暂停任务的定时触发，任务保留注册。
+//
+// 设计决策: 暂停只在触发入口检查标记，不移除 cron 条目，
+// 因此任务在 Jobs() 中仍可见且 NextRun 照常推进，Resume 后无需重新计算调度；
+// 正在执行的任务不受影响，会正常执行完成。
+func (s *cronScheduler) Pause(name string) error {
+	w, err := s.lookupJob(name)
+	if err != nil {
+		return err
+	}
+	w.state.paused.Store(true)
+	return nil
+}
+
+// Resume 恢复已暂停任务的定时触发，对未暂停的任务无效果。
+func (s *cronScheduler) Resume(name string) error {
+	w, err := s.lookupJob(name)
+	if err != nil {
+		return err
+	}
+	w.state.paused.Store(false)
+	return nil
+}
+
+// lookupJob 按任务名查找包装器，任务未注册时返回 [ErrJobNotFound]。
+func (s *cronScheduler) lookupJob(name string) (*jobWrapper, error) {
+	s.mu.Lock()
+	id, ok := s.jobNames[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	wrapper, ok := s.cron.Entry(id).Job.(*jobWrapper)
+	if !ok {
+		// 任务已通过 Cron().Remove 直接移除，注册表中为陈旧记录
+		return nil, fmt.Errorf("%w: %q", ErrJobNotFound, name)
+	}
+	return wrapper, nil
+}
+
+// TriggerNow 立即执行一次指定任务，与定时触发走相同的锁、超时、重试、钩子与统计流程。
+//
+// 设计决策: 同步执行并直接返回结果，而非像 WithImmediate 那样后台执行，
+// 运维脚本需要知道这次手动触发是否真的执行以及是否成功。
+// 手动触发不计入错过补偿判断，也不会触发 RunOnce 的超时补偿。
+// 已暂停的任务仍可手动触发，便于暂停定时调度后由运维按需执行。
+func (s *cronScheduler) TriggerNow(ctx context.Context, name string) error {
+	wrapper, err := s.lookupJob(name)
+	if err != nil {
+		return err
+	}
+
+	// 浅拷贝包装器，使用调用方的 ctx 作为根上下文（与 WithImmediate 一致）
+	w := *wrapper
+	w.baseCtx = ctx
+	_, lockState, err := w.runOnce()
+	if err != nil {
+		return err
+	}
+	switch lockState {
+	case lockStateRunning:
+		return fmt.Errorf("%w: %q", ErrJobRunning, name)
+	case lockStateSkipped:
+		return fmt.Errorf("%w: %q", ErrLockHeld, name)
+	}
+	return nil
+}
+
 // Start 启动调度器
 func (s *cronScheduler) Start() {
 	s.cron.Start()
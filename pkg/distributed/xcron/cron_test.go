@@ -829,3 +829,82 @@ func TestScheduler_ConcurrentAddRemove(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestScheduler_TriggerNow(t *testing.T) {
+	t.Run("runs job once and records stats", func(t *testing.T) {
+		var runs atomic.Int32
+		s := New(WithLocker(newMockLocker()))
+		_, err := s.AddFunc("@every 1h", func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}, WithName("report"))
+		require.NoError(t, err)
+
+		require.NoError(t, s.TriggerNow(context.Background(), "report"))
+		assert.Equal(t, int32(1), runs.Load())
+		assert.Equal(t, int64(1), s.Stats().JobStats("report").SuccessCount())
+	})
+
+	t.Run("returns job error", func(t *testing.T) {
+		jobErr := fmt.Errorf("report failed")
+		s := New()
+		_, err := s.AddFunc("@every 1h", func(context.Context) error { return jobErr }, WithName("report"))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), "report"), jobErr)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		s := New()
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), "missing"), ErrJobNotFound)
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), ""), ErrJobNotFound)
+	})
+
+	t.Run("removed job", func(t *testing.T) {
+		s := New()
+		id, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("report"))
+		require.NoError(t, err)
+		s.Cron().Remove(id)
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), "report"), ErrJobNotFound)
+	})
+
+	t.Run("lock held by another instance", func(t *testing.T) {
+		locker := newMockLocker()
+		var runs atomic.Int32
+		s := New(WithLocker(locker))
+		_, err := s.AddFunc("@every 1h", func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}, WithName("report"))
+		require.NoError(t, err)
+
+		handle, err := locker.TryLock(context.Background(), "report", time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, handle)
+
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), "report"), ErrLockHeld)
+		assert.Equal(t, int32(0), runs.Load())
+	})
+
+	t.Run("lock service error", func(t *testing.T) {
+		lockErr := fmt.Errorf("redis down")
+		s := New(WithLocker(&errorLocker{err: lockErr}))
+		_, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("report"))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, s.TriggerNow(context.Background(), "report"), lockErr)
+	})
+
+	t.Run("caller context cancels job", func(t *testing.T) {
+		s := New()
+		_, err := s.AddFunc("@every 1h", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, WithName("report"))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, s.TriggerNow(ctx, "report"), context.DeadlineExceeded)
+	})
+}
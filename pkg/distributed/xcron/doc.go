@@ -17,6 +17,10 @@
 //   - 多副本（在线）：使用 RedisLocker，基于 Redis 分布式锁
 //   - 多副本（离线）：使用 K8sLocker，基于 K8S Lease 资源
 //
+// K8sLocker 可通过 WithLeaseNamespace 指定 Lease 所在命名空间（RBAC 受限或多个
+// Deployment 共享集群时），通过 WithHolderIdentity 指定实例标识（默认 POD_NAME）。
+// 实例标识须每个副本唯一，K8sLocker.IdentityConflicts 暴露运行时检测到的冲突次数。
+//
 // # 任务选项
 //
 //   - WithName: 任务名（用作锁 key，必须唯一；使用分布式锁时必须设置）
@@ -25,6 +29,35 @@
 //   - WithTimeout: 任务执行超时
 //   - WithRetry: 重试策略
 //   - WithImmediate: 注册后立即执行一次
+//   - WithCatchUp: 错过调度时间后的补偿策略
+//   - WithJitter: 每次触发前的随机延迟，打散多副本同时抢锁
+//   - WithSkipIfRunning: 本实例上一次执行未结束时跳过新触发（进程内互斥，与 Locker 无关）
+//
+// 运维场景可通过 Scheduler.TriggerNow(ctx, name) 在调度时间之外同步执行一次任务，
+// 同样需要获取分布式锁：锁被其他副本持有时返回 ErrLockHeld，任务未注册时返回 ErrJobNotFound。
+// Scheduler.Pause(name)/Resume(name) 暂停与恢复任务的定时触发，暂停期间任务保留注册
+// （Jobs() 中 Paused 为 true），正在执行的一次会正常完成；RemoveByName(name) 按名称移除任务。
+//
+// # 错过补偿
+//
+// 默认（SkipMissed）错过的调度直接跳过。WithCatchUp(RunOnce) 在两种场景补偿执行：
+// 注册任务时发现上次执行距今已错过调度（如全部副本停机），以及单次执行耗时超过调度间隔。
+// 无论错过多少次都只合并为一次执行；WithCatchUp(RunAll) 则按错过次数逐次补偿
+// （单次最多 MaxCatchUpRuns 次）。上次成功执行时间通过 Locker 后端持久化
+// （RedisLocker 写入 "{prefix}{name}:last-run"，K8sLocker 写入 Lease 注解），
+// 其他 Locker 退化为进程内记录。
+//
+// # 可观测性
+//
+// 调度器级 [WithObserver] 接入 xmetrics：每次触发创建一个 span（component=xcron,
+// operation=job_run），覆盖锁获取与任务执行，属性 xcron.lock 标明是否抢到锁。
+// 任务级 [WithTracer] 仅在抢到锁后创建 span，适合只关心实际执行的场景。
+//
+// [WithJobObserver] 注册调度器级结果回调 func(name, duration, err, skipped)，
+// 每次触发结束后调用（含锁竞争跳过和任务 panic），便于统一上报成功率等指标。
+//
+// Scheduler.Jobs 返回每个任务的 cron 表达式、下次调度时间及本实例的上次执行时间、
+// 错误、锁结果和运行状态，可直接序列化为 JSON 用于调试页面。
 //
 // # 任务实现要求
 //
@@ -317,11 +317,18 @@ func (s *customScheduler) AddJob(_ string, _ Job, _ ...JobOption) (JobID, error)
 	return 0, nil
 }
 
-func (s *customScheduler) Remove(_ JobID)        {}
+func (s *customScheduler) Remove(_ JobID)              {}
+func (s *customScheduler) RemoveByName(_ string) error { return nil }
+func (s *customScheduler) Pause(_ string) error        { return nil }
+func (s *customScheduler) Resume(_ string) error       { return nil }
+func (s *customScheduler) TriggerNow(_ context.Context, _ string) error {
+	return nil
+}
 func (s *customScheduler) Start()                {}
 func (s *customScheduler) Stop() context.Context { return context.Background() }
 func (s *customScheduler) Cron() *cron.Cron      { return nil }
 func (s *customScheduler) Entries() []cron.Entry { return nil }
+func (s *customScheduler) Jobs() []JobStatus     { return nil }
 func (s *customScheduler) Stats() *Stats         { return nil }
 
 var _ Scheduler = (*customScheduler)(nil)
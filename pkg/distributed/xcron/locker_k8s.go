@@ -9,7 +9,10 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	coordinationv1 "k8s.io/api/coordination/v1"
@@ -26,6 +29,8 @@ var (
 	// k8sPrefixRegex 校验 Lease 名称前缀：小写字母、数字、'-'，首字符必须为字母或数字。
 	// 允许以 '-' 结尾（作为前缀与 key 的分隔符）。
 	k8sPrefixRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+	// k8sNamespaceRegex 校验命名空间：DNS-1123 label，首尾必须为字母或数字。
+	k8sNamespaceRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
 )
 
 // K8sLocker 基于 K8S Lease 的分布式锁。
@@ -44,20 +49,35 @@ var (
 //
 // 用法：
 //
-//	locker, err := xcron.NewK8sLocker(xcron.K8sLockerOptions{
-//	    Namespace: "my-namespace",
-//	    Identity:  os.Getenv("POD_NAME"),
-//	})
+//	locker, err := xcron.NewK8sLocker(xcron.K8sLockerOptions{},
+//	    xcron.WithLeaseNamespace("my-namespace"),
+//	    xcron.WithHolderIdentity(os.Getenv("POD_NAME")),
+//	)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	scheduler := xcron.New(xcron.WithLocker(locker))
+//
+// # 实例标识冲突
+//
+// 锁的互斥性由每次获取生成的唯一 token（identity:uuid）保证，多个副本使用
+// 相同 identity 不会破坏互斥。但 Lease 的 holderIdentity 将无法区分持有者，
+// 排障时无法判断是哪个副本在执行任务、哪个副本卡住未释放锁。
+// 常见原因是把 identity 设为 Deployment 名等所有副本共享的值。
+//
+// K8sLocker 在 TryLock 时检测冲突：Lease 被未过期、带有本实例 identity 但不是
+// 本实例签发的 token 持有时，计入 [K8sLocker.IdentityConflicts]。
+// 容器重启（Pod 名不变）后的一个 TTL 内，旧进程遗留的 Lease 也会被计入，属预期现象；
+// 计数持续增长才说明多个副本共用了 identity。
 type K8sLocker struct {
 	client    kubernetes.Interface
 	namespace string
 	identity  string        // 实例标识（用于日志和调试）
 	prefix    string        // Lease 名称前缀
 	clockSkew time.Duration // 时钟偏移容忍度
+
+	issued    sync.Map      // 本实例签发且尚未释放的 token，用于识别 identity 冲突
+	conflicts atomic.Uint64 // 检测到的 identity 冲突次数
 }
 
 // k8sLockHandle 表示一次成功的 K8s Lease 锁获取
@@ -94,6 +114,40 @@ type K8sLockerOptions struct {
 	ClockSkew time.Duration
 }
 
+// K8sLockerOption 以函数式选项覆盖 [K8sLockerOptions] 中的字段。
+//
+// 设计决策: 保留 K8sLockerOptions 结构体参数以兼容既有调用，
+// 选项在结构体之后应用，同一字段以选项为准。
+type K8sLockerOption func(*K8sLockerOptions)
+
+// WithLeaseNamespace 设置 Lease 所在的命名空间。
+//
+// 默认从环境变量 POD_NAMESPACE 读取，或 "default"。
+// RBAC 受限时可指定一个已授予 Lease get/create/update 权限的命名空间；
+// 多个 Deployment 共享集群时，也可借此隔离各自的 Lease。
+// 注意：同一组需要互斥的副本必须使用相同的命名空间，否则锁互不可见。
+// ns 为空时忽略此选项。
+func WithLeaseNamespace(ns string) K8sLockerOption {
+	return func(o *K8sLockerOptions) {
+		if ns != "" {
+			o.Namespace = ns
+		}
+	}
+}
+
+// WithHolderIdentity 设置当前实例标识，写入 Lease holderIdentity 的 token 前缀。
+//
+// 默认从环境变量 POD_NAME 读取，或 hostname:pid。
+// 每个副本必须唯一，冲突后果见 [K8sLocker] 的「实例标识冲突」一节。
+// id 为空时忽略此选项。
+func WithHolderIdentity(id string) K8sLockerOption {
+	return func(o *K8sLockerOptions) {
+		if id != "" {
+			o.Identity = id
+		}
+	}
+}
+
 // DefaultClockSkew 是默认的时钟偏移容忍度。
 // K8s 官方 leader-election 库使用的默认值也是 2 秒。
 const DefaultClockSkew = 2 * time.Second
@@ -109,6 +163,12 @@ const k8sMinKeyBudget = 10
 // 且长度不超过 k8sMaxNameLen - k8sMinKeyBudget。
 var ErrInvalidK8sPrefix = fmt.Errorf("xcron: invalid K8s Lease prefix")
 
+// ErrInvalidK8sNamespace 表示 K8s 命名空间不合法（须为不超过 63 字符的 DNS-1123 label）。
+var ErrInvalidK8sNamespace = fmt.Errorf("xcron: invalid K8s namespace")
+
+// ErrInvalidK8sIdentity 表示实例标识不合法（不能包含空白或控制字符）。
+var ErrInvalidK8sIdentity = fmt.Errorf("xcron: invalid K8s holder identity")
+
 // NewK8sLocker 创建基于 K8S Lease 的分布式锁。
 //
 // 如果不提供 Client，将使用 InClusterConfig 自动创建，
@@ -116,7 +176,17 @@ var ErrInvalidK8sPrefix = fmt.Errorf("xcron: invalid K8s Lease prefix")
 //
 // Prefix 必须符合 DNS-1123 字符集（小写字母、数字、'-'），
 // 首字符必须为字母或数字，且长度不超过 53（为 key 预留至少 10 个字符）。
-func NewK8sLocker(opts K8sLockerOptions) (*K8sLocker, error) {
+// Namespace 必须是合法的 DNS-1123 label，否则返回 [ErrInvalidK8sNamespace]；
+// Identity 不能包含空白或控制字符，否则返回 [ErrInvalidK8sIdentity]。
+//
+// extra 中的选项在 opts 之后应用，可覆盖 Namespace、Identity 等字段。
+func NewK8sLocker(opts K8sLockerOptions, extra ...K8sLockerOption) (*K8sLocker, error) {
+	for _, opt := range extra {
+		if opt != nil {
+			opt(&opts)
+		}
+	}
+
 	// 设置默认值
 	if opts.Namespace == "" {
 		opts.Namespace = getEnvOrDefault("POD_NAMESPACE", "default")
@@ -137,6 +207,12 @@ func NewK8sLocker(opts K8sLockerOptions) (*K8sLocker, error) {
 	if err := validateK8sPrefix(opts.Prefix); err != nil {
 		return nil, err
 	}
+	if err := validateK8sNamespace(opts.Namespace); err != nil {
+		return nil, err
+	}
+	if err := validateK8sIdentity(opts.Identity); err != nil {
+		return nil, err
+	}
 
 	// 创建 K8S 客户端
 	client := opts.Client
@@ -192,9 +268,80 @@ func (l *K8sLocker) TryLock(ctx context.Context, key string, ttl time.Duration)
 		return l.acquireLease(ctx, key, leaseName, token, lease, leaseDuration, now)
 	}
 
+	l.detectIdentityConflict(lease)
 	return nil, nil // 被其他实例持有
 }
 
+// IdentityConflicts 返回检测到的 identity 冲突次数。
+//
+// 非零且持续增长说明有其他副本使用了相同的 identity，
+// 应检查 WithHolderIdentity / POD_NAME 配置。
+func (l *K8sLocker) IdentityConflicts() uint64 {
+	return l.conflicts.Load()
+}
+
+// detectIdentityConflict 检查未过期 Lease 的持有者是否为同 identity 的其他实例
+func (l *K8sLocker) detectIdentityConflict(lease *coordinationv1.Lease) {
+	holder := lease.Spec.HolderIdentity
+	if holder == nil {
+		return
+	}
+	// token 格式为 identity:uuid；校验剩余部分是 uuid，避免 "a" 误匹配 "a:b" 的 token
+	rest, ok := strings.CutPrefix(*holder, l.identity+":")
+	if !ok {
+		return
+	}
+	if _, err := uuid.Parse(rest); err != nil {
+		return
+	}
+	if _, ours := l.issued.Load(*holder); !ours {
+		l.conflicts.Add(1)
+	}
+}
+
+// k8sLastRunAnnotation 记录任务上次执行时间的 Lease 注解（RFC3339Nano）。
+const k8sLastRunAnnotation = "xcron.xkit.io/last-run"
+
+// LastRun 实现 [RunRecorder]，从 Lease 注解读取 key 的上次执行时间，无记录时返回零值。
+func (l *K8sLocker) LastRun(ctx context.Context, key string) (time.Time, error) {
+	lease, err := l.client.CoordinationV1().Leases(l.namespace).Get(ctx, l.leaseName(key), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("xcron: failed to get lease for last run: %w", err)
+	}
+	value, ok := lease.Annotations[k8sLastRunAnnotation]
+	if !ok {
+		return time.Time{}, nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("xcron: invalid last run annotation %q: %w", value, err)
+	}
+	return at, nil
+}
+
+// RecordRun 实现 [RunRecorder]，将执行时间写入 Lease 注解。
+//
+// 设计决策: 复用锁所在的 Lease 而非额外创建资源，无需新增 RBAC 权限。
+// 调度器在持有锁时调用，Lease 必然存在；Unlock/Renew 基于最新 Lease 更新，不会覆盖注解。
+func (l *K8sLocker) RecordRun(ctx context.Context, key string, at time.Time) error {
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	lease, err := leases.Get(ctx, l.leaseName(key), metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("xcron: failed to get lease for last run: %w", err)
+	}
+	if lease.Annotations == nil {
+		lease.Annotations = make(map[string]string, 1)
+	}
+	lease.Annotations[k8sLastRunAnnotation] = at.Format(time.RFC3339Nano)
+	if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("xcron: failed to record last run: %w", err)
+	}
+	return nil
+}
+
 // Identity 返回当前实例标识。
 func (l *K8sLocker) Identity() string {
 	return l.identity
@@ -237,6 +384,7 @@ func (l *K8sLocker) createLease(ctx context.Context, key, leaseName, token strin
 		return nil, fmt.Errorf("xcron: failed to create lease: %w", err)
 	}
 
+	l.issued.Store(token, struct{}{})
 	return &k8sLockHandle{
 		locker:    l,
 		key:       key,
@@ -261,6 +409,7 @@ func (l *K8sLocker) acquireLease(ctx context.Context, key, leaseName, token stri
 		return nil, fmt.Errorf("xcron: failed to acquire lease: %w", err)
 	}
 
+	l.issued.Store(token, struct{}{})
 	return &k8sLockHandle{
 		locker:    l,
 		key:       key,
@@ -303,6 +452,9 @@ func (l *K8sLocker) isLeaseExpired(lease *coordinationv1.Lease) bool {
 //
 // 清除 Lease 的 holderIdentity，允许其他实例获取。
 func (h *k8sLockHandle) Unlock(ctx context.Context) error {
+	// 无论释放是否成功，该 token 都不会再被使用；释放失败时 Lease 由 TTL 过期
+	defer h.locker.issued.Delete(h.token)
+
 	lease, err := h.locker.client.CoordinationV1().Leases(h.locker.namespace).Get(ctx, h.leaseName, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -439,6 +591,25 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// validateK8sNamespace 校验命名空间是否为合法的 DNS-1123 label。
+func validateK8sNamespace(ns string) error {
+	if len(ns) > k8sMaxNameLen || !k8sNamespaceRegex.MatchString(ns) {
+		return fmt.Errorf("%w: %q must be a DNS-1123 label of at most %d characters", ErrInvalidK8sNamespace, ns, k8sMaxNameLen)
+	}
+	return nil
+}
+
+// validateK8sIdentity 校验实例标识不含空白或控制字符。
+//
+// 设计决策: 无法在构造时确认 identity 在副本间唯一（需要知道其他副本的配置），
+// 此处只拒绝明显错误的值，唯一性通过运行时的冲突检测暴露。
+func validateK8sIdentity(id string) error {
+	if strings.IndexFunc(id, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return fmt.Errorf("%w: %q must not contain whitespace or control characters", ErrInvalidK8sIdentity, id)
+	}
+	return nil
+}
+
 // validateK8sPrefix 校验 K8s Lease 名称前缀的合法性。
 // DNS-1123 label 要求：小写字母、数字、'-'，首字符为字母或数字。
 // 前缀长度不超过 k8sMaxNameLen - k8sMinKeyBudget，为 key 预留空间。
@@ -453,8 +624,11 @@ func validateK8sPrefix(prefix string) error {
 	return nil
 }
 
-// 确保 K8sLocker 实现了 Locker 接口
-var _ Locker = (*K8sLocker)(nil)
+// 确保 K8sLocker 实现了 Locker 和 RunRecorder 接口
+var (
+	_ Locker      = (*K8sLocker)(nil)
+	_ RunRecorder = (*K8sLocker)(nil)
+)
 
 // 确保 k8sLockHandle 实现了 LockHandle 接口
 var _ LockHandle = (*k8sLockHandle)(nil)
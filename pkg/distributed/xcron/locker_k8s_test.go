@@ -753,3 +753,95 @@ func TestK8sLocker_FullCycle(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, handle2)
 }
+
+// ============================================================================
+// Namespace / Identity Options Tests
+// ============================================================================
+
+func TestNewK8sLocker_NamespaceAndIdentityOptions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("options override struct fields", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		locker, err := NewK8sLocker(K8sLockerOptions{
+			Client:    fakeClient,
+			Namespace: "ignored",
+			Identity:  "ignored",
+		}, WithLeaseNamespace("jobs"), WithHolderIdentity("pod-a"))
+		require.NoError(t, err)
+		assert.Equal(t, "jobs", locker.Namespace())
+		assert.Equal(t, "pod-a", locker.Identity())
+
+		_, err = locker.TryLock(ctx, "report", time.Minute)
+		require.NoError(t, err)
+		lease, err := fakeClient.CoordinationV1().Leases("jobs").Get(ctx, "xcron-report", metav1.GetOptions{})
+		require.NoError(t, err, "lease must be created in the configured namespace")
+		assert.True(t, strings.HasPrefix(*lease.Spec.HolderIdentity, "pod-a:"))
+	})
+
+	t.Run("empty values are ignored", func(t *testing.T) {
+		t.Setenv("POD_NAMESPACE", "from-env")
+		t.Setenv("POD_NAME", "pod-env")
+		locker, err := NewK8sLocker(K8sLockerOptions{Client: fake.NewSimpleClientset()},
+			WithLeaseNamespace(""), WithHolderIdentity(""), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", locker.Namespace())
+		assert.Equal(t, "pod-env", locker.Identity())
+	})
+
+	t.Run("invalid namespace", func(t *testing.T) {
+		for _, ns := range []string{"Upper", "-ns", "ns-", "a_b", strings.Repeat("a", k8sMaxNameLen+1)} {
+			_, err := NewK8sLocker(K8sLockerOptions{Client: fake.NewSimpleClientset()}, WithLeaseNamespace(ns))
+			assert.ErrorIs(t, err, ErrInvalidK8sNamespace, ns)
+		}
+	})
+
+	t.Run("invalid identity", func(t *testing.T) {
+		for _, id := range []string{"pod 1", "pod\t1", "pod\n"} {
+			_, err := NewK8sLocker(K8sLockerOptions{Client: fake.NewSimpleClientset()}, WithHolderIdentity(id))
+			assert.ErrorIs(t, err, ErrInvalidK8sIdentity, id)
+		}
+	})
+}
+
+func TestK8sLocker_IdentityConflicts(t *testing.T) {
+	ctx := context.Background()
+	fakeClient := fake.NewSimpleClientset()
+	newLocker := func(id string) *K8sLocker {
+		l, err := NewK8sLocker(K8sLockerOptions{Client: fakeClient, Namespace: "default"}, WithHolderIdentity(id))
+		require.NoError(t, err)
+		return l
+	}
+
+	a := newLocker("shared")
+	b := newLocker("shared")
+	other := newLocker("shared:x")
+
+	handle, err := a.TryLock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+
+	// 本实例自己持有时不算冲突
+	h, err := a.TryLock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Zero(t, a.IdentityConflicts())
+
+	// 不同 identity（即使以 "shared:" 开头）不算冲突
+	h, err = other.TryLock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Zero(t, other.IdentityConflicts())
+
+	// 相同 identity 的其他实例持有时计入冲突
+	h, err = b.TryLock(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Equal(t, uint64(1), b.IdentityConflicts())
+
+	// 释放后 token 不再属于本实例
+	token := handle.(*k8sLockHandle).token
+	require.NoError(t, handle.Unlock(ctx))
+	_, ours := a.issued.Load(token)
+	assert.False(t, ours)
+}
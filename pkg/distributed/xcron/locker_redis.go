@@ -276,6 +276,30 @@ func (h *redisLockHandle) Key() string {
 	return h.key
 }
 
+// redisLastRunSuffix 上次执行时间记录的 key 后缀，完整 key 为 prefix + key + 后缀。
+const redisLastRunSuffix = ":last-run"
+
+// LastRun 实现 [RunRecorder]，读取 key 的上次执行时间，无记录时返回零值。
+func (l *RedisLocker) LastRun(ctx context.Context, key string) (time.Time, error) {
+	nanos, err := l.client.Get(ctx, l.prefix+key+redisLastRunSuffix).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("xcron: redis get last run failed: %w", err)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// RecordRun 实现 [RunRecorder]，以 Unix 纳秒记录执行时间。
+// 记录不设置过期时间：它描述的是任务的历史状态，与锁的生命周期无关。
+func (l *RedisLocker) RecordRun(ctx context.Context, key string, at time.Time) error {
+	if err := l.client.Set(ctx, l.prefix+key+redisLastRunSuffix, at.UnixNano(), 0).Err(); err != nil {
+		return fmt.Errorf("xcron: redis set last run failed: %w", err)
+	}
+	return nil
+}
+
 // Identity 返回当前实例标识。
 func (l *RedisLocker) Identity() string {
 	return l.identity
@@ -286,8 +310,11 @@ func (l *RedisLocker) Client() redis.UniversalClient {
 	return l.client
 }
 
-// 确保 RedisLocker 实现了 Locker 接口
-var _ Locker = (*RedisLocker)(nil)
+// 确保 RedisLocker 实现了 Locker 和 RunRecorder 接口
+var (
+	_ Locker      = (*RedisLocker)(nil)
+	_ RunRecorder = (*RedisLocker)(nil)
+)
 
 // 确保 redisLockHandle 实现了 LockHandle 接口
 var _ LockHandle = (*redisLockHandle)(nil)
@@ -30,6 +30,18 @@ func (h *mockXdlockHandle) Extend(_ context.Context) error {
 	return h.extendErr
 }
 
+func (h *mockXdlockHandle) TTL(_ context.Context) (time.Duration, error) {
+	return 0, nil
+}
+
+func (h *mockXdlockHandle) Lost() <-chan struct{} {
+	return nil
+}
+
+func (h *mockXdlockHandle) StartAutoExtend(_ time.Duration) func() {
+	return func() {}
+}
+
 func (h *mockXdlockHandle) Key() string {
 	return h.key
 }
@@ -73,6 +85,22 @@ func (f *mockXdlockFactory) Lock(_ context.Context, key string, _ ...xdlock.Mute
 	return f.handle, nil
 }
 
+func (f *mockXdlockFactory) TryLockWithWait(ctx context.Context, key string, _ time.Duration, opts ...xdlock.MutexOption) (xdlock.LockHandle, error) {
+	return f.TryLock(ctx, key, opts...)
+}
+
+func (f *mockXdlockFactory) LockMany(_ context.Context, _ []string, _ ...xdlock.MutexOption) (xdlock.LockHandle, error) {
+	return nil, nil
+}
+
+func (f *mockXdlockFactory) WatchLock(_ context.Context, _ string, _ ...xdlock.MutexOption) (<-chan xdlock.LockEvent, error) {
+	return nil, nil
+}
+
+func (f *mockXdlockFactory) Held() []xdlock.HeldLock {
+	return nil
+}
+
 func (f *mockXdlockFactory) Close(_ context.Context) error {
 	f.closeCalled = true
 	return nil
@@ -82,6 +110,10 @@ func (f *mockXdlockFactory) Health(_ context.Context) error {
 	return f.healthErr
 }
 
+func (f *mockXdlockFactory) HealthDetail(_ context.Context) ([]xdlock.NodeHealth, error) {
+	return nil, f.healthErr
+}
+
 func TestNewXdlockAdapter(t *testing.T) {
 	factory := &mockXdlockFactory{}
 	adapter, err := NewXdlockAdapter(factory)
@@ -0,0 +1,244 @@
+package xcron
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+type observerCtxKey struct{}
+
+// recordingObserver 记录 span 的开始参数与结束结果
+type recordingObserver struct {
+	mu      sync.Mutex
+	starts  []xmetrics.SpanOptions
+	results []xmetrics.Result
+}
+
+func (o *recordingObserver) Start(ctx context.Context, opts xmetrics.SpanOptions) (context.Context, xmetrics.Span) {
+	o.mu.Lock()
+	o.starts = append(o.starts, opts)
+	o.mu.Unlock()
+	return context.WithValue(ctx, observerCtxKey{}, opts.Operation), &recordingSpan{o: o}
+}
+
+type recordingSpan struct{ o *recordingObserver }
+
+func (s *recordingSpan) End(result xmetrics.Result) {
+	s.o.mu.Lock()
+	s.o.results = append(s.o.results, result)
+	s.o.mu.Unlock()
+}
+
+func (o *recordingObserver) lastResult(t *testing.T) xmetrics.Result {
+	t.Helper()
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	require.NotEmpty(t, o.results)
+	return o.results[len(o.results)-1]
+}
+
+func attrValue(attrs []xmetrics.Attr, key string) any {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+func newObservedWrapper(job Job, locker Locker, obs xmetrics.Observer) *jobWrapper {
+	opts := defaultJobOptions()
+	opts.name = "observed-job"
+	w := newJobWrapper(job, locker, nil, nil, opts)
+	w.observer = obs
+	return w
+}
+
+func TestJobWrapper_Observer(t *testing.T) {
+	jobErr := errors.New("job failed")
+	lockErr := errors.New("redis down")
+
+	held := newMockLocker()
+	_, err := held.TryLock(context.Background(), "observed-job", MinLockTTL)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		locker    Locker
+		jobErr    error
+		wantLock  string
+		wantErr   error
+		wantRunOK bool
+	}{
+		{"no lock", nil, nil, lockStateNone, nil, true},
+		{"lock acquired", newMockLocker(), nil, lockStateAcquired, nil, true},
+		{"job error", newMockLocker(), jobErr, lockStateAcquired, jobErr, true},
+		{"lock skipped", held, nil, lockStateSkipped, nil, false},
+		{"lock error", &errorLocker{err: lockErr}, nil, lockStateError, lockErr, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obs := &recordingObserver{}
+			var ran bool
+			var jobCtxValue any
+			job := JobFunc(func(ctx context.Context) error {
+				ran = true
+				jobCtxValue = ctx.Value(observerCtxKey{})
+				return tt.jobErr
+			})
+
+			newObservedWrapper(job, tt.locker, obs).Run()
+
+			assert.Equal(t, tt.wantRunOK, ran)
+			require.Len(t, obs.starts, 1)
+			assert.Equal(t, "xcron", obs.starts[0].Component)
+			assert.Equal(t, "job_run", obs.starts[0].Operation)
+			assert.Equal(t, "observed-job", attrValue(obs.starts[0].Attrs, "xcron.job"))
+
+			result := obs.lastResult(t)
+			assert.Equal(t, tt.wantLock, attrValue(result.Attrs, "xcron.lock"))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, result.Err, tt.wantErr)
+			} else {
+				assert.NoError(t, result.Err)
+			}
+			if tt.wantRunOK {
+				assert.Equal(t, "job_run", jobCtxValue, "job context should carry the span context")
+			}
+		})
+	}
+}
+
+type panicMetricsObserver struct{}
+
+func (panicMetricsObserver) Start(context.Context, xmetrics.SpanOptions) (context.Context, xmetrics.Span) {
+	panic("observer broken")
+}
+
+func TestJobWrapper_ObserverPanic(t *testing.T) {
+	var ran bool
+	job := JobFunc(func(context.Context) error {
+		ran = true
+		return nil
+	})
+	w := newObservedWrapper(job, nil, panicMetricsObserver{})
+	w.logger = newMockLogger()
+
+	assert.NotPanics(t, w.Run)
+	assert.True(t, ran)
+}
+
+func TestScheduler_WithObserver(t *testing.T) {
+	obs := &recordingObserver{}
+	s := New(WithObserver(obs))
+	cs, ok := s.(*cronScheduler)
+	require.True(t, ok)
+	assert.Same(t, obs, cs.opts.observer)
+
+	// nil 保持默认空实现
+	s = New(WithObserver(nil))
+	cs, ok = s.(*cronScheduler)
+	require.True(t, ok)
+	assert.Equal(t, xmetrics.NoopObserver{}, cs.opts.observer)
+}
+
+// jobResult 记录一次 JobResultFunc 回调
+type jobResult struct {
+	name    string
+	err     error
+	skipped bool
+}
+
+// resultRecorder 并发安全地记录回调结果
+type resultRecorder struct {
+	mu      sync.Mutex
+	results []jobResult
+}
+
+func (r *resultRecorder) record(name string, _ time.Duration, err error, skipped bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, jobResult{name: name, err: err, skipped: skipped})
+}
+
+func (r *resultRecorder) all() []jobResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]jobResult(nil), r.results...)
+}
+
+func TestScheduler_WithJobObserver(t *testing.T) {
+	jobErr := errors.New("boom")
+	locker := newMockLocker()
+	_, err := locker.TryLock(context.Background(), "busy", time.Minute)
+	require.NoError(t, err)
+
+	rec := &resultRecorder{}
+	s := New(WithLocker(locker), WithJobObserver(rec.record))
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("ok"))
+	require.NoError(t, err)
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return jobErr }, WithName("fail"))
+	require.NoError(t, err)
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { panic("kaboom") }, WithName("panic"))
+	require.NoError(t, err)
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("busy"))
+	require.NoError(t, err)
+
+	for _, e := range s.Entries() {
+		e.Job.Run()
+	}
+
+	results := make(map[string]jobResult)
+	for _, r := range rec.all() {
+		results[r.name] = r
+	}
+	require.Len(t, results, 4)
+	assert.Equal(t, jobResult{name: "ok"}, results["ok"])
+	assert.ErrorIs(t, results["fail"].err, jobErr)
+	assert.False(t, results["fail"].skipped)
+	require.Error(t, results["panic"].err)
+	assert.Contains(t, results["panic"].err.Error(), "kaboom")
+	assert.Equal(t, jobResult{name: "busy", skipped: true}, results["busy"])
+}
+
+func TestScheduler_WithJobObserver_LockError(t *testing.T) {
+	lockErr := errors.New("redis down")
+	rec := &resultRecorder{}
+	s := New(WithLocker(&errorLocker{err: lockErr}), WithJobObserver(rec.record))
+	_, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("job"))
+	require.NoError(t, err)
+
+	s.Entries()[0].Job.Run()
+
+	results := rec.all()
+	require.Len(t, results, 1)
+	assert.ErrorIs(t, results[0].err, lockErr)
+	assert.False(t, results[0].skipped)
+}
+
+func TestScheduler_WithJobObserver_Panic(t *testing.T) {
+	var ran bool
+	s := New(WithJobObserver(func(string, time.Duration, error, bool) { panic("observer broken") }),
+		WithLogger(newMockLogger()))
+	_, err := s.AddFunc("@every 1h", func(context.Context) error {
+		ran = true
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.NotPanics(t, s.Entries()[0].Job.Run)
+	assert.True(t, ran)
+
+	// nil 回调被忽略
+	cs, ok := New(WithJobObserver(nil)).(*cronScheduler)
+	require.True(t, ok)
+	assert.Nil(t, cs.opts.onResult)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/robfig/cron/v3"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
 )
 
 // ===================== Scheduler Options =====================
@@ -12,8 +14,10 @@ import (
 type schedulerOptions struct {
 	locker   Locker         // 默认分布式锁
 	logger   Logger         // 日志记录器
-	location *time.Location // 时区
-	parser   cron.Parser    // cron 表达式解析器
+	location *time.Location    // 时区
+	parser   cron.Parser       // cron 表达式解析器
+	observer xmetrics.Observer // 统一观测（span + 指标）
+	onResult JobResultFunc     // 任务触发结果回调
 }
 
 // defaultSchedulerOptions 返回默认配置
@@ -23,6 +27,7 @@ func defaultSchedulerOptions() *schedulerOptions {
 		logger:   nil, // 使用内置默认日志
 		location: time.Local,
 		parser:   cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor),
+		observer: xmetrics.NoopObserver{},
 	}
 }
 
@@ -108,6 +113,59 @@ func WithSeconds() SchedulerOption {
 	}
 }
 
+// WithObserver 设置统一观测接口（xmetrics），为每次任务触发创建 span 并上报指标。
+//
+// 与任务级 [WithTracer] 的区别：span 覆盖从获取锁到任务结束的完整过程，
+// 未抢到锁而跳过、锁服务异常的触发同样会产生 span，便于排查"任务为何没执行"。
+// span 记录以下属性：
+//   - xcron.job: 任务名
+//   - xcron.lock: 锁结果，取值 acquired / skipped / error / none（未使用锁）
+//
+// 锁服务异常与任务失败记为 error 状态；锁竞争跳过记为 ok。
+// span 所在的 context 会传递给任务和锁实现，任务内创建的 span 自动成为其子 span。
+//
+// 用法：
+//
+//	observer, _ := xmetrics.NewOTelObserver()
+//	scheduler := xcron.New(xcron.WithObserver(observer))
+func WithObserver(observer xmetrics.Observer) SchedulerOption {
+	return func(o *schedulerOptions) {
+		if observer != nil {
+			o.observer = observer
+		}
+	}
+}
+
+// WithJobObserver 设置任务触发结果回调，每次触发结束后调用一次。
+//
+// 适合在调度器层面统一接入结构化日志和指标（如"定时任务成功率"看板），
+// 无需每个任务函数自行上报。与 [WithHook] 不同，回调对所有任务生效，
+// 并且覆盖未执行的触发：锁被其他实例持有时 skipped 为 true，
+// 锁服务异常时 err 为锁错误。任务 panic 会被恢复并作为 err 传入。
+//
+// 回调在任务 goroutine 中同步执行，应保持轻量；回调自身的 panic 会被捕获并记录日志。
+// 暂停（Pause）或抖动等待期间调度器停止而放弃的触发不会回调。
+//
+// 用法：
+//
+//	scheduler := xcron.New(xcron.WithJobObserver(func(name string, d time.Duration, err error, skipped bool) {
+//	    result := "success"
+//	    switch {
+//	    case skipped:
+//	        result = "skipped"
+//	    case err != nil:
+//	        result = "failure"
+//	    }
+//	    slog.Info("cron job finished", "job", name, "result", result, "duration", d, "error", err)
+//	}))
+func WithJobObserver(fn JobResultFunc) SchedulerOption {
+	return func(o *schedulerOptions) {
+		if fn != nil {
+			o.onResult = fn
+		}
+	}
+}
+
 // ===================== Job Options =====================
 
 // MinLockTTL 是锁 TTL 的最小值。
@@ -126,6 +184,10 @@ type jobOptions struct {
 	tracer      Observer      // 链路追踪
 	immediate   bool          // 是否立即执行一次
 	hooks       []Hook        // 执行钩子
+	catchUp     CatchUpPolicy // 错过调度时的补偿策略
+	jitter      time.Duration // 每次触发前的最大随机延迟（0 表示不延迟）
+
+	skipIfRunning bool // 本实例上一次执行未结束时跳过新的触发
 }
 
 // defaultJobOptions 返回默认任务配置
@@ -345,3 +407,88 @@ func WithHooks(hooks ...Hook) JobOption {
 		}
 	}
 }
+
+// WithCatchUp 设置错过调度时间时的补偿策略。
+//
+// 默认 [SkipMissed]：错过的执行直接丢弃，等待下一个调度时间。
+// [RunOnce]：在下一个时机补偿执行一次，以下两种情况会触发：
+//   - 注册任务时发现上次执行之后已有调度时间到期（如所有副本停机、发布期间）
+//   - 执行耗时超过调度间隔，期间到期的调度因锁被持有而跳过，结束后由持锁者补偿
+//
+// 无论错过多少次调度，RunOnce 都只合并为一次执行，适合"最终执行一次即可"的任务
+// （如数据同步、报表汇总），不适合要求每个调度时间都精确执行一次的场景。
+//
+// [RunAll]：触发时机与 RunOnce 相同，但按错过的调度次数逐次补偿执行，
+// 适合每个调度周期各自产出结果的任务（如按小时切分的账单），任务需自行确定每次处理的区间。
+// 单次补偿最多执行 [MaxCatchUpRuns] 次，超出部分丢弃。
+// 某次补偿未获取到锁时停止剩余补偿，由正在执行的副本负责。
+//
+// 上次执行时间指上次成功执行的开始时间，失败的执行不更新记录，重启后会据此补偿。
+// 记录通过 Locker 后端持久化（Locker 需实现 [RunRecorder]，
+// [RedisLocker] 与 [K8sLocker] 已实现），多副本共享同一份记录。
+// 未实现 RunRecorder 的 Locker（如 [NoopLocker]）使用进程内记录，重启后无法补偿停机期间的调度。
+//
+// RunOnce / RunAll 需要任务名作为记录 key，未设置 [WithName] 时 AddFunc/AddJob 返回 [ErrMissingName]。
+// 同时设置 [WithImmediate] 时注册后总会立即执行，不再额外补偿。
+//
+// 用法：
+//
+//	scheduler.AddFunc("0 * * * *", syncTask,
+//	    xcron.WithName("hourly-sync"),
+//	    xcron.WithCatchUp(xcron.RunOnce),
+//	)
+func WithCatchUp(policy CatchUpPolicy) JobOption {
+	return func(o *jobOptions) {
+		o.catchUp = policy
+	}
+}
+
+// WithJitter 设置每次触发前的随机延迟，范围 [0, maxDelay)。
+//
+// 多副本的同一任务按同一时钟触发（如 "@every 1m"），会在同一瞬间争抢分布式锁，
+// 造成锁服务（如 Redis）的请求尖峰。随机延迟把各副本的抢锁时间打散。
+//
+// 延迟按每次触发独立随机生成（包括 WithImmediate 的立即执行与错过补偿），
+// 而不是在注册时生成一次固定偏移；TriggerNow 手动触发不延迟。
+// 延迟期间调度器 Stop 时本次触发直接放弃。maxDelay <= 0 时不生效。
+//
+// 注意：延迟计入本次触发的耗时，maxDelay 应明显小于调度间隔，
+// 否则可能与下一次触发重叠。
+//
+// 用法：
+//
+//	scheduler.AddFunc("@every 1m", task,
+//	    xcron.WithName("heartbeat"),
+//	    xcron.WithJitter(10*time.Second),
+//	)
+func WithJitter(maxDelay time.Duration) JobOption {
+	return func(o *jobOptions) {
+		if maxDelay > 0 {
+			o.jitter = maxDelay
+		}
+	}
+}
+
+// WithSkipIfRunning 本实例上一次执行尚未结束时跳过新的触发
+//
+// 分布式锁只保证跨副本互斥；执行耗时超过调度间隔时，同一副本上的下一次触发
+// 会与仍在运行的上一次并发执行（锁的 key 相同，但持锁者已结束时新触发可抢到锁，
+// 或未配置锁/无名任务时完全不互斥）。启用后由进程内标记保证同一任务在本实例上
+// 至多一个执行，被跳过的触发记录 "skipped: still running" 警告日志，计入跳过统计，
+// 观测 span 的 xcron.lock 属性与 JobStatus.LastLock 为 "running"。
+//
+// 设计决策: 与 Locker 完全独立，在获取分布式锁之前检查，被跳过的触发不会访问锁服务；
+// 无名任务、NoopLocker 同样生效。覆盖定时触发、WithImmediate、错过补偿与 TriggerNow，
+// TriggerNow 被跳过时返回 [ErrJobRunning]。
+//
+// 用法：
+//
+//	scheduler.AddFunc("@every 1m", syncInventory,
+//	    xcron.WithName("sync-inventory"),
+//	    xcron.WithSkipIfRunning(),
+//	)
+func WithSkipIfRunning() JobOption {
+	return func(o *jobOptions) {
+		o.skipIfRunning = true
+	}
+}
@@ -933,3 +933,206 @@ func TestDefaultJobOptions_ImmediateIsFalse(t *testing.T) {
 	opts := defaultJobOptions()
 	assert.False(t, opts.immediate, "immediate should be false by default")
 }
+
+func TestWithJitter(t *testing.T) {
+	t.Run("sets positive jitter", func(t *testing.T) {
+		opts := defaultJobOptions()
+		WithJitter(10 * time.Second)(opts)
+		assert.Equal(t, 10*time.Second, opts.jitter)
+	})
+
+	t.Run("ignores non-positive jitter", func(t *testing.T) {
+		opts := defaultJobOptions()
+		WithJitter(0)(opts)
+		WithJitter(-time.Second)(opts)
+		assert.Zero(t, opts.jitter)
+	})
+}
+
+func TestJobWrapper_Jitter(t *testing.T) {
+	t.Run("delays each run within max delay", func(t *testing.T) {
+		var runs atomic.Int32
+		opts := defaultJobOptions()
+		WithJitter(50 * time.Millisecond)(opts)
+		w := newJobWrapper(JobFunc(func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}), nil, nil, nil, opts)
+
+		for range 3 {
+			start := time.Now()
+			w.Run()
+			assert.Less(t, time.Since(start), time.Second)
+		}
+		assert.Equal(t, int32(3), runs.Load())
+	})
+
+	t.Run("stop aborts pending run", func(t *testing.T) {
+		var runs atomic.Int32
+		opts := defaultJobOptions()
+		WithJitter(time.Hour)(opts)
+		w := newJobWrapper(JobFunc(func(context.Context) error {
+			runs.Add(1)
+			return nil
+		}), nil, nil, nil, opts)
+		stopCtx, stop := context.WithCancel(context.Background())
+		w.stopCtx = stopCtx
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			w.Run()
+		}()
+		stop()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Run should return when scheduler stops during jitter")
+		}
+		assert.Equal(t, int32(0), runs.Load())
+	})
+
+	t.Run("trigger now is not delayed", func(t *testing.T) {
+		s := New()
+		_, err := s.AddFunc("@every 1h", func(context.Context) error { return nil },
+			WithName("jittered"), WithJitter(time.Hour))
+		require.NoError(t, err)
+
+		start := time.Now()
+		require.NoError(t, s.TriggerNow(context.Background(), "jittered"))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestWithSkipIfRunning(t *testing.T) {
+	opts := defaultJobOptions()
+	assert.False(t, opts.skipIfRunning)
+	WithSkipIfRunning()(opts)
+	assert.True(t, opts.skipIfRunning)
+}
+
+// countingLocker 统计 TryLock 调用次数
+type countingLocker struct {
+	Locker
+	calls atomic.Int32
+}
+
+func (l *countingLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (LockHandle, error) {
+	l.calls.Add(1)
+	return l.Locker.TryLock(ctx, key, ttl)
+}
+
+func TestJobWrapper_SkipIfRunning(t *testing.T) {
+	t.Run("skips overlapping run without touching the locker", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var runs atomic.Int32
+		locker := &countingLocker{Locker: newMockLocker()}
+		logger := newMockLogger()
+		stats := newStats()
+		var skipped atomic.Bool
+
+		opts := defaultJobOptions()
+		WithName("slow")(opts)
+		WithSkipIfRunning()(opts)
+		w := newJobWrapper(JobFunc(func(context.Context) error {
+			if runs.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			return nil
+		}), locker, logger, stats, opts)
+		w.onResult = func(_ string, _ time.Duration, err error, s bool) {
+			if err == nil && s {
+				skipped.Store(true)
+			}
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			w.Run()
+		}()
+		<-started
+		require.Equal(t, int32(1), locker.calls.Load())
+
+		w.Run() // 上一次仍在执行，应跳过
+		assert.Equal(t, int32(1), runs.Load())
+		assert.Equal(t, int32(1), locker.calls.Load(), "skipped run must not touch the locker")
+		assert.True(t, skipped.Load())
+		assert.Equal(t, 1, logger.getWarnCount())
+		assert.Equal(t, int64(1), stats.SkipCount())
+		var js JobStatus
+		w.state.fill(&js)
+		assert.Equal(t, lockStateRunning, js.LastLock)
+		assert.True(t, js.Running)
+
+		close(release)
+		<-done
+		w.Run() // 上一次结束后恢复执行
+		assert.Equal(t, int32(2), runs.Load())
+	})
+
+	t.Run("works without locker and name", func(t *testing.T) {
+		release := make(chan struct{})
+		var running, maxRunning atomic.Int32
+		opts := defaultJobOptions()
+		WithSkipIfRunning()(opts)
+		w := newJobWrapper(JobFunc(func(context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			<-release
+			return nil
+		}), nil, nil, nil, opts)
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Go(w.Run)
+		}
+		require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, int32(1), maxRunning.Load())
+	})
+
+	t.Run("overlap allowed by default", func(t *testing.T) {
+		release := make(chan struct{})
+		var running atomic.Int32
+		w := newJobWrapper(JobFunc(func(context.Context) error {
+			running.Add(1)
+			<-release
+			return nil
+		}), nil, nil, nil, defaultJobOptions())
+
+		var wg sync.WaitGroup
+		wg.Go(w.Run)
+		wg.Go(w.Run)
+		require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("trigger now returns ErrJobRunning", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		s := New()
+		id, err := s.AddFunc("@every 1h", func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}, WithName("busy"), WithSkipIfRunning())
+		require.NoError(t, err)
+
+		go s.Cron().Entry(id).Job.Run()
+		<-started
+		err = s.TriggerNow(context.Background(), "busy")
+		assert.ErrorIs(t, err, ErrJobRunning)
+		close(release)
+	})
+}
@@ -38,6 +38,42 @@ type Scheduler interface {
 	// 移除后任务将不再被调度，正在执行的任务不受影响。
 	Remove(id JobID)
 
+	// RemoveByName 按任务名移除任务。
+	//
+	// 语义同 Remove，移除后任务名可重新注册。任务未注册时返回 [ErrJobNotFound]。
+	RemoveByName(name string) error
+
+	// Pause 暂停指定名称任务的定时触发。
+	//
+	// 暂停的任务保留注册，仍出现在 Jobs() 中（Paused 为 true），但不再被调度执行，
+	// 也不做错过补偿；正在执行的一次会正常完成。TriggerNow 仍可手动执行。
+	// 重复暂停无效果。任务未注册时返回 [ErrJobNotFound]。
+	//
+	// 用法：
+	//
+	//	_ = scheduler.Pause("sync-orders") // 下游维护期间暂停
+	//	// ... 维护结束
+	//	_ = scheduler.Resume("sync-orders")
+	Pause(name string) error
+
+	// Resume 恢复已暂停任务的定时触发，从下一次调度时间开始执行。
+	//
+	// 暂停期间错过的调度不补偿。任务未注册时返回 [ErrJobNotFound]。
+	Resume(name string) error
+
+	// TriggerNow 在调度时间之外立即执行一次指定名称的任务（同步执行）。
+	//
+	// 与定时触发一样先获取任务的分布式锁，保证手动触发与其他副本的定时执行互斥。
+	// 任务未注册时返回 [ErrJobNotFound]；锁被其他实例持有时返回 [ErrLockHeld]，任务不执行；
+	// 锁服务异常或任务执行失败时返回对应错误。ctx 取消会中止任务执行。
+	//
+	// 用法：
+	//
+	//	if err := scheduler.TriggerNow(ctx, "nightly-report"); errors.Is(err, xcron.ErrLockHeld) {
+	//	    log.Println("report is already running on another replica")
+	//	}
+	TriggerNow(ctx context.Context, name string) error
+
 	// Start 启动调度器（非阻塞）。
 	//
 	// 调用后调度器开始按计划执行任务。
@@ -62,6 +98,19 @@ type Scheduler interface {
 	// Entries 返回所有已注册的任务。
 	Entries() []cron.Entry
 
+	// Jobs 返回通过 AddFunc/AddJob 注册的任务状态。
+	//
+	// 每项包含任务名、cron 表达式、下次调度时间，以及本实例记录的
+	// 上次执行时间、错误、锁结果和是否正在执行。结果是调用时刻的快照，
+	// 可直接序列化为 JSON 用于调试页面。
+	//
+	// 用法：
+	//
+	//	for _, job := range scheduler.Jobs() {
+	//	    fmt.Printf("%s next=%s running=%v\n", job.Name, job.NextRun, job.Running)
+	//	}
+	Jobs() []JobStatus
+
 	// Stats 返回执行统计信息。
 	//
 	// 返回的 Stats 对象是线程安全的，可以在任务执行期间安全读取。
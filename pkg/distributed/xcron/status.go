@@ -0,0 +1,113 @@
+package xcron
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// JobStatus 单个任务的运行状态快照，由 [Scheduler.Jobs] 返回。
+//
+// 聚合了底层 cron 的调度时间与 xcron 记录的锁获取、执行结果，
+// 适合用于管理端点或调试页面（如 "/cron/status"）。
+type JobStatus struct {
+	// ID 任务 ID，可用于 Remove
+	ID JobID `json:"id"`
+	// Name 任务名（WithName 设置，未设置时为空）
+	Name string `json:"name"`
+	// Schedule 注册时使用的 cron 表达式
+	Schedule string `json:"schedule"`
+	// NextRun 下次调度时间，调度器未启动时为零值
+	NextRun time.Time `json:"next_run,omitzero"`
+	// LastRun 本实例上次实际执行（获取到锁）的开始时间，未执行过时为零值
+	LastRun time.Time `json:"last_run,omitzero"`
+	// LastErr 本实例上次执行的错误信息，成功时为空
+	LastErr string `json:"last_error,omitempty"`
+	// LastTrigger 上次触发时间（无论是否获取到锁）
+	LastTrigger time.Time `json:"last_trigger,omitzero"`
+	// LastLock 上次触发的锁结果：none（未使用锁）、acquired、skipped（其他实例持有）、error（锁服务异常）、
+	// running（WithSkipIfRunning 下本实例上一次执行未结束）
+	LastLock string `json:"last_lock,omitempty"`
+	// Running 本实例是否正在执行该任务
+	Running bool `json:"running"`
+	// Paused 任务是否已通过 Pause 暂停定时触发
+	Paused bool `json:"paused"`
+}
+
+// jobState 记录单个任务在本实例上的执行状态。
+//
+// 设计决策: 以指针挂在 jobWrapper 上，WithImmediate 与补偿执行使用的浅拷贝共享同一份状态；
+// 不复用 JobStats，因为无名任务不产生 JobStats，而状态查询需要覆盖所有任务。
+type jobState struct {
+	running atomic.Int32 // 并发执行数（立即执行与定时触发可能重叠）
+	paused  atomic.Bool  // 是否暂停定时触发（Pause/Resume）
+	active  atomic.Bool  // WithSkipIfRunning 的进程内互斥标记
+
+	mu          sync.RWMutex
+	lastTrigger time.Time
+	lastLock    string
+	lastRun     time.Time
+	lastErr     error
+}
+
+// recordTrigger 记录一次触发的锁结果。
+func (st *jobState) recordTrigger(at time.Time, lockState string) {
+	st.mu.Lock()
+	st.lastTrigger = at
+	st.lastLock = lockState
+	st.mu.Unlock()
+}
+
+// begin 标记任务开始执行。
+func (st *jobState) begin(at time.Time) {
+	st.running.Add(1)
+	st.mu.Lock()
+	st.lastRun = at
+	st.mu.Unlock()
+}
+
+// end 标记任务执行结束并记录结果。
+func (st *jobState) end(err error) {
+	st.mu.Lock()
+	st.lastErr = err
+	st.mu.Unlock()
+	st.running.Add(-1)
+}
+
+// fill 将执行状态写入 JobStatus。
+func (st *jobState) fill(js *JobStatus) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	js.LastTrigger = st.lastTrigger
+	js.LastLock = st.lastLock
+	js.LastRun = st.lastRun
+	if st.lastErr != nil {
+		js.LastErr = st.lastErr.Error()
+	}
+	js.Running = st.running.Load() > 0
+	js.Paused = st.paused.Load()
+}
+
+// Jobs 返回通过 AddFunc/AddJob 注册的任务状态，顺序与 Entries 一致。
+// 通过 Cron() 直接添加的原生任务不在结果中。
+func (s *cronScheduler) Jobs() []JobStatus {
+	entries := s.cron.Entries()
+	result := make([]JobStatus, 0, len(entries))
+	for _, e := range entries {
+		w, ok := e.Job.(*jobWrapper)
+		if !ok {
+			continue
+		}
+		js := JobStatus{
+			ID:       e.ID,
+			Name:     w.opts.name,
+			Schedule: w.spec,
+			NextRun:  e.Next,
+		}
+		if w.state != nil {
+			w.state.fill(&js)
+		}
+		result = append(result, js)
+	}
+	return result
+}
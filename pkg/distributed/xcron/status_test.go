@@ -0,0 +1,195 @@
+package xcron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_Jobs_Registered(t *testing.T) {
+	s := New()
+	id, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("report"))
+	require.NoError(t, err)
+	_, err = s.AddFunc("*/5 * * * *", func(context.Context) error { return nil })
+	require.NoError(t, err)
+	// 通过 Cron() 添加的原生任务不在结果中
+	_, err = s.Cron().AddFunc("@every 1h", func() {})
+	require.NoError(t, err)
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, id, jobs[0].ID)
+	assert.Equal(t, "report", jobs[0].Name)
+	assert.Equal(t, "@every 1h", jobs[0].Schedule)
+	assert.True(t, jobs[0].NextRun.IsZero(), "next run is unknown before Start")
+	assert.True(t, jobs[0].LastRun.IsZero())
+	assert.Empty(t, jobs[0].LastLock)
+	assert.False(t, jobs[0].Running)
+	assert.Equal(t, "*/5 * * * *", jobs[1].Schedule)
+
+	s.Start()
+	defer s.Stop()
+	for _, job := range s.Jobs() {
+		assert.False(t, job.NextRun.IsZero(), "job %q should have next run after Start", job.Name)
+	}
+}
+
+func TestScheduler_Jobs_ExecutionOutcome(t *testing.T) {
+	jobErr := errors.New("boom")
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s := New()
+	_, err := s.AddFunc("@every 1h", func(context.Context) error {
+		close(started)
+		<-release
+		return jobErr
+	}, WithName("flaky"), WithImmediate())
+	require.NoError(t, err)
+
+	<-started
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.True(t, jobs[0].Running)
+	assert.False(t, jobs[0].LastRun.IsZero())
+
+	close(release)
+	<-s.Stop().Done()
+
+	jobs = s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.False(t, jobs[0].Running)
+	assert.Equal(t, "boom", jobs[0].LastErr)
+	assert.Equal(t, lockStateAcquired, jobs[0].LastLock)
+	assert.Equal(t, jobs[0].LastRun, jobs[0].LastTrigger)
+}
+
+func TestScheduler_Jobs_LockSkipped(t *testing.T) {
+	locker := newMockLocker()
+	_, err := locker.TryLock(context.Background(), "busy", time.Minute)
+	require.NoError(t, err)
+
+	s := New(WithLocker(locker))
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return nil },
+		WithName("busy"), WithImmediate())
+	require.NoError(t, err)
+	<-s.Stop().Done()
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, lockStateSkipped, jobs[0].LastLock)
+	assert.False(t, jobs[0].LastTrigger.IsZero())
+	assert.True(t, jobs[0].LastRun.IsZero(), "skipped trigger is not an execution")
+}
+
+func TestScheduler_Jobs_AfterRemove(t *testing.T) {
+	s := New()
+	id, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("tmp"))
+	require.NoError(t, err)
+	s.Remove(id)
+	assert.Empty(t, s.Jobs())
+}
+
+func TestJobStatus_JSON(t *testing.T) {
+	data, err := json.Marshal(JobStatus{ID: cron.EntryID(1), Name: "report", Schedule: "@daily", LastErr: "boom"})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"name":"report"`)
+	assert.Contains(t, string(data), `"last_error":"boom"`)
+	assert.Contains(t, string(data), `"running":false`)
+	assert.NotContains(t, string(data), "next_run", "zero times are omitted")
+	assert.NotContains(t, string(data), "last_run")
+	assert.NotContains(t, string(data), "last_trigger")
+
+	data, err = json.Marshal(JobStatus{NextRun: time.Unix(0, 0).UTC()})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"next_run":"1970-01-01T00:00:00Z"`)
+}
+
+func TestScheduler_PauseResume(t *testing.T) {
+	var runs atomic.Int32
+	s := New()
+	id, err := s.AddFunc("@every 1h", func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}, WithName("sync"))
+	require.NoError(t, err)
+	trigger := s.Cron().Entry(id).Job.Run // 模拟定时触发
+
+	require.NoError(t, s.Pause("sync"))
+	require.NoError(t, s.Pause("sync"), "pause is idempotent")
+	trigger()
+	assert.Equal(t, int32(0), runs.Load(), "paused job must not run on schedule")
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1, "paused job stays registered")
+	assert.True(t, jobs[0].Paused)
+	assert.True(t, jobs[0].LastTrigger.IsZero())
+
+	// 暂停期间仍可手动触发
+	require.NoError(t, s.TriggerNow(context.Background(), "sync"))
+	assert.Equal(t, int32(1), runs.Load())
+
+	require.NoError(t, s.Resume("sync"))
+	trigger()
+	assert.Equal(t, int32(2), runs.Load())
+	assert.False(t, s.Jobs()[0].Paused)
+
+	assert.ErrorIs(t, s.Pause("missing"), ErrJobNotFound)
+	assert.ErrorIs(t, s.Resume("missing"), ErrJobNotFound)
+}
+
+func TestScheduler_PauseDuringRun(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var runs atomic.Int32
+	s := New()
+	id, err := s.AddFunc("@every 1h", func(context.Context) error {
+		if runs.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return nil
+	}, WithName("long"))
+	require.NoError(t, err)
+	trigger := s.Cron().Entry(id).Job.Run
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		trigger()
+	}()
+	<-started
+	require.NoError(t, s.Pause("long"))
+	assert.True(t, s.Jobs()[0].Running, "current execution continues after pause")
+
+	close(release)
+	<-done
+	jobs := s.Jobs()
+	assert.False(t, jobs[0].Running)
+	assert.Empty(t, jobs[0].LastErr, "current execution finishes normally")
+
+	trigger()
+	assert.Equal(t, int32(1), runs.Load(), "future triggers are skipped")
+}
+
+func TestScheduler_RemoveByName(t *testing.T) {
+	s := New()
+	_, err := s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("tmp"))
+	require.NoError(t, err)
+
+	require.NoError(t, s.RemoveByName("tmp"))
+	assert.Empty(t, s.Jobs())
+	assert.ErrorIs(t, s.RemoveByName("tmp"), ErrJobNotFound)
+	assert.ErrorIs(t, s.Pause("tmp"), ErrJobNotFound)
+
+	// 名称释放后可重新注册
+	_, err = s.AddFunc("@every 1h", func(context.Context) error { return nil }, WithName("tmp"))
+	require.NoError(t, err)
+}
@@ -170,3 +170,12 @@ func (h HookFunc) AfterJob(ctx context.Context, name string, duration time.Durat
 		h.After(ctx, name, duration, err)
 	}
 }
+
+// JobResultFunc 任务触发结果回调，由 [WithJobObserver] 设置。
+//
+// 参数：
+//   - name: 任务名（未设置 WithName 时为空）
+//   - duration: 本次触发耗时（含获取锁）
+//   - err: 任务执行错误或锁服务异常；任务 panic 时为包含 panic 信息的错误
+//   - skipped: 锁被其他实例持有或（WithSkipIfRunning 下）本实例上一次执行未结束、本次未执行时为 true（此时 err 为 nil）
+type JobResultFunc func(name string, duration time.Duration, err error, skipped bool)
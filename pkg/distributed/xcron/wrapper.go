@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math/rand/v2"
 	"sync"
 	"time"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
 )
 
 // jobWrapper 包装原始任务，添加锁、超时、重试等能力。
@@ -17,6 +20,14 @@ type jobWrapper struct {
 	logger  Logger
 	stats   *Stats          // 执行统计
 	baseCtx context.Context // 可选: 立即执行任务使用的可取消上下文
+	stopCtx context.Context // 可选: 调度器停止时取消，用于中止抖动等待
+
+	observer xmetrics.Observer // 可选: 调度器级统一观测，nil 时不观测
+	onResult JobResultFunc     // 可选: 调度器级触发结果回调
+	catchUp  *catchUpState     // 可选: RunOnce / RunAll 补偿策略状态，nil 表示 SkipMissed
+
+	spec  string    // 注册时的 cron 表达式，用于 Jobs() 展示
+	state *jobState // 本实例执行状态，立即执行等浅拷贝共享同一份
 }
 
 // renewHandle 保存单次任务执行的锁续期状态
@@ -36,16 +47,105 @@ func newJobWrapper(job Job, locker Locker, logger Logger, stats *Stats, opts *jo
 		locker: locker,
 		logger: logger,
 		stats:  stats,
+		state:  &jobState{},
 	}
 }
 
 // Run 实现 cron.Job 接口
 func (w *jobWrapper) Run() {
-	ctx := context.Background()
+	if w.state.paused.Load() {
+		w.logDebug(w.runContext(), "job paused, skipping", "job", w.opts.name)
+		return
+	}
+	if !w.waitJitter() {
+		return
+	}
+	startTime, lockState, _ := w.runOnce()
+	executed := lockState == lockStateAcquired || lockState == lockStateNone
+
+	// 设计决策: 执行耗时超过调度间隔时，期间到期的调度因锁被持有而在各副本上跳过。
+	// 由持锁执行者在结束后补偿：RunOnce 多次错过合并为一次，RunAll 按错过次数逐次执行；
+	// 补偿执行本身不再触发补偿，避免持续超时的任务无限循环。
+	// 执行期间被暂停时同样不补偿。
+	if !executed || w.catchUp == nil || w.state.paused.Load() {
+		return
+	}
+	n, truncated := w.catchUp.pendingRuns(startTime, time.Now())
+	if n == 0 {
+		return
+	}
+	ctx := w.runContext()
+	if ctx.Err() != nil {
+		return
+	}
+	w.logWarn(ctx, "scheduled run missed during execution, catching up",
+		"job", w.opts.name, "policy", w.catchUp.policy.String(), "runs", n)
+	if truncated {
+		w.logWarn(ctx, "too many missed runs, dropping the rest",
+			"job", w.opts.name, "max_catch_up_runs", MaxCatchUpRuns)
+	}
+	w.runMissed(n)
+}
+
+// waitJitter 等待 [0, jitter) 的随机延迟，返回 false 表示等待期间上下文取消或调度器停止。
+func (w *jobWrapper) waitJitter() bool {
+	if w.opts.jitter <= 0 {
+		return true
+	}
+	ctx := w.runContext()
+	delay := rand.N(w.opts.jitter)
+	w.logDebug(ctx, "delaying run by jitter", "job", w.opts.name, "delay", delay)
+
+	var stopped <-chan struct{}
+	if w.stopCtx != nil {
+		stopped = w.stopCtx.Done()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-stopped:
+		return false
+	}
+}
+
+// runContext 返回任务执行的根上下文。
+func (w *jobWrapper) runContext() context.Context {
 	if w.baseCtx != nil {
-		ctx = w.baseCtx
+		return w.baseCtx
+	}
+	return context.Background()
+}
+
+// runOnce 执行一次完整的触发流程（获取锁、执行、钩子、统计）。
+// 返回本次开始时间、锁结果（acquired/none 表示任务实际执行），
+// 以及锁服务异常或任务执行的错误（锁竞争或本地仍在运行而跳过时为 nil）。
+func (w *jobWrapper) runOnce() (startTime time.Time, lockState string, err error) {
+	ctx := w.runContext()
+	startTime = time.Now()
+
+	// 0. 统一观测：span 覆盖锁获取与执行的全过程，结果在返回时统一记录
+	ctx, obsSpan := w.startObserve(ctx)
+	lockState = lockStateNone
+	defer func() { w.endObserve(obsSpan, lockState, err) }()
+	defer func() { w.state.recordTrigger(startTime, lockState) }()
+	defer func() { w.notifyResult(startTime, lockState, err) }()
+
+	// 本实例上一次执行未结束时跳过（早于分布式锁，不访问锁服务）
+	if w.opts.skipIfRunning {
+		if !w.state.active.CompareAndSwap(false, true) {
+			lockState = lockStateRunning
+			w.logWarn(ctx, "skipped: still running", "job", w.opts.name)
+			if w.stats != nil {
+				w.stats.recordSkip(w.opts.name)
+			}
+			return startTime, lockState, nil
+		}
+		defer w.state.active.Store(false)
 	}
-	startTime := time.Now()
 
 	// 创建可取消的任务上下文，用于续期失败时中止任务
 	taskCtx, taskCancel := context.WithCancel(ctx)
@@ -55,6 +155,11 @@ func (w *jobWrapper) Run() {
 	rh, lockErr := w.tryAcquireLock(taskCtx, taskCancel)
 	if rh == nil && w.opts.name != "" && w.locker != nil {
 		// 需要锁但未获取到
+		if lockErr != nil {
+			lockState, err = lockStateError, lockErr
+		} else {
+			lockState = lockStateSkipped
+		}
 		if w.stats != nil {
 			if lockErr != nil {
 				// 设计决策: 锁服务异常独立统计（recordLockError），不计入 recordExecution。
@@ -65,8 +170,13 @@ func (w *jobWrapper) Run() {
 				w.stats.recordSkip(w.opts.name)
 			}
 		}
-		return
+		return startTime, lockState, err
 	}
+	if rh != nil {
+		lockState = lockStateAcquired
+	}
+	w.state.begin(startTime)
+	defer func() { w.state.end(err) }()
 
 	// 2. 超时控制
 	taskCtx, cancel := w.applyTimeout(taskCtx)
@@ -87,9 +197,15 @@ func (w *jobWrapper) Run() {
 	w.logDebug(taskCtx, "job starting", "job", w.opts.name)
 
 	// 6. 执行任务（可能带重试）
-	err := w.executeJob(taskCtx, rh)
+	err = w.executeJob(taskCtx, rh)
 	duration := time.Since(startTime)
 
+	// 设计决策: 仅记录成功的执行，失败的执行不推进上次执行时间，
+	// 重启后的补偿判断以上次成功执行为准；使用外层 ctx，避免任务超时上下文耗尽导致记录失败。
+	if err == nil && w.catchUp != nil {
+		w.recordRun(ctx, startTime)
+	}
+
 	// 7. 执行钩子 AfterJob（逆序，类似 defer），每个钩子独立 panic 保护
 	w.runAfterHooks(taskCtx, duration, err)
 
@@ -100,6 +216,65 @@ func (w *jobWrapper) Run() {
 
 	// 9. 记录日志结果
 	w.logResult(taskCtx, span, duration, err)
+	return startTime, lockState, err
+}
+
+// notifyResult 调用触发结果回调，回调 panic 会被捕获并记录日志。
+func (w *jobWrapper) notifyResult(startTime time.Time, lockState string, err error) {
+	if w.onResult == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			w.logError(context.Background(), "job observer panicked",
+				"job", w.opts.name, "panic", r)
+		}
+	}()
+	skipped := lockState == lockStateSkipped || lockState == lockStateRunning
+	w.onResult(w.opts.name, time.Since(startTime), err, skipped)
+}
+
+// 锁结果，作为观测 span 的 xcron.lock 属性值。
+const (
+	lockStateNone     = "none"     // 未使用锁
+	lockStateAcquired = "acquired" // 获取成功
+	lockStateSkipped  = "skipped"  // 锁被其他实例持有，本次跳过
+	lockStateError    = "error"    // 锁服务异常
+	lockStateRunning  = "running"  // WithSkipIfRunning: 本实例上一次执行未结束，本次跳过
+)
+
+// startObserve 开始调度器级观测 span。未配置 observer 时返回空 span。
+// observer 实现 panic 时同样退化为空 span，不影响锁获取与任务执行。
+func (w *jobWrapper) startObserve(ctx context.Context) (resultCtx context.Context, resultSpan xmetrics.Span) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logError(ctx, "observer.Start panicked",
+				"job", w.opts.name, "panic", r)
+			resultCtx, resultSpan = ctx, xmetrics.NoopSpan{}
+		}
+	}()
+	return xmetrics.Start(ctx, w.observer, xmetrics.SpanOptions{
+		Component: "xcron",
+		Operation: "job_run",
+		Kind:      xmetrics.KindInternal,
+		Attrs: []xmetrics.Attr{
+			xmetrics.String("xcron.job", w.opts.name),
+		},
+	})
+}
+
+// endObserve 结束观测 span，记录锁结果与执行错误。
+func (w *jobWrapper) endObserve(span xmetrics.Span, lockState string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.logError(context.Background(), "observer span.End panicked",
+				"job", w.opts.name, "panic", r)
+		}
+	}()
+	span.End(xmetrics.Result{
+		Err:   err,
+		Attrs: []xmetrics.Attr{xmetrics.String("xcron.lock", lockState)},
+	})
 }
 
 // tryAcquireLock 尝试获取分布式锁。
@@ -0,0 +1,123 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// LockHandle.StartAutoExtend 实现
+// =============================================================================
+
+// autoExtender 管理 handle 的后台自动续期，零值可用。
+//
+// 设计决策: 与 xsemaphore Permit 一致采用单次启动策略——运行中重复启动返回同一 stop 函数，
+// 避免多个 goroutine 并发续期。stop 会等待进行中的续期返回，Unlock 前调用 halt，
+// 从而保证 Unlock 之后不会再有续期请求到达后端。
+type autoExtender struct {
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+	halted bool
+}
+
+// start 以 interval 周期性调用 extend，返回停止函数。
+// interval <= 0 或已 halt 时返回空操作。
+func (a *autoExtender) start(interval time.Duration, extend func(context.Context) error) func() {
+	if interval <= 0 {
+		return func() {}
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.halted {
+		return func() {}
+	}
+	if a.cancel != nil {
+		select {
+		case <-a.done:
+			// 上一轮已因锁丢失自行退出，允许重新启动
+		default:
+			return a.stop
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	a.cancel, a.done = cancel, done
+	go runAutoExtend(ctx, interval, extend, done)
+	return a.stop
+}
+
+// stop 停止续期并等待进行中的续期返回，重复调用无副作用。
+func (a *autoExtender) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopLocked()
+}
+
+// halt 停止续期且不再允许启动，由 Unlock 在释放锁之前调用。
+func (a *autoExtender) halt() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.halted = true
+	a.stopLocked()
+}
+
+func (a *autoExtender) stopLocked() {
+	if a.cancel == nil {
+		return
+	}
+	a.cancel()
+	<-a.done
+	a.cancel, a.done = nil, nil
+}
+
+// runAutoExtend 自动续期循环，锁确认丢失或 ctx 取消时退出。
+//
+// 单次续期超时为 interval：超过一个周期仍未完成的续期已无意义，交由下一周期重试。
+// 续期的临时失败（网络错误、ErrExtendFailed）不终止循环，锁是否丢失由 Lost 通知。
+func runAutoExtend(ctx context.Context, interval time.Duration, extend func(context.Context) error, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ectx, cancel := context.WithTimeout(ctx, interval)
+		err := extend(ectx)
+		cancel()
+		if errors.Is(err, ErrNotLocked) || errors.Is(err, ErrSessionExpired) {
+			return
+		}
+	}
+}
+
+// StartAutoExtend 启动后台自动续期。
+func (h *redisLockHandle) StartAutoExtend(interval time.Duration) func() {
+	return h.autoExtend.start(interval, h.Extend)
+}
+
+// StartAutoExtend 启动后台自动续期。
+func (h *reentrantLockHandle) StartAutoExtend(interval time.Duration) func() {
+	return h.autoExtend.start(interval, h.Extend)
+}
+
+// StartAutoExtend 启动后台自动续期。
+func (h *rwLockHandle) StartAutoExtend(interval time.Duration) func() {
+	return h.autoExtend.start(interval, h.Extend)
+}
+
+// StartAutoExtend 启动后台自动续期，每个周期续期全部 key。
+func (h *multiLockHandle) StartAutoExtend(interval time.Duration) func() {
+	return h.autoExtend.start(interval, h.Extend)
+}
+
+// StartAutoExtend etcd 锁由 Session 自动续期，返回空操作。
+func (h *etcdLockHandle) StartAutoExtend(_ time.Duration) func() {
+	return func() {}
+}
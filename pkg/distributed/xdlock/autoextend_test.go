@@ -0,0 +1,180 @@
+package xdlock
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// StartAutoExtend 测试
+// =============================================================================
+
+func TestAutoExtender_StopWaitsAndHalt(t *testing.T) {
+	var a autoExtender
+	var calls atomic.Int32
+	extend := func(ctx context.Context) error {
+		calls.Add(1)
+		<-ctx.Done() // 模拟慢续期，直到被 stop 取消
+		return ctx.Err()
+	}
+
+	stop := a.start(5*time.Millisecond, extend)
+	require.Eventually(t, func() bool { return calls.Load() > 0 }, time.Second, time.Millisecond)
+	stop()
+	n := calls.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, n, calls.Load(), "stop 返回后不应再续期")
+	stop() // 幂等
+
+	// stop 后可以重新启动；halt 后不可以
+	stop = a.start(5*time.Millisecond, extend)
+	require.Eventually(t, func() bool { return calls.Load() > n }, time.Second, time.Millisecond)
+	a.halt()
+	n = calls.Load()
+	a.start(5*time.Millisecond, extend)()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, n, calls.Load(), "halt 后不应再续期")
+	stop()
+}
+
+func TestAutoExtender_SingleStartAndSelfExit(t *testing.T) {
+	var a autoExtender
+	var calls atomic.Int32
+	extend := func(context.Context) error {
+		calls.Add(1)
+		return ErrNotLocked
+	}
+
+	assert.NotNil(t, a.start(0, extend), "interval <= 0 返回空操作")
+
+	a.start(time.Hour, extend)
+	a.mu.Lock()
+	done := a.done
+	a.mu.Unlock()
+	a.start(time.Hour, extend) // 运行中重复启动不创建新的循环
+	a.mu.Lock()
+	assert.Equal(t, done, a.done)
+	a.mu.Unlock()
+	a.stop()
+
+	// 锁丢失后循环自行退出，之后允许重新启动
+	a.start(time.Millisecond, extend)
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		select {
+		case <-a.done:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), calls.Load())
+	a.start(time.Millisecond, extend)
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+	a.halt()
+}
+
+func TestRedisLockHandle_StartAutoExtend(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "job", WithExpiry(2*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	stop := h.StartAutoExtend(10 * time.Millisecond)
+	mr.FastForward(1500 * time.Millisecond)
+	require.Eventually(t, func() bool { return mr.TTL("lock:job") > time.Second }, time.Second, 5*time.Millisecond,
+		"自动续期应将 TTL 恢复到 Expiry")
+	stop()
+
+	// stop 后不再续期
+	mr.FastForward(1500 * time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, mr.TTL("lock:job"), time.Second)
+	require.NoError(t, h.Unlock(ctx))
+}
+
+func TestRedisLockHandle_StartAutoExtend_StopsOnUnlock(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "job", WithExpiry(2*time.Second))
+	require.NoError(t, err)
+	stop := h.StartAutoExtend(time.Millisecond)
+	defer stop()
+	require.NoError(t, h.Unlock(ctx))
+	assert.False(t, mr.Exists("lock:job"))
+
+	// Unlock 后其他持有者获取同名锁，已停止的续期不会触碰它
+	other, err := f.TryLock(ctx, "job", WithExpiry(2*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, other)
+	mr.FastForward(1500 * time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	assert.LessOrEqual(t, mr.TTL("lock:job"), time.Second)
+
+	// Unlock 之后再启动为空操作
+	h.StartAutoExtend(time.Millisecond)()
+	require.NoError(t, other.Unlock(ctx))
+}
+
+func TestRedisLockHandle_StartAutoExtend_Variants(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	t.Run("可重入锁", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "re", WithReentrant(), WithExpiry(2*time.Second))
+		require.NoError(t, err)
+		stop := h.StartAutoExtend(10 * time.Millisecond)
+		defer stop()
+		mr.FastForward(1500 * time.Millisecond)
+		require.Eventually(t, func() bool { return mr.TTL("lock:re") > time.Second }, time.Second, 5*time.Millisecond)
+		require.NoError(t, h.Unlock(ctx))
+	})
+
+	t.Run("多 key 锁", func(t *testing.T) {
+		h, err := f.LockMany(ctx, []string{"m1", "m2"}, WithExpiry(2*time.Second))
+		require.NoError(t, err)
+		stop := h.StartAutoExtend(10 * time.Millisecond)
+		defer stop()
+		mr.FastForward(1500 * time.Millisecond)
+		require.Eventually(t, func() bool {
+			return mr.TTL("lock:m1") > time.Second && mr.TTL("lock:m2") > time.Second
+		}, time.Second, 5*time.Millisecond)
+		require.NoError(t, h.Unlock(ctx))
+	})
+
+	t.Run("读写锁", func(t *testing.T) {
+		rw, err := NewRWFactory(client)
+		require.NoError(t, err)
+		h, err := rw.TryLock(ctx, "rw", WithExpiry(2*time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, h)
+		stop := h.StartAutoExtend(10 * time.Millisecond)
+		defer stop()
+		before := h.(*rwLockHandle).expiresAt.Load()
+		require.Eventually(t, func() bool { return h.(*rwLockHandle).expiresAt.Load() > before },
+			time.Second, 5*time.Millisecond)
+		require.NoError(t, h.Unlock(ctx))
+	})
+}
+
+func TestEtcdLockHandle_StartAutoExtend_Noop(t *testing.T) {
+	h := &etcdLockHandle{}
+	stop := h.StartAutoExtend(time.Millisecond)
+	require.NotNil(t, stop)
+	stop()
+}
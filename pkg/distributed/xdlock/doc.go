@@ -15,8 +15,9 @@
 // # 核心概念
 //
 //   - Factory: 锁工厂，管理连接并提供 TryLock/Lock 操作
-//   - LockHandle: 单次锁获取的句柄，提供 Unlock/Extend/Key 操作
+//   - LockHandle: 单次锁获取的句柄，提供 Unlock/Extend/TTL/Lost/Key 操作
 //   - MutexOption: 锁实例的配置选项
+//   - LockEvent: WatchLock 推送的锁状态变化（获取/释放）
 //
 // # etcd 后端
 //
@@ -36,6 +37,9 @@
 //	|------|------|-----------------|
 //	| 续期方式 | 自动（Session） | 手动（Extend） |
 //	| Extend() | 检查 Session 健康状态和本地解锁标记（不延长 TTL） | 延长锁 TTL |
+//	| TTL() | Lease 剩余 TTL（秒级） | 锁 key 的 PTTL（Redlock 取最小值） |
+//	| Lost() | Session.Done() 触发，即时 | 后台轮询，默认 1s 间隔 |
+//	| StartAutoExtend() | 空操作（Session 已自动续期） | 后台周期性 Extend |
 //	| 多节点支持 | 原生（etcd 集群） | Redlock 算法 |
 //	| 锁释放 | 立即生效 | 立即生效 |
 //	| MutexOption | 仅 KeyPrefix 生效 | 全部生效 |
@@ -55,6 +59,74 @@
 // handle.Unlock(ctx) 中 ctx 已过期），Unlock 会自动切换到 context.Background() 派生的
 // 5 秒超时上下文，确保解锁操作尽力完成，避免锁残留到 TTL/Lease 到期。
 //
+// # 锁事件监听
+//
+// Factory.WatchLock 让等待者事件驱动地响应锁释放，而非轮询 TryLock：
+//
+//   - etcd：基于 Watch 监听锁前缀，Watch 中断时自动重建
+//   - Redis：基于 keyspace notification（需服务端开启 notify-keyspace-events "Kg$x"）
+//
+// 后端通知仅作为唤醒信号，每次唤醒都重新探测锁的真实状态，只在状态变化时推送事件；
+// 另有兜底轮询（WithWatchPollInterval，默认 1s），通知丢失或未开启时最多延迟一个周期。
+// 收到 Released 后仍需 TryLock 竞争，事件不代表获取权。
+//
+// # 有界等待
+//
+// TryLock 立即返回，Lock 按 Tries/RetryDelay 重试（Redis）或阻塞到 ctx 结束（etcd）。
+// TryLockWithWait 介于两者之间：在 maxWait 内按 xretry.BackoffPolicy 重复 TryLock，
+// 超时仍未获取返回 (nil, nil)，后端异常立即返回错误，两者可明确区分：
+//
+//	handle, err := factory.TryLockWithWait(ctx, "job", 3*time.Second)
+//	if err != nil {
+//	    return err // 锁服务异常
+//	}
+//	if handle == nil {
+//	    return nil // 3s 内锁一直被占用
+//	}
+//
+// 默认退避为指数退避（50ms 起，上限 1s），可通过 WithWaitBackoff 替换。
+//
+// # 多 key 锁
+//
+// Factory.LockMany 同时获取多个 key 的锁（如两个账户间转账）：key 按字典序排序、去重后依次 Lock，
+// 任一 key 失败时逆序释放已获取的锁再返回，返回的 handle 的 Unlock 释放全部 key。
+//
+//	handle, err := factory.LockMany(ctx, []string{"account:" + from, "account:" + to})
+//
+// 避免死锁依赖一致的获取顺序：同一组 key 的所有获取方都应通过 LockMany 获取，
+// 与按各自顺序逐个调用 Lock 的代码混用时仍可能相互等待，直到重试耗尽或 TTL 到期。
+//
+// # 持有锁查询
+//
+// Factory.Held（以及 RWFactory.Held）列出当前进程通过该工厂获取且尚未 Unlock 的锁，
+// 仅读取本地记录，不访问后端。HeldLock 与 xdbg.LockInfo 字段一致，可直接转换后
+// 通过 xdbg.WithLockProvider 接入 locks 调试命令。
+//
+// # 锁丢失通知
+//
+// LockHandle.Lost 返回的 channel 在锁丢失时关闭，长任务可 select 该 channel
+// 及时中止已无权继续的工作，而不必等到下一次 Extend 才发现。
+// Redis 后端在首次调用 Lost 时启动后台 watcher，按 WithLostPollInterval（默认 1 秒）
+// 轮询锁 key 的 value 与 PTTL，因此感知延迟最多一个轮询周期；未调用 Lost 不产生额外开销。
+//
+// # 健康检查
+//
+// Factory.Health 任一节点不可达即返回错误，适合作为简单的存活探针。
+// Factory.HealthDetail 探测全部节点并逐个返回 NodeHealth（Addr/Healthy/Err），
+// Redlock 多节点部署可据此在健康节点数跌破多数派之前发现仲裁降级并告警。
+//
+// # 自动续期
+//
+// Redis 锁需要手动 Extend。运行时间不确定的长任务可使用 LockHandle.StartAutoExtend
+// 在后台周期性续期（与 xsemaphore Permit.StartAutoExtend 用法一致），获得与 etcd
+// Session 自动续期相同的使用体验：
+//
+//	stop := handle.StartAutoExtend(expiry / 3)
+//	defer stop()
+//
+// 续期持续到调用 stop、Unlock 或锁确认丢失为止。Unlock 在释放锁之前停止续期并等待
+// 进行中的续期返回，不会出现 Unlock 之后仍有续期请求的情况。
+//
 // # Key 校验
 //
 // 锁 key 必须满足：非空（去除空白后不为空）、长度不超过 512 字节。
@@ -62,7 +134,7 @@
 //
 // # 锁重入
 //
-// 设计决策: xdlock 的锁是非重入的。
+// 设计决策: xdlock 的锁默认是非重入的，Redis 单节点工厂可通过 WithReentrant 显式开启重入。
 //
 // etcd 后端：同一 Factory（同一 Session）对同一 key 只能持有一个 LockHandle。
 // etcd concurrency.Mutex 的所有权标识由 Session Lease 决定，同一 Session 对同一
@@ -73,6 +145,19 @@
 // Redis 后端：每个 LockHandle 通过随机值（redsync 默认）实现独立所有权，
 // 同一 Factory 可对同一 key 创建多个独立 handle（前提是锁未被占用）。
 //
+// Redis 可重入模式：锁 key 存为 HASH（owner token → 持有计数），由 Lua 脚本原子维护。
+// 重入以 owner token 为单位而非 goroutine——相同 token（WithOwnerToken）的获取计数加一，
+// 每个返回的 handle 各自 Unlock 一次，计数归零才释放锁 key：
+//
+//	opts := []xdlock.MutexOption{xdlock.WithReentrant(), xdlock.WithOwnerToken(requestID)}
+//	outer, _ := factory.Lock(ctx, "order:42", opts...)
+//	defer outer.Unlock(ctx)
+//	inner, _ := factory.Lock(ctx, "order:42", opts...) // 同一调用链内重入，不会自我阻塞
+//	defer inner.Unlock(ctx)
+//
+// Unlock 次数少于获取次数时锁会一直被持有直到 TTL 到期；多节点（Redlock）与代理兼容模式
+// 不支持重入，返回 ErrReentrantUnsupported。
+//
 // # 与 xcache 的关系
 //
 // xcache 和 xdlock 各自独立实现，针对不同场景：
@@ -91,6 +176,29 @@
 //	| 业务互斥（单节点） | xdlock.NewRedisFactory(client) |
 //	| 业务互斥（多节点） | xdlock.NewRedisFactory(client1, client2, client3) |
 //	| 强一致性互斥 | xdlock.NewEtcdFactory(etcdClient) |
+//	| 读多写少（单节点） | xdlock.NewRWFactory(client) |
+//
+// # 读写锁
+//
+// NewRWFactory 基于单个 Redis 客户端提供读写锁，适用于"多读者并行、写者独占"的场景
+// （如缓存重建：读请求并发访问，重建时需要独占）：
+//
+//	rw, _ := xdlock.NewRWFactory(client)
+//	r, err := rw.RLock(ctx, "cache:user")   // 读锁之间共享
+//	w, err := rw.Lock(ctx, "cache:user")    // 写锁等待所有读者释放
+//
+// 实现上写锁是一个带 PX 的 STRING，读者记录在 ZSET 中（member 为持有者标识，
+// score 为该读者的过期时间），Lua 脚本原子地完成"检查写锁 + 登记读者"与
+// "清理过期读者 + 检查读者数 + 设置写锁"。
+//
+// 读者泄漏恢复：每个读者独立带 Expiry（WithExpiry，默认 8s），进程崩溃未 Unlock 的读者
+// 会在到期后被下一次加锁操作剔除，写者最多被阻塞一个 Expiry。长时间持有读锁需定期 Extend。
+//
+// 与互斥锁一样，读写锁是尽力而为的：依赖 TTL 而非 fencing token，GC 停顿或网络分区
+// 可能使持有者在锁过期后仍认为自己持有锁。仅支持单节点（不提供 Redlock 多数派语义）；
+// 写者不具备优先权，读者持续不断时写者可能在 Tries 耗尽后返回 ErrLockFailed。
+// 代理兼容模式下改用"先登记、后校验"的基础命令序列，仍保证读写互斥，
+// 但竞争激烈时读写双方可能同时撤回，由重试兜底。
 //
 // # Redis 代理兼容模式
 //
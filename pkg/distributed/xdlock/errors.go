@@ -44,6 +44,10 @@ var (
 	// 尝试 Unlock 或 Extend 未持有的锁时返回此错误。
 	ErrNotLocked = errors.New("xdlock: not locked")
 
+	// ErrNoKeys 未提供任何锁 key。
+	// LockMany 传入空的 keys 时返回此错误。
+	ErrNoKeys = errors.New("xdlock: keys must not be empty")
+
 	// ErrEmptyKey 锁 key 为空。
 	// key 为空字符串或仅含空白时返回此错误。
 	ErrEmptyKey = errors.New("xdlock: key must not be empty")
@@ -59,6 +63,10 @@ var (
 	// ErrNoEndpoints 未配置 endpoints。
 	// etcd 配置中未提供 endpoints 时返回此错误。
 	ErrNoEndpoints = errors.New("xdlock: no endpoints configured")
+
+	// ErrReentrantUnsupported 当前工厂不支持可重入模式。
+	// 可重入锁依赖 Lua 脚本保证计数原子性，仅支持单节点且未启用代理兼容模式的 Redis 工厂。
+	ErrReentrantUnsupported = errors.New("xdlock: reentrant mode requires a single redis node with lua scripting")
 )
 
 // 内部错误（不导出）。
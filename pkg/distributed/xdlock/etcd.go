@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/client/v3/concurrency"
@@ -44,6 +45,8 @@ type etcdFactory struct {
 	// 共享同一 owner key，导致 Unlock 一个 handle 会释放所有 handle 的锁。
 	// 本地追踪确保每个 key 在同一工厂内最多只有一个活跃的 LockHandle。
 	lockedKeys sync.Map
+
+	held heldLocks
 }
 
 // NewEtcdFactory 创建 etcd 锁工厂。
@@ -109,11 +112,7 @@ func (f *etcdFactory) TryLock(ctx context.Context, key string, opts ...MutexOpti
 		return nil, err
 	}
 
-	return &etcdLockHandle{
-		factory: f,
-		mu:      mutex,
-		key:     fullKey,
-	}, nil
+	return f.newHandle(mutex, fullKey), nil
 }
 
 // Lock 阻塞式获取锁，返回 LockHandle。
@@ -143,11 +142,24 @@ func (f *etcdFactory) Lock(ctx context.Context, key string, opts ...MutexOption)
 		return nil, wrapEtcdError(err)
 	}
 
-	return &etcdLockHandle{
-		factory: f,
-		mu:      mutex,
-		key:     fullKey,
-	}, nil
+	return f.newHandle(mutex, fullKey), nil
+}
+
+// newHandle 创建 LockHandle 并记录到持有列表（内部方法）。
+func (f *etcdFactory) newHandle(mu mutexUnlocker, fullKey string) *etcdLockHandle {
+	h := &etcdLockHandle{
+		factory:    f,
+		mu:         mu,
+		key:        fullKey,
+		acquiredAt: time.Now(),
+	}
+	f.held.add(h)
+	return h
+}
+
+// Held 返回当前进程通过本工厂持有的锁。
+func (f *etcdFactory) Held() []HeldLock {
+	return f.held.list()
 }
 
 // checkSession 检查 Session 是否有效（内部方法）。
@@ -168,6 +180,8 @@ func (f *etcdFactory) Close(_ context.Context) error {
 	if f.closed.Swap(true) {
 		return nil // 已关闭
 	}
+	// Session 关闭会撤销 Lease，所有锁随之释放
+	f.held.clear()
 	return f.sp.Close()
 }
 
@@ -201,6 +215,78 @@ func (f *etcdFactory) Health(ctx context.Context) error {
 	return nil
 }
 
+// WatchLock 监听锁状态变化。
+//
+// 基于 etcd Watch 监听锁前缀（concurrency.Mutex 在 "{key}/" 下为每个竞争者创建 key），
+// 前缀下存在任意 key 即视为"已获取"：持有者释放后若有排队等待者会立即接管，
+// 此时不会产生 Released 事件。Watch 因压缩、网络中断等原因关闭时自动重建。
+func (f *etcdFactory) WatchLock(ctx context.Context, key string, opts ...MutexOption) (<-chan LockEvent, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if f.closed.Load() {
+		return nil, ErrFactoryClosed
+	}
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	options := resolveMutexOptions(opts...)
+	fullKey := options.KeyPrefix + key
+	// 与 concurrency.NewMutex 的前缀规则保持一致：pfx = fullKey + "/"
+	pfx := fullKey + "/"
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	wake := make(chan struct{}, 1)
+	go f.forwardWatchEvents(watchCtx, pfx, options.WatchPollInterval, wake)
+
+	out := make(chan LockEvent, 1)
+	go func() {
+		defer cancel()
+		runLockWatch(watchCtx, fullKey, options.WatchPollInterval, f.probeLock(pfx), wake, out)
+	}()
+	return out, nil
+}
+
+// probeLock 返回探测锁前缀下是否存在 key 的函数。
+func (f *etcdFactory) probeLock(pfx string) lockProbe {
+	return func(ctx context.Context) (bool, error) {
+		if f.closed.Load() {
+			return false, ErrFactoryClosed
+		}
+		resp, err := f.client.Get(ctx, pfx, clientv3.WithPrefix(), clientv3.WithCountOnly())
+		if err != nil {
+			return false, err
+		}
+		return resp.Count > 0, nil
+	}
+}
+
+// forwardWatchEvents 将锁前缀的 Watch 事件转为唤醒信号，直到 ctx 取消。
+//
+// 设计决策: Watch channel 关闭（压缩、leader 切换、连接中断）时，先唤醒一次探测
+// 以补偿中断期间可能丢失的事件，再等待一个轮询间隔后重建 Watch，避免客户端不可用时空转。
+func (f *etcdFactory) forwardWatchEvents(ctx context.Context, pfx string, retry time.Duration, wake chan<- struct{}) {
+	timer := time.NewTimer(retry)
+	defer timer.Stop()
+	for {
+		for range f.client.Watch(clientv3.WithRequireLeader(ctx), pfx, clientv3.WithPrefix()) {
+			notifyWake(wake)
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		notifyWake(wake)
+
+		timer.Reset(retry)
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+}
+
 // Session 返回底层 concurrency.Session。
 func (f *etcdFactory) Session() Session {
 	return f.session
@@ -217,6 +303,9 @@ type etcdLockHandle struct {
 	mu       mutexUnlocker // Unlock 使用，通常为 *concurrency.Mutex
 	key      string
 	unlocked atomic.Bool // 标记锁是否已被显式释放
+
+	acquiredAt time.Time
+	lost       lostNotifier
 }
 
 // Unlock 释放锁。
@@ -252,6 +341,8 @@ func (h *etcdLockHandle) Unlock(ctx context.Context) error {
 	// 此时 Extend 应继续报告锁状态正常，而非错误返回 ErrNotLocked。
 	h.unlocked.Store(true)
 	h.factory.lockedKeys.Delete(h.key)
+	h.factory.held.remove(h)
+	h.lost.signal()
 	return nil
 }
 
@@ -288,6 +379,17 @@ func (h *etcdLockHandle) Key() string {
 	return h.key
 }
 
+// heldInfo 返回 handle 的持有信息。
+// etcd 锁由 Session 自动续期，不提供 ExpiresAt。
+func (h *etcdLockHandle) heldInfo() HeldLock {
+	return HeldLock{
+		Key:        h.key,
+		Backend:    BackendEtcd,
+		Mode:       LockModeMutex,
+		AcquiredAt: h.acquiredAt,
+	}
+}
+
 // =============================================================================
 // 错误转换
 // =============================================================================
@@ -124,6 +124,30 @@ func TestEtcdFactory_Health_OK_Embed(t *testing.T) {
 	}
 }
 
+func TestEtcdFactory_HealthDetail_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, f) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	nodes, err := f.HealthDetail(ctx)
+	if err != nil {
+		t.Fatalf("HealthDetail: %v", err)
+	}
+	if len(nodes) != len(cli.Endpoints()) {
+		t.Fatalf("len(nodes) = %d, want %d", len(nodes), len(cli.Endpoints()))
+	}
+	for i, n := range nodes {
+		if n.Addr != cli.Endpoints()[i] || !n.Healthy || n.Err != nil {
+			t.Errorf("nodes[%d] = %+v, want healthy %s", i, n, cli.Endpoints()[i])
+		}
+	}
+}
+
 func TestEtcdFactory_Health_AfterClose_Embed(t *testing.T) {
 	cli := sharedEtcdClient(t)
 	f, err := xdlock.NewEtcdFactory(cli)
@@ -418,3 +442,277 @@ func TestEtcdFactory_MutualExclusion_Embed(t *testing.T) {
 		t.Fatalf("mutex violation: %d", v)
 	}
 }
+
+// -----------------------------------------------------------------------------
+// WatchLock
+// -----------------------------------------------------------------------------
+
+func TestEtcdFactory_WatchLock_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, f) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := uniqueKey(t, "watch")
+	events, err := f.WatchLock(ctx, key)
+	if err != nil {
+		t.Fatalf("WatchLock: %v", err)
+	}
+	if ev := nextLockEvent(t, events); ev.Type != xdlock.LockEventReleased {
+		t.Fatalf("initial event: want released, got %v", ev.Type)
+	}
+
+	h, err := f.TryLock(ctx, key)
+	if err != nil || h == nil {
+		t.Fatalf("TryLock: h=%v err=%v", h, err)
+	}
+	if ev := nextLockEvent(t, events); ev.Type != xdlock.LockEventAcquired {
+		t.Fatalf("want acquired, got %v", ev.Type)
+	}
+
+	if err := h.Unlock(ctx); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if ev := nextLockEvent(t, events); ev.Type != xdlock.LockEventReleased {
+		t.Fatalf("want released, got %v", ev.Type)
+	}
+
+	cancel()
+	waitWatchClosed(t, events)
+}
+
+func TestEtcdFactory_WatchLock_OtherFactoryRelease_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	holder, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, holder) })
+	waiter, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, waiter) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := uniqueKey(t, "watch")
+	h, err := holder.TryLock(ctx, key)
+	if err != nil || h == nil {
+		t.Fatalf("TryLock: h=%v err=%v", h, err)
+	}
+
+	// 轮询间隔足够长，确保释放事件来自 etcd Watch 而非兜底轮询
+	events, err := waiter.WatchLock(ctx, key, xdlock.WithWatchPollInterval(time.Minute))
+	if err != nil {
+		t.Fatalf("WatchLock: %v", err)
+	}
+	if ev := nextLockEvent(t, events); ev.Type != xdlock.LockEventAcquired {
+		t.Fatalf("initial event: want acquired, got %v", ev.Type)
+	}
+
+	// 关闭持有方工厂会撤销 Lease，锁随之释放
+	closeFactoryNoErr(t, holder)
+	if ev := nextLockEvent(t, events); ev.Type != xdlock.LockEventReleased {
+		t.Fatalf("want released, got %v", ev.Type)
+	}
+
+	h2, err := waiter.TryLock(ctx, key)
+	if err != nil || h2 == nil {
+		t.Fatalf("waiter TryLock after release: h=%v err=%v", h2, err)
+	}
+	unlockNoErr(t, h2)
+}
+
+func TestEtcdFactory_WatchLock_AfterClose_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	closeFactoryNoErr(t, f)
+	if _, err := f.WatchLock(context.Background(), "k"); !errors.Is(err, xdlock.ErrFactoryClosed) {
+		t.Fatalf("want ErrFactoryClosed, got %v", err)
+	}
+}
+
+func TestEtcdLockHandle_TTL_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli, xdlock.WithEtcdTTL(7))
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	h, err := f.TryLock(ctx, uniqueKey(t, "k"))
+	if err != nil || h == nil {
+		t.Fatalf("TryLock: h=%v err=%v", h, err)
+	}
+
+	ttl, err := h.TTL(ctx)
+	if err != nil {
+		t.Fatalf("TTL: %v", err)
+	}
+	if ttl <= 0 || ttl > 7*time.Second {
+		t.Fatalf("TTL = %v, want (0, 7s]", ttl)
+	}
+
+	// 工厂关闭后 Lease 被撤销
+	closeFactoryNoErr(t, f)
+	if _, err := h.TTL(ctx); err == nil {
+		t.Fatal("TTL after factory Close: want error")
+	}
+}
+
+func TestEtcdLockHandle_TTL_AfterUnlock_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, f) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	h, err := f.TryLock(ctx, uniqueKey(t, "k"))
+	if err != nil || h == nil {
+		t.Fatalf("TryLock: h=%v err=%v", h, err)
+	}
+	unlockNoErr(t, h)
+	if _, err := h.TTL(ctx); !errors.Is(err, xdlock.ErrNotLocked) {
+		t.Fatalf("TTL after Unlock: want ErrNotLocked, got %v", err)
+	}
+	if _, err := h.TTL(nil); !errors.Is(err, xdlock.ErrNilContext) { //nolint:staticcheck // SA1012: nil ctx 是测试目标
+		t.Fatalf("TTL(nil): want ErrNilContext, got %v", err)
+	}
+}
+
+func TestEtcdFactory_Held_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	h1, err := f.TryLock(ctx, uniqueKey(t, "a"))
+	if err != nil || h1 == nil {
+		t.Fatalf("TryLock a: h=%v err=%v", h1, err)
+	}
+	h2, err := f.TryLock(ctx, uniqueKey(t, "b"))
+	if err != nil || h2 == nil {
+		t.Fatalf("TryLock b: h=%v err=%v", h2, err)
+	}
+
+	held := f.Held()
+	if len(held) != 2 {
+		t.Fatalf("Held len = %d, want 2", len(held))
+	}
+	if held[0].Key != h1.Key() || held[0].Backend != xdlock.BackendEtcd || !held[0].ExpiresAt.IsZero() {
+		t.Fatalf("Held[0] = %+v", held[0])
+	}
+
+	unlockNoErr(t, h1)
+	if held := f.Held(); len(held) != 1 || held[0].Key != h2.Key() {
+		t.Fatalf("Held after Unlock = %+v", held)
+	}
+
+	// Close 撤销 Lease，所有锁随之释放
+	closeFactoryNoErr(t, f)
+	if held := f.Held(); len(held) != 0 {
+		t.Fatalf("Held after Close = %+v", held)
+	}
+}
+
+func TestEtcdLockHandle_Lost_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	h1, err := f.TryLock(ctx, uniqueKey(t, "a"))
+	if err != nil || h1 == nil {
+		t.Fatalf("TryLock a: h=%v err=%v", h1, err)
+	}
+	h2, err := f.TryLock(ctx, uniqueKey(t, "b"))
+	if err != nil || h2 == nil {
+		t.Fatalf("TryLock b: h=%v err=%v", h2, err)
+	}
+
+	lost1, lost2 := h1.Lost(), h2.Lost()
+	unlockNoErr(t, h1)
+	select {
+	case <-lost1:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Lost not closed after Unlock")
+	}
+	select {
+	case <-lost2:
+		t.Fatal("Lost of another handle closed unexpectedly")
+	default:
+	}
+
+	// 工厂关闭撤销 Lease，Session 结束
+	closeFactoryNoErr(t, f)
+	select {
+	case <-lost2:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Lost not closed after factory Close")
+	}
+}
+
+func TestEtcdFactory_LockMany_Embed(t *testing.T) {
+	cli := sharedEtcdClient(t)
+	f, err := xdlock.NewEtcdFactory(cli)
+	if err != nil {
+		t.Fatalf("NewEtcdFactory: %v", err)
+	}
+	t.Cleanup(func() { closeFactoryNoErr(t, f) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	a, b := uniqueKey(t, "a"), uniqueKey(t, "b")
+
+	h, err := f.LockMany(ctx, []string{b, a, a})
+	if err != nil || h == nil {
+		t.Fatalf("LockMany: h=%v err=%v", h, err)
+	}
+	if want := "lock:" + a + ",lock:" + b; h.Key() != want {
+		t.Fatalf("Key = %q, want %q", h.Key(), want)
+	}
+	if held := f.Held(); len(held) != 2 {
+		t.Fatalf("Held len = %d, want 2", len(held))
+	}
+
+	// 同工厂再次获取已持有的 key 失败时，先获取的 key 被回滚
+	c := uniqueKey(t, "0")
+	shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer shortCancel()
+	if _, err := f.LockMany(shortCtx, []string{c, b}); err == nil {
+		t.Fatal("LockMany on held key: want error")
+	}
+	if held := f.Held(); len(held) != 2 {
+		t.Fatalf("Held after failed LockMany = %+v, want only the first 2 keys", held)
+	}
+
+	unlockNoErr(t, h)
+	if held := f.Held(); len(held) != 0 {
+		t.Fatalf("Held after Unlock = %+v", held)
+	}
+	hc, err := f.TryLock(ctx, c)
+	if err != nil || hc == nil {
+		t.Fatalf("TryLock rolled-back key: h=%v err=%v", hc, err)
+	}
+	unlockNoErr(t, hc)
+}
@@ -0,0 +1,80 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// HealthDetail 测试
+// =============================================================================
+
+func TestRedisFactory_HealthDetail(t *testing.T) {
+	ctx := context.Background()
+	mr1, c1 := newTestMiniredis(t)
+	mr2, c2 := newTestMiniredis(t)
+	mr3, c3 := newTestMiniredis(t)
+	f, err := NewRedisFactory(c1, c2, c3)
+	require.NoError(t, err)
+
+	nodes, err := f.HealthDetail(ctx)
+	require.NoError(t, err)
+	require.Len(t, nodes, 3)
+	for i, mr := range []*miniredis.Miniredis{mr1, mr2, mr3} {
+		assert.Equal(t, mr.Addr(), nodes[i].Addr)
+		assert.True(t, nodes[i].Healthy)
+		assert.NoError(t, nodes[i].Err)
+	}
+
+	// 单节点故障：仲裁降级为 2/3，Health 报错而 HealthDetail 给出逐节点结果
+	mr2.SetError("LOADING")
+	require.Error(t, f.Health(ctx))
+	nodes, err = f.HealthDetail(ctx)
+	require.NoError(t, err)
+	assert.True(t, nodes[0].Healthy)
+	assert.False(t, nodes[1].Healthy)
+	assert.Error(t, nodes[1].Err)
+	assert.True(t, nodes[2].Healthy)
+}
+
+func TestRedisFactory_HealthDetail_Errors(t *testing.T) {
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	_, err = f.HealthDetail(nil) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+
+	require.NoError(t, f.Close(context.Background()))
+	_, err = f.HealthDetail(context.Background())
+	require.ErrorIs(t, err, ErrFactoryClosed)
+}
+
+func TestRedisClientAddr(t *testing.T) {
+	single := redis.NewClient(&redis.Options{Addr: "10.0.0.1:6379"})
+	defer func() { _ = single.Close() }()
+	cluster := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{"a:1", "b:2"}})
+	defer func() { _ = cluster.Close() }()
+	ring := redis.NewRing(&redis.RingOptions{Addrs: map[string]string{"s1": "c:3"}})
+	defer func() { _ = ring.Close() }()
+
+	assert.Equal(t, "10.0.0.1:6379", redisClientAddr(single, 0))
+	assert.Equal(t, "a:1,b:2", redisClientAddr(cluster, 1))
+	assert.Equal(t, "node-2", redisClientAddr(ring, 2))
+}
+
+func TestEtcdFactory_HealthDetail_Preconditions(t *testing.T) {
+	f := NewTestEtcdFactory(NewMockSession())
+
+	_, err := f.HealthDetail(nil) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+
+	f.closed.Store(true)
+	_, err = f.HealthDetail(context.Background())
+	require.ErrorIs(t, err, ErrFactoryClosed)
+}
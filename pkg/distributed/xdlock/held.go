@@ -0,0 +1,99 @@
+package xdlock
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// 持有锁的本地记录
+// =============================================================================
+
+// 锁模式，用于 [HeldLock.Mode]。
+const (
+	// LockModeMutex 互斥锁（Factory.TryLock/Lock）。
+	LockModeMutex = "mutex"
+
+	// LockModeRead 读锁（RWFactory.TryRLock/RLock）。
+	LockModeRead = "read"
+
+	// LockModeWrite 写锁（RWFactory.TryLock/Lock）。
+	LockModeWrite = "write"
+)
+
+// 锁后端，用于 [HeldLock.Backend]。
+const (
+	// BackendRedis Redis 后端（含 Redlock 与读写锁）。
+	BackendRedis = "redis"
+
+	// BackendEtcd etcd 后端。
+	BackendEtcd = "etcd"
+)
+
+// HeldLock 描述当前进程持有的一把锁，由 [Factory.Held] 返回。
+type HeldLock struct {
+	// Key 锁的完整 key（包含前缀）。
+	Key string
+
+	// Backend 锁后端（BackendRedis/BackendEtcd）。
+	Backend string
+
+	// Mode 锁模式（LockModeMutex/LockModeRead/LockModeWrite）。
+	Mode string
+
+	// AcquiredAt 获取锁的时间。
+	AcquiredAt time.Time
+
+	// ExpiresAt 本地估计的过期时间（获取或最近一次 Extend 后的 TTL 截止时间）。
+	// etcd 锁由 Session 自动续期，为零值。
+	ExpiresAt time.Time
+}
+
+// heldHandle 可被本地记录的锁句柄。
+type heldHandle interface {
+	heldInfo() HeldLock
+}
+
+// heldLocks 记录工厂已获取且尚未 Unlock 的锁句柄。
+//
+// 设计决策: 记录句柄而非快照，ExpiresAt 等动态信息在查询时由句柄计算，
+// 避免 Extend 路径额外维护记录。
+type heldLocks struct {
+	m sync.Map // heldHandle -> struct{}
+}
+
+// add 记录句柄。
+func (l *heldLocks) add(h heldHandle) {
+	l.m.Store(h, struct{}{})
+}
+
+// remove 移除句柄，重复移除无副作用。
+func (l *heldLocks) remove(h heldHandle) {
+	l.m.Delete(h)
+}
+
+// clear 移除全部记录。
+func (l *heldLocks) clear() {
+	l.m.Clear()
+}
+
+// list 返回全部记录，按获取时间升序、key 升序排列。
+func (l *heldLocks) list() []HeldLock {
+	var out []HeldLock
+	l.m.Range(func(k, _ any) bool {
+		h, ok := k.(heldHandle)
+		if ok {
+			out = append(out, h.heldInfo())
+		}
+		return true
+	})
+	slices.SortFunc(out, func(a, b HeldLock) int {
+		if c := a.AcquiredAt.Compare(b.AcquiredAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return out
+}
@@ -0,0 +1,82 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+)
+
+// =============================================================================
+// Factory.Held 测试
+// =============================================================================
+
+func TestRedisFactory_Held(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+	assert.Empty(t, f.Held())
+
+	h1, err := f.TryLock(ctx, "a", WithExpiry(time.Minute))
+	require.NoError(t, err)
+	h2, err := f.Lock(ctx, "b")
+	require.NoError(t, err)
+
+	held := f.Held()
+	require.Len(t, held, 2)
+	assert.Equal(t, "lock:a", held[0].Key)
+	assert.Equal(t, BackendRedis, held[0].Backend)
+	assert.Equal(t, LockModeMutex, held[0].Mode)
+	assert.False(t, held[0].AcquiredAt.IsZero())
+	assert.WithinDuration(t, time.Now().Add(time.Minute), held[0].ExpiresAt, 5*time.Second)
+
+	before := held[1].ExpiresAt
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, h2.Extend(ctx))
+	assert.True(t, f.Held()[1].ExpiresAt.After(before), "Extend refreshes ExpiresAt")
+
+	require.NoError(t, h1.Unlock(ctx))
+	held = f.Held()
+	require.Len(t, held, 1)
+	assert.Equal(t, "lock:b", held[0].Key)
+
+	// 所有权丢失后的 Unlock 同样移除记录
+	mr.Del("lock:b")
+	require.ErrorIs(t, h2.Unlock(ctx), ErrNotLocked)
+	assert.Empty(t, f.Held())
+}
+
+func TestRWFactory_Held(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestMiniredis(t)
+	f, err := NewRWFactory(client, WithRedisScriptMode(rediscompat.ScriptModeLua))
+	require.NoError(t, err)
+
+	r, err := f.TryRLock(ctx, "cache")
+	require.NoError(t, err)
+	held := f.Held()
+	require.Len(t, held, 1)
+	assert.Equal(t, LockModeRead, held[0].Mode)
+	require.NoError(t, r.Unlock(ctx))
+
+	w, err := f.TryLock(ctx, "cache")
+	require.NoError(t, err)
+	held = f.Held()
+	require.Len(t, held, 1)
+	assert.Equal(t, LockModeWrite, held[0].Mode)
+	assert.Equal(t, "lock:cache", held[0].Key)
+
+	// 未获取到的锁不记录
+	other, err := f.TryRLock(ctx, "cache")
+	require.NoError(t, err)
+	assert.Nil(t, other)
+	assert.Len(t, f.Held(), 1)
+
+	require.NoError(t, w.Unlock(ctx))
+	assert.Empty(t, f.Held())
+}
@@ -1,6 +1,9 @@
 package xdlock
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // =============================================================================
 // LockHandle - 推荐的锁操作接口
@@ -55,6 +58,53 @@ type LockHandle interface {
 	//   - [ErrSessionExpired]: etcd Session 已过期
 	Extend(ctx context.Context) error
 
+	// TTL 返回锁的剩余有效期。
+	//
+	// Redis 后端：读取锁 key 的 PTTL（Redlock 多节点取持有锁节点中的最小值）。
+	// etcd 后端：查询 Session Lease 的剩余 TTL（秒级精度）。
+	// 传入 nil ctx 返回 [ErrNilContext]。
+	//
+	// 返回值：
+	//   - [ErrNotLocked]: 锁已过期、被释放或被其他获取覆盖
+	//   - [ErrSessionExpired]: etcd Session 或 Lease 已过期
+	//   - 其他错误: 查询失败（网络错误等），锁状态未知
+	TTL(ctx context.Context) (time.Duration, error)
+
+	// Lost 返回锁丢失通知 channel，后端确认本 handle 已不再持有锁时关闭。
+	//
+	// 用于长任务在失去锁后立即中止工作，而不是等到下一次 Extend 才发现：
+	//
+	//	select {
+	//	case <-handle.Lost():
+	//	    return ErrAborted // 锁已丢失，停止执行
+	//	case result := <-work:
+	//	    ...
+	//	}
+	//
+	// etcd 后端：监听 Session.Done()，Session 过期或工厂关闭时立即关闭。
+	// Redis 后端：首次调用时启动后台 watcher，按 [WithLostPollInterval]（默认 1 秒）
+	// 轮询锁 key 的 value 与 PTTL，锁丢失最多延迟一个轮询周期被感知；
+	// 后端不可达时以本地估计的过期时间为准。
+	//
+	// Unlock 成功或 Extend 确认所有权丢失后 channel 同样关闭，多次调用返回同一 channel。
+	Lost() <-chan struct{}
+
+	// StartAutoExtend 启动后台自动续期，适用于运行时间不确定的长任务。
+	//
+	// 以 interval 周期性调用 Extend，直到调用返回的 stop 函数、Unlock 或锁确认丢失
+	// （Extend 返回 [ErrNotLocked]）。stop 会等待进行中的续期返回；Unlock 在释放锁之前
+	// 停止续期，因此 Unlock 之后不会再有续期请求。续期的临时失败不会终止续期，
+	// 需要感知锁丢失时配合 Lost 使用。
+	//
+	// 运行中重复调用返回同一 stop 函数；interval <= 0 或 Unlock 之后调用返回空操作。
+	// 建议 interval 不超过 Expiry 的 1/3，确保续期在过期前完成。
+	//
+	// etcd 后端由 Session 自动续期，返回空操作。
+	//
+	//	stop := handle.StartAutoExtend(3 * time.Second)
+	//	defer stop()
+	StartAutoExtend(interval time.Duration) (stop func())
+
 	// Key 返回锁的 key。
 	//
 	// 用于日志记录等场景。
@@ -101,6 +151,48 @@ type Factory interface {
 	//   - [ErrLockFailed]: 重试耗尽仍未获取到锁（Redis 后端）
 	Lock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
 
+	// TryLockWithWait 有界等待式获取锁。
+	//
+	// 在 maxWait 内按退避策略（见 [WithWaitBackoff]）重复 TryLock，直到获取成功或等待超时。
+	// maxWait <= 0 时等价于单次 TryLock。
+	//
+	// 返回：
+	//   - (handle, nil): 获取成功
+	//   - (nil, nil): maxWait 内锁始终被占用（与 TryLock 的"被占用"语义一致）
+	//   - (nil, err): 锁服务异常（立即返回，不再重试）或 ctx 取消/超时
+	TryLockWithWait(ctx context.Context, key string, maxWait time.Duration, opts ...MutexOption) (LockHandle, error)
+
+	// LockMany 阻塞式获取多个 key 的锁，用于需要同时持有多把锁的场景（如两个账户间转账）。
+	//
+	// keys 按字典序排序、去重后依次 Lock（重试策略同 Lock）；任一 key 获取失败时
+	// 逆序释放已获取的锁，再返回该错误。成功时返回的 handle 代表全部 key：
+	// Unlock 释放全部 key，Extend 续期全部 key，TTL 返回最小剩余时间，
+	// Lost 在任一 key 丢失时关闭，Key 返回以逗号连接的全部 key。
+	// keys 为空返回 [ErrNoKeys]，每个 key 的校验规则与 Lock 相同。
+	//
+	// 注意：避免死锁依赖所有调用方使用一致的获取顺序。只有同一组 key 的所有获取方
+	// 都通过 LockMany（而非按各自顺序多次调用 Lock）获取时，才保证不会相互等待成环。
+	LockMany(ctx context.Context, keys []string, opts ...MutexOption) (LockHandle, error)
+
+	// WatchLock 监听锁状态变化（获取/释放），用于等待者事件驱动地响应锁释放。
+	//
+	// 返回的 channel 首个事件反映订阅时的当前状态，之后仅在状态变化时推送事件。
+	// ctx 取消或工厂关闭时停止监听并关闭 channel；调用方应持续消费 channel 直到关闭。
+	// 传入 nil ctx 返回 [ErrNilContext]，key 校验规则与 TryLock 相同。
+	//
+	// 后端通知仅作为唤醒信号，每次唤醒都会重新探测锁的真实状态；
+	// 另有兜底轮询（见 [WithWatchPollInterval]），通知丢失时最多延迟一个轮询周期。
+	// opts 中仅 KeyPrefix 与 WatchPollInterval 生效。
+	//
+	// 注意：收到 [LockEventReleased] 仅表示观察时刻锁空闲，随后 TryLock 仍可能被其他等待者抢先。
+	WatchLock(ctx context.Context, key string, opts ...MutexOption) (<-chan LockEvent, error)
+
+	// Held 返回当前进程通过本工厂获取且尚未 Unlock 的锁，按获取时间升序排列。
+	//
+	// 仅读取本地记录，不访问后端，可用于调试工具排查"任务为何阻塞"。
+	// 锁在后端过期但未被 Unlock 时仍会列出，可通过 [HeldLock.ExpiresAt] 判断。
+	Held() []HeldLock
+
 	// Close 关闭工厂，释放底层资源。
 	// 关闭后不应再创建新的锁实例。
 	//
@@ -112,6 +204,14 @@ type Factory interface {
 	// 检查底层连接是否正常。
 	// 传入 nil ctx 返回 [ErrNilContext]。
 	Health(ctx context.Context) error
+
+	// HealthDetail 逐节点健康检查，返回每个后端节点（Redis 客户端或 etcd endpoint）的可达性。
+	//
+	// 与 Health 的"任一节点失败即报错"不同，HealthDetail 总是探测全部节点，
+	// 单个节点不可达只记录在对应 [NodeHealth] 中。Redlock 多节点部署可据此统计健康节点数，
+	// 在低于多数派（如 3 节点仅剩 2 个可达）导致获取锁失败之前发现仲裁降级。
+	// 传入 nil ctx 返回 [ErrNilContext]，工厂已关闭返回 [ErrFactoryClosed]。
+	HealthDetail(ctx context.Context) ([]NodeHealth, error)
 }
 
 // EtcdFactory 定义 etcd 锁工厂接口。
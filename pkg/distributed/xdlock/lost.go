@@ -0,0 +1,122 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// LockHandle.Lost 实现
+// =============================================================================
+
+// defaultLostPollInterval Redis 后端 Lost watcher 的默认轮询间隔。
+const defaultLostPollInterval = time.Second
+
+// lostNotifier 管理 handle 的锁丢失通知 channel。
+//
+// 设计决策: channel 与后台 watcher 均按需创建，未调用 Lost 的 handle 不产生任何 goroutine，
+// 也不增加对后端的查询压力。零值可用，便于测试直接构造 handle。
+type lostNotifier struct {
+	initOnce  sync.Once
+	closeOnce sync.Once
+	watchOnce sync.Once
+	ch        chan struct{}
+}
+
+// channel 返回通知 channel（首次调用时创建）。
+func (n *lostNotifier) channel() chan struct{} {
+	n.initOnce.Do(func() {
+		n.ch = make(chan struct{})
+	})
+	return n.ch
+}
+
+// signal 关闭通知 channel，重复调用无副作用。
+func (n *lostNotifier) signal() {
+	ch := n.channel()
+	n.closeOnce.Do(func() {
+		close(ch)
+	})
+}
+
+// watch 返回通知 channel，并在首次调用时启动 watcher。
+// watcher 应在 stop（即通知 channel）关闭后退出。
+func (n *lostNotifier) watch(watcher func(stop <-chan struct{})) <-chan struct{} {
+	ch := n.channel()
+	n.watchOnce.Do(func() {
+		go watcher(ch)
+	})
+	return ch
+}
+
+// Lost 返回锁丢失通知 channel。
+//
+// 首次调用时启动后台 watcher，每个轮询周期（见 [WithLostPollInterval]，默认 1 秒）
+// 检查一次锁 key 是否仍由本 handle 持有。
+func (h *redisLockHandle) Lost() <-chan struct{} {
+	return h.lost.watch(h.watchLost)
+}
+
+// watchLost 轮询锁所有权，确认丢失后关闭通知 channel。
+//
+// 设计决策: 查询失败（网络错误、Redlock 凑不齐多数派）时无法断定锁已丢失，继续轮询；
+// 但若本地估计的过期时间已过，说明 TTL 必然已到期（未经成功的 Extend 不会延长），
+// 此时即使后端不可达也判定为丢失，避免 Redis 故障期间任务在无锁状态下继续执行。
+func (h *redisLockHandle) watchLost(stop <-chan struct{}) {
+	pollLost(stop, h.lostPollInterval, h.TTL, &h.expiresAt, h.lost.signal)
+}
+
+// Lost 返回锁丢失通知 channel。
+//
+// 首次调用时启动后台 watcher，写锁检查写锁 key 的 value 与 PTTL，
+// 读锁检查本读者在读者集合中的过期时间，轮询间隔见 [WithLostPollInterval]。
+func (h *rwLockHandle) Lost() <-chan struct{} {
+	return h.lost.watch(h.watchLost)
+}
+
+// watchLost 轮询锁所有权，确认丢失后关闭通知 channel。
+// 判定规则与互斥锁一致，见 redisLockHandle.watchLost。
+func (h *rwLockHandle) watchLost(stop <-chan struct{}) {
+	pollLost(stop, h.lostPollInterval, h.TTL, &h.expiresAt, h.lost.signal)
+}
+
+// pollLost Redis 后端 watcher 的公共轮询流程。
+func pollLost(stop <-chan struct{}, interval time.Duration,
+	ttl func(context.Context) (time.Duration, error), expiresAt *atomic.Int64, signal func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		_, err := ttl(ctx)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if errors.Is(err, ErrNotLocked) || time.Now().UnixNano() >= expiresAt.Load() {
+			signal()
+			return
+		}
+	}
+}
+
+// Lost 返回锁丢失通知 channel。
+//
+// 设计决策: etcd 锁的生命周期由 Session 决定，直接监听 Session.Done()，
+// Lease 过期（KeepAlive 失败）或工厂关闭撤销 Lease 时立即通知，无需轮询。
+func (h *etcdLockHandle) Lost() <-chan struct{} {
+	return h.lost.watch(func(stop <-chan struct{}) {
+		select {
+		case <-h.factory.sp.Done():
+			h.lost.signal()
+		case <-stop:
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// LockHandle.Lost 测试
+// =============================================================================
+
+const lostWait = 2 * time.Second
+
+func requireLost(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(lostWait):
+		t.Fatal("Lost channel not closed")
+	}
+}
+
+func requireNotLost(t *testing.T, ch <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-ch:
+		t.Fatal("Lost channel closed unexpectedly")
+	default:
+	}
+}
+
+func TestRedisLockHandle_Lost(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	t.Run("被覆盖", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "overwritten", WithLostPollInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		require.NotNil(t, h)
+
+		lost := h.Lost()
+		assert.Equal(t, lost, h.Lost(), "多次调用应返回同一 channel")
+		time.Sleep(50 * time.Millisecond)
+		requireNotLost(t, lost)
+
+		mr.Set("lock:overwritten", "someone-else")
+		requireLost(t, lost)
+	})
+
+	t.Run("TTL 到期", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "expired", WithExpiry(time.Second), WithLostPollInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		require.NotNil(t, h)
+
+		lost := h.Lost()
+		mr.FastForward(2 * time.Second)
+		requireLost(t, lost)
+	})
+
+	t.Run("Unlock 关闭", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "unlocked")
+		require.NoError(t, err)
+		require.NotNil(t, h)
+
+		lost := h.Lost()
+		require.NoError(t, h.Unlock(ctx))
+		requireLost(t, lost)
+	})
+
+	t.Run("Unlock 后首次调用", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "unlocked-before")
+		require.NoError(t, err)
+		require.NotNil(t, h)
+
+		require.NoError(t, h.Unlock(ctx))
+		requireLost(t, h.Lost())
+	})
+
+	t.Run("Extend 发现丢失", func(t *testing.T) {
+		h, err := f.TryLock(ctx, "extend-lost", WithLostPollInterval(time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, h)
+
+		mr.Del("lock:extend-lost")
+		require.ErrorIs(t, h.Extend(ctx), ErrNotLocked)
+		requireLost(t, h.Lost())
+	})
+}
+
+func TestRedisLockHandle_Lost_BackendDown(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "down", WithExpiry(300*time.Millisecond), WithLostPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	lost := h.Lost()
+	mr.SetError("LOADING simulated outage")
+
+	// 后端不可达时无法确认丢失，直到本地估计的过期时间到达
+	requireNotLost(t, lost)
+	requireLost(t, lost)
+}
+
+func TestRWLockHandle_Lost(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRWFactory(client)
+	require.NoError(t, err)
+
+	w, err := f.TryLock(ctx, "rw-w", WithLostPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NotNil(t, w)
+	wLost := w.Lost()
+	mr.Set("{lock:rw-w}:writer", "someone-else")
+	requireLost(t, wLost)
+
+	r, err := f.TryRLock(ctx, "rw-r", WithLostPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NotNil(t, r)
+	rLost := r.Lost()
+	time.Sleep(50 * time.Millisecond)
+	requireNotLost(t, rLost)
+	mr.Del("{lock:rw-r}:readers")
+	requireLost(t, rLost)
+
+	r2, err := f.TryRLock(ctx, "rw-r2")
+	require.NoError(t, err)
+	require.NotNil(t, r2)
+	r2Lost := r2.Lost()
+	require.NoError(t, r2.Unlock(ctx))
+	requireLost(t, r2Lost)
+}
+
+func TestEtcdLockHandle_Lost(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Session 过期", func(t *testing.T) {
+		mock := NewMockSession()
+		h := NewTestEtcdLockHandle(NewTestEtcdFactory(mock), "lock:session")
+
+		lost := h.Lost()
+		requireNotLost(t, lost)
+		close(mock.DoneCh)
+		requireLost(t, lost)
+	})
+
+	t.Run("Unlock 关闭", func(t *testing.T) {
+		h := NewTestEtcdLockHandle(NewTestEtcdFactory(NewMockSession()), "lock:unlock")
+
+		lost := h.Lost()
+		require.NoError(t, h.Unlock(ctx))
+		requireLost(t, lost)
+	})
+}
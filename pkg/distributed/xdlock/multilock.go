@@ -0,0 +1,160 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// =============================================================================
+// 多 key 锁
+// =============================================================================
+
+// lockFunc 单次阻塞获取锁的函数签名（Factory.Lock）。
+type lockFunc func(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+// lockMany LockMany 的公共实现，Redis 与 etcd 后端共用。
+//
+// 设计决策: 按字典序排序并去重后逐个 Lock。所有调用方按同一全序获取时不会形成循环等待，
+// 从而避免死锁；重复 key 会让同一调用自我阻塞（etcd 同工厂同 key 只允许一个 handle），因此去重。
+// 任一 key 获取失败时逆序释放已获取的锁再返回，不留下部分持有的状态。
+func lockMany(ctx context.Context, keys []string, opts []MutexOption, lock lockFunc) (LockHandle, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	for _, key := range keys {
+		if err := validateKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	handles := make([]LockHandle, 0, len(sorted))
+	for _, key := range sorted {
+		h, err := lock(ctx, key, opts...)
+		if err != nil {
+			return nil, errors.Join(err, unlockAll(ctx, handles))
+		}
+		handles = append(handles, h)
+	}
+	return &multiLockHandle{handles: handles}, nil
+}
+
+// unlockAll 逆序释放 handles，返回合并后的错误。
+// Unlock 在 ctx 已取消时自动使用独立清理上下文，因此失败回滚不受调用方 ctx 影响。
+func unlockAll(ctx context.Context, handles []LockHandle) error {
+	var errs []error
+	for _, h := range slices.Backward(handles) {
+		if err := h.Unlock(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LockMany 阻塞式获取多个 key 的锁。
+func (f *redisFactory) LockMany(ctx context.Context, keys []string, opts ...MutexOption) (LockHandle, error) {
+	return lockMany(ctx, keys, opts, f.Lock)
+}
+
+// LockMany 阻塞式获取多个 key 的锁。
+func (f *etcdFactory) LockMany(ctx context.Context, keys []string, opts ...MutexOption) (LockHandle, error) {
+	return lockMany(ctx, keys, opts, f.Lock)
+}
+
+// multiLockHandle 实现 LockHandle 接口，聚合 LockMany 获取的各 key 的 handle（按获取顺序）。
+//
+// 各子 handle 仍分别记录在工厂的持有列表中，Held 按 key 逐条列出。
+type multiLockHandle struct {
+	handles    []LockHandle
+	unlocked   atomic.Bool
+	lost       lostNotifier
+	autoExtend autoExtender
+}
+
+// Unlock 逆序释放全部 key 的锁，返回各 key 释放错误的合并结果。
+//
+// 部分 key 释放失败时其余 key 仍会释放，失败的 key 由 TTL/Lease 兜底回收；
+// 重复调用返回 [ErrNotLocked]。
+func (h *multiLockHandle) Unlock(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Swap(true) {
+		return ErrNotLocked
+	}
+	h.autoExtend.halt()
+	return unlockAll(ctx, h.handles)
+}
+
+// Extend 续期全部 key 的锁，返回各 key 续期错误的合并结果。
+// 任一 key 返回 [ErrNotLocked] 时，errors.Is(err, ErrNotLocked) 为 true。
+func (h *multiLockHandle) Extend(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return ErrNotLocked
+	}
+	var errs []error
+	for _, sub := range h.handles {
+		if err := sub.Extend(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// TTL 返回各 key 剩余有效期的最小值；任一 key 查询失败即返回该错误。
+func (h *multiLockHandle) TTL(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return 0, ErrNotLocked
+	}
+	var minTTL time.Duration
+	for i, sub := range h.handles {
+		ttl, err := sub.TTL(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if i == 0 || ttl < minTTL {
+			minTTL = ttl
+		}
+	}
+	return minTTL, nil
+}
+
+// Lost 返回锁丢失通知 channel，任一 key 的锁丢失时关闭。
+func (h *multiLockHandle) Lost() <-chan struct{} {
+	return h.lost.watch(func(stop <-chan struct{}) {
+		for _, sub := range h.handles {
+			go func(lost <-chan struct{}) {
+				select {
+				case <-lost:
+					h.lost.signal()
+				case <-stop:
+				}
+			}(sub.Lost())
+		}
+	})
+}
+
+// Key 返回按获取顺序以逗号连接的全部 key（包含前缀）。
+func (h *multiLockHandle) Key() string {
+	keys := make([]string, len(h.handles))
+	for i, sub := range h.handles {
+		keys[i] = sub.Key()
+	}
+	return strings.Join(keys, ",")
+}
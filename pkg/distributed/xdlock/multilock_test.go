@@ -0,0 +1,133 @@
+package xdlock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// LockMany 测试
+// =============================================================================
+
+func TestRedisFactory_LockMany(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.LockMany(ctx, []string{"b", "a", "b"})
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	assert.Equal(t, "lock:a,lock:b", h.Key())
+	assert.True(t, mr.Exists("lock:a"))
+	assert.True(t, mr.Exists("lock:b"))
+
+	held := f.Held()
+	require.Len(t, held, 2)
+	assert.Equal(t, "lock:a", held[0].Key)
+
+	require.NoError(t, h.Extend(ctx))
+	ttl, err := h.TTL(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, ttl)
+
+	require.NoError(t, h.Unlock(ctx))
+	assert.False(t, mr.Exists("lock:a"))
+	assert.False(t, mr.Exists("lock:b"))
+	assert.Empty(t, f.Held())
+
+	require.ErrorIs(t, h.Unlock(ctx), ErrNotLocked)
+	require.ErrorIs(t, h.Extend(ctx), ErrNotLocked)
+	_, err = h.TTL(ctx)
+	require.ErrorIs(t, err, ErrNotLocked)
+}
+
+func TestRedisFactory_LockMany_PartialFailure(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	blocker, err := f.TryLock(ctx, "b")
+	require.NoError(t, err)
+	require.NotNil(t, blocker)
+
+	h, err := f.LockMany(ctx, []string{"c", "a", "b"}, WithTries(2), WithRetryDelay(5*time.Millisecond))
+	require.ErrorIs(t, err, ErrLockHeld)
+	assert.Nil(t, h)
+
+	// 已获取的 a 被回滚，排在 b 之后的 c 未被获取
+	assert.False(t, mr.Exists("lock:a"))
+	assert.False(t, mr.Exists("lock:c"))
+	held := f.Held()
+	require.Len(t, held, 1)
+	assert.Equal(t, "lock:b", held[0].Key)
+	require.NoError(t, blocker.Unlock(ctx))
+}
+
+func TestRedisFactory_LockMany_InvalidArgs(t *testing.T) {
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = f.LockMany(nil, []string{"a"}) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+	_, err = f.LockMany(ctx, nil)
+	require.ErrorIs(t, err, ErrNoKeys)
+	_, err = f.LockMany(ctx, []string{"a", " "})
+	require.ErrorIs(t, err, ErrEmptyKey)
+	assert.Empty(t, f.Held(), "校验失败不应获取任何锁")
+}
+
+func TestRedisFactory_LockMany_Lost(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.LockMany(ctx, []string{"a", "b"}, WithLostPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	lost := h.Lost()
+	requireNotLost(t, lost)
+
+	mr.Set("lock:b", "someone-else")
+	requireLost(t, lost)
+	require.ErrorIs(t, h.Extend(ctx), ErrNotLocked)
+	require.ErrorIs(t, h.Unlock(ctx), ErrNotLocked)
+	assert.False(t, mr.Exists("lock:a"), "其余 key 仍应被释放")
+}
+
+func TestRedisFactory_LockMany_OppositeOrder(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	// 两组调用方以相反顺序传入 key，排序后获取顺序一致，不会相互等待成环
+	orders := [][]string{{"x", "y"}, {"y", "x"}}
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*20)
+	for _, keys := range orders {
+		wg.Go(func() {
+			for range 20 {
+				h, err := f.LockMany(ctx, keys, WithTries(500), WithRetryDelay(time.Millisecond))
+				if err != nil {
+					errs <- err
+					return
+				}
+				errs <- h.Unlock(ctx)
+			}
+		})
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
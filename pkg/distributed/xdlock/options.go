@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/omeyang/xkit/pkg/resilience/xretry"
 )
 
 // maxKeyLength 锁 key 的最大长度（字节）。
@@ -34,13 +35,18 @@ func validateKey(key string) error {
 // resolveFullKey 应用 MutexOption 并返回完整 key（prefix + key）。
 // 用于 etcd 后端在创建 Mutex 前解析最终 key，消除 TryLock/Lock 的选项解析重复。
 func resolveFullKey(key string, opts ...MutexOption) string {
+	return resolveMutexOptions(opts...).KeyPrefix + key
+}
+
+// resolveMutexOptions 在默认配置上应用 MutexOption（忽略 nil 选项）。
+func resolveMutexOptions(opts ...MutexOption) *mutexOptions {
 	options := defaultMutexOptions()
 	for _, opt := range opts {
 		if opt != nil {
 			opt(options)
 		}
 	}
-	return options.KeyPrefix + key
+	return options
 }
 
 // =============================================================================
@@ -114,6 +120,16 @@ type mutexOptions struct {
 	FailFast       bool // 快速失败，默认 false
 	ShufflePools   bool // 随机打乱 Pool 顺序，默认 false
 	SetNXOnExtend  bool // Extend 时使用 SETNX，默认 false
+	Reentrant      bool // 可重入模式，默认 false
+
+	// WatchLock 专用选项
+	WatchPollInterval time.Duration // 兜底轮询间隔，默认 1s
+
+	// LockHandle.Lost 专用选项（Redis 后端）
+	LostPollInterval time.Duration // 所有权轮询间隔，默认 1s
+
+	// TryLockWithWait 专用选项
+	WaitBackoff xretry.BackoffPolicy // 重试间隔策略，默认指数退避（50ms 起，上限 1s）
 }
 
 // defaultMutexOptions 返回默认的锁实例配置。
@@ -128,6 +144,9 @@ func defaultMutexOptions() *mutexOptions {
 		FailFast:      false,
 		ShufflePools:  false,
 		SetNXOnExtend: false,
+
+		WatchPollInterval: defaultWatchPollInterval,
+		LostPollInterval:  defaultLostPollInterval,
 	}
 }
 
@@ -270,6 +289,93 @@ func WithSetNXOnExtend(b bool) MutexOption {
 	}
 }
 
+// WithReentrant 开启可重入模式（仅 Redis 单节点工厂，etcd 后端忽略）。
+// 默认值：关闭。
+//
+// 重入以 owner token 为单位，而非 goroutine：使用相同 token（见 [WithOwnerToken]）
+// 的获取视为同一持有者，持有计数加一并刷新 TTL；每个返回的 handle 各自 Unlock 一次，
+// 计数归零时才删除锁 key。未指定 token 时每次获取生成随机 token，互相之间不会重入。
+//
+// 注意：Unlock 次数少于获取次数（漏调 Unlock、panic 跳过 defer 等）时，
+// 锁会一直被持有直到 TTL 到期；同一 key 不应混用可重入与非可重入模式。
+// 需要 Lua 脚本支持，多节点（Redlock）或代理兼容模式下获取锁返回 [ErrReentrantUnsupported]。
+func WithReentrant() MutexOption {
+	return func(o *mutexOptions) {
+		o.Reentrant = true
+	}
+}
+
+// WithOwnerToken 设置锁的持有者标识，等价于返回固定值的 [WithGenValueFunc]。
+// 空字符串被忽略。
+//
+// 主要配合 [WithReentrant] 使用：同一递归调用链内传入相同 token 即可重入。
+// token 应在持有者之间唯一（如请求 ID + 业务 key），
+// 非可重入模式下多个获取共用同一 token 会导致所有权混淆。
+func WithOwnerToken(token string) MutexOption {
+	return func(o *mutexOptions) {
+		if token != "" {
+			o.GenValueFunc = func() (string, error) { return token, nil }
+		}
+	}
+}
+
+// =============================================================================
+// WatchLock 专用选项
+// =============================================================================
+
+// WithWatchPollInterval 设置 WatchLock 的兜底轮询间隔。
+// 默认值：1 秒。非正值被忽略。
+//
+// 后端通知不可靠时（Redis 未开启 keyspace notification、Pub/Sub 断线，
+// etcd Watch 因压缩或网络中断重建），WatchLock 依靠此轮询在一个周期内感知状态变化。
+// 间隔越短感知越快，但对后端的查询压力越大。
+func WithWatchPollInterval(d time.Duration) MutexOption {
+	return func(o *mutexOptions) {
+		if d > 0 {
+			o.WatchPollInterval = d
+		}
+	}
+}
+
+// =============================================================================
+// LockHandle.Lost 专用选项
+// =============================================================================
+
+// WithLostPollInterval 设置 Redis 后端 [LockHandle.Lost] 的所有权轮询间隔。
+// 默认值：1 秒。非正值被忽略。etcd 后端由 Session 通知，不使用此选项。
+//
+// 后台 watcher 每个周期查询一次锁 key 的 value 与 PTTL，
+// 因此锁丢失最多延迟一个周期被感知。间隔应明显小于 Expiry，
+// 间隔越短感知越快，但每把锁对 Redis 的查询压力越大。
+func WithLostPollInterval(d time.Duration) MutexOption {
+	return func(o *mutexOptions) {
+		if d > 0 {
+			o.LostPollInterval = d
+		}
+	}
+}
+
+// =============================================================================
+// TryLockWithWait 专用选项
+// =============================================================================
+
+// WithWaitBackoff 设置 TryLockWithWait 两次尝试之间的退避策略。
+// 默认值：指数退避，初始 50ms，上限 1s，10% 抖动。nil 被忽略。
+//
+// 实际等待时间不会超过剩余的 maxWait，最后一次尝试恰好发生在 maxWait 到期时。
+//
+// 示例：
+//
+//	handle, err := factory.TryLockWithWait(ctx, "job", 5*time.Second,
+//	    xdlock.WithWaitBackoff(xretry.NewFixedBackoff(100*time.Millisecond)))
+func WithWaitBackoff(b xretry.BackoffPolicy) MutexOption {
+	return func(o *mutexOptions) {
+		if b != nil {
+			o.WaitBackoff = b
+		}
+	}
+}
+
 // =============================================================================
 // Redis 工厂选项
 // =============================================================================
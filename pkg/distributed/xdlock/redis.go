@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-redsync/redsync/v4"
 	rsredis "github.com/go-redsync/redsync/v4/redis"
@@ -21,7 +22,9 @@ import (
 type redisFactory struct {
 	clients []redis.UniversalClient
 	rs      *redsync.Redsync
+	mode    rediscompat.ScriptMode
 	closed  atomic.Bool
+	held    heldLocks
 }
 
 // NewRedisFactory 创建 Redis 锁工厂。
@@ -76,6 +79,7 @@ func NewRedisFactoryWithOpts(clients []redis.UniversalClient, opts ...RedisFacto
 	return &redisFactory{
 		clients: append([]redis.UniversalClient(nil), clients...),
 		rs:      rs,
+		mode:    scriptMode,
 	}, nil
 }
 
@@ -104,7 +108,11 @@ func (f *redisFactory) TryLock(ctx context.Context, key string, opts ...MutexOpt
 		return nil, err
 	}
 
-	mutex, fullKey := f.createMutex(key, opts...)
+	options := resolveMutexOptions(opts...)
+	if options.Reentrant {
+		return f.acquireReentrant(ctx, options.KeyPrefix+key, options, false)
+	}
+	mutex := f.createMutex(options.KeyPrefix+key, options)
 
 	if err := mutex.TryLockContext(ctx); err != nil {
 		err = wrapRedisError(err)
@@ -114,11 +122,7 @@ func (f *redisFactory) TryLock(ctx context.Context, key string, opts ...MutexOpt
 		return nil, err
 	}
 
-	return &redisLockHandle{
-		factory: f,
-		mutex:   mutex,
-		key:     fullKey,
-	}, nil
+	return f.newHandle(mutex, options), nil
 }
 
 // Lock 阻塞式获取锁，返回 LockHandle。
@@ -133,7 +137,11 @@ func (f *redisFactory) Lock(ctx context.Context, key string, opts ...MutexOption
 		return nil, err
 	}
 
-	mutex, fullKey := f.createMutex(key, opts...)
+	options := resolveMutexOptions(opts...)
+	if options.Reentrant {
+		return f.acquireReentrant(ctx, options.KeyPrefix+key, options, true)
+	}
+	mutex := f.createMutex(options.KeyPrefix+key, options)
 
 	if err := mutex.LockContext(ctx); err != nil {
 		// 设计决策: redsync 内部会将 context 错误包装在自定义类型中（如 ErrFailed），
@@ -146,25 +154,31 @@ func (f *redisFactory) Lock(ctx context.Context, key string, opts ...MutexOption
 		return nil, wrapRedisError(err)
 	}
 
-	return &redisLockHandle{
-		factory: f,
-		mutex:   mutex,
-		key:     fullKey,
-	}, nil
+	return f.newHandle(mutex, options), nil
 }
 
-// createMutex 创建 redsync.Mutex（内部方法）。
-// 返回 mutex 和完整的 key（包含前缀）。
-func (f *redisFactory) createMutex(key string, opts ...MutexOption) (*redsync.Mutex, string) {
-	options := defaultMutexOptions()
-	for _, opt := range opts {
-		if opt != nil {
-			opt(options)
-		}
-	}
+// newHandle 创建 LockHandle 并记录到持有列表（内部方法）。
+func (f *redisFactory) newHandle(mutex *redsync.Mutex, options *mutexOptions) *redisLockHandle {
+	h := &redisLockHandle{
+		factory:          f,
+		mutex:            mutex,
+		key:              mutex.Name(),
+		acquiredAt:       time.Now(),
+		lostPollInterval: options.LostPollInterval,
+	}
+	h.expiresAt.Store(mutex.Until().UnixNano())
+	f.held.add(h)
+	return h
+}
 
-	fullKey := options.KeyPrefix + key
+// Held 返回当前进程通过本工厂持有的锁。
+func (f *redisFactory) Held() []HeldLock {
+	return f.held.list()
+}
 
+// createMutex 按解析后的选项创建 redsync.Mutex（内部方法）。
+// fullKey 为包含前缀的完整 key。
+func (f *redisFactory) createMutex(fullKey string, options *mutexOptions) *redsync.Mutex {
 	// 构建 redsync 选项
 	rsOpts := make([]redsync.Option, 0, 10)
 	rsOpts = append(rsOpts, redsync.WithExpiry(options.Expiry))
@@ -187,7 +201,99 @@ func (f *redisFactory) createMutex(key string, opts ...MutexOption) (*redsync.Mu
 		rsOpts = append(rsOpts, redsync.WithSetNXOnExtend())
 	}
 
-	return f.rs.NewMutex(fullKey, rsOpts...), fullKey
+	return f.rs.NewMutex(fullKey, rsOpts...)
+}
+
+// WatchLock 监听锁状态变化。
+//
+// 基于 Redis keyspace notification 唤醒探测，需服务端开启 notify-keyspace-events
+// （至少包含 "Kg$x"：keyspace、通用命令、字符串命令、过期事件）。
+// 未开启时退化为纯轮询，语义不变。
+//
+// 多节点（Redlock）时以过半节点存在锁 key 作为"已获取"判定，与 Redlock 获取语义一致。
+// Redis Cluster 的 keyspace notification 仅在 key 所在节点发布，订阅可能落在其他节点，
+// 此时同样依赖兜底轮询。
+func (f *redisFactory) WatchLock(ctx context.Context, key string, opts ...MutexOption) (<-chan LockEvent, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if f.closed.Load() {
+		return nil, ErrFactoryClosed
+	}
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	options := resolveMutexOptions(opts...)
+	fullKey := options.KeyPrefix + key
+
+	wake := make(chan struct{}, 1)
+	subs := make([]*redis.PubSub, 0, len(f.clients))
+	for _, client := range f.clients {
+		subs = append(subs, client.Subscribe(ctx, keyspaceChannel(client, fullKey)))
+	}
+
+	out := make(chan LockEvent, 1)
+	go func() {
+		// 设计决策: PubSub 在监听循环退出后关闭，其 Channel() 随之关闭，转发 goroutine 自然退出。
+		defer func() {
+			for _, ps := range subs {
+				_ = ps.Close() //nolint:errcheck // 关闭订阅失败无法补救，监听已结束
+			}
+		}()
+		for _, ps := range subs {
+			go forwardKeyspaceEvents(ps.Channel(), wake)
+		}
+		runLockWatch(ctx, fullKey, options.WatchPollInterval, f.probeLock(fullKey), wake, out)
+	}()
+	return out, nil
+}
+
+// probeLock 返回探测锁是否被持有的函数：过半节点存在 key 即视为持有。
+func (f *redisFactory) probeLock(fullKey string) lockProbe {
+	quorum := len(f.clients)/2 + 1
+	return func(ctx context.Context) (bool, error) {
+		if f.closed.Load() {
+			return false, ErrFactoryClosed
+		}
+		var (
+			present int
+			errs    []error
+		)
+		for _, client := range f.clients {
+			n, err := client.Exists(ctx, fullKey).Result()
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if n > 0 {
+				present++
+			}
+		}
+		// 可达节点不足以判定多数派时视为探测失败，保持上一状态。
+		if len(f.clients)-len(errs) < quorum {
+			return false, errors.Join(errs...)
+		}
+		return present >= quorum, nil
+	}
+}
+
+// keyspaceChannel 返回 key 的 keyspace notification 频道名。
+// 仅单节点客户端可获取 DB 编号，Cluster/Ring 等固定使用 DB 0。
+func keyspaceChannel(client redis.UniversalClient, fullKey string) string {
+	db := 0
+	if c, ok := client.(*redis.Client); ok {
+		db = c.Options().DB
+	}
+	return fmt.Sprintf("__keyspace@%d__:%s", db, fullKey)
+}
+
+// forwardKeyspaceEvents 将 keyspace 消息转为唤醒信号，直到 ch 关闭。
+// 不解析事件内容（set/del/expired/pexpire 等），一律触发重新探测。
+func forwardKeyspaceEvents(ch <-chan *redis.Message, wake chan<- struct{}) {
+	for range ch {
+		notifyWake(wake)
+	}
 }
 
 // Close 关闭工厂。
@@ -234,10 +340,19 @@ func (f *redisFactory) Redsync() Redsync {
 // redisLockHandle 实现 LockHandle 接口。
 // 每次成功获取锁时创建，封装了唯一的锁标识。
 type redisLockHandle struct {
-	factory  *redisFactory
-	mutex    *redsync.Mutex
-	key      string
-	unlocked atomic.Bool // 标记锁是否已被显式释放，与 etcd 后端对称
+	factory    *redisFactory
+	mutex      *redsync.Mutex
+	key        string
+	unlocked   atomic.Bool // 标记锁是否已被显式释放，与 etcd 后端对称
+	acquiredAt time.Time
+	// expiresAt 本地估计的过期时间（UnixNano），获取和续期成功后更新。
+	// 设计决策: 不直接读取 mutex.Until()，redsync 在 Extend 时无锁写入该字段，
+	// 与 Held 的并发读取会产生数据竞争。
+	expiresAt atomic.Int64
+
+	lost             lostNotifier
+	lostPollInterval time.Duration
+	autoExtend       autoExtender
 }
 
 // Unlock 释放锁。
@@ -257,6 +372,7 @@ func (h *redisLockHandle) Unlock(ctx context.Context) error {
 	if h.unlocked.Load() {
 		return ErrNotLocked
 	}
+	h.autoExtend.halt()
 
 	// 当业务 ctx 已取消/超时时，使用独立清理上下文确保解锁能完成
 	if ctx.Err() != nil {
@@ -275,7 +391,7 @@ func (h *redisLockHandle) Unlock(ctx context.Context) error {
 			// 设计决策: Redis 返回的 expired/taken 是确定性结论（Lua 脚本执行成功），
 			// 与网络错误不同，此时 handle 确实已不持有锁，设置 unlocked 标记
 			// 防止后续 Extend 发送无意义的 Redis 请求。
-			h.unlocked.Store(true)
+			h.markUnlocked()
 			return ErrNotLocked
 		}
 		return wrappedErr
@@ -284,16 +400,23 @@ func (h *redisLockHandle) Unlock(ctx context.Context) error {
 		// 设计决策: UnlockContext 返回 (false, nil) 意味着 Lua 脚本执行成功但
 		// 解锁未命中（锁已被其他持有者抢走或过期）。这是确定性结论，handle 已
 		// 不持有锁，设置 unlocked 标记与 errLockExpired/ErrLockHeld 路径保持对称。
-		h.unlocked.Store(true)
+		h.markUnlocked()
 		return ErrNotLocked
 	}
 	// 设计决策: unlocked 标记放在成功解锁之后，与 etcd 后端保持一致。
 	// 网络抖动时 Unlock 可能失败但锁仍由 TTL 保护，
 	// 此时 Extend 应继续报告锁状态正常，而非错误返回 ErrNotLocked。
-	h.unlocked.Store(true)
+	h.markUnlocked()
 	return nil
 }
 
+// markUnlocked 标记 handle 已不再持有锁，并从工厂的持有列表移除。
+func (h *redisLockHandle) markUnlocked() {
+	h.unlocked.Store(true)
+	h.factory.held.remove(h)
+	h.lost.signal()
+}
+
 // Extend 续期锁。
 //
 // 设计决策: 允许在 factory 关闭后续期，与 Unlock 保持一致。
@@ -314,13 +437,16 @@ func (h *redisLockHandle) Extend(ctx context.Context) error {
 		wrappedErr := wrapRedisError(err)
 		// 锁已过期/被抢走 → 所有权已丢失
 		if errors.Is(wrappedErr, errLockExpired) || errors.Is(wrappedErr, ErrLockHeld) {
+			h.lost.signal()
 			return ErrNotLocked
 		}
 		return wrappedErr
 	}
 	if !ok {
+		h.lost.signal()
 		return ErrNotLocked
 	}
+	h.expiresAt.Store(h.mutex.Until().UnixNano())
 	return nil
 }
 
@@ -329,6 +455,17 @@ func (h *redisLockHandle) Key() string {
 	return h.key
 }
 
+// heldInfo 返回 handle 的持有信息。
+func (h *redisLockHandle) heldInfo() HeldLock {
+	return HeldLock{
+		Key:        h.key,
+		Backend:    BackendRedis,
+		Mode:       LockModeMutex,
+		AcquiredAt: h.acquiredAt,
+		ExpiresAt:  time.Unix(0, h.expiresAt.Load()),
+	}
+}
+
 // =============================================================================
 // 错误转换
 // =============================================================================
@@ -0,0 +1,236 @@
+package xdlock
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// Redis 可重入锁
+// =============================================================================
+
+// 可重入锁 Lua 脚本。
+//
+// 数据结构：fullKey 为 HASH，field 为 owner token，value 为持有计数，PX 为最近一次获取/续期的 Expiry。
+// 锁 key 为 STRING（非可重入模式持有）时视为被他人占用，保证两种模式在同一 key 上仍互斥。
+var (
+	// KEYS[1]=key ARGV[1]=token ARGV[2]=ttl(ms)
+	reentrantLockScript = redis.NewScript(`
+local t = redis.call('TYPE', KEYS[1]).ok
+if t == 'none' or (t == 'hash' and redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1) then
+	local n = redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+	redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[2]))
+	return n
+end
+return 0
+`)
+
+	// KEYS[1]=key ARGV[1]=token
+	// 返回剩余计数，-1 表示未持有
+	reentrantUnlockScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok ~= 'hash' or redis.call('HEXISTS', KEYS[1], ARGV[1]) == 0 then
+	return -1
+end
+local n = redis.call('HINCRBY', KEYS[1], ARGV[1], -1)
+if n <= 0 then
+	redis.call('DEL', KEYS[1])
+	return 0
+end
+return n
+`)
+
+	// KEYS[1]=key ARGV[1]=token ARGV[2]=ttl(ms)
+	reentrantExtendScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'hash' and redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1 then
+	return redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[2]))
+end
+return 0
+`)
+
+	// KEYS[1]=key ARGV[1]=token
+	// 返回 PTTL（毫秒），-1 表示未持有
+	reentrantTTLScript = redis.NewScript(`
+if redis.call('TYPE', KEYS[1]).ok == 'hash' and redis.call('HEXISTS', KEYS[1], ARGV[1]) == 1 then
+	return redis.call('PTTL', KEYS[1])
+end
+return -1
+`)
+)
+
+// acquireReentrant 以可重入模式获取锁：参数校验、生成 owner token、按需重试。
+//
+// 设计决策: 与读写锁一致仅支持单节点。Redlock 的多数派仲裁针对单值互斥锁设计，
+// 持有计数在多节点间部分成功时无法可靠回滚；代理兼容模式无法原子地完成"检查 + 计数"。
+func (f *redisFactory) acquireReentrant(ctx context.Context, fullKey string, options *mutexOptions, block bool) (LockHandle, error) {
+	if len(f.clients) != 1 || f.mode == rediscompat.ScriptModeCompat {
+		return nil, ErrReentrantUnsupported
+	}
+
+	genValue := options.GenValueFunc
+	if genValue == nil {
+		genValue = genRWLockID
+	}
+	token, err := genValue()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &reentrantLockHandle{
+		factory:          f,
+		client:           f.clients[0],
+		key:              fullKey,
+		token:            token,
+		expiry:           options.Expiry,
+		lostPollInterval: options.LostPollInterval,
+	}
+
+	tries := 1
+	if block {
+		tries = options.Tries
+	}
+	for i := range tries {
+		if i > 0 {
+			if err := waitRetry(ctx, retryDelay(options, i)); err != nil {
+				return nil, err
+			}
+		}
+		n, err := reentrantLockScript.Run(ctx, h.client, []string{h.key}, h.token, h.expiry.Milliseconds()).Int64()
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, fmt.Errorf("xdlock: reentrant lock: %w", err)
+		}
+		if n > 0 {
+			h.acquiredAt = time.Now()
+			h.expiresAt.Store(h.acquiredAt.Add(h.expiry).UnixNano())
+			f.held.add(h)
+			return h, nil
+		}
+	}
+	if !block {
+		return nil, nil // 锁被其他持有者占用，返回 (nil, nil)
+	}
+	return nil, ErrLockFailed
+}
+
+// reentrantLockHandle 实现 LockHandle 接口，表示可重入锁的一次获取（持有计数中的一份）。
+type reentrantLockHandle struct {
+	factory  *redisFactory
+	client   redis.UniversalClient
+	key      string
+	token    string
+	expiry   time.Duration
+	unlocked atomic.Bool
+
+	acquiredAt time.Time
+	expiresAt  atomic.Int64 // 本地估计的过期时间（UnixNano），获取和续期成功后更新
+
+	lost             lostNotifier
+	lostPollInterval time.Duration
+	autoExtend       autoExtender
+}
+
+// Unlock 将持有计数减一，计数归零时删除锁 key。
+//
+// 每个 handle 只能 Unlock 一次，重复调用返回 [ErrNotLocked]，不会多扣其他获取的计数。
+// 设计决策: 与互斥锁一致，允许在 factory 关闭后解锁；ctx 已取消/超时时使用独立清理上下文。
+func (h *reentrantLockHandle) Unlock(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return ErrNotLocked
+	}
+	h.autoExtend.halt()
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+	}
+
+	n, err := reentrantUnlockScript.Run(ctx, h.client, []string{h.key}, h.token).Int64()
+	if err != nil {
+		return fmt.Errorf("xdlock: reentrant unlock: %w", err)
+	}
+	// 未命中为确定性结论（锁已过期或被覆盖），handle 不再持有锁
+	h.unlocked.Store(true)
+	h.factory.held.remove(h)
+	h.lost.signal()
+	if n < 0 {
+		return ErrNotLocked
+	}
+	return nil
+}
+
+// Extend 续期锁，续期时间为本次获取配置的 Expiry。
+//
+// 续期作用于整把锁（同一 token 的全部持有计数共享一个 TTL）。
+func (h *reentrantLockHandle) Extend(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return ErrNotLocked
+	}
+
+	ttl := h.expiry.Milliseconds()
+	n, err := reentrantExtendScript.Run(ctx, h.client, []string{h.key}, h.token, ttl).Int64()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExtendFailed, err)
+	}
+	if n != 1 {
+		h.lost.signal()
+		return ErrNotLocked
+	}
+	h.expiresAt.Store(time.Now().Add(h.expiry).UnixNano())
+	return nil
+}
+
+// TTL 返回锁的剩余有效期（同一 token 的全部持有计数共享）。
+func (h *reentrantLockHandle) TTL(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return 0, ErrNotLocked
+	}
+
+	ms, err := reentrantTTLScript.Run(ctx, h.client, []string{h.key}, h.token).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("xdlock: query ttl: %w", err)
+	}
+	if ms <= 0 {
+		return 0, ErrNotLocked
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// Lost 返回锁丢失通知 channel，轮询规则与互斥锁一致，见 [WithLostPollInterval]。
+func (h *reentrantLockHandle) Lost() <-chan struct{} {
+	return h.lost.watch(func(stop <-chan struct{}) {
+		pollLost(stop, h.lostPollInterval, h.TTL, &h.expiresAt, h.lost.signal)
+	})
+}
+
+// Key 返回锁的 key（包含前缀）。
+func (h *reentrantLockHandle) Key() string {
+	return h.key
+}
+
+// heldInfo 返回 handle 的持有信息。
+// 重入产生的每个 handle 各自列出一条记录。
+func (h *reentrantLockHandle) heldInfo() HeldLock {
+	return HeldLock{
+		Key:        h.key,
+		Backend:    BackendRedis,
+		Mode:       LockModeMutex,
+		AcquiredAt: h.acquiredAt,
+		ExpiresAt:  time.Unix(0, h.expiresAt.Load()),
+	}
+}
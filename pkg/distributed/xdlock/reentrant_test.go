@@ -0,0 +1,144 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+)
+
+// =============================================================================
+// 可重入锁测试
+// =============================================================================
+
+func TestRedisFactory_Reentrant(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	opts := []MutexOption{WithReentrant(), WithOwnerToken("req-1")}
+	h1, err := f.TryLock(ctx, "job", opts...)
+	require.NoError(t, err)
+	require.NotNil(t, h1)
+	h2, err := f.Lock(ctx, "job", opts...)
+	require.NoError(t, err)
+	require.NotNil(t, h2)
+	assert.Equal(t, "2", mr.HGet("lock:job", "req-1"))
+	assert.Len(t, f.Held(), 2)
+
+	// 其他 token 无法获取
+	other, err := f.TryLock(ctx, "job", WithReentrant(), WithOwnerToken("req-2"))
+	require.NoError(t, err)
+	assert.Nil(t, other)
+
+	// 计数未归零时锁仍被持有
+	require.NoError(t, h2.Unlock(ctx))
+	require.ErrorIs(t, h2.Unlock(ctx), ErrNotLocked, "重复 Unlock 不应多扣计数")
+	assert.Equal(t, "1", mr.HGet("lock:job", "req-1"))
+	ttl, err := h1.TTL(ctx)
+	require.NoError(t, err)
+	assert.Positive(t, ttl)
+
+	require.NoError(t, h1.Unlock(ctx))
+	assert.False(t, mr.Exists("lock:job"))
+	assert.Empty(t, f.Held())
+
+	other, err = f.TryLock(ctx, "job", WithReentrant(), WithOwnerToken("req-2"))
+	require.NoError(t, err)
+	require.NotNil(t, other)
+	require.NoError(t, other.Unlock(ctx))
+}
+
+func TestRedisFactory_Reentrant_RandomToken(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	// 未指定 token 时每次获取互不重入
+	h, err := f.TryLock(ctx, "job", WithReentrant())
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	again, err := f.TryLock(ctx, "job", WithReentrant())
+	require.NoError(t, err)
+	assert.Nil(t, again)
+
+	_, err = f.Lock(ctx, "job", WithReentrant(), WithTries(2), WithRetryDelay(5*time.Millisecond))
+	require.ErrorIs(t, err, ErrLockFailed)
+	require.NoError(t, h.Unlock(ctx))
+}
+
+func TestRedisFactory_Reentrant_MixedModes(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	re, err := f.TryLock(ctx, "mixed", WithReentrant(), WithOwnerToken("t"))
+	require.NoError(t, err)
+	require.NotNil(t, re)
+	plain, err := f.TryLock(ctx, "mixed")
+	require.NoError(t, err)
+	assert.Nil(t, plain, "非可重入获取应被可重入锁阻挡")
+	require.NoError(t, re.Unlock(ctx))
+
+	plain, err = f.TryLock(ctx, "mixed")
+	require.NoError(t, err)
+	require.NotNil(t, plain)
+	re, err = f.TryLock(ctx, "mixed", WithReentrant(), WithOwnerToken("t"))
+	require.NoError(t, err)
+	assert.Nil(t, re, "可重入获取应被非可重入锁阻挡")
+	require.NoError(t, plain.Unlock(ctx))
+}
+
+func TestRedisFactory_Reentrant_ExtendAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "job", WithReentrant(), WithOwnerToken("t"),
+		WithExpiry(2*time.Second), WithLostPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	mr.FastForward(time.Second)
+	require.NoError(t, h.Extend(ctx))
+	ttl, err := h.TTL(ctx)
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Second)
+
+	lost := h.Lost()
+	mr.FastForward(3 * time.Second)
+	requireLost(t, lost)
+	require.ErrorIs(t, h.Extend(ctx), ErrNotLocked)
+	_, err = h.TTL(ctx)
+	require.ErrorIs(t, err, ErrNotLocked)
+	require.ErrorIs(t, h.Unlock(ctx), ErrNotLocked)
+
+	require.ErrorIs(t, h.Unlock(nil), ErrNilContext) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, h.Extend(nil), ErrNilContext) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+}
+
+func TestRedisFactory_Reentrant_Unsupported(t *testing.T) {
+	ctx := context.Background()
+	_, c1 := newTestMiniredis(t)
+	_, c2 := newTestMiniredis(t)
+	_, c3 := newTestMiniredis(t)
+
+	multi, err := NewRedisFactory(c1, c2, c3)
+	require.NoError(t, err)
+	_, err = multi.TryLock(ctx, "job", WithReentrant())
+	require.ErrorIs(t, err, ErrReentrantUnsupported)
+
+	compat, err := NewRedisFactoryWithOpts([]redis.UniversalClient{c1}, WithRedisScriptMode(rediscompat.ScriptModeCompat))
+	require.NoError(t, err)
+	_, err = compat.Lock(ctx, "job", WithReentrant())
+	require.ErrorIs(t, err, ErrReentrantUnsupported)
+}
@@ -0,0 +1,568 @@
+package xdlock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// Redis 读写锁
+// =============================================================================
+
+// RWFactory 定义读写锁工厂接口（仅 Redis 单节点）。
+//
+// 读锁之间共享，写锁与任何读锁、写锁互斥。读锁和写锁均返回 [LockHandle]，
+// Unlock/Extend/Key 的语义与互斥锁一致。
+//
+// opts 中 KeyPrefix、Expiry、Tries、RetryDelay、RetryDelayFunc、GenValueFunc 生效，
+// Redlock 相关选项（DriftFactor、TimeoutFactor、FailFast 等）被忽略。
+type RWFactory interface {
+	// TryRLock 非阻塞式获取读锁。
+	// 存在写锁时返回 (nil, nil)，其余约定与 [Factory.TryLock] 相同。
+	TryRLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+	// RLock 阻塞式获取读锁，按 Tries/RetryDelay 重试直到写锁释放。
+	// 重试耗尽返回 [ErrLockFailed]，其余约定与 [Factory.Lock] 相同。
+	RLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+	// TryLock 非阻塞式获取写锁。
+	// 存在写锁或存活的读锁时返回 (nil, nil)。
+	TryLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+	// Lock 阻塞式获取写锁，按 Tries/RetryDelay 重试直到读锁全部释放且无其他写锁。
+	// 重试耗尽返回 [ErrLockFailed]。
+	Lock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+	// Held 返回当前进程持有的读锁和写锁，语义与 [Factory.Held] 相同。
+	Held() []HeldLock
+
+	// Close 关闭工厂，语义与 [Factory.Close] 相同：仅阻止创建新锁，
+	// 已持有的 handle 仍可 Unlock/Extend。
+	Close(ctx context.Context) error
+
+	// Health 对 Redis 执行 PING。
+	Health(ctx context.Context) error
+}
+
+// 读写锁 Lua 脚本。
+//
+// 数据结构（同一 hash tag，保证 Cluster 下落在同一 slot）：
+//   - {fullKey}:writer  写锁 STRING，值为持有者标识，PX 为 Expiry
+//   - {fullKey}:readers 读锁 ZSET，member 为持有者标识，score 为过期时间戳（毫秒）
+//
+// 读者计数即 readers 中未过期成员的数量。每个读者独立记录过期时间，
+// 崩溃未释放的读者在其 Expiry 到期后被后续操作剔除，不会永久阻塞写者。
+var (
+	// KEYS[1]=writer KEYS[2]=readers ARGV[1]=id ARGV[2]=now(ms) ARGV[3]=ttl(ms)
+	rwReadLockScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local now = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', now)
+redis.call('ZADD', KEYS[2], now + ttl, ARGV[1])
+if redis.call('PTTL', KEYS[2]) < ttl then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+end
+return 1
+`)
+
+	// KEYS[1]=readers ARGV[1]=id
+	rwReadUnlockScript = redis.NewScript(`
+return redis.call('ZREM', KEYS[1], ARGV[1])
+`)
+
+	// KEYS[1]=readers ARGV[1]=id ARGV[2]=now(ms) ARGV[3]=ttl(ms)
+	rwReadExtendScript = redis.NewScript(`
+local score = redis.call('ZSCORE', KEYS[1], ARGV[1])
+local now = tonumber(ARGV[2])
+local ttl = tonumber(ARGV[3])
+if not score or tonumber(score) <= now then
+	return 0
+end
+redis.call('ZADD', KEYS[1], 'XX', now + ttl, ARGV[1])
+if redis.call('PTTL', KEYS[1]) < ttl then
+	redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return 1
+`)
+
+	// KEYS[1]=writer KEYS[2]=readers ARGV[1]=id ARGV[2]=now(ms) ARGV[3]=ttl(ms)
+	rwWriteLockScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', tonumber(ARGV[2]))
+if redis.call('ZCARD', KEYS[2]) > 0 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', tonumber(ARGV[3]))
+return 1
+`)
+
+	// KEYS[1]=writer ARGV[1]=id
+	rwWriteUnlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+	// KEYS[1]=writer ARGV[1]=id ARGV[2]=ttl(ms)
+	rwWriteExtendScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], tonumber(ARGV[2]))
+end
+return 0
+`)
+)
+
+// rwFactory 实现 RWFactory 接口。
+type rwFactory struct {
+	client redis.UniversalClient
+	mode   rediscompat.ScriptMode
+	closed atomic.Bool
+	held   heldLocks
+}
+
+// NewRWFactory 创建 Redis 读写锁工厂。
+//
+// 设计决策: 仅支持单个客户端。Redlock 的多数派仲裁针对单值互斥锁设计，
+// 读者集合在多节点间无法做到同等的多数派语义，因此不提供多节点读写锁。
+// 需要多节点容错的互斥场景请使用 [NewRedisFactory]。
+//
+// opts 中的 ScriptMode 与 [NewRedisFactoryWithOpts] 一致，代理环境下自动切换为基础命令实现。
+func NewRWFactory(client redis.UniversalClient, opts ...RedisFactoryOption) (RWFactory, error) {
+	if client == nil {
+		return nil, ErrNilClient
+	}
+	cfg := &redisFactoryConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &rwFactory{
+		client: client,
+		mode:   resolveRedisScriptMode(cfg.ScriptMode, client),
+	}, nil
+}
+
+// TryRLock 非阻塞式获取读锁。
+func (f *rwFactory) TryRLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error) {
+	return f.acquire(ctx, key, false, false, opts)
+}
+
+// RLock 阻塞式获取读锁。
+func (f *rwFactory) RLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error) {
+	return f.acquire(ctx, key, false, true, opts)
+}
+
+// TryLock 非阻塞式获取写锁。
+func (f *rwFactory) TryLock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error) {
+	return f.acquire(ctx, key, true, false, opts)
+}
+
+// Lock 阻塞式获取写锁。
+func (f *rwFactory) Lock(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error) {
+	return f.acquire(ctx, key, true, true, opts)
+}
+
+// acquire 读写锁获取的公共流程：参数校验、生成持有者标识、按需重试。
+func (f *rwFactory) acquire(ctx context.Context, key string, write, block bool, opts []MutexOption) (LockHandle, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if f.closed.Load() {
+		return nil, ErrFactoryClosed
+	}
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	options := resolveMutexOptions(opts...)
+	genValue := options.GenValueFunc
+	if genValue == nil {
+		genValue = genRWLockID
+	}
+	id, err := genValue()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &rwLockHandle{
+		factory:          f,
+		key:              options.KeyPrefix + key,
+		id:               id,
+		expiry:           options.Expiry,
+		write:            write,
+		lostPollInterval: options.LostPollInterval,
+	}
+
+	tries := 1
+	if block {
+		tries = options.Tries
+	}
+	for i := range tries {
+		if i > 0 {
+			if err := waitRetry(ctx, retryDelay(options, i)); err != nil {
+				return nil, err
+			}
+		}
+		ok, err := h.tryAcquire(ctx)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
+		if ok {
+			h.acquiredAt = time.Now()
+			h.expiresAt.Store(h.acquiredAt.Add(h.expiry).UnixNano())
+			f.held.add(h)
+			return h, nil
+		}
+	}
+	if !block {
+		return nil, nil // 锁被占用，返回 (nil, nil)
+	}
+	return nil, ErrLockFailed
+}
+
+// Held 返回当前进程通过本工厂持有的读锁和写锁。
+func (f *rwFactory) Held() []HeldLock {
+	return f.held.list()
+}
+
+// Close 关闭工厂。
+// 注意：此方法不会关闭传入的 Redis 客户端，客户端的生命周期由调用者管理。
+func (f *rwFactory) Close(_ context.Context) error {
+	f.closed.Store(true)
+	return nil
+}
+
+// Health 健康检查。
+// 传入 nil ctx 返回 [ErrNilContext]。
+func (f *rwFactory) Health(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if f.closed.Load() {
+		return ErrFactoryClosed
+	}
+	if err := f.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("xdlock: health check: %w", err)
+	}
+	return nil
+}
+
+// retryDelay 返回第 tries 次重试前的等待时间，优先使用 RetryDelayFunc。
+func retryDelay(options *mutexOptions, tries int) time.Duration {
+	if options.RetryDelayFunc != nil {
+		return options.RetryDelayFunc(tries)
+	}
+	return options.RetryDelay
+}
+
+// waitRetry 等待 d 或 ctx 结束。
+func waitRetry(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// genRWLockID 生成随机持有者标识（与 redsync 默认值生成方式一致）。
+func genRWLockID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// =============================================================================
+// 读写锁 LockHandle 实现
+// =============================================================================
+
+// rwLockHandle 实现 LockHandle 接口，表示一次读锁或写锁获取。
+type rwLockHandle struct {
+	factory  *rwFactory
+	key      string
+	id       string
+	expiry   time.Duration
+	write    bool
+	unlocked atomic.Bool
+
+	acquiredAt time.Time
+	expiresAt  atomic.Int64 // 本地估计的过期时间（UnixNano），获取和续期成功后更新
+
+	lost             lostNotifier
+	lostPollInterval time.Duration
+	autoExtend       autoExtender
+}
+
+// writerKey 返回写锁 key。
+// 使用 {fullKey} 作为 hash tag，使写锁与读者集合在 Cluster 下位于同一 slot。
+func (h *rwLockHandle) writerKey() string {
+	return "{" + h.key + "}:writer"
+}
+
+// readersKey 返回读者集合 key。
+func (h *rwLockHandle) readersKey() string {
+	return "{" + h.key + "}:readers"
+}
+
+// tryAcquire 尝试获取一次锁，返回是否成功。
+func (h *rwLockHandle) tryAcquire(ctx context.Context) (bool, error) {
+	now := time.Now().UnixMilli()
+	ttl := h.expiry.Milliseconds()
+	if h.factory.mode == rediscompat.ScriptModeCompat {
+		if h.write {
+			return h.writeLockCompat(ctx, now, ttl)
+		}
+		return h.readLockCompat(ctx, now, ttl)
+	}
+	var (
+		n   int64
+		err error
+	)
+	if h.write {
+		n, err = rwWriteLockScript.Run(ctx, h.factory.client,
+			[]string{h.writerKey(), h.readersKey()}, h.id, now, ttl).Int64()
+	} else {
+		n, err = rwReadLockScript.Run(ctx, h.factory.client,
+			[]string{h.writerKey(), h.readersKey()}, h.id, now, ttl).Int64()
+	}
+	return n == 1, err
+}
+
+// Unlock 释放锁。
+//
+// 设计决策: 与互斥锁一致，允许在 factory 关闭后解锁；ctx 已取消/超时时使用独立清理上下文。
+func (h *rwLockHandle) Unlock(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return ErrNotLocked
+	}
+	h.autoExtend.halt()
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+	}
+
+	var (
+		ok  bool
+		err error
+	)
+	switch {
+	case h.factory.mode == rediscompat.ScriptModeCompat:
+		ok, err = h.unlockCompat(ctx)
+	case h.write:
+		var n int64
+		n, err = rwWriteUnlockScript.Run(ctx, h.factory.client, []string{h.writerKey()}, h.id).Int64()
+		ok = n == 1
+	default:
+		var n int64
+		n, err = rwReadUnlockScript.Run(ctx, h.factory.client, []string{h.readersKey()}, h.id).Int64()
+		ok = n == 1
+	}
+	if err != nil {
+		return err
+	}
+	// 未命中为确定性结论（锁已过期或被覆盖），handle 不再持有锁
+	h.unlocked.Store(true)
+	h.factory.held.remove(h)
+	h.lost.signal()
+	if !ok {
+		return ErrNotLocked
+	}
+	return nil
+}
+
+// Extend 续期锁，续期时间为获取时配置的 Expiry。
+//
+// 读锁只续期本读者的过期时间，不影响其他读者。
+func (h *rwLockHandle) Extend(ctx context.Context) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return ErrNotLocked
+	}
+
+	now := time.Now().UnixMilli()
+	ttl := h.expiry.Milliseconds()
+	var (
+		ok  bool
+		err error
+	)
+	switch {
+	case h.factory.mode == rediscompat.ScriptModeCompat:
+		ok, err = h.extendCompat(ctx, now, ttl)
+	case h.write:
+		var n int64
+		n, err = rwWriteExtendScript.Run(ctx, h.factory.client, []string{h.writerKey()}, h.id, ttl).Int64()
+		ok = n == 1
+	default:
+		var n int64
+		n, err = rwReadExtendScript.Run(ctx, h.factory.client, []string{h.readersKey()}, h.id, now, ttl).Int64()
+		ok = n == 1
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrExtendFailed, err)
+	}
+	if !ok {
+		h.lost.signal()
+		return ErrNotLocked
+	}
+	h.expiresAt.Store(time.UnixMilli(now + ttl).UnixNano())
+	return nil
+}
+
+// Key 返回锁的 key（包含前缀）。
+func (h *rwLockHandle) Key() string {
+	return h.key
+}
+
+// heldInfo 返回 handle 的持有信息。
+func (h *rwLockHandle) heldInfo() HeldLock {
+	mode := LockModeRead
+	if h.write {
+		mode = LockModeWrite
+	}
+	return HeldLock{
+		Key:        h.key,
+		Backend:    BackendRedis,
+		Mode:       mode,
+		AcquiredAt: h.acquiredAt,
+		ExpiresAt:  time.Unix(0, h.expiresAt.Load()),
+	}
+}
+
+// =============================================================================
+// 兼容模式（无 Lua）
+//
+// 设计决策: 采用"先登记、后校验"：读者先加入读者集合再检查写锁，写者先 SET NX 再检查读者数，
+// 校验失败则撤回登记。任意读者与写者的登记都先于对方的校验，
+// 因此两者不可能同时成功；最坏情况是双方同时撤回，由 Lock/RLock 的重试兜底。
+// =============================================================================
+
+// readLockCompat 兼容模式获取读锁。
+func (h *rwLockHandle) readLockCompat(ctx context.Context, now, ttl int64) (bool, error) {
+	c := h.factory.client
+	readers := h.readersKey()
+	if err := c.ZRemRangeByScore(ctx, readers, "-inf", strconv.FormatInt(now, 10)).Err(); err != nil {
+		return false, err
+	}
+	if err := c.ZAdd(ctx, readers, redis.Z{Score: float64(now + ttl), Member: h.id}).Err(); err != nil {
+		return false, err
+	}
+	if err := h.ensureReadersTTLCompat(ctx, ttl); err != nil {
+		return false, err
+	}
+	n, err := c.Exists(ctx, h.writerKey()).Result()
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return false, c.ZRem(ctx, readers, h.id).Err()
+	}
+	return true, nil
+}
+
+// writeLockCompat 兼容模式获取写锁。
+func (h *rwLockHandle) writeLockCompat(ctx context.Context, now, ttl int64) (bool, error) {
+	c := h.factory.client
+	ok, err := c.SetNX(ctx, h.writerKey(), h.id, time.Duration(ttl)*time.Millisecond).Result()
+	if err != nil || !ok {
+		return false, err
+	}
+	readers := h.readersKey()
+	if err := c.ZRemRangeByScore(ctx, readers, "-inf", strconv.FormatInt(now, 10)).Err(); err != nil {
+		return false, errors.Join(err, h.releaseWriterCompat(ctx))
+	}
+	n, err := c.ZCard(ctx, readers).Result()
+	if err != nil || n > 0 {
+		return false, errors.Join(err, h.releaseWriterCompat(ctx))
+	}
+	return true, nil
+}
+
+// unlockCompat 兼容模式释放锁。
+func (h *rwLockHandle) unlockCompat(ctx context.Context) (bool, error) {
+	if !h.write {
+		n, err := h.factory.client.ZRem(ctx, h.readersKey(), h.id).Result()
+		return n == 1, err
+	}
+	val, err := h.factory.client.Get(ctx, h.writerKey()).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil || val != h.id {
+		return false, err
+	}
+	return true, h.factory.client.Del(ctx, h.writerKey()).Err()
+}
+
+// extendCompat 兼容模式续期。
+func (h *rwLockHandle) extendCompat(ctx context.Context, now, ttl int64) (bool, error) {
+	c := h.factory.client
+	if h.write {
+		val, err := c.Get(ctx, h.writerKey()).Result()
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		if err != nil || val != h.id {
+			return false, err
+		}
+		return c.PExpire(ctx, h.writerKey(), time.Duration(ttl)*time.Millisecond).Result()
+	}
+	score, err := c.ZScore(ctx, h.readersKey(), h.id).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil || int64(score) <= now {
+		return false, err
+	}
+	if err := c.ZAddXX(ctx, h.readersKey(), redis.Z{Score: float64(now + ttl), Member: h.id}).Err(); err != nil {
+		return false, err
+	}
+	return true, h.ensureReadersTTLCompat(ctx, ttl)
+}
+
+// releaseWriterCompat 撤回本 handle 登记的写锁（GET-then-DEL）。
+func (h *rwLockHandle) releaseWriterCompat(ctx context.Context) error {
+	_, err := h.unlockCompat(ctx)
+	return err
+}
+
+// ensureReadersTTLCompat 保证读者集合的 key TTL 不短于本读者的过期时间，
+// 避免较短 Expiry 的读者缩短集合寿命导致其他读者被提前清除。
+func (h *rwLockHandle) ensureReadersTTLCompat(ctx context.Context, ttl int64) error {
+	c := h.factory.client
+	readers := h.readersKey()
+	pttl, err := c.PTTL(ctx, readers).Result()
+	if err != nil {
+		return err
+	}
+	if pttl.Milliseconds() < ttl {
+		return c.PExpire(ctx, readers, time.Duration(ttl)*time.Millisecond).Err()
+	}
+	return nil
+}
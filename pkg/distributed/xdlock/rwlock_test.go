@@ -0,0 +1,181 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+)
+
+// =============================================================================
+// 读写锁测试
+// =============================================================================
+
+func TestRWFactory(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testRWFactory(t, mode)
+		})
+	}
+}
+
+func testRWFactory(t *testing.T, mode rediscompat.ScriptMode) {
+	ctx := context.Background()
+	newFactory := func(t *testing.T) RWFactory {
+		_, client := newTestMiniredis(t)
+		f, err := NewRWFactory(client, WithRedisScriptMode(mode))
+		require.NoError(t, err)
+		return f
+	}
+
+	t.Run("readers share", func(t *testing.T) {
+		f := newFactory(t)
+		r1, err := f.TryRLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, r1)
+		r2, err := f.TryRLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, r2)
+		assert.Equal(t, "lock:cache", r1.Key())
+
+		require.NoError(t, r1.Unlock(ctx))
+		require.NoError(t, r2.Unlock(ctx))
+	})
+
+	t.Run("writer waits for readers", func(t *testing.T) {
+		f := newFactory(t)
+		r, err := f.TryRLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, r)
+
+		w, err := f.TryLock(ctx, "cache")
+		require.NoError(t, err)
+		assert.Nil(t, w, "active reader blocks writer")
+
+		require.NoError(t, r.Unlock(ctx))
+		w, err = f.TryLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, w)
+
+		other, err := f.TryRLock(ctx, "cache")
+		require.NoError(t, err)
+		assert.Nil(t, other, "writer blocks readers")
+		other, err = f.TryLock(ctx, "cache")
+		require.NoError(t, err)
+		assert.Nil(t, other, "writer blocks writers")
+
+		require.NoError(t, w.Unlock(ctx))
+	})
+
+	t.Run("blocking lock acquires after release", func(t *testing.T) {
+		f := newFactory(t)
+		r, err := f.RLock(ctx, "cache")
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			_ = r.Unlock(context.Background())
+		}()
+		w, err := f.Lock(ctx, "cache", WithRetryDelay(10*time.Millisecond))
+		require.NoError(t, err)
+		require.NoError(t, w.Unlock(ctx))
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		f := newFactory(t)
+		w, err := f.Lock(ctx, "cache")
+		require.NoError(t, err)
+
+		_, err = f.RLock(ctx, "cache", WithTries(2), WithRetryDelay(time.Millisecond))
+		require.ErrorIs(t, err, ErrLockFailed)
+		require.NoError(t, w.Unlock(ctx))
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		f := newFactory(t)
+		w, err := f.Lock(ctx, "cache")
+		require.NoError(t, err)
+
+		cctx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		defer cancel()
+		_, err = f.RLock(cctx, "cache", WithRetryDelay(10*time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NoError(t, w.Unlock(ctx))
+	})
+
+	t.Run("leaked reader expires", func(t *testing.T) {
+		f := newFactory(t)
+		_, err := f.RLock(ctx, "cache", WithExpiry(30*time.Millisecond))
+		require.NoError(t, err)
+
+		time.Sleep(60 * time.Millisecond)
+		w, err := f.TryLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, w, "expired reader no longer blocks writer")
+		require.NoError(t, w.Unlock(ctx))
+	})
+
+	t.Run("extend and ownership", func(t *testing.T) {
+		f := newFactory(t)
+		r, err := f.RLock(ctx, "cache", WithExpiry(50*time.Millisecond))
+		require.NoError(t, err)
+		require.NoError(t, r.Extend(ctx))
+
+		require.NoError(t, r.Unlock(ctx))
+		require.ErrorIs(t, r.Unlock(ctx), ErrNotLocked)
+		require.ErrorIs(t, r.Extend(ctx), ErrNotLocked)
+
+		w, err := f.Lock(ctx, "cache")
+		require.NoError(t, err)
+		require.NoError(t, w.Extend(ctx))
+		require.NoError(t, w.Unlock(ctx))
+		require.ErrorIs(t, w.Extend(ctx), ErrNotLocked)
+	})
+
+	t.Run("expired reader cannot extend", func(t *testing.T) {
+		f := newFactory(t)
+		r, err := f.RLock(ctx, "cache", WithExpiry(20*time.Millisecond))
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+		require.ErrorIs(t, r.Extend(ctx), ErrNotLocked)
+	})
+
+	t.Run("unlock with canceled context", func(t *testing.T) {
+		f := newFactory(t)
+		w, err := f.Lock(ctx, "cache")
+		require.NoError(t, err)
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		require.NoError(t, w.Unlock(cctx))
+
+		r, err := f.TryRLock(ctx, "cache")
+		require.NoError(t, err)
+		require.NotNil(t, r)
+	})
+}
+
+func TestRWFactory_Validation(t *testing.T) {
+	_, err := NewRWFactory(nil)
+	require.ErrorIs(t, err, ErrNilClient)
+
+	_, client := newTestMiniredis(t)
+	f, err := NewRWFactory(client, WithRedisScriptMode(rediscompat.ScriptModeLua))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = f.TryRLock(nil, "k") //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+	_, err = f.Lock(ctx, " ")
+	require.ErrorIs(t, err, ErrEmptyKey)
+
+	require.NoError(t, f.Health(ctx))
+	require.NoError(t, f.Close(ctx))
+	_, err = f.RLock(ctx, "k")
+	require.ErrorIs(t, err, ErrFactoryClosed)
+	require.ErrorIs(t, f.Health(ctx), ErrFactoryClosed)
+}
@@ -0,0 +1,73 @@
+package xdlock
+
+import (
+	"context"
+	"time"
+
+	"github.com/omeyang/xkit/pkg/resilience/xretry"
+)
+
+// 默认等待退避参数：锁通常在毫秒到秒级释放，初始间隔短以降低获取延迟，
+// 上限 1s 避免锁释放后长时间空等。
+const (
+	defaultWaitInitialDelay = 50 * time.Millisecond
+	defaultWaitMaxDelay     = time.Second
+)
+
+// defaultWaitBackoff 返回 TryLockWithWait 的默认退避策略。
+func defaultWaitBackoff() xretry.BackoffPolicy {
+	return xretry.NewExponentialBackoff(
+		xretry.WithInitialDelay(defaultWaitInitialDelay),
+		xretry.WithMaxDelay(defaultWaitMaxDelay),
+	)
+}
+
+// tryLockFunc 单次非阻塞获取锁的函数签名（Factory.TryLock）。
+type tryLockFunc func(ctx context.Context, key string, opts ...MutexOption) (LockHandle, error)
+
+// tryLockWithWait TryLockWithWait 的公共实现，Redis 与 etcd 后端共用。
+//
+// 设计决策: 不以 maxWait 派生 ctx 传给 TryLock。单次 TryLock 被截断时后端可能已写入锁
+// 却无法返回 handle，只能等 TTL/Lease 回收；maxWait 仅约束"是否开始下一次尝试"。
+func tryLockWithWait(ctx context.Context, key string, maxWait time.Duration, opts []MutexOption, tryLock tryLockFunc) (LockHandle, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+
+	backoff := resolveMutexOptions(opts...).WaitBackoff
+	if backoff == nil {
+		backoff = defaultWaitBackoff()
+	}
+	deadline := time.Now().Add(maxWait)
+
+	for attempt := 1; ; attempt++ {
+		handle, err := tryLock(ctx, key, opts...)
+		if err != nil || handle != nil {
+			return handle, err
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil // 等待超时，锁仍被占用
+		}
+		delay := min(backoff.NextDelay(attempt), remaining)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// TryLockWithWait 有界等待式获取锁。
+func (f *redisFactory) TryLockWithWait(ctx context.Context, key string, maxWait time.Duration, opts ...MutexOption) (LockHandle, error) {
+	return tryLockWithWait(ctx, key, maxWait, opts, f.TryLock)
+}
+
+// TryLockWithWait 有界等待式获取锁。
+func (f *etcdFactory) TryLockWithWait(ctx context.Context, key string, maxWait time.Duration, opts ...MutexOption) (LockHandle, error) {
+	return tryLockWithWait(ctx, key, maxWait, opts, f.TryLock)
+}
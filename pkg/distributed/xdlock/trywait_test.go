@@ -0,0 +1,97 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/omeyang/xkit/pkg/resilience/xretry"
+)
+
+// =============================================================================
+// TryLockWithWait 测试
+// =============================================================================
+
+func newTestRedisFactory(t *testing.T) RedisFactory {
+	t.Helper()
+	_, client := newTestMiniredis(t)
+	f, err := NewRedisFactoryWithOpts([]redis.UniversalClient{client},
+		WithRedisScriptMode(rediscompat.ScriptModeLua))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = f.Close(context.Background()) })
+	return f
+}
+
+func TestTryLockWithWait_AcquiresAfterRelease(t *testing.T) {
+	f := newTestRedisFactory(t)
+	ctx := context.Background()
+
+	holder, err := f.TryLock(ctx, "job")
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	time.AfterFunc(50*time.Millisecond, func() { _ = holder.Unlock(context.Background()) })
+
+	h, err := f.TryLockWithWait(ctx, "job", time.Second,
+		WithWaitBackoff(xretry.NewFixedBackoff(10*time.Millisecond)))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+	require.NoError(t, h.Unlock(ctx))
+}
+
+func TestTryLockWithWait_TimeoutReturnsNil(t *testing.T) {
+	f := newTestRedisFactory(t)
+	ctx := context.Background()
+
+	holder, err := f.TryLock(ctx, "job")
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	defer func() { _ = holder.Unlock(ctx) }()
+
+	start := time.Now()
+	h, err := f.TryLockWithWait(ctx, "job", 60*time.Millisecond)
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+}
+
+func TestTryLockWithWait_NonPositiveMaxWait(t *testing.T) {
+	var calls int
+	h, err := tryLockWithWait(context.Background(), "job", 0, nil,
+		func(context.Context, string, ...MutexOption) (LockHandle, error) {
+			calls++
+			return nil, nil
+		})
+	require.NoError(t, err)
+	assert.Nil(t, h)
+	assert.Equal(t, 1, calls, "maxWait <= 0 behaves like a single TryLock")
+}
+
+func TestTryLockWithWait_BackendErrorStopsImmediately(t *testing.T) {
+	backendErr := errors.New("redis down")
+	var calls int
+	_, err := tryLockWithWait(context.Background(), "job", time.Second, nil,
+		func(context.Context, string, ...MutexOption) (LockHandle, error) {
+			calls++
+			return nil, backendErr
+		})
+	require.ErrorIs(t, err, backendErr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTryLockWithWait_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	_, err := tryLockWithWait(ctx, "job", time.Minute,
+		[]MutexOption{WithWaitBackoff(xretry.NewFixedBackoff(10 * time.Millisecond))},
+		func(context.Context, string, ...MutexOption) (LockHandle, error) { return nil, nil })
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = tryLockWithWait(nil, "job", time.Second, nil, nil) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+}
@@ -0,0 +1,147 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// LockHandle.TTL 实现
+// =============================================================================
+
+// TTL 返回锁的剩余有效期。
+//
+// 设计决策: 只统计 value 与本 handle 一致的节点，避免把其他持有者的 TTL 误报为自己的。
+// Redlock 下与获取锁一致按多数派判断：持有节点不足半数视为已失去锁；
+// 剩余时间取持有节点中的最小值，作为保守估计供续期决策使用。
+// 部分节点查询失败不影响结果，仅当无法凑齐多数派时返回最后一个查询错误。
+func (h *redisLockHandle) TTL(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return 0, ErrNotLocked
+	}
+
+	var (
+		held    int
+		minTTL  time.Duration
+		lastErr error
+	)
+	for _, client := range h.factory.clients {
+		ttl, owned, err := ownedPTTL(ctx, client, h.mutex.Name(), h.mutex.Value())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !owned {
+			continue
+		}
+		if held == 0 || ttl < minTTL {
+			minTTL = ttl
+		}
+		held++
+	}
+	if held < len(h.factory.clients)/2+1 {
+		if lastErr != nil {
+			return 0, fmt.Errorf("xdlock: query ttl: %w", lastErr)
+		}
+		return 0, ErrNotLocked
+	}
+	return minTTL, nil
+}
+
+// TTL 返回 Session Lease 的剩余有效期。
+//
+// 设计决策: etcd 锁的生命周期完全由 Session Lease 决定（KeepAlive 自动续期），
+// 因此直接查询 Lease TTL。etcd 以秒为单位返回，精度低于 Redis 后端。
+// Lease 已被撤销或过期时返回 [ErrSessionExpired]。
+func (h *etcdLockHandle) TTL(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return 0, ErrNotLocked
+	}
+	if err := h.factory.checkSession(); err != nil {
+		return 0, err
+	}
+
+	resp, err := h.factory.client.TimeToLive(ctx, h.factory.session.Lease())
+	if err != nil {
+		return 0, wrapEtcdError(err)
+	}
+	if resp.TTL <= 0 {
+		return 0, ErrSessionExpired
+	}
+	return time.Duration(resp.TTL) * time.Second, nil
+}
+
+// TTL 返回锁的剩余有效期。
+//
+// 写锁读取写锁 key 的 PTTL；读锁返回本读者在读者集合中的剩余时间，
+// 与其他读者的过期时间无关。
+func (h *rwLockHandle) TTL(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if h.unlocked.Load() {
+		return 0, ErrNotLocked
+	}
+
+	if h.write {
+		ttl, owned, err := ownedPTTL(ctx, h.factory.client, h.writerKey(), h.id)
+		if err != nil {
+			return 0, fmt.Errorf("xdlock: query ttl: %w", err)
+		}
+		if !owned {
+			return 0, ErrNotLocked
+		}
+		return ttl, nil
+	}
+
+	score, err := h.factory.client.ZScore(ctx, h.readersKey(), h.id).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, ErrNotLocked
+	}
+	if err != nil {
+		return 0, fmt.Errorf("xdlock: query ttl: %w", err)
+	}
+	remaining := time.Duration(int64(score)-time.Now().UnixMilli()) * time.Millisecond
+	if remaining <= 0 {
+		return 0, ErrNotLocked
+	}
+	return remaining, nil
+}
+
+// ownedPTTL 读取 key 的剩余时间，并校验其 value 是否为 value。
+// key 不存在、value 不匹配或未设置过期时间时 owned 为 false。
+//
+// 设计决策: 使用 GET + PTTL 管道而非 Lua 脚本，兼容不支持脚本的代理环境。
+// 两条命令之间 key 被替换的窗口极小，且结果仅用于观测与续期决策，不影响锁的安全性。
+func ownedPTTL(ctx context.Context, client redis.UniversalClient, key, value string) (time.Duration, bool, error) {
+	var (
+		getCmd *redis.StringCmd
+		ttlCmd *redis.DurationCmd
+	)
+	_, err := client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		getCmd = p.Get(ctx, key)
+		ttlCmd = p.PTTL(ctx, key)
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, false, err
+	}
+	if getCmd.Val() != value {
+		return 0, false, nil
+	}
+	ttl := ttlCmd.Val()
+	if ttl <= 0 {
+		return 0, false, nil
+	}
+	return ttl, true, nil
+}
@@ -0,0 +1,133 @@
+package xdlock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+)
+
+// =============================================================================
+// LockHandle.TTL 测试
+// =============================================================================
+
+func TestRedisLockHandle_TTL(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestMiniredis(t)
+	f, err := NewRedisFactory(client)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "job", WithExpiry(5*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	ttl, err := h.TTL(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 5*time.Second, ttl, float64(time.Second))
+
+	mr.FastForward(2 * time.Second)
+	ttl, err = h.TTL(ctx)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, 3*time.Second)
+
+	_, err = h.TTL(nil) //nolint:staticcheck // SA1012: nil ctx 是测试目标
+	require.ErrorIs(t, err, ErrNilContext)
+
+	// 被其他持有者覆盖后不再报告对方的 TTL
+	mr.Set("lock:job", "someone-else")
+	_, err = h.TTL(ctx)
+	require.ErrorIs(t, err, ErrNotLocked)
+
+	h2, err := f.TryLock(ctx, "job2")
+	require.NoError(t, err)
+	require.NoError(t, h2.Unlock(ctx))
+	_, err = h2.TTL(ctx)
+	require.ErrorIs(t, err, ErrNotLocked)
+}
+
+func TestRedisLockHandle_TTL_Redlock(t *testing.T) {
+	ctx := context.Background()
+	var (
+		servers []*miniredis.Miniredis
+		clients []redis.UniversalClient
+	)
+	for range 3 {
+		mr, client := newTestMiniredis(t)
+		servers = append(servers, mr)
+		clients = append(clients, client)
+	}
+	f, err := NewRedisFactory(clients...)
+	require.NoError(t, err)
+
+	h, err := f.TryLock(ctx, "job", WithExpiry(5*time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, h)
+
+	servers[0].FastForward(2 * time.Second)
+	ttl, err := h.TTL(ctx)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, 3*time.Second, "minimum across holding nodes")
+
+	// 单节点故障仍满足多数派
+	servers[1].Close()
+	_, err = h.TTL(ctx)
+	require.NoError(t, err)
+
+	// 持有节点不足多数派
+	servers[0].Del("lock:job")
+	_, err = h.TTL(ctx)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotLocked, "query failure is reported, not ownership loss")
+
+	servers[2].Del("lock:job")
+	_, err = h.TTL(ctx)
+	require.Error(t, err)
+}
+
+func TestRWLockHandle_TTL(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			ctx := context.Background()
+			_, client := newTestMiniredis(t)
+			f, err := NewRWFactory(client, WithRedisScriptMode(mode))
+			require.NoError(t, err)
+
+			r, err := f.RLock(ctx, "cache", WithExpiry(5*time.Second))
+			require.NoError(t, err)
+			ttl, err := r.TTL(ctx)
+			require.NoError(t, err)
+			assert.InDelta(t, 5*time.Second, ttl, float64(time.Second))
+			require.NoError(t, r.Unlock(ctx))
+			_, err = r.TTL(ctx)
+			require.ErrorIs(t, err, ErrNotLocked)
+
+			w, err := f.Lock(ctx, "cache", WithExpiry(5*time.Second))
+			require.NoError(t, err)
+			ttl, err = w.TTL(ctx)
+			require.NoError(t, err)
+			assert.InDelta(t, 5*time.Second, ttl, float64(time.Second))
+			require.NoError(t, w.Unlock(ctx))
+			_, err = w.TTL(ctx)
+			require.ErrorIs(t, err, ErrNotLocked)
+		})
+	}
+
+	t.Run("expired reader", func(t *testing.T) {
+		ctx := context.Background()
+		_, client := newTestMiniredis(t)
+		f, err := NewRWFactory(client, WithRedisScriptMode(rediscompat.ScriptModeLua))
+		require.NoError(t, err)
+
+		r, err := f.RLock(ctx, "cache", WithExpiry(20*time.Millisecond))
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+		_, err = r.TTL(ctx)
+		require.ErrorIs(t, err, ErrNotLocked)
+	})
+}
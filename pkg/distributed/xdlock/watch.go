@@ -0,0 +1,115 @@
+package xdlock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// =============================================================================
+// 锁事件监听
+// =============================================================================
+
+// LockEventType 锁事件类型。
+type LockEventType int
+
+const (
+	// LockEventAcquired 锁被（任意持有者）获取。
+	LockEventAcquired LockEventType = iota + 1
+
+	// LockEventReleased 锁被释放（显式 Unlock、TTL 过期或 Lease 撤销）。
+	LockEventReleased
+)
+
+// String 返回事件类型的可读名称。
+func (t LockEventType) String() string {
+	switch t {
+	case LockEventAcquired:
+		return "acquired"
+	case LockEventReleased:
+		return "released"
+	default:
+		return "unknown"
+	}
+}
+
+// LockEvent 表示一次锁状态变化。
+type LockEvent struct {
+	// Type 事件类型。
+	Type LockEventType
+
+	// Key 锁的完整 key（包含前缀）。
+	Key string
+}
+
+// defaultWatchPollInterval 默认兜底轮询间隔。
+const defaultWatchPollInterval = time.Second
+
+// lockProbe 查询锁当前是否被持有。
+// 返回 ErrFactoryClosed 时监听循环退出。
+type lockProbe func(ctx context.Context) (bool, error)
+
+// runLockWatch 运行锁状态监听循环，直到 ctx 取消或工厂关闭，退出时关闭 out。
+//
+// 设计决策: 后端通知（etcd Watch / Redis keyspace notification）仅作为唤醒信号，
+// 每次唤醒都重新探测锁的真实状态，只在状态变化时发出事件。这样：
+//   - 通知丢失、重复、乱序都不会产生错误事件
+//   - 兜底轮询与通知共用同一探测逻辑，通知不可用时仍能在一个轮询周期内感知变化
+//
+// 首个事件反映订阅时的当前状态，便于等待者在锁本就空闲时立即行动。
+// 探测失败时保持上一状态不发事件，等待下次唤醒或轮询重试。
+func runLockWatch(ctx context.Context, key string, interval time.Duration,
+	probe lockProbe, wake <-chan struct{}, out chan<- LockEvent) {
+	defer close(out)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		known bool // 是否已有确定状态
+		held  bool
+	)
+	check := func() bool {
+		cur, err := probe(ctx)
+		if err != nil {
+			return !errors.Is(err, ErrFactoryClosed)
+		}
+		if known && cur == held {
+			return true
+		}
+		known, held = true, cur
+		ev := LockEvent{Type: LockEventReleased, Key: key}
+		if cur {
+			ev.Type = LockEventAcquired
+		}
+		select {
+		case out <- ev:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if !check() {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-wake:
+		case <-ticker.C:
+		}
+		if !check() {
+			return
+		}
+	}
+}
+
+// notifyWake 非阻塞地投递唤醒信号（wake 容量为 1，多次通知合并为一次探测）。
+func notifyWake(wake chan<- struct{}) {
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,176 @@
+package xdlock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/pkg/distributed/xdlock"
+)
+
+// nextLockEvent 在超时内读取下一个锁事件。
+func nextLockEvent(t *testing.T, ch <-chan xdlock.LockEvent) xdlock.LockEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		require.True(t, ok, "watch channel closed unexpectedly")
+		return ev
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for lock event")
+		return xdlock.LockEvent{}
+	}
+}
+
+// waitWatchClosed 等待监听 channel 关闭（期间的事件被丢弃）。
+func waitWatchClosed(t *testing.T, ch <-chan xdlock.LockEvent) {
+	t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("watch channel not closed")
+		}
+	}
+}
+
+func TestLockEventType_String(t *testing.T) {
+	assert.Equal(t, "acquired", xdlock.LockEventAcquired.String())
+	assert.Equal(t, "released", xdlock.LockEventReleased.String())
+	assert.Equal(t, "unknown", xdlock.LockEventType(0).String())
+}
+
+func TestRedisFactory_WatchLock_WithMiniredis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	factory, err := xdlock.NewRedisFactory(client)
+	require.NoError(t, err)
+	defer func() { _ = factory.Close(context.Background()) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// miniredis 不支持 keyspace notification，此用例覆盖兜底轮询路径
+	events, err := factory.WatchLock(ctx, "watch-key", xdlock.WithWatchPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+
+	// 首个事件反映当前状态：空闲
+	ev := nextLockEvent(t, events)
+	assert.Equal(t, xdlock.LockEventReleased, ev.Type)
+	assert.Equal(t, "lock:watch-key", ev.Key)
+
+	handle, err := factory.TryLock(ctx, "watch-key")
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+	assert.Equal(t, xdlock.LockEventAcquired, nextLockEvent(t, events).Type)
+
+	require.NoError(t, handle.Unlock(ctx))
+	assert.Equal(t, xdlock.LockEventReleased, nextLockEvent(t, events).Type)
+
+	cancel()
+	waitWatchClosed(t, events)
+}
+
+func TestRedisFactory_WatchLock_Expiry_WithMiniredis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	factory, err := xdlock.NewRedisFactory(client)
+	require.NoError(t, err)
+	defer func() { _ = factory.Close(context.Background()) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handle, err := factory.TryLock(ctx, "expire-key", xdlock.WithExpiry(time.Second))
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+
+	events, err := factory.WatchLock(ctx, "expire-key", xdlock.WithWatchPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, xdlock.LockEventAcquired, nextLockEvent(t, events).Type)
+
+	// TTL 过期同样产生 Released 事件
+	mr.FastForward(2 * time.Second)
+	assert.Equal(t, xdlock.LockEventReleased, nextLockEvent(t, events).Type)
+}
+
+func TestRedisFactory_WatchLock_FactoryClose_StopsWatch_WithMiniredis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	factory, err := xdlock.NewRedisFactory(client)
+	require.NoError(t, err)
+
+	events, err := factory.WatchLock(context.Background(), "close-key", xdlock.WithWatchPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+	nextLockEvent(t, events)
+
+	require.NoError(t, factory.Close(context.Background()))
+	waitWatchClosed(t, events)
+
+	_, err = factory.WatchLock(context.Background(), "close-key")
+	assert.ErrorIs(t, err, xdlock.ErrFactoryClosed)
+}
+
+func TestRedisFactory_WatchLock_Quorum_WithMiniredis(t *testing.T) {
+	clients := make([]redis.UniversalClient, 3)
+	servers := make([]*miniredis.Miniredis, 3)
+	for i := range clients {
+		servers[i] = miniredis.RunT(t)
+		c := redis.NewClient(&redis.Options{Addr: servers[i].Addr()})
+		defer c.Close()
+		clients[i] = c
+	}
+
+	factory, err := xdlock.NewRedisFactory(clients...)
+	require.NoError(t, err)
+	defer func() { _ = factory.Close(context.Background()) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := factory.WatchLock(ctx, "quorum-key", xdlock.WithWatchPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, xdlock.LockEventReleased, nextLockEvent(t, events).Type)
+
+	// 单节点存在 key 不构成多数派，不应产生事件
+	require.NoError(t, servers[0].Set("lock:quorum-key", "v"))
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %v with minority", ev.Type)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	require.NoError(t, servers[1].Set("lock:quorum-key", "v"))
+	assert.Equal(t, xdlock.LockEventAcquired, nextLockEvent(t, events).Type)
+}
+
+func TestRedisFactory_WatchLock_InvalidArgs_WithMiniredis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	factory, err := xdlock.NewRedisFactory(client)
+	require.NoError(t, err)
+	defer func() { _ = factory.Close(context.Background()) }()
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err = factory.WatchLock(nil, "key")
+	assert.ErrorIs(t, err, xdlock.ErrNilContext)
+
+	_, err = factory.WatchLock(context.Background(), "  ")
+	assert.ErrorIs(t, err, xdlock.ErrEmptyKey)
+}
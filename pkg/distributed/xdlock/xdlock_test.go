@@ -125,7 +125,12 @@ type mockLockHandle struct{}
 
 func (m *mockLockHandle) Unlock(_ context.Context) error { return nil }
 func (m *mockLockHandle) Extend(_ context.Context) error { return nil }
-func (m *mockLockHandle) Key() string                    { return "" }
+func (m *mockLockHandle) TTL(_ context.Context) (time.Duration, error) {
+	return 0, nil
+}
+func (m *mockLockHandle) Lost() <-chan struct{}                         { return nil }
+func (m *mockLockHandle) StartAutoExtend(_ time.Duration) (stop func()) { return func() {} }
+func (m *mockLockHandle) Key() string                                   { return "" }
 
 // mockFactory 用于编译时接口检查。
 type mockFactory struct{}
@@ -136,8 +141,21 @@ func (m *mockFactory) TryLock(_ context.Context, _ string, _ ...xdlock.MutexOpti
 func (m *mockFactory) Lock(_ context.Context, _ string, _ ...xdlock.MutexOption) (xdlock.LockHandle, error) {
 	return nil, nil
 }
+func (m *mockFactory) TryLockWithWait(_ context.Context, _ string, _ time.Duration, _ ...xdlock.MutexOption) (xdlock.LockHandle, error) {
+	return nil, nil
+}
+func (m *mockFactory) LockMany(_ context.Context, _ []string, _ ...xdlock.MutexOption) (xdlock.LockHandle, error) {
+	return nil, nil
+}
+func (m *mockFactory) WatchLock(_ context.Context, _ string, _ ...xdlock.MutexOption) (<-chan xdlock.LockEvent, error) {
+	return nil, nil
+}
+func (m *mockFactory) Held() []xdlock.HeldLock        { return nil }
 func (m *mockFactory) Close(_ context.Context) error  { return nil }
 func (m *mockFactory) Health(_ context.Context) error { return nil }
+func (m *mockFactory) HealthDetail(_ context.Context) ([]xdlock.NodeHealth, error) {
+	return nil, nil
+}
 
 // mockEtcdFactory 用于编译时接口检查。
 type mockEtcdFactory struct{ mockFactory }
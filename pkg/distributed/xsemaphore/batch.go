@@ -0,0 +1,65 @@
+package xsemaphore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// =============================================================================
+// 批量获取（AcquireN）
+//
+// 一个包含 n 个名额的许可在后端占用 n 个成员：第一个成员使用许可 ID，
+// 其余成员为 "ID#1" ... "ID#(n-1)"。成员与普通许可共用同一集合，
+// 因此容量检查、Query、Inspect、公平队列和租户配额无需区分批量许可；
+// Release/Extend/Handoff 以第一个成员判断许可是否仍被持有，并同时作用于全部成员。
+// =============================================================================
+
+// permitMemberSeparator 批量许可成员 ID 的分隔符（与 Lua 脚本保持一致）
+const permitMemberSeparator = "#"
+
+// permitMemberIDs 返回许可占用的全部成员 ID
+// count <= 1 时只有许可 ID 本身
+func permitMemberIDs(id string, count int) []string {
+	if count <= 1 {
+		return []string{id}
+	}
+	ids := make([]string, count)
+	ids[0] = id
+	for i := 1; i < count; i++ {
+		ids[i] = id + permitMemberSeparator + strconv.Itoa(i)
+	}
+	return ids
+}
+
+// acquireN AcquireN 的公共实现：以指定数量调用 tryAcquire
+//
+// 设计决策: 批量获取复用 TryAcquire 的完整流程（校验、降级、指标、trace），
+// 数量通过内部选项传递并追加在用户选项之后，不会被覆盖。
+// 数量与容量、配额的关系在 acquireOptions.validate 中校验。
+func acquireN(ctx context.Context, resource string, n int, opts []AcquireOption, tryAcquire tryAcquireFunc) (Permit, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: count must be positive, got %d", ErrInvalidPermitCount, n)
+	}
+	if n > MaxPermitCount {
+		return nil, fmt.Errorf("%w: count (%d) cannot exceed %d", ErrInvalidPermitCount, n, MaxPermitCount)
+	}
+	opts = append(slices.Clip(opts), withPermitCount(n))
+	return tryAcquire(ctx, resource, opts...)
+}
+
+// AcquireN 原子地获取 n 个许可
+func (s *redisSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return acquireN(ctx, resource, n, opts, s.TryAcquire)
+}
+
+// AcquireN 原子地获取 n 个本地许可
+func (s *localSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return acquireN(ctx, resource, n, opts, s.TryAcquire)
+}
+
+// AcquireN 原子地获取 n 个许可，Redis 不可用时按降级策略处理
+func (f *fallbackSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return acquireN(ctx, resource, n, opts, f.TryAcquire)
+}
@@ -0,0 +1,236 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// 批量获取测试
+// =============================================================================
+
+func TestAcquireN_Redis(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testAcquireN(t, func(t *testing.T) Semaphore {
+				sem, _ := setupSemaphore(t, WithScriptMode(mode))
+				return sem
+			})
+		})
+	}
+}
+
+func TestAcquireN_Local(t *testing.T) {
+	testAcquireN(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	})
+}
+
+// globalUsed 查询资源当前的全局许可数
+func globalUsed(t *testing.T, sem Semaphore, resource string) int {
+	t.Helper()
+	info, err := sem.Query(context.Background(), resource, QueryWithCapacity(10))
+	require.NoError(t, err)
+	return info.GlobalUsed
+}
+
+func testAcquireN(t *testing.T, newSem func(t *testing.T) Semaphore) {
+	ctx := context.Background()
+
+	t.Run("all or nothing", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.AcquireN(ctx, "gpu", 3, WithCapacity(5))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, 3, globalUsed(t, sem, "gpu"))
+
+		// 剩余 2 个名额，不能容纳 3 个，不部分获取
+		none, err := sem.AcquireN(ctx, "gpu", 3, WithCapacity(5))
+		require.NoError(t, err)
+		assert.Nil(t, none)
+		assert.Equal(t, 3, globalUsed(t, sem, "gpu"))
+
+		rest, err := sem.AcquireN(ctx, "gpu", 2, WithCapacity(5))
+		require.NoError(t, err)
+		require.NotNil(t, rest)
+		releasePermit(t, ctx, rest)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("single release frees all", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.AcquireN(ctx, "gpu", 4, WithCapacity(4))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+
+		full, err := sem.TryAcquire(ctx, "gpu", WithCapacity(4))
+		require.NoError(t, err)
+		assert.Nil(t, full)
+
+		require.NoError(t, p.Release(ctx))
+		assert.Equal(t, 0, globalUsed(t, sem, "gpu"))
+		require.NoError(t, p.Release(ctx), "release is idempotent")
+	})
+
+	t.Run("tenant quota must fit all", func(t *testing.T) {
+		sem := newSem(t)
+		opts := []AcquireOption{WithCapacity(10), WithTenantQuota(3)}
+
+		p1, err := sem.AcquireN(ctx, "gpu", 2, append(opts, WithTenantID("t1"))...)
+		require.NoError(t, err)
+		require.NotNil(t, p1)
+
+		blocked, err := sem.AcquireN(ctx, "gpu", 2, append(opts, WithTenantID("t1"))...)
+		require.NoError(t, err)
+		assert.Nil(t, blocked, "tenant has 1 slot left")
+
+		p2, err := sem.AcquireN(ctx, "gpu", 2, append(opts, WithTenantID("t2"))...)
+		require.NoError(t, err)
+		require.NotNil(t, p2)
+
+		info, err := sem.Query(ctx, "gpu", QueryWithCapacity(10), QueryWithTenantID("t1"), QueryWithTenantQuota(3))
+		require.NoError(t, err)
+		assert.Equal(t, 4, info.GlobalUsed)
+		assert.Equal(t, 2, info.TenantUsed)
+
+		releasePermit(t, ctx, p1)
+		releasePermit(t, ctx, p2)
+		info, err = sem.Query(ctx, "gpu", QueryWithCapacity(10), QueryWithTenantID("t1"), QueryWithTenantQuota(3))
+		require.NoError(t, err)
+		assert.Equal(t, 0, info.GlobalUsed)
+		assert.Equal(t, 0, info.TenantUsed)
+	})
+
+	t.Run("extend renews all members", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.AcquireN(ctx, "gpu", 3, WithCapacity(5), WithTTL(time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		defer releasePermit(t, ctx, p)
+
+		time.Sleep(5 * time.Millisecond)
+		require.NoError(t, p.Extend(ctx))
+
+		permits, err := sem.Inspect(ctx, "gpu")
+		require.NoError(t, err)
+		require.Len(t, permits, 3)
+		ids := make([]string, len(permits))
+		for i, info := range permits {
+			ids[i] = info.ID
+			assert.WithinDuration(t, p.ExpiresAt(), info.ExpiresAt, time.Millisecond)
+		}
+		assert.ElementsMatch(t, []string{p.ID(), p.ID() + "#1", p.ID() + "#2"}, ids)
+	})
+
+	t.Run("handoff transfers all members", func(t *testing.T) {
+		sem := newSem(t)
+		old, err := sem.AcquireN(ctx, "gpu", 3, WithCapacity(3))
+		require.NoError(t, err)
+		require.NotNil(t, old)
+
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+		p, err := sem.TryAcquire(ctx, "gpu", WithCapacity(3), WithHandoffToken(token))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, 3, globalUsed(t, sem, "gpu"))
+
+		require.NoError(t, p.Release(ctx))
+		assert.Equal(t, 0, globalUsed(t, sem, "gpu"))
+	})
+
+	t.Run("invalid count", func(t *testing.T) {
+		sem := newSem(t)
+		for _, tc := range []struct {
+			name string
+			n    int
+			opts []AcquireOption
+		}{
+			{"zero", 0, []AcquireOption{WithCapacity(5)}},
+			{"negative", -1, []AcquireOption{WithCapacity(5)}},
+			{"exceeds capacity", 6, []AcquireOption{WithCapacity(5)}},
+			{"exceeds tenant quota", 3, []AcquireOption{WithCapacity(5), WithTenantID("t1"), WithTenantQuota(2)}},
+			{"exceeds max", MaxPermitCount + 1, []AcquireOption{WithCapacity(MaxPermitCount + 1)}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				p, err := sem.AcquireN(ctx, "gpu", tc.n, tc.opts...)
+				require.ErrorIs(t, err, ErrInvalidPermitCount)
+				assert.Nil(t, p)
+			})
+		}
+	})
+}
+
+// TestAcquireN_LargeCount 名额数超过 Lua unpack 的上限（约 8000）时仍能释放、续期和转移
+func TestAcquireN_LargeCount(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+	const n = MaxPermitCount
+	used := func(resource string) int {
+		info, err := sem.Query(ctx, resource, QueryWithCapacity(n))
+		require.NoError(t, err)
+		return info.GlobalUsed
+	}
+
+	p, err := sem.AcquireN(ctx, "gpu", n, WithCapacity(n), WithTenantID("t1"), WithTenantQuota(n))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	require.NoError(t, p.Extend(ctx))
+
+	token, err := p.Handoff(ctx)
+	require.NoError(t, err)
+	next, err := sem.TryAcquire(ctx, "gpu", WithCapacity(n), WithHandoffToken(token))
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, n, used("gpu"))
+
+	require.NoError(t, next.Release(ctx))
+	assert.Zero(t, used("gpu"))
+}
+
+func TestAcquireN_FairQueue(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+
+	p, err := sem.AcquireN(ctx, "gpu", 2, WithCapacity(3), WithFairQueue())
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	defer releasePermit(t, ctx, p)
+
+	none, err := sem.AcquireN(ctx, "gpu", 2, WithCapacity(3), WithFairQueue())
+	require.NoError(t, err)
+	assert.Nil(t, none)
+}
+
+func TestAcquireN_FallbackLocal(t *testing.T) {
+	sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+	mr.Close()
+	ctx := context.Background()
+
+	p, err := sem.AcquireN(ctx, "gpu", 2, WithCapacity(2))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+
+	none, err := sem.TryAcquire(ctx, "gpu", WithCapacity(2))
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	require.NoError(t, p.Release(ctx))
+	again, err := sem.TryAcquire(ctx, "gpu", WithCapacity(2))
+	require.NoError(t, err)
+	assert.NotNil(t, again)
+	releasePermit(t, ctx, again)
+}
+
+func TestPermitMemberIDs(t *testing.T) {
+	assert.Equal(t, []string{"p"}, permitMemberIDs("p", 0))
+	assert.Equal(t, []string{"p"}, permitMemberIDs("p", 1))
+	assert.Equal(t, []string{"p", "p#1", "p#2"}, permitMemberIDs("p", 3))
+}
@@ -0,0 +1,93 @@
+package xsemaphore
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// =============================================================================
+// 动态容量
+// =============================================================================
+
+// Capacity 可在运行时调整的全局容量
+//
+// 通过 [WithDynamicCapacity] / [QueryWithDynamicCapacity] 传入后，每次获取尝试都会读取当前值，
+// 配合 xconf 热更新即可在不重启服务的情况下扩缩容：
+//
+//	capacity, _ := xsemaphore.NewCapacity(cfg.Client().Int("sem.report.capacity"))
+//	xconf.Watch(cfg, func(c xconf.Config, err error) {
+//	    if err == nil {
+//	        _ = capacity.Set(c.Client().Int("sem.report.capacity"))
+//	    }
+//	})
+//	permit, err := sem.TryAcquire(ctx, "report", xsemaphore.WithDynamicCapacity(capacity))
+//
+// 缩容语义：已发放的许可不会被回收，继续有效直到 Release 或过期；
+// 已用数超过新容量期间，新的获取一律按容量已满处理，直到已用数回落到新容量以下。
+// Query 的 GlobalAvailable 在超发期间为 0。
+//
+// 设计决策: 容量仍由调用方在每次获取时传给后端，而不是写入 Redis 由 Lua 读取。
+// 这样 Lua 脚本、兼容模式（无脚本代理）、本地信号量与降级路径无需任何改动，
+// 语义完全一致；代价是各副本的配置生效时间不同步，切换瞬间以各自读到的容量为准，
+// 最终一致后恢复精确控制。
+//
+// Capacity 并发安全，零值不可用，必须通过 [NewCapacity] 创建。
+type Capacity struct {
+	v atomic.Int64
+}
+
+// NewCapacity 创建动态容量，capacity <= 0 时返回 [ErrInvalidCapacity]。
+func NewCapacity(capacity int) (*Capacity, error) {
+	c := &Capacity{}
+	if err := c.Set(capacity); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Set 原子更新容量，capacity <= 0 时返回 [ErrInvalidCapacity] 且保持原值。
+func (c *Capacity) Set(capacity int) error {
+	if capacity <= 0 {
+		return fmt.Errorf("%w: capacity must be positive, got %d", ErrInvalidCapacity, capacity)
+	}
+	c.v.Store(int64(capacity))
+	return nil
+}
+
+// Load 返回当前容量。
+func (c *Capacity) Load() int {
+	return int(c.v.Load())
+}
+
+// WithDynamicCapacity 使用动态容量作为全局容量上限
+//
+// 与 [WithCapacity] 互斥，后设置者生效。每次获取尝试（包括 Acquire 的重试和
+// WaitAcquire 的轮询）都会重新读取容量，长时间等待期间的扩容可立即生效。
+// c 为 nil 时忽略此选项。
+func WithDynamicCapacity(c *Capacity) AcquireOption {
+	return func(o *acquireOptions) {
+		if c != nil {
+			o.dynamicCapacity = c
+			o.capacity = c.Load()
+		}
+	}
+}
+
+// QueryWithDynamicCapacity 使用动态容量计算查询结果中的可用许可数
+//
+// 应与获取时使用的 [Capacity] 为同一实例，使 Query 与 Acquire 的容量一致。
+// c 为 nil 时忽略此选项。
+func QueryWithDynamicCapacity(c *Capacity) QueryOption {
+	return func(o *queryOptions) {
+		if c != nil {
+			o.capacity = c.Load()
+		}
+	}
+}
+
+// refreshCapacity 重新读取动态容量（重试前调用），未使用动态容量时为空操作
+func (o *acquireOptions) refreshCapacity() {
+	if o.dynamicCapacity != nil {
+		o.capacity = o.dynamicCapacity.Load()
+	}
+}
@@ -0,0 +1,139 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapacity(t *testing.T) {
+	_, err := NewCapacity(0)
+	require.ErrorIs(t, err, ErrInvalidCapacity)
+
+	c, err := NewCapacity(5)
+	require.NoError(t, err)
+	assert.Equal(t, 5, c.Load())
+
+	require.NoError(t, c.Set(10))
+	assert.Equal(t, 10, c.Load())
+	require.ErrorIs(t, c.Set(-1), ErrInvalidCapacity)
+	assert.Equal(t, 10, c.Load(), "invalid value keeps current capacity")
+}
+
+func TestWithDynamicCapacity_Options(t *testing.T) {
+	c, err := NewCapacity(7)
+	require.NoError(t, err)
+
+	cfg := applyAcquireOptions([]AcquireOption{WithDynamicCapacity(c)})
+	assert.Equal(t, 7, cfg.capacity)
+	require.NoError(t, c.Set(3))
+	cfg.refreshCapacity()
+	assert.Equal(t, 3, cfg.capacity)
+
+	// 后设置者生效
+	cfg = applyAcquireOptions([]AcquireOption{WithDynamicCapacity(c), WithCapacity(20)})
+	cfg.refreshCapacity()
+	assert.Equal(t, 20, cfg.capacity)
+
+	cfg = applyAcquireOptions([]AcquireOption{WithCapacity(20), WithDynamicCapacity(nil)})
+	assert.Equal(t, 20, cfg.capacity, "nil dynamic capacity is ignored")
+
+	qcfg := defaultQueryOptions()
+	QueryWithDynamicCapacity(c)(qcfg)
+	assert.Equal(t, 3, qcfg.capacity)
+}
+
+func TestRedisSemaphore_DynamicCapacity(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+	c, err := NewCapacity(3)
+	require.NoError(t, err)
+
+	var permits []Permit
+	for range 3 {
+		p, err := sem.TryAcquire(ctx, "dyn", WithDynamicCapacity(c))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		permits = append(permits, p)
+	}
+	p, err := sem.TryAcquire(ctx, "dyn", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	assert.Nil(t, p, "capacity full")
+
+	// 扩容立即生效
+	require.NoError(t, c.Set(4))
+	p, err = sem.TryAcquire(ctx, "dyn", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	permits = append(permits, p)
+
+	// 缩容：已发放的许可保持有效，超发期间拒绝新的获取
+	require.NoError(t, c.Set(2))
+	info, err := sem.Query(ctx, "dyn", QueryWithDynamicCapacity(c))
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.GlobalCapacity)
+	assert.Equal(t, 4, info.GlobalUsed)
+	assert.Equal(t, 0, info.GlobalAvailable)
+	for _, held := range permits {
+		require.NoError(t, held.Extend(ctx), "over-issued permits stay valid")
+	}
+
+	require.NoError(t, permits[0].Release(ctx))
+	require.NoError(t, permits[1].Release(ctx))
+	p, err = sem.TryAcquire(ctx, "dyn", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	assert.Nil(t, p, "used count (2) has not dropped below new capacity (2)")
+
+	require.NoError(t, permits[2].Release(ctx))
+	p, err = sem.TryAcquire(ctx, "dyn", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	releasePermit(t, ctx, p)
+	releasePermit(t, ctx, permits[3])
+}
+
+func TestRedisSemaphore_DynamicCapacityDuringAcquire(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+	c, err := NewCapacity(1)
+	require.NoError(t, err)
+
+	held, err := sem.TryAcquire(ctx, "dyn-wait", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	defer releasePermit(t, ctx, held)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = c.Set(2)
+	}()
+	p, err := sem.Acquire(ctx, "dyn-wait", WithDynamicCapacity(c),
+		WithMaxRetries(100), WithRetryDelay(5*time.Millisecond))
+	require.NoError(t, err, "scale-up during retries must be observed")
+	releasePermit(t, ctx, p)
+}
+
+func TestLocalSemaphore_DynamicCapacityDuringAcquire(t *testing.T) {
+	sem := newLocalSemaphore(defaultOptions())
+	defer closeSemaphore(t, sem)
+	ctx := context.Background()
+	c, err := NewCapacity(1)
+	require.NoError(t, err)
+
+	held, err := sem.TryAcquire(ctx, "dyn-local", WithDynamicCapacity(c))
+	require.NoError(t, err)
+	require.NotNil(t, held)
+	defer releasePermit(t, ctx, held)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = c.Set(2)
+	}()
+	p, err := sem.Acquire(ctx, "dyn-local", WithDynamicCapacity(c),
+		WithMaxRetries(100), WithRetryDelay(5*time.Millisecond))
+	require.NoError(t, err)
+	releasePermit(t, ctx, p)
+}
@@ -2,6 +2,9 @@ package xsemaphore
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/omeyang/xkit/pkg/context/xtenant"
@@ -39,6 +42,71 @@ func waitForRetry(ctx context.Context, delay time.Duration) error {
 	}
 }
 
+// tryAcquireFunc 非阻塞获取函数，WaitAcquire 以此轮询
+type tryAcquireFunc func(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error)
+
+// waitAcquire WaitAcquire 的公共实现：按退避间隔轮询 tryAcquire 直到成功或 ctx 到期
+//
+// 设计决策: 基于 TryAcquire 轮询而非新增后端原语，各实现（含降级包装）行为一致，
+// 每次轮询的指标和 trace 也与 TryAcquire 相同。
+//
+// 启用公平队列时，各次轮询使用同一等待者 ID 以保持排队位置，未获取到许可时通过 leave 离开队列。
+func waitAcquire(ctx context.Context, resource string, opts []AcquireOption, tryAcquire tryAcquireFunc, leave leaveFairQueueFunc) (permit Permit, err error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	cfg := applyAcquireOptions(opts)
+	backoff := cfg.effectivePollBackoff()
+
+	if cfg.fairQueue && cfg.handoffToken == "" {
+		waiterID, idErr := newFairWaiterID(ctx)
+		if idErr != nil {
+			return nil, idErr
+		}
+		opts = append(slices.Clip(opts), withFairWaiter(waiterID))
+		defer func() {
+			if permit == nil {
+				leave(ctx, resource, waiterID)
+			}
+		}()
+	}
+
+	// lastErr 记录最近一次可重试的 Redis 错误，ctx 到期时据此区分"容量未释放"与"Redis 持续异常"
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		permit, err := tryAcquire(ctx, resource, opts...)
+		if err != nil && !isRetryableRedisError(err) {
+			// ctx 到期导致的 Redis 调用失败视为等待超时
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return waitAcquireTimeout(ctxErr, lastErr)
+			}
+			return nil, err
+		}
+		if permit != nil {
+			return permit, nil
+		}
+		lastErr = err
+		if err := waitForRetry(ctx, backoff.NextDelay(attempt)); err != nil {
+			return waitAcquireTimeout(err, lastErr)
+		}
+	}
+}
+
+// waitAcquireTimeout 将 ctx 终止转换为 WaitAcquire 的返回值
+//
+// deadline 到期且最后一次尝试为容量已满时返回 (nil, nil)；
+// 最后一次尝试失败于可重试的 Redis 错误时返回包装了该错误的 error，避免把 Redis 故障误报为超时；
+// 主动取消仍返回错误。
+func waitAcquireTimeout(err, lastErr error) (Permit, error) {
+	if !errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%w: last attempt failed: %w", err, lastErr)
+	}
+	return nil, nil
+}
+
 // applyAcquireOptions 应用获取选项并返回配置
 func applyAcquireOptions(opts []AcquireOption) *acquireOptions {
 	cfg := defaultAcquireOptions()
@@ -82,6 +150,16 @@ func prepareAcquireCommon(ctx context.Context, resource string, opts []AcquireOp
 		return nil, "", err
 	}
 
+	// 接管转移许可时租户信息沿用原许可
+	if cfg.handoffToken != "" {
+		payload, err := parseHandoffToken(cfg.handoffToken, resource)
+		if err != nil {
+			return nil, "", err
+		}
+		cfg.handoff = payload
+		return cfg, payload.TenantID, nil
+	}
+
 	tenantID := resolveTenantID(ctx, cfg.tenantID)
 	if err := validateTenantID(tenantID); err != nil {
 		return nil, "", err
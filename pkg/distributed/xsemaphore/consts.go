@@ -16,6 +16,13 @@ const (
 	// DefaultTTL 默认许可过期时间
 	DefaultTTL = 5 * time.Minute
 
+	// DefaultReservationTTL Reserve 默认预留过期时间
+	DefaultReservationTTL = 10 * time.Second
+
+	// MaxReservationTTL Reserve 预留过期时间的上限
+	// 预留用于短暂的预检，较长的占用应直接获取许可
+	MaxReservationTTL = time.Minute
+
 	// DefaultMaxRetries Acquire 默认最大尝试次数（首次尝试 + 重试）
 	DefaultMaxRetries = 10
 
@@ -27,8 +34,17 @@ const (
 	// DefaultRetryDelay Acquire 默认重试间隔
 	DefaultRetryDelay = 100 * time.Millisecond
 
+	// DefaultMaxPollInterval WaitAcquire 默认退避的最大轮询间隔
+	// 默认退避从 DefaultRetryDelay 开始指数增长，至多增长到此值
+	DefaultMaxPollInterval = time.Second
+
 	// DefaultPodCount 默认 Pod 数量
 	DefaultPodCount = 1
+
+	// MaxPermitCount AcquireN 单个许可的名额上限
+	// 每个名额在集合中占一个成员，Release/Extend/Handoff 需在一个脚本内处理全部成员，
+	// 上限避免单次脚本执行过久阻塞 Redis；转移 token 中的名额数同样受此限制。
+	MaxPermitCount = 10000
 )
 
 // =============================================================================
@@ -50,6 +66,14 @@ const (
 	// 在 Redis 故障风暴期间，限制回调频率，避免下游雪崩
 	fallbackCallbackMinInterval = 10 * time.Second
 
+	// fairWaiterTTL 公平队列等待者的存活时间
+	// 等待者每次轮询时续约，超过此时间未轮询视为失联，从队列中移除
+	fairWaiterTTL = 10 * time.Second
+
+	// fairLeaveTimeout 离开公平队列的超时时间
+	// 离开队列使用独立的 context，调用方 ctx 已到期时仍能清理
+	fairLeaveTimeout = 3 * time.Second
+
 	// noopPermitIDPrefix FallbackOpen 策略下 noop 许可 ID 的前缀
 	// 用于在日志和监控中区分 noop 许可与正常许可
 	noopPermitIDPrefix = "noop-"
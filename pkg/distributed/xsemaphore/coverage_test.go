@@ -1259,7 +1259,7 @@ func TestCleanupExpiredLocked(t *testing.T) {
 }
 
 // =============================================================================
-// removeExpiredPermitLocked 测试
+// removePermitLocked 测试
 // =============================================================================
 
 func TestRemoveExpiredPermitLocked(t *testing.T) {
@@ -1291,7 +1291,7 @@ func TestRemoveExpiredPermitLocked(t *testing.T) {
 			"test-permit": rp.global["test-permit"],
 		}
 
-		sem.removeExpiredPermitLocked(rp, p)
+		sem.removePermitLocked(rp, p)
 
 		if _, exists := rp.global["test-permit"]; exists {
 			t.Error("permit should be removed from global")
@@ -1315,7 +1315,7 @@ func TestRemoveExpiredPermitLocked(t *testing.T) {
 		rp.global["test-permit"] = &permitEntry{}
 
 		// Should not panic
-		sem.removeExpiredPermitLocked(rp, p)
+		sem.removePermitLocked(rp, p)
 
 		if _, exists := rp.global["test-permit"]; exists {
 			t.Error("permit should be removed from global")
@@ -2142,9 +2142,31 @@ func (s *closableTestSemaphore) TryAcquire(ctx context.Context, resource string,
 func (s *closableTestSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	return nil, nil
 }
+func (s *closableTestSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) Cancel(ctx context.Context, token string) error {
+	return nil
+}
 func (s *closableTestSemaphore) Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error) {
 	return nil, nil
 }
+func (s *closableTestSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	return nil, nil
+}
+func (s *closableTestSemaphore) Held() []HeldPermit { return nil }
 func (s *closableTestSemaphore) Close(_ context.Context) error {
 	s.closed = true
 	return nil
@@ -2228,9 +2250,31 @@ func (s *healthyTestSemaphore) TryAcquire(ctx context.Context, resource string,
 func (s *healthyTestSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	return nil, nil
 }
+func (s *healthyTestSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) Cancel(ctx context.Context, token string) error {
+	return nil
+}
 func (s *healthyTestSemaphore) Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error) {
 	return nil, nil
 }
+func (s *healthyTestSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	return nil, nil
+}
+func (s *healthyTestSemaphore) Held() []HeldPermit { return nil }
 func (s *healthyTestSemaphore) Close(_ context.Context) error {
 	return nil
 }
@@ -2246,9 +2290,31 @@ func (s *unhealthyTestSemaphore) TryAcquire(ctx context.Context, resource string
 func (s *unhealthyTestSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	return nil, nil
 }
+func (s *unhealthyTestSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) Cancel(ctx context.Context, token string) error {
+	return nil
+}
 func (s *unhealthyTestSemaphore) Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error) {
 	return nil, nil
 }
+func (s *unhealthyTestSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	return nil, nil
+}
+func (s *unhealthyTestSemaphore) Held() []HeldPermit { return nil }
 func (s *unhealthyTestSemaphore) Close(_ context.Context) error {
 	return nil
 }
@@ -2578,9 +2644,31 @@ func (s *errorOnCloseSemaphore) TryAcquire(ctx context.Context, resource string,
 func (s *errorOnCloseSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	return nil, nil
 }
+func (s *errorOnCloseSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) Cancel(ctx context.Context, token string) error {
+	return nil
+}
 func (s *errorOnCloseSemaphore) Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error) {
 	return nil, nil
 }
+func (s *errorOnCloseSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	return nil, nil
+}
+func (s *errorOnCloseSemaphore) Held() []HeldPermit { return nil }
 func (s *errorOnCloseSemaphore) Close(_ context.Context) error {
 	return errors.New("close error")
 }
@@ -2749,9 +2837,31 @@ func (s *nonRedisErrorSemaphore) TryAcquire(ctx context.Context, resource string
 func (s *nonRedisErrorSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	return nil, ErrInvalidCapacity // Not a Redis error
 }
+func (s *nonRedisErrorSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	return nil, ErrInvalidCapacity // Not a Redis error
+}
+func (s *nonRedisErrorSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error) {
+	return nil, ErrInvalidCapacity
+}
+func (s *nonRedisErrorSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return nil, ErrInvalidCapacity
+}
+func (s *nonRedisErrorSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return nil, ErrInvalidCapacity
+}
+func (s *nonRedisErrorSemaphore) Cancel(ctx context.Context, token string) error {
+	return ErrInvalidCapacity
+}
 func (s *nonRedisErrorSemaphore) Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error) {
 	return nil, ErrInvalidCapacity
 }
+func (s *nonRedisErrorSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	return nil, ErrInvalidCapacity
+}
+func (s *nonRedisErrorSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	return nil, ErrInvalidCapacity
+}
+func (s *nonRedisErrorSemaphore) Held() []HeldPermit { return nil }
 func (s *nonRedisErrorSemaphore) Close(_ context.Context) error {
 	return nil
 }
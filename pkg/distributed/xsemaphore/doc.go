@@ -20,7 +20,7 @@
 // # 核心概念
 //
 //   - Semaphore: 信号量工厂，管理连接和创建许可
-//   - Permit: 单个许可句柄，提供 Release/Extend/StartAutoExtend 操作
+//   - Permit: 单个许可句柄，提供 Release/Extend/ExtendUntil/StartAutoExtend 操作
 //   - AcquireOption: 获取许可的配置选项
 //   - Option: 工厂级配置选项
 //
@@ -54,6 +54,27 @@
 //
 //	// 执行任务...
 //
+// # 有界等待获取
+//
+// WaitAcquire 在 ctx 到期前以退避间隔轮询，超时返回 (nil, nil)，
+// 与服务异常的 (nil, err) 区分开，适合替代手写的 TryAcquire 重试循环：
+//
+//	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+//	defer cancel()
+//	permit, err := sem.WaitAcquire(ctx, "inference",
+//	    xsemaphore.WithCapacity(100),
+//	    xsemaphore.WithPollInterval(200*time.Millisecond), // 默认指数退避 100ms → 1s
+//	)
+//	if err != nil {
+//	    return err // Redis 异常或 ctx 被取消
+//	}
+//	if permit == nil {
+//	    return errors.New("system busy") // 等待超时
+//	}
+//	defer permit.Release(ctx)
+//
+// 也可以通过 WithPollBackoff 传入任意 xretry.BackoffPolicy。
+//
 // # 长任务自动续租
 //
 // 对于运行时间不确定的长任务，可以启动自动续租：
@@ -70,6 +91,97 @@
 //
 //	// 执行长时间任务...
 //
+// 对于有明确截止时间的任务，可直接续期到绝对时间，避免多次相对续期的时间漂移：
+//
+//	err := permit.ExtendUntil(ctx, jobDeadline)
+//
+// 续期遵循"只延长不缩短"：ExtendUntil 的截止时间早于当前过期时间时保持不变，
+// 之后的 Extend/StartAutoExtend 也不会把更晚的截止时间缩短。
+//
+// # 公平队列
+//
+// 默认模式下容量释放后由恰好到达的请求获得，竞争激烈时长时间等待的请求可能被饿死。
+// WithFairQueue 让 Acquire/WaitAcquire 进入资源的等待队列，按到达顺序分配许可：
+//
+//	permit, err := sem.WaitAcquire(ctx, "inference",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithFairQueue(),
+//	)
+//
+// 入队、排队位置判断和获取在同一个 Lua 脚本中原子完成。需要权衡的是：
+//   - 每个资源额外两个 Redis 键，每次轮询多一次写操作
+//   - 等待者靠轮询续约（存活 10s），崩溃的等待者最多阻塞队列 10s 后被清理；
+//     超时、取消或重试耗尽时等待者会主动离开队列
+//   - 租户配额已满的等待者会被移出队列，避免阻塞其他租户
+//   - 只有同一资源的所有调用方都启用该选项时才能保证公平
+//
+// WithQueuePositionCallback 在每次未获取到许可后反馈排在前面的等待者数量，
+// 调用方可据此取消 ctx 放弃等待（如"前面还有 100 个"）。位置由同一脚本计算，是估计值。
+//
+// # 批量获取
+//
+// 一个任务同时需要多个并发名额时，逐个获取可能只拿到一部分，多个任务互相持有部分名额还会死锁。
+// AcquireN 在一次后端操作中原子获取 n 个名额，要么全部获取，要么一个也不获取：
+//
+//	permit, err := sem.AcquireN(ctx, "gpu", 4, xsemaphore.WithCapacity(8))
+//	if err != nil {
+//	    return err
+//	}
+//	if permit == nil {
+//	    return nil // 剩余名额不足 4 个
+//	}
+//	defer permit.Release(ctx) // 一次释放全部 4 个名额
+//
+// 全局容量和租户配额都必须能容纳全部名额；n 超过容量或配额时永远无法满足，返回 ErrInvalidPermitCount。
+// n 的上限为 MaxPermitCount，超过时同样返回 ErrInvalidPermitCount。
+// 批量许可的每个名额在集合中占一个成员（ID、ID#1 … ID#(n-1)），
+// 因此 Query 按名额计数，Extend 和 Handoff 同时作用于全部名额。
+//
+// # 许可转移
+//
+// 任务在实例间迁移（如优雅关闭前转移工作）时，可以把许可转移给另一个实例，
+// 而不是释放后重新获取——后者在释放与获取之间存在被其他获取者抢占的窗口：
+//
+//	// 原实例：交出许可，将 token 随任务状态一起传给接管方
+//	token, err := permit.Handoff(ctx)
+//	if err != nil {
+//	    return err // 许可仍由本实例持有，可重试或正常 Release
+//	}
+//
+//	// 接管方：使用 token 原子接管，不经过容量检查
+//	permit, err := sem.Acquire(ctx, "long-task",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithHandoffToken(token),
+//	)
+//	if errors.Is(err, xsemaphore.ErrHandoffExpired) {
+//	    // 原许可已过期或 token 已被使用，按需改用普通获取
+//	}
+//
+// Handoff 会先续期许可，接管方需在一个 TTL 内完成接管，否则许可按正常过期流程回收。
+// token 只能使用一次，租户信息沿用原许可。token 未签名，接管时校验原许可的实际名额数
+// 与 token 一致，不一致（如被篡改）同样返回 ErrHandoffExpired，不会产生超出容量的许可。
+//
+// # 两阶段预留
+//
+// 需要先占住名额、做一次快速预检、再决定确认或回滚时，使用 Reserve/Confirm/Cancel，
+// 避免预检期间以完整 TTL 持有许可：
+//
+//	r, err := sem.Reserve(ctx, "export", xsemaphore.WithCapacity(10))
+//	if err != nil || r == nil {
+//	    return err // 服务异常或容量已满
+//	}
+//	if err := preflight(ctx); err != nil {
+//	    _ = sem.Cancel(ctx, r.Token) // 立即归还名额
+//	    return err
+//	}
+//	permit, err := sem.Confirm(ctx, r.Token, xsemaphore.WithTTL(10*time.Minute))
+//
+// 预留基于许可转移实现，真实占用名额，TTL 默认 DefaultReservationTTL（10 秒），
+// 可通过 WithReservationTTL 调整（上限 MaxReservationTTL）。被遗弃的预留到期自动回收。
+// Confirm 原子地转为完整许可，不经过容量检查；预留过期或已使用时返回 ErrReservationExpired。
+// Cancel 幂等，预留已过期或已确认时返回 nil。
+// FallbackLocal 下，Redis 中的预留无法在本地确认（返回 ErrReservationExpired），反之亦然。
+//
 // # 租户配额限制
 //
 // 支持在全局容量基础上叠加租户级配额。租户配额仅在同时满足以下条件时启用：
@@ -102,6 +214,8 @@
 //	| TryAcquire | 使用本地信号量     | 返回虚拟许可       | 返回错误           |
 //	| Acquire    | 使用本地信号量     | 返回虚拟许可       | 返回错误           |
 //	| Query      | 查询本地状态       | 返回全部可用       | 返回错误           |
+//	| Inspect    | 列出本地许可       | 返回空列表         | 返回错误           |
+//	| QueryMulti | 逐资源同 Query     | 逐资源同 Query     | 逐资源同 Query     |
 //
 // 注意：context.Canceled 和 context.DeadlineExceeded 不会触发降级，
 // 因为这些是客户端超时，不表示 Redis 不可用。
@@ -128,6 +242,18 @@
 // 这种设计确保时钟问题不会导致整个服务崩溃，而是返回可处理的错误。
 // 建议在监控系统中设置对 ErrIDGenerationFailed 错误的告警。
 //
+// # 利用率指标
+//
+// 配置 WithMeterProvider 后，除计数器和耗时直方图外，还会上报两个 Gauge：
+//   - xsemaphore.permits.active: 资源当前活跃许可数
+//   - xsemaphore.permits.capacity: 资源容量（取自 WithCapacity / QueryWithCapacity）
+//
+// 两者在 Acquire/TryAcquire（含失败）、Release 和 Query 时更新，
+// 可直接配置 "active / capacity > 0.9" 之类的饱和告警。
+// 分布式信号量上报的是全局活跃数，多实例上报同一资源时应在采集端取 max 而非 sum；
+// 本地信号量上报的是按 Pod 数拆分后的本地容量。
+// 启用 WithDisableResourceLabel 时不上报这两个 Gauge（各资源的值会相互覆盖）。
+//
 // # 资源命名最佳实践
 //
 // 资源名称会作为指标标签，应避免使用动态生成的名称（如包含用户 ID），
@@ -152,11 +278,17 @@
 // Redis 存储使用 Sorted Set：
 //
 //	# 全局许可集合 - score=过期时间戳毫秒, member=permitID
+//	# AcquireN 获取的 n 个名额对应 n 个成员：permitID, permitID#1 ... permitID#(n-1)
 //	{prefix}:{resource}:permits -> ZSET
 //
 //	# 租户许可集合（仅在 TenantID 非空且 TenantQuota > 0 时创建）
 //	{prefix}:{resource}:t:{tenantID} -> ZSET
 //
+//	# 公平队列（仅在使用 WithFairQueue 时创建）
+//	# score=入队时间戳毫秒 / 存活截止时间戳毫秒, member=等待者 ID
+//	{prefix}:{resource}:queue -> ZSET
+//	{prefix}:{resource}:queue:alive -> ZSET
+//
 // # Redis Cluster 支持
 //
 // xsemaphore 使用 {resource} 作为 hash tag，确保同一资源的全局键和租户键
@@ -202,6 +334,9 @@
 //   - 引入"粘滞回切"或"permit 补登"机制会大幅增加复杂度，与 fallback 的"尽力而为"定位不符
 //   - 业务方若需严格容量保证，应使用 FallbackClose 策略
 //
+// 恢复窗口可通过 WithOnFallbackTransition 观测：资源在分布式与本地模式之间切换时
+// 回调 (resource, localActive)，回切时 localActive > 0 即表示存在潜在超发。
+//
 // # 并发安全
 //
 // xsemaphore 的所有公开方法都是并发安全的：
@@ -309,6 +444,36 @@
 // 过期许可的清理由 Acquire 和 Extend 的写路径负责（通过 ZREMRANGEBYSCORE），
 // 因此 Query 返回的计数始终是准确的（排除了 score <= now 的过期条目）。
 //
+// 需要同时展示多个资源（如仪表盘、批量调度前的容量检查）时使用 QueryMulti，
+// 所有资源的 ZCOUNT 在同一个 pipeline 中发送，只需一次网络往返：
+//
+//	results, err := sem.QueryMulti(ctx, []string{"gpu-a", "gpu-b"}, xsemaphore.QueryWithCapacity(100))
+//	for resource, r := range results {
+//	    if r.Err != nil { continue }
+//	    log.Printf("%s used=%d", resource, r.Info.GlobalUsed)
+//	}
+//
+// 参数错误（nil context、非法租户、已关闭）作为调用错误返回；资源名非法或资源所在节点
+// 不可用只体现在该资源的 QueryResult.Err 中。Cluster 模式下 ClusterClient 按 slot 把命令
+// 分组发往各节点，不会产生 CROSSSLOT 错误。
+//
+// # 排查许可泄漏
+//
+// Inspect 列出资源当前的有效许可（ID、过期时间），按过期时间升序排列，
+// 用于定位长时间未释放的持有者。与 Query 一样是只读操作（ZRANGEBYSCORE），可路由到从节点：
+//
+//	permits, err := sem.Inspect(ctx, "inference-task")
+//	for _, p := range permits {
+//	    log.Printf("holder=%s expires_in=%s", p.ID, time.Until(p.ExpiresAt))
+//	}
+//
+// 全局列表不记录租户归属，需要按租户查看时传入 QueryWithTenantID；
+// Inspect 不会从 context 提取租户，避免请求上下文隐式过滤掉其他租户的许可。
+//
+// Inspect 看到的是后端全局视图；Held 则只列出本进程持有且尚未释放的许可（本地记录，
+// 不访问后端），用于判断"是不是本进程占着名额"。HeldPermit 与 xdbg.PermitInfo 字段一致，
+// 可直接转换后通过 xdbg.WithSemaphoreProvider 接入 permits 调试命令。
+//
 // # 设计说明：容量每次调用传入
 //
 // 容量（capacity）和租户配额（tenantQuota）在每次 Acquire 调用时传入，
@@ -317,6 +482,13 @@
 //   - 业务层可通过封装确保一致性
 //   - 避免工厂级配置与调用级配置的优先级混淆
 //
+// # 动态容量
+//
+// 需要运行时扩缩容时，使用 NewCapacity 创建 Capacity 并通过 WithDynamicCapacity 传入，
+// 在 xconf 热更新回调中调用 Capacity.Set 即可生效。每次获取尝试（含 Acquire 重试、
+// WaitAcquire 轮询）都会读取最新容量。缩容时已发放的许可不回收，超发期间新的获取
+// 按容量已满处理，直到已用数回落到新容量以下；Query 配合 QueryWithDynamicCapacity 使用。
+//
 // # Close() 行为
 //
 // Close() 方法会阻止新的 TryAcquire/Acquire/Query 操作，但不会影响已获取的许可：
@@ -102,6 +102,30 @@ var (
 	// 脚本模式必须为 ScriptModeAuto、ScriptModeLua 或 ScriptModeCompat。
 	ErrInvalidScriptMode = errors.New("xsemaphore: invalid script mode")
 
+	// ErrInvalidHandoffToken 无效的转移 token。
+	// token 格式错误或与 Acquire 的资源名不匹配时返回此错误。
+	ErrInvalidHandoffToken = errors.New("xsemaphore: invalid handoff token")
+
+	// ErrHandoffExpired 转移的许可已失效。
+	// 使用 token 接管时原许可已过期或已被其他实例接管（token 只能使用一次）。
+	ErrHandoffExpired = errors.New("xsemaphore: handoff permit expired or already taken over")
+
+	// ErrInvalidReservation 无效的预留 token。
+	// 传给 Confirm/Cancel 的 token 格式错误时返回此错误。
+	ErrInvalidReservation = errors.New("xsemaphore: invalid reservation token")
+
+	// ErrReservationExpired 预留已失效。
+	// Confirm 时预留已过期、已被确认或已被取消（token 只能使用一次）。
+	ErrReservationExpired = errors.New("xsemaphore: reservation expired or already used")
+
+	// ErrInvalidPermitCount 无效的批量许可数量。
+	// AcquireN 的数量必须为正数，且不能超过全局容量和租户配额（否则永远无法满足）。
+	ErrInvalidPermitCount = errors.New("xsemaphore: invalid permit count")
+
+	// ErrInvalidDeadline 无效的续期截止时间。
+	// ExtendUntil 的截止时间不在当前时间之后时返回此错误。
+	ErrInvalidDeadline = errors.New("xsemaphore: invalid extend deadline")
+
 	// errUnexpectedScriptResult Lua 脚本返回结果不符合预期（内部使用）
 	errUnexpectedScriptResult = errors.New("xsemaphore: unexpected script result")
 )
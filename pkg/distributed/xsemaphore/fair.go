@@ -0,0 +1,212 @@
+package xsemaphore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/omeyang/xkit/pkg/util/xid"
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// 公平队列（FIFO）
+//
+// 阻塞获取（Acquire/WaitAcquire）在首次尝试时以等待者 ID 进入资源的等待队列，
+// 后续轮询复用同一 ID 保持排队位置。每次尝试时：
+//  1. 清理失联的等待者（存活时间内未轮询）
+//  2. 入队（已在队列中则保留位置）并续约存活时间
+//  3. 当前许可数 + 排在前面的等待者数 < 容量时才获取许可，获取后离开队列
+//
+// 放弃等待（超时、取消、重试耗尽）时主动离开队列；进程崩溃时依赖存活时间清理。
+// =============================================================================
+
+// leaveFairQueueFunc 离开公平队列，由各实现提供
+type leaveFairQueueFunc func(ctx context.Context, resource, waiterID string)
+
+// fairQueueLeaver 支持离开公平队列的实现（用于降级包装器转发）
+type fairQueueLeaver interface {
+	leaveFairQueue(ctx context.Context, resource, waiterID string)
+}
+
+// newFairWaiterID 生成公平队列等待者 ID
+//
+// 设计决策: 等待者 ID 仅在内部使用，不经过 WithIDGenerator，
+// 自定义生成器只影响对外可见的许可 ID。
+func newFairWaiterID(ctx context.Context) (string, error) {
+	id, err := xid.NewStringWithRetry(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrIDGenerationFailed, err)
+	}
+	return id, nil
+}
+
+// joinFairQueue 为 Acquire 分配公平队列的等待者 ID
+// 未启用公平队列或接管转移许可时不入队
+func joinFairQueue(ctx context.Context, cfg *acquireOptions) error {
+	if !cfg.fairQueue || cfg.handoff != nil || cfg.waiterID != "" {
+		return nil
+	}
+	waiterID, err := newFairWaiterID(ctx)
+	if err != nil {
+		return err
+	}
+	cfg.waiterID = waiterID
+	return nil
+}
+
+// reportQueuePosition 向调用方反馈排队位置
+// 仅对已入队的等待者（Acquire/WaitAcquire）回调
+func (o *acquireOptions) reportQueuePosition(ahead int) {
+	if o.onQueuePosition == nil || o.waiterID == "" {
+		return
+	}
+	o.onQueuePosition(ahead)
+}
+
+// =============================================================================
+// Redis 实现
+// =============================================================================
+
+// leaveFairQueue 将等待者移出 Redis 等待队列
+//
+// 使用独立于调用方的 context，调用方 ctx 已到期时仍能清理。
+// 设计决策: 清理失败不影响正确性（等待者存活时间到期后自动移除），不返回错误；
+// 但在此之前排在其后的等待者会被阻塞，故记录 Warn 日志便于排查。
+func (s *redisSemaphore) leaveFairQueue(ctx context.Context, resource, waiterID string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fairLeaveTimeout)
+	defer cancel()
+
+	pipe := s.client.Pipeline()
+	pipe.ZRem(ctx, s.buildQueueKey(resource), waiterID)
+	pipe.ZRem(ctx, s.buildQueueAliveKey(resource), waiterID)
+	if _, err := pipe.Exec(ctx); err != nil && s.opts.logger != nil {
+		s.opts.logger.Warn(ctx, "leave fair queue failed, waiter stays queued until alive TTL expires",
+			AttrResource(resource),
+			AttrError(err),
+		)
+	}
+}
+
+// fairQueueAheadCompat 维护等待队列并返回排在前面的等待者数量（兼容模式）
+//
+// 与 fair_acquire.lua 步骤 2、3 等价，但非原子：
+// 并发时排队位置可能短暂不准确，容量检查仍由 add-then-check 保证不过量放行。
+// waiterID 为空时不入队，返回队列长度。
+func (s *redisSemaphore) fairQueueAheadCompat(ctx context.Context, resource, waiterID string, nowMs int64) (int64, error) {
+	queueKey := s.buildQueueKey(resource)
+	aliveKey := s.buildQueueAliveKey(resource)
+	nowStr := strconv.FormatInt(nowMs, 10)
+
+	// 1. 清理失联的等待者
+	dead, err := s.client.ZRangeByScore(ctx, aliveKey, &redis.ZRangeBy{Min: "-inf", Max: nowStr}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("fair queue cleanup failed: %w", err)
+	}
+	if len(dead) > 0 {
+		members := make([]any, len(dead))
+		for i, id := range dead {
+			members[i] = id
+		}
+		pipe := s.client.Pipeline()
+		pipe.ZRem(ctx, queueKey, members...)
+		pipe.ZRem(ctx, aliveKey, members...)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return 0, fmt.Errorf("fair queue cleanup failed: %w", err)
+		}
+	}
+
+	// 2. 入队并计算位置
+	pipe := s.client.Pipeline()
+	var rankCmd *redis.IntCmd
+	if waiterID != "" {
+		ttl := fairWaiterTTL + keyTTLMargin
+		pipe.ZAddNX(ctx, queueKey, redis.Z{Score: float64(nowMs), Member: waiterID})
+		pipe.ZAdd(ctx, aliveKey, redis.Z{Score: float64(nowMs + fairWaiterTTL.Milliseconds()), Member: waiterID})
+		pipe.PExpire(ctx, queueKey, ttl)
+		pipe.PExpire(ctx, aliveKey, ttl)
+		rankCmd = pipe.ZRank(ctx, queueKey, waiterID)
+	}
+	cardCmd := pipe.ZCard(ctx, queueKey)
+	// ZRANK 在等待者被并发清理时返回 redis.Nil，此时按排在队尾处理
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("fair queue enqueue failed: %w", err)
+	}
+	if rankCmd != nil && rankCmd.Err() == nil {
+		return rankCmd.Val(), nil
+	}
+	return cardCmd.Val(), nil
+}
+
+// =============================================================================
+// 本地实现
+// =============================================================================
+
+// fairWaiter 本地公平队列中的等待者
+type fairWaiter struct {
+	id         string
+	aliveUntil time.Time
+}
+
+// pruneFairQueueLocked 清理失联的等待者（调用者必须持有 rp.mu 锁）
+func (rp *resourcePermits) pruneFairQueueLocked(now time.Time) {
+	rp.queue = slices.DeleteFunc(rp.queue, func(w fairWaiter) bool {
+		return !w.aliveUntil.After(now)
+	})
+}
+
+// fairAheadLocked 入队并返回排在前面的等待者数量（调用者必须持有 rp.mu 锁）
+// waiterID 为空时不入队，返回队列长度
+func (rp *resourcePermits) fairAheadLocked(waiterID string, now time.Time) int {
+	rp.pruneFairQueueLocked(now)
+	if waiterID == "" {
+		return len(rp.queue)
+	}
+
+	aliveUntil := now.Add(fairWaiterTTL)
+	if i := slices.IndexFunc(rp.queue, func(w fairWaiter) bool { return w.id == waiterID }); i >= 0 {
+		rp.queue[i].aliveUntil = aliveUntil
+		return i
+	}
+	rp.queue = append(rp.queue, fairWaiter{id: waiterID, aliveUntil: aliveUntil})
+	return len(rp.queue) - 1
+}
+
+// leaveFairQueueLocked 将等待者移出队列（调用者必须持有 rp.mu 锁）
+func (rp *resourcePermits) leaveFairQueueLocked(waiterID string) {
+	rp.queue = slices.DeleteFunc(rp.queue, func(w fairWaiter) bool {
+		return w.id == waiterID
+	})
+}
+
+// leaveFairQueue 将等待者移出本地等待队列
+func (s *localSemaphore) leaveFairQueue(_ context.Context, resource, waiterID string) {
+	rp := s.tryGetResourcePermits(resource)
+	if rp == nil {
+		return
+	}
+	rp.mu.Lock()
+	rp.leaveFairQueueLocked(waiterID)
+	rp.mu.Unlock()
+}
+
+// =============================================================================
+// 降级包装
+// =============================================================================
+
+// leaveFairQueue 同时离开分布式和本地等待队列
+// 轮询期间可能在两者之间切换，等待者可能同时存在于两个队列中
+func (f *fallbackSemaphore) leaveFairQueue(ctx context.Context, resource, waiterID string) {
+	if d, ok := f.distributed.(fairQueueLeaver); ok {
+		d.leaveFairQueue(ctx, resource, waiterID)
+	}
+	f.localMu.Lock()
+	local := f.local
+	f.localMu.Unlock()
+	if local != nil {
+		local.leaveFairQueue(ctx, resource, waiterID)
+	}
+}
@@ -0,0 +1,319 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// 公平队列测试
+// =============================================================================
+
+func TestFairQueue_Redis(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testFairQueue(t, func(t *testing.T) Semaphore {
+				sem, _ := setupSemaphore(t, WithScriptMode(mode))
+				return sem
+			})
+		})
+	}
+}
+
+func TestFairQueue_Local(t *testing.T) {
+	testFairQueue(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	})
+}
+
+// startFairWaiter 在后台以公平队列模式等待许可，返回结果 channel
+func startFairWaiter(ctx context.Context, sem Semaphore, opts ...AcquireOption) <-chan Permit {
+	ch := make(chan Permit, 1)
+	opts = append([]AcquireOption{WithCapacity(1), WithFairQueue(), WithPollInterval(5 * time.Millisecond)}, opts...)
+	go func() {
+		p, _ := sem.WaitAcquire(ctx, "job", opts...)
+		ch <- p
+	}()
+	// 首次轮询立即入队
+	time.Sleep(50 * time.Millisecond)
+	return ch
+}
+
+func testFairQueue(t *testing.T, newSem func(t *testing.T) Semaphore) {
+	ctx := context.Background()
+
+	t.Run("waiter is served before newcomers", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, holder)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		waiter := startFairWaiter(waitCtx, sem)
+
+		require.NoError(t, holder.Release(ctx))
+
+		// 空位属于排队的等待者，新来的获取者不能插队
+		newcomer, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+		require.NoError(t, err)
+		assert.Nil(t, newcomer)
+
+		p := <-waiter
+		require.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("waiters are served in arrival order", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		first := startFairWaiter(waitCtx, sem)
+		second := startFairWaiter(waitCtx, sem)
+
+		require.NoError(t, holder.Release(ctx))
+		p1 := <-first
+		require.NotNil(t, p1)
+		select {
+		case <-second:
+			t.Fatal("second waiter acquired before first released")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		require.NoError(t, p1.Release(ctx))
+		p2 := <-second
+		require.NotNil(t, p2)
+		releasePermit(t, ctx, p2)
+	})
+
+	t.Run("timed out waiter leaves the queue", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+		defer cancel()
+		p, err := sem.WaitAcquire(waitCtx, "job", WithCapacity(1), WithFairQueue())
+		require.NoError(t, err)
+		assert.Nil(t, p)
+
+		require.NoError(t, holder.Release(ctx))
+		p, err = sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+		require.NoError(t, err)
+		assert.NotNil(t, p, "abandoned waiter should not hold its place")
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("exhausted Acquire leaves the queue", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+
+		_, err = sem.Acquire(ctx, "job", WithCapacity(1), WithFairQueue(),
+			WithMaxRetries(2), WithRetryDelay(5*time.Millisecond))
+		require.ErrorIs(t, err, ErrAcquireFailed)
+
+		require.NoError(t, holder.Release(ctx))
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+		require.NoError(t, err)
+		assert.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("Acquire succeeds when queue is empty", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.Acquire(ctx, "job", WithCapacity(1), WithFairQueue())
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("tenant blocked waiter does not block others", func(t *testing.T) {
+		sem := newSem(t)
+		held, err := sem.TryAcquire(ctx, "job", WithCapacity(2), WithTenantID("t1"), WithTenantQuota(1))
+		require.NoError(t, err)
+		require.NotNil(t, held)
+		defer releasePermit(t, ctx, held)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		blocked := startFairWaiter(waitCtx, sem, WithCapacity(2), WithTenantID("t1"), WithTenantQuota(1))
+
+		other, err := sem.WaitAcquire(waitCtx, "job", WithCapacity(2), WithFairQueue(), WithTenantID("t2"))
+		require.NoError(t, err)
+		assert.NotNil(t, other)
+		releasePermit(t, ctx, other)
+
+		cancel()
+		assert.Nil(t, <-blocked)
+	})
+
+	t.Run("reports queue position", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, ctx, holder)
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		first := startFairWaiter(waitCtx, sem)
+
+		// 排在第一个等待者之后，位置为 1 时放弃等待
+		var positions []int
+		giveUpCtx, giveUp := context.WithCancel(waitCtx)
+		defer giveUp()
+		p, err := sem.WaitAcquire(giveUpCtx, "job", WithCapacity(1), WithFairQueue(),
+			WithPollInterval(5*time.Millisecond),
+			WithQueuePositionCallback(func(ahead int) {
+				positions = append(positions, ahead)
+				if ahead >= 1 {
+					giveUp()
+				}
+			}))
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, p)
+		assert.Equal(t, []int{1}, positions)
+
+		cancel()
+		assert.Nil(t, <-first)
+	})
+
+	t.Run("Acquire reports head of queue", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, ctx, holder)
+
+		var positions []int
+		_, err = sem.Acquire(ctx, "job", WithCapacity(1), WithFairQueue(),
+			WithMaxRetries(2), WithRetryDelay(5*time.Millisecond),
+			WithQueuePositionCallback(func(ahead int) { positions = append(positions, ahead) }))
+		require.ErrorIs(t, err, ErrAcquireFailed)
+		assert.Equal(t, []int{0, 0}, positions)
+	})
+
+	t.Run("TryAcquire does not report position", func(t *testing.T) {
+		sem := newSem(t)
+		holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, ctx, holder)
+
+		called := false
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue(),
+			WithQueuePositionCallback(func(int) { called = true }))
+		require.NoError(t, err)
+		assert.Nil(t, p)
+		assert.False(t, called)
+	})
+}
+
+func TestFairQueue_RedisStaleWaiter(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			sem, mr := setupSemaphore(t, WithScriptMode(mode))
+			ctx := context.Background()
+			now := time.Now().UnixMilli()
+
+			queueKey := DefaultKeyPrefix + "{job}:queue"
+			aliveKey := DefaultKeyPrefix + "{job}:queue:alive"
+
+			// 存活中的等待者占据唯一空位
+			_, err := mr.ZAdd(queueKey, float64(now), "alive")
+			require.NoError(t, err)
+			_, err = mr.ZAdd(aliveKey, float64(now+time.Minute.Milliseconds()), "alive")
+			require.NoError(t, err)
+
+			p, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+			require.NoError(t, err)
+			assert.Nil(t, p)
+
+			// 失联的等待者（存活时间已过）被清理，不再占位
+			_, err = mr.ZAdd(aliveKey, float64(now-1), "alive")
+			require.NoError(t, err)
+
+			p, err = sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+			require.NoError(t, err)
+			require.NotNil(t, p)
+			releasePermit(t, ctx, p)
+
+			// 队列为空时键被删除，ZMembers 返回 ErrKeyNotFound
+			members, _ := mr.ZMembers(queueKey) //nolint:errcheck // 只关心成员是否为空
+			assert.Empty(t, members)
+		})
+	}
+}
+
+func TestFairQueue_LocalQueue(t *testing.T) {
+	rp := newResourcePermits()
+	now := time.Now()
+
+	assert.Equal(t, 0, rp.fairAheadLocked("a", now))
+	assert.Equal(t, 1, rp.fairAheadLocked("b", now))
+	assert.Equal(t, 0, rp.fairAheadLocked("a", now), "re-entering keeps position")
+	assert.Equal(t, 2, rp.fairAheadLocked("", now), "empty waiter does not enqueue")
+
+	// a 失联后被清理，b 前移；c 排在 b 之后
+	rp.queue[0].aliveUntil = now
+	assert.Equal(t, 1, rp.fairAheadLocked("c", now.Add(time.Millisecond)))
+	assert.Equal(t, 0, rp.fairAheadLocked("b", now.Add(time.Millisecond)))
+
+	rp.leaveFairQueueLocked("b")
+	assert.Equal(t, 0, rp.fairAheadLocked("c", now))
+}
+
+func TestFairQueue_FallbackLocal(t *testing.T) {
+	sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+	mr.Close()
+	ctx := context.Background()
+
+	holder, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	p, err := sem.WaitAcquire(waitCtx, "job", WithCapacity(1), WithFairQueue(), WithPollInterval(5*time.Millisecond))
+	require.NoError(t, err)
+	assert.Nil(t, p)
+
+	// 等待者已同时离开本地队列
+	require.NoError(t, holder.Release(ctx))
+	p, err = sem.TryAcquire(ctx, "job", WithCapacity(1), WithFairQueue())
+	require.NoError(t, err)
+	assert.NotNil(t, p)
+	releasePermit(t, ctx, p)
+}
+
+func TestFairQueue_HandoffIgnoresQueue(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+
+	old, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+	token, err := old.Handoff(ctx)
+	require.NoError(t, err)
+
+	p, err := sem.Acquire(ctx, "job", WithCapacity(1), WithFairQueue(), WithHandoffToken(token))
+	require.NoError(t, err)
+	require.NotNil(t, p)
+	releasePermit(t, ctx, p)
+}
+
+func TestFairQueue_RedisLeaveFailureIsLogged(t *testing.T) {
+	logger := &testLogger{}
+	sem, mr := setupSemaphore(t, WithLogger(logger))
+	mr.SetError("boom")
+
+	sem.(*redisSemaphore).leaveFairQueue(context.Background(), "job", "waiter")
+	assert.True(t, logger.warnCalled)
+}
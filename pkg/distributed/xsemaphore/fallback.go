@@ -32,6 +32,14 @@ type fallbackSemaphore struct {
 	// onFallback 回调限流
 	lastCallbackMu   sync.Mutex
 	lastCallbackTime time.Time
+
+	// localModeResources 当前处于本地降级模式的资源，用于检测模式切换
+	// 仅在配置 onFallbackTransition 时维护，资源回切到分布式后删除
+	modeMu             sync.Mutex
+	localModeResources map[string]struct{}
+
+	// held FallbackOpen 降级产生的空操作许可
+	held heldPermits
 }
 
 // newFallbackSemaphore 创建带降级的信号量
@@ -70,6 +78,7 @@ func (f *fallbackSemaphore) ensureLocalSemaphore() *localSemaphore {
 func (f *fallbackSemaphore) TryAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	permit, err := f.distributed.TryAcquire(ctx, resource, opts...)
 	if err == nil {
+		f.markDistributedMode(ctx, resource)
 		return permit, nil
 	}
 
@@ -80,6 +89,7 @@ func (f *fallbackSemaphore) TryAcquire(ctx context.Context, resource string, opt
 
 	// 处理 Redis 错误：记录日志、指标和触发回调
 	f.handleRedisError(ctx, resource, err)
+	f.markLocalMode(ctx, resource)
 
 	// 执行降级策略
 	return f.fallback(ctx, resource, opts)
@@ -89,6 +99,7 @@ func (f *fallbackSemaphore) TryAcquire(ctx context.Context, resource string, opt
 func (f *fallbackSemaphore) Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
 	permit, err := f.distributed.Acquire(ctx, resource, opts...)
 	if err == nil {
+		f.markDistributedMode(ctx, resource)
 		return permit, nil
 	}
 
@@ -99,11 +110,22 @@ func (f *fallbackSemaphore) Acquire(ctx context.Context, resource string, opts .
 
 	// 处理 Redis 错误：记录日志、指标和触发回调
 	f.handleRedisError(ctx, resource, err)
+	f.markLocalMode(ctx, resource)
 
 	// 执行降级策略
 	return f.fallbackAcquire(ctx, resource, opts)
 }
 
+// WaitAcquire 有界等待式获取许可，每次轮询独立执行降级判断
+//
+// Redis 不可用时按降级策略处理：FallbackLocal 在本地信号量上继续轮询，
+// FallbackOpen 立即返回虚拟许可，FallbackClose 返回 ErrRedisUnavailable。
+func (f *fallbackSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	ctx, cancel := applyDefaultTimeout(ctx, f.opts.defaultTimeout)
+	defer cancel()
+	return waitAcquire(ctx, resource, opts, f.TryAcquire, f.leaveFairQueue)
+}
+
 // logFallback 记录降级日志
 func (f *fallbackSemaphore) logFallback(ctx context.Context, resource string, err error) {
 	if f.opts.logger != nil {
@@ -170,6 +192,69 @@ func (f *fallbackSemaphore) safeOnFallback(ctx context.Context, resource string,
 	f.opts.onFallback(resource, f.strategy, err)
 }
 
+// markLocalMode 标记资源进入本地降级模式，首次进入时触发 onFallbackTransition
+// 在本地获取之前调用，localActive 反映上一轮降级遗留的仍有效本地许可
+func (f *fallbackSemaphore) markLocalMode(ctx context.Context, resource string) {
+	if f.opts.onFallbackTransition == nil || f.strategy != FallbackLocal {
+		return
+	}
+	f.modeMu.Lock()
+	if _, ok := f.localModeResources[resource]; ok {
+		f.modeMu.Unlock()
+		return
+	}
+	if f.localModeResources == nil {
+		f.localModeResources = make(map[string]struct{})
+	}
+	f.localModeResources[resource] = struct{}{}
+	f.modeMu.Unlock()
+
+	f.safeOnFallbackTransition(ctx, resource)
+}
+
+// markDistributedMode 标记资源回到分布式模式，从本地模式回切时触发 onFallbackTransition
+// 快路径：未降级过的资源仅有一次加锁查表
+func (f *fallbackSemaphore) markDistributedMode(ctx context.Context, resource string) {
+	if f.opts.onFallbackTransition == nil || f.strategy != FallbackLocal {
+		return
+	}
+	f.modeMu.Lock()
+	if _, ok := f.localModeResources[resource]; !ok {
+		f.modeMu.Unlock()
+		return
+	}
+	delete(f.localModeResources, resource)
+	f.modeMu.Unlock()
+
+	f.safeOnFallbackTransition(ctx, resource)
+}
+
+// safeOnFallbackTransition 统计本地有效许可数并安全调用回调，隔离 panic
+// 设计决策: 不做限流。切换按资源边沿触发（同一资源连续失败/成功只触发一次），
+// 频率天然受 Redis 故障/恢复次数约束，与 onFallback 的逐请求触发不同。
+func (f *fallbackSemaphore) safeOnFallbackTransition(ctx context.Context, resource string) {
+	f.localMu.Lock()
+	local := f.local
+	f.localMu.Unlock()
+
+	localActive := 0
+	if local != nil {
+		localActive, _ = local.countActivePermits(resource, "")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if f.opts.logger != nil {
+				f.opts.logger.Error(ctx, "onFallbackTransition callback panicked",
+					AttrResource(resource),
+					slog.Any("panic", r),
+				)
+			}
+		}
+	}()
+	f.opts.onFallbackTransition(resource, localActive)
+}
+
 // doFallback 执行降级策略的通用实现
 func (f *fallbackSemaphore) doFallback(ctx context.Context, resource string, opts []AcquireOption, tryAcquire bool) (Permit, error) {
 	switch f.strategy {
@@ -198,7 +283,12 @@ func (f *fallbackSemaphore) doFallback(ctx context.Context, resource string, opt
 		if tenantID == "" {
 			tenantID = xtenant.TenantID(ctx)
 		}
-		return newNoopPermit(ctx, resource, tenantID, cfg.ttl, cfg.metadata, f.opts)
+		permit, err := newNoopPermit(ctx, resource, tenantID, cfg.ttl, cfg.metadata, f.opts)
+		if err != nil {
+			return nil, err
+		}
+		f.held.track(&permit.permitBase, SemaphoreTypeNoop)
+		return permit, nil
 
 	case FallbackClose:
 		return nil, ErrRedisUnavailable
@@ -398,6 +488,18 @@ func (p *noopPermit) Extend(ctx context.Context) error {
 		})
 }
 
+// ExtendUntil 续期许可到指定截止时间
+// 与 Extend 一致，仅更新 expiresAt 并记录续期指标。
+func (p *noopPermit) ExtendUntil(ctx context.Context, deadline time.Time) error {
+	return p.extendUntilCommon(ctx, p.opts.tracer, SemaphoreTypeNoop, deadline,
+		func(ctx context.Context, _ time.Time) error {
+			if p.opts.metrics != nil {
+				p.opts.metrics.RecordExtend(ctx, SemaphoreTypeNoop, p.Resource(), true)
+			}
+			return nil
+		})
+}
+
 // StartAutoExtend 启动自动续租
 // 复用 permitBase.startAutoExtendLoop 模板方法，周期性调用 Extend 更新 expiresAt，
 // 确保 ExpiresAt() 在长时间运行的 FallbackOpen 任务中保持准确。
@@ -405,6 +507,14 @@ func (p *noopPermit) StartAutoExtend(interval time.Duration) func() {
 	return p.startAutoExtendLoop(interval, p.Extend, p)
 }
 
+// Handoff 转移许可
+// noop 许可不占用后端资源，返回的 token 无法在 Redis 上接管（返回 ErrHandoffExpired），
+// 仍在 FallbackOpen 期间的接管方会直接获得新的虚拟许可。
+func (p *noopPermit) Handoff(ctx context.Context) (string, error) {
+	return p.handoffCommon(ctx, p.opts.tracer, SemaphoreTypeNoop,
+		func(context.Context, time.Time) error { return nil })
+}
+
 // logExtendFailed 实现 loggerForExtend 接口
 func (p *noopPermit) logExtendFailed(ctx context.Context, permitID, resource string, err error) {
 	if p.opts.logger != nil {
@@ -419,6 +529,7 @@ func (p *noopPermit) logExtendFailed(ctx context.Context, permitID, resource str
 // 编译时接口检查
 var (
 	_ Semaphore       = (*fallbackSemaphore)(nil)
+	_ fairQueueLeaver = (*fallbackSemaphore)(nil)
 	_ Permit          = (*noopPermit)(nil)
 	_ loggerForExtend = (*noopPermit)(nil)
 )
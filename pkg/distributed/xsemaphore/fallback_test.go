@@ -900,3 +900,95 @@ func TestFallbackSemaphore_LazyLocalInitialization(t *testing.T) {
 }
 
 // setupRedis is defined in semaphore_test.go
+
+// =============================================================================
+// 降级模式切换回调测试
+// =============================================================================
+
+type fallbackTransition struct {
+	resource    string
+	localActive int
+}
+
+func TestFallbackSemaphore_OnFallbackTransition(t *testing.T) {
+	t.Run("reports enter and recovery with local active count", func(t *testing.T) {
+		mr, client := setupRedis(t)
+
+		var transitions []fallbackTransition
+		sem, err := New(client,
+			WithFallback(FallbackLocal),
+			WithOnFallbackTransition(func(resource string, localActive int) {
+				transitions = append(transitions, fallbackTransition{resource, localActive})
+			}),
+		)
+		require.NoError(t, err)
+		defer closeSemaphore(t, sem)
+		ctx := context.Background()
+
+		p, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		releasePermit(t, ctx, p)
+		assert.Empty(t, transitions, "no transition while distributed is healthy")
+
+		mr.Close()
+		local1, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, local1)
+		local2, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, local2)
+		require.Equal(t, []fallbackTransition{{"res", 0}}, transitions, "only the first fallback is a transition")
+
+		require.NoError(t, mr.Restart())
+		p, err = sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, []fallbackTransition{{"res", 0}, {"res", 2}}, transitions,
+			"recovery reports local permits still held")
+
+		p2, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		assert.Len(t, transitions, 2, "subsequent distributed acquires are not transitions")
+
+		releasePermit(t, ctx, p)
+		releasePermit(t, ctx, p2)
+		releasePermit(t, ctx, local1)
+		releasePermit(t, ctx, local2)
+	})
+
+	t.Run("ignored for non-local strategies", func(t *testing.T) {
+		mr, client := setupRedis(t)
+
+		var called atomic.Bool
+		sem, err := New(client,
+			WithFallback(FallbackOpen),
+			WithOnFallbackTransition(func(string, int) { called.Store(true) }),
+		)
+		require.NoError(t, err)
+		defer closeSemaphore(t, sem)
+
+		mr.Close()
+		p, err := sem.TryAcquire(context.Background(), "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.False(t, called.Load())
+	})
+
+	t.Run("callback panic is isolated", func(t *testing.T) {
+		mr, client := setupRedis(t)
+
+		sem, err := New(client,
+			WithFallback(FallbackLocal),
+			WithOnFallbackTransition(func(string, int) { panic("boom") }),
+		)
+		require.NoError(t, err)
+		defer closeSemaphore(t, sem)
+
+		mr.Close()
+		ctx := context.Background()
+		p, err := sem.Acquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+}
@@ -0,0 +1,248 @@
+package xsemaphore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// =============================================================================
+// 许可转移（Handoff）
+//
+// 转移分两步完成：
+//  1. 原持有者调用 Permit.Handoff：续期许可（为接管方预留完整 TTL）并交出本地所有权，
+//     返回 token。此后原句柄的 Release/Extend 不再作用于后端。
+//  2. 接管方调用 Acquire/TryAcquire 并传入 WithHandoffToken：后端原子地以新许可
+//     替换原许可，许可总数不变，不存在被其他获取者抢占的窗口。
+//
+// 接管方未在 TTL 内完成接管时，原许可按正常过期流程被回收，不会泄漏。
+// =============================================================================
+
+// handoffTokenPrefix 转移 token 前缀，携带格式版本，便于未来演进
+const handoffTokenPrefix = "xsemaphore.v1."
+
+// handoffPayload 转移 token 携带的原许可信息
+type handoffPayload struct {
+	Resource    string `json:"r"`
+	TenantID    string `json:"t,omitempty"`
+	PermitID    string `json:"p"`
+	TenantQuota bool   `json:"q,omitempty"` // 原许可是否启用了租户配额
+	Count       int    `json:"n,omitempty"` // 原许可的名额数（AcquireN），缺省为 1
+}
+
+// encodeHandoffToken 将原许可信息编码为可跨进程传递的 token
+func encodeHandoffToken(b *permitBase) (string, error) {
+	data, err := json.Marshal(handoffPayload{
+		Resource:    b.resource,
+		TenantID:    b.tenantID,
+		PermitID:    b.id,
+		TenantQuota: b.hasTenantQuota,
+		Count:       b.count,
+	})
+	if err != nil {
+		return "", fmt.Errorf("xsemaphore: encode handoff token: %w", err)
+	}
+	return handoffTokenPrefix + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// parseHandoffToken 解析转移 token，并校验其资源名与本次获取一致
+func parseHandoffToken(token, resource string) (*handoffPayload, error) {
+	payload, err := decodeHandoffToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Resource != resource {
+		return nil, fmt.Errorf("%w: token is for resource %q, not %q", ErrInvalidHandoffToken, payload.Resource, resource)
+	}
+	return payload, nil
+}
+
+// decodeHandoffToken 解码并校验转移 token 的格式，不检查资源名
+func decodeHandoffToken(token string) (*handoffPayload, error) {
+	encoded, ok := strings.CutPrefix(token, handoffTokenPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidHandoffToken)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHandoffToken, err)
+	}
+	var payload handoffPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHandoffToken, err)
+	}
+	if payload.PermitID == "" {
+		return nil, fmt.Errorf("%w: missing permit ID", ErrInvalidHandoffToken)
+	}
+	if err := validateTenantID(payload.TenantID); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHandoffToken, err)
+	}
+	if payload.Count < 0 || payload.Count > MaxPermitCount {
+		return nil, fmt.Errorf("%w: invalid permit count %d", ErrInvalidHandoffToken, payload.Count)
+	}
+	payload.Count = max(1, payload.Count)
+	return &payload, nil
+}
+
+// recordHandoffSource 在当前 span 上记录被接管的原许可 ID
+func recordHandoffSource(ctx context.Context, h *handoffPayload) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String(attrHandoffFrom, h.PermitID))
+}
+
+// =============================================================================
+// Redis 接管
+// =============================================================================
+
+// doHandoff 以新许可原子替换 token 指向的原许可
+func (s *redisSemaphore) doHandoff(ctx context.Context, resource string, cfg *acquireOptions) (Permit, AcquireFailReason, error) {
+	h := cfg.handoff
+	recordHandoffSource(ctx, h)
+
+	permitID, err := s.opts.effectiveIDGenerator()(ctx)
+	if err != nil {
+		// 设计决策: 使用 %v 而非 %w 包装内部错误，与 doAcquire 保持一致。
+		return nil, ReasonUnknown, fmt.Errorf("%w: %v", ErrIDGenerationFailed, err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(cfg.ttl)
+
+	globalKey := s.buildGlobalKey(resource)
+	var tenantKey string
+	if h.TenantID != "" && h.TenantQuota {
+		tenantKey = s.buildTenantKey(resource, h.TenantID)
+	}
+
+	if s.scriptMode == rediscompat.ScriptModeCompat {
+		err = s.handoffCompat(ctx, globalKey, tenantKey, h.PermitID, permitID, h.Count, now.UnixMilli(), expiresAt.UnixMilli())
+	} else {
+		err = s.handoffScript(ctx, globalKey, tenantKey, h.PermitID, permitID, h.Count, now.UnixMilli(), expiresAt.UnixMilli())
+	}
+	if err != nil {
+		return nil, ReasonUnknown, err
+	}
+
+	permit := newRedisPermit(s, permitID, resource, h.TenantID, expiresAt, cfg.ttl, h.TenantQuota, cfg.metadata)
+	permit.count = h.Count
+	permit.capacity = cfg.capacity
+	s.held.track(&permit.permitBase, SemaphoreTypeDistributed)
+	return permit, ReasonUnknown, nil
+}
+
+// handoffScript 通过 handoff.lua 原子替换许可
+func (s *redisSemaphore) handoffScript(ctx context.Context, globalKey, tenantKey, oldID, newID string, count int, nowMs, expireAtMs int64) error {
+	// 动态构建 KEYS 数组（Redis Cluster 兼容）
+	keys := []string{globalKey}
+	if tenantKey != "" {
+		keys = append(keys, tenantKey)
+	}
+	args := []any{
+		nowMs,
+		expireAtMs,
+		oldID,
+		newID,
+		keyTTLMargin.Milliseconds(),
+		count,
+	}
+
+	result, err := s.evalScriptInt64Slice(ctx, s.scripts.handoff, keys, args...)
+	if err != nil {
+		return fmt.Errorf("handoff script failed: %w", err)
+	}
+	// 验证结果长度：handoff 返回 {status}
+	if err := validateScriptResult(result, 1); err != nil {
+		return fmt.Errorf("handoff script failed: %w", err)
+	}
+
+	switch status := int(result[0]); status {
+	case scriptStatusOK:
+		return nil
+	case scriptStatusNotHeld:
+		return ErrHandoffExpired
+	default:
+		return fmt.Errorf("%w: handoff returned status %d", ErrUnknownScriptStatus, status)
+	}
+}
+
+// =============================================================================
+// 本地接管
+// =============================================================================
+
+// holdsExactly 报告 entries 是否恰好包含许可 id 的全部成员 members
+func holdsExactly(entries map[string]*permitEntry, id string, members []string) bool {
+	for _, member := range members {
+		if _, ok := entries[member]; !ok {
+			return false
+		}
+	}
+	_, extra := entries[id+permitMemberSeparator+strconv.Itoa(len(members))]
+	return !extra
+}
+
+// doHandoff 以新许可替换 token 指向的原许可（本地实现）
+//
+// 仅能接管同一 localSemaphore 内的许可，适用于进程内的任务迁移。
+func (s *localSemaphore) doHandoff(ctx context.Context, resource string, cfg *acquireOptions) (Permit, AcquireFailReason, error) {
+	h := cfg.handoff
+	recordHandoffSource(ctx, h)
+
+	// 在锁外生成许可 ID，与 doAcquire 保持一致
+	permitID, err := s.opts.effectiveIDGenerator()(ctx)
+	if err != nil {
+		return nil, ReasonUnknown, fmt.Errorf("%w: %v", ErrIDGenerationFailed, err)
+	}
+
+	rp := s.tryGetResourcePermits(resource)
+	if rp == nil {
+		return nil, ReasonUnknown, ErrHandoffExpired
+	}
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	now := time.Now()
+	old, ok := rp.global[h.PermitID]
+	if !ok || !old.expiresAt.After(now) {
+		s.cleanupExpiredLocked(rp, now)
+		return nil, ReasonUnknown, ErrHandoffExpired
+	}
+
+	hasTenantQuota := old.tenantID != "" && h.TenantQuota
+	// 与 handoff.lua 一致：token 的名额数必须与原许可的实际成员数一致
+	oldMembers := permitMemberIDs(h.PermitID, h.Count)
+	if !holdsExactly(rp.global, h.PermitID, oldMembers) ||
+		(hasTenantQuota && !holdsExactly(rp.tenants[old.tenantID], h.PermitID, oldMembers)) {
+		return nil, ReasonUnknown, ErrHandoffExpired
+	}
+
+	expiresAt := now.Add(cfg.ttl)
+
+	// 批量许可的成员一一对应替换
+	for i, member := range permitMemberIDs(permitID, h.Count) {
+		entry := &permitEntry{
+			id:        member,
+			resource:  resource,
+			tenantID:  old.tenantID,
+			expiresAt: expiresAt,
+		}
+		delete(rp.global, oldMembers[i])
+		rp.global[member] = entry
+		if hasTenantQuota {
+			delete(rp.tenants[old.tenantID], oldMembers[i])
+			rp.tenants[old.tenantID][member] = entry
+		}
+	}
+
+	permit := newLocalPermit(s, permitID, resource, old.tenantID, expiresAt, cfg.ttl, hasTenantQuota, cfg.metadata)
+	permit.count = h.Count
+	permit.capacity, _ = s.calculateLocalCapacity(cfg)
+	s.held.track(&permit.permitBase, SemaphoreTypeLocal)
+	return permit, ReasonUnknown, nil
+}
@@ -0,0 +1,286 @@
+package xsemaphore
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// Handoff 测试
+// =============================================================================
+
+func TestHandoff_Redis(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testHandoff(t, func(t *testing.T) Semaphore {
+				sem, _ := setupSemaphore(t, WithScriptMode(mode))
+				return sem
+			})
+		})
+	}
+}
+
+func TestHandoff_Local(t *testing.T) {
+	testHandoff(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	})
+}
+
+func testHandoff(t *testing.T, newSem func(t *testing.T) Semaphore) {
+	ctx := context.Background()
+
+	t.Run("takeover keeps the slot", func(t *testing.T) {
+		sem := newSem(t)
+		old, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithTTL(time.Minute))
+		require.NoError(t, err)
+		require.NotNil(t, old)
+
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+
+		// 转移后、接管前，许可仍占用容量，其他获取者无法抢占
+		other, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		assert.Nil(t, other)
+
+		p, err := sem.Acquire(ctx, "job", WithCapacity(1), WithTTL(time.Minute),
+			WithHandoffToken(token), WithMetadata(map[string]string{"k": "v"}))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.NotEqual(t, old.ID(), p.ID())
+		assert.Equal(t, "v", p.Metadata()["k"])
+
+		info, err := sem.Query(ctx, "job", QueryWithCapacity(1))
+		require.NoError(t, err)
+		assert.Equal(t, 1, info.GlobalUsed)
+
+		// 原句柄已交出所有权
+		require.NoError(t, old.Release(ctx))
+		assert.ErrorIs(t, old.Extend(ctx), ErrPermitNotHeld)
+		require.NoError(t, p.Extend(ctx))
+
+		require.NoError(t, p.Release(ctx))
+		info, err = sem.Query(ctx, "job", QueryWithCapacity(1))
+		require.NoError(t, err)
+		assert.Equal(t, 0, info.GlobalUsed)
+	})
+
+	t.Run("token is single use", func(t *testing.T) {
+		sem := newSem(t)
+		old, err := sem.TryAcquire(ctx, "job", WithCapacity(2))
+		require.NoError(t, err)
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(2), WithHandoffToken(token))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		defer releasePermit(t, ctx, p)
+
+		_, err = sem.TryAcquire(ctx, "job", WithCapacity(2), WithHandoffToken(token))
+		assert.ErrorIs(t, err, ErrHandoffExpired)
+	})
+
+	t.Run("expired before takeover", func(t *testing.T) {
+		sem := newSem(t)
+		old, err := sem.TryAcquire(ctx, "job", WithCapacity(1), WithTTL(30*time.Millisecond))
+		require.NoError(t, err)
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+
+		time.Sleep(60 * time.Millisecond)
+
+		_, err = sem.Acquire(ctx, "job", WithCapacity(1), WithHandoffToken(token))
+		assert.ErrorIs(t, err, ErrHandoffExpired)
+
+		// 过期的许可已被回收，普通获取可以成功
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		assert.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("tenant quota is preserved", func(t *testing.T) {
+		sem := newSem(t)
+		old, err := sem.TryAcquire(ctx, "job", WithCapacity(10), WithTenantID("t1"), WithTenantQuota(1))
+		require.NoError(t, err)
+		require.NotNil(t, old)
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+
+		// 接管方不需要重复传入租户配置
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(10), WithHandoffToken(token))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, "t1", p.TenantID())
+
+		other, err := sem.TryAcquire(ctx, "job", WithCapacity(10), WithTenantID("t1"), WithTenantQuota(1))
+		require.NoError(t, err)
+		assert.Nil(t, other, "tenant slot should still be held")
+
+		require.NoError(t, p.Release(ctx))
+		other, err = sem.TryAcquire(ctx, "job", WithCapacity(10), WithTenantID("t1"), WithTenantQuota(1))
+		require.NoError(t, err)
+		assert.NotNil(t, other)
+		releasePermit(t, ctx, other)
+	})
+
+	t.Run("token count must match held members", func(t *testing.T) {
+		sem := newSem(t)
+		tenantOpts := []AcquireOption{WithCapacity(4), WithTenantID("t1"), WithTenantQuota(4)}
+		old, err := sem.AcquireN(ctx, "job", 2, tenantOpts...)
+		require.NoError(t, err)
+		require.NotNil(t, old)
+		token, err := old.Handoff(ctx)
+		require.NoError(t, err)
+
+		// token 未签名：篡改名额数不能凭空新增许可或遗留原成员
+		for _, n := range []int{1, 4} {
+			_, err = sem.TryAcquire(ctx, "job", WithCapacity(4), WithHandoffToken(withHandoffCount(t, token, n)))
+			assert.ErrorIs(t, err, ErrHandoffExpired, "count %d", n)
+		}
+
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(4), WithHandoffToken(token))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		defer releasePermit(t, ctx, p)
+
+		info, err := sem.Query(ctx, "job", QueryWithCapacity(4), QueryWithTenantID("t1"), QueryWithTenantQuota(4))
+		require.NoError(t, err)
+		assert.Equal(t, 2, info.GlobalUsed)
+		assert.Equal(t, 2, info.TenantUsed)
+	})
+
+	t.Run("released permit cannot hand off", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		require.NoError(t, p.Release(ctx))
+
+		_, err = p.Handoff(ctx)
+		assert.ErrorIs(t, err, ErrPermitNotHeld)
+	})
+
+	t.Run("handoff twice", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		_, err = p.Handoff(ctx)
+		require.NoError(t, err)
+
+		_, err = p.Handoff(ctx)
+		assert.ErrorIs(t, err, ErrPermitNotHeld)
+	})
+
+	t.Run("nil context", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, ctx, p)
+
+		_, err = p.Handoff(nil) //nolint:staticcheck // 测试 nil context
+		assert.ErrorIs(t, err, ErrNilContext)
+	})
+}
+
+func TestHandoff_StopsAutoExtend(t *testing.T) {
+	sem := newLocalSemaphore(defaultOptions())
+	defer closeSemaphore(t, sem)
+	ctx := context.Background()
+
+	p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+	lp := p.(*localPermit)
+	lp.StartAutoExtend(time.Hour)
+
+	_, err = p.Handoff(ctx)
+	require.NoError(t, err)
+
+	lp.autoExtendMu.Lock()
+	defer lp.autoExtendMu.Unlock()
+	assert.False(t, lp.autoRunning)
+}
+
+func TestHandoff_ExtendFailureKeepsOwnership(t *testing.T) {
+	sem, mr := setupSemaphore(t)
+	ctx := context.Background()
+
+	p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+
+	mr.SetError("connection refused")
+	_, err = p.Handoff(ctx)
+	require.Error(t, err)
+	mr.SetError("")
+
+	// 转移失败时许可仍由原持有者持有
+	require.NoError(t, p.Extend(ctx))
+	require.NoError(t, p.Release(ctx))
+}
+
+// withHandoffCount 返回名额数被改写为 n 的转移 token
+func withHandoffCount(t *testing.T, token string, n int) string {
+	t.Helper()
+	payload, err := decodeHandoffToken(token)
+	require.NoError(t, err)
+	payload.Count = n
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return handoffTokenPrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+func TestHandoff_InvalidToken(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+
+	p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+	token, err := p.Handoff(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		resource string
+		token    string
+	}{
+		{"resource mismatch", "other", token},
+		{"unknown prefix", "job", "garbage"},
+		{"bad base64", "job", handoffTokenPrefix + "!!!"},
+		{"bad json", "job", handoffTokenPrefix + "bm90LWpzb24"},
+		{"missing permit id", "job", handoffTokenPrefix + "eyJyIjoiam9iIn0"},
+		{"truncated", "job", strings.TrimSuffix(token, token[len(token)-4:])},
+		{"count exceeds max", "job", handoffTokenPrefix + base64.RawURLEncoding.EncodeToString(
+			[]byte(fmt.Sprintf(`{"r":"job","p":"x","n":%d}`, MaxPermitCount+1)))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := sem.TryAcquire(ctx, tt.resource, WithCapacity(1), WithHandoffToken(tt.token))
+			assert.ErrorIs(t, err, ErrInvalidHandoffToken)
+		})
+	}
+}
+
+func TestHandoff_NoopPermit(t *testing.T) {
+	p, err := newNoopPermit(context.Background(), "job", "", time.Minute, nil, defaultOptions())
+	require.NoError(t, err)
+
+	token, err := p.Handoff(context.Background())
+	require.NoError(t, err)
+
+	payload, err := parseHandoffToken(token, "job")
+	require.NoError(t, err)
+	assert.Equal(t, p.ID(), payload.PermitID)
+	assert.True(t, p.isReleased())
+}
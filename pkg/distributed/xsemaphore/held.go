@@ -0,0 +1,114 @@
+package xsemaphore
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// 持有许可的本地记录
+// =============================================================================
+
+// HeldPermit 描述当前进程持有的一个许可，由 [Semaphore.Held] 返回
+type HeldPermit struct {
+	// ID 许可 ID
+	ID string
+
+	// Resource 资源名称
+	Resource string
+
+	// TenantID 租户 ID（未使用租户时为空）
+	TenantID string
+
+	// Type 许可类型（SemaphoreTypeDistributed/SemaphoreTypeLocal/SemaphoreTypeNoop）
+	Type string
+
+	// Count 许可包含的名额数（AcquireN 获取时大于 1）
+	Count int
+
+	// AcquiredAt 获取许可的时间
+	AcquiredAt time.Time
+
+	// ExpiresAt 许可过期时间（随 Extend/ExtendUntil 更新）
+	ExpiresAt time.Time
+}
+
+// heldPermits 记录信号量已获取且尚未释放的许可
+//
+// 设计决策: 记录 permitBase 指针而非快照，ExpiresAt 在查询时读取，
+// 续期路径无需额外维护；释放（含 Handoff 交出所有权）时经 markReleased 移除。
+type heldPermits struct {
+	m sync.Map // *permitBase -> permitType string
+}
+
+// track 记录许可，并让许可在释放时自动移除
+func (h *heldPermits) track(b *permitBase, permitType string) {
+	b.acquiredAt = time.Now()
+	b.held = h
+	h.m.Store(b, permitType)
+}
+
+// remove 移除许可，重复移除无副作用
+func (h *heldPermits) remove(b *permitBase) {
+	h.m.Delete(b)
+}
+
+// list 返回全部记录，按获取时间升序、ID 升序排列
+func (h *heldPermits) list() []HeldPermit {
+	var out []HeldPermit
+	h.m.Range(func(k, v any) bool {
+		b, ok := k.(*permitBase)
+		if !ok {
+			return true
+		}
+		permitType, _ := v.(string)
+		out = append(out, HeldPermit{
+			ID:         b.id,
+			Resource:   b.resource,
+			TenantID:   b.tenantID,
+			Type:       permitType,
+			Count:      max(b.count, 1),
+			AcquiredAt: b.acquiredAt,
+			ExpiresAt:  b.ExpiresAt(),
+		})
+		return true
+	})
+	sortHeldPermits(out)
+	return out
+}
+
+// sortHeldPermits 按获取时间升序、ID 升序排序
+func sortHeldPermits(permits []HeldPermit) {
+	slices.SortFunc(permits, func(a, b HeldPermit) int {
+		if c := a.AcquiredAt.Compare(b.AcquiredAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// Held 列出当前进程持有的分布式许可
+func (s *redisSemaphore) Held() []HeldPermit {
+	return s.held.list()
+}
+
+// Held 列出当前进程持有的本地许可
+func (s *localSemaphore) Held() []HeldPermit {
+	return s.held.list()
+}
+
+// Held 列出当前进程持有的分布式、本地降级和空操作许可
+func (f *fallbackSemaphore) Held() []HeldPermit {
+	out := f.distributed.Held()
+	f.localMu.Lock()
+	local := f.local
+	f.localMu.Unlock()
+	if local != nil {
+		out = append(out, local.Held()...)
+	}
+	out = append(out, f.held.list()...)
+	sortHeldPermits(out)
+	return out
+}
@@ -0,0 +1,112 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeld_Redis(t *testing.T) {
+	testHeld(t, func(t *testing.T) Semaphore {
+		sem, _ := setupSemaphore(t)
+		return sem
+	}, SemaphoreTypeDistributed)
+}
+
+func TestHeld_Local(t *testing.T) {
+	testHeld(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	}, SemaphoreTypeLocal)
+}
+
+// testHeld 验证 Held 的通用语义
+func testHeld(t *testing.T, newSem func(t *testing.T) Semaphore, permitType string) {
+	ctx := context.Background()
+
+	t.Run("tracks until release", func(t *testing.T) {
+		sem := newSem(t)
+		assert.Empty(t, sem.Held())
+
+		first, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithTTL(time.Minute))
+		require.NoError(t, err)
+		second, err := sem.AcquireN(ctx, "res", 3, WithCapacity(10), WithTenantID("t1"))
+		require.NoError(t, err)
+
+		held := sem.Held()
+		require.Len(t, held, 2)
+		assert.Equal(t, first.ID(), held[0].ID)
+		assert.Equal(t, "res", held[0].Resource)
+		assert.Equal(t, permitType, held[0].Type)
+		assert.Equal(t, 1, held[0].Count)
+		assert.False(t, held[0].AcquiredAt.IsZero())
+		assert.Equal(t, 3, held[1].Count)
+		assert.Equal(t, "t1", held[1].TenantID)
+
+		require.NoError(t, first.ExtendUntil(ctx, time.Now().Add(time.Hour)))
+		assert.Equal(t, first.ExpiresAt(), sem.Held()[0].ExpiresAt)
+
+		require.NoError(t, first.Release(ctx))
+		held = sem.Held()
+		require.Len(t, held, 1)
+		assert.Equal(t, second.ID(), held[0].ID)
+
+		require.NoError(t, second.Release(ctx))
+		assert.Empty(t, sem.Held())
+	})
+
+	t.Run("handoff transfers ownership", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		token, err := p.Handoff(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, sem.Held(), "handed-off permit is no longer held")
+
+		taken, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithHandoffToken(token))
+		require.NoError(t, err)
+		held := sem.Held()
+		require.Len(t, held, 1)
+		assert.Equal(t, taken.ID(), held[0].ID)
+		releasePermit(t, ctx, taken)
+	})
+}
+
+func TestHeld_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merges distributed and local", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+		dist, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+
+		mr.Close()
+		local, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+
+		held := sem.Held()
+		require.Len(t, held, 2)
+		assert.Equal(t, dist.ID(), held[0].ID)
+		assert.Equal(t, SemaphoreTypeDistributed, held[0].Type)
+		assert.Equal(t, local.ID(), held[1].ID)
+		assert.Equal(t, SemaphoreTypeLocal, held[1].Type)
+	})
+
+	t.Run("open", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackOpen))
+		mr.Close()
+
+		p, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		held := sem.Held()
+		require.Len(t, held, 1)
+		assert.Equal(t, SemaphoreTypeNoop, held[0].Type)
+
+		require.NoError(t, p.Release(ctx))
+		assert.Empty(t, sem.Held())
+	})
+}
@@ -0,0 +1,144 @@
+package xsemaphore
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// prepareInspect 准备 Inspect 的公共逻辑，返回租户过滤条件
+//
+// 设计决策: 租户只取 QueryWithTenantID 的显式值，不从 context 提取。
+// Inspect 用于排查，请求 context 中的租户不应隐式地过滤掉其他租户的许可。
+func prepareInspect(ctx context.Context, resource string, opts []QueryOption, closed bool) (string, error) {
+	if err := validateCommonParams(ctx, resource, closed); err != nil {
+		return "", err
+	}
+	tenantID := applyQueryOptions(opts).tenantID
+	if err := validateTenantID(tenantID); err != nil {
+		return "", err
+	}
+	return tenantID, nil
+}
+
+// Inspect 列出资源当前的有效许可
+func (s *redisSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	ctx, cancel := applyDefaultTimeout(ctx, s.opts.defaultTimeout)
+	defer cancel()
+
+	tenantID, err := prepareInspect(ctx, resource, opts, s.closed.Load())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := startSpan(ctx, s.opts.tracer, spanNameInspect)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String(attrSemType, SemaphoreTypeDistributed),
+		attribute.String(attrResource, resource),
+	)
+
+	key := s.buildGlobalKey(resource)
+	if tenantID != "" {
+		span.SetAttributes(attribute.String(attrTenantID, tenantID))
+		key = s.buildTenantKey(resource, tenantID)
+	}
+
+	// 使用只读命令而非 Lua 脚本，兼容模式无需分流，且可被路由到从节点
+	// "(" 前缀表示开区间，与 query.lua 一致排除恰好等于 now 的过期条目
+	members, err := s.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		setSpanError(span, err)
+		return nil, fmt.Errorf("inspect failed: %w", err)
+	}
+
+	permits := make([]PermitInfo, 0, len(members))
+	for _, m := range members {
+		id, ok := m.Member.(string)
+		if !ok {
+			continue // 设计决策: go-redis 对 ZRANGE 成员总是返回 string，此分支不可达
+		}
+		permits = append(permits, PermitInfo{
+			ID:        id,
+			ExpiresAt: time.UnixMilli(int64(m.Score)),
+			TenantID:  tenantID,
+		})
+	}
+
+	span.SetAttributes(attribute.Int(attrGlobalUsed, len(permits)))
+	setSpanOK(span)
+	return permits, nil
+}
+
+// Inspect 列出本地资源当前的有效许可
+func (s *localSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	tenantID, err := prepareInspect(ctx, resource, opts, s.closed.Load())
+	if err != nil {
+		return nil, err
+	}
+
+	rp := s.tryGetResourcePermits(resource)
+	if rp == nil {
+		return []PermitInfo{}, nil
+	}
+
+	rp.mu.RLock()
+	entries := rp.global
+	if tenantID != "" {
+		entries = rp.tenants[tenantID]
+	}
+	now := time.Now()
+	permits := make([]PermitInfo, 0, len(entries))
+	for _, e := range entries {
+		// 与 countActivePermits 一致：只读，不清理，按 After(now) 排除过期许可
+		if e.expiresAt.After(now) {
+			permits = append(permits, PermitInfo{ID: e.id, ExpiresAt: e.expiresAt, TenantID: e.tenantID})
+		}
+	}
+	rp.mu.RUnlock()
+
+	// 与 Redis ZSET 的顺序对齐：按过期时间升序，相同时按 ID 排序
+	slices.SortFunc(permits, func(a, b PermitInfo) int {
+		if c := a.ExpiresAt.Compare(b.ExpiresAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return permits, nil
+}
+
+// Inspect 列出资源当前的有效许可，Redis 不可用时按降级策略处理
+//
+// FallbackLocal 返回本地信号量的许可，FallbackOpen 返回空列表（虚拟许可不占用资源），
+// FallbackClose 返回 ErrRedisUnavailable。
+func (f *fallbackSemaphore) Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error) {
+	permits, err := f.distributed.Inspect(ctx, resource, opts...)
+	if err == nil || !IsRedisError(err) {
+		return permits, err
+	}
+
+	// 与 Query 相同：记录降级可观测性信息，不触发 onFallback 回调
+	f.recordFallbackObservability(ctx, resource, err)
+
+	switch f.strategy {
+	case FallbackLocal:
+		local := f.ensureLocalSemaphore()
+		if local == nil {
+			return nil, ErrSemaphoreClosed
+		}
+		return local.Inspect(ctx, resource, opts...)
+	case FallbackOpen:
+		return []PermitInfo{}, nil
+	default:
+		return nil, ErrRedisUnavailable
+	}
+}
@@ -0,0 +1,152 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/pkg/context/xtenant"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect_Redis(t *testing.T) {
+	testInspect(t, func(t *testing.T) Semaphore {
+		sem, _ := setupSemaphore(t)
+		return sem
+	}, false)
+}
+
+func TestInspect_Local(t *testing.T) {
+	testInspect(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	}, true)
+}
+
+// testInspect 验证 Inspect 的通用语义；localTenants 表示实现是否在全局列表中记录租户
+func testInspect(t *testing.T, newSem func(t *testing.T) Semaphore, localTenants bool) {
+	ctx := context.Background()
+
+	t.Run("empty resource", func(t *testing.T) {
+		sem := newSem(t)
+		permits, err := sem.Inspect(ctx, "none")
+		require.NoError(t, err)
+		assert.Empty(t, permits)
+	})
+
+	t.Run("lists holders ordered by expiry", func(t *testing.T) {
+		sem := newSem(t)
+		long, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithTTL(time.Hour))
+		require.NoError(t, err)
+		short, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithTTL(time.Minute))
+		require.NoError(t, err)
+		tenant, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithTTL(2*time.Minute),
+			WithTenantID("t1"), WithTenantQuota(5))
+		require.NoError(t, err)
+
+		permits, err := sem.Inspect(ctx, "res")
+		require.NoError(t, err)
+		require.Len(t, permits, 3)
+		assert.Equal(t, []string{short.ID(), tenant.ID(), long.ID()},
+			[]string{permits[0].ID, permits[1].ID, permits[2].ID})
+		assert.WithinDuration(t, short.ExpiresAt(), permits[0].ExpiresAt, time.Millisecond)
+		if localTenants {
+			assert.Equal(t, "t1", permits[1].TenantID)
+		} else {
+			assert.Empty(t, permits[1].TenantID)
+		}
+
+		tenantPermits, err := sem.Inspect(ctx, "res", QueryWithTenantID("t1"))
+		require.NoError(t, err)
+		require.Len(t, tenantPermits, 1)
+		assert.Equal(t, tenant.ID(), tenantPermits[0].ID)
+		assert.Equal(t, "t1", tenantPermits[0].TenantID)
+
+		// Inspect 只读，释放后不再列出
+		require.NoError(t, short.Release(ctx))
+		permits, err = sem.Inspect(ctx, "res")
+		require.NoError(t, err)
+		assert.Len(t, permits, 2)
+
+		releasePermit(t, ctx, long)
+		releasePermit(t, ctx, tenant)
+	})
+
+	t.Run("excludes expired permits", func(t *testing.T) {
+		sem := newSem(t)
+		_, err := sem.TryAcquire(ctx, "res", WithCapacity(10), WithTTL(20*time.Millisecond))
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+
+		permits, err := sem.Inspect(ctx, "res")
+		require.NoError(t, err)
+		assert.Empty(t, permits)
+	})
+
+	t.Run("context tenant is not applied", func(t *testing.T) {
+		sem := newSem(t)
+		p, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		defer releasePermit(t, ctx, p)
+
+		tenantCtx, err := xtenant.WithTenantID(ctx, "other")
+		require.NoError(t, err)
+		permits, err := sem.Inspect(tenantCtx, "res")
+		require.NoError(t, err)
+		assert.Len(t, permits, 1)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		sem := newSem(t)
+		_, err := sem.Inspect(nil, "res") //nolint:staticcheck // 测试 nil context
+		assert.ErrorIs(t, err, ErrNilContext)
+		_, err = sem.Inspect(ctx, "")
+		assert.ErrorIs(t, err, ErrInvalidResource)
+		_, err = sem.Inspect(ctx, "res", QueryWithTenantID("bad:tenant"))
+		assert.ErrorIs(t, err, ErrInvalidTenantID)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		sem := newSem(t)
+		require.NoError(t, sem.Close(ctx))
+		_, err := sem.Inspect(ctx, "res")
+		assert.ErrorIs(t, err, ErrSemaphoreClosed)
+	})
+}
+
+func TestInspect_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("local", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+		mr.Close()
+
+		p, err := sem.TryAcquire(ctx, "res", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+
+		permits, err := sem.Inspect(ctx, "res")
+		require.NoError(t, err)
+		require.Len(t, permits, 1)
+		assert.Equal(t, p.ID(), permits[0].ID)
+	})
+
+	t.Run("open", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackOpen))
+		mr.Close()
+
+		permits, err := sem.Inspect(ctx, "res")
+		require.NoError(t, err)
+		assert.Empty(t, permits)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackClose))
+		mr.Close()
+
+		_, err := sem.Inspect(ctx, "res")
+		assert.ErrorIs(t, err, ErrRedisUnavailable)
+	})
+}
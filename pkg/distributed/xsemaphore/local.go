@@ -28,6 +28,7 @@ type localSemaphore struct {
 	opts    *options
 	permits sync.Map // resource -> *resourcePermits
 	closed  atomic.Bool
+	held    heldPermits
 
 	// 后台清理
 	cleanupOnce   sync.Once
@@ -41,6 +42,7 @@ type resourcePermits struct {
 	mu      sync.RWMutex
 	global  map[string]*permitEntry            // permitID -> entry
 	tenants map[string]map[string]*permitEntry // tenantID -> permitID -> entry
+	queue   []fairWaiter                       // 公平队列等待者，按入队顺序排列
 }
 
 // newResourcePermits 创建新的资源许可集合
@@ -99,6 +101,7 @@ func (s *localSemaphore) cleanupAllExpired() {
 		}
 		rp.mu.Lock()
 		s.cleanupExpiredLocked(rp, now)
+		rp.pruneFairQueueLocked(now)
 		rp.mu.Unlock()
 		return true
 	})
@@ -157,7 +160,7 @@ func (s *localSemaphore) TryAcquire(ctx context.Context, resource string, opts .
 	localCapacity, localTenantQuota := s.calculateLocalCapacity(cfg)
 
 	start := time.Now()
-	permit, reason, err := s.doAcquire(ctx, resource, tenantID, localCapacity, localTenantQuota, cfg.ttl, cfg.metadata)
+	permit, reason, err := s.tryAcquireOnce(ctx, resource, tenantID, localCapacity, localTenantQuota, cfg)
 	duration := time.Since(start)
 
 	// 记录 span 结果
@@ -198,6 +201,13 @@ func (s *localSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 	if err := cfg.validateRetryParams(); err != nil {
 		return nil, err
 	}
+	if err := joinFairQueue(ctx, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.waiterID != "" {
+		// 获取成功时已离开队列，此处为空操作
+		defer s.leaveFairQueue(ctx, resource, cfg.waiterID)
+	}
 
 	// 创建 span
 	ctx, span := startSpan(ctx, s.opts.tracer, spanNameAcquire)
@@ -218,8 +228,12 @@ func (s *localSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 			setSpanError(span, err)
 			return nil, err
 		}
+		if attempt > 0 && cfg.dynamicCapacity != nil {
+			cfg.refreshCapacity()
+			localCapacity, localTenantQuota = s.calculateLocalCapacity(cfg)
+		}
 
-		permit, reason, err := s.tryAcquireOnce(ctx, resource, tenantID, localCapacity, localTenantQuota, cfg.ttl, cfg.metadata)
+		permit, reason, err := s.tryAcquireOnce(ctx, resource, tenantID, localCapacity, localTenantQuota, cfg)
 		if err != nil {
 			s.recordAcquireMetrics(ctx, resource, false, ReasonUnknown, time.Since(start))
 			span.SetAttributes(attribute.Int(attrRetryCount, attempt))
@@ -260,6 +274,13 @@ func (s *localSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 	return nil, ErrAcquireFailed
 }
 
+// WaitAcquire 有界等待式获取本地许可
+func (s *localSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	ctx, cancel := applyDefaultTimeout(ctx, s.opts.defaultTimeout)
+	defer cancel()
+	return waitAcquire(ctx, resource, opts, s.TryAcquire, s.leaveFairQueue)
+}
+
 // prepareAcquire 准备获取许可的参数
 func (s *localSemaphore) prepareAcquire(ctx context.Context, resource string, opts []AcquireOption) (*acquireOptions, string, error) {
 	return prepareAcquireCommon(ctx, resource, opts, s.closed.Load())
@@ -284,8 +305,12 @@ func (s *localSemaphore) calculateLocalCapacity(cfg *acquireOptions) (localCapac
 
 // tryAcquireOnce 执行一次获取尝试
 // 注意：此方法不记录指标，指标由调用方统一记录（避免重试时重复记录）
-func (s *localSemaphore) tryAcquireOnce(ctx context.Context, resource, tenantID string, localCapacity, localTenantQuota int, ttl time.Duration, metadata map[string]string) (Permit, AcquireFailReason, error) {
-	return s.doAcquire(ctx, resource, tenantID, localCapacity, localTenantQuota, ttl, metadata)
+// 携带转移 token 时接管原许可，不经过容量检查
+func (s *localSemaphore) tryAcquireOnce(ctx context.Context, resource, tenantID string, localCapacity, localTenantQuota int, cfg *acquireOptions) (Permit, AcquireFailReason, error) {
+	if cfg.handoff != nil {
+		return s.doHandoff(ctx, resource, cfg)
+	}
+	return s.doAcquire(ctx, resource, tenantID, localCapacity, localTenantQuota, cfg)
 }
 
 // doAcquire 执行获取许可的核心逻辑
@@ -295,8 +320,7 @@ func (s *localSemaphore) doAcquire(
 	tenantID string,
 	capacity int,
 	tenantQuota int,
-	ttl time.Duration,
-	metadata map[string]string,
+	cfg *acquireOptions,
 ) (Permit, AcquireFailReason, error) {
 	// 在锁外生成许可 ID，避免时钟回拨等待期间（最多 500ms）阻塞其他 goroutine
 	permitID, err := s.opts.effectiveIDGenerator()(ctx)
@@ -305,6 +329,14 @@ func (s *localSemaphore) doAcquire(
 		return nil, ReasonUnknown, fmt.Errorf("%w: %v", ErrIDGenerationFailed, err)
 	}
 
+	// 排队位置回调在释放锁后执行（defer 后进先出），避免回调阻塞同资源的其他获取
+	queueAhead := -1
+	defer func() {
+		if queueAhead >= 0 {
+			cfg.reportQueuePosition(queueAhead)
+		}
+	}()
+
 	rp := s.getResourcePermits(resource)
 	rp.mu.Lock()
 	defer rp.mu.Unlock()
@@ -314,42 +346,65 @@ func (s *localSemaphore) doAcquire(
 	// 清理过期许可
 	s.cleanupExpiredLocked(rp, now)
 
-	// 检查全局容量
-	if len(rp.global) >= capacity {
+	// 公平队列：空位需先满足排在前面的等待者
+	ahead := 0
+	if cfg.fairQueue {
+		ahead = rp.fairAheadLocked(cfg.waiterID, now)
+	}
+
+	// 检查全局容量（需容纳全部名额）
+	if len(rp.global)+ahead+cfg.permitCount() > capacity {
+		s.recordUtilization(ctx, resource, len(rp.global), capacity)
+		if cfg.fairQueue {
+			queueAhead = ahead
+		}
 		return nil, ReasonCapacityFull, nil
 	}
 
 	// 检查租户配额
+	// 租户配额满的等待者移出队列，避免其占据队首阻塞其他租户（与 fair_acquire.lua 一致）
 	if tenantID != "" && tenantQuota > 0 {
-		tenantPermits := rp.tenants[tenantID]
-		if tenantPermits != nil && len(tenantPermits) >= tenantQuota {
+		if len(rp.tenants[tenantID])+cfg.permitCount() > tenantQuota {
+			rp.leaveFairQueueLocked(cfg.waiterID)
+			s.recordUtilization(ctx, resource, len(rp.global), capacity)
 			return nil, ReasonTenantQuotaExceeded, nil
 		}
 	}
 
-	expiresAt := now.Add(ttl)
-	entry := &permitEntry{
-		id:        permitID,
-		resource:  resource,
-		tenantID:  tenantID,
-		expiresAt: expiresAt,
-	}
+	rp.leaveFairQueueLocked(cfg.waiterID)
 
-	// 添加到全局集合
-	rp.global[permitID] = entry
+	expiresAt := now.Add(cfg.ttl)
 
 	// 计算是否启用租户配额（与租户检查条件一致）
 	hasTenantQuota := tenantID != "" && tenantQuota > 0
 
-	// 添加到租户集合（仅在启用租户配额时）
-	if hasTenantQuota {
-		if rp.tenants[tenantID] == nil {
-			rp.tenants[tenantID] = make(map[string]*permitEntry)
+	// 批量许可的每个名额占用一个成员，与 Redis 实现一致
+	for _, member := range permitMemberIDs(permitID, cfg.permitCount()) {
+		entry := &permitEntry{
+			id:        member,
+			resource:  resource,
+			tenantID:  tenantID,
+			expiresAt: expiresAt,
+		}
+
+		// 添加到全局集合
+		rp.global[member] = entry
+
+		// 添加到租户集合（仅在启用租户配额时）
+		if hasTenantQuota {
+			if rp.tenants[tenantID] == nil {
+				rp.tenants[tenantID] = make(map[string]*permitEntry)
+			}
+			rp.tenants[tenantID][member] = entry
 		}
-		rp.tenants[tenantID][permitID] = entry
 	}
 
-	return newLocalPermit(s, permitID, resource, tenantID, expiresAt, ttl, hasTenantQuota, metadata), ReasonUnknown, nil
+	s.recordUtilization(ctx, resource, len(rp.global), capacity)
+	permit := newLocalPermit(s, permitID, resource, tenantID, expiresAt, cfg.ttl, hasTenantQuota, cfg.metadata)
+	permit.count = cfg.permitCount()
+	permit.capacity = capacity
+	s.held.track(&permit.permitBase, SemaphoreTypeLocal)
+	return permit, ReasonUnknown, nil
 }
 
 // cleanupExpiredLocked 清理过期许可（调用者必须持有 rp.mu 锁）
@@ -387,23 +442,13 @@ func (s *localSemaphore) releasePermit(ctx context.Context, p *localPermit) erro
 	if _, ok := rp.global[p.id]; !ok {
 		return ErrPermitNotHeld
 	}
-	delete(rp.global, p.id)
-
-	// 从租户集合删除（使用 hasTenantQuota 判断，与 acquire 时保持一致）
-	if p.tenantID != "" && p.hasTenantQuota {
-		if tenantPermits := rp.tenants[p.tenantID]; tenantPermits != nil {
-			delete(tenantPermits, p.id)
-			// 如果租户集合为空，删除整个 key 以回收内存
-			if len(tenantPermits) == 0 {
-				delete(rp.tenants, p.tenantID)
-			}
-		}
-	}
+	s.removePermitLocked(rp, p)
 
 	// 记录指标（保留 trace context）
 	if s.opts.metrics != nil {
 		s.opts.metrics.RecordRelease(ctx, SemaphoreTypeLocal, p.resource)
 	}
+	s.recordUtilization(ctx, p.resource, len(rp.global), p.capacity)
 
 	return nil
 }
@@ -415,6 +460,13 @@ func (s *localSemaphore) recordAcquireMetrics(ctx context.Context, resource stri
 	}
 }
 
+// recordUtilization 记录资源利用率指标
+func (s *localSemaphore) recordUtilization(ctx context.Context, resource string, active, capacity int) {
+	if s.opts.metrics != nil {
+		s.opts.metrics.RecordUtilization(ctx, SemaphoreTypeLocal, resource, active, capacity)
+	}
+}
+
 // recordExtendMetrics 记录续期指标
 func (s *localSemaphore) recordExtendMetrics(ctx context.Context, resource string, success bool) {
 	if s.opts.metrics != nil {
@@ -422,13 +474,18 @@ func (s *localSemaphore) recordExtendMetrics(ctx context.Context, resource strin
 	}
 }
 
-// removeExpiredPermitLocked 删除过期许可（调用者必须持有 rp.mu 锁）
-func (s *localSemaphore) removeExpiredPermitLocked(rp *resourcePermits, p *localPermit) {
-	delete(rp.global, p.id)
+// removePermitLocked 删除许可的全部成员（调用者必须持有 rp.mu 锁）
+func (s *localSemaphore) removePermitLocked(rp *resourcePermits, p *localPermit) {
+	members := p.memberIDs()
+	for _, member := range members {
+		delete(rp.global, member)
+	}
 	// 从租户集合删除（使用 hasTenantQuota 判断，与 acquire 时保持一致）
 	if p.tenantID != "" && p.hasTenantQuota {
 		if tenantPermits := rp.tenants[p.tenantID]; tenantPermits != nil {
-			delete(tenantPermits, p.id)
+			for _, member := range members {
+				delete(tenantPermits, member)
+			}
 			// 如果租户集合为空，删除整个 key 以回收内存
 			if len(tenantPermits) == 0 {
 				delete(rp.tenants, p.tenantID)
@@ -455,13 +512,18 @@ func (s *localSemaphore) extendPermit(ctx context.Context, p *localPermit, newEx
 
 	// 检查是否已过期（使用 !After 语义，与 cleanupExpiredLocked 保持一致：expiresAt <= now 视为过期）
 	if !entry.expiresAt.After(time.Now()) {
-		s.removeExpiredPermitLocked(rp, p)
+		s.removePermitLocked(rp, p)
 		s.recordExtendMetrics(ctx, p.resource, false)
 		return ErrPermitNotHeld
 	}
 
-	// 更新过期时间
+	// 更新过期时间（批量许可的成员共享过期时间，全局和租户集合引用同一条目）
 	entry.expiresAt = newExpiresAt
+	for _, member := range p.memberIDs()[1:] {
+		if e, ok := rp.global[member]; ok {
+			e.expiresAt = newExpiresAt
+		}
+	}
 	s.recordExtendMetrics(ctx, p.resource, true)
 	return nil
 }
@@ -503,6 +565,7 @@ func (s *localSemaphore) Query(ctx context.Context, resource string, opts ...Que
 	if s.opts.metrics != nil {
 		s.opts.metrics.RecordQuery(ctx, SemaphoreTypeLocal, resource, true, time.Since(start))
 	}
+	s.recordUtilization(ctx, resource, globalUsed, localCapacity)
 
 	span.SetAttributes(
 		attribute.Int(attrGlobalUsed, globalUsed),
@@ -608,4 +671,5 @@ func (s *localSemaphore) logExtendFailed(ctx context.Context, permitID, resource
 var (
 	_ Semaphore       = (*localSemaphore)(nil)
 	_ loggerForExtend = (*localSemaphore)(nil)
+	_ fairQueueLeaver = (*localSemaphore)(nil)
 )
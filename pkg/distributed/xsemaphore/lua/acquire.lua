@@ -10,6 +10,7 @@
 -- ARGV[4]: 全局容量上限
 -- ARGV[5]: 租户配额上限（0 表示不限制）
 -- ARGV[6]: 键过期余量（毫秒）
+-- ARGV[7]: 许可数量（可选，默认 1；大于 1 时额外成员为 permitID#1 ... permitID#(n-1)）
 --
 -- 返回: {status, globalCount, tenantCount}
 --   - status: 0=成功, 1=全局容量满, 2=租户配额满
@@ -27,13 +28,14 @@ local permitID = ARGV[3]
 local capacity = tonumber(ARGV[4])
 local tenantQuota = tonumber(ARGV[5])
 local keyTTLMargin = tonumber(ARGV[6])
+local count = tonumber(ARGV[7]) or 1
 
 -- 1. 清理过期的全局许可
 redis.call('ZREMRANGEBYSCORE', globalKey, '-inf', now)
 
--- 2. 检查全局容量
+-- 2. 检查全局容量（需容纳全部 count 个名额）
 local globalCount = redis.call('ZCARD', globalKey)
-if globalCount >= capacity then
+if globalCount + count > capacity then
     return {1, globalCount, 0}
 end
 
@@ -42,15 +44,21 @@ local tenantCount = 0
 if hasTenantKey and tenantQuota > 0 then
     redis.call('ZREMRANGEBYSCORE', tenantKey, '-inf', now)
     tenantCount = redis.call('ZCARD', tenantKey)
-    if tenantCount >= tenantQuota then
+    if tenantCount + count > tenantQuota then
         return {2, globalCount, tenantCount}
     end
 end
 
--- 4. 添加许可
-redis.call('ZADD', globalKey, expireAt, permitID)
-if hasTenantKey and tenantQuota > 0 then
-    redis.call('ZADD', tenantKey, expireAt, permitID)
+-- 4. 添加许可（全部名额在同一脚本内写入，要么全部获取要么全部不获取）
+for i = 0, count - 1 do
+    local member = permitID
+    if i > 0 then
+        member = permitID .. '#' .. i
+    end
+    redis.call('ZADD', globalKey, expireAt, member)
+    if hasTenantKey and tenantQuota > 0 then
+        redis.call('ZADD', tenantKey, expireAt, member)
+    end
 end
 
 -- 5. 设置键过期时间（只延长，不缩短，防止短 TTL 许可影响长 TTL 许可）
@@ -69,9 +77,9 @@ if hasTenantKey and tenantQuota > 0 then
     end
 end
 
--- 修正返回值：tenantCount 只有在启用租户配额时才加 1
+-- 修正返回值：tenantCount 只有在启用租户配额时才增加
 local newTenantCount = tenantCount
 if hasTenantKey and tenantQuota > 0 then
-    newTenantCount = tenantCount + 1
+    newTenantCount = tenantCount + count
 end
-return {0, globalCount + 1, newTenantCount}
+return {0, globalCount + count, newTenantCount}
@@ -8,6 +8,7 @@
 -- ARGV[2]: 新的过期时间戳（毫秒）
 -- ARGV[3]: 许可 ID
 -- ARGV[4]: 键过期余量（毫秒）
+-- ARGV[5]: 许可数量（可选，默认 1；以许可 ID 判断是否持有，全部成员一并续期）
 --
 -- 返回: {status}
 --   - status: 0=成功, 3=未持有
@@ -21,6 +22,21 @@ local now = tonumber(ARGV[1])
 local newExpireAt = tonumber(ARGV[2])
 local permitID = ARGV[3]
 local keyTTLMargin = tonumber(ARGV[4])
+local count = tonumber(ARGV[5]) or 1
+
+-- 分批 ZREM：unpack 受 Lua 栈大小限制（约 8000 个值），每批最多 1000 个成员
+local function zremAll(key, members)
+    local removed = 0
+    for i = 1, #members, 1000 do
+        removed = removed + redis.call('ZREM', key, unpack(members, i, math.min(i + 999, #members)))
+    end
+    return removed
+end
+
+local members = {permitID}
+for i = 1, count - 1 do
+    members[#members + 1] = permitID .. '#' .. i
+end
 
 -- 检查许可是否存在
 local score = redis.call('ZSCORE', globalKey, permitID)
@@ -30,9 +46,9 @@ end
 
 -- 检查是否已过期（使用 <= 语义，与 local.go 保持一致）
 if tonumber(score) <= now then
-    redis.call('ZREM', globalKey, permitID)
+    zremAll(globalKey, members)
     if hasTenantKey then
-        redis.call('ZREM', tenantKey, permitID)
+        zremAll(tenantKey, members)
     end
     return {3}
 end
@@ -44,9 +60,11 @@ if newExpireAt <= now then
 end
 
 -- 更新过期时间
-redis.call('ZADD', globalKey, newExpireAt, permitID)
-if hasTenantKey then
-    redis.call('ZADD', tenantKey, newExpireAt, permitID)
+for _, member in ipairs(members) do
+    redis.call('ZADD', globalKey, newExpireAt, member)
+    if hasTenantKey then
+        redis.call('ZADD', tenantKey, newExpireAt, member)
+    end
 end
 
 -- 更新键过期时间（只延长，不缩短，防止短 TTL 许可影响长 TTL 许可）
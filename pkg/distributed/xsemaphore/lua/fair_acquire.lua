@@ -0,0 +1,117 @@
+-- fair_acquire.lua
+-- 公平队列模式下获取许可的原子操作
+--
+-- KEYS[1]: 全局许可集合键 {prefix}:{resource}:permits
+-- KEYS[2]: 等待队列键 {prefix}:{resource}:queue（score 为入队时间）
+-- KEYS[3]: 等待者存活键 {prefix}:{resource}:queue:alive（score 为存活截止时间）
+-- KEYS[4]: 租户许可集合键 {prefix}:{resource}:t:{tenantID}（可选，动态传递）
+--
+-- ARGV[1]: 当前时间戳（毫秒）
+-- ARGV[2]: 许可过期时间戳（毫秒）
+-- ARGV[3]: 许可 ID
+-- ARGV[4]: 全局容量上限
+-- ARGV[5]: 租户配额上限（0 表示不限制）
+-- ARGV[6]: 键过期余量（毫秒）
+-- ARGV[7]: 等待者 ID（空字符串表示不入队，仅在无人排队的空位上获取）
+-- ARGV[8]: 等待者存活时间（毫秒）
+-- ARGV[9]: 许可数量（可选，默认 1，成员命名与 acquire.lua 一致）
+--
+-- 返回: {status, globalCount, tenantCount, ahead}
+--   - status: 0=成功, 1=全局容量满（或被排在前面的等待者占用）, 2=租户配额满
+--   - globalCount: 当前全局许可数
+--   - tenantCount: 当前租户许可数（未设置租户时为 0）
+--   - ahead: 排在前面的等待者数量（未入队时为队列长度），用于反馈排队位置
+
+local globalKey = KEYS[1]
+local queueKey = KEYS[2]
+local aliveKey = KEYS[3]
+-- KEYS[4] 动态传递，可能不存在（Redis Cluster 兼容）
+local tenantKey = KEYS[4]
+local hasTenantKey = tenantKey ~= nil and tenantKey ~= ''
+
+local now = tonumber(ARGV[1])
+local expireAt = tonumber(ARGV[2])
+local permitID = ARGV[3]
+local capacity = tonumber(ARGV[4])
+local tenantQuota = tonumber(ARGV[5])
+local keyTTLMargin = tonumber(ARGV[6])
+local waiterID = ARGV[7]
+local waiterTTL = tonumber(ARGV[8])
+local count = tonumber(ARGV[9]) or 1
+
+-- 1. 清理过期的全局许可
+redis.call('ZREMRANGEBYSCORE', globalKey, '-inf', now)
+
+-- 2. 清理失联的等待者（进程崩溃等未主动离开队列的情况）
+local dead = redis.call('ZRANGEBYSCORE', aliveKey, '-inf', now)
+for _, id in ipairs(dead) do
+    redis.call('ZREM', queueKey, id)
+end
+if #dead > 0 then
+    redis.call('ZREMRANGEBYSCORE', aliveKey, '-inf', now)
+end
+
+-- 3. 入队（已在队列中则保留原位置）并刷新存活时间，计算排在前面的等待者数量
+local ahead
+if waiterID ~= '' then
+    redis.call('ZADD', queueKey, 'NX', now, waiterID)
+    redis.call('ZADD', aliveKey, now + waiterTTL, waiterID)
+    redis.call('PEXPIRE', queueKey, waiterTTL + keyTTLMargin)
+    redis.call('PEXPIRE', aliveKey, waiterTTL + keyTTLMargin)
+    ahead = redis.call('ZRANK', queueKey, waiterID)
+else
+    ahead = redis.call('ZCARD', queueKey)
+end
+
+-- 4. 检查全局容量：空位需先满足排在前面的等待者，并容纳全部 count 个名额
+local globalCount = redis.call('ZCARD', globalKey)
+if globalCount + ahead + count > capacity then
+    return {1, globalCount, 0, ahead}
+end
+
+-- 5. 如果设置了租户配额，检查租户
+-- 租户配额满的等待者移出队列，避免其占据队首阻塞其他租户
+local tenantCount = 0
+if hasTenantKey and tenantQuota > 0 then
+    redis.call('ZREMRANGEBYSCORE', tenantKey, '-inf', now)
+    tenantCount = redis.call('ZCARD', tenantKey)
+    if tenantCount + count > tenantQuota then
+        if waiterID ~= '' then
+            redis.call('ZREM', queueKey, waiterID)
+            redis.call('ZREM', aliveKey, waiterID)
+        end
+        return {2, globalCount, tenantCount, ahead}
+    end
+end
+
+-- 6. 添加许可并离开队列
+for i = 0, count - 1 do
+    local member = permitID
+    if i > 0 then
+        member = permitID .. '#' .. i
+    end
+    redis.call('ZADD', globalKey, expireAt, member)
+    if hasTenantKey and tenantQuota > 0 then
+        redis.call('ZADD', tenantKey, expireAt, member)
+    end
+end
+if waiterID ~= '' then
+    redis.call('ZREM', queueKey, waiterID)
+    redis.call('ZREM', aliveKey, waiterID)
+end
+
+-- 7. 设置键过期时间（只延长，不缩短，与 acquire.lua 一致）
+local ttlSec = math.ceil((expireAt - now + keyTTLMargin) / 1000)
+local currentTTL = redis.call('TTL', globalKey)
+if currentTTL < 0 or ttlSec > currentTTL then
+    redis.call('EXPIRE', globalKey, ttlSec)
+end
+if hasTenantKey and tenantQuota > 0 then
+    local tenantCurrentTTL = redis.call('TTL', tenantKey)
+    if tenantCurrentTTL < 0 or ttlSec > tenantCurrentTTL then
+        redis.call('EXPIRE', tenantKey, ttlSec)
+    end
+    tenantCount = tenantCount + count
+end
+
+return {0, globalCount + count, tenantCount, ahead}
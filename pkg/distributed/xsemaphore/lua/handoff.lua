@@ -0,0 +1,110 @@
+-- handoff.lua
+-- 接管已转移许可的原子操作
+--
+-- KEYS[1]: 全局许可集合键
+-- KEYS[2]: 租户许可集合键（可选，动态传递）
+--
+-- ARGV[1]: 当前时间戳（毫秒）
+-- ARGV[2]: 新许可过期时间戳（毫秒）
+-- ARGV[3]: 原许可 ID
+-- ARGV[4]: 新许可 ID
+-- ARGV[5]: 键过期余量（毫秒）
+-- ARGV[6]: 许可数量（可选，默认 1；批量许可的全部成员一并替换）
+--
+-- 返回: {status}
+--   - status: 0=成功, 3=原许可未持有（已过期或已被接管）
+--
+-- 原许可与新许可在同一脚本内替换，许可总数不变，不经过容量检查，
+-- 因此交接期间不存在被其他获取者抢占的窗口。
+--
+-- 许可数量来自客户端传入的 token（未签名），替换前校验原许可的成员恰好为 count 个：
+-- 数量偏大会凭空新增许可（超出容量），偏小会遗留原许可的成员，均按未持有处理。
+
+local globalKey = KEYS[1]
+-- KEYS[2] 动态传递，可能不存在（Redis Cluster 兼容）
+local tenantKey = KEYS[2]
+local hasTenantKey = tenantKey ~= nil and tenantKey ~= ''
+
+local now = tonumber(ARGV[1])
+local newExpireAt = tonumber(ARGV[2])
+local oldID = ARGV[3]
+local newID = ARGV[4]
+local keyTTLMargin = tonumber(ARGV[5])
+local count = tonumber(ARGV[6]) or 1
+
+-- 分批 ZREM：unpack 受 Lua 栈大小限制（约 8000 个值），每批最多 1000 个成员
+local function zremAll(key, members)
+    local removed = 0
+    for i = 1, #members, 1000 do
+        removed = removed + redis.call('ZREM', key, unpack(members, i, math.min(i + 999, #members)))
+    end
+    return removed
+end
+
+local oldMembers = {oldID}
+local newMembers = {newID}
+for i = 1, count - 1 do
+    oldMembers[#oldMembers + 1] = oldID .. '#' .. i
+    newMembers[#newMembers + 1] = newID .. '#' .. i
+end
+
+-- 检查原许可是否存在
+local score = redis.call('ZSCORE', globalKey, oldID)
+if not score then
+    return {3}
+end
+
+-- 检查是否已过期（使用 <= 语义，与 extend.lua 保持一致）
+if tonumber(score) <= now then
+    zremAll(globalKey, oldMembers)
+    if hasTenantKey then
+        zremAll(tenantKey, oldMembers)
+    end
+    return {3}
+end
+
+-- 防御性检查：新的过期时间必须在当前时间之后
+if newExpireAt <= now then
+    return {3}
+end
+
+-- 校验实际成员数与 count 一致（全部成员存在，且不存在第 count+1 个成员）
+local function holdsExactly(key)
+    for _, member in ipairs(oldMembers) do
+        if not redis.call('ZSCORE', key, member) then
+            return false
+        end
+    end
+    return not redis.call('ZSCORE', key, oldID .. '#' .. count)
+end
+if not holdsExactly(globalKey) or (hasTenantKey and not holdsExactly(tenantKey)) then
+    return {3}
+end
+
+-- 以新 ID 替换原许可
+zremAll(globalKey, oldMembers)
+for _, member in ipairs(newMembers) do
+    redis.call('ZADD', globalKey, newExpireAt, member)
+end
+if hasTenantKey then
+    zremAll(tenantKey, oldMembers)
+    for _, member in ipairs(newMembers) do
+        redis.call('ZADD', tenantKey, newExpireAt, member)
+    end
+end
+
+-- 更新键过期时间（只延长，不缩短）
+local ttlMs = newExpireAt - now + keyTTLMargin
+local ttlSec = math.ceil(ttlMs / 1000)
+local currentTTL = redis.call('TTL', globalKey)
+if currentTTL < 0 or ttlSec > currentTTL then
+    redis.call('EXPIRE', globalKey, ttlSec)
+end
+if hasTenantKey then
+    local tenantCurrentTTL = redis.call('TTL', tenantKey)
+    if tenantCurrentTTL < 0 or ttlSec > tenantCurrentTTL then
+        redis.call('EXPIRE', tenantKey, ttlSec)
+    end
+end
+
+return {0}
@@ -5,10 +5,13 @@
 -- KEYS[2]: 租户许可集合键（可选，动态传递）
 --
 -- ARGV[1]: 许可 ID
+-- ARGV[2]: 许可数量（可选，默认 1；批量许可的全部成员一并删除）
+-- ARGV[3]: 当前时间戳（毫秒，可选；用于统计剩余未过期许可数）
 --
--- 返回: {status, removed}
+-- 返回: {status, removed, globalCount}
 --   - status: 0=成功, 3=未持有
 --   - removed: 删除的许可数
+--   - globalCount: 释放后的全局未过期许可数（用于利用率指标）
 
 local globalKey = KEYS[1]
 -- KEYS[2] 动态传递，可能不存在（Redis Cluster 兼容）
@@ -16,17 +19,36 @@ local tenantKey = KEYS[2]
 local hasTenantKey = tenantKey ~= nil and tenantKey ~= ''
 
 local permitID = ARGV[1]
+local count = tonumber(ARGV[2]) or 1
+local now = tonumber(ARGV[3]) or 0
+
+-- 分批 ZREM：unpack 受 Lua 栈大小限制（约 8000 个值），每批最多 1000 个成员
+local function zremAll(key, members)
+    local removed = 0
+    for i = 1, #members, 1000 do
+        removed = removed + redis.call('ZREM', key, unpack(members, i, math.min(i + 999, #members)))
+    end
+    return removed
+end
+
+local members = {permitID}
+for i = 1, count - 1 do
+    members[#members + 1] = permitID .. '#' .. i
+end
 
 -- 从全局集合删除
-local removed = redis.call('ZREM', globalKey, permitID)
+local removed = zremAll(globalKey, members)
 
 -- 从租户集合删除
 if hasTenantKey then
-    redis.call('ZREM', tenantKey, permitID)
+    zremAll(tenantKey, members)
 end
 
 if removed == 0 then
-    return {3, 0}
+    return {3, 0, 0}
 end
 
-return {0, removed}
+-- 与 query.lua 一致：score > now 表示未过期
+local globalCount = redis.call('ZCOUNT', globalKey, '(' .. now, '+inf')
+
+return {0, removed, globalCount}
@@ -26,10 +26,14 @@ const (
 	metricNameQueryTotal = "xsemaphore.query.total"
 	// metricNameQueryDuration 查询耗时直方图
 	metricNameQueryDuration = "xsemaphore.query.duration"
+	// metricNamePermitsActive 当前活跃许可数仪表
+	metricNamePermitsActive = "xsemaphore.permits.active"
+	// metricNamePermitsCapacity 许可容量仪表
+	metricNamePermitsCapacity = "xsemaphore.permits.capacity"
 )
 
 // Metrics 信号量指标收集器
-// 提供 Counter 和 Histogram 类型的指标收集
+// 提供 Counter、Histogram 和 Gauge 类型的指标收集
 type Metrics struct {
 	meter                metric.Meter
 	acquireTotal         metric.Int64Counter
@@ -39,6 +43,8 @@ type Metrics struct {
 	acquireDuration      metric.Float64Histogram
 	queryTotal           metric.Int64Counter
 	queryDuration        metric.Float64Histogram
+	permitsActive        metric.Int64Gauge
+	permitsCapacity      metric.Int64Gauge
 	disableResourceLabel bool // 是否禁用 resource 标签
 }
 
@@ -64,6 +70,9 @@ func NewMetrics(meterProvider metric.MeterProvider, opts ...MetricsOption) (*Met
 	if err := m.initHistograms(); err != nil {
 		return nil, err
 	}
+	if err := m.initGauges(); err != nil {
+		return nil, err
+	}
 
 	return m, nil
 }
@@ -113,6 +122,20 @@ func (m *Metrics) initHistograms() error {
 	return nil
 }
 
+// initGauges 初始化所有仪表指标
+func (m *Metrics) initGauges() error {
+	var err error
+	if m.permitsActive, err = m.meter.Int64Gauge(metricNamePermitsActive,
+		metric.WithDescription("信号量当前活跃许可数"), metric.WithUnit("{permit}")); err != nil {
+		return err
+	}
+	if m.permitsCapacity, err = m.meter.Int64Gauge(metricNamePermitsCapacity,
+		metric.WithDescription("信号量许可容量"), metric.WithUnit("{permit}")); err != nil {
+		return err
+	}
+	return nil
+}
+
 // MetricsOption 指标收集器配置选项
 type MetricsOption func(*Metrics)
 
@@ -267,3 +290,35 @@ func (m *Metrics) RecordQuery(ctx context.Context, semType, resource string, suc
 	m.queryTotal.Add(metricsCtx, 1, metric.WithAttributes(attrs...))
 	m.queryDuration.Record(metricsCtx, duration.Seconds(), metric.WithAttributes(attrs...))
 }
+
+// RecordUtilization 记录资源的当前活跃许可数和容量
+// ctx: 上下文
+// semType: 信号量类型
+// resource: 资源名称
+// active: 当前活跃许可数
+// capacity: 容量上限（<= 0 表示未知，仅记录活跃数）
+//
+// 设计决策: 使用同步 Gauge 在 Acquire/Release/Query 时更新，而非异步 Observable 回调，
+// 避免为每个资源维护注册表并在采集时访问 Redis。分布式信号量的活跃数为全局值，
+// 多实例上报同一资源时应在采集端取 max/last 而非 sum。
+// 容量取自调用方传入的 WithCapacity，本地信号量为按 Pod 数拆分后的本地容量。
+//
+// 禁用 resource 标签时不记录：Gauge 只保留最后一次写入的值，
+// 去掉 resource 后各资源的活跃数和容量会相互覆盖，得到的值没有意义。
+func (m *Metrics) RecordUtilization(ctx context.Context, semType, resource string, active, capacity int) {
+	if m == nil || m.disableResourceLabel {
+		return
+	}
+
+	metricsCtx := context.WithoutCancel(ctx)
+
+	attrs := []attribute.KeyValue{
+		attribute.String(attrSemType, semType),
+		attribute.String(attrResource, resource),
+	}
+
+	m.permitsActive.Record(metricsCtx, int64(active), metric.WithAttributes(attrs...))
+	if capacity > 0 {
+		m.permitsCapacity.Record(metricsCtx, int64(capacity), metric.WithAttributes(attrs...))
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/omeyang/xkit/internal/rediscompat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 )
 
 func TestNewMetrics(t *testing.T) {
@@ -112,6 +117,7 @@ func TestMetrics_NilReceiver(t *testing.T) {
 	m.RecordExtend(ctx, SemaphoreTypeDistributed, "r", true)
 	m.RecordFallback(ctx, FallbackLocal, "r", "test")
 	m.RecordQuery(ctx, SemaphoreTypeDistributed, "r", true, time.Millisecond)
+	m.RecordUtilization(ctx, SemaphoreTypeDistributed, "r", 1, 10)
 }
 
 func TestSemaphore_WithMetrics(t *testing.T) {
@@ -163,3 +169,138 @@ func TestLocalSemaphore_WithMetrics(t *testing.T) {
 	err = permit.Release(ctx)
 	assert.NoError(t, err)
 }
+
+// gaugeValue 从 reader 中读取指定 Gauge 在 resource 标签下的当前值
+// resource 为空时匹配不含 resource 标签的数据点
+func gaugeValue(t *testing.T, reader *sdkmetric.ManualReader, name, resource string) (int64, bool) {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			gauge, ok := m.Data.(metricdata.Gauge[int64])
+			require.True(t, ok, "%s should be an int64 gauge", name)
+			for _, dp := range gauge.DataPoints {
+				v, has := dp.Attributes.Value(attribute.Key(attrResource))
+				if (resource == "" && !has) || (has && v.AsString() == resource) {
+					return dp.Value, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestMetrics_RecordUtilization(t *testing.T) {
+	t.Run("with resource label", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		metrics, err := NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		require.NoError(t, err)
+
+		metrics.RecordUtilization(context.Background(), SemaphoreTypeDistributed, "r", 3, 10)
+
+		active, ok := gaugeValue(t, reader, metricNamePermitsActive, "r")
+		require.True(t, ok)
+		assert.Equal(t, int64(3), active)
+		capacity, ok := gaugeValue(t, reader, metricNamePermitsCapacity, "r")
+		require.True(t, ok)
+		assert.Equal(t, int64(10), capacity)
+	})
+
+	t.Run("disable resource label", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		metrics, err := NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+			MetricsWithDisableResourceLabel())
+		require.NoError(t, err)
+
+		metrics.RecordUtilization(context.Background(), SemaphoreTypeLocal, "r", 2, 5)
+
+		// 无 resource 标签的 Gauge 会被各资源相互覆盖，不记录
+		_, ok := gaugeValue(t, reader, metricNamePermitsActive, "r")
+		assert.False(t, ok)
+		_, ok = gaugeValue(t, reader, metricNamePermitsActive, "")
+		assert.False(t, ok)
+		_, ok = gaugeValue(t, reader, metricNamePermitsCapacity, "")
+		assert.False(t, ok)
+	})
+
+	t.Run("unknown capacity skipped", func(t *testing.T) {
+		reader := sdkmetric.NewManualReader()
+		metrics, err := NewMetrics(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+		require.NoError(t, err)
+
+		metrics.RecordUtilization(context.Background(), SemaphoreTypeLocal, "r", 1, 0)
+
+		_, ok := gaugeValue(t, reader, metricNamePermitsActive, "r")
+		assert.True(t, ok)
+		_, ok = gaugeValue(t, reader, metricNamePermitsCapacity, "r")
+		assert.False(t, ok)
+	})
+}
+
+func TestSemaphore_UtilizationGauge(t *testing.T) {
+	for name, newSem := range map[string]func(t *testing.T, mp metric.MeterProvider) Semaphore{
+		"redis lua": func(t *testing.T, mp metric.MeterProvider) Semaphore {
+			sem, _ := setupSemaphore(t, WithMeterProvider(mp))
+			return sem
+		},
+		"redis compat": func(t *testing.T, mp metric.MeterProvider) Semaphore {
+			sem, _ := setupSemaphore(t, WithMeterProvider(mp), WithScriptMode(rediscompat.ScriptModeCompat))
+			return sem
+		},
+		"local": func(t *testing.T, mp metric.MeterProvider) Semaphore {
+			metrics, err := NewMetrics(mp)
+			require.NoError(t, err)
+			opts := defaultOptions()
+			opts.metrics = metrics
+			sem := newLocalSemaphore(opts)
+			t.Cleanup(func() { closeSemaphore(t, sem) })
+			return sem
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			sem := newSem(t, sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+			ctx := context.Background()
+
+			assertActive := func(want int64) {
+				t.Helper()
+				active, ok := gaugeValue(t, reader, metricNamePermitsActive, "gauge")
+				require.True(t, ok)
+				assert.Equal(t, want, active)
+			}
+
+			p1, err := sem.AcquireN(ctx, "gauge", 2, WithCapacity(3))
+			require.NoError(t, err)
+			require.NotNil(t, p1)
+			assertActive(2)
+			capacity, ok := gaugeValue(t, reader, metricNamePermitsCapacity, "gauge")
+			require.True(t, ok)
+			assert.Equal(t, int64(3), capacity)
+
+			// 获取失败时也更新活跃数
+			none, err := sem.AcquireN(ctx, "gauge", 2, WithCapacity(3))
+			require.NoError(t, err)
+			assert.Nil(t, none)
+			assertActive(2)
+
+			p2, err := sem.TryAcquire(ctx, "gauge", WithCapacity(3))
+			require.NoError(t, err)
+			require.NotNil(t, p2)
+			assertActive(3)
+
+			require.NoError(t, p1.Release(ctx))
+			assertActive(1)
+
+			_, err = sem.Query(ctx, "gauge", QueryWithCapacity(3))
+			require.NoError(t, err)
+			assertActive(1)
+
+			require.NoError(t, p2.Release(ctx))
+			assertActive(0)
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/omeyang/xkit/internal/rediscompat"
 	"github.com/omeyang/xkit/pkg/observability/xlog"
+	"github.com/omeyang/xkit/pkg/resilience/xretry"
 	"github.com/omeyang/xkit/pkg/util/xid"
 )
 
@@ -70,6 +71,7 @@ type options struct {
 	fallback             FallbackStrategy
 	podCount             int
 	onFallback           func(resource string, strategy FallbackStrategy, err error)
+	onFallbackTransition func(resource string, localActive int)
 	disableResourceLabel bool                   // 禁用 resource 标签，避免高基数问题
 	defaultTimeout       time.Duration          // 默认操作超时时间
 	idGenerator          IDGeneratorFunc        // 许可 ID 生成函数，nil 时使用 xid.NewStringWithRetry
@@ -155,6 +157,21 @@ func WithOnFallback(fn func(resource string, strategy FallbackStrategy, err erro
 	}
 }
 
+// WithOnFallbackTransition 设置资源在分布式/本地模式之间切换时的回调
+// 仅对 FallbackLocal 策略生效，按资源维度检测切换：
+//   - 分布式 → 本地：资源首次因 Redis 错误降级到本地信号量时调用
+//   - 本地 → 分布式：降级过的资源首次重新从 Redis 获取成功时调用
+//
+// localActive 为切换时刻该资源在本地信号量中仍有效的许可数。
+// 回切时 localActive > 0 表示进入恢复窗口：这些本地许可不在 Redis 账本中，
+// 在其释放或过期前 local_active + redis_active 可能超过全局容量。
+// 回调不改变降级的尽力而为语义，仅用于日志/指标观测；回调 panic 会被隔离。
+func WithOnFallbackTransition(fn func(resource string, localActive int)) Option {
+	return func(o *options) {
+		o.onFallbackTransition = fn
+	}
+}
+
 // WithDisableResourceLabel 禁用指标中的 resource 标签
 // 当资源名称为动态生成时（如包含用户 ID），建议启用此选项以避免高基数问题
 // 高基数标签会导致指标存储和查询性能下降
@@ -258,6 +275,30 @@ type acquireOptions struct {
 	maxRetries  int
 	retryDelay  time.Duration
 	metadata    map[string]string
+
+	// dynamicCapacity 动态容量（WithDynamicCapacity 设置），非 nil 时每次获取尝试前刷新 capacity
+	dynamicCapacity *Capacity
+
+	// handoffToken 由 Permit.Handoff 生成的转移 token，非空时接管原许可
+	handoffToken string
+	// handoff 解析后的 token（prepareAcquireCommon 中填充）
+	handoff *handoffPayload
+
+	// pollBackoff WaitAcquire 的轮询退避策略，nil 时使用默认指数退避
+	pollBackoff xretry.BackoffPolicy
+
+	// fairQueue 启用公平队列，按到达顺序分配许可
+	fairQueue bool
+	// waiterID 公平队列中的等待者 ID（Acquire/WaitAcquire 内部填充，为空时不入队）
+	waiterID string
+	// onQueuePosition 排队位置回调（WithQueuePositionCallback 设置）
+	onQueuePosition func(ahead int)
+
+	// count 一次获取的许可数量（AcquireN 内部设置），通过 permitCount 读取
+	count int
+
+	// reservationTTL Reserve 的预留过期时间，其他获取方式忽略
+	reservationTTL time.Duration
 }
 
 // AcquireOption 获取许可的配置选项函数
@@ -266,10 +307,11 @@ type AcquireOption func(*acquireOptions)
 // defaultAcquireOptions 返回默认获取配置
 func defaultAcquireOptions() *acquireOptions {
 	return &acquireOptions{
-		capacity:   DefaultCapacity,
-		ttl:        DefaultTTL,
-		maxRetries: DefaultMaxRetries,
-		retryDelay: DefaultRetryDelay,
+		capacity:       DefaultCapacity,
+		ttl:            DefaultTTL,
+		maxRetries:     DefaultMaxRetries,
+		retryDelay:     DefaultRetryDelay,
+		reservationTTL: DefaultReservationTTL,
 	}
 }
 
@@ -291,9 +333,20 @@ func (o *acquireOptions) validate() error {
 	if o.tenantQuota > 0 && o.tenantQuota > o.capacity {
 		return fmt.Errorf("%w: tenant quota (%d) cannot exceed capacity (%d)", ErrInvalidTenantQuota, o.tenantQuota, o.capacity)
 	}
+	if n := o.permitCount(); n > o.capacity {
+		return fmt.Errorf("%w: count (%d) cannot exceed capacity (%d)", ErrInvalidPermitCount, n, o.capacity)
+	}
+	if n := o.permitCount(); o.tenantQuota > 0 && n > o.tenantQuota {
+		return fmt.Errorf("%w: count (%d) cannot exceed tenant quota (%d)", ErrInvalidPermitCount, n, o.tenantQuota)
+	}
 	return nil
 }
 
+// permitCount 返回一次获取的许可数量，未设置时为 1
+func (o *acquireOptions) permitCount() int {
+	return max(1, o.count)
+}
+
 // validateRetryParams 验证重试相关参数（仅 Acquire 调用）
 func (o *acquireOptions) validateRetryParams() error {
 	if o.maxRetries <= 0 {
@@ -318,6 +371,7 @@ func (o *acquireOptions) validateRetryParams() error {
 func WithCapacity(capacity int) AcquireOption {
 	return func(o *acquireOptions) {
 		o.capacity = capacity
+		o.dynamicCapacity = nil
 	}
 }
 
@@ -371,6 +425,49 @@ func WithRetryDelay(d time.Duration) AcquireOption {
 	}
 }
 
+// WithPollInterval 设置 WaitAcquire 的固定轮询间隔
+// 默认从 DefaultRetryDelay 开始指数退避，最大 DefaultMaxPollInterval
+// 仅对 WaitAcquire 方法有效，非正值被忽略（保持默认退避）
+func WithPollInterval(d time.Duration) AcquireOption {
+	return func(o *acquireOptions) {
+		if d > 0 {
+			o.pollBackoff = xretry.NewFixedBackoff(d)
+		}
+	}
+}
+
+// WithPollBackoff 设置 WaitAcquire 的轮询退避策略
+// 复用 xretry 的退避实现，例如 xretry.NewExponentialBackoff、xretry.NewLinearBackoff
+// 仅对 WaitAcquire 方法有效，nil 被忽略（保持默认退避）
+//
+// 示例:
+//
+//	permit, err := sem.WaitAcquire(ctx, "resource",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithPollBackoff(xretry.NewExponentialBackoff(
+//	        xretry.WithInitialDelay(50*time.Millisecond),
+//	        xretry.WithMaxDelay(2*time.Second),
+//	    )),
+//	)
+func WithPollBackoff(b xretry.BackoffPolicy) AcquireOption {
+	return func(o *acquireOptions) {
+		if b != nil {
+			o.pollBackoff = b
+		}
+	}
+}
+
+// effectivePollBackoff 返回有效的轮询退避策略
+func (o *acquireOptions) effectivePollBackoff() xretry.BackoffPolicy {
+	if o.pollBackoff != nil {
+		return o.pollBackoff
+	}
+	return xretry.NewExponentialBackoff(
+		xretry.WithInitialDelay(DefaultRetryDelay),
+		xretry.WithMaxDelay(DefaultMaxPollInterval),
+	)
+}
+
 // WithMetadata 设置许可的元数据
 // 元数据会被复制存储在许可中，可通过 Permit.Metadata() 获取
 // 用于携带业务上下文信息，如 trace_id、request_id 等
@@ -390,6 +487,107 @@ func WithMetadata(metadata map[string]string) AcquireOption {
 	}
 }
 
+// WithHandoffToken 使用 [Permit.Handoff] 生成的 token 接管原许可
+//
+// 接管在后端原子完成：原许可被替换为新许可，许可总数不变、不经过容量检查，
+// 因此不存在"释放后重新获取"的竞争窗口。新许可使用本次调用的 TTL 和元数据，
+// 租户信息沿用原许可（WithTenantID/WithTenantQuota 被忽略）。
+//
+// token 只能使用一次。原许可已过期或已被接管时返回 [ErrHandoffExpired]，
+// token 格式错误或资源名不匹配时返回 [ErrInvalidHandoffToken]。
+// 接管失败不会重试，调用方可按需改用普通获取。
+//
+// 示例:
+//
+//	permit, err := sem.Acquire(ctx, "resource",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithHandoffToken(token),
+//	)
+func WithHandoffToken(token string) AcquireOption {
+	return func(o *acquireOptions) {
+		o.handoffToken = token
+	}
+}
+
+// WithReservationTTL 设置 Reserve 的预留过期时间
+// 默认为 DefaultReservationTTL（10 秒），必须在 (0, MaxReservationTTL] 范围内，
+// 否则 Reserve 返回 [ErrInvalidTTL]。仅对 Reserve 生效，其他获取方式忽略此选项。
+//
+// 预留应尽量短：被遗弃的预留在到期前一直占用名额。
+func WithReservationTTL(ttl time.Duration) AcquireOption {
+	return func(o *acquireOptions) {
+		o.reservationTTL = ttl
+	}
+}
+
+// WithFairQueue 启用公平队列（FIFO）模式
+//
+// 默认模式下，容量释放后由下一次恰好到达的 Acquire 获得，长时间等待的请求可能被饿死。
+// 启用后，Acquire/WaitAcquire 在首次尝试时进入资源的等待队列，
+// 容量释放后按入队顺序分配：只有排在前面的等待者都能获得空位时，当前等待者才会获得许可。
+// TryAcquire 不入队，仅当空位多于排队人数时才能获取，不会插队。
+//
+// 代价：
+//   - Redis 中每个资源额外维护两个键（queue 和 queue:alive），每次轮询多一次写操作
+//   - 等待者依靠轮询续约存活时间（10s），进程崩溃未离开队列的等待者最多阻塞队列 10s；
+//     轮询间隔（WithRetryDelay/WithPollInterval）应明显小于该值，否则等待者会失去排队位置
+//   - 租户配额已满的等待者会被移出队列（避免阻塞其他租户），下次轮询重新排到队尾
+//   - 同一资源的所有调用方都应启用该选项，未启用的获取不感知队列，仍可能抢到空位
+//
+// 示例:
+//
+//	permit, err := sem.Acquire(ctx, "inference",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithFairQueue(),
+//	)
+func WithFairQueue() AcquireOption {
+	return func(o *acquireOptions) {
+		o.fairQueue = true
+	}
+}
+
+// WithQueuePositionCallback 设置公平队列的排队位置回调
+//
+// 启用 WithFairQueue 时，Acquire/WaitAcquire 每次因容量不足未获取到许可后，
+// 以排在前面的等待者数量（0 表示已在队首）调用 fn。位置由 Redis 脚本在入队时原子计算，
+// 是该时刻的估计值：前面的等待者放弃或失联后会前移。
+// 调用方可据此取消 ctx 放弃等待。未启用公平队列、TryAcquire 或因租户配额不足失败时不回调。
+//
+// fn 在获取许可的 goroutine 中同步调用，应快速返回。
+//
+// 示例:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+//	defer cancel()
+//	permit, err := sem.WaitAcquire(ctx, "inference",
+//	    xsemaphore.WithCapacity(10),
+//	    xsemaphore.WithFairQueue(),
+//	    xsemaphore.WithQueuePositionCallback(func(ahead int) {
+//	        if ahead >= 100 {
+//	            cancel() // 前面还有 100 个，放弃等待
+//	        }
+//	    }),
+//	)
+func WithQueuePositionCallback(fn func(ahead int)) AcquireOption {
+	return func(o *acquireOptions) {
+		o.onQueuePosition = fn
+	}
+}
+
+// withFairWaiter 设置公平队列的等待者 ID（内部使用，WaitAcquire 在多次轮询间保持排队位置）
+func withFairWaiter(waiterID string) AcquireOption {
+	return func(o *acquireOptions) {
+		o.waiterID = waiterID
+	}
+}
+
+// withPermitCount 设置一次获取的许可数量（内部使用，由 AcquireN 设置）
+func withPermitCount(n int) AcquireOption {
+	return func(o *acquireOptions) {
+		o.count = n
+	}
+}
+
 // =============================================================================
 // 查询配置选项
 // =============================================================================
@@ -2,6 +2,7 @@ package xsemaphore
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -29,6 +30,12 @@ type permitBase struct {
 	// metadata 存储用户自定义的元数据
 	metadata map[string]string
 
+	// count 许可包含的名额数（AcquireN 获取时大于 1），0 视为 1
+	count int
+
+	// capacity 获取许可时的容量上限，用于释放时上报利用率指标（0 表示未知）
+	capacity int
+
 	// expiresAt 使用原子指针保护，避免读写竞争
 	expiresAt atomic.Pointer[time.Time]
 
@@ -39,6 +46,10 @@ type permitBase struct {
 
 	// 释放状态
 	released atomic.Bool
+
+	// 持有记录（见 heldPermits），未被记录时为 nil
+	acquiredAt time.Time
+	held       *heldPermits
 }
 
 // initPermitBase 初始化许可基础字段
@@ -58,6 +69,11 @@ func initPermitBase(base *permitBase, id, resource, tenantID string, expiresAt t
 	}
 }
 
+// memberIDs 返回许可在后端占用的全部成员 ID
+func (b *permitBase) memberIDs() []string {
+	return permitMemberIDs(b.id, b.count)
+}
+
 // ID 返回许可 ID
 func (b *permitBase) ID() string {
 	return b.id
@@ -105,8 +121,13 @@ func (b *permitBase) isReleased() bool {
 }
 
 // markReleased 标记为已释放，返回之前的状态
+// 首次标记时从信号量的持有记录中移除
 func (b *permitBase) markReleased() bool {
-	return b.released.Swap(true)
+	prev := b.released.Swap(true)
+	if !prev && b.held != nil {
+		b.held.remove(b)
+	}
+	return prev
 }
 
 // startAutoExtendLoop 启动自动续租循环
@@ -265,7 +286,40 @@ func (b *permitBase) extendCommon(ctx context.Context, tracer trace.Tracer, semT
 	defer span.End()
 	span.SetAttributes(extendSpanAttributes(semType, b.resource, b.tenantID, b.id)...)
 
-	newExpiresAt := time.Now().Add(b.ttl)
+	// 只延长不缩短：ExtendUntil 设置的更晚截止时间不会被相对续期覆盖
+	newExpiresAt := laterTime(time.Now().Add(b.ttl), b.ExpiresAt())
+	if err := doExtend(ctx, newExpiresAt); err != nil {
+		setSpanError(span, err)
+		return err
+	}
+
+	b.setExpiresAt(newExpiresAt)
+	setSpanOK(span)
+	return nil
+}
+
+// extendUntilCommon 续期到绝对截止时间的模板方法
+// 参数与 extendCommon 相同，deadline 为目标过期时间
+//
+// 设计决策: deadline 早于当前过期时间时仍以当前过期时间调用 doExtend，
+// 而非直接返回 nil，使调用方始终能通过返回值感知许可是否仍被持有。
+func (b *permitBase) extendUntilCommon(ctx context.Context, tracer trace.Tracer, semType string, deadline time.Time, doExtend func(context.Context, time.Time) error) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if now := time.Now(); !deadline.After(now) {
+		return fmt.Errorf("%w: deadline %s is not after now %s",
+			ErrInvalidDeadline, deadline.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
+	}
+	if b.isReleased() {
+		return ErrPermitNotHeld
+	}
+
+	ctx, span := startSpan(ctx, tracer, spanNameExtend)
+	defer span.End()
+	span.SetAttributes(extendSpanAttributes(semType, b.resource, b.tenantID, b.id)...)
+
+	newExpiresAt := laterTime(deadline, b.ExpiresAt())
 	if err := doExtend(ctx, newExpiresAt); err != nil {
 		setSpanError(span, err)
 		return err
@@ -276,6 +330,59 @@ func (b *permitBase) extendCommon(ctx context.Context, tracer trace.Tracer, semT
 	return nil
 }
 
+// laterTime 返回两个时间中较晚的一个
+func laterTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// handoffCommon 转移许可的模板方法
+// 参数：
+//   - ctx: 上下文
+//   - tracer: 用于创建 span 的 tracer
+//   - semType: 信号量类型（distributed/local/noop）
+//   - doExtend: 实际执行续期的函数，为接管方预留完整 TTL
+func (b *permitBase) handoffCommon(ctx context.Context, tracer trace.Tracer, semType string, doExtend func(context.Context, time.Time) error) (string, error) {
+	if ctx == nil {
+		return "", ErrNilContext
+	}
+	if b.isReleased() {
+		return "", ErrPermitNotHeld
+	}
+
+	// 创建 span（转移对本实例而言等同于释放，复用 release 的属性）
+	ctx, span := startSpan(ctx, tracer, spanNameHandoff)
+	defer span.End()
+	span.SetAttributes(releaseSpanAttributes(semType, b.resource, b.tenantID, b.id)...)
+
+	// 续期失败时不交出所有权，原持有者仍可重试或正常释放
+	newExpiresAt := time.Now().Add(b.ttl)
+	if err := doExtend(ctx, newExpiresAt); err != nil {
+		setSpanError(span, err)
+		return "", err
+	}
+	b.setExpiresAt(newExpiresAt)
+
+	token, err := encodeHandoffToken(b)
+	if err != nil {
+		setSpanError(span, err)
+		return "", err
+	}
+
+	// 交出所有权：此后本句柄不再操作后端，后端许可由接管方负责
+	if b.markReleased() {
+		// 并发 Release 已先完成，后端许可已不存在
+		setSpanError(span, ErrPermitNotHeld)
+		return "", ErrPermitNotHeld
+	}
+	b.stopAutoExtend()
+
+	setSpanOK(span)
+	return token, nil
+}
+
 // =============================================================================
 // Redis 许可实现
 // =============================================================================
@@ -309,11 +416,27 @@ func (p *redisPermit) Extend(ctx context.Context) error {
 		})
 }
 
+// ExtendUntil 续期许可到指定截止时间
+func (p *redisPermit) ExtendUntil(ctx context.Context, deadline time.Time) error {
+	return p.extendUntilCommon(ctx, p.sem.opts.tracer, SemaphoreTypeDistributed, deadline,
+		func(ctx context.Context, newExpiresAt time.Time) error {
+			return p.sem.extendPermit(ctx, p, newExpiresAt)
+		})
+}
+
 // StartAutoExtend 启动自动续租
 func (p *redisPermit) StartAutoExtend(interval time.Duration) (stop func()) {
 	return p.startAutoExtendLoop(interval, p.Extend, p.sem)
 }
 
+// Handoff 转移许可
+func (p *redisPermit) Handoff(ctx context.Context) (string, error) {
+	return p.handoffCommon(ctx, p.sem.opts.tracer, SemaphoreTypeDistributed,
+		func(ctx context.Context, newExpiresAt time.Time) error {
+			return p.sem.extendPermit(ctx, p, newExpiresAt)
+		})
+}
+
 // =============================================================================
 // 本地许可实现
 // =============================================================================
@@ -347,11 +470,27 @@ func (p *localPermit) Extend(ctx context.Context) error {
 		})
 }
 
+// ExtendUntil 续期本地许可到指定截止时间
+func (p *localPermit) ExtendUntil(ctx context.Context, deadline time.Time) error {
+	return p.extendUntilCommon(ctx, p.sem.opts.tracer, SemaphoreTypeLocal, deadline,
+		func(ctx context.Context, newExpiresAt time.Time) error {
+			return p.sem.extendPermit(ctx, p, newExpiresAt)
+		})
+}
+
 // StartAutoExtend 启动自动续租
 func (p *localPermit) StartAutoExtend(interval time.Duration) (stop func()) {
 	return p.startAutoExtendLoop(interval, p.Extend, p.sem)
 }
 
+// Handoff 转移本地许可
+func (p *localPermit) Handoff(ctx context.Context) (string, error) {
+	return p.handoffCommon(ctx, p.sem.opts.tracer, SemaphoreTypeLocal,
+		func(ctx context.Context, newExpiresAt time.Time) error {
+			return p.sem.extendPermit(ctx, p, newExpiresAt)
+		})
+}
+
 // =============================================================================
 // 编译时接口检查
 // =============================================================================
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/omeyang/xkit/internal/rediscompat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -313,6 +314,86 @@ func TestPermit_Extend_MultipleExtensions(t *testing.T) {
 	releasePermit(t, ctx, permit)
 }
 
+func TestPermit_ExtendUntil(t *testing.T) {
+	for name, newSem := range map[string]func(t *testing.T) Semaphore{
+		"redis lua": func(t *testing.T) Semaphore {
+			sem, _ := setupSemaphore(t, WithScriptMode(rediscompat.ScriptModeLua))
+			return sem
+		},
+		"redis compat": func(t *testing.T) Semaphore {
+			sem, _ := setupSemaphore(t, WithScriptMode(rediscompat.ScriptModeCompat))
+			return sem
+		},
+		"local": func(t *testing.T) Semaphore {
+			sem := newLocalSemaphore(defaultOptions())
+			t.Cleanup(func() { closeSemaphore(t, sem) })
+			return sem
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sem := newSem(t)
+			ctx := context.Background()
+
+			permit, err := sem.TryAcquire(ctx, "extend-until", WithCapacity(10), WithTTL(time.Minute))
+			require.NoError(t, err)
+			require.NotNil(t, permit)
+			defer releasePermit(t, ctx, permit)
+
+			backendExpiry := func() time.Time {
+				t.Helper()
+				permits, err := sem.Inspect(ctx, "extend-until")
+				require.NoError(t, err)
+				require.Len(t, permits, 1)
+				return permits[0].ExpiresAt
+			}
+
+			// 延长到绝对截止时间
+			deadline := time.Now().Add(time.Hour).Truncate(time.Millisecond)
+			require.NoError(t, permit.ExtendUntil(ctx, deadline))
+			assert.True(t, permit.ExpiresAt().Equal(deadline))
+			assert.WithinDuration(t, deadline, backendExpiry(), time.Millisecond)
+
+			// 更早的截止时间不缩短
+			require.NoError(t, permit.ExtendUntil(ctx, time.Now().Add(30*time.Minute)))
+			assert.True(t, permit.ExpiresAt().Equal(deadline))
+			assert.WithinDuration(t, deadline, backendExpiry(), time.Millisecond)
+
+			// 相对续期也不覆盖更晚的截止时间
+			require.NoError(t, permit.Extend(ctx))
+			assert.True(t, permit.ExpiresAt().Equal(deadline))
+
+			// 截止时间必须在未来
+			err = permit.ExtendUntil(ctx, time.Now().Add(-time.Second))
+			require.ErrorIs(t, err, ErrInvalidDeadline)
+			assert.True(t, permit.ExpiresAt().Equal(deadline))
+		})
+	}
+}
+
+func TestPermit_ExtendUntil_Errors(t *testing.T) {
+	sem, _ := setupSemaphore(t)
+	ctx := context.Background()
+
+	permit, err := sem.TryAcquire(ctx, "extend-until-errors", WithCapacity(10))
+	require.NoError(t, err)
+	require.NotNil(t, permit)
+
+	err = permit.ExtendUntil(nil, time.Now().Add(time.Minute)) //nolint:staticcheck // 测试 nil context
+	assert.ErrorIs(t, err, ErrNilContext)
+
+	require.NoError(t, permit.Release(ctx))
+	assert.ErrorIs(t, permit.ExtendUntil(ctx, time.Now().Add(time.Minute)), ErrPermitNotHeld)
+}
+
+func TestNoopPermit_ExtendUntil(t *testing.T) {
+	permit, err := newNoopPermit(context.Background(), "noop", "", time.Minute, nil, defaultOptions())
+	require.NoError(t, err)
+
+	deadline := time.Now().Add(time.Hour)
+	require.NoError(t, permit.ExtendUntil(context.Background(), deadline))
+	assert.True(t, permit.ExpiresAt().Equal(deadline))
+}
+
 // =============================================================================
 // Permit 释放测试
 // =============================================================================
@@ -2,6 +2,7 @@ package xsemaphore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -25,6 +26,9 @@ import (
 //  3. Pipeline 2（仅回滚时）: ZREM 回滚
 //  4. Pipeline 3（仅成功时）: EXPIRE 设置键 TTL
 //
+// 公平队列模式下，第 1 步前先维护等待队列（fairQueueAheadCompat），
+// 第 2 步的容量检查计入排在前面的等待者。
+//
 // 竞态分析：两客户端同时 add 且都超容 → 都 undo → 短暂欠利用（安全，重试自愈）。
 // 最坏情况是误拒绝，不会过量放行。
 func (s *redisSemaphore) doAcquireCompat(
@@ -51,16 +55,26 @@ func (s *redisSemaphore) doAcquireCompat(
 	nowMs := now.UnixMilli()
 	expireAtMs := expiresAt.UnixMilli()
 
-	// Pipeline 1: 清理 + 添加 + 计数
+	// 公平队列：空位需先满足排在前面的等待者
+	var ahead int64
+	if cfg.fairQueue {
+		if ahead, err = s.fairQueueAheadCompat(ctx, resource, cfg.waiterID, nowMs); err != nil {
+			return nil, ReasonUnknown, err
+		}
+	}
+
+	// Pipeline 1: 清理 + 添加 + 计数（批量许可的全部成员在同一条 ZADD 中添加）
+	members := permitMemberIDs(permitID, cfg.permitCount())
+	zs := memberZs(members, expireAtMs)
 	pipe := s.client.Pipeline()
 	pipe.ZRemRangeByScore(ctx, globalKey, "-inf", strconv.FormatInt(nowMs, 10))
-	pipe.ZAdd(ctx, globalKey, redis.Z{Score: float64(expireAtMs), Member: permitID})
+	pipe.ZAdd(ctx, globalKey, zs...)
 	globalCardCmd := pipe.ZCard(ctx, globalKey)
 
 	var tenantCardCmd *redis.IntCmd
 	if hasTenantQuota {
 		pipe.ZRemRangeByScore(ctx, tenantKey, "-inf", strconv.FormatInt(nowMs, 10))
-		pipe.ZAdd(ctx, tenantKey, redis.Z{Score: float64(expireAtMs), Member: permitID})
+		pipe.ZAdd(ctx, tenantKey, zs...)
 		tenantCardCmd = pipe.ZCard(ctx, tenantKey)
 	}
 
@@ -74,29 +88,62 @@ func (s *redisSemaphore) doAcquireCompat(
 		tenantCount = tenantCardCmd.Val()
 	}
 
-	// 检查容量
-	if globalCount > int64(cfg.capacity) {
-		s.undoAcquireCompat(ctx, globalKey, tenantKey, permitID, hasTenantQuota)
+	// 检查容量（回滚后的活跃数不含本次添加的成员）
+	if globalCount+ahead > int64(cfg.capacity) {
+		s.undoAcquireCompat(ctx, globalKey, tenantKey, members, hasTenantQuota)
+		s.recordUtilization(ctx, resource, int(globalCount)-len(members), cfg.capacity)
+		cfg.reportQueuePosition(int(ahead))
 		return nil, ReasonCapacityFull, nil
 	}
 	if hasTenantQuota && tenantCount > int64(cfg.tenantQuota) {
-		s.undoAcquireCompat(ctx, globalKey, tenantKey, permitID, hasTenantQuota)
+		s.undoAcquireCompat(ctx, globalKey, tenantKey, members, hasTenantQuota)
+		s.recordUtilization(ctx, resource, int(globalCount)-len(members), cfg.capacity)
+		// 租户配额满的等待者移出队列，避免阻塞其他租户（与 fair_acquire.lua 一致）
+		if cfg.waiterID != "" {
+			s.leaveFairQueue(ctx, resource, cfg.waiterID)
+		}
 		return nil, ReasonTenantQuotaExceeded, nil
 	}
+	if cfg.waiterID != "" {
+		s.leaveFairQueue(ctx, resource, cfg.waiterID)
+	}
 
 	// 成功: 设置键 TTL（只延长，不缩短）
 	s.setKeyTTLCompat(ctx, globalKey, tenantKey, hasTenantQuota, nowMs, expireAtMs)
 
+	s.recordUtilization(ctx, resource, int(globalCount), cfg.capacity)
 	permit := newRedisPermit(s, permitID, resource, tenantID, expiresAt, cfg.ttl, hasTenantQuota, cfg.metadata)
+	permit.count = cfg.permitCount()
+	permit.capacity = cfg.capacity
+	s.held.track(&permit.permitBase, SemaphoreTypeDistributed)
 	return permit, ReasonUnknown, nil
 }
 
-// undoAcquireCompat 回滚获取操作（移除刚添加的许可）
-func (s *redisSemaphore) undoAcquireCompat(ctx context.Context, globalKey, tenantKey, permitID string, hasTenant bool) {
+// memberZs 构建许可成员的 ZADD 参数
+func memberZs(members []string, expireAtMs int64) []redis.Z {
+	zs := make([]redis.Z, len(members))
+	for i, m := range members {
+		zs[i] = redis.Z{Score: float64(expireAtMs), Member: m}
+	}
+	return zs
+}
+
+// memberArgs 将许可成员转换为 ZREM 参数
+func memberArgs(members []string) []any {
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return args
+}
+
+// undoAcquireCompat 回滚获取操作（移除刚添加的许可成员）
+func (s *redisSemaphore) undoAcquireCompat(ctx context.Context, globalKey, tenantKey string, members []string, hasTenant bool) {
+	args := memberArgs(members)
 	pipe := s.client.Pipeline()
-	pipe.ZRem(ctx, globalKey, permitID)
+	pipe.ZRem(ctx, globalKey, args...)
 	if hasTenant {
-		pipe.ZRem(ctx, tenantKey, permitID)
+		pipe.ZRem(ctx, tenantKey, args...)
 	}
 	// 设计决策: 回滚失败不影响正确性（TTL 自然过期清理），故忽略错误。
 	if _, err := pipe.Exec(ctx); err != nil {
@@ -144,8 +191,9 @@ func expireIfLonger(ctx context.Context, client redis.UniversalClient, key strin
 // 租户条目会通过 TTL 自然过期。
 func (s *redisSemaphore) releasePermitCompat(ctx context.Context, p *redisPermit) error {
 	globalKey := s.buildGlobalKey(p.resource)
+	members := memberArgs(p.memberIDs())
 
-	removed, err := s.client.ZRem(ctx, globalKey, p.id).Result()
+	removed, err := s.client.ZRem(ctx, globalKey, members...).Result()
 	if err != nil {
 		return fmt.Errorf("release compat failed: %w", err)
 	}
@@ -157,11 +205,16 @@ func (s *redisSemaphore) releasePermitCompat(ctx context.Context, p *redisPermit
 	if p.tenantID != "" && p.hasTenantQuota {
 		tenantKey := s.buildTenantKey(p.resource, p.tenantID)
 		//nolint:errcheck // 租户键清理失败 TTL 自愈
-		s.client.ZRem(ctx, tenantKey, p.id)
+		s.client.ZRem(ctx, tenantKey, members...)
 	}
 
 	if s.opts.metrics != nil {
 		s.opts.metrics.RecordRelease(ctx, SemaphoreTypeDistributed, p.resource)
+		// 兼容模式需额外一次 ZCOUNT 获取剩余许可数，仅在启用指标时执行
+		nowMs := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		if active, err := s.client.ZCount(ctx, globalKey, "("+nowMs, "+inf").Result(); err == nil {
+			s.recordUtilization(ctx, p.resource, int(active), p.capacity)
+		}
 	}
 	return nil
 }
@@ -218,10 +271,11 @@ func (s *redisSemaphore) updatePermitScore(ctx context.Context, p *redisPermit,
 		tenantKey = s.buildTenantKey(p.resource, p.tenantID)
 	}
 
+	zs := memberZs(p.memberIDs(), newExpireAtMs)
 	pipe := s.client.Pipeline()
-	pipe.ZAdd(ctx, globalKey, redis.Z{Score: float64(newExpireAtMs), Member: p.id})
+	pipe.ZAdd(ctx, globalKey, zs...)
 	if hasTenant {
-		pipe.ZAdd(ctx, tenantKey, redis.Z{Score: float64(newExpireAtMs), Member: p.id})
+		pipe.ZAdd(ctx, tenantKey, zs...)
 	}
 	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("extend compat failed: %w", err)
@@ -231,6 +285,100 @@ func (s *redisSemaphore) updatePermitScore(ctx context.Context, p *redisPermit,
 	return nil
 }
 
+// handoffCompat 使用基础命令以新许可替换原许可（兼容模式）
+//
+// 算法：先加后认领
+//  1. ZSCORE 检查原许可存在且未过期
+//  2. Pipeline: ZADD 新许可 + ZREM 原许可 [+ 租户等价操作]
+//  3. ZREM 返回 0（原许可已被其他接管方认领或被清理）→ 回滚新许可
+//
+// 先添加新许可再删除原许可，中间状态只会多计许可（误拒绝其他获取者），
+// 不会过量放行；原许可 ID 的 ZREM 原子性保证同一 token 只有一个接管方成功。
+// 批量许可的其余成员随原许可 ID 一并替换。
+func (s *redisSemaphore) handoffCompat(ctx context.Context, globalKey, tenantKey, oldID, newID string, count int, nowMs, expireAtMs int64) error {
+	if err := s.checkPermitExists(ctx, globalKey, oldID, nowMs); err != nil {
+		if IsPermitNotHeld(err) {
+			return ErrHandoffExpired
+		}
+		return err
+	}
+	if err := s.checkHandoffMembersCompat(ctx, globalKey, tenantKey, oldID, count); err != nil {
+		return err
+	}
+
+	hasTenant := tenantKey != ""
+	newMembers := permitMemberIDs(newID, count)
+	newZs := memberZs(newMembers, expireAtMs)
+	oldRest := memberArgs(permitMemberIDs(oldID, count)[1:])
+	pipe := s.client.Pipeline()
+	pipe.ZAdd(ctx, globalKey, newZs...)
+	removedCmd := pipe.ZRem(ctx, globalKey, oldID)
+	if hasTenant {
+		pipe.ZAdd(ctx, tenantKey, newZs...)
+		pipe.ZRem(ctx, tenantKey, oldID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("handoff compat failed: %w", err)
+	}
+
+	if removedCmd.Val() == 0 {
+		s.undoAcquireCompat(ctx, globalKey, tenantKey, newMembers, hasTenant)
+		return ErrHandoffExpired
+	}
+
+	// 认领成功后移除原许可的其余成员（失败时随 score 过期自愈）
+	if len(oldRest) > 0 {
+		pipe := s.client.Pipeline()
+		pipe.ZRem(ctx, globalKey, oldRest...)
+		if hasTenant {
+			pipe.ZRem(ctx, tenantKey, oldRest...)
+		}
+		//nolint:errcheck // 原成员清理失败 TTL 自愈
+		pipe.Exec(ctx)
+	}
+
+	s.setKeyTTLCompat(ctx, globalKey, tenantKey, hasTenant, nowMs, expireAtMs)
+	return nil
+}
+
+// checkHandoffMembersCompat 校验原许可的实际成员数与 token 中的 count 一致（兼容模式）
+//
+// 与 handoff.lua 一致：count 来自未签名的 token，偏大会凭空新增许可，偏小会遗留原成员，
+// 均返回 ErrHandoffExpired。校验与替换之间不是原子的，但成员仅随原许可释放或过期而减少，不会凭空增加。
+func (s *redisSemaphore) checkHandoffMembersCompat(ctx context.Context, globalKey, tenantKey, oldID string, count int) error {
+	keys := []string{globalKey}
+	if tenantKey != "" {
+		keys = append(keys, tenantKey)
+	}
+	members := permitMemberIDs(oldID, count)
+	extra := oldID + permitMemberSeparator + strconv.Itoa(count)
+
+	pipe := s.client.Pipeline()
+	var held []*redis.FloatCmd
+	var extras []*redis.FloatCmd
+	for _, key := range keys {
+		for _, member := range members {
+			held = append(held, pipe.ZScore(ctx, key, member))
+		}
+		extras = append(extras, pipe.ZScore(ctx, key, extra))
+	}
+	// redis.Nil 表示成员不存在，逐条命令判断
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("handoff compat failed: %w", err)
+	}
+	for _, cmd := range held {
+		if cmd.Err() != nil {
+			return ErrHandoffExpired
+		}
+	}
+	for _, cmd := range extras {
+		if cmd.Err() == nil {
+			return ErrHandoffExpired
+		}
+	}
+	return nil
+}
+
 // queryCompat 使用 ZCOUNT 查询许可状态（兼容模式）
 //
 // 纯读取操作，完全正确，无原子性要求。
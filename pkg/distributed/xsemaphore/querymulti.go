@@ -0,0 +1,208 @@
+package xsemaphore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// QueryResult 批量查询中单个资源的结果（QueryMulti 返回）
+type QueryResult struct {
+	// Info 资源使用信息，Err 非 nil 时为 nil
+	Info *ResourceInfo
+
+	// Err 查询该资源失败的错误（如资源名非法、Redis 节点不可用）
+	Err error
+}
+
+// prepareQueryMulti 准备 QueryMulti 的公共逻辑：校验与资源无关的参数
+//
+// 资源名在逐个资源的结果中校验，单个非法资源不影响其他资源的查询。
+func prepareQueryMulti(ctx context.Context, opts []QueryOption, closed bool) (*queryOptions, string, error) {
+	if ctx == nil {
+		return nil, "", ErrNilContext
+	}
+	if closed {
+		return nil, "", ErrSemaphoreClosed
+	}
+	cfg := applyQueryOptions(opts)
+	if err := cfg.validate(); err != nil {
+		return nil, "", err
+	}
+	tenantID := resolveTenantID(ctx, cfg.tenantID)
+	if err := validateTenantID(tenantID); err != nil {
+		return nil, "", err
+	}
+	return cfg, tenantID, nil
+}
+
+// queryResultInfo 根据计数构建 ResourceInfo，与 Query 的计算方式一致
+func queryResultInfo(resource, tenantID string, cfg *queryOptions, globalUsed, tenantUsed int) *ResourceInfo {
+	return &ResourceInfo{
+		Resource:        resource,
+		GlobalCapacity:  cfg.capacity,
+		GlobalUsed:      globalUsed,
+		GlobalAvailable: max(0, cfg.capacity-globalUsed),
+		TenantID:        tenantID,
+		TenantQuota:     cfg.tenantQuota,
+		TenantUsed:      tenantUsed,
+		TenantAvailable: max(0, cfg.tenantQuota-tenantUsed),
+	}
+}
+
+// redisQueryCmds 单个资源在 pipeline 中的计数命令
+type redisQueryCmds struct {
+	global *redis.IntCmd
+	tenant *redis.IntCmd // 未查询租户时为 nil
+}
+
+// err 返回第一个失败命令的错误
+func (c redisQueryCmds) err() error {
+	if err := c.global.Err(); err != nil {
+		return err
+	}
+	if c.tenant != nil {
+		return c.tenant.Err()
+	}
+	return nil
+}
+
+// QueryMulti 在单次 pipeline 中批量查询多个资源的状态
+//
+// 设计决策: 使用只读的 ZCOUNT 而非 query.lua。pipeline 中的 EVALSHA 遇到 NOSCRIPT 时
+// 无法像 Script.Run 那样自动回退到 EVAL，而 query.lua 本身只有 ZCOUNT，直接发送命令语义一致，
+// 兼容模式也无需分流。每条命令只访问一个键，Cluster 模式下 ClusterClient 按 slot 把命令
+// 分组发往各自节点并并行执行；不同资源的键位于不同 slot 不会触发 CROSSSLOT，
+// 某个节点失败只影响落在该节点上的资源。
+func (s *redisSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	ctx, cancel := applyDefaultTimeout(ctx, s.opts.defaultTimeout)
+	defer cancel()
+
+	cfg, tenantID, err := prepareQueryMulti(ctx, opts, s.closed.Load())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, span := startSpan(ctx, s.opts.tracer, spanNameQueryMulti)
+	defer span.End()
+	span.SetAttributes(
+		attribute.String(attrSemType, SemaphoreTypeDistributed),
+		attribute.Int(attrResourceCount, len(resources)),
+	)
+	if tenantID != "" {
+		span.SetAttributes(attribute.String(attrTenantID, tenantID))
+	}
+
+	start := time.Now()
+	// 与 Query/Acquire 一致：仅在 tenantID 非空且 tenantQuota > 0 时才查询租户键
+	hasTenantKey := tenantID != "" && cfg.tenantQuota > 0
+	// "(" 前缀表示开区间，与 query.lua 一致排除恰好等于 now 的过期条目
+	minScore := "(" + strconv.FormatInt(start.UnixMilli(), 10)
+
+	results := make(map[string]QueryResult, len(resources))
+	cmds := make(map[string]redisQueryCmds, len(resources))
+	pipe := s.client.Pipeline()
+	for _, resource := range resources {
+		if _, ok := results[resource]; ok {
+			continue
+		}
+		if _, ok := cmds[resource]; ok {
+			continue
+		}
+		if err := validateResource(resource); err != nil {
+			results[resource] = QueryResult{Err: err}
+			continue
+		}
+		c := redisQueryCmds{global: pipe.ZCount(ctx, s.buildGlobalKey(resource), minScore, "+inf")}
+		if hasTenantKey {
+			c.tenant = pipe.ZCount(ctx, s.buildTenantKey(resource, tenantID), minScore, "+inf")
+		}
+		cmds[resource] = c
+	}
+	var execErr error
+	if len(cmds) > 0 {
+		_, execErr = pipe.Exec(ctx)
+		// 逐条命令检查错误：Cluster 模式下部分节点失败时其余资源仍有结果。
+		// 单节点客户端重试耗尽后不会把连接错误写入各命令，此时以 Exec 的错误为准。
+		for _, c := range cmds {
+			if c.err() != nil {
+				execErr = nil
+				break
+			}
+		}
+	}
+
+	var failed int
+	for resource, c := range cmds {
+		err := c.err()
+		if err == nil {
+			err = execErr
+		}
+		if err != nil {
+			failed++
+			results[resource] = QueryResult{Err: fmt.Errorf("query multi failed: %w", err)}
+			if s.opts.metrics != nil {
+				s.opts.metrics.RecordQuery(ctx, SemaphoreTypeDistributed, resource, false, time.Since(start))
+			}
+			continue
+		}
+		globalUsed := int(c.global.Val())
+		var tenantUsed int
+		if c.tenant != nil {
+			tenantUsed = int(c.tenant.Val())
+		}
+		results[resource] = QueryResult{Info: queryResultInfo(resource, tenantID, cfg, globalUsed, tenantUsed)}
+		if s.opts.metrics != nil {
+			s.opts.metrics.RecordQuery(ctx, SemaphoreTypeDistributed, resource, true, time.Since(start))
+		}
+		s.recordUtilization(ctx, resource, globalUsed, cfg.capacity)
+	}
+
+	if failed > 0 {
+		setSpanError(span, fmt.Errorf("query multi: %d of %d resources failed", failed, len(cmds)))
+	} else {
+		setSpanOK(span)
+	}
+	return results, nil
+}
+
+// QueryMulti 批量查询多个本地资源的状态
+//
+// 本地实现没有网络往返，逐个调用 Query，结果与 Query 完全一致。
+func (s *localSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	if _, _, err := prepareQueryMulti(ctx, opts, s.closed.Load()); err != nil {
+		return nil, err
+	}
+	results := make(map[string]QueryResult, len(resources))
+	for _, resource := range resources {
+		if _, ok := results[resource]; ok {
+			continue
+		}
+		info, err := s.Query(ctx, resource, opts...)
+		results[resource] = QueryResult{Info: info, Err: err}
+	}
+	return results, nil
+}
+
+// QueryMulti 批量查询多个资源的状态，Redis 不可用时按降级策略处理
+//
+// 降级以资源为单位：只有因 Redis 错误失败的资源按策略改用本地结果（FallbackLocal）、
+// 空闲状态（FallbackOpen）或 ErrRedisUnavailable（FallbackClose），与 Query 一致。
+func (f *fallbackSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error) {
+	results, err := f.distributed.QueryMulti(ctx, resources, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for resource, res := range results {
+		if !IsRedisError(res.Err) {
+			continue
+		}
+		info, err := f.queryFallback(ctx, resource, opts, res.Err)
+		results[resource] = QueryResult{Info: info, Err: err}
+	}
+	return results, nil
+}
@@ -0,0 +1,193 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryMulti_Redis(t *testing.T) {
+	testQueryMulti(t, func(t *testing.T) Semaphore {
+		sem, _ := setupSemaphore(t)
+		return sem
+	})
+}
+
+func TestQueryMulti_Local(t *testing.T) {
+	testQueryMulti(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	})
+}
+
+// testQueryMulti 验证 QueryMulti 与逐个 Query 的结果一致
+func testQueryMulti(t *testing.T, newSem func(t *testing.T) Semaphore) {
+	ctx := context.Background()
+
+	t.Run("matches query", func(t *testing.T) {
+		sem := newSem(t)
+		for range 2 {
+			p, err := sem.TryAcquire(ctx, "a", WithCapacity(10), WithTenantID("t1"), WithTenantQuota(5))
+			require.NoError(t, err)
+			require.NotNil(t, p)
+		}
+		p, err := sem.TryAcquire(ctx, "b", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+
+		opts := []QueryOption{QueryWithCapacity(10), QueryWithTenantID("t1"), QueryWithTenantQuota(5)}
+		results, err := sem.QueryMulti(ctx, []string{"a", "b", "c", "a"}, opts...)
+		require.NoError(t, err)
+		require.Len(t, results, 3, "duplicate resources are queried once")
+
+		for _, resource := range []string{"a", "b", "c"} {
+			require.NoError(t, results[resource].Err, resource)
+			want, err := sem.Query(ctx, resource, opts...)
+			require.NoError(t, err)
+			assert.Equal(t, want, results[resource].Info, resource)
+		}
+		assert.Equal(t, 2, results["a"].Info.GlobalUsed)
+		assert.Equal(t, 2, results["a"].Info.TenantUsed)
+		assert.Equal(t, 1, results["b"].Info.GlobalUsed)
+		assert.Equal(t, 0, results["c"].Info.GlobalUsed)
+		assert.Equal(t, 10, results["c"].Info.GlobalAvailable)
+	})
+
+	t.Run("excludes expired permits", func(t *testing.T) {
+		sem := newSem(t)
+		_, err := sem.TryAcquire(ctx, "a", WithCapacity(10), WithTTL(20*time.Millisecond))
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+
+		results, err := sem.QueryMulti(ctx, []string{"a"})
+		require.NoError(t, err)
+		require.NoError(t, results["a"].Err)
+		assert.Zero(t, results["a"].Info.GlobalUsed)
+	})
+
+	t.Run("invalid resource is reported per resource", func(t *testing.T) {
+		sem := newSem(t)
+		results, err := sem.QueryMulti(ctx, []string{"ok", "", "bad resource"})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.NoError(t, results["ok"].Err)
+		assert.NotNil(t, results["ok"].Info)
+		assert.ErrorIs(t, results[""].Err, ErrInvalidResource)
+		assert.Nil(t, results[""].Info)
+		assert.ErrorIs(t, results["bad resource"].Err, ErrInvalidResource)
+	})
+
+	t.Run("empty resources", func(t *testing.T) {
+		sem := newSem(t)
+		results, err := sem.QueryMulti(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		sem := newSem(t)
+		_, err := sem.QueryMulti(nil, []string{"a"}) //nolint:staticcheck // 测试 nil context
+		assert.ErrorIs(t, err, ErrNilContext)
+		_, err = sem.QueryMulti(ctx, []string{"a"}, QueryWithTenantID("bad:tenant"))
+		assert.ErrorIs(t, err, ErrInvalidTenantID)
+		_, err = sem.QueryMulti(ctx, []string{"a"}, QueryWithCapacity(0))
+		assert.ErrorIs(t, err, ErrInvalidCapacity)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		sem := newSem(t)
+		require.NoError(t, sem.Close(ctx))
+		_, err := sem.QueryMulti(ctx, []string{"a"})
+		assert.ErrorIs(t, err, ErrSemaphoreClosed)
+	})
+}
+
+func TestQueryMulti_ClusterClient(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	t.Cleanup(func() { _ = client.Close() })
+	sem, err := New(client)
+	require.NoError(t, err)
+	t.Cleanup(func() { closeSemaphore(t, sem) })
+	ctx := context.Background()
+
+	// 不同资源的键使用各自的 hash tag，位于不同 slot
+	resources := []string{"alpha", "beta", "gamma", "delta"}
+	for i, resource := range resources {
+		for range i {
+			p, err := sem.TryAcquire(ctx, resource, WithCapacity(10))
+			require.NoError(t, err)
+			require.NotNil(t, p)
+		}
+	}
+
+	results, err := sem.QueryMulti(ctx, resources, QueryWithCapacity(10))
+	require.NoError(t, err)
+	for i, resource := range resources {
+		require.NoError(t, results[resource].Err, resource)
+		assert.Equal(t, i, results[resource].Info.GlobalUsed, resource)
+	}
+}
+
+func TestQueryMulti_RedisError(t *testing.T) {
+	sem, mr := setupSemaphore(t)
+	mr.Close()
+
+	results, err := sem.QueryMulti(context.Background(), []string{"a", "b", ""})
+	require.NoError(t, err)
+	assert.True(t, IsRedisError(results["a"].Err))
+	assert.True(t, IsRedisError(results["b"].Err))
+	assert.ErrorIs(t, results[""].Err, ErrInvalidResource)
+}
+
+func TestQueryMulti_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("local", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+		mr.Close()
+
+		p, err := sem.TryAcquire(ctx, "a", WithCapacity(10))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+
+		results, err := sem.QueryMulti(ctx, []string{"a", "b"}, QueryWithCapacity(10))
+		require.NoError(t, err)
+		require.NoError(t, results["a"].Err)
+		assert.Equal(t, 1, results["a"].Info.GlobalUsed)
+		require.NoError(t, results["b"].Err)
+		assert.Zero(t, results["b"].Info.GlobalUsed)
+	})
+
+	t.Run("open", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackOpen))
+		mr.Close()
+
+		results, err := sem.QueryMulti(ctx, []string{"a", ""}, QueryWithCapacity(10))
+		require.NoError(t, err)
+		require.NoError(t, results["a"].Err)
+		assert.Equal(t, 10, results["a"].Info.GlobalAvailable)
+		assert.ErrorIs(t, results[""].Err, ErrInvalidResource, "non-redis errors are not degraded")
+	})
+
+	t.Run("close", func(t *testing.T) {
+		sem, mr := setupSemaphore(t, WithFallback(FallbackClose))
+		mr.Close()
+
+		results, err := sem.QueryMulti(ctx, []string{"a"})
+		require.NoError(t, err)
+		assert.ErrorIs(t, results["a"].Err, ErrRedisUnavailable)
+	})
+
+	t.Run("top-level error", func(t *testing.T) {
+		sem, _ := setupSemaphore(t, WithFallback(FallbackLocal))
+		_, err := sem.QueryMulti(nil, []string{"a"}) //nolint:staticcheck // 测试 nil context
+		assert.ErrorIs(t, err, ErrNilContext)
+	})
+}
@@ -71,6 +71,7 @@ type redisSemaphore struct {
 	scripts    *scripts
 	scriptMode rediscompat.ScriptMode // 已解析的脚本模式（不会是 Auto）
 	closed     atomic.Bool
+	held       heldPermits
 }
 
 // New 创建 Redis 信号量
@@ -204,6 +205,9 @@ func (s *redisSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 	if err := cfg.validateRetryParams(); err != nil {
 		return nil, err
 	}
+	if err := joinFairQueue(ctx, cfg); err != nil {
+		return nil, err
+	}
 
 	// 创建 span
 	ctx, span := startSpan(ctx, s.opts.tracer, spanNameAcquire)
@@ -215,6 +219,11 @@ func (s *redisSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 
 	permit, lastReason, retryCount, err := s.acquireWithRetry(ctx, resource, tenantID, cfg)
 
+	// 未获取到许可时离开公平队列（获取成功时脚本已将其移出）
+	if permit == nil && cfg.waiterID != "" {
+		s.leaveFairQueue(ctx, resource, cfg.waiterID)
+	}
+
 	// 计算总耗时
 	totalDuration := time.Since(start)
 
@@ -249,6 +258,13 @@ func (s *redisSemaphore) Acquire(ctx context.Context, resource string, opts ...A
 	return nil, ErrAcquireFailed
 }
 
+// WaitAcquire 有界等待式获取许可
+func (s *redisSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error) {
+	ctx, cancel := applyDefaultTimeout(ctx, s.opts.defaultTimeout)
+	defer cancel()
+	return waitAcquire(ctx, resource, opts, s.TryAcquire, s.leaveFairQueue)
+}
+
 // acquireWithRetry 执行带重试的获取逻辑
 // 返回值：permit, lastReason, retryCount, error
 // retryCount 表示实际发生的重试次数（不包括首次尝试）
@@ -265,6 +281,9 @@ func (s *redisSemaphore) acquireWithRetry(ctx context.Context, resource, tenantI
 			// 当前 attempt 尚未执行，重试次数 = 已完成的尝试数 - 1
 			return nil, lastReason, max(0, attempt-1), err
 		}
+		if attempt > 0 {
+			cfg.refreshCapacity()
+		}
 
 		permit, reason, redisErr := s.tryAcquireOnce(ctx, resource, tenantID, cfg)
 
@@ -316,6 +335,13 @@ func (s *redisSemaphore) recordAcquireMetrics(ctx context.Context, resource stri
 	}
 }
 
+// recordUtilization 记录资源利用率指标
+func (s *redisSemaphore) recordUtilization(ctx context.Context, resource string, active, capacity int) {
+	if s.opts.metrics != nil {
+		s.opts.metrics.RecordUtilization(ctx, SemaphoreTypeDistributed, resource, active, capacity)
+	}
+}
+
 // logAcquireExhausted 记录重试耗尽日志
 func (s *redisSemaphore) logAcquireExhausted(ctx context.Context, resource string, maxRetries int, reason AcquireFailReason) {
 	if s.opts.logger != nil {
@@ -334,6 +360,11 @@ func (s *redisSemaphore) doAcquire(
 	tenantID string,
 	cfg *acquireOptions,
 ) (Permit, AcquireFailReason, error) {
+	// 接管转移许可，不经过容量检查
+	if cfg.handoff != nil {
+		return s.doHandoff(ctx, resource, cfg)
+	}
+
 	// 兼容模式分流
 	if s.scriptMode == rediscompat.ScriptModeCompat {
 		return s.doAcquireCompat(ctx, resource, tenantID, cfg)
@@ -357,7 +388,13 @@ func (s *redisSemaphore) doAcquire(
 	globalKey := s.buildGlobalKey(resource)
 
 	// 动态构建 KEYS 数组，避免传递空字符串（Redis Cluster 兼容）
+	// 公平队列的队列键位于租户键之前，租户键始终是可选的最后一个键
 	keys := []string{globalKey}
+	script := s.scripts.acquire
+	if cfg.fairQueue {
+		keys = append(keys, s.buildQueueKey(resource), s.buildQueueAliveKey(resource))
+		script = s.scripts.fair
+	}
 	if hasTenantQuota {
 		keys = append(keys, s.buildTenantKey(resource, tenantID))
 	}
@@ -371,8 +408,12 @@ func (s *redisSemaphore) doAcquire(
 		cfg.tenantQuota,
 		keyTTLMargin.Milliseconds(),
 	}
+	if cfg.fairQueue {
+		args = append(args, cfg.waiterID, fairWaiterTTL.Milliseconds())
+	}
+	args = append(args, cfg.permitCount())
 
-	result, err := s.evalScriptInt64Slice(ctx, s.scripts.acquire, keys, args...)
+	result, err := s.evalScriptInt64Slice(ctx, script, keys, args...)
 	if err != nil {
 		return nil, ReasonUnknown, fmt.Errorf("acquire script failed: %w", err)
 	}
@@ -398,13 +439,23 @@ func (s *redisSemaphore) handleAcquireResult(
 
 	switch status {
 	case scriptStatusOK:
+		s.recordUtilization(ctx, resource, int(result[1]), cfg.capacity)
 		permit := newRedisPermit(s, permitID, resource, tenantID, expiresAt, cfg.ttl, hasTenantQuota, cfg.metadata)
+		permit.count = cfg.permitCount()
+		permit.capacity = cfg.capacity
+		s.held.track(&permit.permitBase, SemaphoreTypeDistributed)
 		return permit, ReasonUnknown, nil
 
 	case scriptStatusCapacityFull:
+		s.recordUtilization(ctx, resource, int(result[1]), cfg.capacity)
+		// fair_acquire.lua 额外返回排在前面的等待者数量
+		if len(result) > 3 {
+			cfg.reportQueuePosition(int(result[3]))
+		}
 		return nil, ReasonCapacityFull, nil
 
 	case scriptStatusTenantQuotaExceeded:
+		s.recordUtilization(ctx, resource, int(result[1]), cfg.capacity)
 		return nil, ReasonTenantQuotaExceeded, nil
 
 	default:
@@ -438,14 +489,14 @@ func (s *redisSemaphore) releasePermit(ctx context.Context, p *redisPermit) erro
 		keys = []string{globalKey}
 	}
 
-	args := []any{p.id}
+	args := []any{p.id, max(1, p.count), time.Now().UnixMilli()}
 
 	result, err := s.evalScriptInt64Slice(ctx, s.scripts.release, keys, args...)
 	if err != nil {
 		return fmt.Errorf("release script failed: %w", err)
 	}
 
-	// 验证结果长度：release 返回 {status, removed}
+	// 验证结果长度：release 返回 {status, removed[, globalCount]}
 	if err := validateScriptResult(result, 2); err != nil {
 		return fmt.Errorf("release script failed: %w", err)
 	}
@@ -457,6 +508,9 @@ func (s *redisSemaphore) releasePermit(ctx context.Context, p *redisPermit) erro
 		if s.opts.metrics != nil {
 			s.opts.metrics.RecordRelease(ctx, SemaphoreTypeDistributed, p.resource)
 		}
+		if len(result) > 2 {
+			s.recordUtilization(ctx, p.resource, int(result[2]), p.capacity)
+		}
 		return nil
 	case scriptStatusNotHeld:
 		return ErrPermitNotHeld
@@ -490,6 +544,7 @@ func (s *redisSemaphore) extendPermit(ctx context.Context, p *redisPermit, newEx
 		newExpiresAt.UnixMilli(),
 		p.id,
 		keyTTLMargin.Milliseconds(),
+		max(1, p.count),
 	}
 
 	result, err := s.evalScriptInt64Slice(ctx, s.scripts.extend, keys, args...)
@@ -586,6 +641,7 @@ func (s *redisSemaphore) Query(ctx context.Context, resource string, opts ...Que
 	if s.opts.metrics != nil {
 		s.opts.metrics.RecordQuery(ctx, SemaphoreTypeDistributed, resource, true, time.Since(start))
 	}
+	s.recordUtilization(ctx, resource, globalUsed, cfg.capacity)
 
 	return info, nil
 }
@@ -665,6 +721,16 @@ func (s *redisSemaphore) buildTenantKey(resource, tenantID string) string {
 	return s.opts.keyPrefix + "{" + resource + "}:t:" + tenantID
 }
 
+// buildQueueKey 构建公平队列的等待队列键（score 为入队时间）
+func (s *redisSemaphore) buildQueueKey(resource string) string {
+	return s.opts.keyPrefix + "{" + resource + "}:queue"
+}
+
+// buildQueueAliveKey 构建公平队列的等待者存活键（score 为存活截止时间）
+func (s *redisSemaphore) buildQueueAliveKey(resource string) string {
+	return s.opts.keyPrefix + "{" + resource + "}:queue:alive"
+}
+
 // =============================================================================
 // 编译时接口检查
 // =============================================================================
@@ -672,4 +738,5 @@ func (s *redisSemaphore) buildTenantKey(resource, tenantID string) string {
 var (
 	_ Semaphore       = (*redisSemaphore)(nil)
 	_ loggerForExtend = (*redisSemaphore)(nil)
+	_ fairQueueLeaver = (*redisSemaphore)(nil)
 )
@@ -0,0 +1,134 @@
+package xsemaphore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// 两阶段预留（Reserve / Confirm / Cancel）
+//
+// 预留基于许可转移实现：Reserve 以短 TTL 获取许可并立即 Handoff，返回的 token
+// 即预留凭证；Confirm 通过 WithHandoffToken 原子地把预留替换为完整 TTL 的许可；
+// Cancel 接管后立即释放。预留期间名额真实占用，其他获取者无法抢占；
+// 被遗弃的预留在短 TTL 到期后由后端按正常过期流程回收，无需额外清理。
+// =============================================================================
+
+// Reservation 一次预留（Reserve 返回）
+type Reservation struct {
+	// Token 预留凭证，传给 Confirm/Cancel，可跨进程传递
+	Token string
+
+	// Resource 资源名称
+	Resource string
+
+	// ExpiresAt 预留过期时间，此前未 Confirm 的预留自动失效
+	ExpiresAt time.Time
+}
+
+// reserve Reserve 的公共实现
+//
+// 设计决策: 复用 TryAcquire + Handoff，而不是新增后端脚本。
+// 预留因此与普通许可共用容量检查、租户配额、降级、指标和 trace，
+// 代价是 Reserve 需要两次后端往返（获取 + 续期交出），对预检场景可以接受。
+func reserve(ctx context.Context, resource string, opts []AcquireOption, tryAcquire tryAcquireFunc) (*Reservation, error) {
+	ttl := applyAcquireOptions(opts).reservationTTL
+	if ttl <= 0 || ttl > MaxReservationTTL {
+		return nil, fmt.Errorf("%w: reservation ttl must be in (0, %s], got %s", ErrInvalidTTL, MaxReservationTTL, ttl)
+	}
+
+	permit, err := tryAcquire(ctx, resource, append(slices.Clip(opts), WithTTL(ttl))...)
+	if err != nil || permit == nil {
+		return nil, err
+	}
+
+	token, err := permit.Handoff(ctx)
+	if err != nil {
+		// 交出失败时许可仍由本句柄持有，释放后返回错误；释放失败时许可在短 TTL 后自动回收
+		return nil, errors.Join(err, permit.Release(context.WithoutCancel(ctx)))
+	}
+	return &Reservation{
+		Token:     token,
+		Resource:  permit.Resource(),
+		ExpiresAt: permit.ExpiresAt(),
+	}, nil
+}
+
+// confirmReservation Confirm 的公共实现：以预留 token 接管为完整许可
+func confirmReservation(ctx context.Context, token string, opts []AcquireOption, tryAcquire tryAcquireFunc) (Permit, error) {
+	payload, err := decodeHandoffToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidReservation, err)
+	}
+	permit, err := tryAcquire(ctx, payload.Resource, append(slices.Clip(opts), WithHandoffToken(token))...)
+	if errors.Is(err, ErrHandoffExpired) {
+		return nil, fmt.Errorf("%w: %w", ErrReservationExpired, err)
+	}
+	return permit, err
+}
+
+// cancelReservation Cancel 的公共实现：接管预留后立即释放
+//
+// 预留已过期或已被确认时视为已取消，返回 nil，使 Cancel 可安全用于 defer。
+func cancelReservation(ctx context.Context, token string, tryAcquire tryAcquireFunc) error {
+	// 接管时使用预留级别的短 TTL，释放失败时许可也能很快自动回收
+	permit, err := confirmReservation(ctx, token, []AcquireOption{WithTTL(DefaultReservationTTL)}, tryAcquire)
+	if errors.Is(err, ErrReservationExpired) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if permit == nil {
+		return nil
+	}
+	return permit.Release(ctx)
+}
+
+// Reserve 以短 TTL 预留一个名额
+func (s *redisSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return reserve(ctx, resource, opts, s.TryAcquire)
+}
+
+// Confirm 将预留转为完整许可
+func (s *redisSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return confirmReservation(ctx, token, opts, s.TryAcquire)
+}
+
+// Cancel 立即释放预留
+func (s *redisSemaphore) Cancel(ctx context.Context, token string) error {
+	return cancelReservation(ctx, token, s.TryAcquire)
+}
+
+// Reserve 以短 TTL 预留一个本地名额
+func (s *localSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return reserve(ctx, resource, opts, s.TryAcquire)
+}
+
+// Confirm 将本地预留转为完整许可
+func (s *localSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return confirmReservation(ctx, token, opts, s.TryAcquire)
+}
+
+// Cancel 立即释放本地预留
+func (s *localSemaphore) Cancel(ctx context.Context, token string) error {
+	return cancelReservation(ctx, token, s.TryAcquire)
+}
+
+// Reserve 以短 TTL 预留一个名额，Redis 不可用时按降级策略处理
+func (f *fallbackSemaphore) Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error) {
+	return reserve(ctx, resource, opts, f.TryAcquire)
+}
+
+// Confirm 将预留转为完整许可，Redis 不可用时按降级策略处理
+func (f *fallbackSemaphore) Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error) {
+	return confirmReservation(ctx, token, opts, f.TryAcquire)
+}
+
+// Cancel 立即释放预留，Redis 不可用时按降级策略处理
+func (f *fallbackSemaphore) Cancel(ctx context.Context, token string) error {
+	return cancelReservation(ctx, token, f.TryAcquire)
+}
@@ -0,0 +1,134 @@
+package xsemaphore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/internal/rediscompat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// 两阶段预留测试
+// =============================================================================
+
+func TestReservation_Redis(t *testing.T) {
+	for _, mode := range []rediscompat.ScriptMode{rediscompat.ScriptModeLua, rediscompat.ScriptModeCompat} {
+		t.Run(mode.String(), func(t *testing.T) {
+			testReservation(t, func(t *testing.T) Semaphore {
+				sem, _ := setupSemaphore(t, WithScriptMode(mode))
+				return sem
+			})
+		})
+	}
+}
+
+func TestReservation_Local(t *testing.T) {
+	testReservation(t, func(t *testing.T) Semaphore {
+		sem := newLocalSemaphore(defaultOptions())
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		return sem
+	})
+}
+
+func testReservation(t *testing.T, newSem func(t *testing.T) Semaphore) {
+	ctx := context.Background()
+
+	t.Run("reserve holds capacity with short ttl", func(t *testing.T) {
+		sem := newSem(t)
+		r, err := sem.Reserve(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, r)
+		assert.Equal(t, "job", r.Resource)
+		assert.NotEmpty(t, r.Token)
+		assert.WithinDuration(t, time.Now().Add(DefaultReservationTTL), r.ExpiresAt, time.Second)
+
+		full, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		assert.Nil(t, full, "reservation occupies the slot")
+
+		none, err := sem.Reserve(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		assert.Nil(t, none, "capacity full returns nil reservation")
+
+		require.NoError(t, sem.Cancel(ctx, r.Token))
+	})
+
+	t.Run("confirm converts to full permit", func(t *testing.T) {
+		sem := newSem(t)
+		r, err := sem.Reserve(ctx, "job", WithCapacity(2), WithReservationTTL(2*time.Second))
+		require.NoError(t, err)
+		require.NotNil(t, r)
+
+		p, err := sem.Confirm(ctx, r.Token, WithCapacity(2), WithTTL(time.Minute), WithMetadata(map[string]string{"k": "v"}))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		assert.Equal(t, "job", p.Resource())
+		assert.Equal(t, "v", p.Metadata()["k"])
+		assert.WithinDuration(t, time.Now().Add(time.Minute), p.ExpiresAt(), time.Second)
+		assert.Equal(t, 1, globalUsed(t, sem, "job"), "confirm does not consume an extra slot")
+
+		_, err = sem.Confirm(ctx, r.Token)
+		require.ErrorIs(t, err, ErrReservationExpired, "token is single use")
+
+		require.NoError(t, sem.Cancel(ctx, r.Token), "cancel after confirm is a no-op")
+		assert.Equal(t, 1, globalUsed(t, sem, "job"))
+
+		releasePermit(t, ctx, p)
+		assert.Equal(t, 0, globalUsed(t, sem, "job"))
+	})
+
+	t.Run("cancel releases immediately", func(t *testing.T) {
+		sem := newSem(t)
+		r, err := sem.Reserve(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, r)
+
+		require.NoError(t, sem.Cancel(ctx, r.Token))
+		assert.Equal(t, 0, globalUsed(t, sem, "job"))
+		require.NoError(t, sem.Cancel(ctx, r.Token), "cancel is idempotent")
+
+		p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, p)
+		releasePermit(t, ctx, p)
+	})
+
+	t.Run("invalid reservation ttl", func(t *testing.T) {
+		sem := newSem(t)
+		for _, ttl := range []time.Duration{0, -time.Second, MaxReservationTTL + time.Second} {
+			r, err := sem.Reserve(ctx, "job", WithReservationTTL(ttl))
+			require.ErrorIs(t, err, ErrInvalidTTL)
+			assert.Nil(t, r)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		sem := newSem(t)
+		_, err := sem.Confirm(ctx, "garbage")
+		require.ErrorIs(t, err, ErrInvalidReservation)
+		require.ErrorIs(t, sem.Cancel(ctx, "garbage"), ErrInvalidReservation)
+	})
+}
+
+func TestReservation_ExpiresWhenAbandoned(t *testing.T) {
+	sem := newLocalSemaphore(defaultOptions())
+	t.Cleanup(func() { closeSemaphore(t, sem) })
+	ctx := context.Background()
+
+	r, err := sem.Reserve(ctx, "job", WithCapacity(1), WithReservationTTL(30*time.Millisecond))
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	time.Sleep(60 * time.Millisecond)
+
+	p, err := sem.TryAcquire(ctx, "job", WithCapacity(1))
+	require.NoError(t, err)
+	require.NotNil(t, p, "abandoned reservation self-heals")
+	releasePermit(t, ctx, p)
+
+	_, err = sem.Confirm(ctx, r.Token)
+	require.ErrorIs(t, err, ErrReservationExpired)
+}
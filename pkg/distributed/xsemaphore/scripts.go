@@ -41,6 +41,12 @@ var (
 
 	//go:embed lua/query.lua
 	queryLuaSource string
+
+	//go:embed lua/handoff.lua
+	handoffLuaSource string
+
+	//go:embed lua/fair_acquire.lua
+	fairAcquireLuaSource string
 )
 
 // =============================================================================
@@ -53,6 +59,8 @@ type scripts struct {
 	release *redis.Script
 	extend  *redis.Script
 	query   *redis.Script
+	handoff *redis.Script
+	fair    *redis.Script
 }
 
 var (
@@ -68,6 +76,8 @@ func getScripts() *scripts {
 			release: redis.NewScript(releaseLuaSource),
 			extend:  redis.NewScript(extendLuaSource),
 			query:   redis.NewScript(queryLuaSource),
+			handoff: redis.NewScript(handoffLuaSource),
+			fair:    redis.NewScript(fairAcquireLuaSource),
 		}
 	})
 	return globalScripts
@@ -105,7 +115,7 @@ func WarmupScripts(ctx context.Context, client redis.UniversalClient) error {
 
 	// 使用 SCRIPT LOAD 预加载脚本
 	// redis.Script.Load 会执行 SCRIPT LOAD 并缓存 SHA
-	// 设计决策: 顺序加载而非 Pipeline 批量加载。启动时一次性操作，额外 3 个 RTT（~3ms）
+	// 设计决策: 顺序加载而非 Pipeline 批量加载。启动时一次性操作，额外几个 RTT（~ms 级）
 	// 不影响服务启动时间，且顺序加载更易于定位失败的脚本。
 	if err := s.acquire.Load(ctx, client).Err(); err != nil {
 		return fmt.Errorf("load acquire script: %w", err)
@@ -119,6 +129,12 @@ func WarmupScripts(ctx context.Context, client redis.UniversalClient) error {
 	if err := s.query.Load(ctx, client).Err(); err != nil {
 		return fmt.Errorf("load query script: %w", err)
 	}
+	if err := s.handoff.Load(ctx, client).Err(); err != nil {
+		return fmt.Errorf("load handoff script: %w", err)
+	}
+	if err := s.fair.Load(ctx, client).Err(); err != nil {
+		return fmt.Errorf("load fair acquire script: %w", err)
+	}
 
 	return nil
 }
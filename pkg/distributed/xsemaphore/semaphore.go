@@ -54,6 +54,16 @@ type Permit interface {
 	// 其他错误表示续期操作失败（如网络错误），可以重试。
 	Extend(ctx context.Context) error
 
+	// ExtendUntil 将许可续期到指定的绝对截止时间。
+	//
+	// 适用于有明确截止时间的任务，避免多次相对续期累积的时间漂移。
+	// 遵循"只延长不缩短"：deadline 早于当前过期时间时保持当前过期时间，
+	// 但仍会访问后端确认许可仍被持有。
+	//
+	// deadline 不在当前时间之后时返回 [ErrInvalidDeadline]；
+	// 其余错误语义与 [Permit.Extend] 相同。
+	ExtendUntil(ctx context.Context, deadline time.Time) error
+
 	// StartAutoExtend 启动自动续租。
 	//
 	// 以指定间隔周期性调用 Extend，适用于运行时间不确定的长任务。
@@ -69,6 +79,20 @@ type Permit interface {
 	//	// 执行长时间任务...
 	StartAutoExtend(interval time.Duration) (stop func())
 
+	// Handoff 转移许可，返回可交给其他实例的 token。
+	//
+	// 用于任务在实例间迁移（如优雅关闭前转移工作）：接管方在 Acquire/TryAcquire 时
+	// 通过 [WithHandoffToken] 传入 token，后端原子地以新许可替换本许可，
+	// 避免"释放后重新获取"期间许可被其他获取者抢占。
+	//
+	// Handoff 先续期许可，为接管方预留完整的 TTL，然后停止自动续租并交出所有权：
+	// 此后本句柄的 Release 为空操作、Extend 返回 [ErrPermitNotHeld]。
+	// 续期失败时返回错误，许可仍由当前实例持有，可重试或正常 Release。
+	// 接管方未在 TTL 内完成接管时，许可按正常过期流程被回收。
+	//
+	// 已释放或已转移的许可返回 [ErrPermitNotHeld]。
+	Handoff(ctx context.Context) (token string, err error)
+
 	// ID 返回许可的唯一标识。
 	//
 	// 用于日志记录和调试。
@@ -133,6 +157,65 @@ type Semaphore interface {
 	//   - ErrAcquireFailed: 重试耗尽仍未获取到许可
 	Acquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error)
 
+	// AcquireN 非阻塞式地原子获取 n 个许可。
+	//
+	// 适用于一次需要多个并发名额的任务：n 个名额在同一次后端操作中全部获取或全部不获取，
+	// 避免逐个获取导致的部分持有和死锁。全局容量和租户配额（如设置）都必须能容纳全部 n 个名额，
+	// 否则返回 (nil, nil)，语义与 TryAcquire 相同。
+	//
+	// 返回的 Permit 代表全部 n 个名额：一次 Release 释放全部名额，Extend/Handoff 同时作用于全部名额。
+	// Query 按名额计数；Inspect 中每个名额单独列出，除第一个外 ID 为 "许可ID#序号"。
+	//
+	// 错误：
+	//   - ErrInvalidPermitCount: n <= 0，或 n 超过全局容量或租户配额（永远无法满足）
+	//   - 其余与 TryAcquire 相同
+	AcquireN(ctx context.Context, resource string, n int, opts ...AcquireOption) (Permit, error)
+
+	// WaitAcquire 有界等待式获取许可。
+	//
+	// 以退避间隔轮询，直到获取到许可或 ctx 到期。与 Acquire 不同，
+	// 等待时长只由 ctx 的 deadline 决定（未设置时使用 WithDefaultTimeout），
+	// 不受 WithMaxRetries 限制，轮询间隔通过 WithPollInterval/WithPollBackoff 配置。
+	//
+	// 返回：
+	//   - (permit, nil): 获取成功
+	//   - (nil, nil): ctx 到期前容量始终未释放（超时是正常结果，不是错误）
+	//   - (nil, err): 服务异常（如 Redis 不可用）或 ctx 被取消；
+	//     ctx 到期时最后一次尝试失败于可重试的 Redis 错误（如 TRYAGAIN），err 同时包装
+	//     context.DeadlineExceeded 与该 Redis 错误
+	//
+	// 注意：ctx 既无 deadline 又未配置 WithDefaultTimeout 时会一直等待到获取成功或 ctx 取消。
+	WaitAcquire(ctx context.Context, resource string, opts ...AcquireOption) (Permit, error)
+
+	// Reserve 以短 TTL 预留一个名额，用于"预留 → 预检 → 确认/回滚"的两阶段获取。
+	//
+	// 预留真实占用名额（容量检查、租户配额与 TryAcquire 相同），但只保留
+	// WithReservationTTL 指定的短时间（默认 DefaultReservationTTL），
+	// 被遗弃的预留到期后自动回收。容量已满时返回 (nil, nil)。
+	// 选项中的 WithTTL 被忽略，完整许可的 TTL 在 Confirm 时指定。
+	//
+	// 错误：
+	//   - ErrInvalidTTL: 预留 TTL 不在 (0, MaxReservationTTL] 范围内
+	//   - 其余与 TryAcquire 相同
+	Reserve(ctx context.Context, resource string, opts ...AcquireOption) (*Reservation, error)
+
+	// Confirm 将预留原子地转为完整许可。
+	//
+	// 新许可使用本次调用的 TTL 和元数据（如 WithTTL、WithMetadata），
+	// 资源和租户沿用预留时的值，不经过容量检查。token 只能使用一次。
+	//
+	// 错误：
+	//   - ErrInvalidReservation: token 格式错误
+	//   - ErrReservationExpired: 预留已过期、已确认或已取消
+	//   - 其余与 TryAcquire 相同
+	Confirm(ctx context.Context, token string, opts ...AcquireOption) (Permit, error)
+
+	// Cancel 立即释放预留，不必等待预留过期。
+	//
+	// 预留已过期、已确认或已取消时返回 nil，可安全用于 defer。
+	// token 格式错误时返回 ErrInvalidReservation。
+	Cancel(ctx context.Context, token string) error
+
 	// Query 查询资源的当前状态。
 	//
 	// 返回全局和租户级别的许可使用情况。
@@ -148,6 +231,36 @@ type Semaphore interface {
 	//   - err: 查询失败时的错误
 	Query(ctx context.Context, resource string, opts ...QueryOption) (*ResourceInfo, error)
 
+	// QueryMulti 批量查询多个资源的当前状态，用于监控面板等需要同时展示多个资源的场景。
+	//
+	// 分布式实现在单次 pipeline 中完成全部查询，减少往返次数；Cluster 模式下命令按 slot
+	// 自动分发到各节点。opts 作用于全部资源（容量、租户等），重复的资源只查询一次。
+	//
+	// 返回：
+	//   - results: 以资源名为键；单个资源失败（资源名非法、所在节点不可用）时记录在 QueryResult.Err，
+	//     不影响其他资源
+	//   - err: 与资源无关的错误（ctx 为 nil、信号量已关闭、选项或租户 ID 非法）
+	QueryMulti(ctx context.Context, resources []string, opts ...QueryOption) (map[string]QueryResult, error)
+
+	// Inspect 列出资源当前的有效许可，用于排查卡住的资源和未释放的许可。
+	//
+	// 与 Query 相同，Inspect 是只读操作，不消耗也不清理许可，已过期的许可不会出现在结果中。
+	// 分布式实现只使用只读命令（ZRANGEBYSCORE），开启从节点读取时可安全路由到副本。
+	// 结果按过期时间升序排列。
+	//
+	// 默认列出全局许可集合；通过 QueryWithTenantID 指定租户时只列出该租户的许可
+	// （租户 ID 不会从 context 自动提取）。分布式实现中全局集合不记录租户归属，
+	// 未指定租户时 PermitInfo.TenantID 为空；租户许可集合仅在获取时启用了租户配额才存在。
+	Inspect(ctx context.Context, resource string, opts ...QueryOption) ([]PermitInfo, error)
+
+	// Held 列出当前进程通过本信号量获取且尚未释放的许可，按获取时间升序排列。
+	//
+	// 与 Inspect 不同，Held 只读取本地记录，不访问后端，也不包含其他实例的许可，
+	// 用于调试工具排查"本进程占着哪些名额"。许可在后端过期但未被 Release 时仍会列出，
+	// 可通过 [HeldPermit.ExpiresAt] 判断。带降级的信号量同时列出分布式、本地降级
+	// 和 FallbackOpen 空操作许可，以 [HeldPermit.Type] 区分。
+	Held() []HeldPermit
+
 	// Close 关闭信号量，释放底层资源。
 	// 关闭后不应再创建新的许可。已获取的许可仍可正常 Release 和 Extend。
 	//
@@ -190,3 +303,15 @@ type ResourceInfo struct {
 	// TenantAvailable 租户可用许可数
 	TenantAvailable int
 }
+
+// PermitInfo 单个许可的信息（Inspect 返回）
+type PermitInfo struct {
+	// ID 许可 ID
+	ID string
+
+	// ExpiresAt 许可过期时间
+	ExpiresAt time.Time
+
+	// TenantID 租户 ID（未知或未使用租户时为空）
+	TenantID string
+}
@@ -235,6 +235,162 @@ func TestAcquire_Timeout(t *testing.T) {
 	releasePermit(t, context.Background(), permit1)
 }
 
+// countingBackoff 记录 NextDelay 调用次数的固定间隔退避
+type countingBackoff struct {
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (b *countingBackoff) NextDelay(_ int) time.Duration {
+	b.calls.Add(1)
+	return b.delay
+}
+
+func TestWaitAcquire(t *testing.T) {
+	t.Run("immediate success", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		ctx := context.Background()
+
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, permit)
+		releasePermit(t, ctx, permit)
+	})
+
+	t.Run("deadline returns nil permit without error", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		holder, err := sem.TryAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, context.Background(), holder)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1), WithPollInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		assert.Nil(t, permit)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	t.Run("acquires after release", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		holder, err := sem.TryAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+
+		time.AfterFunc(50*time.Millisecond, func() {
+			releasePermit(t, context.Background(), holder)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		backoff := &countingBackoff{delay: 10 * time.Millisecond}
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1), WithPollBackoff(backoff))
+		require.NoError(t, err)
+		require.NotNil(t, permit)
+		assert.Positive(t, backoff.calls.Load())
+		releasePermit(t, context.Background(), permit)
+	})
+
+	t.Run("cancel returns error", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		holder, err := sem.TryAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, context.Background(), holder)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(30*time.Millisecond, cancel)
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1), WithPollInterval(10*time.Millisecond))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, permit)
+	})
+
+	t.Run("redis failure returns error", func(t *testing.T) {
+		sem, mr := setupSemaphore(t)
+		mr.SetError("connection refused")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1))
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, permit)
+	})
+
+	t.Run("deadline after retryable redis errors returns last error", func(t *testing.T) {
+		// 关闭客户端内部重试：go-redis 会自行重试 TRYAGAIN，单次调用可能耗尽整个 deadline
+		mr := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+		t.Cleanup(func() { _ = client.Close() })
+		sem, err := New(client)
+		require.NoError(t, err)
+		t.Cleanup(func() { closeSemaphore(t, sem) })
+		mr.SetError("TRYAGAIN Multiple keys request during rehashing of slot")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1), WithPollInterval(10*time.Millisecond))
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		var redisErr redis.Error
+		require.ErrorAs(t, err, &redisErr, "wraps the last redis error")
+		assert.True(t, redis.IsTryAgainError(redisErr))
+		assert.Nil(t, permit)
+	})
+
+	t.Run("default timeout bounds the wait", func(t *testing.T) {
+		sem, _ := setupSemaphore(t, WithDefaultTimeout(80*time.Millisecond))
+		holder, err := sem.TryAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+		defer releasePermit(t, context.Background(), holder)
+
+		permit, err := sem.WaitAcquire(context.Background(), "wait", WithCapacity(1), WithPollInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		assert.Nil(t, permit)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		_, err := sem.WaitAcquire(context.Background(), "wait", WithCapacity(0))
+		assert.ErrorIs(t, err, ErrInvalidCapacity)
+	})
+
+	t.Run("nil context", func(t *testing.T) {
+		sem, _ := setupSemaphore(t)
+		_, err := sem.WaitAcquire(nil, "wait") //nolint:staticcheck // 测试 nil context
+		assert.ErrorIs(t, err, ErrNilContext)
+	})
+
+	t.Run("local", func(t *testing.T) {
+		sem := newLocalSemaphore(defaultOptions())
+		defer closeSemaphore(t, sem)
+		holder, err := sem.TryAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		permit, err := sem.WaitAcquire(ctx, "wait", WithCapacity(1), WithPollInterval(10*time.Millisecond))
+		assert.NoError(t, err)
+		assert.Nil(t, permit)
+
+		releasePermit(t, context.Background(), holder)
+		permit, err = sem.WaitAcquire(context.Background(), "wait", WithCapacity(1))
+		require.NoError(t, err)
+		require.NotNil(t, permit)
+		releasePermit(t, context.Background(), permit)
+	})
+}
+
+func TestWaitAcquire_FallbackLocal(t *testing.T) {
+	sem, mr := setupSemaphore(t, WithFallback(FallbackLocal))
+	mr.Close()
+
+	permit, err := sem.WaitAcquire(context.Background(), "wait", WithCapacity(1))
+	require.NoError(t, err)
+	require.NotNil(t, permit)
+	assert.IsType(t, &localPermit{}, permit)
+	releasePermit(t, context.Background(), permit)
+}
+
 // =============================================================================
 // Release 测试
 // =============================================================================
@@ -25,24 +25,29 @@ const (
 	spanNameRelease    = "xsemaphore.Release"
 	spanNameExtend     = "xsemaphore.Extend"
 	spanNameQuery      = "xsemaphore.Query"
+	spanNameHandoff    = "xsemaphore.Handoff"
+	spanNameInspect    = "xsemaphore.Inspect"
+	spanNameQueryMulti = "xsemaphore.QueryMulti"
 )
 
 // Span 属性名称（Metrics 也复用这些常量，确保 trace 与 metrics 键名一致）
 const (
-	attrSemType      = "xsemaphore.type"
-	attrResource     = "xsemaphore.resource"
-	attrTenantID     = "xsemaphore.tenant_id"
-	attrCapacity     = "xsemaphore.capacity"
-	attrTenantQuota  = "xsemaphore.tenant_quota"
-	attrAcquired     = "xsemaphore.acquired"
-	attrPermitID     = "xsemaphore.permit_id"
-	attrFailReason   = "xsemaphore.fail_reason"
-	attrGlobalUsed   = "xsemaphore.global_used"
-	attrTenantUsed   = "xsemaphore.tenant_used"
-	attrFallbackUsed = "xsemaphore.fallback_used"
-	attrRetryCount   = "xsemaphore.retry_count"
-	attrSuccess      = "xsemaphore.success"
-	attrStrategy     = "xsemaphore.strategy"
+	attrSemType       = "xsemaphore.type"
+	attrResource      = "xsemaphore.resource"
+	attrTenantID      = "xsemaphore.tenant_id"
+	attrCapacity      = "xsemaphore.capacity"
+	attrTenantQuota   = "xsemaphore.tenant_quota"
+	attrAcquired      = "xsemaphore.acquired"
+	attrPermitID      = "xsemaphore.permit_id"
+	attrFailReason    = "xsemaphore.fail_reason"
+	attrGlobalUsed    = "xsemaphore.global_used"
+	attrTenantUsed    = "xsemaphore.tenant_used"
+	attrFallbackUsed  = "xsemaphore.fallback_used"
+	attrRetryCount    = "xsemaphore.retry_count"
+	attrSuccess       = "xsemaphore.success"
+	attrStrategy      = "xsemaphore.strategy"
+	attrHandoffFrom   = "xsemaphore.handoff_from"
+	attrResourceCount = "xsemaphore.resource_count"
 )
 
 // =============================================================================
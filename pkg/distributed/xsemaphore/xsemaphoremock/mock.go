@@ -70,6 +70,35 @@ func (mr *MockPermitMockRecorder) Extend(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Extend", reflect.TypeOf((*MockPermit)(nil).Extend), ctx)
 }
 
+// ExtendUntil mocks base method.
+func (m *MockPermit) ExtendUntil(ctx context.Context, deadline time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendUntil", ctx, deadline)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExtendUntil indicates an expected call of ExtendUntil.
+func (mr *MockPermitMockRecorder) ExtendUntil(ctx, deadline any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendUntil", reflect.TypeOf((*MockPermit)(nil).ExtendUntil), ctx, deadline)
+}
+
+// Handoff mocks base method.
+func (m *MockPermit) Handoff(ctx context.Context) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Handoff", ctx)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Handoff indicates an expected call of Handoff.
+func (mr *MockPermitMockRecorder) Handoff(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handoff", reflect.TypeOf((*MockPermit)(nil).Handoff), ctx)
+}
+
 // ID mocks base method.
 func (m *MockPermit) ID() string {
 	m.ctrl.T.Helper()
@@ -198,6 +227,40 @@ func (mr *MockSemaphoreMockRecorder) Acquire(ctx, resource any, opts ...any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockSemaphore)(nil).Acquire), varargs...)
 }
 
+// AcquireN mocks base method.
+func (m *MockSemaphore) AcquireN(ctx context.Context, resource string, n int, opts ...xsemaphore.AcquireOption) (xsemaphore.Permit, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, resource, n}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AcquireN", varargs...)
+	ret0, _ := ret[0].(xsemaphore.Permit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireN indicates an expected call of AcquireN.
+func (mr *MockSemaphoreMockRecorder) AcquireN(ctx, resource, n any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, resource, n}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireN", reflect.TypeOf((*MockSemaphore)(nil).AcquireN), varargs...)
+}
+
+// Cancel mocks base method.
+func (m *MockSemaphore) Cancel(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockSemaphoreMockRecorder) Cancel(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockSemaphore)(nil).Cancel), ctx, token)
+}
+
 // Close mocks base method.
 func (m *MockSemaphore) Close(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -212,6 +275,26 @@ func (mr *MockSemaphoreMockRecorder) Close(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSemaphore)(nil).Close), ctx)
 }
 
+// Confirm mocks base method.
+func (m *MockSemaphore) Confirm(ctx context.Context, token string, opts ...xsemaphore.AcquireOption) (xsemaphore.Permit, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, token}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Confirm", varargs...)
+	ret0, _ := ret[0].(xsemaphore.Permit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockSemaphoreMockRecorder) Confirm(ctx, token any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, token}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockSemaphore)(nil).Confirm), varargs...)
+}
+
 // Health mocks base method.
 func (m *MockSemaphore) Health(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -226,6 +309,40 @@ func (mr *MockSemaphoreMockRecorder) Health(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Health", reflect.TypeOf((*MockSemaphore)(nil).Health), ctx)
 }
 
+// Held mocks base method.
+func (m *MockSemaphore) Held() []xsemaphore.HeldPermit {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Held")
+	ret0, _ := ret[0].([]xsemaphore.HeldPermit)
+	return ret0
+}
+
+// Held indicates an expected call of Held.
+func (mr *MockSemaphoreMockRecorder) Held() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Held", reflect.TypeOf((*MockSemaphore)(nil).Held))
+}
+
+// Inspect mocks base method.
+func (m *MockSemaphore) Inspect(ctx context.Context, resource string, opts ...xsemaphore.QueryOption) ([]xsemaphore.PermitInfo, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, resource}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Inspect", varargs...)
+	ret0, _ := ret[0].([]xsemaphore.PermitInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inspect indicates an expected call of Inspect.
+func (mr *MockSemaphoreMockRecorder) Inspect(ctx, resource any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, resource}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inspect", reflect.TypeOf((*MockSemaphore)(nil).Inspect), varargs...)
+}
+
 // Query mocks base method.
 func (m *MockSemaphore) Query(ctx context.Context, resource string, opts ...xsemaphore.QueryOption) (*xsemaphore.ResourceInfo, error) {
 	m.ctrl.T.Helper()
@@ -246,6 +363,46 @@ func (mr *MockSemaphoreMockRecorder) Query(ctx, resource any, opts ...any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockSemaphore)(nil).Query), varargs...)
 }
 
+// QueryMulti mocks base method.
+func (m *MockSemaphore) QueryMulti(ctx context.Context, resources []string, opts ...xsemaphore.QueryOption) (map[string]xsemaphore.QueryResult, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, resources}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "QueryMulti", varargs...)
+	ret0, _ := ret[0].(map[string]xsemaphore.QueryResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryMulti indicates an expected call of QueryMulti.
+func (mr *MockSemaphoreMockRecorder) QueryMulti(ctx, resources any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, resources}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryMulti", reflect.TypeOf((*MockSemaphore)(nil).QueryMulti), varargs...)
+}
+
+// Reserve mocks base method.
+func (m *MockSemaphore) Reserve(ctx context.Context, resource string, opts ...xsemaphore.AcquireOption) (*xsemaphore.Reservation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, resource}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Reserve", varargs...)
+	ret0, _ := ret[0].(*xsemaphore.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockSemaphoreMockRecorder) Reserve(ctx, resource any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, resource}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockSemaphore)(nil).Reserve), varargs...)
+}
+
 // TryAcquire mocks base method.
 func (m *MockSemaphore) TryAcquire(ctx context.Context, resource string, opts ...xsemaphore.AcquireOption) (xsemaphore.Permit, error) {
 	m.ctrl.T.Helper()
@@ -265,3 +422,23 @@ func (mr *MockSemaphoreMockRecorder) TryAcquire(ctx, resource any, opts ...any)
 	varargs := append([]any{ctx, resource}, opts...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TryAcquire", reflect.TypeOf((*MockSemaphore)(nil).TryAcquire), varargs...)
 }
+
+// WaitAcquire mocks base method.
+func (m *MockSemaphore) WaitAcquire(ctx context.Context, resource string, opts ...xsemaphore.AcquireOption) (xsemaphore.Permit, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, resource}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WaitAcquire", varargs...)
+	ret0, _ := ret[0].(xsemaphore.Permit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WaitAcquire indicates an expected call of WaitAcquire.
+func (mr *MockSemaphoreMockRecorder) WaitAcquire(ctx, resource any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, resource}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitAcquire", reflect.TypeOf((*MockSemaphore)(nil).WaitAcquire), varargs...)
+}
@@ -128,9 +128,22 @@
 //
 //  22. 并发启动与关闭：xrun 不提供阶段化启动、逆序关闭或依赖编排能力。
 //     所有服务通过 context 并发启动和同时取消。有序启动可通过嵌套 Group
-//     或在服务内部使用 ready channel 实现；健康检查建议在 HTTPServer 的
+//     或在服务内部使用 ready channel 实现；对外部依赖（DB、下游服务）的软依赖
+//     可使用 GoAfter 在依赖就绪后再启动（见设计决策 24）；健康检查建议在 HTTPServer 的
 //     handler 中实现，xrun 不内置此功能。这遵循 YAGNI 原则——编排策略
 //     因业务而异，过早抽象会增加不必要的复杂性。
 //
+//  23. panic 恢复为可选项：默认不恢复服务 panic，让进程崩溃以暴露未预期状态。
+//     WithRecover 将 panic 转为 *PanicError（含堆栈，记录 Error 日志），
+//     走正常的 context 取消流程，使其他服务完成优雅关闭；Wait() 返回该错误，
+//     调用方仍应以失败状态退出，而非继续运行。
+//
+//  24. 依赖等待：GoAfter 在启动 fn 前依次执行 DependencyCheck，失败时按
+//     WithDependencyInterval（默认 1s）重试，总等待时间受 WithStartupTimeout 限制。
+//     超时返回包装 ErrDependencyTimeout 与最后一次检查错误的错误，按普通服务错误
+//     触发 Group 取消（依赖长期不可用时应 fail-fast 交由编排系统重启）；
+//     等待期间 Group 被取消则直接返回 ctx.Err()，fn 不会执行。
+//     依赖检查仅作为启动门槛，不做运行期持续监测。
+//
 // [errgroup]: https://pkg.go.dev/golang.org/x/sync/errgroup
 package xrun
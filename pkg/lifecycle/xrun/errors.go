@@ -25,6 +25,44 @@ var ErrNilServer = errors.New("xrun: server must not be nil")
 // ErrNilService 表示 RunServices/RunServicesWithOptions 的 service 参数为 nil。
 var ErrNilService = errors.New("xrun: service must not be nil")
 
+// ErrDependencyTimeout 表示 GoAfter 的依赖在启动超时（WithStartupTimeout）内未就绪。
+var ErrDependencyTimeout = errors.New("xrun: dependency not ready before startup timeout")
+
+// ErrPanic 表示服务函数发生了 panic（仅在启用 WithRecover 时返回）。
+// 使用 errors.Is(err, ErrPanic) 判断是否为 panic 错误。
+var ErrPanic = errors.New("xrun: service panicked")
+
+// PanicError 包含服务 panic 的值与堆栈。
+//
+// 启用 WithRecover 后，服务 panic 会被转为此错误，
+// 使用 errors.As 获取详细信息：
+//
+//	var panicErr *xrun.PanicError
+//	if errors.As(err, &panicErr) {
+//	    fmt.Printf("service %s panicked: %v\n%s", panicErr.Service, panicErr.Value, panicErr.Stack)
+//	}
+type PanicError struct {
+	// Service 服务名称（通过 Go 启动时为空）。
+	Service string
+	// Value panic 传入的值。
+	Value any
+	// Stack panic 发生时的 goroutine 堆栈。
+	Stack []byte
+}
+
+// Error 实现 error 接口。
+func (e *PanicError) Error() string {
+	if e.Service == "" {
+		return fmt.Sprintf("xrun: service panicked: %v", e.Value)
+	}
+	return fmt.Sprintf("xrun: service %s panicked: %v", e.Service, e.Value)
+}
+
+// Unwrap 返回底层错误，使 errors.Is(err, ErrPanic) 正常工作。
+func (e *PanicError) Unwrap() error {
+	return ErrPanic
+}
+
 // SignalError 包含触发终止的具体信号信息。
 //
 // Run/RunServices/RunWithOptions 在收到系统信号时返回此错误。
@@ -3,10 +3,12 @@ package xrun
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -16,7 +18,7 @@ import (
 //
 // 当任一服务返回错误或 context 被取消时，所有服务都会收到取消信号。
 //
-// Go、GoWithName、Cancel 可安全地从多个 goroutine 并发调用。
+// Go、GoWithName、GoAfter、Cancel 可安全地从多个 goroutine 并发调用。
 // Wait 应仅调用一次。
 //
 // 使用方式：
@@ -96,12 +98,13 @@ func NewGroup(ctx context.Context, opts ...Option) (*Group, context.Context) {
 //	})
 //
 // 当 fn 返回非 nil 错误时，会触发所有其他 goroutine 的取消。
+// 启用 WithRecover 时，fn 的 panic 会被转为 *PanicError 返回。
 func (g *Group) Go(fn func(ctx context.Context) error) {
 	g.eg.Go(func() error {
 		if fn == nil {
 			return ErrNilFunc
 		}
-		return fn(g.ctx)
+		return g.call("", fn)
 	})
 }
 
@@ -116,7 +119,7 @@ func (g *Group) GoWithName(name string, fn func(ctx context.Context) error) {
 			slog.String("group", g.opts.name),
 			slog.String("service", name),
 		)
-		err := fn(g.ctx)
+		err := g.call(name, fn)
 		if err != nil && !errors.Is(err, context.Canceled) {
 			g.opts.logger.Warn("service exited with error",
 				slog.String("group", g.opts.name),
@@ -133,6 +136,104 @@ func (g *Group) GoWithName(name string, fn func(ctx context.Context) error) {
 	})
 }
 
+// DependencyCheck 检查服务的某个依赖是否就绪（如 DB 可连接），返回 nil 表示就绪。
+//
+// 检查应尊重 ctx 的取消与超时，避免单次检查阻塞超过启动超时。
+type DependencyCheck func(ctx context.Context) error
+
+// GoAfter 与 Go 相同，但在所有依赖就绪后才启动 fn。
+//
+// 依赖按顺序检查，检查失败时按 WithDependencyInterval（默认 1s）重试，
+// 已就绪的依赖不再重复检查。等待受 WithStartupTimeout 限制：
+// 超时返回包装 ErrDependencyTimeout 的错误并触发 Group 取消；
+// Group 在等待期间被取消时 fn 不会执行，返回 ctx.Err()。
+//
+// 用于处理服务间的软依赖启动顺序，避免启动初期因依赖未就绪产生的连接失败重试噪音：
+//
+//	g.GoAfter([]xrun.DependencyCheck{
+//	    func(ctx context.Context) error { return db.PingContext(ctx) },
+//	}, runConsumer)
+//
+// 设计决策: 依赖检查是启动前的一次性门槛，服务运行期间依赖再次失效由服务自行处理，
+// xrun 不做持续健康监测（与设计决策 22 的"不内置编排"一致，仅提供最小的启动等待）。
+func (g *Group) GoAfter(deps []DependencyCheck, fn func(ctx context.Context) error) {
+	g.eg.Go(func() error {
+		if fn == nil {
+			return ErrNilFunc
+		}
+		if err := g.waitDependencies(deps); err != nil {
+			return err
+		}
+		return g.call("", fn)
+	})
+}
+
+// waitDependencies 依次等待 deps 就绪，受启动超时与 Group 取消约束。
+func (g *Group) waitDependencies(deps []DependencyCheck) error {
+	ctx := g.ctx
+	if g.opts.startupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.opts.startupTimeout)
+		defer cancel()
+	}
+
+	for i, dep := range deps {
+		if dep == nil {
+			return ErrNilFunc
+		}
+		if err := g.waitDependency(ctx, i, dep); err != nil {
+			// Group 取消优先于超时，按普通取消处理
+			if groupErr := g.ctx.Err(); groupErr != nil {
+				return groupErr
+			}
+			return fmt.Errorf("%w: dependency %d: %w", ErrDependencyTimeout, i, err)
+		}
+	}
+	return nil
+}
+
+// waitDependency 重试单个依赖检查直到成功或 ctx 结束，返回最后一次检查的错误。
+func (g *Group) waitDependency(ctx context.Context, index int, dep DependencyCheck) error {
+	for {
+		err := dep(ctx)
+		if err == nil {
+			return nil
+		}
+		g.opts.logger.Debug("dependency not ready",
+			slog.String("group", g.opts.name),
+			slog.Int("dependency", index),
+			slog.Any("error", err),
+		)
+
+		timer := time.NewTimer(g.opts.dependencyInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// call 执行服务函数；启用 WithRecover 时将 panic 转为 *PanicError。
+func (g *Group) call(name string, fn func(ctx context.Context) error) (err error) {
+	if g.opts.recoverPanic {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				g.opts.logger.Error("service panicked",
+					slog.String("group", g.opts.name),
+					slog.String("service", name),
+					slog.Any("panic", r),
+					slog.String("stack", string(stack)),
+				)
+				err = &PanicError{Service: name, Value: r, Stack: stack}
+			}
+		}()
+	}
+	return fn(g.ctx)
+}
+
 // Wait 等待所有 goroutine 完成。
 //
 // 返回第一个非 nil 错误（如果有）。
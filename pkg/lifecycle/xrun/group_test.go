@@ -1388,3 +1388,182 @@ func TestWithoutSignalHandler_OverridesWithSignals(t *testing.T) {
 		t.Fatal("timeout")
 	}
 }
+
+func TestWithRecover_GoPanic(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), WithRecover())
+	var peerCanceled atomic.Bool
+
+	g.Go(func(ctx context.Context) error {
+		panic("boom")
+	})
+	g.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		peerCanceled.Store(true)
+		return nil
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, ErrPanic) {
+		t.Fatalf("expected ErrPanic, got %v", err)
+	}
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %T", err)
+	}
+	if panicErr.Value != "boom" {
+		t.Errorf("unexpected panic value: %v", panicErr.Value)
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Error("expected non-empty stack")
+	}
+	if !peerCanceled.Load() {
+		t.Error("peer service was not canceled")
+	}
+	if ctx.Err() == nil {
+		t.Error("group context should be canceled")
+	}
+}
+
+func TestWithRecover_GoWithNamePanic(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithRecover())
+	g.GoWithName("worker", func(ctx context.Context) error {
+		panic(errors.New("nil map"))
+	})
+
+	err := g.Wait()
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected *PanicError, got %v", err)
+	}
+	if panicErr.Service != "worker" {
+		t.Errorf("expected service name worker, got %q", panicErr.Service)
+	}
+	if got := panicErr.Error(); got != "xrun: service worker panicked: nil map" {
+		t.Errorf("unexpected error message: %s", got)
+	}
+}
+
+func TestWithRecover_NoPanic(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithRecover())
+	expectedErr := errors.New("service error")
+	g.Go(func(ctx context.Context) error {
+		return expectedErr
+	})
+
+	if err := g.Wait(); err != expectedErr {
+		t.Errorf("expected %v, got %v", expectedErr, err)
+	}
+}
+
+func TestPanicError_ErrorWithoutService(t *testing.T) {
+	err := &PanicError{Value: 42}
+	if got := err.Error(); got != "xrun: service panicked: 42" {
+		t.Errorf("unexpected error message: %s", got)
+	}
+}
+
+func TestGoAfter_WaitsForDependencies(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithDependencyInterval(5*time.Millisecond))
+
+	var checks atomic.Int32
+	var started atomic.Bool
+	g.GoAfter([]DependencyCheck{
+		func(ctx context.Context) error {
+			if checks.Add(1) < 3 {
+				return errors.New("db not ready")
+			}
+			return nil
+		},
+	}, func(ctx context.Context) error {
+		if checks.Load() < 3 {
+			t.Error("service started before dependency was ready")
+		}
+		started.Store(true)
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !started.Load() {
+		t.Error("service was not started")
+	}
+}
+
+func TestGoAfter_StartupTimeout(t *testing.T) {
+	g, ctx := NewGroup(context.Background(),
+		WithStartupTimeout(30*time.Millisecond),
+		WithDependencyInterval(5*time.Millisecond),
+	)
+
+	depErr := errors.New("db not ready")
+	var started atomic.Bool
+	g.GoAfter([]DependencyCheck{
+		func(ctx context.Context) error { return nil },
+		func(ctx context.Context) error { return depErr },
+	}, func(ctx context.Context) error {
+		started.Store(true)
+		return nil
+	})
+
+	err := g.Wait()
+	if !errors.Is(err, ErrDependencyTimeout) {
+		t.Fatalf("expected ErrDependencyTimeout, got %v", err)
+	}
+	if !errors.Is(err, depErr) {
+		t.Errorf("expected last check error to be wrapped, got %v", err)
+	}
+	if started.Load() {
+		t.Error("service should not start when dependency times out")
+	}
+	if ctx.Err() == nil {
+		t.Error("group context should be canceled")
+	}
+}
+
+func TestGoAfter_GroupCanceled(t *testing.T) {
+	g, _ := NewGroup(context.Background(), WithDependencyInterval(5*time.Millisecond))
+
+	var started atomic.Bool
+	g.GoAfter([]DependencyCheck{
+		func(ctx context.Context) error { return errors.New("never ready") },
+	}, func(ctx context.Context) error {
+		started.Store(true)
+		return nil
+	})
+
+	time.AfterFunc(20*time.Millisecond, func() { g.Cancel(nil) })
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expected nil error on plain cancel, got %v", err)
+	}
+	if started.Load() {
+		t.Error("service should not start after group cancel")
+	}
+}
+
+func TestGoAfter_NilArguments(t *testing.T) {
+	g, _ := NewGroup(context.Background())
+	g.GoAfter(nil, nil)
+	if err := g.Wait(); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("expected ErrNilFunc for nil fn, got %v", err)
+	}
+
+	g, _ = NewGroup(context.Background())
+	g.GoAfter([]DependencyCheck{nil}, func(ctx context.Context) error { return nil })
+	if err := g.Wait(); !errors.Is(err, ErrNilFunc) {
+		t.Errorf("expected ErrNilFunc for nil dependency, got %v", err)
+	}
+}
+
+func TestWithStartupTimeout_IgnoresNonPositive(t *testing.T) {
+	opts := defaultOptions()
+	WithStartupTimeout(0)(opts)
+	WithDependencyInterval(-time.Second)(opts)
+	if opts.startupTimeout != 0 {
+		t.Errorf("expected no startup timeout, got %v", opts.startupTimeout)
+	}
+	if opts.dependencyInterval != defaultDependencyInterval {
+		t.Errorf("expected default interval, got %v", opts.dependencyInterval)
+	}
+}
@@ -3,8 +3,12 @@ package xrun
 import (
 	"log/slog"
 	"os"
+	"time"
 )
 
+// defaultDependencyInterval GoAfter 依赖检查的默认重试间隔。
+const defaultDependencyInterval = time.Second
+
 // Option 配置 Group 的选项函数。
 type Option func(*groupOptions)
 
@@ -13,12 +17,18 @@ type groupOptions struct {
 	name            string
 	signals         []os.Signal
 	noSignalHandler bool
+	recoverPanic    bool
+
+	startupTimeout     time.Duration
+	dependencyInterval time.Duration
 }
 
 func defaultOptions() *groupOptions {
 	return &groupOptions{
 		logger: slog.Default(),
 		name:   "xrun",
+
+		dependencyInterval: defaultDependencyInterval,
 	}
 }
 
@@ -82,3 +92,47 @@ func WithoutSignalHandler() Option {
 		o.noSignalHandler = true
 	}
 }
+
+// WithRecover 启用服务 panic 恢复。
+//
+// 启用后，Go/GoWithName 启动的服务函数发生 panic 时不会导致进程崩溃，
+// 而是被转为 *PanicError 返回：记录 Error 级别日志（含堆栈），
+// 并像普通服务错误一样触发 Group 的 context 取消，其他服务随之优雅退出，
+// Wait() 返回该 *PanicError（可用 errors.Is(err, ErrPanic) 判断）。
+//
+// 权衡：panic 通常意味着程序处于未预期状态（如数据竞争、不变量被破坏），
+// 默认让进程崩溃可以暴露问题并由编排系统重启到干净状态。
+// WithRecover 适合希望"单服务 panic 仍能走完整优雅关闭流程"（刷盘、释放锁、
+// 注销服务）的场景；它不会让进程继续服务，Wait() 返回后仍应以非零状态退出。
+// 服务内部自行启动的 goroutine 不在恢复范围内。
+//
+// 默认不启用。
+func WithRecover() Option {
+	return func(o *groupOptions) {
+		o.recoverPanic = true
+	}
+}
+
+// WithStartupTimeout 设置 GoAfter 等待依赖就绪的最长时间。
+//
+// 超时后服务不会启动，返回包装 ErrDependencyTimeout 的错误（含最后一次检查失败原因），
+// 并像普通服务错误一样触发 Group 取消。默认 0 表示不限时，一直等待到依赖就绪或 Group 取消。
+// 非正值被忽略。
+func WithStartupTimeout(d time.Duration) Option {
+	return func(o *groupOptions) {
+		if d > 0 {
+			o.startupTimeout = d
+		}
+	}
+}
+
+// WithDependencyInterval 设置 GoAfter 依赖检查失败后的重试间隔。
+//
+// 默认 1 秒。非正值被忽略。
+func WithDependencyInterval(d time.Duration) Option {
+	return func(o *groupOptions) {
+		if d > 0 {
+			o.dependencyInterval = d
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+// =============================================================================
+// 批量消费
+// =============================================================================
+
+const (
+	// DefaultBatchSize 批量消费默认每批最大消息数。
+	DefaultBatchSize = 100
+
+	// DefaultBatchWindow 批量消费默认攒批窗口，从批次第一条消息读取时开始计时。
+	DefaultBatchWindow = time.Second
+)
+
+// BatchHandler 批量消息处理函数。
+// msgs 按读取顺序排列，同一分区内 offset 递增。
+//
+// 返回 nil 表示整批处理成功；返回 *BatchPartialError 表示前 Processed 条成功，
+// 其余消息在下次 Consume 时重试；返回其他错误表示整批失败、整批重试。
+type BatchHandler func(ctx context.Context, msgs []*kafka.Message) error
+
+// BatchPartialError 批次部分处理成功。
+//
+// handler 按顺序处理消息、中途失败时返回此错误（可被包装），BatchConsumer 只存储
+// 前 Processed 条消息的 offset，剩余消息保留到下次 Consume 重新交给 handler。
+type BatchPartialError struct {
+	// Processed 已成功处理的消息数（msgs 的前缀长度）。
+	Processed int
+	// Err 导致中断的错误。
+	Err error
+}
+
+// Error 实现 error 接口。
+func (e *BatchPartialError) Error() string {
+	return fmt.Sprintf("xkafka: batch partially processed (%d succeeded): %v", e.Processed, e.Err)
+}
+
+// Unwrap 返回底层错误。
+func (e *BatchPartialError) Unwrap() error {
+	return e.Err
+}
+
+// BatchConsumer 本地攒批的消费者。
+//
+// 按 [WithConsumerBatchSize] 与 [WithConsumerBatchWindow] 聚合消息，
+// 任一条件满足即把整批交给 [BatchHandler]，适合批量写下游（如 xclickhouse 的 BatchInsert）。
+//
+// 语义保证（at-least-once）：
+//   - 整批处理成功后按分区统一存储 offset，由 auto-commit 提交
+//   - 处理失败的消息（整批或 [BatchPartialError] 之后的部分）不存储 offset，
+//     保留在本地并在下次 Consume 时原样重试，不会被后续消息的 offset 越过
+//   - 分区撤销或重新分配时丢弃本地缓冲中属于这些分区的消息，由新持有者从已提交 offset 重新消费
+//
+// 设计决策: 嵌入 *TracingConsumer 复用统计、DecodeValue 与 Close，但以 BatchHandler
+// 版本的 Consume/ConsumeLoop/ConsumeLoopWithPolicy 覆盖原方法。原方法逐条 StoreMessage，
+// 与本地缓冲混用会让 offset 越过尚未处理的消息。
+// 批次 span 使用调用方 ctx，不提取单条消息的追踪信息：一批消息通常来自不同的上游链路。
+//
+// 持续失败的消息会被无限重试，handler 应自行把无法处理的消息投递到死信队列并计为成功。
+// Consume 须在单个 goroutine 中调用（如 ConsumeLoop）。
+type BatchConsumer struct {
+	*TracingConsumer
+
+	// mu 保护 buf 和 retry。
+	// rebalance 回调在 ReadMessage 内同步触发，与攒批共用缓冲。
+	mu  sync.Mutex
+	buf []*kafka.Message
+	// retry 为 true 表示 buf 是上次处理失败的剩余消息，下次 Consume 不再读取新消息直接重试
+	retry bool
+}
+
+// NewBatchConsumer 创建本地攒批的消费者。
+// opts 中的 WithConsumerBatch* 选项控制批次大小和攒批窗口。
+func NewBatchConsumer(config *kafka.ConfigMap, topics []string, opts ...ConsumerOption) (*BatchConsumer, error) {
+	c := &BatchConsumer{}
+	wrapper, err := subscribeConsumerWrapper(config, topics, c.rebalance, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.TracingConsumer = &TracingConsumer{consumerWrapper: wrapper}
+	return c, nil
+}
+
+// Consume 攒满一批消息（或窗口到期）后调用 handler，处理成功的消息存储 offset。
+//
+// 没有消息时阻塞直到读到第一条消息或 ctx 取消。ctx 取消或读取出错时已读取的消息保留在缓冲中，
+// 下次 Consume 继续攒批。
+func (c *BatchConsumer) Consume(ctx context.Context, handler BatchHandler) (err error) {
+	if handler == nil {
+		return ErrNilHandler
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+	if c.closed.Load() {
+		return ErrClosed
+	}
+
+	batch, err := c.nextBatch(ctx)
+	if err != nil || len(batch) == 0 {
+		return err
+	}
+
+	ctx, span := xmetrics.Start(ctx, c.options.Observer, xmetrics.SpanOptions{
+		Component: componentName,
+		Operation: "consume_batch",
+		Kind:      xmetrics.KindConsumer,
+		Attrs:     batchAttrs(batch, c.groupID),
+	})
+	defer func() {
+		span.End(xmetrics.Result{Err: err})
+	}()
+
+	handlerErr := handler(ctx, batch)
+	processed := processedCount(handlerErr, len(batch))
+
+	c.mu.Lock()
+	c.buf = batch[processed:]
+	c.retry = len(c.buf) > 0
+	c.mu.Unlock()
+
+	if storeErr := c.storeOffsets(batch[:processed]); storeErr != nil {
+		return errors.Join(handlerErr, storeErr)
+	}
+	return handlerErr
+}
+
+// ConsumeLoop 循环批量消费直到 ctx 取消。
+func (c *BatchConsumer) ConsumeLoop(ctx context.Context, handler BatchHandler) error {
+	return c.ConsumeLoopWithPolicy(ctx, handler, nil)
+}
+
+// ConsumeLoopWithPolicy 启动带退避策略的批量消费循环，backoff 为 nil 时使用默认退避。
+// handler 失败后按退避延迟重试失败的消息。
+func (c *BatchConsumer) ConsumeLoopWithPolicy(ctx context.Context, handler BatchHandler, backoff BackoffPolicy) error {
+	if handler == nil {
+		return ErrNilHandler
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	consume := func(ctx context.Context) error {
+		return c.Consume(ctx, handler)
+	}
+	return runConsumeLoop(ctx, consume, &c.errorsCount, backoff)
+}
+
+// Buffered 返回本地缓冲中尚未处理成功的消息数。
+func (c *BatchConsumer) Buffered() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.buf)
+}
+
+// nextBatch 返回待处理的批次：上次失败的剩余消息直接重试，否则继续攒批。
+func (c *BatchConsumer) nextBatch(ctx context.Context) ([]*kafka.Message, error) {
+	c.mu.Lock()
+	retry := c.retry && len(c.buf) > 0
+	c.retry = false
+	c.mu.Unlock()
+
+	if !retry {
+		if err := c.fill(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.buf, nil
+}
+
+// fill 读取消息直到缓冲达到 BatchSize 或自首条消息起超过 BatchWindow。
+func (c *BatchConsumer) fill(ctx context.Context) error {
+	var deadline time.Time
+	for {
+		c.mu.Lock()
+		n := len(c.buf)
+		c.mu.Unlock()
+		if n >= c.options.BatchSize {
+			return nil
+		}
+
+		timeout := c.options.PollTimeout
+		if n > 0 {
+			// 首条消息可能在上次 Consume 中读取（ctx 取消后保留），窗口从本次开始计时
+			if deadline.IsZero() {
+				deadline = time.Now().Add(c.options.BatchWindow)
+			}
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return nil
+			}
+			timeout = min(timeout, remaining)
+		}
+
+		if c.closed.Load() {
+			return ErrClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		msg, err := c.client.ReadMessage(timeout)
+		if err != nil {
+			var kafkaErr kafka.Error
+			if errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrTimedOut {
+				continue
+			}
+			return err
+		}
+
+		c.messagesConsumed.Add(1)
+		c.bytesConsumed.Add(int64(len(msg.Value)))
+		c.mu.Lock()
+		c.buf = append(c.buf, msg)
+		c.mu.Unlock()
+	}
+}
+
+// storeOffsets 按分区存储已处理消息中最大 offset 的下一位置。
+func (c *BatchConsumer) storeOffsets(msgs []*kafka.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	next := make(map[partitionKey]kafka.Offset)
+	for _, msg := range msgs {
+		key := partitionKey{topic: topicName(msg), partition: msg.TopicPartition.Partition}
+		if off := msg.TopicPartition.Offset + 1; off > next[key] {
+			next[key] = off
+		}
+	}
+
+	offsets := make([]kafka.TopicPartition, 0, len(next))
+	for key, off := range next {
+		topic := key.topic
+		offsets = append(offsets, kafka.TopicPartition{Topic: &topic, Partition: key.partition, Offset: off})
+	}
+	if _, err := c.client.StoreOffsets(offsets); err != nil {
+		return fmt.Errorf("store offset failed: %w", err)
+	}
+	return nil
+}
+
+// rebalance 注册到 SubscribeTopics 的回调，在 ReadMessage/Close 内同步执行。
+func (c *BatchConsumer) rebalance(_ *kafka.Consumer, ev kafka.Event) error {
+	c.onRebalance(ev)
+	return nil
+}
+
+// onRebalance 丢弃缓冲中属于被分配或撤销分区的消息。
+//
+// 撤销的分区可能已被其他成员持有，继续处理会与新持有者重复且 offset 无法存储；
+// 新分配的分区会从已提交 offset 重新读取，缓冲中的旧消息同样多余。
+func (c *BatchConsumer) onRebalance(ev kafka.Event) {
+	var partitions []kafka.TopicPartition
+	switch e := ev.(type) {
+	case kafka.AssignedPartitions:
+		partitions = e.Partitions
+	case kafka.RevokedPartitions:
+		partitions = e.Partitions
+	default:
+		return
+	}
+
+	drop := make(map[partitionKey]struct{}, len(partitions))
+	for _, tp := range partitions {
+		drop[partitionKey{topic: derefTopic(tp.Topic), partition: tp.Partition}] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	kept := c.buf[:0:0]
+	for _, msg := range c.buf {
+		if _, ok := drop[partitionKey{topic: topicName(msg), partition: msg.TopicPartition.Partition}]; !ok {
+			kept = append(kept, msg)
+		}
+	}
+	c.buf = kept
+}
+
+// processedCount 根据 handler 返回的错误计算成功处理的消息数。
+func processedCount(err error, total int) int {
+	if err == nil {
+		return total
+	}
+	var partial *BatchPartialError
+	if errors.As(err, &partial) {
+		return min(max(partial.Processed, 0), total)
+	}
+	return 0
+}
+
+// batchAttrs 返回批次 span 的属性列表。
+func batchAttrs(batch []*kafka.Message, consumerGroup string) []xmetrics.Attr {
+	attrs := kafkaAttrs(topicName(batch[0]))
+	attrs = append(attrs, xmetrics.Int("messaging.batch.message_count", len(batch)))
+	if consumerGroup != "" {
+		attrs = append(attrs, xmetrics.String("messaging.kafka.consumer.group", consumerGroup))
+	}
+	return attrs
+}
@@ -0,0 +1,329 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var errBatch = errors.New("batch insert failed")
+
+func newTestBatchConsumer(ctrl *gomock.Controller) (*BatchConsumer, *MockkafkaConsumerClient) {
+	w, mock := newTestConsumerWrapper(ctrl)
+	return &BatchConsumer{TracingConsumer: &TracingConsumer{consumerWrapper: w}}, mock
+}
+
+// offsetsOf 将 StoreOffsets 参数转换为 "topic[partition]" -> offset 映射，忽略顺序。
+func offsetsOf(tps []kafka.TopicPartition) map[string]kafka.Offset {
+	m := make(map[string]kafka.Offset, len(tps))
+	for _, tp := range tps {
+		m[fmt.Sprintf("%s[%d]", *tp.Topic, tp.Partition)] = tp.Offset
+	}
+	return m
+}
+
+// valuesOf 返回批次中的消息体。
+func valuesOf(msgs []*kafka.Message) []string {
+	values := make([]string, len(msgs))
+	for i, msg := range msgs {
+		values[i] = string(msg.Value)
+	}
+	return values
+}
+
+func TestNewBatchConsumer_Validation(t *testing.T) {
+	_, err := NewBatchConsumer(nil, []string{"t"})
+	assert.ErrorIs(t, err, ErrNilConfig)
+
+	_, err = NewBatchConsumer(&kafka.ConfigMap{}, nil)
+	assert.ErrorIs(t, err, ErrEmptyTopics)
+}
+
+func TestBatchOptions(t *testing.T) {
+	o := defaultConsumerOptions()
+	assert.Equal(t, DefaultBatchSize, o.BatchSize)
+	assert.Equal(t, DefaultBatchWindow, o.BatchWindow)
+
+	WithConsumerBatchSize(0)(o)
+	WithConsumerBatchWindow(-time.Second)(o)
+	assert.Equal(t, DefaultBatchSize, o.BatchSize)
+	assert.Equal(t, DefaultBatchWindow, o.BatchWindow)
+
+	WithConsumerBatchSize(500)(o)
+	WithConsumerBatchWindow(200 * time.Millisecond)(o)
+	assert.Equal(t, 500, o.BatchSize)
+	assert.Equal(t, 200*time.Millisecond, o.BatchWindow)
+}
+
+func TestBatchPartialError(t *testing.T) {
+	err := fmt.Errorf("insert: %w", &BatchPartialError{Processed: 2, Err: errBatch})
+	assert.ErrorIs(t, err, errBatch)
+	assert.Contains(t, err.Error(), "2 succeeded")
+
+	assert.Equal(t, 3, processedCount(nil, 3))
+	assert.Equal(t, 0, processedCount(errBatch, 3))
+	assert.Equal(t, 2, processedCount(err, 3))
+	assert.Equal(t, 0, processedCount(&BatchPartialError{Processed: -1}, 3))
+	assert.Equal(t, 3, processedCount(&BatchPartialError{Processed: 9}, 3))
+}
+
+func TestBatchConsumer_FullBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(3)(c.options)
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 10, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 1, 7, "b"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 11, "c"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		assert.Equal(t, map[string]kafka.Offset{"orders[0]": 12, "orders[1]": 8}, offsetsOf(tps))
+		return tps, nil
+	})
+
+	var got []string
+	require.NoError(t, c.Consume(context.Background(), func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.Equal(t, []string{"a", "b", "c"}, got)
+	assert.Zero(t, c.Buffered())
+	assert.Equal(t, int64(3), c.messagesConsumed.Load())
+}
+
+func TestBatchConsumer_WindowFlush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchWindow(30 * time.Millisecond)(c.options)
+	timedOut := kafka.NewError(kafka.ErrTimedOut, "timed out", false)
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	// 首条消息后按剩余窗口轮询，窗口到期即交给 handler
+	mock.EXPECT().ReadMessage(gomock.Any()).DoAndReturn(func(timeout time.Duration) (*kafka.Message, error) {
+		assert.LessOrEqual(t, timeout, 30*time.Millisecond)
+		time.Sleep(timeout)
+		return nil, timedOut
+	}).MinTimes(1)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+
+	var got []string
+	start := time.Now()
+	require.NoError(t, c.Consume(context.Background(), func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+	assert.Equal(t, []string{"a"}, got)
+}
+
+func TestBatchConsumer_FailureRetriesBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(2)(c.options)
+	ctx := context.Background()
+
+	// 只读取一次：失败的批次保留在本地重试，不存储 offset
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 2, "b"), nil)
+
+	err := c.Consume(ctx, func(context.Context, []*kafka.Message) error { return errBatch })
+	require.ErrorIs(t, err, errBatch)
+	assert.Equal(t, 2, c.Buffered())
+
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		assert.Equal(t, map[string]kafka.Offset{"orders[0]": 3}, offsetsOf(tps))
+		return tps, nil
+	})
+	var got []string
+	require.NoError(t, c.Consume(ctx, func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.Equal(t, []string{"a", "b"}, got)
+	assert.Zero(t, c.Buffered())
+}
+
+func TestBatchConsumer_PartialFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(3)(c.options)
+	ctx := context.Background()
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 2, "b"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 3, "c"), nil)
+	// 仅存储成功前缀的 offset
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		assert.Equal(t, map[string]kafka.Offset{"orders[0]": 2}, offsetsOf(tps))
+		return tps, nil
+	})
+
+	err := c.Consume(ctx, func(context.Context, []*kafka.Message) error {
+		return fmt.Errorf("insert: %w", &BatchPartialError{Processed: 1, Err: errBatch})
+	})
+	require.ErrorIs(t, err, errBatch)
+	assert.Equal(t, 2, c.Buffered())
+
+	// 剩余消息直接重试，不读取新消息
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		assert.Equal(t, map[string]kafka.Offset{"orders[0]": 4}, offsetsOf(tps))
+		return tps, nil
+	})
+	var got []string
+	require.NoError(t, c.Consume(ctx, func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.Equal(t, []string{"b", "c"}, got)
+}
+
+func TestBatchConsumer_StoreOffsetsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(1)(c.options)
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, errStore)
+
+	err := c.Consume(context.Background(), func(context.Context, []*kafka.Message) error { return nil })
+	require.ErrorIs(t, err, errStore)
+	// 已处理成功的消息不重试，offset 由后续批次的存储覆盖
+	assert.Zero(t, c.Buffered())
+}
+
+func TestBatchConsumer_ContextCanceledKeepsBuffer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(2)(c.options)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mock.EXPECT().ReadMessage(gomock.Any()).DoAndReturn(func(time.Duration) (*kafka.Message, error) {
+		cancel()
+		return testMessage("orders", 0, 1, "a"), nil
+	})
+
+	called := false
+	err := c.Consume(ctx, func(context.Context, []*kafka.Message) error {
+		called = true
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, called)
+	assert.Equal(t, 1, c.Buffered())
+
+	// 下次 Consume 继续攒批
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 2, "b"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+	var got []string
+	require.NoError(t, c.Consume(context.Background(), func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.Equal(t, []string{"a", "b"}, got)
+}
+
+func TestBatchConsumer_ReadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	readErr := kafka.NewError(kafka.ErrTransport, "broker down", false)
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(nil, readErr)
+
+	err := c.Consume(context.Background(), func(context.Context, []*kafka.Message) error { return nil })
+	assert.ErrorIs(t, err, readErr)
+	assert.Equal(t, 1, c.Buffered())
+}
+
+func TestBatchConsumer_RebalanceDropsBuffered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(3)(c.options)
+	topic := "orders"
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 1, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 1, 1, "b"), nil)
+	// 读取期间分区 0 被撤销，缓冲中的分区 0 消息被丢弃
+	mock.EXPECT().ReadMessage(gomock.Any()).DoAndReturn(func(time.Duration) (*kafka.Message, error) {
+		c.onRebalance(kafka.RevokedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 0}}})
+		return testMessage(topic, 1, 2, "c"), nil
+	})
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 1, 3, "d"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		assert.Equal(t, map[string]kafka.Offset{"orders[1]": 4}, offsetsOf(tps))
+		return tps, nil
+	})
+
+	var got []string
+	require.NoError(t, c.Consume(context.Background(), func(_ context.Context, msgs []*kafka.Message) error {
+		got = valuesOf(msgs)
+		return nil
+	}))
+	assert.Equal(t, []string{"b", "c", "d"}, got)
+
+	c.onRebalance(kafka.PartitionEOF{})
+	assert.Zero(t, c.Buffered())
+}
+
+func TestBatchConsumer_RebalanceDropsRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(1)(c.options)
+	topic := "orders"
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 1, "a"), nil)
+	require.ErrorIs(t, c.Consume(context.Background(), func(context.Context, []*kafka.Message) error { return errBatch }), errBatch)
+
+	// 失败的消息所在分区被重新分配后不再重试，改为读取新消息
+	c.onRebalance(kafka.AssignedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 0}}})
+	assert.Zero(t, c.Buffered())
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 1, "a"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+	require.NoError(t, c.Consume(context.Background(), func(context.Context, []*kafka.Message) error { return nil }))
+}
+
+func TestBatchConsumer_Guards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+
+	assert.ErrorIs(t, c.Consume(context.Background(), nil), ErrNilHandler)
+	assert.ErrorIs(t, c.ConsumeLoop(context.Background(), nil), ErrNilHandler)
+
+	mock.EXPECT().Commit().Return(nil, nil)
+	mock.EXPECT().Close().Return(nil)
+	require.NoError(t, c.Close())
+	assert.ErrorIs(t, c.Consume(context.Background(), func(context.Context, []*kafka.Message) error { return nil }), ErrClosed)
+}
+
+func TestBatchConsumer_ConsumeLoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestBatchConsumer(ctrl)
+	WithConsumerBatchSize(2)(c.options)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 2, "b"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+
+	calls := 0
+	err := c.ConsumeLoop(ctx, func(_ context.Context, msgs []*kafka.Message) error {
+		calls++
+		if calls == 1 {
+			return errBatch
+		}
+		assert.Equal(t, []string{"a", "b"}, valuesOf(msgs))
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, int64(1), c.errorsCount.Load())
+}
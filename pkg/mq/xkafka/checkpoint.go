@@ -0,0 +1,442 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+// =============================================================================
+// 消费进度检查点
+// =============================================================================
+
+const (
+	// DefaultCheckpointInterval 检查点默认保存间隔，与 librdkafka auto.commit.interval.ms 默认值一致。
+	DefaultCheckpointInterval = 5 * time.Second
+
+	// DefaultCheckpointTimeout rebalance/Close 时保存检查点的默认超时时间。
+	DefaultCheckpointTimeout = 5 * time.Second
+)
+
+// Checkpoint 分区处理进度检查点。
+type Checkpoint struct {
+	// Topic 主题名称。
+	Topic string `json:"topic"`
+	// Partition 分区号。
+	Partition int32 `json:"partition"`
+	// Offset 下一条待处理消息的 offset，State 已包含 Offset 之前全部消息的处理结果。
+	// 与 Kafka 提交语义一致（提交的是"下次从哪里开始"）。
+	Offset kafka.Offset `json:"offset"`
+	// State 业务处理状态，由 StatefulHandler 产生，本包不解释其内容。
+	State []byte `json:"state"`
+	// UpdatedAt 检查点保存时间。
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CheckpointStore 检查点存储。
+//
+// 实现必须并发安全，Save 返回 nil 即表示检查点已持久化（CheckpointConsumer
+// 随后才会存储 offset）。Save 返回后 State 可能被 handler 复用，
+// 需要保留时应自行复制。
+type CheckpointStore interface {
+	// Load 读取分区检查点，不存在时返回 (nil, nil)。
+	Load(ctx context.Context, group, topic string, partition int32) (*Checkpoint, error)
+	// Save 保存分区检查点，覆盖该分区的旧检查点。
+	Save(ctx context.Context, group string, cp Checkpoint) error
+}
+
+// StatefulHandler 有状态消息处理函数。
+// state 为该分区当前的处理状态（首次处理且无检查点时为 nil），返回值为处理该消息后的新状态。
+// 返回错误时状态保持不变。
+type StatefulHandler func(ctx context.Context, msg *kafka.Message, state []byte) ([]byte, error)
+
+// CheckpointStats 检查点统计信息。
+type CheckpointStats struct {
+	// Saved 成功保存的检查点数量。
+	Saved int64
+	// Failed 保存失败的次数。
+	Failed int64
+	// Skipped 因 offset 落后于检查点而跳过的消息数量（已包含在检查点状态中）。
+	Skipped int64
+}
+
+// CheckpointConsumer 带处理进度检查点的消费者。
+//
+// 为有状态处理（窗口聚合、去重集合等）提供与 offset 关联的检查点：
+// 每个分区维护一份业务状态，按 [WithConsumerCheckpointInterval] /
+// [WithConsumerCheckpointEvery] 周期性保存到 [CheckpointStore]，
+// 重启或 rebalance 后从检查点恢复状态。
+//
+// 一致性保证：
+//   - 先保存检查点再存储 offset，已提交的 offset 永远不超过检查点 offset；
+//     检查点保存失败时 offset 不前移
+//   - 恢复时 offset 小于检查点 offset 的消息已包含在状态中，直接跳过不交给 handler，
+//     因此状态恰好包含每条消息一次；handler 中的外部副作用仍为 at-least-once
+//   - 分区撤销时保存该分区检查点，分区丢失（AssignmentLost）时直接丢弃内存状态，
+//     避免覆盖新持有者的检查点
+//
+// 设计决策: 嵌入 *TracingConsumer 复用追踪、统计与 DecodeValue，但以 StatefulHandler
+// 版本的 Consume/ConsumeLoop/ConsumeLoopWithPolicy 覆盖原方法。原方法逐条 StoreMessage，
+// 会让 offset 越过尚未保存的检查点，破坏上述不变式。
+//
+// Consume 须在单个 goroutine 中调用（如 ConsumeLoop），同一分区的状态按 offset 顺序演进。
+// 空闲分区的未保存进度在分区撤销、[CheckpointConsumer.Checkpoint] 或 Close 时保存。
+type CheckpointConsumer struct {
+	*TracingConsumer
+	store CheckpointStore
+
+	// mu 保护 partitions、pending、rebalanceErr。
+	// rebalance 回调在 ReadMessage 内同步触发，Checkpoint 可能来自其他 goroutine。
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionState
+	// pending 读取后因检查点加载失败未处理的消息，下次 Consume 优先重试
+	pending *kafka.Message
+	// rebalanceErr rebalance 回调中保存检查点的错误，由下次 Consume 返回
+	rebalanceErr error
+
+	saved   atomic.Int64
+	failed  atomic.Int64
+	skipped atomic.Int64
+}
+
+// partitionKey 分区标识。
+type partitionKey struct {
+	topic     string
+	partition int32
+}
+
+// partitionState 分区的内存处理状态。
+type partitionState struct {
+	state    []byte
+	next     kafka.Offset // 下一条待处理消息的 offset
+	pending  int          // 自上次检查点以来处理的消息数
+	lastSave time.Time
+}
+
+// NewCheckpointConsumer 创建带处理进度检查点的消费者。
+// store 不能为 nil；opts 中的 WithConsumerCheckpoint* 选项控制检查点保存频率。
+func NewCheckpointConsumer(config *kafka.ConfigMap, topics []string, store CheckpointStore,
+	opts ...ConsumerOption) (*CheckpointConsumer, error) {
+	if store == nil {
+		return nil, ErrCheckpointStoreRequired
+	}
+	c := newCheckpointConsumer(nil, store)
+	wrapper, err := subscribeConsumerWrapper(config, topics, c.rebalance, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.TracingConsumer = &TracingConsumer{consumerWrapper: wrapper}
+	return c, nil
+}
+
+func newCheckpointConsumer(tc *TracingConsumer, store CheckpointStore) *CheckpointConsumer {
+	return &CheckpointConsumer{
+		TracingConsumer: tc,
+		store:           store,
+		partitions:      make(map[partitionKey]*partitionState),
+	}
+}
+
+// Consume 消费一条消息，以分区当前状态调用 handler 并在到期时保存检查点。
+func (c *CheckpointConsumer) Consume(ctx context.Context, handler StatefulHandler) (err error) {
+	if handler == nil {
+		return ErrNilHandler
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+	if c.closed.Load() {
+		return ErrClosed
+	}
+	if err := c.takeRebalanceErr(); err != nil {
+		return err
+	}
+
+	msgCtx, msg, err := c.nextMessage(ctx)
+	if err != nil || msg == nil {
+		return err
+	}
+
+	msgCtx, span := xmetrics.Start(msgCtx, c.options.Observer, xmetrics.SpanOptions{
+		Component: componentName,
+		Operation: "consume",
+		Kind:      xmetrics.KindConsumer,
+		Attrs:     kafkaConsumerMessageAttrs(msg, c.groupID),
+	})
+	defer func() {
+		span.End(xmetrics.Result{Err: err})
+	}()
+
+	key := partitionKey{topic: topicName(msg), partition: msg.TopicPartition.Partition}
+	ps, err := c.partitionFor(ctx, key, msg)
+	if err != nil {
+		return err
+	}
+	if msg.TopicPartition.Offset < ps.next {
+		c.skipped.Add(1)
+		return nil
+	}
+
+	state, err := handler(msgCtx, msg, ps.state)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// 防御性检查：rebalance 只在 ReadMessage 内触发，正常情况下 handler 执行期间状态不会被丢弃
+	if c.partitions[key] != ps {
+		return nil
+	}
+	ps.state = state
+	ps.next = msg.TopicPartition.Offset + 1
+	ps.pending++
+	if !c.checkpointDue(ps, time.Now()) {
+		return nil
+	}
+	return c.saveLocked(ctx, key, ps)
+}
+
+// ConsumeLoop 循环消费消息直到 ctx 取消。
+func (c *CheckpointConsumer) ConsumeLoop(ctx context.Context, handler StatefulHandler) error {
+	return c.ConsumeLoopWithPolicy(ctx, handler, nil)
+}
+
+// ConsumeLoopWithPolicy 启动带退避策略的消费循环，backoff 为 nil 时使用默认退避。
+func (c *CheckpointConsumer) ConsumeLoopWithPolicy(ctx context.Context, handler StatefulHandler, backoff BackoffPolicy) error {
+	if handler == nil {
+		return ErrNilHandler
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	consume := func(ctx context.Context) error {
+		return c.Consume(ctx, handler)
+	}
+	return runConsumeLoop(ctx, consume, &c.errorsCount, backoff)
+}
+
+// Checkpoint 立即保存所有有未保存进度的分区检查点。
+// 适用于业务侧需要在特定时机（如窗口结束）强制落盘的场景。
+func (c *CheckpointConsumer) Checkpoint(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+	if c.closed.Load() {
+		return ErrClosed
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.saveAllLocked(ctx)
+}
+
+// CheckpointStats 返回检查点统计信息。
+func (c *CheckpointConsumer) CheckpointStats() CheckpointStats {
+	return CheckpointStats{
+		Saved:   c.saved.Load(),
+		Failed:  c.failed.Load(),
+		Skipped: c.skipped.Load(),
+	}
+}
+
+// Close 保存所有分区检查点后关闭消费者。
+// 检查点保存失败时仍会关闭消费者，错误合并返回；重复调用 Close 安全返回 ErrClosed。
+func (c *CheckpointConsumer) Close() error {
+	c.closeMu.Lock()
+	defer c.closeMu.Unlock()
+	if c.closed.Load() {
+		return ErrClosed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.CheckpointTimeout)
+	defer cancel()
+	c.mu.Lock()
+	saveErr := c.saveAllLocked(ctx)
+	c.mu.Unlock()
+
+	return errors.Join(saveErr, c.consumerWrapper.Close())
+}
+
+// nextMessage 优先返回上次加载检查点失败的消息，否则读取新消息。
+func (c *CheckpointConsumer) nextMessage(ctx context.Context) (context.Context, *kafka.Message, error) {
+	c.mu.Lock()
+	msg := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+	if msg != nil {
+		return extractKafkaTrace(ctx, c.options.Tracer, msg), msg, nil
+	}
+	return c.ReadMessage(ctx)
+}
+
+// partitionFor 返回分区状态，首次遇到该分区时从 store 加载检查点。
+//
+// 设计决策: 在分区的第一条消息到达时加载，而非在 rebalance 回调中加载。
+// 回调中加载失败无法重试，且会阻塞整个消费组的 rebalance；
+// 此处失败时消息暂存到 pending，下次 Consume 重试，不会丢失。
+func (c *CheckpointConsumer) partitionFor(ctx context.Context, key partitionKey, msg *kafka.Message) (*partitionState, error) {
+	c.mu.Lock()
+	ps := c.partitions[key]
+	c.mu.Unlock()
+	if ps != nil {
+		return ps, nil
+	}
+
+	cp, err := c.store.Load(ctx, c.groupID, key.topic, key.partition)
+	if err != nil {
+		c.mu.Lock()
+		c.pending = msg
+		c.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s[%d]: %w", ErrCheckpointLoad, key.topic, key.partition, err)
+	}
+
+	ps = &partitionState{next: msg.TopicPartition.Offset, lastSave: time.Now()}
+	if cp != nil {
+		ps.state = cp.State
+		ps.next = cp.Offset
+	}
+	c.mu.Lock()
+	c.partitions[key] = ps
+	c.mu.Unlock()
+	return ps, nil
+}
+
+// checkpointDue 判断分区是否需要保存检查点。
+func (c *CheckpointConsumer) checkpointDue(ps *partitionState, now time.Time) bool {
+	if ps.pending == 0 {
+		return false
+	}
+	if every := c.options.CheckpointEvery; every > 0 && ps.pending >= every {
+		return true
+	}
+	return now.Sub(ps.lastSave) >= c.options.CheckpointInterval
+}
+
+// saveLocked 保存分区检查点并存储对应 offset，调用方必须持有 mu。
+//
+// 顺序是一致性的关键：先 Save 后 StoreOffsets。若反过来，offset 提交后、
+// 检查点保存前崩溃，重启将从新 offset 开始消费，而状态仍停留在旧检查点，
+// 中间消息的处理结果永久丢失。
+func (c *CheckpointConsumer) saveLocked(ctx context.Context, key partitionKey, ps *partitionState) error {
+	now := time.Now()
+	cp := Checkpoint{
+		Topic:     key.topic,
+		Partition: key.partition,
+		Offset:    ps.next,
+		State:     ps.state,
+		UpdatedAt: now,
+	}
+	if err := c.store.Save(ctx, c.groupID, cp); err != nil {
+		c.failed.Add(1)
+		return fmt.Errorf("%w: %s[%d]@%d: %w", ErrCheckpointSave, key.topic, key.partition, ps.next, err)
+	}
+	c.saved.Add(1)
+	ps.pending = 0
+	ps.lastSave = now
+
+	topic := key.topic
+	if _, err := c.client.StoreOffsets([]kafka.TopicPartition{
+		{Topic: &topic, Partition: key.partition, Offset: ps.next},
+	}); err != nil {
+		// 检查点已保存，offset 落后只会导致重启后多读一段消息（按检查点跳过），不影响正确性
+		return fmt.Errorf("store offset failed: %w", err)
+	}
+	return nil
+}
+
+// saveAllLocked 保存所有有未保存进度的分区检查点，调用方必须持有 mu。
+func (c *CheckpointConsumer) saveAllLocked(ctx context.Context) error {
+	var errs []error
+	for key, ps := range c.partitions {
+		if ps.pending == 0 {
+			continue
+		}
+		if err := c.saveLocked(ctx, key, ps); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// rebalance 注册到 SubscribeTopics 的回调，在 ReadMessage/Close 内同步执行。
+func (c *CheckpointConsumer) rebalance(consumer *kafka.Consumer, ev kafka.Event) error {
+	c.onRebalance(ev, consumer != nil && consumer.AssignmentLost())
+	return nil
+}
+
+// onRebalance 处理分区分配变化。
+//
+// 新分配的分区丢弃旧的内存状态（可能在其他成员持有期间已前进），首条消息到达时重新加载；
+// 撤销的分区先保存检查点再丢弃。lost 为 true 时分区可能已被其他成员持有，不保存检查点。
+// 保存失败记录到 rebalanceErr，由下次 Consume 返回。
+func (c *CheckpointConsumer) onRebalance(ev kafka.Event, lost bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch e := ev.(type) {
+	case kafka.AssignedPartitions:
+		c.dropLocked(e.Partitions)
+	case kafka.RevokedPartitions:
+		if !lost {
+			ctx, cancel := context.WithTimeout(context.Background(), c.options.CheckpointTimeout)
+			var errs []error
+			for _, tp := range e.Partitions {
+				key := partitionKey{topic: derefTopic(tp.Topic), partition: tp.Partition}
+				if ps := c.partitions[key]; ps != nil && ps.pending > 0 {
+					if err := c.saveLocked(ctx, key, ps); err != nil {
+						errs = append(errs, err)
+					}
+				}
+			}
+			cancel()
+			c.rebalanceErr = errors.Join(c.rebalanceErr, errors.Join(errs...))
+		}
+		c.dropLocked(e.Partitions)
+	}
+}
+
+// dropLocked 丢弃分区的内存状态和暂存消息，调用方必须持有 mu。
+func (c *CheckpointConsumer) dropLocked(partitions []kafka.TopicPartition) {
+	for _, tp := range partitions {
+		key := partitionKey{topic: derefTopic(tp.Topic), partition: tp.Partition}
+		delete(c.partitions, key)
+		if c.pending != nil && topicName(c.pending) == key.topic && c.pending.TopicPartition.Partition == key.partition {
+			c.pending = nil
+		}
+	}
+}
+
+// takeRebalanceErr 取出并清空 rebalance 期间记录的错误。
+func (c *CheckpointConsumer) takeRebalanceErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := c.rebalanceErr
+	c.rebalanceErr = nil
+	return err
+}
+
+// topicName 返回消息所属主题，Topic 为 nil 时返回空字符串。
+func topicName(msg *kafka.Message) string {
+	return derefTopic(msg.TopicPartition.Topic)
+}
+
+// derefTopic 安全解引用主题名。
+func derefTopic(topic *string) string {
+	if topic == nil {
+		return ""
+	}
+	return *topic
+}
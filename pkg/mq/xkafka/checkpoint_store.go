@@ -0,0 +1,106 @@
+package xkafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// FileCheckpointStore 基于本地文件的检查点存储。
+//
+// 每个分区一个 JSON 文件：{dir}/{group}/{topic}-{partition}.json，
+// group 与 topic 经 URL 路径转义。写入采用临时文件 + fsync + rename，
+// 崩溃时要么保留旧检查点、要么是完整的新检查点，不会出现半写文件。
+//
+// 适用于固定分区分配或带持久卷的 StatefulSet 部署；分区可能在多个节点间迁移时，
+// 应使用共享存储（Redis、数据库等）实现 [CheckpointStore]。
+type FileCheckpointStore struct {
+	dir string
+}
+
+// NewFileCheckpointStore 创建文件检查点存储，dir 不存在时自动创建。
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if dir == "" {
+		return nil, errors.New("xkafka: checkpoint dir is empty")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("xkafka: create checkpoint dir: %w", err)
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Load 读取分区检查点，文件不存在时返回 (nil, nil)。
+func (s *FileCheckpointStore) Load(ctx context.Context, group, topic string, partition int32) (*Checkpoint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	path := s.path(group, topic, partition)
+	data, err := os.ReadFile(path) // #nosec G304 -- 路径由 dir 与转义后的 group/topic 拼接
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrCheckpointCorrupted, path, err)
+	}
+	return &cp, nil
+}
+
+// Save 原子写入分区检查点。
+func (s *FileCheckpointStore) Save(ctx context.Context, group string, cp Checkpoint) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	path := s.path(group, cp.Topic, cp.Partition)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+
+	// 临时文件与目标文件同目录，保证 rename 不跨文件系统
+	f, err := os.CreateTemp(filepath.Dir(path), ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err := f.Write(data); err != nil {
+		return errors.Join(err, f.Close(), os.Remove(tmp))
+	}
+	if err := f.Sync(); err != nil {
+		return errors.Join(err, f.Close(), os.Remove(tmp))
+	}
+	if err := f.Close(); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	return nil
+}
+
+// path 返回分区检查点文件路径。
+func (s *FileCheckpointStore) path(group, topic string, partition int32) string {
+	name := url.PathEscape(topic) + "-" + strconv.FormatInt(int64(partition), 10) + ".json"
+	return filepath.Join(s.dir, escapeSegment(group), name)
+}
+
+// escapeSegment 转义路径段；PathEscape 不转义 "."，"." 与 ".." 需额外处理以防目录穿越。
+func escapeSegment(s string) string {
+	switch s = url.PathEscape(s); s {
+	case "", ".", "..":
+		return "_" + s
+	}
+	return s
+}
@@ -0,0 +1,404 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var errStore = errors.New("store unavailable")
+
+// memCheckpointStore 内存检查点存储，支持注入错误。
+type memCheckpointStore struct {
+	mu      sync.Mutex
+	data    map[string]Checkpoint
+	loadErr error
+	saveErr error
+	saves   int
+}
+
+func newMemCheckpointStore() *memCheckpointStore {
+	return &memCheckpointStore{data: make(map[string]Checkpoint)}
+}
+
+func (s *memCheckpointStore) key(group, topic string, partition int32) string {
+	return group + "/" + topic + "/" + strconv.Itoa(int(partition))
+}
+
+func (s *memCheckpointStore) Load(_ context.Context, group, topic string, partition int32) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	cp, ok := s.data[s.key(group, topic, partition)]
+	if !ok {
+		return nil, nil
+	}
+	return &cp, nil
+}
+
+func (s *memCheckpointStore) Save(_ context.Context, group string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.saves++
+	cp.State = append([]byte(nil), cp.State...)
+	s.data[s.key(group, cp.Topic, cp.Partition)] = cp
+	return nil
+}
+
+func (s *memCheckpointStore) get(topic string, partition int32) (Checkpoint, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.data[s.key("test-group", topic, partition)]
+	return cp, ok
+}
+
+func newTestCheckpointConsumer(ctrl *gomock.Controller, store CheckpointStore) (*CheckpointConsumer, *MockkafkaConsumerClient) {
+	w, mock := newTestConsumerWrapper(ctrl)
+	return newCheckpointConsumer(&TracingConsumer{consumerWrapper: w}, store), mock
+}
+
+func testMessage(topic string, partition int32, offset kafka.Offset, value string) *kafka.Message {
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: partition, Offset: offset},
+		Value:          []byte(value),
+	}
+}
+
+// appendHandler 将消息体追加到状态，模拟有状态聚合。
+func appendHandler(_ context.Context, msg *kafka.Message, state []byte) ([]byte, error) {
+	return append(state, msg.Value...), nil
+}
+
+func TestNewCheckpointConsumer_Validation(t *testing.T) {
+	_, err := NewCheckpointConsumer(&kafka.ConfigMap{}, []string{"t"}, nil)
+	assert.ErrorIs(t, err, ErrCheckpointStoreRequired)
+
+	_, err = NewCheckpointConsumer(nil, []string{"t"}, newMemCheckpointStore())
+	assert.ErrorIs(t, err, ErrNilConfig)
+}
+
+func TestCheckpointOptions(t *testing.T) {
+	o := defaultConsumerOptions()
+	assert.Equal(t, DefaultCheckpointInterval, o.CheckpointInterval)
+	assert.Equal(t, DefaultCheckpointTimeout, o.CheckpointTimeout)
+	assert.Zero(t, o.CheckpointEvery)
+
+	WithConsumerCheckpointInterval(0)(o)
+	WithConsumerCheckpointEvery(-1)(o)
+	WithConsumerCheckpointTimeout(0)(o)
+	assert.Equal(t, DefaultCheckpointInterval, o.CheckpointInterval)
+	assert.Equal(t, DefaultCheckpointTimeout, o.CheckpointTimeout)
+	assert.Zero(t, o.CheckpointEvery)
+
+	WithConsumerCheckpointInterval(time.Second)(o)
+	WithConsumerCheckpointEvery(10)(o)
+	WithConsumerCheckpointTimeout(2 * time.Second)(o)
+	assert.Equal(t, time.Second, o.CheckpointInterval)
+	assert.Equal(t, 10, o.CheckpointEvery)
+	assert.Equal(t, 2*time.Second, o.CheckpointTimeout)
+}
+
+func TestCheckpointConsumer_SaveBeforeStoreOffsets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	WithConsumerCheckpointEvery(2)(c.options)
+	ctx := context.Background()
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 10, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 11, "b"), nil)
+	// 未到期不存储 offset；到期时先保存检查点，再存储下一条待处理的 offset
+	mock.EXPECT().StoreOffsets(gomock.Any()).DoAndReturn(func(tps []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+		require.Len(t, tps, 1)
+		assert.Equal(t, "orders", *tps[0].Topic)
+		assert.Equal(t, kafka.Offset(12), tps[0].Offset)
+		cp, ok := store.get("orders", 0)
+		require.True(t, ok, "checkpoint must be saved before offsets are stored")
+		assert.Equal(t, kafka.Offset(12), cp.Offset)
+		return tps, nil
+	})
+
+	require.NoError(t, c.Consume(ctx, appendHandler))
+	_, ok := store.get("orders", 0)
+	assert.False(t, ok)
+	require.NoError(t, c.Consume(ctx, appendHandler))
+
+	cp, ok := store.get("orders", 0)
+	require.True(t, ok)
+	assert.Equal(t, []byte("ab"), cp.State)
+	assert.Equal(t, CheckpointStats{Saved: 1}, c.CheckpointStats())
+}
+
+func TestCheckpointConsumer_RestoreAndSkip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	require.NoError(t, store.Save(context.Background(), "test-group",
+		Checkpoint{Topic: "orders", Partition: 1, Offset: 12, State: []byte("ab")}))
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	WithConsumerCheckpointEvery(1)(c.options)
+	ctx := context.Background()
+
+	// 已提交 offset 落后于检查点：11 已包含在状态中，应跳过
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 1, 11, "b"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 1, 12, "c"), nil)
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+
+	var seen [][]byte
+	handler := func(ctx context.Context, msg *kafka.Message, state []byte) ([]byte, error) {
+		seen = append(seen, append([]byte(nil), state...))
+		return appendHandler(ctx, msg, state)
+	}
+	require.NoError(t, c.Consume(ctx, handler))
+	require.NoError(t, c.Consume(ctx, handler))
+
+	assert.Equal(t, [][]byte{[]byte("ab")}, seen, "handler receives restored state and skipped message is not replayed")
+	cp, _ := store.get("orders", 1)
+	assert.Equal(t, []byte("abc"), cp.State)
+	assert.Equal(t, kafka.Offset(13), cp.Offset)
+	assert.Equal(t, int64(1), c.CheckpointStats().Skipped)
+}
+
+func TestCheckpointConsumer_LoadErrorRetriesMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	store.loadErr = errStore
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	ctx := context.Background()
+
+	// 只读取一次：加载失败的消息暂存后重试，不会丢失
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 5, "x"), nil).Times(1)
+
+	err := c.Consume(ctx, appendHandler)
+	require.ErrorIs(t, err, ErrCheckpointLoad)
+	require.ErrorIs(t, err, errStore)
+
+	store.loadErr = nil
+	var got []byte
+	require.NoError(t, c.Consume(ctx, func(_ context.Context, msg *kafka.Message, state []byte) ([]byte, error) {
+		got = msg.Value
+		return state, nil
+	}))
+	assert.Equal(t, []byte("x"), got)
+}
+
+func TestCheckpointConsumer_SaveErrorKeepsOffset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	store.saveErr = errStore
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	WithConsumerCheckpointEvery(1)(c.options)
+	ctx := context.Background()
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 0, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "b"), nil)
+	// 保存失败时不存储 offset（StoreOffsets 无期望，调用即失败）
+	err := c.Consume(ctx, appendHandler)
+	require.ErrorIs(t, err, ErrCheckpointSave)
+
+	// 恢复后下一次保存包含全部未保存进度
+	store.saveErr = nil
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+	require.NoError(t, c.Consume(ctx, appendHandler))
+	cp, _ := store.get("orders", 0)
+	assert.Equal(t, []byte("ab"), cp.State)
+	assert.Equal(t, kafka.Offset(2), cp.Offset)
+	assert.Equal(t, CheckpointStats{Saved: 1, Failed: 1}, c.CheckpointStats())
+}
+
+func TestCheckpointConsumer_HandlerError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestCheckpointConsumer(ctrl, newMemCheckpointStore())
+	handlerErr := errors.New("boom")
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 0, "a"), nil)
+	err := c.Consume(context.Background(), func(context.Context, *kafka.Message, []byte) ([]byte, error) {
+		return []byte("ignored"), handlerErr
+	})
+	require.ErrorIs(t, err, handlerErr)
+
+	c.mu.Lock()
+	ps := c.partitions[partitionKey{topic: "orders"}]
+	c.mu.Unlock()
+	require.NotNil(t, ps)
+	assert.Nil(t, ps.state, "state must not change on handler error")
+	assert.Zero(t, ps.pending)
+}
+
+func TestCheckpointConsumer_Rebalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	ctx := context.Background()
+	topic := "orders"
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 0, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 1, 0, "b"), nil)
+	require.NoError(t, c.Consume(ctx, appendHandler))
+	require.NoError(t, c.Consume(ctx, appendHandler))
+
+	// 正常撤销：保存检查点并存储 offset
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil)
+	c.onRebalance(kafka.RevokedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 0}}}, false)
+	cp, ok := store.get(topic, 0)
+	require.True(t, ok)
+	assert.Equal(t, []byte("a"), cp.State)
+
+	// 分区丢失：不保存，直接丢弃
+	c.onRebalance(kafka.RevokedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 1}}}, true)
+	_, ok = store.get(topic, 1)
+	assert.False(t, ok)
+	c.mu.Lock()
+	assert.Empty(t, c.partitions)
+	c.mu.Unlock()
+
+	// 重新分配后从检查点重新加载
+	c.onRebalance(kafka.AssignedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 0}}}, false)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 1, "c"), nil)
+	var restored []byte
+	require.NoError(t, c.Consume(ctx, func(_ context.Context, _ *kafka.Message, state []byte) ([]byte, error) {
+		restored = append([]byte(nil), state...)
+		return state, nil
+	}))
+	assert.Equal(t, []byte("a"), restored)
+}
+
+func TestCheckpointConsumer_RebalanceSaveErrorSurfaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	topic := "orders"
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 0, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage(topic, 0, 3, "b"), nil)
+	require.NoError(t, c.Consume(context.Background(), appendHandler))
+
+	store.saveErr = errStore
+	// 撤销前 pending 的消息属于该分区，应一并丢弃
+	c.pending = testMessage(topic, 0, 2, "stale")
+	c.onRebalance(kafka.RevokedPartitions{Partitions: []kafka.TopicPartition{{Topic: &topic, Partition: 0}}}, false)
+	assert.Nil(t, c.pending)
+
+	err := c.Consume(context.Background(), appendHandler)
+	require.ErrorIs(t, err, ErrCheckpointSave)
+	// 错误只返回一次
+	store.saveErr = nil
+	require.NoError(t, c.Consume(context.Background(), appendHandler))
+}
+
+func TestCheckpointConsumer_CheckpointAndClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := newMemCheckpointStore()
+	c, mock := newTestCheckpointConsumer(ctrl, store)
+	ctx := context.Background()
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 0, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 1, "b"), nil)
+	require.NoError(t, c.Consume(ctx, appendHandler))
+
+	mock.EXPECT().StoreOffsets(gomock.Any()).Return(nil, nil).Times(2)
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试默认值
+	require.NoError(t, c.Checkpoint(nil))
+	assert.Equal(t, 1, store.saves)
+	// 无新进度时不重复保存
+	require.NoError(t, c.Checkpoint(ctx))
+	assert.Equal(t, 1, store.saves)
+
+	require.NoError(t, c.Consume(ctx, appendHandler))
+	mock.EXPECT().Commit().Return(nil, nil)
+	mock.EXPECT().Close().Return(nil)
+	require.NoError(t, c.Close())
+	cp, _ := store.get("orders", 0)
+	assert.Equal(t, []byte("ab"), cp.State)
+	assert.Equal(t, kafka.Offset(2), cp.Offset)
+
+	assert.ErrorIs(t, c.Close(), ErrClosed)
+	assert.ErrorIs(t, c.Checkpoint(ctx), ErrClosed)
+	assert.ErrorIs(t, c.Consume(ctx, appendHandler), ErrClosed)
+}
+
+func TestCheckpointConsumer_NilHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, _ := newTestCheckpointConsumer(ctrl, newMemCheckpointStore())
+	assert.ErrorIs(t, c.Consume(context.Background(), nil), ErrNilHandler)
+	assert.ErrorIs(t, c.ConsumeLoop(context.Background(), nil), ErrNilHandler)
+}
+
+func TestCheckpointConsumer_ConsumeLoop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	c, mock := newTestCheckpointConsumer(ctrl, newMemCheckpointStore())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mock.EXPECT().ReadMessage(gomock.Any()).Return(testMessage("orders", 0, 0, "a"), nil)
+	mock.EXPECT().ReadMessage(gomock.Any()).DoAndReturn(func(time.Duration) (*kafka.Message, error) {
+		cancel()
+		return nil, kafka.NewError(kafka.ErrTimedOut, "timeout", false)
+	}).AnyTimes()
+
+	err := c.ConsumeLoop(ctx, appendHandler)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileCheckpointStore(filepath.Join(dir, "cp"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	cp, err := store.Load(ctx, "group", "orders", 0)
+	require.NoError(t, err)
+	assert.Nil(t, cp)
+
+	want := Checkpoint{Topic: "orders", Partition: 3, Offset: 42, State: []byte{0, 1, 2}, UpdatedAt: time.Now().UTC()}
+	require.NoError(t, store.Save(ctx, "group", want))
+	want.Offset = 43
+	require.NoError(t, store.Save(ctx, "group", want))
+
+	got, err := store.Load(ctx, "group", "orders", 3)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, want.Offset, got.Offset)
+	assert.Equal(t, want.State, got.State)
+	assert.True(t, want.UpdatedAt.Equal(got.UpdatedAt))
+
+	// 不同消费组互不影响，group 中的特殊字符不会穿越目录
+	got, err = store.Load(ctx, "..", "orders", 3)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	require.NoError(t, store.Save(ctx, "../evil", want))
+	_, err = os.Stat(filepath.Join(dir, "evil"))
+	assert.True(t, os.IsNotExist(err))
+
+	// 无临时文件残留
+	entries, err := os.ReadDir(filepath.Join(dir, "cp", "group"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// 损坏的检查点
+	require.NoError(t, os.WriteFile(store.path("group", "orders", 3), []byte("{"), 0o600))
+	_, err = store.Load(ctx, "group", "orders", 3)
+	assert.ErrorIs(t, err, ErrCheckpointCorrupted)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = store.Load(canceled, "group", "orders", 3)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, store.Save(canceled, "group", want), context.Canceled)
+
+	_, err = NewFileCheckpointStore("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,416 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// HeaderReprocessID 重放消息的幂等标识 Header 键名。
+//
+// 值为 "{原始 topic}/{原始分区}/{原始偏移量}"，同一条原始消息无论被重放多少次都相同，
+// 原 topic 的消费端可据此去重。
+const HeaderReprocessID = "x-reprocess-id"
+
+const (
+	// defaultReprocessBatchSize 默认每批重放的消息数。
+	defaultReprocessBatchSize = 100
+	// defaultReprocessPollTimeout 默认单次拉取超时，超时即视为 DLQ 已消费完。
+	defaultReprocessPollTimeout = time.Second
+)
+
+// ReprocessResult 单次 Reprocess 的统计结果。
+type ReprocessResult struct {
+	// Read 从 DLQ 读取的消息数。
+	Read int
+	// Republished 成功投递回原 topic 的消息数。
+	Republished int
+	// Skipped 被过滤器跳过或无法确定目标 topic 的消息数，其 offset 会被提交。
+	Skipped int
+	// Failed 投递失败的消息数，其 offset 不会被提交。
+	Failed int
+}
+
+// reprocessOptions DLQReprocessor 的配置选项。
+type reprocessOptions struct {
+	BatchSize      int
+	PollTimeout    time.Duration
+	FlushTimeout   time.Duration
+	TargetTopic    string
+	Filter         func(msg *kafka.Message) bool
+	ProducerConfig *kafka.ConfigMap
+}
+
+func defaultReprocessOptions() *reprocessOptions {
+	return &reprocessOptions{
+		BatchSize:    defaultReprocessBatchSize,
+		PollTimeout:  defaultReprocessPollTimeout,
+		FlushTimeout: defaultFlushTimeout,
+	}
+}
+
+// DLQReprocessorOption 定义 DLQReprocessor 的配置选项函数类型。
+type DLQReprocessorOption func(*reprocessOptions)
+
+// WithReprocessBatchSize 设置每批重放的消息数，默认 100。
+// 同一批内的消息并发投递，全部确认后再存储 offset。
+func WithReprocessBatchSize(n int) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		if n > 0 {
+			o.BatchSize = n
+		}
+	}
+}
+
+// WithReprocessPollTimeout 设置单次拉取超时，默认 1 秒。
+// 拉取超时即认为 DLQ 中暂无更多消息，Reprocess 返回。
+func WithReprocessPollTimeout(d time.Duration) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		if d > 0 {
+			o.PollTimeout = d
+		}
+	}
+}
+
+// WithReprocessFlushTimeout 设置 Close 时刷新 Producer 的超时时间，默认 10 秒。
+func WithReprocessFlushTimeout(d time.Duration) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		if d > 0 {
+			o.FlushTimeout = d
+		}
+	}
+}
+
+// WithReprocessTargetTopic 将所有消息投递到指定 topic，忽略 x-original-topic Header。
+// 适用于原 topic 已更名或需先投递到验证 topic 的场景。
+func WithReprocessTargetTopic(topic string) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		o.TargetTopic = topic
+	}
+}
+
+// WithReprocessFilter 设置消息过滤器，返回 false 的消息被跳过（计入 Skipped 并提交 offset）。
+// 用于只重放特定失败原因或特定时间段的消息。
+func WithReprocessFilter(filter func(msg *kafka.Message) bool) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		o.Filter = filter
+	}
+}
+
+// WithReprocessProducerConfig 设置重放 Producer 的配置。
+// 未设置时从 consumer config 派生（过滤 consumer-only 配置项）。
+func WithReprocessProducerConfig(config *kafka.ConfigMap) DLQReprocessorOption {
+	return func(o *reprocessOptions) {
+		o.ProducerConfig = config
+	}
+}
+
+// DLQReprocessor 从 DLQ 消费消息并重新投递回原 topic，标准化 DLQ "修复后重放" 流程。
+//
+// 目标 topic 取自 x-original-topic Header（或 [WithReprocessTargetTopic]）；
+// 重放时移除 DLQ 元数据 Header（重试次数、失败原因等），使消息在原 topic 重新开始重试计数，
+// 并附加 [HeaderReprocessID] 作为幂等标识。
+//
+// 投递语义为 at-least-once：消息投递确认后才存储 offset。派生的 Producer 默认开启
+// enable.idempotence，避免 Producer 内部重试产生重复；跨进程的重复（如存储 offset 前崩溃）
+// 由消费端基于 [HeaderReprocessID] 去重。
+//
+// 并发调用 Reprocess / Close 是安全的，它们互斥执行。
+type DLQReprocessor struct {
+	consumer *consumerWrapper
+	producer kafkaProducerClient
+	options  *reprocessOptions
+
+	// mu 串行化 Reprocess 与 Close。
+	mu     sync.Mutex
+	closed bool
+	// aborted 记录导致中止的错误，非 nil 后 Reprocess 不再执行。
+	aborted error
+}
+
+// NewDLQReprocessor 创建 DLQ 重放器，订阅 dlqTopic。
+//
+// config 为 consumer 配置，须包含 bootstrap.servers 与 group.id；
+// 建议为重放使用独立的 group.id，避免与 DLQ 的其他消费者（如告警）争抢分区。
+func NewDLQReprocessor(config *kafka.ConfigMap, dlqTopic string, opts ...DLQReprocessorOption) (*DLQReprocessor, error) {
+	if config == nil {
+		return nil, ErrNilConfig
+	}
+	if strings.TrimSpace(dlqTopic) == "" {
+		return nil, ErrDLQTopicRequired
+	}
+
+	options := defaultReprocessOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	producerConfig, err := reprocessProducerConfig(config, options.ProducerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	consumer, err := newConsumerWrapper(config, []string{dlqTopic}, WithConsumerPollTimeout(options.PollTimeout))
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := kafka.NewProducer(producerConfig)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("xkafka: create reprocess producer: %w", err), consumer.Close())
+	}
+
+	return newDLQReprocessor(consumer, producer, options), nil
+}
+
+// newDLQReprocessor 内部构造函数，便于测试注入 mock 客户端。
+func newDLQReprocessor(consumer *consumerWrapper, producer kafkaProducerClient, options *reprocessOptions) *DLQReprocessor {
+	return &DLQReprocessor{
+		consumer: consumer,
+		producer: producer,
+		options:  options,
+	}
+}
+
+// reprocessProducerConfig 确定重放 Producer 的配置。
+//
+// 设计决策: 派生配置时默认开启 enable.idempotence，由 broker 对 Producer 重试去重；
+// 调用方显式提供 ProducerConfig 或在 consumer config 中设置该项时尊重其取值。
+func reprocessProducerConfig(consumerConfig, producerConfig *kafka.ConfigMap) (*kafka.ConfigMap, error) {
+	if producerConfig != nil {
+		return producerConfig, nil
+	}
+	derived, err := filterProducerConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if v, _ := derived.Get("enable.idempotence", nil); v == nil {
+		if err := derived.SetKey("enable.idempotence", true); err != nil {
+			return nil, fmt.Errorf("set enable.idempotence: %w", err)
+		}
+	}
+	return derived, nil
+}
+
+// Reprocess 从 DLQ 读取至多 limit 条消息并投递回原 topic。
+// limit <= 0 表示持续处理直到拉取超时（DLQ 暂无更多消息）。
+//
+// 消息按批（见 [WithReprocessBatchSize]）并发投递，批内全部确认后按读取顺序存储 offset；
+// 某条消息投递失败时，同一 DLQ 分区中该消息及其后消息的 offset 均不存储，
+// Reprocess 返回包装 [ErrReprocessAborted] 的错误。由于消费位置已越过未存储的消息，
+// 中止后的 DLQReprocessor 不再可用，调用方应 Close 后重新创建，从已提交位置继续重放。
+// ctx 取消同样会中止。
+func (r *DLQReprocessor) Reprocess(ctx context.Context, limit int) (ReprocessResult, error) {
+	var result ReprocessResult
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return result, ErrClosed
+	}
+	if r.aborted != nil {
+		return result, fmt.Errorf("%w: %w", ErrReprocessAborted, r.aborted)
+	}
+
+	for limit <= 0 || result.Read < limit {
+		size := r.options.BatchSize
+		if limit > 0 {
+			size = min(size, limit-result.Read)
+		}
+		batch, err := r.readBatch(ctx, size)
+		if err != nil {
+			if r.aborted != nil {
+				return result, fmt.Errorf("%w: %w", ErrReprocessAborted, err)
+			}
+			return result, err
+		}
+		if len(batch) == 0 {
+			return result, nil
+		}
+		result.Read += len(batch)
+
+		if err := r.processBatch(ctx, batch, &result); err != nil {
+			r.aborted = err
+			return result, fmt.Errorf("%w: %w", ErrReprocessAborted, err)
+		}
+		if len(batch) < size {
+			return result, nil // 拉取超时，DLQ 已消费完
+		}
+	}
+	return result, nil
+}
+
+// readBatch 读取至多 size 条消息，拉取超时时提前返回。
+func (r *DLQReprocessor) readBatch(ctx context.Context, size int) ([]*kafka.Message, error) {
+	batch := make([]*kafka.Message, 0, size)
+	for len(batch) < size {
+		msg, err := r.readMessage(ctx)
+		if err != nil {
+			if len(batch) > 0 {
+				// 已读取的消息未投递也未存储 offset，只能由重建的 DLQReprocessor 重新读取
+				r.aborted = err
+			}
+			return nil, err
+		}
+		if msg == nil {
+			break
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
+
+// readMessage 读取单条消息，拉取超时返回 nil, nil。
+func (r *DLQReprocessor) readMessage(ctx context.Context) (*kafka.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	msg, err := r.consumer.client.ReadMessage(r.consumer.options.PollTimeout)
+	if err != nil {
+		var kafkaErr kafka.Error
+		if errors.As(err, &kafkaErr) && kafkaErr.Code() == kafka.ErrTimedOut {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return msg, nil
+}
+
+// reprocessItem 批内单条消息的处理状态。
+type reprocessItem struct {
+	msg       *kafka.Message
+	delivered chan kafka.Event // nil 表示已跳过
+}
+
+// processBatch 并发投递一批消息并按读取顺序存储 offset。
+func (r *DLQReprocessor) processBatch(ctx context.Context, batch []*kafka.Message, result *ReprocessResult) error {
+	items := make([]reprocessItem, len(batch))
+	for i, msg := range batch {
+		items[i].msg = msg
+		out, ok := r.buildMessage(msg)
+		if !ok {
+			continue
+		}
+		// 使用缓冲 channel 避免 ctx 取消时 producer 发送阻塞
+		ch := make(chan kafka.Event, 1)
+		if err := r.producer.Produce(out, ch); err != nil {
+			// 入队失败视同投递失败，交由下方按分区截断 offset 存储
+			ch <- &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: out.TopicPartition.Topic, Error: err}}
+		}
+		items[i].delivered = ch
+	}
+
+	// failedPartitions 记录出现投递失败的 DLQ 分区，其后续消息不再存储 offset，保证不越过失败消息
+	failedPartitions := make(map[int32]bool)
+	var errs []error
+	for _, item := range items {
+		partition := item.msg.TopicPartition.Partition
+		if item.delivered == nil {
+			result.Skipped++
+		} else if err := waitDelivery(ctx, item.delivered); err != nil {
+			if ctx.Err() != nil {
+				return errors.Join(append(errs, ctx.Err())...)
+			}
+			result.Failed++
+			failedPartitions[partition] = true
+			errs = append(errs, err)
+			continue
+		} else {
+			result.Republished++
+		}
+		if failedPartitions[partition] {
+			continue
+		}
+		if _, err := r.consumer.client.StoreMessage(item.msg); err != nil {
+			failedPartitions[partition] = true
+			errs = append(errs, fmt.Errorf("store offset failed: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// waitDelivery 等待单条消息的投递确认。
+func waitDelivery(ctx context.Context, ch <-chan kafka.Event) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case e := <-ch:
+		m, ok := e.(*kafka.Message)
+		if !ok {
+			return fmt.Errorf("unexpected reprocess delivery event type: %T", e)
+		}
+		if m.TopicPartition.Error != nil {
+			return fmt.Errorf("reprocess delivery failed for topic %q: %w",
+				topicFromKafkaMessage(m), m.TopicPartition.Error)
+		}
+		return nil
+	}
+}
+
+// buildMessage 构建重放消息。返回 false 表示跳过该消息（被过滤或无法确定目标 topic）。
+func (r *DLQReprocessor) buildMessage(msg *kafka.Message) (*kafka.Message, bool) {
+	if r.options.Filter != nil && !r.options.Filter(msg) {
+		return nil, false
+	}
+	target := r.options.TargetTopic
+	if target == "" {
+		target = getHeader(msg, HeaderOriginalTopic)
+	}
+	if target == "" {
+		return nil, false
+	}
+
+	headers := make([]kafka.Header, 0, len(msg.Headers)+1)
+	for _, h := range msg.Headers {
+		if !dlqMetadataSkipKeys[h.Key] && h.Key != HeaderReprocessID {
+			headers = append(headers, h)
+		}
+	}
+	headers = append(headers, kafka.Header{Key: HeaderReprocessID, Value: []byte(reprocessID(msg))})
+
+	return &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &target, Partition: kafka.PartitionAny},
+		Key:            msg.Key,
+		Value:          msg.Value,
+		Headers:        headers,
+	}, true
+}
+
+// reprocessID 计算消息的幂等标识。
+//
+// 设计决策: 基于 x-original-* Header 而非 DLQ 中的位置，同一原始消息多次进入 DLQ
+// （重放后再次失败）时标识不变；缺少 Header 时退化为 DLQ 中的位置。
+func reprocessID(msg *kafka.Message) string {
+	topic := getHeader(msg, HeaderOriginalTopic)
+	if topic == "" {
+		topic = topicFromKafkaMessage(msg)
+	}
+	return fmt.Sprintf("%s/%d/%d", topic, parseOriginalPartition(msg), parseOriginalOffset(msg))
+}
+
+// Close 关闭重放器：提交已存储的 offset 并关闭消费者，随后刷新并关闭 Producer。
+// 重复调用返回 ErrClosed。
+func (r *DLQReprocessor) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	r.closed = true
+
+	consumerErr := r.consumer.Close()
+	remaining := r.producer.Flush(int(r.options.FlushTimeout.Milliseconds()))
+	r.producer.Close()
+	if remaining > 0 {
+		return errors.Join(consumerErr, fmt.Errorf("%w: %d reprocess messages still in queue", ErrFlushTimeout, remaining))
+	}
+	return consumerErr
+}
@@ -0,0 +1,283 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestDLQReprocessor creates a DLQReprocessor with mock clients for testing.
+func newTestDLQReprocessor(ctrl *gomock.Controller, opts ...DLQReprocessorOption) (*DLQReprocessor, *MockkafkaConsumerClient, *MockkafkaProducerClient) {
+	consumerMock := NewMockkafkaConsumerClient(ctrl)
+	producerMock := NewMockkafkaProducerClient(ctrl)
+
+	options := defaultReprocessOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	cw := &consumerWrapper{
+		client:  consumerMock,
+		options: defaultConsumerOptions(),
+	}
+	cw.options.PollTimeout = options.PollTimeout
+	return newDLQReprocessor(cw, producerMock, options), consumerMock, producerMock
+}
+
+// newDLQMessage 构造 DLQ 中的消息，offset 为其在 DLQ 分区中的位置。
+func newDLQMessage(partition int32, offset int64, originalTopic string) *kafka.Message {
+	dlqTopic := "orders.dlq"
+	msg := &kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &dlqTopic, Partition: partition, Offset: kafka.Offset(offset)},
+		Key:            []byte("k" + strconv.FormatInt(offset, 10)),
+		Value:          []byte("v" + strconv.FormatInt(offset, 10)),
+		Headers: []kafka.Header{
+			{Key: "trace-id", Value: []byte("t1")},
+			{Key: HeaderRetryCount, Value: []byte("3")},
+			{Key: HeaderFailureReason, Value: []byte("boom")},
+		},
+	}
+	if originalTopic != "" {
+		msg.Headers = append(msg.Headers,
+			kafka.Header{Key: HeaderOriginalTopic, Value: []byte(originalTopic)},
+			kafka.Header{Key: HeaderOriginalPartition, Value: []byte("2")},
+			kafka.Header{Key: HeaderOriginalOffset, Value: []byte(strconv.FormatInt(offset+1000, 10))},
+		)
+	}
+	return msg
+}
+
+// expectReads 按顺序返回 msgs，之后返回拉取超时。
+func expectReads(consumerMock *MockkafkaConsumerClient, msgs ...*kafka.Message) {
+	calls := make([]any, 0, len(msgs)+1)
+	for _, m := range msgs {
+		calls = append(calls, consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(m, nil))
+	}
+	timedOut := kafka.NewError(kafka.ErrTimedOut, "timed out", false)
+	calls = append(calls, consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(nil, timedOut).AnyTimes())
+	gomock.InOrder(calls...)
+}
+
+// deliverWith 模拟 Produce 投递回执，value 在 failValues 中的消息投递失败。
+func deliverWith(produced *[]*kafka.Message, failValues ...string) func(*kafka.Message, chan kafka.Event) error {
+	return func(m *kafka.Message, ch chan kafka.Event) error {
+		*produced = append(*produced, m)
+		report := &kafka.Message{TopicPartition: m.TopicPartition}
+		for _, v := range failValues {
+			if string(m.Value) == v {
+				report.TopicPartition.Error = kafka.NewError(kafka.ErrMsgTimedOut, "delivery timed out", false)
+			}
+		}
+		ch <- report
+		return nil
+	}
+}
+
+func TestDLQReprocessor_Reprocess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl, WithReprocessBatchSize(2))
+
+	msgs := []*kafka.Message{
+		newDLQMessage(0, 10, "orders"),
+		newDLQMessage(0, 11, "orders"),
+		newDLQMessage(0, 12, ""), // 缺少原始 topic，跳过
+	}
+	expectReads(consumerMock, msgs...)
+	var produced []*kafka.Message
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(deliverWith(&produced)).Times(2)
+	for _, m := range msgs {
+		consumerMock.EXPECT().StoreMessage(m).Return(nil, nil)
+	}
+
+	result, err := r.Reprocess(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, ReprocessResult{Read: 3, Republished: 2, Skipped: 1}, result)
+
+	require.Len(t, produced, 2)
+	out := produced[0]
+	assert.Equal(t, "orders", *out.TopicPartition.Topic)
+	assert.Equal(t, kafka.PartitionAny, out.TopicPartition.Partition)
+	assert.Equal(t, []byte("k10"), out.Key)
+	assert.Equal(t, []byte("v10"), out.Value)
+	assert.Equal(t, "t1", getHeader(out, "trace-id"))
+	assert.Equal(t, "orders/2/1010", getHeader(out, HeaderReprocessID))
+	for _, key := range []string{HeaderRetryCount, HeaderFailureReason, HeaderOriginalTopic, HeaderOriginalOffset} {
+		assert.Empty(t, getHeader(out, key), "DLQ 元数据 %s 应被移除", key)
+	}
+}
+
+func TestDLQReprocessor_Reprocess_Limit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl)
+
+	msgs := []*kafka.Message{newDLQMessage(0, 1, "orders"), newDLQMessage(0, 2, "orders")}
+	consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(msgs[0], nil)
+	consumerMock.EXPECT().StoreMessage(msgs[0]).Return(nil, nil)
+	var produced []*kafka.Message
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(deliverWith(&produced))
+
+	result, err := r.Reprocess(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, ReprocessResult{Read: 1, Republished: 1}, result)
+}
+
+func TestDLQReprocessor_Reprocess_FilterAndTargetTopic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl,
+		WithReprocessTargetTopic("orders.replay"),
+		WithReprocessFilter(func(m *kafka.Message) bool { return string(m.Value) != "v2" }),
+	)
+
+	msgs := []*kafka.Message{newDLQMessage(0, 1, "orders"), newDLQMessage(0, 2, "orders"), newDLQMessage(0, 3, "")}
+	expectReads(consumerMock, msgs...)
+	for _, m := range msgs {
+		consumerMock.EXPECT().StoreMessage(m).Return(nil, nil)
+	}
+	var produced []*kafka.Message
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(deliverWith(&produced)).Times(2)
+
+	result, err := r.Reprocess(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, ReprocessResult{Read: 3, Republished: 2, Skipped: 1}, result)
+	for _, out := range produced {
+		assert.Equal(t, "orders.replay", *out.TopicPartition.Topic)
+	}
+	// 缺少原始 Header 时以 DLQ 位置作为幂等标识
+	assert.Equal(t, "orders.dlq/0/3", getHeader(produced[1], HeaderReprocessID))
+}
+
+func TestDLQReprocessor_Reprocess_DeliveryFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl)
+
+	p0a, p0b, p0c := newDLQMessage(0, 1, "orders"), newDLQMessage(0, 2, "orders"), newDLQMessage(0, 3, "orders")
+	p1 := newDLQMessage(1, 4, "orders")
+	expectReads(consumerMock, p0a, p0b, p0c, p1)
+	var produced []*kafka.Message
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(deliverWith(&produced, "v2")).Times(4)
+	// 分区 0 只存储失败消息之前的 offset，分区 1 不受影响
+	consumerMock.EXPECT().StoreMessage(p0a).Return(nil, nil)
+	consumerMock.EXPECT().StoreMessage(p1).Return(nil, nil)
+
+	result, err := r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, ErrReprocessAborted)
+	assert.Equal(t, ReprocessResult{Read: 4, Republished: 3, Failed: 1}, result)
+
+	// 中止后不再读取
+	_, err = r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, ErrReprocessAborted)
+}
+
+func TestDLQReprocessor_Reprocess_ProduceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl)
+
+	msg := newDLQMessage(0, 1, "orders")
+	expectReads(consumerMock, msg)
+	queueFull := kafka.NewError(kafka.ErrQueueFull, "queue full", false)
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(queueFull)
+
+	result, err := r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, ErrReprocessAborted)
+	require.ErrorIs(t, err, queueFull)
+	assert.Equal(t, 1, result.Failed)
+}
+
+func TestDLQReprocessor_Reprocess_ReadError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, _ := newTestDLQReprocessor(ctrl)
+
+	readErr := errors.New("broker down")
+	consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(nil, readErr)
+
+	// 尚未读到消息，错误不导致中止
+	_, err := r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, readErr)
+	require.NotErrorIs(t, err, ErrReprocessAborted)
+
+	msg := newDLQMessage(0, 1, "orders")
+	gomock.InOrder(
+		consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(msg, nil),
+		consumerMock.EXPECT().ReadMessage(gomock.Any()).Return(nil, readErr),
+	)
+	_, err = r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, ErrReprocessAborted)
+	require.ErrorIs(t, err, readErr)
+}
+
+func TestDLQReprocessor_Reprocess_ContextCanceled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	msg := newDLQMessage(0, 1, "orders")
+	expectReads(consumerMock, msg)
+	// 投递回执迟迟不到，取消 ctx 后 Reprocess 返回且不存储 offset
+	producerMock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(func(*kafka.Message, chan kafka.Event) error {
+		cancel()
+		return nil
+	})
+
+	_, err := r.Reprocess(ctx, 0)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, err, ErrReprocessAborted)
+}
+
+func TestDLQReprocessor_Close(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	r, consumerMock, producerMock := newTestDLQReprocessor(ctrl, WithReprocessFlushTimeout(time.Second))
+
+	consumerMock.EXPECT().Commit().Return(nil, nil)
+	consumerMock.EXPECT().Close().Return(nil)
+	producerMock.EXPECT().Flush(1000).Return(1)
+	producerMock.EXPECT().Close()
+
+	err := r.Close()
+	require.ErrorIs(t, err, ErrFlushTimeout)
+	require.ErrorIs(t, r.Close(), ErrClosed)
+
+	_, err = r.Reprocess(context.Background(), 0)
+	require.ErrorIs(t, err, ErrClosed)
+}
+
+func TestNewDLQReprocessor_InvalidArgs(t *testing.T) {
+	_, err := NewDLQReprocessor(nil, "orders.dlq")
+	require.ErrorIs(t, err, ErrNilConfig)
+
+	_, err = NewDLQReprocessor(&kafka.ConfigMap{"bootstrap.servers": "localhost:9092"}, " ")
+	require.ErrorIs(t, err, ErrDLQTopicRequired)
+}
+
+func TestReprocessProducerConfig(t *testing.T) {
+	consumerConfig := &kafka.ConfigMap{
+		"bootstrap.servers": "localhost:9092",
+		"group.id":          "replay",
+	}
+	cfg, err := reprocessProducerConfig(consumerConfig, nil)
+	require.NoError(t, err)
+	v, err := cfg.Get("enable.idempotence", nil)
+	require.NoError(t, err)
+	assert.Equal(t, true, v)
+	v, err = cfg.Get("group.id", nil)
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	// 用户显式关闭时尊重其取值
+	require.NoError(t, consumerConfig.SetKey("enable.idempotence", false))
+	cfg, err = reprocessProducerConfig(consumerConfig, nil)
+	require.NoError(t, err)
+	v, err = cfg.Get("enable.idempotence", nil)
+	require.NoError(t, err)
+	assert.Equal(t, false, v)
+
+	explicit := &kafka.ConfigMap{"bootstrap.servers": "other:9092"}
+	cfg, err = reprocessProducerConfig(consumerConfig, explicit)
+	require.NoError(t, err)
+	assert.Same(t, explicit, cfg)
+}
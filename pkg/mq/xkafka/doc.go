@@ -23,6 +23,19 @@
 // 而非接口，因为 Tracing 类型通过嵌入添加了额外方法（如带追踪的 Produce/Consume），
 // 返回接口会丢失这些方法。
 //
+// # Schema 校验
+//
+// 通过 [WithProducerSerializer] 和 [WithConsumerDeserializer] 集成 Schema Registry，
+// [TracingProducer.ProduceValue] 在发送前序列化并校验消息，[TracingConsumer.DecodeValue] 解码消息体。
+// 接口与 confluent-kafka-go 的 schemaregistry/serde 兼容，可直接使用 avrov2/protobuf/jsonschema：
+//
+//	client, _ := schemaregistry.NewClient(schemaregistry.NewConfig("http://registry:8081"))
+//	ser, _ := avrov2.NewSerializer(client, serde.ValueSerde, avrov2.NewSerializerConfig())
+//	producer, _ := xkafka.NewTracingProducer(config, xkafka.WithProducerSerializer(ser))
+//	err := producer.ProduceValue(ctx, &kafka.Message{TopicPartition: tp}, &order, nil)
+//
+// 不符合 schema 的消息返回 [ErrSchemaValidation]，不会被发送。
+//
 // # 死信队列
 //
 // 使用 ConsumerWithDLQ 结合 DLQPolicy 实现消息重试和死信处理。
@@ -36,6 +49,30 @@
 // 失败原因写入 x-failure-reason Header 时默认截断至 1024 字符，
 // 防止敏感信息泄露。可通过 [DLQPolicy].FailureReasonFormatter 自定义格式化。
 //
+// 问题修复后使用 [DLQReprocessor] 将 DLQ 消息按 x-original-topic 重新投递回原 topic：
+// 批量投递、确认后才提交 offset，重放消息移除 DLQ 元数据并附带 [HeaderReprocessID]
+// 作为幂等标识，消费端可据此去重。
+//
+// # 处理进度检查点
+//
+// 有状态处理（窗口聚合、去重集合等）使用 [NewCheckpointConsumer]：handler 接收分区当前状态
+// 并返回新状态，[CheckpointConsumer] 按 [WithConsumerCheckpointInterval] /
+// [WithConsumerCheckpointEvery] 将状态与"下一条待处理 offset"一起保存到 [CheckpointStore]，
+// 重启或 rebalance 后从检查点恢复。内置 [FileCheckpointStore]，分区会跨节点迁移时
+// 应基于共享存储实现 CheckpointStore。
+//
+// 与 offset 提交的协调：先保存检查点再存储 offset，已提交 offset 永远不超过检查点；
+// 恢复时落后于检查点的消息直接跳过，状态中每条消息恰好生效一次。
+// 分区撤销时保存检查点，注册了 rebalance 回调，是本包唯一不依赖 auto-commit 窗口处理撤销的消费者。
+//
+// # 批量消费
+//
+// 需要批量写下游（如 xclickhouse 的 BatchInsert）时使用 [NewBatchConsumer]：按
+// [WithConsumerBatchSize] / [WithConsumerBatchWindow] 在本地攒批，[BatchHandler] 一次接收整批消息，
+// 处理成功后按分区统一存储 offset。handler 失败时整批保留在本地，下次 Consume 原样重试；
+// 返回 [BatchPartialError] 时只存储前 Processed 条的 offset，仅重试剩余部分，保持 at-least-once。
+// 分区撤销时丢弃缓冲中该分区的消息，由新持有者从已提交 offset 重新消费。
+//
 // # 统计信息
 //
 // [ProducerStats] 和 [ConsumerStats] 中的 MessagesProduced/MessagesConsumed 等计数
@@ -46,6 +83,9 @@
 // 在分区数较多时可能持有锁数秒。不建议在高频路径（如秒级健康检查）中调用 Stats()。
 // Lag RPC 超时复用 [WithConsumerHealthTimeout] 配置，设置较短值可能导致 lag 返回 0。
 //
+// 需要持续监控或告警时使用 [LagMonitor]：在独立 goroutine 中周期采集逐分区 lag
+// 并回调，可通过 [WithLagMeterProvider] 导出 xkafka.consumer.lag gauge，不阻塞消费循环。
+//
 // # Offset 提交模型
 //
 // 本包强制设置 enable.auto.offset.store=false 和 enable.auto.commit=true
@@ -43,4 +43,35 @@ var (
 
 	// ErrEmptyTopics 表示订阅的主题列表为空。
 	ErrEmptyTopics = errors.New("xkafka: empty topics")
+
+	// ErrInvalidInterval 表示间隔时间必须为正数。
+	ErrInvalidInterval = errors.New("xkafka: interval must be positive")
+
+	// ErrUnsupportedConsumer 表示 Consumer 不是本包创建的实例，无法访问底层 offset 查询。
+	// LagMonitor 仅支持 NewConsumer/NewTracingConsumer/NewConsumerWithDLQ 返回的 Consumer。
+	ErrUnsupportedConsumer = errors.New("xkafka: unsupported consumer implementation")
+
+	// ErrSerializerRequired 表示未配置序列化器，无法调用 ProduceValue。
+	ErrSerializerRequired = errors.New("xkafka: serializer is required")
+
+	// ErrDeserializerRequired 表示未配置反序列化器，无法调用 DecodeValue。
+	ErrDeserializerRequired = errors.New("xkafka: deserializer is required")
+
+	// ErrSchemaValidation 表示消息序列化或反序列化失败，通常是消息不符合 schema。
+	ErrSchemaValidation = errors.New("xkafka: schema validation failed")
+
+	// ErrReprocessAborted 表示 DLQ 重放因投递失败或读取中断而中止，需重建 DLQReprocessor 后继续。
+	ErrReprocessAborted = errors.New("xkafka: DLQ reprocess aborted")
+
+	// ErrCheckpointStoreRequired 表示创建 CheckpointConsumer 时未提供检查点存储。
+	ErrCheckpointStoreRequired = errors.New("xkafka: checkpoint store is required")
+
+	// ErrCheckpointLoad 表示读取分区检查点失败，消息会在下次 Consume 时重试。
+	ErrCheckpointLoad = errors.New("xkafka: load checkpoint failed")
+
+	// ErrCheckpointSave 表示保存分区检查点失败，offset 不会前移。
+	ErrCheckpointSave = errors.New("xkafka: save checkpoint failed")
+
+	// ErrCheckpointCorrupted 表示检查点数据无法解析。
+	ErrCheckpointCorrupted = errors.New("xkafka: checkpoint corrupted")
 )
@@ -165,6 +165,12 @@ func NewConsumer(config *kafka.ConfigMap, topics []string, opts ...ConsumerOptio
 }
 
 func newConsumerWrapper(config *kafka.ConfigMap, topics []string, opts ...ConsumerOption) (*consumerWrapper, error) {
+	return subscribeConsumerWrapper(config, topics, nil, opts...)
+}
+
+// subscribeConsumerWrapper 创建 consumerWrapper 并订阅 topics，rebalanceCb 可为 nil。
+func subscribeConsumerWrapper(config *kafka.ConfigMap, topics []string, rebalanceCb kafka.RebalanceCb,
+	opts ...ConsumerOption) (*consumerWrapper, error) {
 	if config == nil {
 		return nil, ErrNilConfig
 	}
@@ -202,10 +208,10 @@ func newConsumerWrapper(config *kafka.ConfigMap, topics []string, opts ...Consum
 		return nil, fmt.Errorf("xkafka: create consumer: %w", err)
 	}
 
-	// 设计决策: SubscribeTopics 第二参数为 nil，未注册 rebalance 回调。
+	// 设计决策: 除 CheckpointConsumer、BatchConsumer 外 rebalanceCb 均为 nil，未注册 rebalance 回调。
 	// 分区撤销时 offset 提交依赖 auto-commit 窗口（默认 5s）。
 	// 如需更精确的 rebalance 处理，用户可通过 Consumer() 获取底层 API 自行管理。
-	if err := consumer.SubscribeTopics(topics, nil); err != nil {
+	if err := consumer.SubscribeTopics(topics, rebalanceCb); err != nil {
 		return nil, errors.Join(err, consumer.Close())
 	}
 
@@ -240,6 +246,7 @@ type producerOptions struct {
 	Observer      xmetrics.Observer
 	FlushTimeout  time.Duration
 	HealthTimeout time.Duration
+	Serializer    Serializer
 }
 
 func defaultProducerOptions() *producerOptions {
@@ -290,20 +297,44 @@ func WithProducerHealthTimeout(d time.Duration) ProducerOption {
 	}
 }
 
+// WithProducerSerializer 设置 ProduceValue 使用的序列化器。
+// 通常传入 Schema Registry 序列化器，在发送前校验消息格式。
+func WithProducerSerializer(s Serializer) ProducerOption {
+	return func(o *producerOptions) {
+		if s != nil {
+			o.Serializer = s
+		}
+	}
+}
+
 // consumerOptions 包含 Kafka Consumer 的配置选项。
 type consumerOptions struct {
 	Tracer        Tracer
 	Observer      xmetrics.Observer
 	PollTimeout   time.Duration
 	HealthTimeout time.Duration
+	Deserializer  Deserializer
+
+	// 以下仅 CheckpointConsumer 使用
+	CheckpointInterval time.Duration
+	CheckpointEvery    int
+	CheckpointTimeout  time.Duration
+
+	// 以下仅 BatchConsumer 使用
+	BatchSize   int
+	BatchWindow time.Duration
 }
 
 func defaultConsumerOptions() *consumerOptions {
 	return &consumerOptions{
-		Tracer:        NoopTracer{},
-		Observer:      xmetrics.NoopObserver{},
-		PollTimeout:   100 * time.Millisecond,
-		HealthTimeout: 5 * time.Second,
+		Tracer:             NoopTracer{},
+		Observer:           xmetrics.NoopObserver{},
+		PollTimeout:        100 * time.Millisecond,
+		HealthTimeout:      5 * time.Second,
+		CheckpointInterval: DefaultCheckpointInterval,
+		CheckpointTimeout:  DefaultCheckpointTimeout,
+		BatchSize:          DefaultBatchSize,
+		BatchWindow:        DefaultBatchWindow,
 	}
 }
 
@@ -345,3 +376,61 @@ func WithConsumerHealthTimeout(d time.Duration) ConsumerOption {
 		}
 	}
 }
+
+// WithConsumerDeserializer 设置 DecodeValue 使用的反序列化器。
+func WithConsumerDeserializer(d Deserializer) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d != nil {
+			o.Deserializer = d
+		}
+	}
+}
+
+// WithConsumerCheckpointInterval 设置 CheckpointConsumer 的检查点保存间隔。
+// 分区自上次检查点以来处理过消息且距上次保存超过 d 时，处理完当前消息后保存检查点。
+func WithConsumerCheckpointInterval(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			o.CheckpointInterval = d
+		}
+	}
+}
+
+// WithConsumerCheckpointEvery 设置 CheckpointConsumer 每处理 n 条消息保存一次检查点。
+// 与 WithConsumerCheckpointInterval 同时生效，任一条件满足即保存。
+func WithConsumerCheckpointEvery(n int) ConsumerOption {
+	return func(o *consumerOptions) {
+		if n > 0 {
+			o.CheckpointEvery = n
+		}
+	}
+}
+
+// WithConsumerCheckpointTimeout 设置 rebalance 和 Close 时保存检查点的超时时间。
+// Consume 路径的检查点读写使用调用方传入的 ctx。
+func WithConsumerCheckpointTimeout(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			o.CheckpointTimeout = d
+		}
+	}
+}
+
+// WithConsumerBatchSize 设置 BatchConsumer 每批最大消息数。
+func WithConsumerBatchSize(n int) ConsumerOption {
+	return func(o *consumerOptions) {
+		if n > 0 {
+			o.BatchSize = n
+		}
+	}
+}
+
+// WithConsumerBatchWindow 设置 BatchConsumer 的攒批窗口。
+// 从批次第一条消息读取时开始计时，到期后即使未攒满 BatchSize 也交给 handler 处理。
+func WithConsumerBatchWindow(d time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		if d > 0 {
+			o.BatchWindow = d
+		}
+	}
+}
@@ -25,6 +25,7 @@ type kafkaConsumerClient interface {
 	Committed(partitions []kafka.TopicPartition, timeoutMs int) ([]kafka.TopicPartition, error)
 	QueryWatermarkOffsets(topic string, partition int32, timeoutMs int) (low int64, high int64, err error)
 	StoreMessage(msg *kafka.Message) ([]kafka.TopicPartition, error)
+	StoreOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error)
 	Commit() ([]kafka.TopicPartition, error)
 	Close() error
 }
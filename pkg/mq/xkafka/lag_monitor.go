@@ -0,0 +1,306 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// =============================================================================
+// 消费延迟监控
+// =============================================================================
+
+// PartitionLag 单个分区的消费延迟。
+type PartitionLag struct {
+	// Topic 主题名称。
+	Topic string
+	// Partition 分区号。
+	Partition int32
+	// Committed 消费组已提交的 offset。
+	// 为负数表示该分区尚无提交记录（kafka.OffsetInvalid），此时 Lag 为 0。
+	Committed int64
+	// HighWatermark 分区最新 offset（下一条消息的 offset）。
+	// 查询失败时为 -1。
+	HighWatermark int64
+	// Lag 消费延迟，HighWatermark - Committed，最小为 0。
+	Lag int64
+	// Err 查询该分区水位失败时的错误，其他分区不受影响。
+	Err error
+}
+
+// LagReport 一次 lag 采集的结果。
+type LagReport struct {
+	// Group 消费组 ID。
+	Group string
+	// Partitions 当前分配给本消费者的各分区 lag。
+	// 未分配任何分区时为空。
+	Partitions []PartitionLag
+	// TotalLag 所有分区 lag 之和。
+	TotalLag int64
+	// Time 采集完成时间。
+	Time time.Time
+	// Err 整体采集失败的错误（如获取分区分配或已提交 offset 失败）。
+	// 非 nil 时 Partitions 为空。
+	Err error
+}
+
+// LagCallback 接收 lag 采集结果的回调函数。
+// 回调在 LagMonitor 的 goroutine 中同步执行，耗时操作应自行异步处理，
+// 否则会推迟下一次采集。
+type LagCallback func(report LagReport)
+
+// lagSource 提供逐分区 lag 查询，由 consumerWrapper 实现，
+// TracingConsumer 与 dlqConsumer 通过嵌入 *consumerWrapper 获得。
+type lagSource interface {
+	partitionLags(timeout time.Duration) ([]PartitionLag, error)
+	consumerGroup() string
+}
+
+// partitionLags 采集当前分配分区的逐分区 lag。
+//
+// 与 Stats().Lag 相比，已提交 offset 通过一次 Committed RPC 批量获取，
+// 仅水位查询按分区执行，显著缩短持有 mu 的时间。
+// 消费路径（ReadMessage）不获取 mu，因此采集不会阻塞消费。
+func (w *consumerWrapper) partitionLags(timeout time.Duration) ([]PartitionLag, error) {
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+
+	assignment, err := w.client.Assignment()
+	if err != nil {
+		return nil, fmt.Errorf("xkafka: get assignment: %w", err)
+	}
+	if len(assignment) == 0 {
+		return nil, nil
+	}
+
+	timeoutMs := int(timeout.Milliseconds())
+	committed, err := w.client.Committed(assignment, timeoutMs)
+	if err != nil {
+		return nil, fmt.Errorf("xkafka: get committed offsets: %w", err)
+	}
+
+	lags := make([]PartitionLag, 0, len(committed))
+	for _, tp := range committed {
+		if tp.Topic == nil {
+			continue
+		}
+		pl := PartitionLag{
+			Topic:         *tp.Topic,
+			Partition:     tp.Partition,
+			Committed:     int64(tp.Offset),
+			HighWatermark: -1,
+		}
+		_, high, err := w.client.QueryWatermarkOffsets(pl.Topic, pl.Partition, timeoutMs)
+		if err != nil {
+			pl.Err = fmt.Errorf("xkafka: query watermark %s[%d]: %w", pl.Topic, pl.Partition, err)
+			lags = append(lags, pl)
+			continue
+		}
+		pl.HighWatermark = high
+		if pl.Committed >= 0 && high > pl.Committed {
+			pl.Lag = high - pl.Committed
+		}
+		lags = append(lags, pl)
+	}
+	return lags, nil
+}
+
+// consumerGroup 返回消费组 ID。
+func (w *consumerWrapper) consumerGroup() string {
+	return w.groupID
+}
+
+// 默认 lag 查询 RPC 超时。
+const defaultLagTimeout = 5 * time.Second
+
+// lagMonitorOptions LagMonitor 配置选项。
+type lagMonitorOptions struct {
+	Timeout       time.Duration
+	MeterProvider metric.MeterProvider
+}
+
+// LagMonitorOption 定义 LagMonitor 的配置选项函数类型。
+type LagMonitorOption func(*lagMonitorOptions)
+
+// WithLagTimeout 设置 Committed/QueryWatermarkOffsets RPC 的超时时间，默认 5s。
+// 与 Stats().Lag 不同，LagMonitor 不复用 HealthTimeout，以便独立调整。
+func WithLagTimeout(d time.Duration) LagMonitorOption {
+	return func(o *lagMonitorOptions) {
+		if d > 0 {
+			o.Timeout = d
+		}
+	}
+}
+
+// WithLagMeterProvider 设置 OpenTelemetry MeterProvider，导出逐分区 lag gauge。
+//
+// 指标名为 xkafka.consumer.lag，属性包括 messaging.destination.name、
+// messaging.destination.partition.id 和 messaging.consumer.group.name。
+// 使用异步 gauge 只上报最近一次采集中存在的分区，rebalance 后被撤销的分区不会残留旧值。
+// 不设置时不导出指标。
+func WithLagMeterProvider(mp metric.MeterProvider) LagMonitorOption {
+	return func(o *lagMonitorOptions) {
+		o.MeterProvider = mp
+	}
+}
+
+// LagMonitor 在独立 goroutine 中周期性采集消费组各分区 lag。
+//
+// 设计决策: Stats().Lag 在调用方 goroutine 中同步执行 RPC，只返回总和，
+// 不适合接入告警。LagMonitor 把采集移出消费循环，提供逐分区明细与可选的 OTel gauge，
+// 且与消费路径无锁竞争（仅与 Health/Stats/Close 串行）。
+//
+// 使用 Run 启动，适合交给 xrun.Group 管理生命周期：
+//
+//	monitor, err := xkafka.NewLagMonitor(consumer, 30*time.Second, func(r xkafka.LagReport) {
+//	    if r.TotalLag > 10000 {
+//	        alert(r)
+//	    }
+//	})
+//	g.Go(monitor.Run)
+type LagMonitor struct {
+	source   lagSource
+	interval time.Duration
+	callback LagCallback
+	options  *lagMonitorOptions
+
+	last atomic.Pointer[LagReport]
+}
+
+// NewLagMonitor 创建消费延迟监控器。
+//
+// consumer 必须由 NewConsumer、NewTracingConsumer 或 NewConsumerWithDLQ 创建，
+// 否则返回 ErrUnsupportedConsumer。interval 必须为正数，callback 不能为 nil。
+func NewLagMonitor(consumer Consumer, interval time.Duration, callback LagCallback, opts ...LagMonitorOption) (*LagMonitor, error) {
+	if consumer == nil {
+		return nil, ErrNilClient
+	}
+	source, ok := consumer.(lagSource)
+	if !ok {
+		return nil, ErrUnsupportedConsumer
+	}
+	if interval <= 0 {
+		return nil, ErrInvalidInterval
+	}
+	if callback == nil {
+		return nil, ErrNilHandler
+	}
+
+	options := &lagMonitorOptions{Timeout: defaultLagTimeout}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &LagMonitor{
+		source:   source,
+		interval: interval,
+		callback: callback,
+		options:  options,
+	}, nil
+}
+
+// Run 立即采集一次，之后每隔 interval 采集并回调，直到 ctx 取消或 Consumer 关闭。
+//
+// ctx 取消时返回 ctx.Err()；Consumer 关闭时返回 ErrClosed。
+// 单次采集失败不会终止监控，错误通过 LagReport.Err 传给回调。
+func (m *LagMonitor) Run(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if m.options.MeterProvider != nil {
+		reg, err := m.registerGauge()
+		if err != nil {
+			return err
+		}
+		// 注销失败仅意味着 gauge 继续读取最后一次结果，不影响监控本身
+		defer func() { _ = reg.Unregister() }()
+	}
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		report := m.Collect()
+		if errors.Is(report.Err, ErrClosed) {
+			return ErrClosed
+		}
+		m.callback(report)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect 同步采集一次 lag 并返回结果，同时更新 gauge 读取的最近结果。
+// 可用于在 Run 之外按需查询（如管理端点）。
+func (m *LagMonitor) Collect() LagReport {
+	report := LagReport{Group: m.source.consumerGroup()}
+	report.Partitions, report.Err = m.source.partitionLags(m.options.Timeout)
+	for _, pl := range report.Partitions {
+		report.TotalLag += pl.Lag
+	}
+	report.Time = time.Now()
+	m.last.Store(&report)
+	return report
+}
+
+// Last 返回最近一次采集结果，尚未采集时返回 false。
+func (m *LagMonitor) Last() (LagReport, bool) {
+	r := m.last.Load()
+	if r == nil {
+		return LagReport{}, false
+	}
+	return *r, true
+}
+
+// registerGauge 注册异步 lag gauge，回调读取最近一次采集结果。
+func (m *LagMonitor) registerGauge() (metric.Registration, error) {
+	meter := m.options.MeterProvider.Meter(componentName)
+	gauge, err := meter.Int64ObservableGauge(
+		"xkafka.consumer.lag",
+		metric.WithDescription("消费组分区消费延迟"),
+		metric.WithUnit("{message}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("xkafka: create lag gauge: %w", err)
+	}
+	reg, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		r := m.last.Load()
+		if r == nil {
+			return nil
+		}
+		for _, pl := range r.Partitions {
+			if pl.Err != nil {
+				continue
+			}
+			o.ObserveInt64(gauge, pl.Lag, metric.WithAttributes(
+				attribute.String("messaging.destination.name", pl.Topic),
+				attribute.String("messaging.destination.partition.id", strconv.FormatInt(int64(pl.Partition), 10)),
+				attribute.String("messaging.consumer.group.name", r.Group),
+			))
+		}
+		return nil
+	}, gauge)
+	if err != nil {
+		return nil, fmt.Errorf("xkafka: register lag gauge: %w", err)
+	}
+	return reg, nil
+}
@@ -0,0 +1,208 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/mock/gomock"
+)
+
+// expectTwoPartitionLag 设置两个分区的 lag 查询期望：p0 lag=20，p1 lag=5。
+func expectTwoPartitionLag(mock *MockkafkaConsumerClient, topic *string) {
+	assignment := []kafka.TopicPartition{
+		{Topic: topic, Partition: 0},
+		{Topic: topic, Partition: 1},
+	}
+	mock.EXPECT().Assignment().Return(assignment, nil)
+	mock.EXPECT().Committed(assignment, gomock.Any()).Return([]kafka.TopicPartition{
+		{Topic: topic, Partition: 0, Offset: 80},
+		{Topic: topic, Partition: 1, Offset: 45},
+	}, nil)
+	mock.EXPECT().QueryWatermarkOffsets(*topic, int32(0), gomock.Any()).Return(int64(0), int64(100), nil)
+	mock.EXPECT().QueryWatermarkOffsets(*topic, int32(1), gomock.Any()).Return(int64(0), int64(50), nil)
+}
+
+func TestNewLagMonitor_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, _ := newTestConsumerWrapper(ctrl)
+	cb := func(LagReport) {}
+
+	_, err := NewLagMonitor(nil, time.Second, cb)
+	assert.ErrorIs(t, err, ErrNilClient)
+
+	_, err = NewLagMonitor(w, 0, cb)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+
+	_, err = NewLagMonitor(w, time.Second, nil)
+	assert.ErrorIs(t, err, ErrNilHandler)
+
+	_, err = NewLagMonitor(&fakeConsumer{}, time.Second, cb)
+	assert.ErrorIs(t, err, ErrUnsupportedConsumer)
+
+	_, err = NewLagMonitor(&TracingConsumer{consumerWrapper: w}, time.Second, cb)
+	assert.NoError(t, err)
+}
+
+// fakeConsumer 非本包创建的 Consumer 实现。
+type fakeConsumer struct{}
+
+func (fakeConsumer) Consumer() *kafka.Consumer    { return nil }
+func (fakeConsumer) Health(context.Context) error { return nil }
+func (fakeConsumer) Stats() ConsumerStats         { return ConsumerStats{} }
+func (fakeConsumer) Close() error                 { return nil }
+
+func TestLagMonitor_Collect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, mock := newTestConsumerWrapper(ctrl)
+	topic := "orders"
+	expectTwoPartitionLag(mock, &topic)
+
+	m, err := NewLagMonitor(w, time.Second, func(LagReport) {})
+	require.NoError(t, err)
+
+	_, ok := m.Last()
+	assert.False(t, ok)
+
+	report := m.Collect()
+	require.NoError(t, report.Err)
+	assert.Equal(t, "test-group", report.Group)
+	assert.Equal(t, int64(25), report.TotalLag)
+	require.Len(t, report.Partitions, 2)
+	assert.Equal(t, PartitionLag{Topic: "orders", Partition: 0, Committed: 80, HighWatermark: 100, Lag: 20}, report.Partitions[0])
+
+	last, ok := m.Last()
+	assert.True(t, ok)
+	assert.Equal(t, report.TotalLag, last.TotalLag)
+}
+
+func TestLagMonitor_Collect_PartialFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, mock := newTestConsumerWrapper(ctrl)
+	topic := "orders"
+	assignment := []kafka.TopicPartition{{Topic: &topic, Partition: 0}, {Topic: &topic, Partition: 1}}
+	mock.EXPECT().Assignment().Return(assignment, nil)
+	mock.EXPECT().Committed(assignment, gomock.Any()).Return([]kafka.TopicPartition{
+		{Topic: &topic, Partition: 0, Offset: kafka.OffsetInvalid},
+		{Topic: &topic, Partition: 1, Offset: 10},
+	}, nil)
+	mock.EXPECT().QueryWatermarkOffsets("orders", int32(0), gomock.Any()).Return(int64(0), int64(100), nil)
+	mock.EXPECT().QueryWatermarkOffsets("orders", int32(1), gomock.Any()).Return(int64(0), int64(0), errors.New("timeout"))
+
+	m, err := NewLagMonitor(w, time.Second, func(LagReport) {})
+	require.NoError(t, err)
+
+	report := m.Collect()
+	require.NoError(t, report.Err)
+	assert.Equal(t, int64(0), report.TotalLag)
+	assert.Equal(t, int64(0), report.Partitions[0].Lag, "no committed offset => lag 0")
+	assert.Error(t, report.Partitions[1].Err)
+	assert.Equal(t, int64(-1), report.Partitions[1].HighWatermark)
+}
+
+func TestLagMonitor_Collect_CommittedError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, mock := newTestConsumerWrapper(ctrl)
+	topic := "orders"
+	mock.EXPECT().Assignment().Return([]kafka.TopicPartition{{Topic: &topic, Partition: 0}}, nil)
+	mock.EXPECT().Committed(gomock.Any(), gomock.Any()).Return(nil, errors.New("coordinator unavailable"))
+
+	m, err := NewLagMonitor(w, time.Second, func(LagReport) {})
+	require.NoError(t, err)
+
+	report := m.Collect()
+	assert.Error(t, report.Err)
+	assert.Empty(t, report.Partitions)
+}
+
+func TestLagMonitor_Run_StopsOnContextCancel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, mock := newTestConsumerWrapper(ctrl)
+	mock.EXPECT().Assignment().Return(nil, nil).MinTimes(2)
+
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err := NewLagMonitor(w, 10*time.Millisecond, func(LagReport) {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+
+	err = m.Run(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.GreaterOrEqual(t, calls.Load(), int32(2))
+}
+
+func TestLagMonitor_Run_StopsOnConsumerClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, _ := newTestConsumerWrapper(ctrl)
+	w.closed.Store(true)
+
+	m, err := NewLagMonitor(w, time.Millisecond, func(LagReport) {
+		t.Error("callback should not be called after close")
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.Run(context.Background()), ErrClosed)
+}
+
+func TestLagMonitor_Gauge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	w, mock := newTestConsumerWrapper(ctrl)
+	topic := "orders"
+	expectTwoPartitionLag(mock, &topic)
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	m, err := NewLagMonitor(w, time.Hour, func(LagReport) {
+		var rm metricdata.ResourceMetrics
+		if err := reader.Collect(context.Background(), &rm); err != nil {
+			t.Errorf("collect: %v", err)
+		}
+		gauge := findLagGauge(t, rm)
+		if assert.NotNil(t, gauge) {
+			assert.Len(t, gauge.DataPoints, 2)
+			var total int64
+			for _, dp := range gauge.DataPoints {
+				total += dp.Value
+			}
+			assert.Equal(t, int64(25), total)
+		}
+		cancel()
+	}, WithLagMeterProvider(provider), WithLagTimeout(time.Second))
+	require.NoError(t, err)
+
+	go func() { done <- m.Run(ctx) }()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return")
+	}
+}
+
+func findLagGauge(t *testing.T, rm metricdata.ResourceMetrics) *metricdata.Gauge[int64] {
+	t.Helper()
+	for _, sm := range rm.ScopeMetrics {
+		for _, md := range sm.Metrics {
+			if md.Name == "xkafka.consumer.lag" {
+				if g, ok := md.Data.(metricdata.Gauge[int64]); ok {
+					return &g
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -253,3 +253,18 @@ func (mr *MockkafkaConsumerClientMockRecorder) StoreMessage(msg any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreMessage", reflect.TypeOf((*MockkafkaConsumerClient)(nil).StoreMessage), msg)
 }
+
+// StoreOffsets mocks base method.
+func (m *MockkafkaConsumerClient) StoreOffsets(offsets []kafka.TopicPartition) ([]kafka.TopicPartition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreOffsets", offsets)
+	ret0, _ := ret[0].([]kafka.TopicPartition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StoreOffsets indicates an expected call of StoreOffsets.
+func (mr *MockkafkaConsumerClientMockRecorder) StoreOffsets(offsets any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreOffsets", reflect.TypeOf((*MockkafkaConsumerClient)(nil).StoreOffsets), offsets)
+}
@@ -0,0 +1,67 @@
+package xkafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+)
+
+// Serializer 将业务对象序列化为消息体。
+//
+// 方法签名与 confluent-kafka-go 的 schemaregistry/serde.Serializer 兼容，
+// 可直接传入 avrov2、protobuf、jsonschema 等包创建的 Serializer：
+// 序列化时按 Schema Registry 中的 schema 校验消息，并在消息体前写入 schema ID。
+type Serializer interface {
+	Serialize(topic string, msg any) ([]byte, error)
+}
+
+// Deserializer 将消息体反序列化为业务对象。
+//
+// 方法签名与 confluent-kafka-go 的 schemaregistry/serde.Deserializer 兼容，
+// 按消息体中的 schema ID 从 Schema Registry 获取写入方 schema 进行解码。
+type Deserializer interface {
+	DeserializeInto(topic string, payload []byte, msg any) error
+}
+
+// ProduceValue 使用 WithProducerSerializer 配置的序列化器编码 value，写入 msg.Value 后发送。
+//
+// 序列化失败（包括消息不符合 schema）时返回 ErrSchemaValidation，消息不会被发送，
+// 计入 ProducerStats.Errors。未配置序列化器时返回 ErrSerializerRequired。
+func (w *TracingProducer) ProduceValue(ctx context.Context, msg *kafka.Message, value any, deliveryChan chan kafka.Event) error {
+	if msg == nil {
+		return ErrNilMessage
+	}
+	if w.options.Serializer == nil {
+		return ErrSerializerRequired
+	}
+	// 序列化器可能访问 Schema Registry，关闭后不再发起请求
+	if w.closed.Load() {
+		return ErrClosed
+	}
+
+	payload, err := w.options.Serializer.Serialize(topicFromKafkaMessage(msg), value)
+	if err != nil {
+		w.errors.Add(1)
+		return fmt.Errorf("%w: %w", ErrSchemaValidation, err)
+	}
+	msg.Value = payload
+	return w.Produce(ctx, msg, deliveryChan)
+}
+
+// DecodeValue 使用 WithConsumerDeserializer 配置的反序列化器将 msg.Value 解码到 v。
+//
+// 通常在 MessageHandler 中调用。解码失败（包括 schema 不兼容）时返回 ErrSchemaValidation，
+// 重试无法修复此类错误。未配置反序列化器时返回 ErrDeserializerRequired。
+func (w *TracingConsumer) DecodeValue(msg *kafka.Message, v any) error {
+	if msg == nil {
+		return ErrNilMessage
+	}
+	if w.options.Deserializer == nil {
+		return ErrDeserializerRequired
+	}
+	if err := w.options.Deserializer.DeserializeInto(topicFromKafkaMessage(msg), msg.Value, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrSchemaValidation, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package xkafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde"
+	"github.com/confluentinc/confluent-kafka-go/v2/schemaregistry/serde/avrov2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testOrder struct {
+	ID     string `avro:"id"`
+	Amount int64  `avro:"amount"`
+}
+
+// newTestAvroSerde 基于 mock Schema Registry 创建 avrov2 序列化器和反序列化器。
+func newTestAvroSerde(t *testing.T) (schemaregistry.Client, *avrov2.Serializer, *avrov2.Deserializer) {
+	t.Helper()
+	client, err := schemaregistry.NewClient(schemaregistry.NewConfig("mock://" + t.Name()))
+	require.NoError(t, err)
+
+	ser, err := avrov2.NewSerializer(client, serde.ValueSerde, avrov2.NewSerializerConfig())
+	require.NoError(t, err)
+	deser, err := avrov2.NewDeserializer(client, serde.ValueSerde, avrov2.NewDeserializerConfig())
+	require.NoError(t, err)
+	return client, ser, deser
+}
+
+func TestSchema_AvroRoundTrip(t *testing.T) {
+	_, ser, deser := newTestAvroSerde(t)
+
+	ctrl := gomock.NewController(t)
+	tp, pmock := newTestTracingProducer(ctrl)
+	WithProducerSerializer(ser)(tp.options)
+	tc, _ := newTestTracingConsumer(ctrl)
+	WithConsumerDeserializer(deser)(tc.options)
+
+	var produced *kafka.Message
+	pmock.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(func(m *kafka.Message, _ chan kafka.Event) error {
+		produced = m
+		return nil
+	})
+
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}}
+	require.NoError(t, tp.ProduceValue(context.Background(), msg, &testOrder{ID: "o-1", Amount: 42}, nil))
+	require.NotNil(t, produced)
+	// Confluent wire format: magic byte 0 + 4 字节 schema ID
+	require.Greater(t, len(produced.Value), 5)
+	assert.Equal(t, byte(0), produced.Value[0])
+
+	var got testOrder
+	require.NoError(t, tc.DecodeValue(produced, &got))
+	assert.Equal(t, testOrder{ID: "o-1", Amount: 42}, got)
+}
+
+func TestSchema_AvroIncompatibleMessage(t *testing.T) {
+	client, _, _ := newTestAvroSerde(t)
+	_, err := client.Register("orders-value", schemaregistry.SchemaInfo{
+		Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"amount","type":"long"}]}`,
+	}, false)
+	require.NoError(t, err)
+
+	// 使用已注册的最新 schema 而非自动注册，模拟生产环境的 schema 约束
+	conf := avrov2.NewSerializerConfig()
+	conf.AutoRegisterSchemas = false
+	conf.UseLatestVersion = true
+	ser, err := avrov2.NewSerializer(client, serde.ValueSerde, conf)
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	tp, pmock := newTestTracingProducer(ctrl)
+	WithProducerSerializer(ser)(tp.options)
+	pmock.EXPECT().Len().Return(0).AnyTimes()
+
+	type badOrder struct {
+		ID string `avro:"id"`
+	}
+	topic := "orders"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}}
+	err = tp.ProduceValue(context.Background(), msg, &badOrder{ID: "o-1"}, nil)
+	require.ErrorIs(t, err, ErrSchemaValidation)
+	assert.Nil(t, msg.Value)
+	assert.Equal(t, int64(1), tp.Stats().Errors)
+}
+
+type fakeSerializer struct{ err error }
+
+func (s fakeSerializer) Serialize(_ string, _ any) ([]byte, error) { return []byte("x"), s.err }
+
+type fakeDeserializer struct{ err error }
+
+func (d fakeDeserializer) DeserializeInto(_ string, _ []byte, _ any) error { return d.err }
+
+func TestTracingProducer_ProduceValue_Errors(t *testing.T) {
+	topic := "t"
+	newMsg := func() *kafka.Message { return &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}} }
+
+	t.Run("nil message", func(t *testing.T) {
+		tp, _ := newTestTracingProducer(gomock.NewController(t))
+		WithProducerSerializer(fakeSerializer{})(tp.options)
+		assert.ErrorIs(t, tp.ProduceValue(context.Background(), nil, "v", nil), ErrNilMessage)
+	})
+
+	t.Run("serializer required", func(t *testing.T) {
+		tp, _ := newTestTracingProducer(gomock.NewController(t))
+		assert.ErrorIs(t, tp.ProduceValue(context.Background(), newMsg(), "v", nil), ErrSerializerRequired)
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		tp, _ := newTestTracingProducer(gomock.NewController(t))
+		WithProducerSerializer(fakeSerializer{})(tp.options)
+		tp.closed.Store(true)
+		assert.ErrorIs(t, tp.ProduceValue(context.Background(), newMsg(), "v", nil), ErrClosed)
+	})
+
+	t.Run("serialize error", func(t *testing.T) {
+		tp, _ := newTestTracingProducer(gomock.NewController(t))
+		cause := errors.New("boom")
+		WithProducerSerializer(fakeSerializer{err: cause})(tp.options)
+		err := tp.ProduceValue(context.Background(), newMsg(), "v", nil)
+		assert.ErrorIs(t, err, ErrSchemaValidation)
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("nil option ignored", func(t *testing.T) {
+		opts := defaultProducerOptions()
+		WithProducerSerializer(nil)(opts)
+		assert.Nil(t, opts.Serializer)
+	})
+}
+
+func TestTracingConsumer_DecodeValue_Errors(t *testing.T) {
+	topic := "t"
+	msg := &kafka.Message{TopicPartition: kafka.TopicPartition{Topic: &topic}, Value: []byte("x")}
+
+	t.Run("nil message", func(t *testing.T) {
+		tc, _ := newTestTracingConsumer(gomock.NewController(t))
+		WithConsumerDeserializer(fakeDeserializer{})(tc.options)
+		assert.ErrorIs(t, tc.DecodeValue(nil, new(string)), ErrNilMessage)
+	})
+
+	t.Run("deserializer required", func(t *testing.T) {
+		tc, _ := newTestTracingConsumer(gomock.NewController(t))
+		assert.ErrorIs(t, tc.DecodeValue(msg, new(string)), ErrDeserializerRequired)
+	})
+
+	t.Run("deserialize error", func(t *testing.T) {
+		tc, _ := newTestTracingConsumer(gomock.NewController(t))
+		WithConsumerDeserializer(fakeDeserializer{err: errors.New("unknown schema id")})(tc.options)
+		assert.ErrorIs(t, tc.DecodeValue(msg, new(string)), ErrSchemaValidation)
+	})
+
+	t.Run("nil option ignored", func(t *testing.T) {
+		opts := defaultConsumerOptions()
+		WithConsumerDeserializer(nil)(opts)
+		assert.Nil(t, opts.Deserializer)
+	})
+}
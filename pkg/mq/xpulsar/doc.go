@@ -19,6 +19,26 @@
 // 两者均要求非 nil 的 producer/consumer 参数，否则返回 ErrNilProducer/ErrNilConsumer。
 // WrapProducer 的 topic 参数为空时自动从 producer.Topic() 获取。
 //
+// # 分区路由
+//
+// 分区 Topic 需要按 key 保序（如同一用户的消息按序处理）时，使用 ProducerOptionsBuilder
+// 配置路由，再交给 NewTracingProducer；追踪注入发生在路由之前，不受路由方式影响：
+//
+//	opts := xpulsar.NewProducerOptionsBuilder("persistent://public/default/orders").
+//	    WithKeyHashRouting(xpulsar.PropertyKey("user_id"), pulsar.Murmur3_32Hash).
+//	    Build()
+//	producer, err := xpulsar.NewTracingProducer(client, opts, tracer, observer)
+//
+// KeyHashRouter 默认按 OrderingKey/Key 取 key，哈希结果与 Pulsar 默认路由一致；
+// 没有 key 的消息轮询分布，不保证顺序。完全自定义的路由使用 WithMessageRouter。
+// WrapProducer 包装的是已创建的 Producer，路由需在创建时通过 ProducerOptions 配置。
+//
+// 路由只保证同一 key 落在同一分区，消费端能否按序处理取决于订阅类型：
+//   - Exclusive/Failover：每个分区只有一个活跃消费者，分区内有序，同一 key 有序
+//   - Shared：同一分区的消息分发给多个消费者，不保证顺序（ConsumerOptionsBuilder 的默认值）
+//   - Key_Shared：按消息的 OrderingKey/Key 分发，同一 key 始终由同一消费者处理；
+//     它读取的是消息本身的 key，用 PropertyKey 路由时需同时设置 Key 才能保序
+//
 // # DLQ 配置
 //
 // 使用 DLQBuilder 构建死信队列策略，通过 WithMaxDeliveries/WithDeadLetterTopic 等方法配置。
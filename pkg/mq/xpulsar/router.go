@@ -0,0 +1,123 @@
+package xpulsar
+
+import (
+	"sync/atomic"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/spaolacci/murmur3"
+)
+
+// MessageRouter 自定义分区路由函数，与 pulsar.ProducerOptions.MessageRouter 签名一致。
+// 返回值为分区下标，取值范围 [0, NumPartitions)。
+type MessageRouter = func(msg *pulsar.ProducerMessage, metadata pulsar.TopicMetadata) int
+
+// KeyFunc 从消息中提取路由 key。返回空字符串表示该消息没有路由 key。
+type KeyFunc func(msg *pulsar.ProducerMessage) string
+
+// MessageKey 返回消息的 OrderingKey，未设置时返回 Key。
+// 取值顺序与 Pulsar 默认路由一致，是 KeyHashRouter 的默认 KeyFunc。
+func MessageKey(msg *pulsar.ProducerMessage) string {
+	if msg.OrderingKey != "" {
+		return msg.OrderingKey
+	}
+	return msg.Key
+}
+
+// PropertyKey 返回以消息属性 name 的值作为路由 key 的 KeyFunc。
+// 适用于路由字段（如用户 ID）已放在 Properties 中、不希望改写 Key 的场景。
+func PropertyKey(name string) KeyFunc {
+	return func(msg *pulsar.ProducerMessage) string {
+		return msg.Properties[name]
+	}
+}
+
+// KeyHashRouter 返回按 key 哈希选择分区的 MessageRouter，相同 key 的消息始终路由到同一分区。
+//
+// keyFn 为 nil 时使用 MessageKey。scheme 与 pulsar.ProducerOptions.HashingScheme 含义相同，
+// 哈希结果与 Pulsar 默认路由及 Java 客户端一致，切换路由方式不会改变已有 key 的分区归属；
+// 未知取值按 JavaStringHash 处理。
+//
+// 没有 key 的消息按轮询分布到各分区，不保证顺序。
+//
+// 设计决策: Pulsar 默认路由同样按 Key/OrderingKey 哈希，但无法从其他字段取 key，
+// 无 key 消息还会为了批量发送粘在同一分区一段时间。KeyHashRouter 把"从哪里取 key"
+// 交给 KeyFunc，路由本身无锁，只有无 key 消息才使用一个原子计数器。
+func KeyHashRouter(keyFn KeyFunc, scheme pulsar.HashingScheme) MessageRouter {
+	if keyFn == nil {
+		keyFn = MessageKey
+	}
+	hash := hashFunc(scheme)
+	var next atomic.Uint32
+	return func(msg *pulsar.ProducerMessage, metadata pulsar.TopicMetadata) int {
+		n := metadata.NumPartitions()
+		if n <= 1 {
+			return 0
+		}
+		if key := keyFn(msg); key != "" {
+			return int(hash(key) % n)
+		}
+		return int((next.Add(1) - 1) % n)
+	}
+}
+
+// hashFunc 返回 scheme 对应的哈希函数
+func hashFunc(scheme pulsar.HashingScheme) func(string) uint32 {
+	if scheme == pulsar.Murmur3_32Hash {
+		return murmur3Hash
+	}
+	return javaStringHash
+}
+
+// javaStringHash 与 Pulsar Go 客户端的 JavaStringHash 实现一致（按字节计算）
+func javaStringHash(s string) uint32 {
+	var h uint32
+	for i := range len(s) {
+		h = 31*h + uint32(s[i])
+	}
+	return h
+}
+
+// murmur3Hash 与 Pulsar 客户端的 Murmur3_32Hash 实现一致（截断为非负 int32 以兼容 Java 客户端）
+func murmur3Hash(s string) uint32 {
+	return murmur3.Sum32([]byte(s)) & 0x7fffffff
+}
+
+// ProducerOptionsBuilder Pulsar Producer 配置构建器
+// 提供便捷的方法来配置分区路由
+type ProducerOptionsBuilder struct {
+	opts pulsar.ProducerOptions
+}
+
+// NewProducerOptionsBuilder 创建 Producer 配置构建器。
+func NewProducerOptionsBuilder(topic string) *ProducerOptionsBuilder {
+	return &ProducerOptionsBuilder{
+		opts: pulsar.ProducerOptions{Topic: topic},
+	}
+}
+
+// WithMessageRouter 设置自定义分区路由。router 为 nil 时保持 Pulsar 默认路由。
+func (b *ProducerOptionsBuilder) WithMessageRouter(router MessageRouter) *ProducerOptionsBuilder {
+	if router != nil {
+		b.opts.MessageRouter = router
+	}
+	return b
+}
+
+// WithKeyHashRouting 按 keyFn 提取的 key 哈希路由（见 KeyHashRouter），
+// 同时把 HashingScheme 设为 scheme，保持配置自洽。
+// keyFn 为 nil 时使用 MessageKey。
+func (b *ProducerOptionsBuilder) WithKeyHashRouting(keyFn KeyFunc, scheme pulsar.HashingScheme) *ProducerOptionsBuilder {
+	b.opts.HashingScheme = scheme
+	b.opts.MessageRouter = KeyHashRouter(keyFn, scheme)
+	return b
+}
+
+// Build 构建 pulsar.ProducerOptions
+func (b *ProducerOptionsBuilder) Build() pulsar.ProducerOptions {
+	return b.opts
+}
+
+// Options 返回配置指针，用于进一步自定义
+func (b *ProducerOptionsBuilder) Options() *pulsar.ProducerOptions {
+	return &b.opts
+}
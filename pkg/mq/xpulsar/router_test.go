@@ -0,0 +1,127 @@
+package xpulsar
+
+import (
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partitions 实现 pulsar.TopicMetadata
+type partitions uint32
+
+func (p partitions) NumPartitions() uint32 { return uint32(p) }
+
+func TestMessageKey(t *testing.T) {
+	assert.Equal(t, "", MessageKey(&pulsar.ProducerMessage{}))
+	assert.Equal(t, "k", MessageKey(&pulsar.ProducerMessage{Key: "k"}))
+	assert.Equal(t, "o", MessageKey(&pulsar.ProducerMessage{Key: "k", OrderingKey: "o"}))
+}
+
+func TestPropertyKey(t *testing.T) {
+	keyFn := PropertyKey("user_id")
+	assert.Equal(t, "u1", keyFn(&pulsar.ProducerMessage{Properties: map[string]string{"user_id": "u1"}}))
+	assert.Equal(t, "", keyFn(&pulsar.ProducerMessage{Key: "k"}))
+}
+
+func TestHashFunc(t *testing.T) {
+	// 与 Java String.hashCode() 及 Pulsar 客户端的取值一致
+	assert.Equal(t, uint32(99162322), hashFunc(pulsar.JavaStringHash)("hello"))
+	assert.Equal(t, uint32(613153351), hashFunc(pulsar.Murmur3_32Hash)("hello"))
+	assert.Equal(t, uint32(99162322), hashFunc(pulsar.HashingScheme(99))("hello"), "unknown scheme falls back to JavaStringHash")
+	assert.LessOrEqual(t, murmur3Hash("any key"), uint32(0x7fffffff))
+}
+
+func TestKeyHashRouter(t *testing.T) {
+	t.Run("SameKeySamePartition", func(t *testing.T) {
+		router := KeyHashRouter(nil, pulsar.JavaStringHash)
+		msg := &pulsar.ProducerMessage{Key: "hello"}
+		want := int(uint32(99162322) % 7)
+		for range 10 {
+			assert.Equal(t, want, router(msg, partitions(7)))
+		}
+	})
+
+	t.Run("OrderingKeyFirst", func(t *testing.T) {
+		router := KeyHashRouter(nil, pulsar.Murmur3_32Hash)
+		assert.Equal(t,
+			router(&pulsar.ProducerMessage{OrderingKey: "user-1"}, partitions(16)),
+			router(&pulsar.ProducerMessage{Key: "other", OrderingKey: "user-1"}, partitions(16)))
+	})
+
+	t.Run("CustomKeyFunc", func(t *testing.T) {
+		router := KeyHashRouter(PropertyKey("user_id"), pulsar.JavaStringHash)
+		msg := &pulsar.ProducerMessage{Properties: map[string]string{"user_id": "hello"}}
+		assert.Equal(t, int(uint32(99162322)%5), router(msg, partitions(5)))
+	})
+
+	t.Run("NoKeyRoundRobin", func(t *testing.T) {
+		router := KeyHashRouter(nil, pulsar.JavaStringHash)
+		var got []int
+		for range 6 {
+			got = append(got, router(&pulsar.ProducerMessage{}, partitions(3)))
+		}
+		assert.Equal(t, []int{0, 1, 2, 0, 1, 2}, got)
+	})
+
+	t.Run("SinglePartition", func(t *testing.T) {
+		router := KeyHashRouter(nil, pulsar.JavaStringHash)
+		assert.Equal(t, 0, router(&pulsar.ProducerMessage{Key: "hello"}, partitions(1)))
+		assert.Equal(t, 0, router(&pulsar.ProducerMessage{Key: "hello"}, partitions(0)))
+	})
+
+	t.Run("InRange", func(t *testing.T) {
+		router := KeyHashRouter(nil, pulsar.Murmur3_32Hash)
+		for _, key := range []string{"a", "b", "user-42", "订单-1", "\xff\xfe"} {
+			p := router(&pulsar.ProducerMessage{Key: key}, partitions(4))
+			assert.GreaterOrEqual(t, p, 0)
+			assert.Less(t, p, 4)
+		}
+	})
+}
+
+func TestProducerOptionsBuilder(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		opts := NewProducerOptionsBuilder("orders").Build()
+		assert.Equal(t, "orders", opts.Topic)
+		assert.Nil(t, opts.MessageRouter)
+	})
+
+	t.Run("WithMessageRouter", func(t *testing.T) {
+		opts := NewProducerOptionsBuilder("orders").
+			WithMessageRouter(func(*pulsar.ProducerMessage, pulsar.TopicMetadata) int { return 2 }).
+			Build()
+		require.NotNil(t, opts.MessageRouter)
+		assert.Equal(t, 2, opts.MessageRouter(&pulsar.ProducerMessage{}, partitions(4)))
+	})
+
+	t.Run("WithMessageRouter_Nil", func(t *testing.T) {
+		b := NewProducerOptionsBuilder("orders").WithKeyHashRouting(nil, pulsar.JavaStringHash)
+		opts := b.WithMessageRouter(nil).Build()
+		assert.NotNil(t, opts.MessageRouter, "nil router keeps the previous setting")
+	})
+
+	t.Run("WithKeyHashRouting", func(t *testing.T) {
+		opts := NewProducerOptionsBuilder("orders").
+			WithKeyHashRouting(PropertyKey("user_id"), pulsar.Murmur3_32Hash).
+			Build()
+		assert.Equal(t, pulsar.Murmur3_32Hash, opts.HashingScheme)
+		require.NotNil(t, opts.MessageRouter)
+		msg := &pulsar.ProducerMessage{Properties: map[string]string{"user_id": "hello"}}
+		assert.Equal(t, int(uint32(613153351)%8), opts.MessageRouter(msg, partitions(8)))
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		b := NewProducerOptionsBuilder("orders")
+		b.Options().Name = "order-producer"
+		assert.Equal(t, "order-producer", b.Build().Name)
+	})
+
+	t.Run("WithTracingProducer", func(t *testing.T) {
+		opts := NewProducerOptionsBuilder("orders").WithKeyHashRouting(nil, pulsar.JavaStringHash).Build()
+		producer, err := NewTracingProducer(&mockClient{}, opts, NoopTracer{}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "orders", producer.topic)
+	})
+}
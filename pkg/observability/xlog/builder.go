@@ -45,9 +45,14 @@ type Builder struct {
 	deploymentType xctx.DeploymentType // 部署类型（作为固定属性）
 	replaceAttr    ReplaceAttrFunc     // 属性替换函数（用于治理）
 	rotator        xrotate.Rotator
-	onError        func(error) // 内部错误回调（Handler.Handle 失败时）
+	onError        func(error)     // 内部错误回调（Handler.Handle 失败时）
+	sampling       *SamplingConfig // 日志采样配置（nil 表示不采样）
 	err            error
 	built          bool // Build() 已调用，防止重复构建
+
+	// 字段白名单/黑名单（nil 表示不启用）
+	fieldAllow *fieldSet
+	fieldDeny  *fieldSet
 }
 
 // New 创建配置构建器
@@ -240,6 +245,32 @@ func (b *Builder) SetReplaceAttr(fn ReplaceAttrFunc) *Builder {
 	return b
 }
 
+// SetSampling 启用日志采样，并周期性输出被抑制日志的汇总
+//
+// 用于抑制高频重复日志（如故障期间的错误风暴），同时保证被抑制的日志可见：
+// 每个 cfg.ReportInterval 输出一条 "xlog: logs suppressed by sampling" 汇总日志，
+// 包含被抑制的消息及次数。详见 SamplingConfig 与 SamplingHandler。
+//
+// 汇总 goroutine 由 Build 返回的 cleanup 停止，停止前会输出最后一次汇总。
+//
+// 示例：
+//
+//	logger, cleanup, _ := xlog.New().
+//		SetSampling(xlog.SamplingConfig{First: 10, Thereafter: 100}).
+//		Build()
+//	defer cleanup()
+func (b *Builder) SetSampling(cfg SamplingConfig) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if cfg.Tick < 0 || cfg.First < 0 || cfg.Thereafter < 0 || cfg.ReportInterval < 0 {
+		b.err = ErrInvalidSamplingConfig
+		return b
+	}
+	b.sampling = &cfg
+	return b
+}
+
 // SetDeploymentType 设置部署类型（作为固定属性添加到每条日志）
 //
 // 部署类型在 Build 时通过 handler.WithAttrs 注入，
@@ -322,8 +353,11 @@ func (b *Builder) Build() (LoggerWithLevel, func() error, error) {
 		AddSource: b.addSource,
 	}
 
-	// 设置属性替换函数（日志治理）
-	if b.replaceAttr != nil {
+	// 设置属性替换函数（日志治理），字段过滤在其之后执行
+	if b.fieldAllow != nil || b.fieldDeny != nil {
+		filter := &fieldFilter{allow: b.fieldAllow, deny: b.fieldDeny}
+		opts.ReplaceAttr = filter.wrap(b.replaceAttr)
+	} else if b.replaceAttr != nil {
 		opts.ReplaceAttr = b.replaceAttr
 	}
 
@@ -352,6 +386,18 @@ func (b *Builder) Build() (LoggerWithLevel, func() error, error) {
 		})
 	}
 
+	// 采样位于最外层：被抑制的日志不再经过 enrich 等后续处理，
+	// 汇总日志则经过完整的处理链（含部署类型等固定属性）
+	var sampler *SamplingHandler
+	if b.sampling != nil {
+		var err error
+		sampler, err = NewSamplingHandler(handler, *b.sampling)
+		if err != nil {
+			return nil, nil, err
+		}
+		handler = sampler
+	}
+
 	// 创建 logger
 	// 初始化共享指针，确保派生 logger (With/WithGroup) 能正确共享状态
 	logger := &xlogger{
@@ -364,7 +410,7 @@ func (b *Builder) Build() (LoggerWithLevel, func() error, error) {
 	}
 
 	// 创建 cleanup 函数
-	cleanup := b.createCleanup()
+	cleanup := b.createCleanup(sampler)
 	// 资源所有权已转移到 cleanup，清空 builder 指针避免重复 Build() 误关闭。
 	b.rotator = nil
 
@@ -383,13 +429,18 @@ func (b *Builder) closeRotator() {
 }
 
 // createCleanup 创建清理函数
-func (b *Builder) createCleanup() func() error {
+//
+// 先停止采样汇总（最后一次汇总仍需写入 output），再关闭 rotator。
+func (b *Builder) createCleanup(sampler *SamplingHandler) func() error {
 	var once sync.Once
 	rotator := b.rotator
 
 	return func() error {
 		var err error
 		once.Do(func() {
+			if sampler != nil {
+				_ = sampler.Close() //nolint:errcheck // Close 恒返回 nil
+			}
 			if rotator != nil {
 				err = rotator.Close()
 			}
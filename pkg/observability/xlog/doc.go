@@ -6,6 +6,7 @@
 //   - 自动从 context 注入 trace_id、tenant_id 等（EnrichHandler，默认启用）
 //   - 动态级别调整（运行时热更新）
 //   - 部署类型固定属性
+//   - 高频日志采样与抑制汇总（SamplingHandler，默认关闭）
 //   - 全局 Logger 便利函数
 //   - 延迟求值（Lazy* 系列函数）
 //
@@ -14,11 +15,16 @@
 // 使用 Builder 模式（first-error-wins：遇到第一个配置错误后，后续 Set 操作被跳过）。
 // Builder 为一次性使用：调用 [Builder.Build] 后不可复用，需通过 [New] 创建新实例。
 // Builder 方法：SetLevel、SetFormat、SetOutput、SetRotation、SetEnrich、
-// SetDeploymentType、SetOnError、SetReplaceAttr。
+// SetDeploymentType、SetOnError、SetReplaceAttr、SetFieldAllowlist、SetFieldDenylist、SetSampling。
 //
 // [SetReplaceAttr] 支持日志治理场景（字段重命名、敏感信息脱敏、字段过滤）。
 // xlog 提供机制而非策略——无内置敏感字段黑名单，由调用方按业务需求配置脱敏规则。
 //
+// [Builder.SetFieldAllowlist] / [Builder.SetFieldDenylist] 在 ReplaceAttr 之上提供声明式字段过滤，
+// 适用于"日志中绝不能出现某字段"的合规约束。名称匹配任意层级的同名字段或分组，
+// "a.b" 形式的路径匹配完整路径，大小写不敏感，基于 map 查找，常见小写字段名不产生分配。
+// 过滤作用于 SetReplaceAttr 的输出，重命名无法绕过黑名单。
+//
 // # 全局 Logger
 //
 // 适用于脚手架、小工具等简单场景，服务端推荐依赖注入。
@@ -59,6 +65,18 @@
 //
 // 派生 logger 共享父级的 LevelVar，动态级别变更会同步生效。
 //
+// # 日志采样
+//
+// [Builder.SetSampling] 或 [NewSamplingHandler] 按 (级别, 消息) 对日志采样：
+// 每个 Tick 窗口内前 First 条全部输出，之后每 Thereafter 条输出 1 条。
+// 被抑制的日志不会静默丢失，每个 ReportInterval 输出一条同级别的汇总日志
+// （msg 为 "xlog: logs suppressed by sampling"，属性 sampled_msg、suppressed、window），
+// 可据此感知真实日志量。热路径仅有原子操作，无锁、无内存分配。
+//
+// 采样可在运行时调整：[SamplingHandler.SetPaused] 临时关闭采样以抓取完整日志，
+// [SamplingHandler.SetRate] 调整 First/Thereafter。Builder 构建的 logger 通过 [SamplerOf] 获取采样器，
+// 可交给 xdbg 的 logsample 命令在排查期间临时调整并自动恢复。
+//
 // # EnrichHandler 注意事项
 //
 // 当对启用了 enrich 的 logger 调用 WithGroup 时，trace_id、tenant_id 等注入字段
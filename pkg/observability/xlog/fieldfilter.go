@@ -0,0 +1,189 @@
+package xlog
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+)
+
+// ErrEmptyFieldList 表示字段白名单/黑名单为空（或仅包含空字符串）
+var ErrEmptyFieldList = errors.New("xlog: field list is empty")
+
+// fieldSet 字段名集合，预先小写化，匹配时大小写不敏感
+//
+// 条目分两类：
+//   - 不含 "." 的名称：匹配任意层级的同名字段或分组（"card_number" 同时匹配
+//     顶层的 card_number 和 payment 分组下的 card_number）
+//   - 含 "." 的路径：匹配完整路径（"payment.card_number" 只匹配 payment 分组下的字段）
+type fieldSet struct {
+	names map[string]struct{}
+	paths map[string]struct{}
+}
+
+// newFieldSet 构建字段集合，忽略空字符串；全部为空时返回 nil
+func newFieldSet(fields []string) *fieldSet {
+	s := &fieldSet{names: make(map[string]struct{}), paths: make(map[string]struct{})}
+	for _, f := range fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		switch {
+		case f == "":
+			continue
+		case strings.Contains(f, "."):
+			s.paths[f] = struct{}{}
+		default:
+			s.names[f] = struct{}{}
+		}
+	}
+	if len(s.names) == 0 && len(s.paths) == 0 {
+		return nil
+	}
+	return s
+}
+
+// match 判断字段本身或其所在的任一分组是否命中集合
+func (s *fieldSet) match(groups []string, key string) bool {
+	if s.hasName(key) {
+		return true
+	}
+	for _, g := range groups {
+		if s.hasName(g) {
+			return true
+		}
+	}
+	if len(s.paths) == 0 || len(groups) == 0 {
+		return false
+	}
+	// 逐级拼接路径：命中 "a.b" 时 a.b 分组下的全部字段都算命中
+	path := lowerASCII(groups[0])
+	for _, g := range groups[1:] {
+		path += "." + lowerASCII(g)
+		if _, ok := s.paths[path]; ok {
+			return true
+		}
+	}
+	_, ok := s.paths[path+"."+lowerASCII(key)]
+	return ok
+}
+
+// hasName 大小写不敏感地查找名称
+func (s *fieldSet) hasName(name string) bool {
+	_, ok := s.names[lowerASCII(name)]
+	return ok
+}
+
+// lowerASCII 返回小写形式；已是小写时直接返回原串，避免热路径分配
+func lowerASCII(s string) string {
+	for i := range len(s) {
+		if c := s[i]; c >= 'A' && c <= 'Z' {
+			return strings.ToLower(s)
+		}
+	}
+	return s
+}
+
+// fieldFilter 字段白名单/黑名单过滤器
+type fieldFilter struct {
+	allow *fieldSet // nil 表示不启用白名单
+	deny  *fieldSet // nil 表示不启用黑名单
+}
+
+// keep 判断字段是否保留
+//
+// 黑名单优先于白名单；白名单模式下顶层内置字段（time、level、msg、source）始终保留。
+func (f *fieldFilter) keep(groups []string, key string) bool {
+	if f.deny != nil && f.deny.match(groups, key) {
+		return false
+	}
+	if f.allow == nil {
+		return true
+	}
+	if len(groups) == 0 && isBuiltinKey(key) {
+		return true
+	}
+	return f.allow.match(groups, key)
+}
+
+// isBuiltinKey 是否为 slog 内置字段
+func isBuiltinKey(key string) bool {
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		return true
+	}
+	return false
+}
+
+// wrap 将过滤器组合到用户的 ReplaceAttr 之后
+//
+// 设计决策: 过滤作用于用户 ReplaceAttr 的输出而非输入。
+// 若先过滤再替换，用户函数把 "cc" 重命名为 "card_number" 就能绕过黑名单；
+// 后置过滤保证最终输出的字段名一定满足白名单/黑名单约束。
+func (f *fieldFilter) wrap(next ReplaceAttrFunc) ReplaceAttrFunc {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if next != nil {
+			a = next(groups, a)
+		}
+		if a.Key == "" || !f.keep(groups, a.Key) {
+			return slog.Attr{}
+		}
+		return a
+	}
+}
+
+// SetFieldAllowlist 设置字段白名单：只输出列出的字段
+//
+// 基于 ReplaceAttr 实现的声明式字段过滤，适用于"日志只允许出现指定字段"的合规场景。
+// 匹配规则（大小写不敏感）：
+//   - "user_id"：匹配任意层级的 user_id 字段；若为分组名，则分组下全部字段保留
+//   - "request.method"：只匹配 request 分组下的 method 字段
+//
+// 顶层内置字段（time、level、msg、source）始终保留。注意 EnrichHandler 注入的
+// trace_id、tenant_id 等字段同样受白名单约束，需要时应一并列出。
+//
+// 与 SetFieldDenylist 同时设置时黑名单优先；与 SetReplaceAttr 同时设置时，
+// 过滤作用于 ReplaceAttr 处理后的字段。多次调用时以最后一次为准。
+// fields 为空（或只含空字符串）时返回 ErrEmptyFieldList。
+//
+// 示例：
+//
+//	logger, cleanup, _ := xlog.New().
+//		SetFieldAllowlist("trace_id", "user_id", "request").
+//		Build()
+func (b *Builder) SetFieldAllowlist(fields ...string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	set := newFieldSet(fields)
+	if set == nil {
+		b.err = ErrEmptyFieldList
+		return b
+	}
+	b.fieldAllow = set
+	return b
+}
+
+// SetFieldDenylist 设置字段黑名单：列出的字段永不输出
+//
+// 用于"日志中绝不能出现信用卡号字段"这类强约束。匹配规则同 SetFieldAllowlist：
+// 不含 "." 的名称匹配任意层级的同名字段或分组，含 "." 的路径匹配完整路径。
+// 命中的字段被整体移除（而非脱敏），需要保留字段仅隐藏值时应使用 SetReplaceAttr。
+//
+// 黑名单只按字段名匹配，不检查字段值：把卡号拼进 msg 或其他字段值中无法被拦截。
+// 多次调用时以最后一次为准。fields 为空（或只含空字符串）时返回 ErrEmptyFieldList。
+//
+// 示例：
+//
+//	logger, cleanup, _ := xlog.New().
+//		SetFieldDenylist("card_number", "cvv", "password").
+//		Build()
+func (b *Builder) SetFieldDenylist(fields ...string) *Builder {
+	if b.err != nil {
+		return b
+	}
+	set := newFieldSet(fields)
+	if set == nil {
+		b.err = ErrEmptyFieldList
+		return b
+	}
+	b.fieldDeny = set
+	return b
+}
@@ -0,0 +1,156 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/omeyang/xkit/pkg/observability/xlog"
+)
+
+// logFiltered 构建 JSON 格式的 logger，输出一条日志并解析为 map
+func logFiltered(t *testing.T, b *xlog.Builder, attrs ...slog.Attr) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, cleanup, err := b.SetOutput(&buf).SetFormat("json").SetEnrich(false).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	testCleanup(t, cleanup)
+
+	logger.Info(context.Background(), "payment", attrs...)
+
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("invalid json output %q: %v", buf.String(), err)
+	}
+	return m
+}
+
+func TestBuilder_SetFieldDenylist(t *testing.T) {
+	m := logFiltered(t, xlog.New().SetFieldDenylist("card_number", "order.cvv", " "),
+		slog.String("card_number", "4111111111111111"),
+		slog.String("Card_Number", "4111111111111111"),
+		slog.String("user", "alice"),
+		slog.Group("payment", slog.String("card_number", "4111"), slog.Int("amount", 10)),
+		slog.Group("order", slog.String("cvv", "123"), slog.String("id", "o-1")),
+		slog.Group("vault", slog.Group("card_number", slog.String("last4", "1111"))),
+	)
+
+	if _, ok := m["card_number"]; ok {
+		t.Errorf("card_number must be removed: %v", m)
+	}
+	if _, ok := m["Card_Number"]; ok {
+		t.Errorf("matching must be case-insensitive: %v", m)
+	}
+	if m["user"] != "alice" {
+		t.Errorf("user should be kept: %v", m)
+	}
+	payment, _ := m["payment"].(map[string]any)
+	if _, ok := payment["card_number"]; ok || payment["amount"] != float64(10) {
+		t.Errorf("nested card_number must be removed, amount kept: %v", payment)
+	}
+	order, _ := m["order"].(map[string]any)
+	if _, ok := order["cvv"]; ok || order["id"] != "o-1" {
+		t.Errorf("path order.cvv must be removed, id kept: %v", order)
+	}
+	if _, ok := m["vault"]; ok {
+		t.Errorf("denied group must be removed entirely: %v", m)
+	}
+	if m["msg"] != "payment" || m["level"] == nil || m["time"] == nil {
+		t.Errorf("builtin fields should be kept: %v", m)
+	}
+}
+
+func TestBuilder_SetFieldAllowlist(t *testing.T) {
+	m := logFiltered(t, xlog.New().SetFieldAllowlist("user", "request", "order.id"),
+		slog.String("user", "alice"),
+		slog.String("password", "secret"),
+		slog.Group("request", slog.String("method", "GET"), slog.String("path", "/")),
+		slog.Group("order", slog.String("id", "o-1"), slog.String("note", "x")),
+	)
+
+	if m["user"] != "alice" {
+		t.Errorf("allowed field missing: %v", m)
+	}
+	if _, ok := m["password"]; ok {
+		t.Errorf("non-allowed field must be removed: %v", m)
+	}
+	request, _ := m["request"].(map[string]any)
+	if request["method"] != "GET" || request["path"] != "/" {
+		t.Errorf("allowed group should be kept entirely: %v", request)
+	}
+	order, _ := m["order"].(map[string]any)
+	if _, ok := order["note"]; ok || order["id"] != "o-1" {
+		t.Errorf("only order.id should be kept: %v", order)
+	}
+	if m["msg"] != "payment" || m["level"] == nil || m["time"] == nil {
+		t.Errorf("builtin fields should always be kept: %v", m)
+	}
+}
+
+func TestBuilder_FieldFilter_DenyOverridesAllow(t *testing.T) {
+	m := logFiltered(t, xlog.New().
+		SetFieldAllowlist("payment").
+		SetFieldDenylist("card_number"),
+		slog.Group("payment", slog.String("card_number", "4111"), slog.Int("amount", 10)),
+	)
+	payment, _ := m["payment"].(map[string]any)
+	if _, ok := payment["card_number"]; ok || payment["amount"] != float64(10) {
+		t.Errorf("denylist must take precedence: %v", payment)
+	}
+}
+
+func TestBuilder_FieldFilter_AppliedAfterReplaceAttr(t *testing.T) {
+	m := logFiltered(t, xlog.New().
+		SetFieldDenylist("card_number").
+		SetReplaceAttr(func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == "cc" {
+				return slog.String("card_number", a.Value.String())
+			}
+			return a
+		}),
+		slog.String("cc", "4111"),
+	)
+	if _, ok := m["card_number"]; ok {
+		t.Errorf("renaming must not bypass the denylist: %v", m)
+	}
+	if _, ok := m["cc"]; ok {
+		t.Errorf("renamed field should not keep its original key: %v", m)
+	}
+}
+
+func TestBuilder_FieldFilter_WithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger, cleanup, err := xlog.New().SetOutput(&buf).SetFieldDenylist("token").Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	testCleanup(t, cleanup)
+
+	logger.With(slog.String("token", "abc")).WithGroup("auth").Info(context.Background(), "login",
+		slog.String("token", "def"), slog.String("user", "alice"))
+
+	out := buf.String()
+	if strings.Contains(out, "abc") || strings.Contains(out, "def") {
+		t.Errorf("token must be removed from With attrs and groups: %s", out)
+	}
+	if !strings.Contains(out, "alice") {
+		t.Errorf("user should be kept: %s", out)
+	}
+}
+
+func TestBuilder_FieldFilter_EmptyList(t *testing.T) {
+	for name, b := range map[string]*xlog.Builder{
+		"allow": xlog.New().SetFieldAllowlist(),
+		"deny":  xlog.New().SetFieldDenylist("", "  "),
+	} {
+		if _, _, err := b.Build(); !errors.Is(err, xlog.ErrEmptyFieldList) {
+			t.Errorf("%s: expected ErrEmptyFieldList, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package xlog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrInvalidSamplingConfig 采样配置无效（负值）
+var ErrInvalidSamplingConfig = errors.New("xlog: invalid sampling config")
+
+// 采样默认值
+const (
+	defaultSamplingTick     = time.Second
+	defaultSamplingFirst    = 100
+	defaultSamplingReport   = 10 * time.Second
+	samplingSlots           = 1024
+	samplingSummaryMessage  = "xlog: logs suppressed by sampling"
+	samplingAttrMessage     = "sampled_msg"
+	samplingAttrLevel       = "sampled_level"
+	samplingAttrSuppressed  = "suppressed"
+	samplingAttrReportRange = "window"
+)
+
+// SamplingConfig 日志采样配置
+//
+// 采样按 (级别, 消息) 计数：每个 Tick 窗口内，同一消息的前 First 条全部输出，
+// 之后每 Thereafter 条输出 1 条，其余被抑制。零值字段使用默认值。
+type SamplingConfig struct {
+	// Tick 采样窗口，默认 1s
+	Tick time.Duration
+	// First 每个窗口内每条消息无条件输出的条数，默认 100
+	First int
+	// Thereafter 超过 First 后每 Thereafter 条输出 1 条，0 表示其余全部抑制
+	Thereafter int
+	// ReportInterval 抑制汇总的输出周期，默认 10s
+	ReportInterval time.Duration
+}
+
+// samplingSlot 单条消息的采样计数
+//
+// 设计决策: 使用固定数量的槽位（按哈希定位）而非 map，热路径只有原子操作、无锁无分配，
+// 且内存占用与消息种类无关。代价是哈希冲突的消息共享计数，汇总中以最近一次
+// 被抑制的消息为代表——对于降噪场景这是可接受的近似。
+type samplingSlot struct {
+	windowStart atomic.Int64  // 当前窗口起点（UnixNano）
+	count       atomic.Uint64 // 当前窗口内的记录数
+	suppressed  atomic.Uint64 // 上次汇总以来被抑制的记录数
+	key         atomic.Pointer[samplingKey]
+}
+
+// samplingKey 槽位最近一次被抑制的消息，用于汇总输出
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// samplingState 派生 handler（WithAttrs/WithGroup）共享的采样状态
+type samplingState struct {
+	cfg     SamplingConfig // Tick、ReportInterval 创建后不变；First/Thereafter 以 first/thereafter 为准
+	summary slog.Handler   // 汇总日志的输出目标（未附加派生属性的原始 handler）
+	slots   [samplingSlots]samplingSlot
+	total   atomic.Uint64
+
+	// first、thereafter 支持运行时调整（SetRate），热路径原子读取
+	first      atomic.Int64
+	thereafter atomic.Int64
+	// paused 为 true 时暂停采样，全部日志直接输出
+	paused atomic.Bool
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// SamplingHandler 对高频重复日志采样，并周期性输出被抑制日志的汇总
+//
+// 装饰模式实现，被抑制的日志不会完全丢失：每个 ReportInterval 周期输出一条汇总日志，
+// 包含消息内容（sampled_msg）、级别（sampled_level）、抑制次数（suppressed）
+// 和统计周期（window），级别与被抑制的日志相同。
+//
+// SamplingHandler 是并发安全的。创建时启动后台汇总 goroutine，
+// 使用完毕必须调用 Close 停止并输出最后一次汇总（Builder 构建的 logger 由 cleanup 负责）。
+type SamplingHandler struct {
+	base  slog.Handler
+	state *samplingState
+}
+
+// NewSamplingHandler 创建 SamplingHandler
+//
+// base 为 nil 返回 ErrNilHandler；配置含负值返回 ErrInvalidSamplingConfig。
+func NewSamplingHandler(base slog.Handler, cfg SamplingConfig) (*SamplingHandler, error) {
+	if base == nil {
+		return nil, ErrNilHandler
+	}
+	if cfg.Tick < 0 || cfg.First < 0 || cfg.Thereafter < 0 || cfg.ReportInterval < 0 {
+		return nil, ErrInvalidSamplingConfig
+	}
+	if cfg.Tick == 0 {
+		cfg.Tick = defaultSamplingTick
+	}
+	if cfg.First == 0 {
+		cfg.First = defaultSamplingFirst
+	}
+	if cfg.ReportInterval == 0 {
+		cfg.ReportInterval = defaultSamplingReport
+	}
+
+	state := &samplingState{
+		cfg:     cfg,
+		summary: base,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	state.first.Store(int64(cfg.First))
+	state.thereafter.Store(int64(cfg.Thereafter))
+	// 预置窗口起点，避免首个窗口在并发下的重置竞争
+	now := time.Now().UnixNano()
+	for i := range state.slots {
+		state.slots[i].windowStart.Store(now)
+	}
+	go state.reportLoop()
+	return &SamplingHandler{base: base, state: state}, nil
+}
+
+// Enabled 委托给底层 handler
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.base.Enabled(ctx, level)
+}
+
+// Handle 按采样规则决定输出或抑制
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.state.sample(r.Level, r.Message) {
+		return nil
+	}
+	return h.base.Handle(ctx, r)
+}
+
+// WithAttrs 返回带额外属性的新 handler，与原 handler 共享采样计数
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{base: h.base.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup 返回带分组的新 handler，与原 handler 共享采样计数
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{base: h.base.WithGroup(name), state: h.state}
+}
+
+// Suppressed 返回创建以来被抑制的日志总数，可用于评估实际日志量
+func (h *SamplingHandler) Suppressed() uint64 {
+	return h.state.total.Load()
+}
+
+// Rate 返回当前生效的 First 与 Thereafter
+func (h *SamplingHandler) Rate() (first, thereafter int) {
+	return int(h.state.first.Load()), int(h.state.thereafter.Load())
+}
+
+// SetRate 运行时调整采样速率，对所有派生 handler 立即生效
+//
+// 语义与 SamplingConfig 相同：first 为 0 时使用默认值 100，thereafter 为 0 表示超出部分全部抑制；
+// 负值返回 ErrInvalidSamplingConfig。Tick 与 ReportInterval 不支持运行时调整。
+func (h *SamplingHandler) SetRate(first, thereafter int) error {
+	if first < 0 || thereafter < 0 {
+		return ErrInvalidSamplingConfig
+	}
+	if first == 0 {
+		first = defaultSamplingFirst
+	}
+	h.state.first.Store(int64(first))
+	h.state.thereafter.Store(int64(thereafter))
+	return nil
+}
+
+// Paused 报告采样是否已暂停
+func (h *SamplingHandler) Paused() bool {
+	return h.state.paused.Load()
+}
+
+// SetPaused 暂停或恢复采样，对所有派生 handler 立即生效
+//
+// 暂停期间全部日志直接输出、不计入采样计数，用于排查问题时临时抓取完整日志。
+// 恢复后各消息从新的窗口重新计数。
+func (h *SamplingHandler) SetPaused(paused bool) {
+	h.state.paused.Store(paused)
+}
+
+// Config 返回当前生效的采样配置（含运行时调整后的 First/Thereafter）
+func (h *SamplingHandler) Config() SamplingConfig {
+	cfg := h.state.cfg
+	cfg.First, cfg.Thereafter = h.Rate()
+	return cfg
+}
+
+// Close 停止后台汇总并输出最后一次汇总，重复调用安全
+//
+// 派生 handler 共享同一后台 goroutine，对任一 handler 调用 Close 即全部停止。
+func (h *SamplingHandler) Close() error {
+	h.state.closeOnce.Do(func() {
+		close(h.state.stop)
+	})
+	<-h.state.done
+	return nil
+}
+
+// SamplerOf 返回 Builder 构建的 logger（含派生 logger）所使用的 SamplingHandler
+//
+// 未通过 Builder.SetSampling 启用采样，或 l 不是本包构建的 logger 时返回 false。
+// 返回的 handler 可用于运行时调整采样（如交给 xdbg 的 logsample 命令），
+// 生命周期仍由 Build 返回的 cleanup 管理，调用方不应对其调用 Close。
+func SamplerOf(l Logger) (*SamplingHandler, bool) {
+	xl, ok := l.(*xlogger)
+	if !ok {
+		return nil, false
+	}
+	h, ok := xl.handler.(*SamplingHandler)
+	return h, ok
+}
+
+// sample 返回该记录是否应输出
+func (s *samplingState) sample(level slog.Level, msg string) bool {
+	if s.paused.Load() {
+		return true
+	}
+	slot := &s.slots[samplingHash(level, msg)%samplingSlots]
+
+	// 与 zap 的 sampler 相同：窗口切换时并发写入的少量计数可能被清零，换取热路径无锁
+	now := time.Now().UnixNano()
+	start := slot.windowStart.Load()
+	if now-start >= int64(s.cfg.Tick) && slot.windowStart.CompareAndSwap(start, now) {
+		slot.count.Store(0)
+	}
+
+	n := slot.count.Add(1)
+	first := uint64(s.first.Load())
+	if n <= first {
+		return true
+	}
+	if thereafter := uint64(s.thereafter.Load()); thereafter > 0 && (n-first)%thereafter == 0 {
+		return true
+	}
+
+	slot.suppressed.Add(1)
+	s.total.Add(1)
+	if k := slot.key.Load(); k == nil || k.level != level || k.msg != msg {
+		slot.key.Store(&samplingKey{level: level, msg: msg})
+	}
+	return false
+}
+
+// reportLoop 周期性输出汇总，停止时补发最后一次
+func (s *samplingState) reportLoop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.ReportInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.report()
+		case <-s.stop:
+			s.report()
+			return
+		}
+	}
+}
+
+// report 输出各槽位自上次汇总以来的抑制次数
+func (s *samplingState) report() {
+	ctx := context.Background()
+	for i := range s.slots {
+		slot := &s.slots[i]
+		n := slot.suppressed.Swap(0)
+		if n == 0 {
+			continue
+		}
+		k := slot.key.Load()
+		if k == nil || !s.summary.Enabled(ctx, k.level) {
+			continue
+		}
+		r := slog.NewRecord(time.Now(), k.level, samplingSummaryMessage, 0)
+		r.AddAttrs(
+			slog.String(samplingAttrMessage, k.msg),
+			slog.String(samplingAttrLevel, k.level.String()),
+			slog.Uint64(samplingAttrSuppressed, n),
+			slog.Duration(samplingAttrReportRange, s.cfg.ReportInterval),
+		)
+		// best-effort：汇总输出失败不影响业务日志
+		_ = s.summary.Handle(ctx, r) //nolint:errcheck // 汇总日志失败无可处理的调用方
+	}
+}
+
+// samplingHash 对 (level, msg) 计算 FNV-1a 哈希，无内存分配
+func samplingHash(level slog.Level, msg string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	h ^= uint32(level)
+	h *= prime32
+	for i := 0; i < len(msg); i++ {
+		h ^= uint32(msg[i])
+		h *= prime32
+	}
+	return h
+}
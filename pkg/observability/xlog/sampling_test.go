@@ -0,0 +1,379 @@
+package xlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/omeyang/xkit/pkg/observability/xlog"
+)
+
+// syncBuffer 并发安全的 buffer（汇总由后台 goroutine 写入）
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// records 解析已写入的 JSON 日志
+func (b *syncBuffer) records(t *testing.T) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid json line %q: %v", line, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func countMsg(records []map[string]any, msg string) int {
+	n := 0
+	for _, r := range records {
+		if r["msg"] == msg {
+			n++
+		}
+	}
+	return n
+}
+
+func summaries(records []map[string]any) []map[string]any {
+	var out []map[string]any
+	for _, r := range records {
+		if r["msg"] == "xlog: logs suppressed by sampling" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func newSampling(t *testing.T, buf *syncBuffer, cfg xlog.SamplingConfig) *xlog.SamplingHandler {
+	t.Helper()
+	h, err := xlog.NewSamplingHandler(slog.NewJSONHandler(buf, nil), cfg)
+	if err != nil {
+		t.Fatalf("NewSamplingHandler() error: %v", err)
+	}
+	t.Cleanup(func() { _ = h.Close() })
+	return h
+}
+
+func TestSamplingHandler_FirstAndThereafter(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 3, Thereafter: 5, ReportInterval: time.Hour})
+	logger := slog.New(h)
+
+	for range 23 {
+		logger.Info("hot")
+	}
+	logger.Info("cold")
+
+	// 前 3 条 + 第 8、13、18、23 条
+	if got := countMsg(buf.records(t), "hot"); got != 7 {
+		t.Errorf("hot logged %d times, want 7", got)
+	}
+	if got := countMsg(buf.records(t), "cold"); got != 1 {
+		t.Errorf("cold logged %d times, want 1", got)
+	}
+	if got := h.Suppressed(); got != 16 {
+		t.Errorf("Suppressed() = %d, want 16", got)
+	}
+}
+
+func TestSamplingHandler_LevelsCountedSeparately(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: time.Hour})
+	logger := slog.New(h)
+
+	logger.Info("same")
+	logger.Warn("same")
+	logger.Info("same")
+
+	if got := countMsg(buf.records(t), "same"); got != 2 {
+		t.Errorf("logged %d times, want 2", got)
+	}
+}
+
+func TestSamplingHandler_WindowReset(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: 20 * time.Millisecond, First: 1, ReportInterval: time.Hour})
+	logger := slog.New(h)
+
+	logger.Info("tick")
+	logger.Info("tick")
+	time.Sleep(40 * time.Millisecond)
+	logger.Info("tick")
+
+	if got := countMsg(buf.records(t), "tick"); got != 2 {
+		t.Errorf("logged %d times, want 2", got)
+	}
+}
+
+func TestSamplingHandler_CloseFlushesSummary(t *testing.T) {
+	buf := &syncBuffer{}
+	h, err := xlog.NewSamplingHandler(slog.NewJSONHandler(buf, nil),
+		xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewSamplingHandler() error: %v", err)
+	}
+	logger := slog.New(h)
+	for range 5 {
+		logger.Error("boom")
+	}
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("second Close() error: %v", err)
+	}
+
+	got := summaries(buf.records(t))
+	if len(got) != 1 {
+		t.Fatalf("got %d summaries, want 1", len(got))
+	}
+	s := got[0]
+	if s["level"] != "ERROR" || s["sampled_msg"] != "boom" || s["sampled_level"] != "ERROR" {
+		t.Errorf("unexpected summary: %v", s)
+	}
+	if s["suppressed"] != float64(4) {
+		t.Errorf("suppressed = %v, want 4", s["suppressed"])
+	}
+	if _, ok := s["window"]; !ok {
+		t.Error("summary should carry window")
+	}
+}
+
+func TestSamplingHandler_PeriodicReport(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: 10 * time.Millisecond})
+	logger := slog.New(h)
+	logger.Info("noisy")
+	logger.Info("noisy")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(summaries(buf.records(t))) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("periodic summary not emitted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 计数在汇总后清零：无新的抑制时不重复输出
+	time.Sleep(50 * time.Millisecond)
+	if got := len(summaries(buf.records(t))); got != 1 {
+		t.Errorf("got %d summaries, want 1", got)
+	}
+}
+
+func TestSamplingHandler_DerivedShareState(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: time.Hour})
+	child := slog.New(h).With("k", "v").WithGroup("g")
+
+	slog.New(h).Info("shared")
+	child.Info("shared")
+
+	if got := countMsg(buf.records(t), "shared"); got != 1 {
+		t.Errorf("logged %d times, want 1", got)
+	}
+	if got := h.Suppressed(); got != 1 {
+		t.Errorf("Suppressed() = %d, want 1", got)
+	}
+}
+
+func TestSamplingHandler_Concurrent(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 10, ReportInterval: time.Millisecond})
+	logger := slog.New(h)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 500 {
+				logger.Info("concurrent")
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := h.Suppressed(); got != 8*500-10 {
+		t.Errorf("Suppressed() = %d, want %d", got, 8*500-10)
+	}
+}
+
+func TestNewSamplingHandler_Errors(t *testing.T) {
+	if _, err := xlog.NewSamplingHandler(nil, xlog.SamplingConfig{}); !errors.Is(err, xlog.ErrNilHandler) {
+		t.Errorf("nil base: got %v, want ErrNilHandler", err)
+	}
+	base := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	for _, cfg := range []xlog.SamplingConfig{
+		{Tick: -1}, {First: -1}, {Thereafter: -1}, {ReportInterval: -1},
+	} {
+		if _, err := xlog.NewSamplingHandler(base, cfg); !errors.Is(err, xlog.ErrInvalidSamplingConfig) {
+			t.Errorf("cfg %+v: got %v, want ErrInvalidSamplingConfig", cfg, err)
+		}
+	}
+}
+
+func TestSamplingHandler_Enabled(t *testing.T) {
+	base := slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn})
+	h, err := xlog.NewSamplingHandler(base, xlog.SamplingConfig{})
+	if err != nil {
+		t.Fatalf("NewSamplingHandler() error: %v", err)
+	}
+	defer h.Close()
+
+	if h.Enabled(context.Background(), slog.LevelInfo) {
+		t.Error("Info should not be enabled when base level is Warn")
+	}
+	if !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Error should be enabled when base level is Warn")
+	}
+}
+
+func TestBuilder_SetSampling(t *testing.T) {
+	buf := &syncBuffer{}
+	logger, cleanup, err := xlog.New().
+		SetOutput(buf).
+		SetFormat("json").
+		SetSampling(xlog.SamplingConfig{Tick: time.Hour, First: 2, ReportInterval: time.Hour}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	ctx := context.Background()
+	for range 5 {
+		logger.Warn(ctx, "storm")
+	}
+	if err := cleanup(); err != nil {
+		t.Fatalf("cleanup() error: %v", err)
+	}
+
+	records := buf.records(t)
+	if got := countMsg(records, "storm"); got != 2 {
+		t.Errorf("logged %d times, want 2", got)
+	}
+	s := summaries(records)
+	if len(s) != 1 || s[0]["suppressed"] != float64(3) {
+		t.Errorf("unexpected summaries: %v", s)
+	}
+}
+
+func TestBuilder_SetSampling_Invalid(t *testing.T) {
+	_, _, err := xlog.New().SetSampling(xlog.SamplingConfig{First: -1}).Build()
+	if !errors.Is(err, xlog.ErrInvalidSamplingConfig) {
+		t.Errorf("got %v, want ErrInvalidSamplingConfig", err)
+	}
+}
+
+func TestSamplingHandler_SetPaused(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: time.Hour})
+	logger := slog.New(h).With("k", "v")
+
+	h.SetPaused(true)
+	if !h.Paused() {
+		t.Fatal("Paused() = false after SetPaused(true)")
+	}
+	for range 5 {
+		logger.Info("debugging")
+	}
+	h.SetPaused(false)
+	logger.Info("debugging")
+	logger.Info("debugging")
+
+	// 暂停期间全部输出且不计数；恢复后首条计入 First
+	if got := countMsg(buf.records(t), "debugging"); got != 6 {
+		t.Errorf("logged %d times, want 6", got)
+	}
+	if got := h.Suppressed(); got != 1 {
+		t.Errorf("Suppressed() = %d, want 1", got)
+	}
+}
+
+func TestSamplingHandler_SetRate(t *testing.T) {
+	buf := &syncBuffer{}
+	h := newSampling(t, buf, xlog.SamplingConfig{Tick: time.Hour, First: 1, ReportInterval: time.Hour})
+	logger := slog.New(h)
+
+	if err := h.SetRate(-1, 0); !errors.Is(err, xlog.ErrInvalidSamplingConfig) {
+		t.Errorf("SetRate(-1, 0) = %v, want ErrInvalidSamplingConfig", err)
+	}
+	if err := h.SetRate(3, 2); err != nil {
+		t.Fatalf("SetRate() error: %v", err)
+	}
+	if first, thereafter := h.Rate(); first != 3 || thereafter != 2 {
+		t.Errorf("Rate() = (%d, %d), want (3, 2)", first, thereafter)
+	}
+	cfg := h.Config()
+	if cfg.First != 3 || cfg.Thereafter != 2 || cfg.Tick != time.Hour {
+		t.Errorf("Config() = %+v", cfg)
+	}
+
+	for range 7 {
+		logger.Info("rate")
+	}
+	// 前 3 条 + 第 5、7 条
+	if got := countMsg(buf.records(t), "rate"); got != 5 {
+		t.Errorf("logged %d times, want 5", got)
+	}
+
+	if err := h.SetRate(0, 0); err != nil {
+		t.Fatalf("SetRate(0, 0) error: %v", err)
+	}
+	if first, _ := h.Rate(); first != 100 {
+		t.Errorf("Rate() first = %d, want default 100", first)
+	}
+}
+
+func TestSamplerOf(t *testing.T) {
+	logger, cleanup, err := xlog.New().
+		SetOutput(&syncBuffer{}).
+		SetSampling(xlog.SamplingConfig{}).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	defer func() { _ = cleanup() }()
+
+	h, ok := xlog.SamplerOf(logger.With(slog.String("k", "v")))
+	if !ok || h == nil {
+		t.Fatal("SamplerOf() did not find sampler on derived logger")
+	}
+	h.SetPaused(true)
+	root, _ := xlog.SamplerOf(logger)
+	if !root.Paused() {
+		t.Error("derived sampler does not share state with root")
+	}
+
+	plain, cleanupPlain, err := xlog.New().SetOutput(&syncBuffer{}).Build()
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+	defer func() { _ = cleanupPlain() }()
+	if _, ok := xlog.SamplerOf(plain); ok {
+		t.Error("SamplerOf() found sampler on logger without sampling")
+	}
+	if _, ok := xlog.SamplerOf(nil); ok {
+		t.Error("SamplerOf(nil) = true")
+	}
+}
@@ -73,3 +73,28 @@ func BenchmarkLogger_With(b *testing.B) {
 		_ = logger.With(slog.String("key", "value"))
 	}
 }
+
+func BenchmarkLogger_Info_FieldDenylist(b *testing.B) {
+	logger, cleanup, err := xlog.New().
+		SetOutput(io.Discard).
+		SetFieldDenylist("card_number", "cvv", "password", "payment.token").
+		Build()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		if err := cleanup(); err != nil {
+			b.Errorf("cleanup error: %v", err)
+		}
+	})
+
+	ctx := context.Background()
+	b.ResetTimer()
+
+	for b.Loop() {
+		logger.Info(ctx, "benchmark message",
+			slog.String("user", "alice"),
+			slog.String("card_number", "4111"),
+			slog.Group("payment", slog.Int("amount", 10), slog.String("token", "t")))
+	}
+}
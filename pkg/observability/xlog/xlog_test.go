@@ -396,6 +396,8 @@ func TestBuilder_FirstErrorWins_AllSetMethods(t *testing.T) {
 		{"SetDeploymentType", func(b *xlog.Builder) *xlog.Builder {
 			return b.SetDeploymentType(xctx.DeploymentSaaS)
 		}},
+		{"SetFieldAllowlist", func(b *xlog.Builder) *xlog.Builder { return b.SetFieldAllowlist("user") }},
+		{"SetFieldDenylist", func(b *xlog.Builder) *xlog.Builder { return b.SetFieldDenylist("card_number") }},
 	}
 
 	for _, tt := range tests {
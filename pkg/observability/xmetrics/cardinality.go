@@ -0,0 +1,80 @@
+package xmetrics
+
+import "sync"
+
+// CardinalityOverflowValue 是超出基数上限时 metrics 中替代 component/operation 的取值。
+// 与 OTel SDK 的 otel.metric.overflow 思路一致：丢弃具体标签值但保留计数总量，
+// 仪表盘可按该值检测并告警。
+const CardinalityOverflowValue = "_overflow"
+
+// cardinalityLimiter 限制 metrics 中 component / operation 标签的基数。
+//
+// 上限分两层：最多 limit 个不同 component；每个 component 下最多 limit 个不同 operation。
+// 按 component 分桶而非全局计数，避免单个误用的组件耗尽配额、连带其他组件的新操作被折叠。
+//
+// 已登记的组合永久保留（不做过期淘汰），因此同一标签值在进程生命周期内取值稳定，
+// 不会在真实值与 _overflow 之间来回跳变。
+type cardinalityLimiter struct {
+	limit      int
+	onOverflow func(component, operation string)
+
+	mu         sync.RWMutex
+	components map[string]map[string]struct{}
+}
+
+func newCardinalityLimiter(limit int, onOverflow func(component, operation string)) *cardinalityLimiter {
+	return &cardinalityLimiter{
+		limit:      limit,
+		onOverflow: onOverflow,
+		components: make(map[string]map[string]struct{}),
+	}
+}
+
+// resolve 返回写入 metrics 的 component / operation。
+// 已登记或仍有配额的组合原样返回；超出上限的部分替换为 CardinalityOverflowValue，
+// 并触发 onOverflow 回调。nil 接收者表示未开启基数保护。
+func (l *cardinalityLimiter) resolve(component, operation string) (string, string) {
+	if l == nil {
+		return component, operation
+	}
+
+	// 快路径：已登记的组合只需读锁
+	l.mu.RLock()
+	ops, ok := l.components[component]
+	if ok {
+		if _, known := ops[operation]; known {
+			l.mu.RUnlock()
+			return component, operation
+		}
+	}
+	l.mu.RUnlock()
+
+	metricComponent, metricOperation := l.admit(component, operation)
+	if l.onOverflow != nil && (metricComponent != component || metricOperation != operation) {
+		l.onOverflow(component, operation)
+	}
+	return metricComponent, metricOperation
+}
+
+// admit 在写锁下登记新组合，返回实际使用的标签值。
+func (l *cardinalityLimiter) admit(component, operation string) (string, string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ops, ok := l.components[component]
+	if !ok {
+		if len(l.components) >= l.limit {
+			return CardinalityOverflowValue, CardinalityOverflowValue
+		}
+		ops = make(map[string]struct{})
+		l.components[component] = ops
+	}
+	if _, known := ops[operation]; known {
+		return component, operation
+	}
+	if len(ops) >= l.limit {
+		return component, CardinalityOverflowValue
+	}
+	ops[operation] = struct{}{}
+	return component, operation
+}
@@ -0,0 +1,143 @@
+package xmetrics
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// ============================================================================
+// 基数保护测试
+// ============================================================================
+
+func TestCardinalityLimiter_Nil(t *testing.T) {
+	var l *cardinalityLimiter
+	c, o := l.resolve("comp", "op")
+	assert.Equal(t, "comp", c)
+	assert.Equal(t, "op", o)
+}
+
+func TestCardinalityLimiter_OperationLimit(t *testing.T) {
+	var overflowed []string
+	l := newCardinalityLimiter(2, func(_, operation string) {
+		overflowed = append(overflowed, operation)
+	})
+
+	for _, op := range []string{"a", "b"} {
+		c, o := l.resolve("comp", op)
+		assert.Equal(t, "comp", c)
+		assert.Equal(t, op, o)
+	}
+
+	c, o := l.resolve("comp", "c")
+	assert.Equal(t, "comp", c, "component is kept when only operation overflows")
+	assert.Equal(t, CardinalityOverflowValue, o)
+
+	// 已登记的值保持稳定
+	_, o = l.resolve("comp", "a")
+	assert.Equal(t, "a", o)
+
+	// 其他组件有独立的 operation 配额
+	c, o = l.resolve("other", "c")
+	assert.Equal(t, "other", c)
+	assert.Equal(t, "c", o)
+
+	assert.Equal(t, []string{"c"}, overflowed)
+}
+
+func TestCardinalityLimiter_ComponentLimit(t *testing.T) {
+	l := newCardinalityLimiter(1, nil)
+
+	c, o := l.resolve("first", "op")
+	assert.Equal(t, "first", c)
+	assert.Equal(t, "op", o)
+
+	c, o = l.resolve("second", "op")
+	assert.Equal(t, CardinalityOverflowValue, c)
+	assert.Equal(t, CardinalityOverflowValue, o)
+}
+
+func TestCardinalityLimiter_Concurrent(t *testing.T) {
+	l := newCardinalityLimiter(10, nil)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Go(func() {
+			l.resolve("comp", string(rune('a'+i%26)))
+		})
+	}
+	wg.Wait()
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	assert.Len(t, l.components["comp"], 10)
+}
+
+func TestWithCardinalityLimit(t *testing.T) {
+	cfg := &otelConfig{}
+	WithCardinalityLimit(5)(cfg)
+	assert.Equal(t, 5, cfg.cardinalityLimit)
+
+	WithCardinalityLimit(0)(cfg)
+	WithCardinalityLimit(-1)(cfg)
+	assert.Equal(t, 5, cfg.cardinalityLimit, "non-positive values are ignored")
+}
+
+func TestOTelObserver_CardinalityLimit(t *testing.T) {
+	tp, exporter := newTestTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	mp, reader := newTestMeterProvider()
+	defer func() { _ = mp.Shutdown(context.Background()) }()
+
+	var overflowCount int
+	obs, err := NewOTelObserver(
+		WithTracerProvider(tp),
+		WithMeterProvider(mp),
+		WithCardinalityLimit(2),
+		WithCardinalityOverflowHandler(func(component, operation string) {
+			assert.Equal(t, "svc", component)
+			overflowCount++
+		}),
+	)
+	require.NoError(t, err)
+
+	for _, op := range []string{"get", "put", "user-1", "user-2", "get"} {
+		_, span := obs.Start(context.Background(), SpanOptions{Component: "svc", Operation: op})
+		span.End(Result{})
+	}
+	assert.Equal(t, 2, overflowCount)
+
+	// trace 保留原始名称
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 5)
+	assert.Equal(t, "user-1", spans[2].Name)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	counts := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != metricOperationTotal {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok)
+			for _, dp := range sum.DataPoints {
+				op, _ := dp.Attributes.Value(attribute.Key(AttrKeyOperation))
+				counts[op.AsString()] += dp.Value
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		"get":                    2,
+		"put":                    1,
+		CardinalityOverflowValue: 2,
+	}, counts)
+}
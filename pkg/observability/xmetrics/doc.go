@@ -34,6 +34,21 @@
 // 适用于典型 API 操作（1ms ~ 10s）。可通过 [WithHistogramBuckets] 自定义。
 // 自定义桶边界必须为非负值、严格递增且不含 NaN/Inf，否则 [NewOTelObserver] 返回 [ErrInvalidBuckets]。
 //
+// # 批处理指标
+//
+// 批量操作（如批量插入 N 条）在 [Result.BatchSize] 中填写实际处理条数，额外记录：
+//
+//   - xkit.operation.items — 处理条数（Counter，unit="{item}"）
+//   - xkit.operation.item.duration — 摊销到每条的耗时（Histogram，unit="s"，即总耗时 / BatchSize）
+//
+// 两者与统一指标使用相同的 component / operation / status 维度，span 上附加 batch.size 属性。
+// 据此可区分"一次操作慢"和"一次处理了很多条"：
+//
+//	span.End(xmetrics.Result{Err: err, BatchSize: len(docs)})
+//
+// item.duration 与 duration 共用桶边界；单条耗时远小于 1ms 时可通过 [WithHistogramBuckets]
+// 调整，或在 SDK 侧为该指标单独配置 View。BatchSize <= 0 时不记录批处理指标。
+//
 // # 统一属性
 //
 // 每次观测自动附加 metrics 三维度：component / operation / status（见 [AttrKeyComponent] 等常量）。
@@ -51,6 +66,11 @@
 // 禁止将动态值（如请求 ID、用户 ID、URL 路径参数）写入。
 // 违反此约束会导致 metrics 时序爆炸和存储成本上升。
 //
+// 需要运行时防护时，可通过 [WithCardinalityLimit] 开启基数保护：
+// 超出上限的新 component / operation 在 metrics 中折叠为 [CardinalityOverflowValue]，
+// 并可通过 [WithCardinalityOverflowHandler] 接收回调用于告警。
+// 折叠仅作用于 metrics，trace span 保留原始名称，便于定位误用的调用方。
+//
 // operation 推荐使用 snake_case 命名（如 "find_page"、"produce"），
 // 与 OTel 语义约定保持一致，便于跨包查询和仪表盘维护。
 //
@@ -92,6 +92,9 @@ type Result struct {
 	// Attrs 附加到 trace span 的自定义属性（不影响 metrics 维度）。
 	// 注意：component / operation / status 是保留键，使用这些键的属性会被静默过滤。
 	Attrs []Attr
+	// BatchSize 批处理操作实际处理的条数；> 0 时额外记录处理条数和摊销到每条的耗时，
+	// 并在 span 上附加 batch.size 属性。0 或负数表示非批处理操作。
+	BatchSize int
 }
 
 // Span 表示一次观测跨度。
@@ -22,8 +22,10 @@ const (
 	unknownComponent           = "unknown"
 	unknownOperation           = "unknown"
 
-	metricOperationTotal    = "xkit.operation.total"
-	metricOperationDuration = "xkit.operation.duration"
+	metricOperationTotal        = "xkit.operation.total"
+	metricOperationDuration     = "xkit.operation.duration"
+	metricOperationItems        = "xkit.operation.items"
+	metricOperationItemDuration = "xkit.operation.item.duration"
 
 	// traceFlagsSampled 是 W3C TraceFlags 的 sampled 位（0x01）。
 	// 用作 ensureParentSpan 中 trace_flags 缺失时的默认值。
@@ -39,6 +41,8 @@ const (
 	AttrKeyOperation = "operation"
 	// AttrKeyStatus 是 metrics 中操作状态的属性键。
 	AttrKeyStatus = "status"
+	// AttrKeyBatchSize 是 trace span 中批处理条数的属性键（仅 [Result.BatchSize] > 0 时设置）。
+	AttrKeyBatchSize = "batch.size"
 )
 
 // defaultDurationBuckets 定义了适用于典型 API 操作的 Histogram 桶边界（秒）。
@@ -52,6 +56,8 @@ type otelConfig struct {
 	tracerProvider      trace.TracerProvider
 	meterProvider       metric.MeterProvider
 	histogramBuckets    []float64
+	cardinalityLimit    int
+	onOverflow          func(component, operation string)
 }
 
 // Option 定义 OTel Observer 的配置选项。
@@ -105,6 +111,30 @@ func WithHistogramBuckets(buckets []float64) Option {
 	}
 }
 
+// WithCardinalityLimit 开启 metrics 标签基数保护。
+// 最多记录 n 个不同 component，每个 component 下最多 n 个不同 operation；
+// 超出后新出现的值在 metrics 中替换为 [CardinalityOverflowValue]，
+// 计数与耗时仍计入，只是丢弃具体标签值。trace span 不受影响，保留原始名称便于排查。
+// n <= 0 表示不限制（默认）。
+func WithCardinalityLimit(n int) Option {
+	return func(cfg *otelConfig) {
+		if n > 0 {
+			cfg.cardinalityLimit = n
+		}
+	}
+}
+
+// WithCardinalityOverflowHandler 设置超出基数上限时的回调，用于日志或告警。
+// 参数为被折叠的原始 component / operation。仅在配置 [WithCardinalityLimit] 时生效。
+//
+// 回调在 Start 路径同步调用，且同一超限值每次出现都会触发，
+// 实现应足够轻量并自行限流（如按 component 去重或采样日志）。
+func WithCardinalityOverflowHandler(fn func(component, operation string)) Option {
+	return func(cfg *otelConfig) {
+		cfg.onOverflow = fn
+	}
+}
+
 // NewOTelObserver 创建基于 OpenTelemetry 的 Observer。
 func NewOTelObserver(opts ...Option) (Observer, error) {
 	cfg := &otelConfig{
@@ -136,46 +166,78 @@ func NewOTelObserver(opts ...Option) (Observer, error) {
 		return nil, ErrNilMeter
 	}
 
-	total, err := meter.Int64Counter(
-		metricOperationTotal,
-		metric.WithDescription("total operations"),
-		metric.WithUnit("{operation}"),
-	)
+	total, err := newCounter(meter, metricOperationTotal, "total operations", "{operation}")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := newHistogram(meter, metricOperationDuration, "operation duration", cfg.histogramBuckets)
+	if err != nil {
+		return nil, err
+	}
+	items, err := newCounter(meter, metricOperationItems, "items processed by batch operations", "{item}")
+	if err != nil {
+		return nil, err
+	}
+	itemDuration, err := newHistogram(meter, metricOperationItemDuration,
+		"amortized per-item duration of batch operations", cfg.histogramBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	obs := &otelObserver{
+		tracer:       tracer,
+		total:        total,
+		duration:     duration,
+		items:        items,
+		itemDuration: itemDuration,
+	}
+	if cfg.cardinalityLimit > 0 {
+		obs.cardinality = newCardinalityLimiter(cfg.cardinalityLimit, cfg.onOverflow)
+	}
+	return obs, nil
+}
+
+// newCounter 创建 Int64Counter。
+//
+// 设计决策: 对 nil / typed-nil instrument 做 fail-open 防御（即使 err==nil）。
+// OTel API 契约保证 err==nil 时返回非 nil instrument，但自定义 MeterProvider
+// 可能返回 typed-nil（如 (*customCounter)(nil)），仅 == nil 检查会漏检，
+// 导致 End 时调用 Add/Record panic。
+func newCounter(meter metric.Meter, name, desc, unit string) (metric.Int64Counter, error) {
+	counter, err := meter.Int64Counter(name, metric.WithDescription(desc), metric.WithUnit(unit))
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCreateCounter, err)
 	}
-	// 设计决策: 对 nil / typed-nil instrument 做 fail-open 防御（即使 err==nil）。
-	// OTel API 契约保证 err==nil 时返回非 nil instrument，但自定义 MeterProvider
-	// 可能返回 typed-nil（如 (*customCounter)(nil)），仅 == nil 检查会漏检，
-	// 导致 End 时调用 Add/Record panic。
-	if isNilInterface(total) {
+	if isNilInterface(counter) {
 		return nil, fmt.Errorf("%w: meter returned nil counter", ErrCreateCounter)
 	}
+	return counter, nil
+}
 
-	duration, err := meter.Float64Histogram(
-		metricOperationDuration,
-		metric.WithDescription("operation duration"),
+// newHistogram 创建单位为秒的 Float64Histogram，nil 防御同 newCounter。
+func newHistogram(meter metric.Meter, name, desc string, buckets []float64) (metric.Float64Histogram, error) {
+	histogram, err := meter.Float64Histogram(
+		name,
+		metric.WithDescription(desc),
 		metric.WithUnit("s"),
-		metric.WithExplicitBucketBoundaries(cfg.histogramBuckets...),
+		metric.WithExplicitBucketBoundaries(buckets...),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCreateHistogram, err)
 	}
-	if isNilInterface(duration) {
+	if isNilInterface(histogram) {
 		return nil, fmt.Errorf("%w: meter returned nil histogram", ErrCreateHistogram)
 	}
-
-	return &otelObserver{
-		tracer:   tracer,
-		total:    total,
-		duration: duration,
-	}, nil
+	return histogram, nil
 }
 
 type otelObserver struct {
-	tracer   trace.Tracer
-	total    metric.Int64Counter
-	duration metric.Float64Histogram
+	tracer       trace.Tracer
+	total        metric.Int64Counter
+	duration     metric.Float64Histogram
+	items        metric.Int64Counter     // 批处理条数，仅 Result.BatchSize > 0 时记录
+	itemDuration metric.Float64Histogram // 批处理摊销到每条的耗时
+	cardinality  *cardinalityLimiter     // nil 表示未开启基数保护
 }
 
 // Start 开始一次观测跨度。
@@ -189,6 +251,7 @@ func (o *otelObserver) Start(ctx context.Context, opts SpanOptions) (context.Con
 	// 原因：(1) 任何长度/模式检查都无法真正防止高基数（短 UUID 也会膨胀）；
 	// (2) 静默截断/降级会掩盖调用方的错误使用，比不检查更难排查；
 	// (3) 作为工具库，在热路径增加校验的性价比低，文档约束更适合。
+	// 需要运行时防护时通过 WithCardinalityLimit 按"不同值个数"限制，而非校验值本身。
 	// 相关文档：doc.go "component / operation 使用约束" 段落。
 	component := opts.Component
 	if component == "" {
@@ -219,6 +282,9 @@ func (o *otelObserver) Start(ctx context.Context, opts SpanOptions) (context.Con
 		ctx = parentCtx
 	}
 
+	// metrics 标签经基数保护折叠；trace 仍使用原始 component/operation
+	metricComponent, metricOperation := o.cardinality.resolve(component, operation)
+
 	// 设计决策: 对 nil/typed-nil span 做 fail-open 防御。
 	// OTel API 契约保证 Tracer.Start 返回非 nil span，但自定义 TracerProvider
 	// 可能违反此约定（包括返回 typed-nil，如 (*mySpan)(nil)）；
@@ -228,8 +294,8 @@ func (o *otelObserver) Start(ctx context.Context, opts SpanOptions) (context.Con
 			span:      trace.SpanFromContext(ctx), // noop span（保证非 nil）
 			observer:  o,
 			ctx:       ctx,
-			component: component,
-			operation: operation,
+			component: metricComponent,
+			operation: metricOperation,
 			start:     time.Now(),
 		}
 	}
@@ -240,8 +306,8 @@ func (o *otelObserver) Start(ctx context.Context, opts SpanOptions) (context.Con
 		span:      span,
 		observer:  o,
 		ctx:       ctx,
-		component: component,
-		operation: operation,
+		component: metricComponent,
+		operation: metricOperation,
 		start:     time.Now(),
 	}
 }
@@ -250,8 +316,8 @@ type otelSpan struct {
 	span      trace.Span
 	observer  *otelObserver
 	ctx       context.Context
-	component string
-	operation string
+	component string // metrics 使用的 component（可能已被基数保护折叠）
+	operation string // metrics 使用的 operation（可能已被基数保护折叠）
 	start     time.Time
 	endOnce   sync.Once // 保证 End 幂等，多次调用只记录一次 metrics
 }
@@ -290,6 +356,9 @@ func (s *otelSpan) End(result Result) {
 		if len(result.Attrs) > 0 {
 			s.span.SetAttributes(attrsToOTel(result.Attrs)...)
 		}
+		if result.BatchSize > 0 {
+			s.span.SetAttributes(attribute.Int(AttrKeyBatchSize, result.BatchSize))
+		}
 
 		s.span.End()
 
@@ -303,6 +372,10 @@ func (s *otelSpan) End(result Result) {
 		attrs := metricAttrs(s.component, s.operation, status)
 		s.observer.total.Add(metricsCtx, 1, metric.WithAttributes(attrs...))
 		s.observer.duration.Record(metricsCtx, elapsed, metric.WithAttributes(attrs...))
+		if result.BatchSize > 0 {
+			s.observer.items.Add(metricsCtx, int64(result.BatchSize), metric.WithAttributes(attrs...))
+			s.observer.itemDuration.Record(metricsCtx, elapsed/float64(result.BatchSize), metric.WithAttributes(attrs...))
+		}
 
 		// 释放 context 引用，避免长生命周期 span 阻止 GC 回收 context 链上的值。
 		s.ctx = nil
@@ -296,6 +296,66 @@ func TestOTelSpan_End_WithResultAttrs(t *testing.T) {
 	require.Len(t, spans, 1)
 }
 
+func TestOTelSpan_End_BatchSize(t *testing.T) {
+	tp, exporter := newTestTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	mp, reader := newTestMeterProvider()
+	defer func() { _ = mp.Shutdown(context.Background()) }()
+
+	obs, err := NewOTelObserver(WithTracerProvider(tp), WithMeterProvider(mp))
+	require.NoError(t, err)
+
+	for _, size := range []int{100, 50, 0} {
+		_, span := obs.Start(context.Background(), SpanOptions{
+			Component: "test",
+			Operation: "bulk_insert",
+		})
+		span.End(Result{BatchSize: size})
+	}
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 3)
+	assert.Contains(t, spans[0].Attributes, attribute.Int(AttrKeyBatchSize, 100))
+	for _, kv := range spans[2].Attributes {
+		assert.NotEqual(t, attribute.Key(AttrKeyBatchSize), kv.Key, "non-batch span has no batch.size")
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var (
+		items      int64
+		itemCount  uint64
+		totalCount int64
+	)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case metricOperationItems:
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				for _, dp := range sum.DataPoints {
+					items += dp.Value
+				}
+			case metricOperationItemDuration:
+				hist, ok := m.Data.(metricdata.Histogram[float64])
+				require.True(t, ok)
+				for _, dp := range hist.DataPoints {
+					itemCount += dp.Count
+				}
+			case metricOperationTotal:
+				sum, ok := m.Data.(metricdata.Sum[int64])
+				require.True(t, ok)
+				for _, dp := range sum.DataPoints {
+					totalCount += dp.Value
+				}
+			}
+		}
+	}
+	assert.Equal(t, int64(150), items, "items counter sums batch sizes")
+	assert.Equal(t, uint64(2), itemCount, "per-item duration recorded once per batch operation")
+	assert.Equal(t, int64(3), totalCount, "operation counter is unaffected")
+}
+
 func TestOTelSpan_End_Nil(t *testing.T) {
 	// nil span 的 End 不应该 panic
 	var span *otelSpan
@@ -34,12 +34,14 @@
 //   - ErrNilSampler: CompositeSampler 的子采样器为 nil
 //   - ErrNilOption: functional option 为 nil
 //
+// 验证工具额外返回 ErrNilKeyContext、ErrNoKeys 和 ErrInconsistentDecision。
+//
 // # 不可变性与状态
 //
 // 所有采样器的配置（rate、n、mode 等）创建后不可变，不支持运行时动态修改。
 // CountSampler 和 CompositeSampler 的内部计数器状态可通过 Reset() 重置。
-// 如需动态调整采样率，建议使用 atomic.Pointer[Sampler] 持有采样器引用，
-// 在配置变更时创建新采样器并原子替换。
+// 如需动态调整采样率，使用 NewDynamicSampler(initial) 包装采样器，
+// 在配置变更时通过 Store/SetRate 创建新采样器并原子替换，nil 采样器或非法 rate 返回错误且不替换。
 //
 // # 零值行为
 //
@@ -80,6 +82,16 @@
 //   - 零分配：热路径无内存分配
 //   - 行业标准：Prometheus、OpenTelemetry 等项目广泛使用
 //
+// # 部署前验证
+//
+// 包内提供两个验证工具，用于在测试或上线前检查采样配置：
+//   - VerifyConsistency(sampler, keys, newCtx): 多轮求值验证同一 key 的决策一致，
+//     可发现 KeyFunc 取不到 key（回退随机采样）或组合了有状态子采样器等配置错误
+//   - MeasureRate(sampler, expected, keys, newCtx): 统计实际采样率，
+//     RateReport.WithinSigma(k) 按标准误差判断偏差是否在统计波动范围内
+//
+// newCtx（KeyContextFunc）是 KeyFunc 的逆操作，为 key 构造可被 KeyFunc 读取的 context。
+//
 // # 使用方式
 //
 // 调用 NewXxxSampler 创建采样器，通过 ShouldSample(ctx) 进行采样决策。
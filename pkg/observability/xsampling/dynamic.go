@@ -0,0 +1,88 @@
+package xsampling
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// DynamicSampler 可在运行时原子替换的采样器
+//
+// 包装一个当前生效的采样器，ShouldSample 委托给它；Store/SetRate 原子替换，
+// 无需重启即可调整采样策略（如排查故障时临时提高采样率，问题解决后再降回）。
+// 并发安全：替换与 ShouldSample 之间无锁竞争，进行中的调用使用替换前的采样器完成判断。
+//
+// 设计决策: 替换而非修改——被包装的采样器仍保持不可变，
+// DynamicSampler 只持有引用，与"配置创建后不可变"的约定一致。
+type DynamicSampler struct {
+	current atomic.Pointer[samplerRef]
+}
+
+// samplerRef 包装接口值，使其可存入 atomic.Pointer
+type samplerRef struct {
+	sampler Sampler
+}
+
+// NewDynamicSampler 创建动态采样器
+//
+// initial 是初始生效的采样器，为 nil（含 typed-nil）时返回 ErrNilSampler。
+//
+// 示例：
+//
+//	initial, _ := NewRateSampler(0.01)
+//	sampler, _ := NewDynamicSampler(initial)
+//
+//	// 排查问题时临时提高采样率
+//	_ = sampler.SetRate(1.0)
+func NewDynamicSampler(initial Sampler) (*DynamicSampler, error) {
+	s := &DynamicSampler{}
+	if err := s.Store(initial); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ShouldSample 委托给当前生效的采样器
+//
+// 零值（未经 NewDynamicSampler 构造）不采样，等同于 Never()。
+func (s *DynamicSampler) ShouldSample(ctx context.Context) bool {
+	ref := s.current.Load()
+	if ref == nil {
+		return false
+	}
+	return ref.sampler.ShouldSample(ctx)
+}
+
+// Store 原子替换当前采样器
+//
+// sampler 为 nil（含 typed-nil）时返回 ErrNilSampler，当前采样器保持不变。
+func (s *DynamicSampler) Store(sampler Sampler) error {
+	if isNilSampler(sampler) {
+		return ErrNilSampler
+	}
+	s.current.Store(&samplerRef{sampler: sampler})
+	return nil
+}
+
+// SetRate 以固定比率采样器替换当前采样器
+//
+// 等价于 Store(NewRateSampler(rate))，便于在配置热更新回调中直接使用。
+// rate 超出 [0.0, 1.0] 范围或为 NaN 时返回 ErrInvalidRate，当前采样器保持不变。
+func (s *DynamicSampler) SetRate(rate float64) error {
+	rs, err := NewRateSampler(rate)
+	if err != nil {
+		return err
+	}
+	return s.Store(rs)
+}
+
+// Load 返回当前生效的采样器，零值时返回 nil
+func (s *DynamicSampler) Load() Sampler {
+	ref := s.current.Load()
+	if ref == nil {
+		return nil
+	}
+	return ref.sampler
+}
+
+// 确保实现了接口
+var _ Sampler = (*DynamicSampler)(nil)
@@ -26,6 +26,18 @@ var (
 	ErrNilOption = errors.New("xsampling: option must not be nil")
 )
 
+// 采样配置验证相关的错误
+var (
+	// ErrNilKeyContext 表示 VerifyConsistency 的 KeyContextFunc 为 nil
+	ErrNilKeyContext = errors.New("xsampling: key context func must not be nil")
+
+	// ErrNoKeys 表示验证工具的 keys 为空
+	ErrNoKeys = errors.New("xsampling: keys must not be empty")
+
+	// ErrInconsistentDecision 表示采样器对同一 key 做出了不一致的采样决策
+	ErrInconsistentDecision = errors.New("xsampling: inconsistent sampling decision for the same key")
+)
+
 // validateRate 校验采样比率是否在 [0.0, 1.0] 范围内
 func validateRate(rate float64) error {
 	if math.IsNaN(rate) || rate < 0 || rate > 1 {
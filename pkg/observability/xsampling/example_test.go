@@ -256,3 +256,36 @@ func Example_traceSampling() {
 	fmt.Printf("All spans in trace sampled consistently: %v\n", allSame)
 	// Output: All spans in trace sampled consistently: true
 }
+
+func ExampleVerifyConsistency() {
+	keyFunc := func(ctx context.Context) string {
+		if v, ok := ctx.Value(traceIDKey).(string); ok {
+			return v
+		}
+		return ""
+	}
+	sampler, err := xsampling.NewKeyBasedSampler(0.1, keyFunc)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// KeyContextFunc 是 KeyFunc 的逆操作
+	newCtx := func(key string) context.Context {
+		return context.WithValue(context.Background(), traceIDKey, key)
+	}
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("trace-%d", i)
+	}
+
+	fmt.Println("consistent:", xsampling.VerifyConsistency(sampler, keys, newCtx) == nil)
+
+	report, err := xsampling.MeasureRate(sampler, 0.1, keys, newCtx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("rate within 4σ:", report.WithinSigma(4))
+	// Output:
+	// consistent: true
+	// rate within 4σ: true
+}
@@ -605,6 +605,65 @@ func TestKeyBasedSampler(t *testing.T) {
 	})
 }
 
+func TestDynamicSampler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("delegates to current sampler", func(t *testing.T) {
+		sampler, err := NewDynamicSampler(Never())
+		require.NoError(t, err)
+		assertNeverSamples(t, sampler, ctx, "initial Never should not sample")
+
+		require.NoError(t, sampler.Store(Always()))
+		assertAlwaysSamples(t, sampler, ctx, "after Store(Always) should sample")
+		assert.Same(t, Always(), sampler.Load())
+	})
+
+	t.Run("SetRate", func(t *testing.T) {
+		sampler, err := NewDynamicSampler(Never())
+		require.NoError(t, err)
+		require.NoError(t, sampler.SetRate(1.0))
+		assertAlwaysSamples(t, sampler, ctx, "rate 1.0 should always sample")
+
+		rs, ok := sampler.Load().(*RateSampler)
+		require.True(t, ok)
+		assert.Equal(t, 1.0, rs.Rate())
+	})
+
+	t.Run("invalid input keeps current sampler", func(t *testing.T) {
+		_, err := NewDynamicSampler(nil)
+		assert.ErrorIs(t, err, ErrNilSampler)
+		_, err = NewDynamicSampler((*RateSampler)(nil))
+		assert.ErrorIs(t, err, ErrNilSampler)
+
+		sampler, err := NewDynamicSampler(Always())
+		require.NoError(t, err)
+		assert.ErrorIs(t, sampler.Store(nil), ErrNilSampler)
+		assert.ErrorIs(t, sampler.SetRate(1.5), ErrInvalidRate)
+		assert.ErrorIs(t, sampler.SetRate(math.NaN()), ErrInvalidRate)
+		assertAlwaysSamples(t, sampler, ctx, "failed updates should not replace sampler")
+	})
+
+	t.Run("zero value never samples", func(t *testing.T) {
+		var sampler DynamicSampler
+		assertNeverSamples(t, &sampler, ctx, "zero value should not sample")
+		assert.Nil(t, sampler.Load())
+	})
+
+	t.Run("concurrent swap", func(t *testing.T) {
+		sampler, err := NewDynamicSampler(Never())
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			for i := range 1000 {
+				_ = sampler.SetRate(float64(i%2) * 1.0)
+			}
+		})
+		runConcurrentSamplingOnly(sampler, ctx, 10, 1000)
+		wg.Wait()
+		// 主要验证没有 panic 或 data race
+	})
+}
+
 // 并发安全测试
 func TestConcurrency(t *testing.T) {
 	ctx := context.Background()
@@ -0,0 +1,138 @@
+package xsampling
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// =============================================================================
+// 采样配置验证工具
+// =============================================================================
+
+// KeyContextFunc 为指定 key 构造 context，使采样器的 KeyFunc 能从中提取出该 key。
+//
+// 它是 KeyFunc 的逆操作，例如 KeyFunc 读取 trace ID 时，
+// KeyContextFunc 应返回携带该 trace ID 的 context。
+type KeyContextFunc func(key string) context.Context
+
+// verifyRounds 是 VerifyConsistency 对每个 key 的求值轮数。
+const verifyRounds = 3
+
+// VerifyConsistency 验证采样器对同一 key 总是做出相同的采样决策。
+//
+// 对 keys 中的每个 key 通过 newCtx 构造 context 并多轮求值（正序与逆序交替），
+// 任一 key 在不同轮次中决策不一致时返回 ErrInconsistentDecision（包含该 key）。
+// sampler 为 nil 返回 ErrNilSampler，newCtx 为 nil 返回 ErrNilKeyContext，
+// keys 为空返回 ErrNoKeys。
+//
+// 设计决策: 验证经过 ShouldSample(ctx) 而非直接对 key 求哈希，覆盖 KeyFunc 与
+// KeyContextFunc 的实际配合。KeyFunc 提取不到 key 时采样器回退到随机采样，
+// 多轮求值下几乎必然出现不一致，从而暴露上下文传播配置错误；
+// 组合了 CountSampler 等有状态子采样器的 CompositeSampler 同样会被识别为不一致。
+// 注意：rate 为 0 或 1 时任何采样器都会表现为一致，验证应使用实际部署的采样率。
+func VerifyConsistency(sampler Sampler, keys []string, newCtx KeyContextFunc) error {
+	if isNilSampler(sampler) {
+		return ErrNilSampler
+	}
+	if newCtx == nil {
+		return ErrNilKeyContext
+	}
+	if len(keys) == 0 {
+		return ErrNoKeys
+	}
+
+	first := make([]bool, len(keys))
+	for i, key := range keys {
+		first[i] = sampler.ShouldSample(newCtx(key))
+	}
+
+	// 逆序求值，避免按调用次序产生决策的采样器恰好与首轮对齐
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	for range verifyRounds - 1 {
+		slices.Reverse(order)
+		for _, i := range order {
+			if sampler.ShouldSample(newCtx(keys[i])) != first[i] {
+				return fmt.Errorf("%w: key %q", ErrInconsistentDecision, keys[i])
+			}
+		}
+	}
+	return nil
+}
+
+// RateReport 是 MeasureRate 的采样率统计结果。
+type RateReport struct {
+	// Total 参与统计的样本数。
+	Total int
+
+	// Sampled 被采样的样本数。
+	Sampled int
+
+	// Expected 期望采样率。
+	Expected float64
+
+	// Observed 实际采样率（Sampled / Total）。
+	Observed float64
+}
+
+// Deviation 返回实际采样率与期望采样率的绝对偏差。
+func (r RateReport) Deviation() float64 {
+	return math.Abs(r.Observed - r.Expected)
+}
+
+// StdError 返回期望采样率下观测采样率的标准误差 sqrt(p(1-p)/n)。
+//
+// 样本之间相互独立时，观测采样率约以 Expected 为中心、StdError 为标准差分布。
+func (r RateReport) StdError() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return math.Sqrt(r.Expected * (1 - r.Expected) / float64(r.Total))
+}
+
+// WithinSigma 判断偏差是否在 k 倍标准误差以内。
+//
+// 常用 k=3（约 99.7% 置信度）作为"采样率符合预期"的判定标准。
+// rate 为 0 或 1 时标准误差为 0，要求观测值与期望值完全相等。
+func (r RateReport) WithinSigma(k float64) bool {
+	return r.Deviation() <= k*r.StdError()
+}
+
+// MeasureRate 统计采样器在给定 keys 上的实际采样率，并与期望采样率对比。
+//
+// 每个 key 求值一次：newCtx 不为 nil 时用其构造 context，
+// 为 nil 时使用 context.Background()（适用于 RateSampler 等与 key 无关的采样器，
+// 此时 keys 仅决定样本数）。
+// sampler 为 nil 返回 ErrNilSampler，keys 为空返回 ErrNoKeys，
+// expected 不在 [0.0, 1.0] 范围返回 ErrInvalidRate。
+//
+// 对 KeyBasedSampler 而言，keys 应取自真实流量（如线上 trace ID 样本），
+// 以验证哈希在实际 key 分布上的均匀性。
+func MeasureRate(sampler Sampler, expected float64, keys []string, newCtx KeyContextFunc) (RateReport, error) {
+	if isNilSampler(sampler) {
+		return RateReport{}, ErrNilSampler
+	}
+	if err := validateRate(expected); err != nil {
+		return RateReport{}, err
+	}
+	if len(keys) == 0 {
+		return RateReport{}, ErrNoKeys
+	}
+
+	report := RateReport{Total: len(keys), Expected: expected}
+	for _, key := range keys {
+		ctx := context.Background()
+		if newCtx != nil {
+			ctx = newCtx(key)
+		}
+		if sampler.ShouldSample(ctx) {
+			report.Sampled++
+		}
+	}
+	report.Observed = float64(report.Sampled) / float64(report.Total)
+	return report, nil
+}
@@ -0,0 +1,96 @@
+package xsampling
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKeyContext 构造携带 key 的 context，与 testKeyFunc 互逆
+func testKeyContext(key string) context.Context {
+	return context.WithValue(context.Background(), testKeyName, key)
+}
+
+// genVerifyKeys 生成 n 个测试 key
+func genVerifyKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("trace-%06d", i)
+	}
+	return keys
+}
+
+func TestVerifyConsistency(t *testing.T) {
+	keyed, err := NewKeyBasedSampler(0.3, testKeyFunc)
+	require.NoError(t, err)
+
+	t.Run("key based sampler is consistent", func(t *testing.T) {
+		require.NoError(t, VerifyConsistency(keyed, genVerifyKeys(1000), testKeyContext))
+	})
+
+	t.Run("broken key propagation falls back to random", func(t *testing.T) {
+		broken := func(string) context.Context { return context.Background() }
+		err := VerifyConsistency(keyed, genVerifyKeys(1000), broken)
+		require.ErrorIs(t, err, ErrInconsistentDecision)
+	})
+
+	t.Run("stateful composite is inconsistent", func(t *testing.T) {
+		count, err := NewCountSampler(2)
+		require.NoError(t, err)
+		composite, err := All(keyed, count)
+		require.NoError(t, err)
+		err = VerifyConsistency(composite, genVerifyKeys(1000), testKeyContext)
+		require.ErrorIs(t, err, ErrInconsistentDecision)
+		assert.Contains(t, err.Error(), "trace-")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		require.ErrorIs(t, VerifyConsistency(nil, genVerifyKeys(1), testKeyContext), ErrNilSampler)
+		require.ErrorIs(t, VerifyConsistency((*KeyBasedSampler)(nil), genVerifyKeys(1), testKeyContext), ErrNilSampler)
+		require.ErrorIs(t, VerifyConsistency(keyed, genVerifyKeys(1), nil), ErrNilKeyContext)
+		require.ErrorIs(t, VerifyConsistency(keyed, nil, testKeyContext), ErrNoKeys)
+	})
+}
+
+func TestMeasureRate(t *testing.T) {
+	t.Run("key based sampler distribution", func(t *testing.T) {
+		sampler, err := NewKeyBasedSampler(0.1, testKeyFunc)
+		require.NoError(t, err)
+
+		report, err := MeasureRate(sampler, 0.1, genVerifyKeys(20000), testKeyContext)
+		require.NoError(t, err)
+		assert.Equal(t, 20000, report.Total)
+		assert.InDelta(t, 0.1, report.Observed, 0.01)
+		assert.True(t, report.WithinSigma(4), "deviation %f exceeds 4σ (%f)", report.Deviation(), report.StdError())
+	})
+
+	t.Run("misconfigured rate is detected", func(t *testing.T) {
+		sampler, err := NewKeyBasedSampler(0.2, testKeyFunc)
+		require.NoError(t, err)
+
+		report, err := MeasureRate(sampler, 0.1, genVerifyKeys(20000), testKeyContext)
+		require.NoError(t, err)
+		assert.False(t, report.WithinSigma(4))
+	})
+
+	t.Run("nil key context uses background", func(t *testing.T) {
+		report, err := MeasureRate(Always(), 1, genVerifyKeys(10), nil)
+		require.NoError(t, err)
+		assert.Equal(t, 10, report.Sampled)
+		assert.Zero(t, report.StdError())
+		assert.True(t, report.WithinSigma(3))
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := MeasureRate(nil, 0.1, genVerifyKeys(1), nil)
+		require.ErrorIs(t, err, ErrNilSampler)
+		_, err = MeasureRate(Always(), 1.5, genVerifyKeys(1), nil)
+		require.ErrorIs(t, err, ErrInvalidRate)
+		_, err = MeasureRate(Always(), 1, nil, nil)
+		require.ErrorIs(t, err, ErrNoKeys)
+		assert.Zero(t, RateReport{}.StdError())
+	})
+}
@@ -103,6 +103,13 @@
 // 如需严格控制自动生成行为，可使用选项禁用。
 // 禁用后，缺失的字段将保持为空，不会自动生成。
 //
+// # 入口采样
+//
+// WithSampler 为没有上游采样决策（未携带有效 trace-flags）的请求调用 xsampling.Sampler，
+// 将结果写入 trace-flags（"01"/"00"）并随出站注入传播；上游已有决策时始终沿用。
+// 传入 xsampling.DynamicSampler 可在运行时通过 SetRate 调整采样率（如故障排查时临时提高），
+// 中间件无需重建，适合与 xconf.Watch 等配置热更新机制配合使用。
+//
 // # W3C traceparent 大小写处理
 //
 // W3C Trace Context 规范要求 trace-id、parent-id、trace-flags 必须是小写十六进制。
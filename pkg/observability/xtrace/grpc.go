@@ -100,6 +100,7 @@ func GRPCUnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 
 		// 注入到 context
 		ctx = injectTraceToContext(ctx, traceInfo, cfg.autoGenerate)
+		ctx = applySampling(ctx, cfg.sampler)
 
 		return handler(ctx, req)
 	}
@@ -124,6 +125,7 @@ func GRPCStreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 
 		// 注入到 context
 		ctx = injectTraceToContext(ctx, traceInfo, cfg.autoGenerate)
+		ctx = applySampling(ctx, cfg.sampler)
 
 		return handler(srv, &wrappedServerStream{ServerStream: ss, ctx: ctx})
 	}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/omeyang/xkit/pkg/context/xctx"
+	"github.com/omeyang/xkit/pkg/observability/xsampling"
 	"github.com/omeyang/xkit/pkg/observability/xtrace"
 
 	"google.golang.org/grpc"
@@ -228,6 +229,42 @@ func TestGRPCStreamServerInterceptor_WithOptions(t *testing.T) {
 	})
 }
 
+func TestGRPCServerInterceptors_WithSampler(t *testing.T) {
+	t.Run("一元拦截器为入口请求决定采样", func(t *testing.T) {
+		var capturedFlags string
+		interceptor := xtrace.GRPCUnaryServerInterceptor(xtrace.WithSampler(xsampling.Never()))
+		handler := func(ctx context.Context, req any) (any, error) {
+			capturedFlags = xctx.TraceFlags(ctx)
+			return nil, nil
+		}
+
+		if _, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+			t.Fatalf("interceptor error = %v", err)
+		}
+		if capturedFlags != "00" {
+			t.Errorf("TraceFlags = %q, want %q", capturedFlags, "00")
+		}
+	})
+
+	t.Run("流式拦截器沿用上游采样决策", func(t *testing.T) {
+		var capturedFlags string
+		interceptor := xtrace.GRPCStreamServerInterceptor(xtrace.WithSampler(xsampling.Never()))
+		md := metadata.Pairs(xtrace.MetaTraceparent, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		stream := &mockServerStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+		handler := func(srv any, stream grpc.ServerStream) error {
+			capturedFlags = xctx.TraceFlags(stream.Context())
+			return nil
+		}
+
+		if err := interceptor(nil, stream, &grpc.StreamServerInfo{}, handler); err != nil {
+			t.Fatalf("interceptor error = %v", err)
+		}
+		if capturedFlags != "01" {
+			t.Errorf("TraceFlags = %q, want upstream %q", capturedFlags, "01")
+		}
+	})
+}
+
 // =============================================================================
 // gRPC 客户端拦截器测试
 // =============================================================================
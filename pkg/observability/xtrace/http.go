@@ -95,6 +95,7 @@ func HTTPMiddleware(opts ...Option) func(http.Handler) http.Handler {
 
 			// 注入到 context
 			ctx = injectTraceToContext(ctx, info, cfg.autoGenerate)
+			ctx = applySampling(ctx, cfg.sampler)
 
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
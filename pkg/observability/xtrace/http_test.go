@@ -4,9 +4,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/omeyang/xkit/pkg/context/xctx"
+	"github.com/omeyang/xkit/pkg/observability/xsampling"
 	"github.com/omeyang/xkit/pkg/observability/xtrace"
 	"google.golang.org/grpc/metadata"
 )
@@ -737,6 +739,102 @@ func TestHTTPMiddleware_TraceFlagsPropagation(t *testing.T) {
 	}
 }
 
+func TestHTTPMiddleware_WithSampler(t *testing.T) {
+	serve := func(sampler xsampling.Sampler, traceparent string) (flags, outgoing string) {
+		handler := xtrace.HTTPMiddleware(xtrace.WithSampler(sampler))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				flags = xctx.TraceFlags(r.Context())
+				out := httptest.NewRequest("GET", "/downstream", nil)
+				xtrace.InjectToRequest(r.Context(), out)
+				outgoing = out.Header.Get(xtrace.HeaderTraceparent)
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+		req := httptest.NewRequest("GET", "/test", nil)
+		if traceparent != "" {
+			req.Header.Set(xtrace.HeaderTraceparent, traceparent)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return flags, outgoing
+	}
+
+	t.Run("root request sampled", func(t *testing.T) {
+		flags, outgoing := serve(xsampling.Always(), "")
+		if flags != "01" {
+			t.Errorf("TraceFlags = %q, want %q", flags, "01")
+		}
+		if !strings.HasSuffix(outgoing, "-01") {
+			t.Errorf("outgoing traceparent = %q, want sampled", outgoing)
+		}
+	})
+
+	t.Run("root request not sampled", func(t *testing.T) {
+		flags, outgoing := serve(xsampling.Never(), "")
+		if flags != "00" {
+			t.Errorf("TraceFlags = %q, want %q", flags, "00")
+		}
+		if !strings.HasSuffix(outgoing, "-00") {
+			t.Errorf("outgoing traceparent = %q, want unsampled", outgoing)
+		}
+	})
+
+	t.Run("upstream decision wins", func(t *testing.T) {
+		flags, _ := serve(xsampling.Never(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		if flags != "01" {
+			t.Errorf("TraceFlags = %q, want upstream %q", flags, "01")
+		}
+	})
+
+	t.Run("nil sampler keeps default", func(t *testing.T) {
+		flags, _ := serve(nil, "")
+		if flags != "" {
+			t.Errorf("TraceFlags = %q, want empty without sampler", flags)
+		}
+	})
+
+	t.Run("dynamic sampler updates without rebuilding middleware", func(t *testing.T) {
+		sampler, err := xsampling.NewDynamicSampler(xsampling.Never())
+		if err != nil {
+			t.Fatalf("NewDynamicSampler() error = %v", err)
+		}
+		var flags string
+		handler := xtrace.HTTPMiddleware(xtrace.WithSampler(sampler))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				flags = xctx.TraceFlags(r.Context())
+			}),
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+		if flags != "00" {
+			t.Errorf("before SetRate: TraceFlags = %q, want %q", flags, "00")
+		}
+
+		if err := sampler.SetRate(1.0); err != nil {
+			t.Fatalf("SetRate() error = %v", err)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test", nil))
+		if flags != "01" {
+			t.Errorf("after SetRate(1.0): TraceFlags = %q, want %q", flags, "01")
+		}
+	})
+
+	t.Run("key based sampler sees generated trace id", func(t *testing.T) {
+		var keys []string
+		sampler, err := xsampling.NewKeyBasedSampler(0.5, func(ctx context.Context) string {
+			key := xtrace.TraceID(ctx)
+			keys = append(keys, key)
+			return key
+		})
+		if err != nil {
+			t.Fatalf("NewKeyBasedSampler() error = %v", err)
+		}
+		serve(sampler, "")
+		if len(keys) != 1 || keys[0] == "" {
+			t.Errorf("sampler keys = %v, want one non-empty trace id", keys)
+		}
+	})
+}
+
 func TestInjectToRequest_TraceFlagsPropagation(t *testing.T) {
 	// 覆盖 InjectToRequest: trace-flags 从 context 传播到请求
 	ctx := context.Background()
@@ -8,6 +8,7 @@ import (
 
 	"github.com/omeyang/xkit/pkg/context/xctx"
 	"github.com/omeyang/xkit/pkg/observability/xlog"
+	"github.com/omeyang/xkit/pkg/observability/xsampling"
 )
 
 var errInvalidHex = errors.New("xtrace: invalid hex")
@@ -21,7 +22,8 @@ var errInvalidHex = errors.New("xtrace: invalid hex")
 type Option func(*config)
 
 type config struct {
-	autoGenerate bool // 是否自动生成缺失的追踪 ID
+	autoGenerate bool              // 是否自动生成缺失的追踪 ID
+	sampler      xsampling.Sampler // 入口采样器，nil 表示不做采样决策
 }
 
 // WithAutoGenerate 设置是否自动生成缺失的追踪 ID。
@@ -34,6 +36,32 @@ func WithAutoGenerate(enabled bool) Option {
 	}
 }
 
+// WithSampler 设置入口采样器，为没有上游采样决策的请求决定 trace-flags。
+//
+// 仅当请求未携带有效 trace-flags 时（即当前服务是链路入口）才调用 sampler，
+// 采样结果写入 context（"01" 已采样 / "00" 未采样），并随 InjectToRequest/InjectToOutgoingContext
+// 传播到下游；上游已有采样决策时始终沿用，保证同一链路决策一致。
+// sampler 在追踪 ID 注入之后调用，可使用 xsampling.NewKeyBasedSampler 以 TraceID 为 key 做一致性采样。
+//
+// 需要运行时调整采样率时传入 [xsampling.DynamicSampler]，通过 SetRate/Store 原子替换，
+// 无需重建中间件。例如结合 xconf 热更新：
+//
+//	sampler, _ := xsampling.NewDynamicSampler(xsampling.Never())
+//	_ = sampler.SetRate(cfg.Client().Float64("trace.sample_rate"))
+//	xconf.Watch(cfg, func(c xconf.Config, err error) {
+//	    if err == nil {
+//	        _ = sampler.SetRate(c.Client().Float64("trace.sample_rate"))
+//	    }
+//	})
+//	handler = xtrace.HTTPMiddleware(xtrace.WithSampler(sampler))(handler)
+//
+// sampler 为 nil 时不做采样决策（默认行为），trace-flags 保持缺失，出站时按 "01" 生成。
+func WithSampler(sampler xsampling.Sampler) Option {
+	return func(cfg *config) {
+		cfg.sampler = sampler
+	}
+}
+
 func applyOptions(opts []Option) *config {
 	cfg := &config{
 		autoGenerate: true, // 默认自动生成
@@ -136,6 +164,30 @@ func injectTraceToContext(ctx context.Context, info TraceInfo, autoGenerate bool
 	return ctx
 }
 
+// applySampling 为没有上游采样决策的请求调用入口采样器并注入 trace-flags。
+// 需在 injectTraceToContext 之后调用：无效的上游 trace-flags 已被丢弃，视为无决策。
+func applySampling(ctx context.Context, sampler xsampling.Sampler) context.Context {
+	if sampler == nil || xctx.TraceFlags(ctx) != "" {
+		return ctx
+	}
+	flags := traceFlagsUnsampled
+	if sampler.ShouldSample(ctx) {
+		flags = traceFlagsSampled
+	}
+	newCtx, err := xctx.WithTraceFlags(ctx, flags)
+	if err != nil { // 防御性处理：正常流程不会触发（仅 nil context）
+		xlog.Warn(ctx, "xtrace: failed to inject sampling decision", slog.Any("error", err))
+		return ctx
+	}
+	return newCtx
+}
+
+// W3C trace-flags 取值（仅 sampled 位）
+const (
+	traceFlagsSampled   = "01"
+	traceFlagsUnsampled = "00"
+)
+
 // idInjector 定义 ID 注入的行为
 type idInjector struct {
 	name     string                                                 // ID 名称，用于日志
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sony/gobreaker/v2"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // 默认配置常量
@@ -69,11 +72,31 @@ type Breaker struct {
 	tripPolicy    TripPolicy
 	successPolicy SuccessPolicy
 	excludePolicy ExcludePolicy
+	classifier    Classifier
 	timeout       time.Duration
 	interval      time.Duration
 	bucketPeriod  time.Duration // 滑动窗口桶周期
 	maxRequests   uint32
 	onStateChange func(name string, from, to State)
+	latency       latencyTracker // 从 tripPolicy 解析的延迟追踪器（nil 表示不测量耗时）
+	outcomes      outcomeTracker // 从 tripPolicy 解析的调用结果追踪器（nil 表示不上报结果）
+	rampUp        time.Duration  // 恢复后的预热时长（0 表示不预热）
+	ramp          *rampUpGate    // 预热控制（nil 表示不预热）
+
+	halfOpenMaxConcurrent     uint32        // 半开期间同时在途的探测上限（0 表示不限制）
+	halfOpenRequiredSuccesses uint32        // 半开恢复所需连续成功次数（0 表示使用 maxRequests）
+	halfOpen                  *halfOpenGate // 半开并发限制（nil 表示不限制）
+
+	fallback FallbackFunc // 请求被拒绝时的降级函数（nil 表示直接返回熔断器错误）
+
+	meterProvider metric.MeterProvider // 指标 MeterProvider（nil 表示不记录指标）
+	metrics       *breakerMetrics      // 指标收集器（nil 表示不记录指标）
+
+	forced  atomic.Int32 // 强制状态（forceNone/forceOpen/forceClosed）
+	forceMu sync.Mutex   // 串行化强制状态切换，保证通知的 from/to 与切换顺序一致
+
+	// notifyStateChange 底层熔断器的状态变化通知链（回调、指标、预热），强制状态切换时复用
+	notifyStateChange func(name string, from, to State)
 
 	// 底层熔断器（延迟初始化）
 	cb *gobreaker.CircuitBreaker[any]
@@ -196,6 +219,9 @@ func WithBucketPeriod(d time.Duration) BreakerOption {
 
 // WithMaxRequests 设置 HalfOpen 状态下允许通过的最大请求数
 //
+// gobreaker 在半开期间连续成功达到该值时恢复到 Closed，
+// 需要分别控制并发探测数与恢复条件时使用 WithHalfOpenMaxConcurrent 与 WithHalfOpenRequiredSuccesses。
+//
 // 默认值：1
 func WithMaxRequests(n uint32) BreakerOption {
 	return func(b *Breaker) {
@@ -248,6 +274,11 @@ func NewBreaker(name string, opts ...BreakerOption) *Breaker {
 	for _, opt := range opts {
 		opt(b)
 	}
+	b.latency = latencyTrackerOf(b.tripPolicy)
+	b.outcomes = outcomeTrackerOf(b.tripPolicy)
+	b.ramp = newRampUpGate(b.rampUp)
+	b.halfOpen = newHalfOpenGate(b.halfOpenMaxConcurrent)
+	b.initMetrics()
 
 	// 初始化底层熔断器
 	b.cb = b.buildCircuitBreaker()
@@ -269,7 +300,7 @@ func (b *Breaker) buildSettings() gobreaker.Settings {
 
 	st := gobreaker.Settings{
 		Name:         b.name,
-		MaxRequests:  b.maxRequests,
+		MaxRequests:  b.halfOpenMaxRequests(),
 		Interval:     b.interval,
 		BucketPeriod: b.bucketPeriod,
 		Timeout:      b.timeout,
@@ -278,20 +309,21 @@ func (b *Breaker) buildSettings() gobreaker.Settings {
 		},
 	}
 
-	// 如果有自定义成功判定策略
-	if b.successPolicy != nil {
+	// 如果有自定义成功判定策略或错误分类函数
+	if b.successPolicy != nil || b.classifier != nil {
 		st.IsSuccessful = func(err error) bool {
-			return b.successPolicy.IsSuccessful(err)
+			// 延迟超阈值标记必须计为失败，不交给用户策略判定
+			return err != errLatencyExceeded && b.IsSuccessful(err)
 		}
 	}
 
-	// 如果有错误排除策略
-	if b.excludePolicy != nil {
+	// 如果有错误排除策略或错误分类函数
+	if b.excludePolicy != nil || b.classifier != nil {
 		// 设计决策: err == nil 视为成功（不可排除），与 Breaker.IsExcluded 公开方法
 		// 及 RetryThenBreak.toResultError 的语义对齐。避免成功调用被错误地排除出统计，
 		// 导致半开状态探测成功无法推动状态机关闭。
 		st.IsExcluded = func(err error) bool {
-			return err != nil && b.excludePolicy.IsExcluded(err)
+			return err != errLatencyExceeded && b.IsExcluded(err)
 		}
 	}
 
@@ -299,6 +331,29 @@ func (b *Breaker) buildSettings() gobreaker.Settings {
 		st.OnStateChange = wrapOnStateChange(b.onStateChange)
 	}
 
+	// 状态变化时清空延迟窗口，避免熔断前的慢样本让 HalfOpen 探测立即再次熔断。
+	// resetLatency 只持有策略自身的锁，可在 gobreaker mutex 内同步调用。
+	if b.latency != nil {
+		notify := st.OnStateChange
+		st.OnStateChange = func(name string, from, to gobreaker.State) {
+			b.latency.resetLatency()
+			if notify != nil {
+				notify(name, from, to)
+			}
+		}
+	}
+
+	// 同理清空调用结果窗口，避免熔断前的失败让恢复后的首次失败立即再次熔断
+	if b.outcomes != nil {
+		notify := st.OnStateChange
+		st.OnStateChange = func(name string, from, to gobreaker.State) {
+			b.outcomes.resetOutcomes()
+			if notify != nil {
+				notify(name, from, to)
+			}
+		}
+	}
+
 	return st
 }
 
@@ -319,8 +374,41 @@ func wrapOnStateChange(cb func(name string, from, to gobreaker.State)) func(stri
 }
 
 // buildCircuitBreaker 构建底层熔断器
+//
+// 设计决策: 预热与指标钩子只挂在 Breaker 自身的熔断器上，而不放进 buildSettings。
+// ManagedBreaker、RetryThenBreak 复用 buildSettings 但维护独立状态，
+// 若共享同一个 rampUpGate，它们的状态变化会错误地开始或结束 Breaker 的预热；
+// 共享指标则会让 xbreaker.state 在两个独立状态机之间来回跳变。
 func (b *Breaker) buildCircuitBreaker() *gobreaker.CircuitBreaker[any] {
-	return gobreaker.NewCircuitBreaker[any](b.buildSettings())
+	st := b.buildSettings()
+	if b.metrics != nil {
+		notify := st.OnStateChange
+		st.OnStateChange = func(name string, from, to gobreaker.State) {
+			b.metrics.recordStateChange(name, from, to)
+			if notify != nil {
+				notify(name, from, to)
+			}
+		}
+	}
+	if b.ramp != nil {
+		notify := st.OnStateChange
+		st.OnStateChange = func(name string, from, to gobreaker.State) {
+			b.ramp.onStateChange(from, to)
+			if notify != nil {
+				notify(name, from, to)
+			}
+		}
+	}
+	b.notifyStateChange = st.OnStateChange
+	if notify := st.OnStateChange; notify != nil {
+		// 强制期间对外状态固定，底层转换不通知，参见 setForced
+		st.OnStateChange = func(name string, from, to gobreaker.State) {
+			if b.forced.Load() == forceNone {
+				notify(name, from, to)
+			}
+		}
+	}
+	return gobreaker.NewCircuitBreaker[any](st)
 }
 
 // Do 执行受熔断器保护的操作
@@ -328,6 +416,9 @@ func (b *Breaker) buildCircuitBreaker() *gobreaker.CircuitBreaker[any] {
 // 如果 context 已取消或超时，直接返回 context 错误。
 // 如果熔断器处于 Open 状态，操作不会被执行，直接返回 ErrOpenState。
 // 如果熔断器处于 HalfOpen 状态且请求过多，返回 ErrTooManyRequests。
+// ForceOpen 期间同样直接返回 ErrOpenState，ForceClose 期间直接执行且不计入熔断统计。
+// 配置 WithRampUp 时，恢复后的预热期内未放行的请求同样返回 ErrTooManyRequests。
+// 配置 WithFallback 时，被拒绝的请求改为返回降级函数的错误（降级值被丢弃）。
 //
 // 注意：
 //   - context 仅用于入口检查，不会传递给底层操作
@@ -347,24 +438,55 @@ func (b *Breaker) Do(ctx context.Context, fn func() error) error {
 	if err := ctx.Err(); err != nil {
 		return err
 	}
+	if bypass, err := b.admitForced(); err != nil {
+		return b.fallbackDo(ctx, err)
+	} else if bypass {
+		fnErr := fn()
+		b.recordResult(fnErr)
+		return fnErr
+	}
+	if err := b.admitRampUp(); err != nil {
+		b.recordRejected()
+		return b.fallbackDo(ctx, err)
+	}
+	release, err := b.admitHalfOpen()
+	if err != nil {
+		b.recordRejected()
+		return b.fallbackDo(ctx, err)
+	}
+	defer release()
 
 	// 设计决策: called 标志区分"熔断器拒绝"和"业务函数返回 gobreaker sentinel"。
 	// 仅当 called == false 时才包装为 BreakerError，避免将业务错误误归因为熔断器拒绝。
+	// fnErr 保存 fn 的原始错误：配置 LatencyPolicy 时交给 gobreaker 的可能是延迟标记错误，
+	// 该值保存在 reported 中用于指标。
 	var called bool
-	_, err := b.cb.Execute(func() (any, error) {
+	var fnErr, reported error
+	_, err = b.cb.Execute(func() (any, error) {
 		called = true
-		return nil, fn()
+		if b.latency == nil && b.outcomes == nil {
+			fnErr = fn()
+			reported = fnErr
+			return nil, fnErr
+		}
+		start := time.Now()
+		fnErr = fn()
+		reported = b.observeCall(start, fnErr)
+		return nil, reported
 	})
 	if err != nil && !called {
-		return wrapBreakerError(err, b.name)
+		b.recordRejected()
+		return b.fallbackDo(ctx, wrapBreakerError(err, b.name))
 	}
-	return err
+	b.recordResult(reported)
+	return fnErr
 }
 
 // Execute 执行受熔断器保护的操作（泛型版本）
 //
 // 与 Do 类似，但支持返回值。
 // 如果 context 已取消或超时，直接返回 context 错误。
+// 配置 WithFallback 时，被拒绝的请求返回降级函数的结果。
 //
 // 注意：
 //   - 此函数是包级函数而非方法，因为 Go 不支持方法的类型参数
@@ -390,18 +512,51 @@ func Execute[T any](ctx context.Context, b *Breaker, fn func() (T, error)) (T, e
 	if err := ctx.Err(); err != nil {
 		return zero, err
 	}
+	if bypass, err := b.admitForced(); err != nil {
+		return fallbackExecute[T](ctx, b, err)
+	} else if bypass {
+		v, fnErr := fn()
+		b.recordResult(fnErr)
+		if fnErr != nil {
+			return zero, fnErr
+		}
+		return v, nil
+	}
+	if err := b.admitRampUp(); err != nil {
+		b.recordRejected()
+		return fallbackExecute[T](ctx, b, err)
+	}
+	release, err := b.admitHalfOpen()
+	if err != nil {
+		b.recordRejected()
+		return fallbackExecute[T](ctx, b, err)
+	}
+	defer release()
 
 	// 设计决策: called 标志区分"熔断器拒绝"和"业务函数返回 gobreaker sentinel"。
+	// fnErr、reported 语义同 Breaker.Do。
 	var called bool
+	var fnErr, reported error
 	result, err := b.cb.Execute(func() (any, error) {
 		called = true
-		return fn()
-	})
-	if err != nil {
-		if !called {
-			return zero, wrapBreakerError(err, b.name)
+		if b.latency == nil && b.outcomes == nil {
+			v, err := fn()
+			fnErr, reported = err, err
+			return v, err
 		}
-		return zero, err
+		start := time.Now()
+		v, err := fn()
+		fnErr = err
+		reported = b.observeCall(start, err)
+		return v, reported
+	})
+	if err != nil && !called {
+		b.recordRejected()
+		return fallbackExecute[T](ctx, b, wrapBreakerError(err, b.name))
+	}
+	b.recordResult(reported)
+	if fnErr != nil {
+		return zero, fnErr
 	}
 	// result 来自 fn()，类型始终为 T；nil 对应 T 的零值
 	if result == nil {
@@ -416,16 +571,43 @@ func Execute[T any](ctx context.Context, b *Breaker, fn func() (T, error)) (T, e
 	return typed, nil
 }
 
+// DoTyped 执行受熔断器保护的操作，fn 接收调用方的 context 并返回类型化结果
+//
+// 熔断语义（状态检查、计数、LatencyPolicy、指标）与 Execute 完全一致，
+// 区别仅在于 ctx 会传给 fn，便于 fn 内部的 I/O 感知取消与超时，签名与 xretry.DoWithResult 对齐。
+//
+// 设计决策: 参数顺序沿用本包和 xretry 的惯例（ctx 在前），基于 Execute 实现而非复制其流程，
+// 避免两份执行路径在后续演进中出现语义分叉。
+func DoTyped[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	// 保持 Execute 的入参校验顺序：b、ctx、fn
+	var call func() (T, error)
+	if fn != nil {
+		call = func() (T, error) { return fn(ctx) }
+	}
+	return Execute(ctx, b, call)
+}
+
 // State 返回熔断器当前状态
 //
 // 如果 b 为 nil，返回 StateClosed（零值）。
+// ForceOpen/ForceClose 期间返回强制的状态。
 func (b *Breaker) State() State {
 	if b == nil {
 		return StateClosed
 	}
+	if state, ok := b.forcedState(); ok {
+		return state
+	}
 	return b.cb.State()
 }
 
+// IsOpen 报告熔断器当前是否处于打开状态，实现 xretry.CircuitChecker
+//
+// 如果 b 为 nil，返回 false。
+func (b *Breaker) IsOpen() bool {
+	return b.State() == StateOpen
+}
+
 // Name 返回熔断器名称
 func (b *Breaker) Name() string {
 	return b.name
@@ -475,8 +657,15 @@ func (b *Breaker) ExcludePolicy() ExcludePolicy {
 
 // IsSuccessful 判断操作结果是否成功
 //
-// 如果设置了自定义 SuccessPolicy，使用它判断；否则使用默认的 err == nil 判断。
+// 错误分类函数返回 OutcomeSuccess/OutcomeFailure 时以其为准；
+// 否则如果设置了自定义 SuccessPolicy，使用它判断；否则使用默认的 err == nil 判断。
 func (b *Breaker) IsSuccessful(err error) bool {
+	switch b.classify(err) {
+	case OutcomeSuccess:
+		return true
+	case OutcomeFailure:
+		return false
+	}
 	if b.successPolicy != nil {
 		return b.successPolicy.IsSuccessful(err)
 	}
@@ -485,8 +674,15 @@ func (b *Breaker) IsSuccessful(err error) bool {
 
 // IsExcluded 判断错误是否应被排除在统计之外
 //
-// 如果设置了 ExcludePolicy 且 err 非 nil，使用它判断；否则返回 false。
+// 错误分类函数返回 OutcomeIgnore 时排除，返回 OutcomeSuccess/OutcomeFailure 时不排除；
+// 否则如果设置了 ExcludePolicy 且 err 非 nil，使用它判断；否则返回 false。
 func (b *Breaker) IsExcluded(err error) bool {
+	switch b.classify(err) {
+	case OutcomeIgnore:
+		return true
+	case OutcomeSuccess, OutcomeFailure:
+		return false
+	}
 	if b.excludePolicy != nil && err != nil {
 		return b.excludePolicy.IsExcluded(err)
 	}
@@ -572,6 +768,11 @@ func (m *ManagedBreaker[T]) State() State {
 	return m.cb.State()
 }
 
+// IsOpen 报告熔断器当前是否处于打开状态，实现 xretry.CircuitChecker
+func (m *ManagedBreaker[T]) IsOpen() bool {
+	return m.State() == StateOpen
+}
+
 // Counts 返回当前统计计数
 //
 // 设计决策: 先调用 State() 触发窗口过期刷新，再读 Counts。参见 Breaker.Counts 注释。
@@ -170,6 +170,7 @@ func TestBreaker_State(t *testing.T) {
 
 	// 初始状态：Closed
 	assert.Equal(t, StateClosed, b.State())
+	assert.False(t, b.IsOpen())
 
 	// 第一次失败
 	_ = b.Do(ctx, func() error { return errTest })
@@ -178,10 +179,12 @@ func TestBreaker_State(t *testing.T) {
 	// 第二次失败，触发熔断
 	_ = b.Do(ctx, func() error { return errTest })
 	assert.Equal(t, StateOpen, b.State())
+	assert.True(t, b.IsOpen())
 
 	// 等待超时，进入 HalfOpen
 	time.Sleep(150 * time.Millisecond)
 	assert.Equal(t, StateHalfOpen, b.State())
+	assert.False(t, b.IsOpen())
 
 	// 成功一次，恢复 Closed
 	_ = b.Do(ctx, func() error { return nil })
@@ -293,6 +296,7 @@ func TestManagedBreaker(t *testing.T) {
 
 		// 现在熔断器应该打开了
 		assert.Equal(t, StateOpen, m.State())
+		assert.True(t, m.IsOpen())
 
 		// 再次执行，应该返回 BreakerError
 		_, err = m.Execute(func() (string, error) {
@@ -534,6 +538,53 @@ func TestExecute_NilBreaker(t *testing.T) {
 	assert.ErrorIs(t, err, ErrNilBreaker)
 }
 
+func TestDoTyped(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("passes context and returns typed value", func(t *testing.T) {
+		b := NewBreaker("test")
+		ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+
+		got, err := DoTyped(ctx, b, func(ctx context.Context) (string, error) {
+			v, _ := ctx.Value(ctxKey{}).(string)
+			return v, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "v", got)
+		assert.Equal(t, uint32(1), b.Counts().TotalSuccesses)
+	})
+
+	t.Run("failure trips breaker", func(t *testing.T) {
+		b := NewBreaker("test",
+			WithTripPolicy(NewConsecutiveFailures(1)),
+			WithTimeout(time.Hour),
+		)
+		ctx := context.Background()
+
+		got, err := DoTyped(ctx, b, func(context.Context) (int, error) { return 42, errTest })
+		assert.ErrorIs(t, err, errTest)
+		assert.Zero(t, got)
+
+		called := false
+		_, err = DoTyped(ctx, b, func(context.Context) (int, error) {
+			called = true
+			return 1, nil
+		})
+		assert.True(t, IsOpen(err))
+		assert.False(t, called)
+	})
+
+	t.Run("argument validation", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := DoTyped(ctx, nil, func(context.Context) (int, error) { return 0, nil })
+		assert.ErrorIs(t, err, ErrNilBreaker)
+		_, err = DoTyped[int](ctx, NewBreaker("test"), nil)
+		assert.ErrorIs(t, err, ErrNilFunc)
+		_, err = DoTyped(nil, NewBreaker("test"), func(context.Context) (int, error) { return 0, nil }) //nolint:staticcheck // 测试 nil context 入口
+		assert.ErrorIs(t, err, ErrNilContext)
+	})
+}
+
 func TestWithExcludePolicy(t *testing.T) {
 	// 自定义排除策略：context.Canceled 被排除在统计之外
 	excludePolicy := &testExcludePolicy{
@@ -785,6 +836,11 @@ func TestBreaker_NilReceiver(t *testing.T) {
 		assert.Equal(t, StateClosed, b.State())
 	})
 
+	t.Run("IsOpen returns false", func(t *testing.T) {
+		var b *Breaker
+		assert.False(t, b.IsOpen())
+	})
+
 	t.Run("Counts returns zero value", func(t *testing.T) {
 		var b *Breaker
 		counts := b.Counts()
@@ -0,0 +1,88 @@
+package xbreaker
+
+// Outcome 错误分类结果，决定一次调用如何计入熔断统计
+type Outcome int
+
+const (
+	// OutcomeDefault 不做判定，交给 SuccessPolicy / ExcludePolicy 及默认规则处理
+	OutcomeDefault Outcome = iota
+	// OutcomeSuccess 计为成功
+	OutcomeSuccess
+	// OutcomeFailure 计为失败
+	OutcomeFailure
+	// OutcomeIgnore 排除在统计之外，不计入成功或失败
+	OutcomeIgnore
+)
+
+// String 返回分类结果名称
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeDefault:
+		return "default"
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	case OutcomeIgnore:
+		return "ignore"
+	default:
+		return "unknown"
+	}
+}
+
+// Classifier 错误分类函数，把操作返回的非 nil 错误映射为 Outcome
+type Classifier func(err error) Outcome
+
+// WithClassifier 设置错误分类函数
+//
+// 分类函数按错误类型/包装链（errors.Is、errors.As）把错误映射为成功、失败或忽略，
+// 比分别配置 SuccessPolicy 和 ExcludePolicy 更直观，例如下游 429/503 计为失败、400 忽略。
+//
+// 优先级（从高到低）：
+//  1. err == nil：不调用分类函数，交给 SuccessPolicy（默认成功）
+//  2. Classifier：返回 OutcomeSuccess/OutcomeFailure/OutcomeIgnore 时直接采用，
+//     不再咨询 SuccessPolicy 和 ExcludePolicy
+//  3. ExcludePolicy：分类函数返回 OutcomeDefault 时，先判断是否排除
+//  4. SuccessPolicy：未被排除时判断成功与否（默认 err == nil）
+//
+// LatencyPolicy 判定延迟超阈值的调用始终计为失败，不受分类函数影响。
+//
+// 示例：
+//
+//	breaker := xbreaker.NewBreaker("payment",
+//	    xbreaker.WithClassifier(func(err error) xbreaker.Outcome {
+//	        var he *HTTPError
+//	        if !errors.As(err, &he) {
+//	            return xbreaker.OutcomeDefault
+//	        }
+//	        switch {
+//	        case he.Status == 429 || he.Status >= 500:
+//	            return xbreaker.OutcomeFailure
+//	        case he.Status >= 400:
+//	            return xbreaker.OutcomeIgnore
+//	        }
+//	        return xbreaker.OutcomeDefault
+//	    }),
+//	)
+func WithClassifier(fn Classifier) BreakerOption {
+	return func(b *Breaker) {
+		if fn != nil {
+			b.classifier = fn
+		}
+	}
+}
+
+// Classifier 返回当前错误分类函数
+//
+// 如果未设置，返回 nil
+func (b *Breaker) Classifier() Classifier {
+	return b.classifier
+}
+
+// classify 对非 nil 错误调用分类函数，未设置或 err 为 nil 时返回 OutcomeDefault
+func (b *Breaker) classify(err error) Outcome {
+	if b.classifier == nil || err == nil {
+		return OutcomeDefault
+	}
+	return b.classifier(err)
+}
@@ -0,0 +1,121 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/omeyang/xkit/pkg/resilience/xretry"
+)
+
+// statusError 模拟携带 HTTP 状态码的下游错误
+type statusError struct{ code int }
+
+func (e *statusError) Error() string { return fmt.Sprintf("status %d", e.code) }
+
+// classifyStatus 429/5xx 计为失败，其余 4xx 忽略
+func classifyStatus(err error) Outcome {
+	var se *statusError
+	if !errors.As(err, &se) {
+		return OutcomeDefault
+	}
+	switch {
+	case se.code == 429 || se.code >= 500:
+		return OutcomeFailure
+	case se.code >= 400:
+		return OutcomeIgnore
+	}
+	return OutcomeSuccess
+}
+
+func TestOutcome_String(t *testing.T) {
+	assert.Equal(t, "default", OutcomeDefault.String())
+	assert.Equal(t, "success", OutcomeSuccess.String())
+	assert.Equal(t, "failure", OutcomeFailure.String())
+	assert.Equal(t, "ignore", OutcomeIgnore.String())
+	assert.Equal(t, "unknown", Outcome(99).String())
+}
+
+func TestWithClassifier(t *testing.T) {
+	ctx := context.Background()
+	b := NewBreaker("classifier",
+		WithTripPolicy(NewConsecutiveFailures(2)),
+		WithClassifier(classifyStatus),
+		WithTimeout(time.Hour),
+	)
+	require.NotNil(t, b.Classifier())
+
+	// 400 被忽略，不计入任何计数
+	for range 3 {
+		_ = b.Do(ctx, func() error { return fmt.Errorf("call: %w", &statusError{code: 400}) })
+	}
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, uint32(3), b.Counts().TotalExclusions)
+
+	// 429、503 计为失败
+	_ = b.Do(ctx, func() error { return &statusError{code: 429} })
+	_ = b.Do(ctx, func() error { return &statusError{code: 503} })
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestWithClassifier_Precedence(t *testing.T) {
+	b := NewBreaker("precedence",
+		WithClassifier(classifyStatus),
+		// 策略与分类函数的判定相反，验证分类函数优先
+		WithExcludePolicy(excludeFunc(func(error) bool { return true })),
+		WithSuccessPolicy(successFunc(func(err error) bool { return err != nil })),
+	)
+
+	// 分类函数给出结论时不咨询策略
+	assert.False(t, b.IsExcluded(&statusError{code: 503}))
+	assert.False(t, b.IsSuccessful(&statusError{code: 503}))
+	assert.True(t, b.IsExcluded(&statusError{code: 400}))
+	assert.False(t, b.IsExcluded(&statusError{code: 200}))
+	assert.True(t, b.IsSuccessful(&statusError{code: 200}))
+
+	// OutcomeDefault 交给 ExcludePolicy / SuccessPolicy
+	assert.True(t, b.IsExcluded(errTest))
+	assert.True(t, b.IsSuccessful(errTest))
+
+	// err == nil 不经过分类函数，也不可排除
+	assert.False(t, b.IsExcluded(nil))
+	assert.False(t, b.IsSuccessful(nil))
+}
+
+func TestWithClassifier_Nil(t *testing.T) {
+	b := NewBreaker("nil", WithClassifier(nil))
+	assert.Nil(t, b.Classifier())
+	assert.Equal(t, OutcomeDefault, b.classify(errTest))
+}
+
+func TestWithClassifier_SuccessOutcomeResetsFailures(t *testing.T) {
+	ctx := context.Background()
+	b := NewBreaker("success",
+		WithTripPolicy(NewConsecutiveFailures(2)),
+		WithClassifier(classifyStatus),
+	)
+
+	_ = b.Do(ctx, func() error { return &statusError{code: 503} })
+	// 3xx 被分类为成功，打断连续失败
+	err := b.Do(ctx, func() error { return &statusError{code: 304} })
+	assert.Error(t, err, "classification does not swallow the error")
+	_ = b.Do(ctx, func() error { return &statusError{code: 503} })
+
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, uint32(1), b.Counts().TotalSuccesses)
+}
+
+func TestRetryThenBreak_Classifier(t *testing.T) {
+	retryer := xretry.NewRetryer(xretry.WithRetryPolicy(xretry.NewFixedRetry(1)))
+	rtb, err := NewRetryThenBreakWithConfig("rtb", retryer, WithClassifier(classifyStatus))
+	require.NoError(t, err)
+
+	assert.Nil(t, rtb.toResultError(&statusError{code: 200}))
+	ignored := &statusError{code: 400}
+	assert.Equal(t, error(ignored), rtb.toResultError(ignored))
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/sony/gobreaker/v2"
 )
 
+// 确保熔断器可作为 xretry.WithCircuitBreaker 的状态来源
+var (
+	_ xretry.CircuitChecker = (*Breaker)(nil)
+	_ xretry.CircuitChecker = (*ManagedBreaker[any])(nil)
+)
+
 // BreakerRetryer 熔断器+重试组合执行器
 //
 // 组合熔断器和重试器，提供更强大的容错能力：
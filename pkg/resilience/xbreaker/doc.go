@@ -16,9 +16,25 @@
 // 内置策略（TripPolicy）：
 //   - ConsecutiveFailuresPolicy：连续失败 N 次后熔断
 //   - FailureRatioPolicy：失败率超过阈值后熔断
+//   - SlidingWindowRatioPolicy：最近一段时间（分桶滑动窗口）内失败率超过阈值后熔断
 //   - FailureCountPolicy：失败次数超过阈值后熔断
 //   - CompositePolicy：组合多个策略
 //   - SlowCallRatioPolicy：慢调用熔断（基于 FailureRatioPolicy，需配合 SuccessPolicy 使用）
+//   - LatencyPolicy：响应时间熔断，最近 N 次调用的分位数延迟（默认 P99）超过阈值后熔断
+//
+// LatencyPolicy 的耗时由 Breaker.Do / Execute 自动测量，调用即使没有返回错误，
+// 只要分位数延迟超过阈值也会触发熔断，用于"下游变慢但未报错"的场景。
+// 可单独使用，也可放入 CompositePolicy 与失败类策略组合。
+//
+// FailureRatioPolicy 基于 gobreaker 的计数，配合 WithInterval 时计数在周期边界整体清零，
+// 同样的失败序列是否熔断取决于它落在周期的哪个位置。SlidingWindowRatioPolicy 自行维护
+// 固定桶数的环形窗口，旧桶逐个过期，判定更平滑；代价是每个策略多出 buckets 个桶的内存
+// 和每次调用一次加锁。与 LatencyPolicy 一样，其调用结果由 Breaker.Do / Execute 上报。
+//
+// # 执行方式
+//
+// Breaker.Do 执行无返回值的操作；Execute[T] 与 DoTyped[T] 返回类型化结果，无需类型断言。
+// DoTyped 会把 ctx 传给 fn，签名与 xretry.DoWithResult 一致；热路径可使用 ManagedBreaker[T]。
 //
 // # 组合模式
 //
@@ -36,10 +52,43 @@
 // 可通过 WithExcludePolicy 设置错误排除策略。
 // 若需将特定错误标记为"成功"（计入成功计数），请使用 WithSuccessPolicy。
 //
+// 按错误类型区分时可用 WithClassifier 一次性把错误映射为 OutcomeSuccess、
+// OutcomeFailure 或 OutcomeIgnore（如 429/503 计为失败、400 忽略）。
+// 分类函数先于 ExcludePolicy 和 SuccessPolicy 判定，返回 OutcomeDefault 时才交给这两个策略；
+// err == nil 不经过分类函数。
+//
 // 比率类策略（FailureRatioPolicy、SlowCallRatioPolicy）在计算失败率时，
 // 使用有效请求数（Requests - TotalExclusions）作为分母，
 // 确保被排除的请求不会稀释失败率或虚增 minRequests 判定基数。
 //
+// # 半开探测
+//
+// HalfOpen 期间 gobreaker 最多放行 MaxRequests 个探测，连续成功 MaxRequests 次后恢复。
+// WithHalfOpenRequiredSuccesses(n) 设置恢复所需的连续成功次数（即 MaxRequests），
+// WithHalfOpenMaxConcurrent(n) 额外限制同时在途的探测数，超出的请求返回
+// ErrTooManyRequests（不计入统计）。两者组合可实现"逐个探测、连续多次成功才恢复"，
+// 避免一波并发探测打垮刚恢复的下游。
+//
+// # 恢复预热
+//
+// WithRampUp 让熔断器从 HalfOpen 恢复到 Closed 后逐步放行：预热期内放行比例
+// 从 10% 线性升至 100%，避免恢复瞬间的全量流量再次打垮下游。
+// 未放行的请求返回 ErrTooManyRequests（不计入统计），预热期间再次熔断会结束本轮预热。
+// Breaker.RampUpRatio 返回当前放行比例，可用于监控恢复进度。
+//
+// # 降级
+//
+// WithFallback 设置请求被熔断器拒绝（Open、ForceOpen、HalfOpen 请求过多、预热未放行）时执行的降级函数，
+// 其结果代替熔断器错误返回，集中实现"熔断时返回缓存值/默认值"。WithFallbackTyped[T] 在编译期约束降级值类型。
+// 降级结果不计入熔断统计；context 取消等非熔断器拒绝的错误不触发降级。
+//
+// # 手动覆盖
+//
+// 维护窗口内可通过 Breaker.ForceOpen 强制打开熔断器（所有请求快速失败，主动卸载流量），
+// 或通过 Breaker.ForceClose 强制关闭（绕过判定直接放行，压制抖动的熔断器）。
+// 强制期间熔断计数暂停、State() 固定为强制的状态；Breaker.ClearForce 解除后回到强制前的底层状态。
+// 强制与解除同样触发 WithOnStateChange 回调并更新指标，观察方能看到人工覆盖。
+//
 // # 状态变化回调
 //
 // WithOnStateChange 注册的回调通过 goroutine 异步执行，
@@ -53,5 +102,12 @@
 // 不设背压机制。若回调执行缓慢且状态快速振荡，goroutine 可能累积。
 // 需要在回调中执行 I/O 的场景，请自行实现有界队列+消费者模式。
 //
+// # 指标
+//
+// WithMeterProvider 启用 OpenTelemetry 指标：xbreaker.state（0=closed、1=half_open、2=open，
+// 状态转换时同步更新）、xbreaker.state_changes.total（带 from/to）与
+// xbreaker.requests.total（result 为 success/failure/excluded/rejected），均带 breaker 属性。
+// 对 xbreaker.state == 2 设置持续时间条件即可告警"熔断持续超过 N 秒"。
+//
 // [sony/gobreaker/v2]: https://github.com/sony/gobreaker
 package xbreaker
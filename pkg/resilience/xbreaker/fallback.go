@@ -0,0 +1,95 @@
+package xbreaker
+
+import (
+	"context"
+	"fmt"
+)
+
+// FallbackFunc 请求被熔断器拒绝时执行的降级函数
+//
+// err 为本应返回给调用方的熔断器错误（*BreakerError，包装 ErrOpenState 或 ErrTooManyRequests），
+// 可用 IsOpen/IsTooManyRequests 区分拒绝原因。返回值代替熔断器错误返回给调用方。
+type FallbackFunc func(ctx context.Context, err error) (any, error)
+
+// WithFallback 设置请求被熔断器拒绝时执行的降级函数
+//
+// 熔断器本应直接返回熔断器错误时（Open、ForceOpen、HalfOpen 请求过多、
+// 半开并发限制或预热期未放行），改为调用 fn 并返回其结果，
+// 把"熔断时返回缓存值/默认值"集中到熔断器配置中，不必在每个调用点重复处理。
+//
+// 降级函数的结果不计入熔断统计（此时操作本身未执行），指标中仍记为 rejected。
+// Do 丢弃降级值，只返回 fn 的错误；Execute/DoTyped 按 T 返回降级值，
+// 类型不匹配时返回错误，推荐使用 WithFallbackTyped 在编译期约束类型。
+//
+// 注意：
+//   - fn 返回 nil 错误时调用方看不到熔断器错误，与 BreakerRetryer 组合时不会触发停止重试的判断；
+//     需要区分降级结果时由 fn 自行标记
+//   - 降级仅作用于 [Breaker.Do]、[Execute]、[DoTyped]；ManagedBreaker、RetryThenBreak 不受影响
+//   - context 已取消、参数校验失败等非熔断器拒绝的错误不触发降级
+//
+// 示例：
+//
+//	breaker := xbreaker.NewBreaker("user-service",
+//	    xbreaker.WithFallback(func(ctx context.Context, err error) (any, error) {
+//	        return cache.Get(ctx, "user:default")
+//	    }),
+//	)
+func WithFallback(fn FallbackFunc) BreakerOption {
+	return func(b *Breaker) {
+		if fn != nil {
+			b.fallback = fn
+		}
+	}
+}
+
+// WithFallbackTyped 设置返回 T 类型降级值的降级函数，语义同 WithFallback
+//
+// 降级值的类型在编译期确定，配合 Execute[T]/DoTyped[T] 使用时无需类型断言。
+// 设计决策: Go 不支持方法的类型参数，Breaker 本身不携带结果类型，
+// 因此以泛型构造函数把 fn 适配为 FallbackFunc；与 Execute[T] 的 T 不一致时返回错误。
+//
+// 示例：
+//
+//	breaker := xbreaker.NewBreaker("price-service",
+//	    xbreaker.WithFallbackTyped(func(ctx context.Context, err error) (Price, error) {
+//	        return defaultPrice, nil
+//	    }),
+//	)
+//	price, err := xbreaker.Execute(ctx, breaker, fetchPrice)
+func WithFallbackTyped[T any](fn func(ctx context.Context, err error) (T, error)) BreakerOption {
+	if fn == nil {
+		return WithFallback(nil)
+	}
+	return WithFallback(func(ctx context.Context, err error) (any, error) {
+		return fn(ctx, err)
+	})
+}
+
+// fallbackDo 返回 Do 被拒绝时的结果：未配置降级函数时返回 rejection，否则返回降级函数的错误
+func (b *Breaker) fallbackDo(ctx context.Context, rejection error) error {
+	if b.fallback == nil {
+		return rejection
+	}
+	_, err := b.fallback(ctx, rejection)
+	return err
+}
+
+// fallbackExecute 返回 Execute 被拒绝时的结果，把降级值转换为 T
+func fallbackExecute[T any](ctx context.Context, b *Breaker, rejection error) (T, error) {
+	var zero T
+	if b.fallback == nil {
+		return zero, rejection
+	}
+	v, err := b.fallback(ctx, rejection)
+	if err != nil {
+		return zero, err
+	}
+	if v == nil {
+		return zero, nil
+	}
+	typed, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("xbreaker: fallback returned %T, want %T: %w", v, zero, rejection)
+	}
+	return typed, nil
+}
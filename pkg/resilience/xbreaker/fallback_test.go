@@ -0,0 +1,150 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFallback_Open(t *testing.T) {
+	ctx := context.Background()
+	var gotErr error
+	b := NewBreaker("fallback-open",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(time.Hour),
+		WithFallback(func(_ context.Context, err error) (any, error) {
+			gotErr = err
+			return "cached", nil
+		}),
+	)
+
+	// Closed 状态下业务错误原样返回，不触发降级
+	assert.ErrorIs(t, b.Do(ctx, func() error { return errTest }), errTest)
+	assert.Nil(t, gotErr)
+	require.Equal(t, StateOpen, b.State())
+	before := b.Counts()
+
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	assert.True(t, IsOpen(gotErr))
+
+	v, err := Execute(ctx, b, func() (string, error) { return "live", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "cached", v)
+
+	v, err = DoTyped(ctx, b, func(context.Context) (string, error) { return "live", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "cached", v)
+
+	assert.Equal(t, before, b.Counts(), "fallback results are not counted")
+}
+
+func TestWithFallback_ErrorNotCounted(t *testing.T) {
+	ctx := context.Background()
+	errFallback := errors.New("fallback failed")
+	b := NewBreaker("fallback-error",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(time.Hour),
+		WithFallback(func(context.Context, error) (any, error) { return nil, errFallback }),
+	)
+	b.ForceOpen()
+
+	assert.ErrorIs(t, b.Do(ctx, func() error { return nil }), errFallback)
+	_, err := Execute(ctx, b, func() (int, error) { return 1, nil })
+	assert.ErrorIs(t, err, errFallback)
+	assert.Equal(t, Counts{}, b.Counts())
+}
+
+func TestWithFallback_HalfOpenRejected(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var rejections []error
+	b := NewBreaker("fallback-halfopen",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithHalfOpenMaxConcurrent(1),
+		WithFallbackTyped(func(_ context.Context, err error) (int, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			rejections = append(rejections, err)
+			return -1, nil
+		}),
+	)
+	tripAndWaitHalfOpen(t, b)
+
+	release, err := b.admitHalfOpen()
+	require.NoError(t, err)
+	defer release()
+
+	v, err := Execute(ctx, b, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, -1, v)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, rejections, 1)
+	assert.True(t, IsTooManyRequests(rejections[0]))
+}
+
+func TestWithFallbackTyped_TypeMismatch(t *testing.T) {
+	b := NewBreaker("fallback-mismatch",
+		WithFallbackTyped(func(context.Context, error) (string, error) { return "default", nil }),
+	)
+	b.ForceOpen()
+
+	_, err := Execute(context.Background(), b, func() (int, error) { return 1, nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fallback returned string, want int")
+	assert.True(t, IsOpen(err), "type mismatch keeps the breaker rejection in the chain")
+}
+
+func TestWithFallback_NilValue(t *testing.T) {
+	b := NewBreaker("fallback-nil",
+		WithFallback(func(context.Context, error) (any, error) { return nil, nil }),
+	)
+	b.ForceOpen()
+
+	v, err := Execute(context.Background(), b, func() (*int, error) { return new(int), nil })
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestWithFallback_NotTriggered(t *testing.T) {
+	called := false
+	b := NewBreaker("fallback-skip",
+		WithFallback(func(context.Context, error) (any, error) {
+			called = true
+			return nil, nil
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, b.Do(ctx, func() error { return nil }), context.Canceled)
+	assert.ErrorIs(t, b.Do(context.Background(), nil), ErrNilFunc)
+	assert.False(t, called, "only breaker rejections trigger fallback")
+}
+
+func TestWithFallback_NilIgnored(t *testing.T) {
+	b := NewBreaker("fallback-nil-option", WithFallback(nil), WithFallbackTyped[int](nil))
+	assert.Nil(t, b.fallback)
+
+	b.ForceOpen()
+	assert.True(t, IsOpen(b.Do(context.Background(), func() error { return nil })))
+}
+
+func TestWithFallback_Metrics(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	b := NewBreaker("fallback-metrics",
+		WithMeterProvider(provider),
+		WithFallback(func(context.Context, error) (any, error) { return nil, nil }),
+	)
+	b.ForceOpen()
+	require.NoError(t, b.Do(context.Background(), func() error { return nil }))
+
+	assert.Equal(t, map[string]int64{resultRejected: 1}, requestCounts(t, reader))
+}
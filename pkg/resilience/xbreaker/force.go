@@ -0,0 +1,107 @@
+package xbreaker
+
+import (
+	"github.com/sony/gobreaker/v2"
+)
+
+// 强制状态（Breaker.forced）
+const (
+	forceNone   int32 = iota // 未强制，由熔断策略判定状态
+	forceOpen                // 强制打开：所有请求快速失败
+	forceClosed              // 强制关闭：所有请求直接放行
+)
+
+// ForceOpen 强制打开熔断器
+//
+// 强制期间所有请求不执行操作，返回包装了 ErrOpenState 的 BreakerError（State 为 StateOpen），
+// State() 固定返回 StateOpen。用于维护窗口内主动对即将下线的下游快速失败、卸载流量。
+//
+// 强制期间熔断计数暂停，底层熔断器保持强制前的状态，调用 ClearForce 后恢复由熔断策略判定。
+// 强制状态与 ForceClose 互相覆盖，重复调用无副作用。
+//
+// 注意：强制状态仅作用于 [Breaker.Do]、[Execute]、[DoTyped]（含基于它们的 BreakerRetryer）；
+// ManagedBreaker、RetryThenBreak 维护独立状态，不受影响。
+func (b *Breaker) ForceOpen() {
+	b.setForced(forceOpen)
+}
+
+// ForceClose 强制关闭熔断器
+//
+// 强制期间所有请求绕过熔断判定直接执行，结果不计入熔断统计，State() 固定返回 StateClosed。
+// 用于人工压制反复抖动的熔断器，或在确认下游已恢复时立即放行全部流量。
+// 预热（WithRampUp）与半开并发限制在强制期间同样不生效。
+func (b *Breaker) ForceClose() {
+	b.setForced(forceClosed)
+}
+
+// ClearForce 解除强制状态，恢复由熔断策略判定
+//
+// 解除后熔断器回到强制前的底层状态：例如强制前已熔断，则仍为 Open（Timeout 照常计时），
+// 强制期间的请求不会影响恢复后的判定。未处于强制状态时调用无副作用。
+func (b *Breaker) ClearForce() {
+	b.setForced(forceNone)
+}
+
+// Forced 报告熔断器当前是否处于 ForceOpen/ForceClose 设置的强制状态
+func (b *Breaker) Forced() bool {
+	return b != nil && b.forced.Load() != forceNone
+}
+
+// setForced 切换强制状态，状态实际变化时触发状态变化通知
+//
+// 设计决策: 强制与解除同样触发 OnStateChange（含指标与预热钩子），观察方能看到人工覆盖；
+// 强制期间底层熔断器的惰性转换（如 Open→HalfOpen）不对外通知，避免与固定的对外状态矛盾，
+// 解除时直接通知"强制状态→底层当前状态"。
+//
+// 设计决策: 通知在释放 forceMu 后执行，回调（如告警钩子）可以再次调用
+// ForceOpen/ForceClose/ClearForce 而不会死锁；代价是并发切换时通知顺序可能与切换顺序不一致。
+func (b *Breaker) setForced(mode int32) {
+	if b == nil {
+		return
+	}
+	b.forceMu.Lock()
+	if b.forced.Load() == mode {
+		b.forceMu.Unlock()
+		return
+	}
+	from := b.State()
+	var to State
+	switch mode {
+	case forceOpen:
+		to = StateOpen
+	case forceClosed:
+		to = StateClosed
+	default:
+		// 仍处于强制状态时读取底层状态，期间的惰性转换不会被单独通知
+		to = b.cb.State()
+	}
+	b.forced.Store(mode)
+	b.forceMu.Unlock()
+
+	if from != to && b.notifyStateChange != nil {
+		b.notifyStateChange(b.name, from, to)
+	}
+}
+
+// forcedState 返回强制状态下对外呈现的状态，ok 为 false 表示未强制
+func (b *Breaker) forcedState() (state State, ok bool) {
+	switch b.forced.Load() {
+	case forceOpen:
+		return StateOpen, true
+	case forceClosed:
+		return StateClosed, true
+	}
+	return StateClosed, false
+}
+
+// admitForced 处理强制状态：强制打开时返回熔断器错误，强制关闭时 bypass 为 true（绕过熔断器直接执行）
+func (b *Breaker) admitForced() (bypass bool, err error) {
+	switch b.forced.Load() {
+	case forceOpen:
+		b.recordRejected()
+		return false, newBreakerError(gobreaker.ErrOpenState, b.name, StateOpen)
+	case forceClosed:
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,216 @@
+package xbreaker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transitionRecorder 记录异步状态变化回调
+type transitionRecorder struct {
+	mu     sync.Mutex
+	events [][2]State
+}
+
+func (r *transitionRecorder) record(_ string, from, to State) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, [2]State{from, to})
+}
+
+// waitFor 等待记录到 want 中的全部转换（回调异步执行，不比较顺序）
+func (r *transitionRecorder) waitFor(t *testing.T, want ...[2]State) {
+	t.Helper()
+	var got [][2]State
+	require.Eventually(t, func() bool {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		got = append(got[:0], r.events...)
+		return len(got) >= len(want)
+	}, time.Second, time.Millisecond, "want transitions %v", want)
+	assert.ElementsMatch(t, want, got)
+}
+
+func TestBreaker_ForceOpen(t *testing.T) {
+	rec := &transitionRecorder{}
+	b := NewBreaker("force-open", WithOnStateChange(rec.record))
+	ctx := context.Background()
+
+	b.ForceOpen()
+	b.ForceOpen() // 重复调用无副作用
+	assert.True(t, b.Forced())
+	assert.Equal(t, StateOpen, b.State())
+	assert.True(t, b.IsOpen())
+
+	called := false
+	err := b.Do(ctx, func() error {
+		called = true
+		return nil
+	})
+	assert.False(t, called, "operation must not run while forced open")
+	assert.True(t, IsOpen(err))
+	var be *BreakerError
+	require.ErrorAs(t, err, &be)
+	assert.Equal(t, StateOpen, be.State)
+
+	_, err = Execute(ctx, b, func() (int, error) { return 1, nil })
+	assert.True(t, IsOpen(err))
+
+	b.ClearForce()
+	assert.False(t, b.Forced())
+	assert.Equal(t, StateClosed, b.State())
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+
+	rec.waitFor(t, [2]State{StateClosed, StateOpen}, [2]State{StateOpen, StateClosed})
+}
+
+func TestBreaker_ForceClose(t *testing.T) {
+	rec := &transitionRecorder{}
+	b := NewBreaker("force-close",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(time.Hour),
+		WithOnStateChange(rec.record),
+	)
+	ctx := context.Background()
+
+	_ = b.Do(ctx, func() error { return errTest })
+	require.Equal(t, StateOpen, b.State())
+	before := b.Counts()
+
+	b.ForceClose()
+	assert.Equal(t, StateClosed, b.State())
+	assert.False(t, b.IsOpen())
+
+	// 强制关闭期间直接执行，失败不计入统计
+	assert.ErrorIs(t, b.Do(ctx, func() error { return errTest }), errTest)
+	v, err := Execute(ctx, b, func() (int, error) { return 42, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 42, v)
+	_, err = Execute(ctx, b, func() (int, error) { return 42, errTest })
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, before, b.Counts())
+
+	// 解除后回到强制前的底层状态
+	b.ClearForce()
+	assert.Equal(t, StateOpen, b.State())
+
+	rec.waitFor(t,
+		[2]State{StateClosed, StateOpen},
+		[2]State{StateOpen, StateClosed},
+		[2]State{StateClosed, StateOpen},
+	)
+}
+
+func TestBreaker_Force_SuppressesUnderlyingTransitions(t *testing.T) {
+	rec := &transitionRecorder{}
+	b := NewBreaker("force-suppress",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithOnStateChange(rec.record),
+	)
+
+	_ = b.Do(context.Background(), func() error { return errTest })
+	b.ForceClose()
+
+	// 底层 Open→HalfOpen 的惰性转换在强制期间发生，不对外通知
+	time.Sleep(30 * time.Millisecond)
+	_ = b.Counts()
+	assert.Equal(t, StateClosed, b.State())
+
+	b.ClearForce()
+	assert.Equal(t, StateHalfOpen, b.State())
+
+	rec.waitFor(t,
+		[2]State{StateClosed, StateOpen},
+		[2]State{StateOpen, StateClosed},
+		[2]State{StateClosed, StateHalfOpen},
+	)
+}
+
+func TestBreaker_Force_Switch(t *testing.T) {
+	rec := &transitionRecorder{}
+	b := NewBreaker("force-switch", WithOnStateChange(rec.record))
+
+	b.ForceOpen()
+	b.ForceClose()
+	assert.Equal(t, StateClosed, b.State())
+	b.ClearForce() // 底层同为 Closed，不触发通知
+	b.ClearForce()
+
+	rec.waitFor(t, [2]State{StateClosed, StateOpen}, [2]State{StateOpen, StateClosed})
+}
+
+func TestBreaker_Force_BypassesHalfOpenGate(t *testing.T) {
+	b := NewBreaker("force-gates",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithHalfOpenMaxConcurrent(1),
+	)
+	tripAndWaitHalfOpen(t, b)
+
+	release, err := b.admitHalfOpen()
+	require.NoError(t, err)
+	defer release()
+
+	b.ForceClose()
+	require.NoError(t, b.Do(context.Background(), func() error { return nil }))
+}
+
+func TestBreaker_Force_Metrics(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	b := NewBreaker("force-metrics", WithMeterProvider(provider))
+	ctx := context.Background()
+
+	b.ForceOpen()
+	assert.Equal(t, int64(StateOpen), stateGauge(t, reader, "force-metrics"))
+	_ = b.Do(ctx, func() error { return nil })
+
+	b.ForceClose()
+	assert.Equal(t, int64(StateClosed), stateGauge(t, reader, "force-metrics"))
+	_ = b.Do(ctx, func() error { return errTest })
+
+	assert.Equal(t, map[string]int64{
+		resultRejected: 1,
+		resultFailure:  1,
+	}, requestCounts(t, reader))
+}
+
+func TestBreaker_Force_ReentrantNotify(t *testing.T) {
+	b := NewBreaker("force-reentrant")
+	// 通知链同步执行：在通知中再次切换强制状态不得死锁
+	var transitions [][2]State
+	b.notifyStateChange = func(_ string, from, to State) {
+		transitions = append(transitions, [2]State{from, to})
+		if to == StateOpen {
+			b.ClearForce()
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.ForceOpen()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ForceOpen deadlocked on reentrant notify")
+	}
+
+	assert.False(t, b.Forced())
+	assert.Equal(t, [][2]State{{StateClosed, StateOpen}, {StateOpen, StateClosed}}, transitions)
+}
+
+func TestBreaker_Force_NilReceiver(t *testing.T) {
+	var b *Breaker
+	assert.NotPanics(t, func() {
+		b.ForceOpen()
+		b.ForceClose()
+		b.ClearForce()
+	})
+	assert.False(t, b.Forced())
+}
@@ -0,0 +1,103 @@
+package xbreaker
+
+import (
+	"sync/atomic"
+
+	"github.com/sony/gobreaker/v2"
+)
+
+// halfOpenGate 半开状态的并发探测限制
+//
+// gobreaker 的 MaxRequests 只限制半开期间放行的请求总数，这些请求可能同时到达下游；
+// halfOpenGate 额外限制同时在途的探测数，让探测逐个（或小批）发出。
+type halfOpenGate struct {
+	limit    int32
+	inFlight atomic.Int32
+}
+
+// newHalfOpenGate 创建半开并发限制，limit 为 0 时返回 nil（不启用）
+func newHalfOpenGate(limit uint32) *halfOpenGate {
+	if limit == 0 {
+		return nil
+	}
+	return &halfOpenGate{limit: int32(min(limit, uint32(1<<31-1)))}
+}
+
+// acquire 占用一个探测名额，名额已满时返回 false
+func (g *halfOpenGate) acquire() bool {
+	for {
+		cur := g.inFlight.Load()
+		if cur >= g.limit {
+			return false
+		}
+		if g.inFlight.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+	}
+}
+
+// release 归还探测名额
+func (g *halfOpenGate) release() {
+	g.inFlight.Add(-1)
+}
+
+// WithHalfOpenMaxConcurrent 设置 HalfOpen 状态下同时在途的探测请求上限
+//
+// gobreaker 的 MaxRequests 允许半开期间放行的请求同时到达下游，
+// 刚恢复的下游可能被一波并发探测再次打垮。设置 n 后，半开期间最多 n 个探测同时执行，
+// 其余请求不执行操作，返回包装了 ErrTooManyRequests 的 BreakerError（State 为 StateHalfOpen），
+// 不计入熔断统计。
+//
+// 默认值：0（不限制，仅由 MaxRequests 控制）
+//
+// 注意：并发限制仅作用于 [Breaker.Do] 和 [Execute]（含基于它们的 BreakerRetryer）；
+// ManagedBreaker、RetryThenBreak 维护独立状态，不受此选项影响。
+func WithHalfOpenMaxConcurrent(n uint32) BreakerOption {
+	return func(b *Breaker) {
+		b.halfOpenMaxConcurrent = n
+	}
+}
+
+// WithHalfOpenRequiredSuccesses 设置 HalfOpen 恢复到 Closed 所需的连续成功次数
+//
+// 设计决策: gobreaker 在半开期间连续成功达到 MaxRequests 时关闭熔断器，任一失败立即重新熔断，
+// 因此"所需连续成功次数"与"半开放行总数"在 gobreaker 中是同一个值。
+// 设置 n 后以 n 作为 MaxRequests，覆盖 WithMaxRequests；配合 WithHalfOpenMaxConcurrent
+// 可实现"逐个探测、连续 n 次成功才恢复"。
+//
+// 默认值：0（使用 WithMaxRequests，默认 1）
+//
+// 示例：
+//
+//	breaker := xbreaker.NewBreaker("my-service",
+//	    xbreaker.WithHalfOpenMaxConcurrent(1),
+//	    xbreaker.WithHalfOpenRequiredSuccesses(5),
+//	)
+func WithHalfOpenRequiredSuccesses(n uint32) BreakerOption {
+	return func(b *Breaker) {
+		b.halfOpenRequiredSuccesses = n
+	}
+}
+
+// halfOpenMaxRequests 返回传给 gobreaker 的 MaxRequests
+func (b *Breaker) halfOpenMaxRequests() uint32 {
+	if b.halfOpenRequiredSuccesses > 0 {
+		return b.halfOpenRequiredSuccesses
+	}
+	return b.maxRequests
+}
+
+// admitHalfOpen 半开期间限制并发探测数，返回的 release 在操作结束后调用
+//
+// 设计决策: 以入口时的状态决定是否占用名额。状态在入口检查与执行之间可能变化：
+// 刚转为 Closed 的请求多占用一次名额不影响正确性；刚转为 HalfOpen 的请求未占用名额，
+// 最多让并发探测短暂超出上限，仍受 gobreaker MaxRequests 约束。
+func (b *Breaker) admitHalfOpen() (release func(), err error) {
+	if b.halfOpen == nil || b.cb.State() != StateHalfOpen {
+		return func() {}, nil
+	}
+	if !b.halfOpen.acquire() {
+		return nil, newBreakerError(gobreaker.ErrTooManyRequests, b.name, StateHalfOpen)
+	}
+	return b.halfOpen.release, nil
+}
@@ -0,0 +1,107 @@
+package xbreaker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHalfOpenGate(t *testing.T) {
+	assert.Nil(t, newHalfOpenGate(0))
+
+	g := newHalfOpenGate(2)
+	require.True(t, g.acquire())
+	require.True(t, g.acquire())
+	assert.False(t, g.acquire(), "limit reached")
+
+	g.release()
+	assert.True(t, g.acquire())
+}
+
+func TestWithHalfOpenRequiredSuccesses_MaxRequests(t *testing.T) {
+	assert.Equal(t, DefaultMaxRequests, NewBreaker("default").halfOpenMaxRequests())
+	assert.Equal(t, uint32(3), NewBreaker("max", WithMaxRequests(3)).halfOpenMaxRequests())
+	assert.Equal(t, uint32(5), NewBreaker("required",
+		WithMaxRequests(3), WithHalfOpenRequiredSuccesses(5)).halfOpenMaxRequests(),
+		"required successes override MaxRequests")
+}
+
+// tripAndWaitHalfOpen 触发熔断并等待进入 HalfOpen
+func tripAndWaitHalfOpen(t *testing.T, b *Breaker) {
+	t.Helper()
+	require.ErrorIs(t, b.Do(context.Background(), func() error { return errTest }), errTest)
+	require.Equal(t, StateOpen, b.State())
+	require.Eventually(t, func() bool { return b.State() == StateHalfOpen }, time.Second, time.Millisecond)
+}
+
+func TestBreaker_HalfOpenMaxConcurrent(t *testing.T) {
+	ctx := context.Background()
+	b := NewBreaker("halfopen",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithHalfOpenMaxConcurrent(1),
+		WithHalfOpenRequiredSuccesses(3),
+	)
+	tripAndWaitHalfOpen(t, b)
+
+	// 一个探测在途时，其余请求被拒绝且不执行
+	started := make(chan struct{})
+	finish := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		assert.NoError(t, b.Do(ctx, func() error {
+			close(started)
+			<-finish
+			return nil
+		}))
+	})
+	<-started
+
+	called := false
+	err := b.Do(ctx, func() error {
+		called = true
+		return nil
+	})
+	assert.True(t, IsTooManyRequests(err))
+	assert.False(t, called)
+	var be *BreakerError
+	require.ErrorAs(t, err, &be)
+	assert.Equal(t, StateHalfOpen, be.State)
+
+	close(finish)
+	wg.Wait()
+
+	// 逐个探测，连续 3 次成功后才恢复
+	require.Equal(t, StateHalfOpen, b.State())
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	require.Equal(t, StateHalfOpen, b.State())
+	v, err := Execute(ctx, b, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, StateClosed, b.State())
+
+	// Closed 状态下不限制并发
+	release, err := b.admitHalfOpen()
+	require.NoError(t, err)
+	release()
+}
+
+func TestBreaker_HalfOpenProbeFailureReopens(t *testing.T) {
+	ctx := context.Background()
+	b := NewBreaker("halfopen-fail",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithHalfOpenMaxConcurrent(1),
+		WithHalfOpenRequiredSuccesses(3),
+	)
+	tripAndWaitHalfOpen(t, b)
+
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	require.ErrorIs(t, b.Do(ctx, func() error { return errTest }), errTest)
+	assert.Equal(t, StateOpen, b.State(), "any probe failure reopens the breaker")
+	assert.Zero(t, b.halfOpen.inFlight.Load(), "probe slots are released")
+}
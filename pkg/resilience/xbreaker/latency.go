@@ -0,0 +1,235 @@
+package xbreaker
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 延迟策略默认值
+const (
+	// DefaultLatencyPercentile 默认延迟分位数（P99）
+	DefaultLatencyPercentile = 0.99
+
+	// DefaultLatencyWindow 默认延迟滑动窗口大小（最近的调用次数）
+	DefaultLatencyWindow uint32 = 100
+)
+
+// errLatencyExceeded 调用成功但延迟分位数超过阈值时上报给 gobreaker 的内部标记错误。
+// gobreaker 仅在失败时调用 ReadyToTrip，借此让"慢但成功"的调用也能触发熔断判定；
+// Do/Execute 会还原 fn 的原始结果，调用方不会看到此错误，但它会计入 gobreaker 的 Counts
+// （TotalFailures、ConsecutiveFailures），对 CompositePolicy 中基于 Counts 的兄弟策略可见。
+var errLatencyExceeded = errors.New("xbreaker: latency percentile exceeded threshold")
+
+// latencyTracker 由需要调用耗时的策略实现
+//
+// Breaker 在 NewBreaker 时从 TripPolicy（含 CompositePolicy 子策略）中解析，
+// 未配置时 Do/Execute 不测量耗时，无额外开销。
+type latencyTracker interface {
+	// observeLatency 记录一次调用耗时，返回记录后延迟是否超过阈值
+	observeLatency(d time.Duration) bool
+	// resetLatency 清空窗口（熔断器状态变化时调用）
+	resetLatency()
+}
+
+// LatencyPolicy 响应时间熔断策略
+//
+// 维护最近 windowSize 次调用耗时的滑动窗口，当窗口已满且指定分位数（默认 P99）
+// 超过阈值时触发熔断——即使这些调用都没有返回错误。
+// 适用于"下游变慢但未报错"的场景，避免慢调用占满上游资源。
+//
+// 与 SlowCallRatioPolicy 不同，LatencyPolicy 由 Breaker 自动测量耗时，
+// 无需配合 SuccessPolicy 手动标记慢调用。
+//
+// 注意：
+//   - 耗时仅在 [Breaker.Do] 和 [Execute] 中测量；ManagedBreaker、RetryThenBreak 不上报耗时
+//   - 被 ExcludePolicy 排除的调用不计入窗口
+//   - 熔断器状态变化时窗口被清空，HalfOpen 探测基于新的样本判定
+//   - LatencyPolicy 持有窗口状态，不应在多个 Breaker 间共享
+//   - 窗口按调用次数滑动，低流量服务需要更长时间才能积累满窗口
+//   - 窗口超阈值时，本次成功调用在 gobreaker Counts 中计为失败，
+//     组合在 CompositePolicy 中的基于 Counts 的策略可能看到这次失败（见 CompositePolicy）
+type LatencyPolicy struct {
+	threshold  time.Duration
+	percentile float64
+	allowSlow  int // 满窗口时允许超阈值的最大样本数，超过即分位数超阈值
+
+	mu      sync.Mutex
+	samples []time.Duration // 环形缓冲区
+	next    int
+	filled  bool
+	slow    int // 窗口内超过阈值的样本数
+}
+
+// NewLatencyPolicy 创建响应时间熔断策略
+//
+// threshold: 延迟阈值，分位数超过此值时触发熔断
+// percentile: 分位数 (0.0 - 1.0]，例如 0.99 表示 P99；超出范围时使用 DefaultLatencyPercentile
+// windowSize: 滑动窗口大小（最近的调用次数），为 0 时使用 DefaultLatencyWindow
+//
+// 示例:
+//
+//	breaker := xbreaker.NewBreaker("slow-api",
+//	    xbreaker.WithTripPolicy(xbreaker.NewCompositePolicy(
+//	        xbreaker.NewConsecutiveFailures(5),
+//	        xbreaker.NewLatencyPolicy(500*time.Millisecond, 0.99, 100),
+//	    )),
+//	)
+//	// 连续失败 5 次 OR 最近 100 次调用的 P99 超过 500ms 时触发熔断
+func NewLatencyPolicy(threshold time.Duration, percentile float64, windowSize uint32) *LatencyPolicy {
+	if percentile <= 0 || percentile > 1 {
+		percentile = DefaultLatencyPercentile
+	}
+	if windowSize == 0 {
+		windowSize = DefaultLatencyWindow
+	}
+	n := int(windowSize)
+	return &LatencyPolicy{
+		threshold:  threshold,
+		percentile: percentile,
+		allowSlow:  n - percentileRank(percentile, n),
+		samples:    make([]time.Duration, n),
+	}
+}
+
+// percentileRank 返回最近秩法（nearest-rank）下分位数在 n 个有序样本中的秩（1-based）
+func percentileRank(p float64, n int) int {
+	// 减去极小量，避免 0.99*100 之类的浮点误差导致秩多进一位
+	rank := int(math.Ceil(p*float64(n) - 1e-9))
+	return max(rank, 1)
+}
+
+// ReadyToTrip 判断是否应该触发熔断
+//
+// 忽略 counts，仅依据延迟窗口判定。
+func (p *LatencyPolicy) ReadyToTrip(_ Counts) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.exceededLocked()
+}
+
+// Threshold 返回延迟阈值
+func (p *LatencyPolicy) Threshold() time.Duration {
+	return p.threshold
+}
+
+// Percentile 返回分位数
+func (p *LatencyPolicy) Percentile() float64 {
+	return p.percentile
+}
+
+// WindowSize 返回滑动窗口大小
+func (p *LatencyPolicy) WindowSize() uint32 {
+	return uint32(len(p.samples)) //nolint:gosec // 由 uint32 windowSize 构造，不会溢出
+}
+
+// Current 返回当前窗口内样本的分位数延迟，窗口为空时返回 0
+//
+// 窗口未满时基于已有样本计算，可用于监控上报。
+func (p *LatencyPolicy) Current() time.Duration {
+	p.mu.Lock()
+	n := p.next
+	if p.filled {
+		n = len(p.samples)
+	}
+	sorted := slices.Clone(p.samples[:n])
+	p.mu.Unlock()
+
+	if n == 0 {
+		return 0
+	}
+	slices.Sort(sorted)
+	return sorted[percentileRank(p.percentile, n)-1]
+}
+
+// observeLatency 实现 latencyTracker
+func (p *LatencyPolicy) observeLatency(d time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.filled && p.samples[p.next] > p.threshold {
+		p.slow--
+	}
+	p.samples[p.next] = d
+	if d > p.threshold {
+		p.slow++
+	}
+	p.next++
+	if p.next == len(p.samples) {
+		p.next = 0
+		p.filled = true
+	}
+	return p.exceededLocked()
+}
+
+// resetLatency 实现 latencyTracker
+func (p *LatencyPolicy) resetLatency() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.next = 0
+	p.filled = false
+	p.slow = 0
+}
+
+// exceededLocked 窗口已满且超阈值样本数超过允许值时返回 true，调用方需持有 mu
+func (p *LatencyPolicy) exceededLocked() bool {
+	return p.filled && p.slow > p.allowSlow
+}
+
+// multiLatencyTracker 聚合 CompositePolicy 中的多个延迟策略
+type multiLatencyTracker []latencyTracker
+
+func (m multiLatencyTracker) observeLatency(d time.Duration) bool {
+	exceeded := false
+	for _, t := range m {
+		// 不短路：每个策略都需要记录样本
+		if t.observeLatency(d) {
+			exceeded = true
+		}
+	}
+	return exceeded
+}
+
+func (m multiLatencyTracker) resetLatency() {
+	for _, t := range m {
+		t.resetLatency()
+	}
+}
+
+// latencyTrackerOf 从策略中解析延迟追踪器，递归展开 CompositePolicy；无则返回 nil
+func latencyTrackerOf(p TripPolicy) latencyTracker {
+	switch v := p.(type) {
+	case latencyTracker:
+		return v
+	case *CompositePolicy:
+		var trackers multiLatencyTracker
+		for _, child := range v.policies {
+			if t := latencyTrackerOf(child); t != nil {
+				trackers = append(trackers, t)
+			}
+		}
+		switch len(trackers) {
+		case 0:
+			return nil
+		case 1:
+			return trackers[0]
+		}
+		return trackers
+	}
+	return nil
+}
+
+// trackLatency 上报本次调用耗时，返回交给 gobreaker 判定的错误
+//
+// 调用被判定为成功但延迟已超阈值时返回 errLatencyExceeded，使 gobreaker 计为失败并调用 ReadyToTrip。
+func (b *Breaker) trackLatency(start time.Time, err error) error {
+	if b.IsExcluded(err) {
+		return err
+	}
+	if b.latency.observeLatency(time.Since(start)) && b.IsSuccessful(err) {
+		return errLatencyExceeded
+	}
+	return err
+}
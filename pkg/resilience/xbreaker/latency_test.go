@@ -0,0 +1,224 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLatencyPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := NewLatencyPolicy(time.Second, 0, 0)
+		assert.Equal(t, time.Second, p.Threshold())
+		assert.Equal(t, DefaultLatencyPercentile, p.Percentile())
+		assert.Equal(t, DefaultLatencyWindow, p.WindowSize())
+	})
+
+	t.Run("percentile out of range", func(t *testing.T) {
+		assert.Equal(t, DefaultLatencyPercentile, NewLatencyPolicy(time.Second, 1.5, 10).Percentile())
+		assert.Equal(t, DefaultLatencyPercentile, NewLatencyPolicy(time.Second, -0.1, 10).Percentile())
+		assert.Equal(t, 1.0, NewLatencyPolicy(time.Second, 1, 10).Percentile())
+	})
+}
+
+func TestLatencyPolicy_Window(t *testing.T) {
+	const threshold = 100 * time.Millisecond
+	fast, slow := 10*time.Millisecond, 200*time.Millisecond
+
+	t.Run("window not full", func(t *testing.T) {
+		p := NewLatencyPolicy(threshold, 0.99, 10)
+		for range 9 {
+			assert.False(t, p.observeLatency(slow))
+		}
+		assert.False(t, p.ReadyToTrip(Counts{}))
+	})
+
+	t.Run("p99 of 100 allows one slow sample", func(t *testing.T) {
+		p := NewLatencyPolicy(threshold, 0.99, 100)
+		for range 99 {
+			p.observeLatency(fast)
+		}
+		assert.False(t, p.observeLatency(slow), "P99 is the 99th sample, still fast")
+
+		// 第 101 个样本覆盖最早的快样本，窗口内 2 个慢样本
+		assert.True(t, p.observeLatency(slow), "two slow samples push P99 over threshold")
+		assert.True(t, p.ReadyToTrip(Counts{}))
+		assert.Equal(t, slow, p.Current())
+	})
+
+	t.Run("slow samples slide out", func(t *testing.T) {
+		p := NewLatencyPolicy(threshold, 0.5, 4)
+		for range 4 {
+			p.observeLatency(slow)
+		}
+		require.True(t, p.ReadyToTrip(Counts{}))
+		p.observeLatency(fast)
+		p.observeLatency(fast)
+		assert.False(t, p.ReadyToTrip(Counts{}), "P50 of [fast fast slow slow] is fast")
+	})
+
+	t.Run("reset clears window", func(t *testing.T) {
+		p := NewLatencyPolicy(threshold, 0.5, 2)
+		p.observeLatency(slow)
+		p.observeLatency(slow)
+		require.True(t, p.ReadyToTrip(Counts{}))
+		p.resetLatency()
+		assert.False(t, p.ReadyToTrip(Counts{}))
+		assert.Zero(t, p.Current())
+	})
+}
+
+func TestLatencyTrackerOf(t *testing.T) {
+	l1 := NewLatencyPolicy(time.Second, 0.99, 10)
+	l2 := NewLatencyPolicy(time.Second, 0.5, 10)
+
+	assert.Nil(t, latencyTrackerOf(NewConsecutiveFailures(3)))
+	assert.Nil(t, latencyTrackerOf(NewCompositePolicy(NewConsecutiveFailures(3))))
+	assert.Same(t, l1, latencyTrackerOf(l1))
+	assert.Same(t, l1, latencyTrackerOf(NewCompositePolicy(NewConsecutiveFailures(3), l1)))
+
+	nested := latencyTrackerOf(NewCompositePolicy(l1, NewCompositePolicy(l2)))
+	require.IsType(t, multiLatencyTracker{}, nested)
+	assert.Len(t, nested, 2)
+}
+
+// fakeSlow 让调用耗时超过阈值
+func fakeSlow(d time.Duration) func() error {
+	return func() error {
+		time.Sleep(d)
+		return nil
+	}
+}
+
+func TestBreaker_LatencyTrip(t *testing.T) {
+	policy := NewLatencyPolicy(5*time.Millisecond, 0.5, 4)
+	b := NewBreaker("latency", WithTripPolicy(policy), WithTimeout(time.Hour))
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, b.Do(ctx, fakeSlow(10*time.Millisecond)))
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	// 第 4 次调用填满窗口，P50 超阈值：调用本身成功，但熔断器打开
+	require.NoError(t, b.Do(ctx, fakeSlow(10*time.Millisecond)))
+	assert.Equal(t, StateOpen, b.State())
+
+	err := b.Do(ctx, func() error { return nil })
+	assert.True(t, IsOpen(err))
+}
+
+func TestExecute_LatencyTripKeepsResult(t *testing.T) {
+	b := NewBreaker("latency", WithTripPolicy(NewLatencyPolicy(time.Millisecond, 1, 1)), WithTimeout(time.Hour))
+
+	v, err := Execute(context.Background(), b, func() (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "ok", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ok", v)
+	assert.Equal(t, StateOpen, b.State())
+}
+
+func TestBreaker_LatencyFastCallsStayClosed(t *testing.T) {
+	b := NewBreaker("latency", WithTripPolicy(NewLatencyPolicy(time.Second, 0.99, 10)))
+	for range 50 {
+		require.NoError(t, b.Do(context.Background(), func() error { return nil }))
+	}
+	assert.Equal(t, StateClosed, b.State())
+	assert.Equal(t, uint32(50), b.Counts().TotalSuccesses)
+}
+
+func TestBreaker_LatencyPreservesFnError(t *testing.T) {
+	bizErr := errors.New("biz")
+	b := NewBreaker("latency",
+		WithTripPolicy(NewLatencyPolicy(time.Millisecond, 1, 1)),
+		WithSuccessPolicy(successFunc(func(err error) bool { return err == nil || errors.Is(err, bizErr) })),
+		WithTimeout(time.Hour),
+	)
+
+	err := b.Do(context.Background(), func() error {
+		time.Sleep(5 * time.Millisecond)
+		return bizErr
+	})
+	assert.ErrorIs(t, err, bizErr)
+	assert.Equal(t, StateOpen, b.State(), "slow call judged successful by SuccessPolicy still trips")
+}
+
+func TestBreaker_LatencyMarkerVisibleToSiblingPolicies(t *testing.T) {
+	latency := NewLatencyPolicy(time.Millisecond, 1, 4)
+	b := NewBreaker("latency",
+		WithTripPolicy(NewCompositePolicy(latency, NewConsecutiveFailures(100))),
+		WithTimeout(time.Hour),
+	)
+	// 窗口未满：只记录样本，不产生失败
+	for range 3 {
+		require.NoError(t, b.Do(context.Background(), fakeSlow(5*time.Millisecond)))
+	}
+	assert.Zero(t, b.Counts().TotalFailures)
+
+	// 填满窗口：本次成功调用以失败计入 Counts 并由 LatencyPolicy 熔断，状态变化清空 Counts
+	require.NoError(t, b.Do(context.Background(), fakeSlow(5*time.Millisecond)))
+	assert.Equal(t, StateOpen, b.State())
+	assert.Zero(t, b.Counts().TotalFailures, "marker does not linger after the trip")
+}
+
+func TestBreaker_LatencyExcludedNotObserved(t *testing.T) {
+	policy := NewLatencyPolicy(time.Millisecond, 1, 1)
+	b := NewBreaker("latency",
+		WithTripPolicy(policy),
+		WithExcludePolicy(excludeFunc(func(err error) bool { return errors.Is(err, context.Canceled) })),
+	)
+
+	err := b.Do(context.Background(), func() error {
+		time.Sleep(5 * time.Millisecond)
+		return context.Canceled
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, policy.Current())
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_LatencyResetOnHalfOpen(t *testing.T) {
+	var mu sync.Mutex
+	var transitions []State
+	b := NewBreaker("latency",
+		WithTripPolicy(NewLatencyPolicy(5*time.Millisecond, 1, 2)),
+		WithTimeout(20*time.Millisecond),
+		WithOnStateChange(func(_ string, _, to State) {
+			mu.Lock()
+			transitions = append(transitions, to)
+			mu.Unlock()
+		}),
+	)
+	ctx := context.Background()
+
+	require.NoError(t, b.Do(ctx, fakeSlow(10*time.Millisecond)))
+	require.NoError(t, b.Do(ctx, fakeSlow(10*time.Millisecond)))
+	require.Equal(t, StateOpen, b.State())
+
+	// 熔断前的慢样本已被清空，HalfOpen 探测的快速调用可以恢复熔断器
+	time.Sleep(30 * time.Millisecond)
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	assert.Equal(t, StateClosed, b.State())
+
+	// 用户回调仍然生效
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(transitions) == 3
+	}, time.Second, 5*time.Millisecond)
+}
+
+type successFunc func(error) bool
+
+func (f successFunc) IsSuccessful(err error) bool { return f(err) }
+
+type excludeFunc func(error) bool
+
+func (f excludeFunc) IsExcluded(err error) bool { return f(err) }
@@ -0,0 +1,154 @@
+package xbreaker
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// 设计决策: 指标前缀使用 "xbreaker.*"，与 OTel Meter scope name 保持一致（Meter("xbreaker")），
+// 与 xlimit、xsemaphore 的命名方式相同。
+const (
+	// metricNameState 熔断器当前状态仪表（0=closed, 1=half_open, 2=open）
+	metricNameState = "xbreaker.state"
+	// metricNameStateChangesTotal 状态转换次数计数器
+	metricNameStateChangesTotal = "xbreaker.state_changes.total"
+	// metricNameRequestsTotal 请求次数计数器（按 result 区分）
+	metricNameRequestsTotal = "xbreaker.requests.total"
+)
+
+// 指标属性
+const (
+	attrBreaker = "breaker"
+	attrFrom    = "from"
+	attrTo      = "to"
+	attrResult  = "result"
+)
+
+// 请求结果（xbreaker.requests.total 的 result 属性）
+const (
+	resultSuccess  = "success"  // 计入成功
+	resultFailure  = "failure"  // 计入失败（含 LatencyPolicy 判定的慢调用）
+	resultExcluded = "excluded" // 被 ExcludePolicy 或 Classifier 排除在统计之外
+	resultRejected = "rejected" // 被熔断器拒绝，操作未执行
+)
+
+// breakerMetrics 熔断器指标收集器
+type breakerMetrics struct {
+	state         metric.Int64Gauge
+	stateChanges  metric.Int64Counter
+	requestsTotal metric.Int64Counter
+}
+
+// newBreakerMetrics 创建指标收集器，meterProvider 为 nil 时返回 nil（不收集指标）
+func newBreakerMetrics(meterProvider metric.MeterProvider) (*breakerMetrics, error) {
+	if meterProvider == nil {
+		return nil, nil
+	}
+	meter := meterProvider.Meter("xbreaker")
+
+	m := &breakerMetrics{}
+	var err error
+	if m.state, err = meter.Int64Gauge(metricNameState,
+		metric.WithDescription("熔断器当前状态（0=closed, 1=half_open, 2=open）")); err != nil {
+		return nil, err
+	}
+	if m.stateChanges, err = meter.Int64Counter(metricNameStateChangesTotal,
+		metric.WithDescription("熔断器状态转换次数"), metric.WithUnit("{transition}")); err != nil {
+		return nil, err
+	}
+	if m.requestsTotal, err = meter.Int64Counter(metricNameRequestsTotal,
+		metric.WithDescription("经过熔断器的请求次数"), metric.WithUnit("{request}")); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// recordState 记录当前状态
+func (m *breakerMetrics) recordState(name string, state State) {
+	m.state.Record(context.Background(), int64(state),
+		metric.WithAttributes(attribute.String(attrBreaker, name)))
+}
+
+// recordStateChange 记录状态转换并更新状态仪表
+//
+// 在 gobreaker 持有内部锁期间同步调用：OTel instrument 只做内存聚合、不会回调 Breaker，
+// 因此不存在 WithOnStateChange 文档所述的死锁风险，同步记录保证仪表值与转换顺序一致。
+func (m *breakerMetrics) recordStateChange(name string, from, to State) {
+	m.stateChanges.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String(attrBreaker, name),
+		attribute.String(attrFrom, from.String()),
+		attribute.String(attrTo, to.String()),
+	))
+	m.recordState(name, to)
+}
+
+// recordRequest 记录一次请求结果
+func (m *breakerMetrics) recordRequest(name, result string) {
+	m.requestsTotal.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String(attrBreaker, name),
+		attribute.String(attrResult, result),
+	))
+}
+
+// WithMeterProvider 设置 OpenTelemetry MeterProvider，启用熔断器指标
+//
+// 启用后记录以下指标（均带 breaker=<name> 属性）：
+//   - xbreaker.state：当前状态仪表，0=closed、1=half_open、2=open，状态转换时更新
+//   - xbreaker.state_changes.total：状态转换次数，带 from/to 属性
+//   - xbreaker.requests.total：经过 Do/Execute 的请求次数，result 属性为
+//     success、failure、excluded（被排除在统计之外）或 rejected（被熔断器拒绝）
+//
+// 设计决策: 使用 MeterProvider 而非 xmetrics.Observer。Observer 面向单次操作的 span，
+// 无法表达"当前状态"这类仪表值；与 xlimit、xsemaphore 一样直接使用 OTel Meter。
+//
+// 注意：gobreaker 的 Open→HalfOpen 转换是惰性的，在 Timeout 到期后的下一次请求或
+// State()/Counts() 调用时才发生，无流量期间 xbreaker.state 会保持为 2。
+// 告警"熔断持续超过 N 秒"时可直接对 xbreaker.state == 2 设置持续时间条件。
+// 指标仅覆盖 Breaker 自身，ManagedBreaker、RetryThenBreak 维护独立状态，不记录指标。
+func WithMeterProvider(mp metric.MeterProvider) BreakerOption {
+	return func(b *Breaker) {
+		b.meterProvider = mp
+	}
+}
+
+// initMetrics 创建指标收集器并记录初始状态
+//
+// 设计决策: NewBreaker 不返回错误，指标初始化失败时记录告警并禁用指标，不影响熔断功能。
+func (b *Breaker) initMetrics() {
+	m, err := newBreakerMetrics(b.meterProvider)
+	if err != nil {
+		slog.Warn("xbreaker: failed to create metrics, metrics disabled", "name", b.name, "error", err)
+		return
+	}
+	b.metrics = m
+	if m != nil {
+		m.recordState(b.name, StateClosed)
+	}
+}
+
+// recordRejected 记录被熔断器拒绝的请求
+func (b *Breaker) recordRejected() {
+	if b.metrics != nil {
+		b.metrics.recordRequest(b.name, resultRejected)
+	}
+}
+
+// recordResult 按上报给 gobreaker 的错误记录请求结果，判定顺序与 gobreaker 一致
+func (b *Breaker) recordResult(reported error) {
+	if b.metrics == nil {
+		return
+	}
+	result := resultFailure
+	if reported != errLatencyExceeded {
+		switch {
+		case b.IsExcluded(reported):
+			result = resultExcluded
+		case b.IsSuccessful(reported):
+			result = resultSuccess
+		}
+	}
+	b.metrics.recordRequest(b.name, result)
+}
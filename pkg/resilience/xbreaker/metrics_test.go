@@ -0,0 +1,169 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newTestMeterProvider 创建使用 ManualReader 的 MeterProvider
+func newTestMeterProvider(t *testing.T) (*sdkmetric.MeterProvider, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, reader
+}
+
+// collectMetric 采集指定名称的指标数据
+func collectMetric(t *testing.T, reader *sdkmetric.ManualReader, name string) metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("metric %q not recorded", name)
+	return nil
+}
+
+// stateGauge 返回 breaker 的 xbreaker.state 当前值
+func stateGauge(t *testing.T, reader *sdkmetric.ManualReader, breaker string) int64 {
+	t.Helper()
+	gauge, ok := collectMetric(t, reader, metricNameState).(metricdata.Gauge[int64])
+	require.True(t, ok)
+	for _, dp := range gauge.DataPoints {
+		if v, _ := dp.Attributes.Value(attrBreaker); v.AsString() == breaker {
+			return dp.Value
+		}
+	}
+	t.Fatalf("no state data point for breaker %q", breaker)
+	return 0
+}
+
+// requestCounts 返回 xbreaker.requests.total 按 result 的计数
+func requestCounts(t *testing.T, reader *sdkmetric.ManualReader) map[string]int64 {
+	t.Helper()
+	sum, ok := collectMetric(t, reader, metricNameRequestsTotal).(metricdata.Sum[int64])
+	require.True(t, ok)
+	counts := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		v, _ := dp.Attributes.Value(attrResult)
+		counts[v.AsString()] += dp.Value
+	}
+	return counts
+}
+
+func TestNewBreakerMetrics_NilProvider(t *testing.T) {
+	m, err := newBreakerMetrics(nil)
+	require.NoError(t, err)
+	assert.Nil(t, m)
+
+	b := NewBreaker("no-metrics")
+	assert.Nil(t, b.metrics)
+	require.NoError(t, b.Do(context.Background(), func() error { return nil }))
+}
+
+func TestBreaker_StateMetrics(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	ctx := context.Background()
+	b := NewBreaker("state",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithMeterProvider(provider),
+	)
+	assert.Equal(t, int64(StateClosed), stateGauge(t, reader, "state"), "initial state recorded")
+
+	_ = b.Do(ctx, func() error { return errTest })
+	assert.Equal(t, int64(StateOpen), stateGauge(t, reader, "state"))
+
+	require.Eventually(t, func() bool { return b.State() == StateHalfOpen }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(StateHalfOpen), stateGauge(t, reader, "state"))
+
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	assert.Equal(t, int64(StateClosed), stateGauge(t, reader, "state"))
+
+	sum, ok := collectMetric(t, reader, metricNameStateChangesTotal).(metricdata.Sum[int64])
+	require.True(t, ok)
+	assert.Len(t, sum.DataPoints, 3, "closed->open, open->half-open, half-open->closed")
+	for _, dp := range sum.DataPoints {
+		assert.Equal(t, int64(1), dp.Value)
+		assert.True(t, dp.Attributes.HasValue(attribute.Key(attrFrom)))
+		assert.True(t, dp.Attributes.HasValue(attribute.Key(attrTo)))
+	}
+}
+
+func TestBreaker_RequestMetrics(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	ctx := context.Background()
+	b := NewBreaker("requests",
+		WithTripPolicy(NewConsecutiveFailures(2)),
+		WithTimeout(time.Hour),
+		WithExcludePolicy(excludeFunc(func(err error) bool { return errors.Is(err, context.Canceled) })),
+		WithMeterProvider(provider),
+	)
+
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	_, err := Execute(ctx, b, func() (int, error) { return 1, nil })
+	require.NoError(t, err)
+	_ = b.Do(ctx, func() error { return context.Canceled })
+	_ = b.Do(ctx, func() error { return errTest })
+	_, _ = Execute(ctx, b, func() (int, error) { return 0, errTest })
+	require.Equal(t, StateOpen, b.State())
+
+	// Open 状态下被拒绝，操作不执行
+	assert.Error(t, b.Do(ctx, func() error { return nil }))
+	_, err = Execute(ctx, b, func() (int, error) { return 1, nil })
+	assert.Error(t, err)
+
+	assert.Equal(t, map[string]int64{
+		resultSuccess:  2,
+		resultExcluded: 1,
+		resultFailure:  2,
+		resultRejected: 2,
+	}, requestCounts(t, reader))
+}
+
+func TestBreaker_RequestMetrics_Latency(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	b := NewBreaker("latency",
+		WithTripPolicy(NewLatencyPolicy(time.Nanosecond, 0.5, 1)),
+		WithMeterProvider(provider),
+	)
+
+	// 调用本身成功，但延迟超过阈值，计为失败
+	require.NoError(t, b.Do(context.Background(), func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}))
+	assert.Equal(t, int64(1), requestCounts(t, reader)[resultFailure])
+}
+
+func TestBreaker_RequestMetrics_HalfOpenRejected(t *testing.T) {
+	provider, reader := newTestMeterProvider(t)
+	b := NewBreaker("halfopen-metrics",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithHalfOpenMaxConcurrent(1),
+		WithMeterProvider(provider),
+	)
+	tripAndWaitHalfOpen(t, b)
+
+	release, err := b.admitHalfOpen()
+	require.NoError(t, err)
+	defer release()
+	assert.True(t, IsTooManyRequests(b.Do(context.Background(), func() error { return nil })))
+
+	assert.Equal(t, int64(1), requestCounts(t, reader)[resultRejected])
+}
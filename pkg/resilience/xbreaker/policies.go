@@ -148,6 +148,13 @@ func (p *FailureCountPolicy) Threshold() uint32 {
 //
 // 组合多个策略，任一策略满足即触发熔断。
 // 适用于需要多重熔断条件的场景。
+//
+// 注意：所有子策略共享同一份 gobreaker Counts。与 LatencyPolicy 组合时，延迟窗口超阈值后的
+// 成功调用会以失败计入 Counts（这是让 gobreaker 调用 ReadyToTrip 的唯一途径）。
+// 通常该次判定即因 LatencyPolicy 熔断，状态变化清空 Counts；但并发调用可能在判定前
+// 把慢样本挤出延迟窗口，此时不熔断，多出的失败留在 Counts 中，
+// ConsecutiveFailuresPolicy、FailureRatioPolicy 等基于 Counts 的兄弟策略会看到它。
+// 需要失败统计完全不受延迟影响时，为延迟熔断单独创建 Breaker。
 type CompositePolicy struct {
 	policies []TripPolicy
 }
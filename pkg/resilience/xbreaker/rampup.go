@@ -0,0 +1,120 @@
+package xbreaker
+
+import (
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/sony/gobreaker/v2"
+)
+
+// minRampUpRatio 预热开始时的最低放行比例
+//
+// 设计决策: 不从 0 开始放行。HalfOpen 探测刚成功时下游已被证明可用，
+// 若起点为 0，恢复后的最初一段时间几乎拒绝全部请求，等同于延长了熔断时间；
+// 保留 10% 的起点让下游立即承接少量真实流量，再线性升至 100%。
+const minRampUpRatio = 0.1
+
+// rampUpGate 熔断器恢复后的渐进放行控制
+//
+// 仅在 HalfOpen→Closed 转换时开始预热，预热期内按已过时间线性提高放行比例，
+// 期满后恢复全量放行。预热期间再次熔断（Closed→Open）会立即结束本轮预热，
+// 下一次恢复时重新开始。
+type rampUpGate struct {
+	duration time.Duration
+	closedAt atomic.Int64 // 本轮预热开始时间（UnixNano），0 表示未处于预热期
+	now      func() time.Time
+}
+
+// newRampUpGate 创建预热控制，duration <= 0 时返回 nil（不启用）
+func newRampUpGate(duration time.Duration) *rampUpGate {
+	if duration <= 0 {
+		return nil
+	}
+	return &rampUpGate{duration: duration, now: time.Now}
+}
+
+// onStateChange 跟踪状态转换，决定预热的开始与结束
+//
+// 在 gobreaker mutex 内同步调用，只做原子写入，不会阻塞或回调熔断器。
+func (g *rampUpGate) onStateChange(from, to State) {
+	switch {
+	case from == StateHalfOpen && to == StateClosed:
+		g.closedAt.Store(g.now().UnixNano())
+	case to != StateClosed:
+		g.closedAt.Store(0)
+	}
+}
+
+// ratio 返回当前放行比例，未处于预热期时返回 1
+func (g *rampUpGate) ratio() float64 {
+	start := g.closedAt.Load()
+	if start == 0 {
+		return 1
+	}
+	elapsed := g.now().UnixNano() - start
+	if elapsed >= int64(g.duration) {
+		// 预热结束；CAS 避免覆盖并发开始的新一轮预热
+		g.closedAt.CompareAndSwap(start, 0)
+		return 1
+	}
+	return max(float64(elapsed)/float64(g.duration), minRampUpRatio)
+}
+
+// allow 判断本次请求是否放行
+func (g *rampUpGate) allow() bool {
+	r := g.ratio()
+	return r >= 1 || rand.Float64() < r
+}
+
+// WithRampUp 设置熔断器恢复后的预热时长
+//
+// 熔断器从 HalfOpen 恢复到 Closed 后，不立即全量放行，而是在 d 时间内
+// 将放行比例从 10% 线性提高到 100%，给刚恢复的下游留出扩容、预热缓存的时间，
+// 避免瞬时流量洪峰再次将其打垮。
+//
+// 与半开状态的协同：
+//   - HalfOpen 阶段仍由 WithMaxRequests 控制探测请求数，预热不参与
+//   - 只有 HalfOpen→Closed 才会开始预热；预热期间请求正常计入统计，
+//     失败达到 TripPolicy 条件时照常熔断，并结束本轮预热
+//
+// 预热期间未放行的请求不执行操作，返回包装了 ErrTooManyRequests 的 BreakerError
+// （State 为 StateClosed），IsTooManyRequests/IsBreakerError 均可识别，
+// 且不计入熔断统计。
+//
+// 默认值：0（不预热，恢复后立即全量放行）
+//
+// 注意：预热仅作用于 [Breaker.Do] 和 [Execute]（含基于它们的 BreakerRetryer）；
+// ManagedBreaker、RetryThenBreak 维护独立状态，不受此选项影响。
+//
+// 示例：
+//
+//	breaker := xbreaker.NewBreaker("my-service",
+//	    xbreaker.WithTimeout(30*time.Second),
+//	    xbreaker.WithRampUp(time.Minute),
+//	)
+func WithRampUp(d time.Duration) BreakerOption {
+	return func(b *Breaker) {
+		if d >= 0 {
+			b.rampUp = d
+		}
+	}
+}
+
+// RampUpRatio 返回当前放行比例，范围 (0, 1]
+//
+// 未配置 WithRampUp 或未处于预热期时返回 1，可用于监控恢复进度。
+func (b *Breaker) RampUpRatio() float64 {
+	if b == nil || b.ramp == nil {
+		return 1
+	}
+	return b.ramp.ratio()
+}
+
+// admitRampUp 预热期内按比例放行，未放行时返回熔断器错误
+func (b *Breaker) admitRampUp() error {
+	if b.ramp == nil || b.ramp.allow() {
+		return nil
+	}
+	return newBreakerError(gobreaker.ErrTooManyRequests, b.name, StateClosed)
+}
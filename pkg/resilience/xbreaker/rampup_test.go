@@ -0,0 +1,128 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRampUpGate_Ratio(t *testing.T) {
+	assert.Nil(t, newRampUpGate(0))
+
+	now := time.Unix(1000, 0)
+	g := newRampUpGate(10 * time.Second)
+	g.now = func() time.Time { return now }
+
+	assert.Equal(t, 1.0, g.ratio(), "not ramping before recovery")
+
+	g.onStateChange(StateClosed, StateOpen)
+	assert.Equal(t, 1.0, g.ratio(), "only HalfOpen->Closed starts ramp-up")
+
+	g.onStateChange(StateHalfOpen, StateClosed)
+	assert.Equal(t, minRampUpRatio, g.ratio(), "ramp-up starts from the minimum ratio")
+
+	now = now.Add(5 * time.Second)
+	assert.InDelta(t, 0.5, g.ratio(), 1e-9)
+
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, 1.0, g.ratio())
+	assert.Zero(t, g.closedAt.Load(), "ramp-up ends after duration")
+}
+
+func TestRampUpGate_TripDuringRampUp(t *testing.T) {
+	now := time.Unix(1000, 0)
+	g := newRampUpGate(10 * time.Second)
+	g.now = func() time.Time { return now }
+
+	g.onStateChange(StateHalfOpen, StateClosed)
+	now = now.Add(time.Second)
+	require.Less(t, g.ratio(), 1.0)
+
+	g.onStateChange(StateClosed, StateOpen)
+	assert.Equal(t, 1.0, g.ratio(), "tripping aborts the current ramp-up")
+}
+
+func TestWithRampUp_NegativeIgnored(t *testing.T) {
+	b := NewBreaker("test", WithRampUp(-time.Second))
+	assert.Nil(t, b.ramp)
+	assert.Equal(t, 1.0, b.RampUpRatio())
+
+	var nilBreaker *Breaker
+	assert.Equal(t, 1.0, nilBreaker.RampUpRatio())
+}
+
+func TestBreaker_RampUpAfterRecovery(t *testing.T) {
+	ctx := context.Background()
+	b := NewBreaker("rampup",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithTimeout(20*time.Millisecond),
+		WithRampUp(time.Hour),
+	)
+
+	// Closed 状态下未经历恢复，全量放行
+	assert.Equal(t, 1.0, b.RampUpRatio())
+	require.ErrorIs(t, b.Do(ctx, func() error { return errTest }), errTest)
+	require.Equal(t, StateOpen, b.State())
+
+	// HalfOpen 探测成功 → Closed，开始预热
+	require.Eventually(t, func() bool { return b.State() == StateHalfOpen }, time.Second, time.Millisecond)
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	require.Equal(t, StateClosed, b.State())
+	assert.Less(t, b.RampUpRatio(), 1.0)
+
+	var admitted, rejected int
+	for range 200 {
+		err := b.Do(ctx, func() error { return nil })
+		if err == nil {
+			admitted++
+			continue
+		}
+		rejected++
+		assert.True(t, IsTooManyRequests(err))
+		var be *BreakerError
+		require.True(t, errors.As(err, &be))
+		assert.Equal(t, StateClosed, be.State)
+		assert.Equal(t, "rampup", be.Name)
+	}
+	assert.Positive(t, admitted, "ramp-up must admit some requests")
+	assert.Positive(t, rejected, "ramp-up must reject some requests")
+	assert.Equal(t, uint32(admitted), b.Counts().Requests, "rejected requests are not counted")
+
+	// Execute 同样受预热控制
+	var execRejected bool
+	for range 200 {
+		if _, err := Execute(ctx, b, func() (int, error) { return 1, nil }); IsTooManyRequests(err) {
+			execRejected = true
+			break
+		}
+	}
+	assert.True(t, execRejected)
+
+	// 预热期满后全量放行
+	b.ramp.now = func() time.Time { return time.Now().Add(time.Hour) }
+	for range 50 {
+		require.NoError(t, b.Do(ctx, func() error { return nil }))
+	}
+	assert.Equal(t, 1.0, b.RampUpRatio())
+}
+
+func TestBreaker_RampUpKeepsStateChangeCallback(t *testing.T) {
+	changes := make(chan State, 4)
+	b := NewBreaker("rampup-callback",
+		WithTripPolicy(NewConsecutiveFailures(1)),
+		WithRampUp(time.Minute),
+		WithOnStateChange(func(_ string, _, to State) { changes <- to }),
+	)
+	_ = b.Do(context.Background(), func() error { return errTest })
+
+	select {
+	case to := <-changes:
+		assert.Equal(t, StateOpen, to)
+	case <-time.After(time.Second):
+		t.Fatal("OnStateChange callback not invoked")
+	}
+}
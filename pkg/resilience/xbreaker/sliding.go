@@ -0,0 +1,223 @@
+package xbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+// 滑动窗口失败率策略默认值
+const (
+	// DefaultSlidingWindow 默认滑动窗口时长
+	DefaultSlidingWindow = 60 * time.Second
+
+	// DefaultSlidingWindowBuckets 默认滑动窗口桶数
+	DefaultSlidingWindowBuckets uint32 = 10
+)
+
+// outcomeTracker 由需要逐次调用结果的策略实现
+//
+// 与 latencyTracker 相同，Breaker 在 NewBreaker 时从 TripPolicy（含 CompositePolicy 子策略）中解析，
+// 未配置时 Do/Execute 不上报调用结果。
+type outcomeTracker interface {
+	// observeOutcome 记录一次调用结果
+	observeOutcome(success bool)
+	// resetOutcomes 清空窗口（熔断器状态变化时调用）
+	resetOutcomes()
+}
+
+// windowBucket 滑动窗口中的一个桶
+type windowBucket struct {
+	epoch     int64 // 桶对应的时间片序号（UnixNano / 桶宽），不等于当前序号时视为过期
+	successes uint32
+	failures  uint32
+}
+
+// SlidingWindowRatioPolicy 滑动时间窗口失败率熔断策略
+//
+// 在最近 window 时长内统计失败率，失败率达到阈值且请求数不少于 minRequests 时触发熔断。
+// 窗口被划分为 buckets 个桶，按时间逐桶滑动：旧桶过期后单独移出统计，
+// 不会像 FailureRatioPolicy 依赖的 WithInterval 固定窗口那样在周期边界整体清零，
+// 判定结果不受请求落在周期哪个位置的影响。
+//
+// 与 WithBucketPeriod 的区别：WithBucketPeriod 让 gobreaker 的 Counts 整体按桶滑动，
+// 影响所有基于 Counts 的策略；本策略自行维护窗口，不依赖 WithInterval/WithBucketPeriod，
+// 可与 ConsecutiveFailuresPolicy 等策略组合而互不干扰。
+//
+// 内存开销：固定为 buckets 个桶的环形数组（每桶 16 字节），与请求量无关；
+// 相比 FailureRatioPolicy 直接读取 gobreaker 的计数器，额外付出这部分内存和每次调用一次加锁。
+// 桶数越多滑动越平滑，桶数越少开销越小，通常 10 个桶已足够。
+//
+// 注意：
+//   - 调用结果仅在 [Breaker.Do] 和 [Execute] 中上报；ManagedBreaker、RetryThenBreak 不上报，使用本策略不会熔断
+//   - 被 ExcludePolicy 排除的调用不计入窗口，成功与否由 SuccessPolicy/Classifier 判定
+//   - 熔断器状态变化时窗口被清空，恢复后基于新的调用重新统计
+//   - SlidingWindowRatioPolicy 持有窗口状态，不应在多个 Breaker 间共享
+type SlidingWindowRatioPolicy struct {
+	ratioPolicy
+	window time.Duration
+	width  time.Duration // 单个桶的时间跨度
+	now    func() time.Time
+
+	mu      sync.Mutex
+	buckets []windowBucket // 环形数组，按 epoch % len 定位
+}
+
+// NewSlidingWindowRatio 创建滑动时间窗口失败率熔断策略
+//
+// ratio: 失败率阈值 (0.0 - 1.0)，超出范围时截断
+// minRequests: 窗口内的最小请求数，不足时不触发熔断
+// window: 窗口时长，<= 0 时使用 DefaultSlidingWindow
+// buckets: 窗口桶数，为 0 时使用 DefaultSlidingWindowBuckets
+//
+// 示例:
+//
+//	breaker := xbreaker.NewBreaker("my-service",
+//	    xbreaker.WithTripPolicy(xbreaker.NewSlidingWindowRatio(0.5, 20, time.Minute, 12)),
+//	)
+//	// 最近 1 分钟（12 个 5 秒的桶）内请求数 >= 20 且失败率 >= 50% 时触发熔断
+func NewSlidingWindowRatio(ratio float64, minRequests uint32, window time.Duration, buckets uint32) *SlidingWindowRatioPolicy {
+	if window <= 0 {
+		window = DefaultSlidingWindow
+	}
+	if buckets == 0 {
+		buckets = DefaultSlidingWindowBuckets
+	}
+	return &SlidingWindowRatioPolicy{
+		ratioPolicy: newRatioPolicy(ratio, minRequests),
+		window:      window,
+		width:       max(window/time.Duration(buckets), 1),
+		now:         time.Now,
+		buckets:     make([]windowBucket, buckets),
+	}
+}
+
+// ReadyToTrip 判断是否应该触发熔断
+//
+// 忽略 counts，仅依据滑动窗口内的统计判定。
+func (p *SlidingWindowRatioPolicy) ReadyToTrip(_ Counts) bool {
+	return p.readyToTrip(p.Counts())
+}
+
+// Window 返回窗口时长
+func (p *SlidingWindowRatioPolicy) Window() time.Duration {
+	return p.window
+}
+
+// Buckets 返回窗口桶数
+func (p *SlidingWindowRatioPolicy) Buckets() uint32 {
+	return uint32(len(p.buckets)) //nolint:gosec // 由 uint32 buckets 构造，不会溢出
+}
+
+// Counts 返回当前窗口内的统计，仅填充 Requests、TotalSuccesses、TotalFailures
+//
+// 可用于监控上报。
+func (p *SlidingWindowRatioPolicy) Counts() Counts {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	current := p.epochLocked()
+	oldest := current - int64(len(p.buckets)) + 1
+	var c Counts
+	for _, b := range p.buckets {
+		if b.epoch < oldest || b.epoch > current {
+			continue
+		}
+		c.TotalSuccesses += b.successes
+		c.TotalFailures += b.failures
+	}
+	c.Requests = c.TotalSuccesses + c.TotalFailures
+	return c
+}
+
+// observeOutcome 实现 outcomeTracker
+func (p *SlidingWindowRatioPolicy) observeOutcome(success bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	epoch := p.epochLocked()
+	b := &p.buckets[epoch%int64(len(p.buckets))]
+	if b.epoch != epoch {
+		*b = windowBucket{epoch: epoch}
+	}
+	if success {
+		b.successes++
+	} else {
+		b.failures++
+	}
+}
+
+// resetOutcomes 实现 outcomeTracker
+func (p *SlidingWindowRatioPolicy) resetOutcomes() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clear(p.buckets)
+}
+
+// epochLocked 返回当前时间所在的时间片序号，调用方需持有 mu
+func (p *SlidingWindowRatioPolicy) epochLocked() int64 {
+	return p.now().UnixNano() / int64(p.width)
+}
+
+// multiOutcomeTracker 聚合 CompositePolicy 中的多个结果追踪策略
+type multiOutcomeTracker []outcomeTracker
+
+func (m multiOutcomeTracker) observeOutcome(success bool) {
+	for _, t := range m {
+		t.observeOutcome(success)
+	}
+}
+
+func (m multiOutcomeTracker) resetOutcomes() {
+	for _, t := range m {
+		t.resetOutcomes()
+	}
+}
+
+// outcomeTrackerOf 从策略中解析结果追踪器，递归展开 CompositePolicy；无则返回 nil
+func outcomeTrackerOf(p TripPolicy) outcomeTracker {
+	switch v := p.(type) {
+	case outcomeTracker:
+		return v
+	case *CompositePolicy:
+		var trackers multiOutcomeTracker
+		for _, child := range v.policies {
+			if t := outcomeTrackerOf(child); t != nil {
+				trackers = append(trackers, t)
+			}
+		}
+		switch len(trackers) {
+		case 0:
+			return nil
+		case 1:
+			return trackers[0]
+		}
+		return trackers
+	}
+	return nil
+}
+
+// observeCall 向延迟策略和结果追踪策略上报本次调用，返回交给 gobreaker 判定的错误
+func (b *Breaker) observeCall(start time.Time, err error) error {
+	if b.latency != nil {
+		err = b.trackLatency(start, err)
+	}
+	if b.outcomes != nil {
+		b.trackOutcome(err)
+	}
+	return err
+}
+
+// trackOutcome 上报交给 gobreaker 判定的调用结果
+//
+// 必须在 gobreaker 调用 ReadyToTrip 之前执行，使本次失败计入判定。
+func (b *Breaker) trackOutcome(err error) {
+	// 延迟超阈值标记始终计为失败，不交给用户策略判定，与 buildSettings 一致
+	if err == errLatencyExceeded {
+		b.outcomes.observeOutcome(false)
+		return
+	}
+	if b.IsExcluded(err) {
+		return
+	}
+	b.outcomes.observeOutcome(b.IsSuccessful(err))
+}
@@ -0,0 +1,180 @@
+package xbreaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSlidingWindowRatio(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p := NewSlidingWindowRatio(0.5, 10, 0, 0)
+		assert.Equal(t, DefaultSlidingWindow, p.Window())
+		assert.Equal(t, DefaultSlidingWindowBuckets, p.Buckets())
+		assert.Equal(t, 0.5, p.Ratio())
+		assert.Equal(t, uint32(10), p.MinRequests())
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		p := NewSlidingWindowRatio(1.5, 0, time.Minute, 6)
+		assert.Equal(t, time.Minute, p.Window())
+		assert.Equal(t, uint32(6), p.Buckets())
+		assert.Equal(t, 10*time.Second, p.width)
+		assert.Equal(t, 1.0, p.Ratio(), "ratio is clamped")
+	})
+
+	t.Run("more buckets than nanoseconds", func(t *testing.T) {
+		p := NewSlidingWindowRatio(0.5, 1, 2*time.Nanosecond, 10)
+		assert.Equal(t, time.Duration(1), p.width)
+	})
+}
+
+// newSlidingWindowWithClock 创建使用可控时钟的策略，返回推进时钟的函数
+func newSlidingWindowWithClock(ratio float64, minRequests uint32, window time.Duration, buckets uint32) (*SlidingWindowRatioPolicy, func(time.Duration)) {
+	p := NewSlidingWindowRatio(ratio, minRequests, window, buckets)
+	now := time.Unix(1_700_000_000, 0)
+	p.now = func() time.Time { return now }
+	return p, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSlidingWindowRatio_Window(t *testing.T) {
+	t.Run("min requests", func(t *testing.T) {
+		p, _ := newSlidingWindowWithClock(0.5, 4, 10*time.Second, 10)
+		for range 3 {
+			p.observeOutcome(false)
+		}
+		assert.False(t, p.ReadyToTrip(Counts{}))
+		p.observeOutcome(false)
+		assert.True(t, p.ReadyToTrip(Counts{}))
+	})
+
+	t.Run("ratio", func(t *testing.T) {
+		p, _ := newSlidingWindowWithClock(0.5, 1, 10*time.Second, 10)
+		p.observeOutcome(true)
+		p.observeOutcome(true)
+		p.observeOutcome(false)
+		assert.False(t, p.ReadyToTrip(Counts{}))
+		p.observeOutcome(false)
+		assert.True(t, p.ReadyToTrip(Counts{}), "2/4 reaches 50%")
+	})
+
+	t.Run("ignores gobreaker counts", func(t *testing.T) {
+		p, _ := newSlidingWindowWithClock(0.5, 1, 10*time.Second, 10)
+		assert.False(t, p.ReadyToTrip(Counts{Requests: 100, TotalFailures: 100}))
+	})
+
+	t.Run("old buckets slide out one by one", func(t *testing.T) {
+		p, advance := newSlidingWindowWithClock(0.5, 1, 10*time.Second, 10)
+		p.observeOutcome(false) // 第 0 秒
+		advance(5 * time.Second)
+		p.observeOutcome(true) // 第 5 秒
+		assert.Equal(t, Counts{Requests: 2, TotalSuccesses: 1, TotalFailures: 1}, p.Counts())
+
+		advance(4 * time.Second) // 第 9 秒：两个样本都在窗口内
+		assert.Equal(t, uint32(2), p.Counts().Requests)
+
+		advance(time.Second) // 第 10 秒：第 0 秒的失败移出，成功仍保留
+		assert.Equal(t, Counts{Requests: 1, TotalSuccesses: 1}, p.Counts())
+		assert.False(t, p.ReadyToTrip(Counts{}))
+
+		advance(5 * time.Second)
+		assert.Equal(t, Counts{}, p.Counts())
+	})
+
+	t.Run("reused bucket is cleared", func(t *testing.T) {
+		p, advance := newSlidingWindowWithClock(0.5, 1, 10*time.Second, 10)
+		p.observeOutcome(false)
+		advance(10 * time.Second) // 落在同一个环形槽位
+		p.observeOutcome(true)
+		assert.Equal(t, Counts{Requests: 1, TotalSuccesses: 1}, p.Counts())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		p, _ := newSlidingWindowWithClock(0.5, 1, 10*time.Second, 10)
+		p.observeOutcome(false)
+		p.resetOutcomes()
+		assert.Equal(t, Counts{}, p.Counts())
+	})
+}
+
+func TestSlidingWindowRatio_NoBoundaryReset(t *testing.T) {
+	// 失败集中在固定窗口边界两侧：固定窗口在边界清零后两边都达不到阈值，
+	// 滑动窗口仍能看到完整的失败序列
+	p, advance := newSlidingWindowWithClock(0.5, 4, 10*time.Second, 10)
+	advance(8 * time.Second)
+	p.observeOutcome(false)
+	p.observeOutcome(false)
+	advance(3 * time.Second)
+	p.observeOutcome(false)
+	assert.False(t, p.ReadyToTrip(Counts{}))
+	p.observeOutcome(false)
+	assert.True(t, p.ReadyToTrip(Counts{}))
+}
+
+func TestOutcomeTrackerOf(t *testing.T) {
+	s1 := NewSlidingWindowRatio(0.5, 1, 0, 0)
+	s2 := NewSlidingWindowRatio(0.5, 1, 0, 0)
+
+	assert.Nil(t, outcomeTrackerOf(NewFailureRatio(0.5, 1)))
+	assert.Nil(t, outcomeTrackerOf(NewCompositePolicy(NewLatencyPolicy(time.Second, 0, 0))))
+	assert.Same(t, s1, outcomeTrackerOf(s1))
+	assert.Same(t, s1, outcomeTrackerOf(NewCompositePolicy(NewConsecutiveFailures(3), s1)))
+
+	nested := outcomeTrackerOf(NewCompositePolicy(s1, NewCompositePolicy(s2)))
+	require.IsType(t, multiOutcomeTracker{}, nested)
+	nested.observeOutcome(false)
+	assert.Equal(t, uint32(1), s1.Counts().TotalFailures)
+	assert.Equal(t, uint32(1), s2.Counts().TotalFailures)
+	nested.resetOutcomes()
+	assert.Zero(t, s1.Counts().Requests)
+	assert.Zero(t, s2.Counts().Requests)
+}
+
+func TestBreaker_SlidingWindowTrip(t *testing.T) {
+	policy := NewSlidingWindowRatio(0.5, 4, time.Minute, 6)
+	b := NewBreaker("sliding", WithTripPolicy(policy), WithTimeout(time.Hour))
+	ctx := context.Background()
+
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	require.NoError(t, b.Do(ctx, func() error { return nil }))
+	assert.ErrorIs(t, b.Do(ctx, func() error { return errTest }), errTest)
+	assert.Equal(t, StateClosed, b.State())
+
+	_, err := Execute(ctx, b, func() (int, error) { return 0, errTest })
+	assert.ErrorIs(t, err, errTest)
+	assert.Equal(t, StateOpen, b.State())
+	assert.Zero(t, policy.Counts().Requests, "window is cleared on state change")
+}
+
+func TestBreaker_SlidingWindowClassification(t *testing.T) {
+	bizErr := errors.New("biz")
+	policy := NewSlidingWindowRatio(0.5, 1, time.Minute, 6)
+	b := NewBreaker("sliding",
+		WithTripPolicy(policy),
+		WithSuccessPolicy(successFunc(func(err error) bool { return err == nil || errors.Is(err, bizErr) })),
+		WithExcludePolicy(excludeFunc(func(err error) bool { return errors.Is(err, context.Canceled) })),
+	)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, b.Do(ctx, func() error { return bizErr }), bizErr)
+	assert.ErrorIs(t, b.Do(ctx, func() error { return context.Canceled }), context.Canceled)
+	assert.Equal(t, Counts{Requests: 1, TotalSuccesses: 1}, policy.Counts())
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_TrackOutcomeLatencyExceeded(t *testing.T) {
+	policy := NewSlidingWindowRatio(1, 1, time.Minute, 6)
+	b := NewBreaker("sliding",
+		WithTripPolicy(policy),
+		WithSuccessPolicy(successFunc(func(error) bool { return true })),
+		WithExcludePolicy(excludeFunc(func(error) bool { return true })),
+	)
+
+	b.trackOutcome(errLatencyExceeded)
+	assert.Equal(t, Counts{Requests: 1, TotalFailures: 1}, policy.Counts(),
+		"latency marker is a failure regardless of user policies")
+}
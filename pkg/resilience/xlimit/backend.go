@@ -9,9 +9,12 @@ import (
 type CheckResult struct {
 	Allowed    bool          // 是否允许
 	Limit      int           // 实际使用的配额上限（本地后端可能会调整）
+	Burst      int           // 实际使用的突发容量（令牌桶容量；滑动窗口等于 Limit）
 	Remaining  int           // 剩余配额
 	ResetAt    time.Time     // 配额重置时间
-	RetryAfter time.Duration // 如果被限流，建议重试等待时间
+	RetryAfter time.Duration // 如果被限流，建议重试等待时间；-1 表示 n 超过容量，永远无法满足
+
+	grant slidingGrant // 滑动窗口放行时写入的记录，供 Reservation.Cancel 精确归还
 }
 
 // Backend 定义限流后端的核心操作接口
@@ -34,9 +37,9 @@ type Backend interface {
 	Reset(ctx context.Context, key string) error
 
 	// Query 查询当前配额状态（不消耗配额）
-	// effectiveLimit 为后端实际生效的 limit（本地后端会按 podCount 调整）
-	Query(ctx context.Context, key string, limit, burst int, window time.Duration) (
-		effectiveLimit, remaining int, resetAt time.Time, err error)
+	// 返回的 Limit/Burst 为后端实际生效的值（本地后端会按 podCount 调整），
+	// Remaining 为当前可立即放行的请求数（令牌桶不超过 Burst），Allowed 与 RetryAfter 无意义
+	Query(ctx context.Context, key string, limit, burst int, window time.Duration) (CheckResult, error)
 
 	// Close 释放后端自有资源（不关闭注入的外部客户端）
 	// 设计决策: 保留 ctx 参数（D-02），当前未使用但预留用于未来超时控制。
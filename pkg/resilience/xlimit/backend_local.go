@@ -2,6 +2,7 @@ package xlimit
 
 import (
 	"context"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -24,19 +25,23 @@ const maxBuckets = 1 << 16 // 65536
 // (4) bucketCount 超过 maxBuckets 时拒绝创建新桶（fail-close），防止 OOM。
 // 如需定期清理，由调用方通过重建 limiter 实例实现。
 type localBackend struct {
-	buckets          sync.Map // map[string]*tokenBucket
+	buckets          sync.Map // map[string]*tokenBucket 或 map[string]*slidingLog（取决于 algorithm）
 	bucketCount      atomic.Int64
 	podCount         int
 	podCountProvider PodCountProvider
 	logger           xlog.Logger // 可选，nil 时使用 slog 降级
+	algorithm        Algorithm
+	warmups          sync.Map // map[string]*warmupEntry，仅启用 WithWarmup 时使用
+	warmupCount      atomic.Int64
 }
 
 // newLocalBackend 创建本地后端
-func newLocalBackend(podCount int, podCountProvider PodCountProvider, logger xlog.Logger) *localBackend {
+func newLocalBackend(podCount int, podCountProvider PodCountProvider, logger xlog.Logger, algorithm Algorithm) *localBackend {
 	return &localBackend{
 		podCount:         podCount,
 		podCountProvider: podCountProvider,
 		logger:           logger,
+		algorithm:        algorithm,
 	}
 }
 
@@ -51,6 +56,12 @@ func (b *localBackend) Type() string {
 // 与 Redis 后端（redis_rate.Limit{Rate: limit, Burst: burst}）语义对齐。
 // 降级场景下本地后端可正确处理突发流量。
 func (b *localBackend) CheckRule(ctx context.Context, key string, limit, burst int, window time.Duration, n int) (CheckResult, error) {
+	return b.check(ctx, key, limit, burst, window, float64(n))
+}
+
+// check CheckRule 与 CheckRuleCost 的公共实现
+// 令牌桶以浮点令牌数原生支持小数成本，滑动窗口按整数请求计数，成本向上取整
+func (b *localBackend) check(ctx context.Context, key string, limit, burst int, window time.Duration, cost float64) (CheckResult, error) {
 	if err := ctx.Err(); err != nil {
 		return CheckResult{}, err
 	}
@@ -62,30 +73,46 @@ func (b *localBackend) CheckRule(ctx context.Context, key string, limit, burst i
 	localLimit := max(limit/podCount, 1)
 	localBurst := max(burst/podCount, 1)
 
+	if b.algorithm == AlgoSlidingWindow {
+		log := b.getOrCreateLog(key)
+		if log == nil {
+			return b.overflowResult(localLimit, window), nil
+		}
+		return log.take(localLimit, window, int(math.Ceil(cost))), nil
+	}
+
 	bucket := b.getOrCreateBucket(key, localLimit, localBurst, window)
 	if bucket == nil {
-		// 桶数量超过 maxBuckets 安全阀，拒绝新 key（fail-close）
-		return CheckResult{
-			Allowed:    false,
-			Limit:      localLimit,
-			Remaining:  0,
-			ResetAt:    time.Now().Add(window),
-			RetryAfter: window,
-		}, nil
+		res := b.overflowResult(localLimit, window)
+		res.Burst = localBurst
+		return res, nil
 	}
 	// take 内部会先按旧参数补令牌到 now，再切换到新参数，避免参数变更
 	// retroactive 应用于过去区间导致的过放/欠放（FG-M2 fix）。
-	allowed, remaining, retryAfter := bucket.takeWithParams(localLimit, localBurst, window, n)
+	allowed, remaining, retryAfter := bucket.takeWithParams(localLimit, localBurst, window, cost)
 
 	return CheckResult{
 		Allowed:    allowed,
 		Limit:      localLimit,
+		Burst:      localBurst,
 		Remaining:  remaining,
 		ResetAt:    time.Now().Add(window),
 		RetryAfter: retryAfter,
 	}, nil
 }
 
+// overflowResult 桶数量超过 maxBuckets 安全阀时的拒绝结果（fail-close）
+func (b *localBackend) overflowResult(limit int, window time.Duration) CheckResult {
+	return CheckResult{
+		Allowed:    false,
+		Limit:      limit,
+		Burst:      limit,
+		Remaining:  0,
+		ResetAt:    time.Now().Add(window),
+		RetryAfter: window,
+	}
+}
+
 // Reset 重置指定键的限流计数
 func (b *localBackend) Reset(_ context.Context, key string) error {
 	if _, loaded := b.buckets.LoadAndDelete(key); loaded {
@@ -94,32 +121,64 @@ func (b *localBackend) Reset(_ context.Context, key string) error {
 	return nil
 }
 
+// Refund 向 rule.key 归还 n 个配额，实现 refunder
+//
+// 桶不存在（已 Reset 或从未创建）时视为已满，无需归还。
+// 令牌数截断到桶当前容量，滑动窗口只移除本次预留写入的记录。
+func (b *localBackend) Refund(ctx context.Context, rule consumedRule, n int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	val, ok := b.buckets.Load(rule.key)
+	if !ok {
+		return nil
+	}
+	switch v := val.(type) {
+	case *tokenBucket:
+		v.refund(n)
+	case *slidingLog:
+		v.refund(rule.grant.id)
+	}
+	return nil
+}
+
 // Query 查询当前配额状态（不消耗配额）
 //
 // 设计决策: 已有桶时按经过时间补充令牌后返回（只读，不修改桶状态），
 // 与 take() 的补令牌逻辑一致；无桶时返回 localBurst（桶容量）作为剩余配额，
 // 与新创建桶的初始令牌数（burst）语义对齐。
-func (b *localBackend) Query(ctx context.Context, key string, limit, burst int, window time.Duration) (
-	effectiveLimit, remaining int, resetAt time.Time, err error) {
+func (b *localBackend) Query(ctx context.Context, key string, limit, burst int, window time.Duration) (CheckResult, error) {
 	// 与 CheckRule 的 ctx 契约一致（FG-M4 fix）
-	if cerr := ctx.Err(); cerr != nil {
-		return 0, 0, time.Time{}, cerr
+	if err := ctx.Err(); err != nil {
+		return CheckResult{}, err
 	}
 	// 获取当前 Pod 数量
 	podCount := b.getPodCount(ctx)
 	localLimit := max(limit/podCount, 1)
 	localBurst := max(burst/podCount, 1)
 
-	resetAt = time.Now().Add(window)
-	remaining = localBurst // 无桶时默认为桶容量，与新建桶初始 tokens=burst 一致
+	if b.algorithm == AlgoSlidingWindow {
+		res := CheckResult{Limit: localLimit, Burst: localLimit, Remaining: localLimit, ResetAt: time.Now()}
+		if val, ok := b.buckets.Load(key); ok {
+			if log, ok := val.(*slidingLog); ok {
+				res.Remaining, res.ResetAt = log.remaining(localLimit, window)
+			}
+		}
+		return res, nil
+	}
 
+	res := CheckResult{
+		Limit:     localLimit,
+		Burst:     localBurst,
+		Remaining: localBurst, // 无桶时默认为桶容量，与新建桶初始 tokens=burst 一致
+		ResetAt:   time.Now().Add(window),
+	}
 	if val, ok := b.buckets.Load(key); ok {
 		if bucket, ok := val.(*tokenBucket); ok {
-			remaining = bucket.currentTokens(localLimit, localBurst, window)
+			res.Remaining = bucket.currentTokens(localLimit, localBurst, window)
 		}
 	}
-
-	return localLimit, remaining, resetAt, nil
+	return res, nil
 }
 
 // Close 关闭后端
@@ -157,14 +216,8 @@ func (b *localBackend) getOrCreateBucket(key string, limit, burst int, window ti
 	}
 
 	// CAS 预留桶名额
-	for {
-		cur := b.bucketCount.Load()
-		if cur >= maxBuckets {
-			return nil
-		}
-		if b.bucketCount.CompareAndSwap(cur, cur+1) {
-			break
-		}
+	if !b.reserveSlot() {
+		return nil
 	}
 
 	bucket := &tokenBucket{
@@ -186,6 +239,38 @@ func (b *localBackend) getOrCreateBucket(key string, limit, burst int, window ti
 	return bucket
 }
 
+// getOrCreateLog 获取或创建滑动窗口日志，与令牌桶共享 maxBuckets 安全阀
+func (b *localBackend) getOrCreateLog(key string) *slidingLog {
+	if val, ok := b.buckets.Load(key); ok {
+		if log, ok := val.(*slidingLog); ok {
+			return log
+		}
+	}
+	if !b.reserveSlot() {
+		return nil
+	}
+	actual, loaded := b.buckets.LoadOrStore(key, &slidingLog{})
+	if loaded {
+		b.bucketCount.Add(-1)
+	}
+	// buckets 中的类型由 algorithm 决定，同一实例内一致
+	log, _ := actual.(*slidingLog)
+	return log
+}
+
+// reserveSlot 以 CAS 预留一个桶名额，超过 maxBuckets 时返回 false
+func (b *localBackend) reserveSlot() bool {
+	for {
+		cur := b.bucketCount.Load()
+		if cur >= maxBuckets {
+			return false
+		}
+		if b.bucketCount.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+	}
+}
+
 // tokenBucket 令牌桶实现
 // limit 控制补令牌速率（limit/window），burst 控制桶容量（突发上限）
 type tokenBucket struct {
@@ -198,9 +283,9 @@ type tokenBucket struct {
 }
 
 // takeWithParams 在桶锁内：先按旧参数补令牌到 now，再切换新参数（截断到新 burst），
-// 最后尝试消耗 n 个令牌。确保参数变更（Pod 数/规则覆盖）不会 retroactive 地
+// 最后尝试消耗 n 个令牌（n 可为小数成本）。确保参数变更（Pod 数/规则覆盖）不会 retroactive 地
 // 用新速率重新计算过去时段（FG-M2 fix）。
-func (tb *tokenBucket) takeWithParams(limit, burst int, window time.Duration, n int) (
+func (tb *tokenBucket) takeWithParams(limit, burst int, window time.Duration, n float64) (
 	allowed bool, remaining int, retryAfter time.Duration) {
 	tb.mu.Lock()
 	defer tb.mu.Unlock()
@@ -229,21 +314,38 @@ func (tb *tokenBucket) takeWithParams(limit, burst int, window time.Duration, n
 		tb.tokens = float64(burst)
 	}
 
-	if float64(n) <= tb.tokens {
-		tb.tokens -= float64(n)
-		return true, int(tb.tokens), 0
+	// 设计决策: 令牌数以浮点存储，反复扣减小数成本会累积表示误差（如 3 - 0.6*5 略大于 0），
+	// 比较时放宽 costEpsilon 并在扣减后截断到 0；costEpsilon 远小于成本精度 1/costScale，不会多放行。
+	if n <= tb.tokens+costEpsilon {
+		tb.tokens = max(tb.tokens-n, 0)
+		return true, wholeTokens(tb.tokens), 0
 	}
 
 	// 令牌不足
+	if n > float64(burst) {
+		// 超过桶容量，等待多久都无法满足
+		return false, 0, -1
+	}
 	if window <= 0 || limit <= 0 {
 		return false, 0, 0
 	}
 	rate := float64(limit) / window.Seconds()
-	deficit := float64(n) - tb.tokens
+	deficit := n - tb.tokens
 	waitTime := time.Duration(deficit / rate * float64(time.Second))
 	return false, 0, waitTime
 }
 
+// refund 归还 n 个令牌，不超过桶容量
+//
+// 不需要先补令牌到 now：截断后的令牌数加上之后的补充量仍会被 take 截断到 burst，
+// 结果与先补令牌再归还一致。
+func (tb *tokenBucket) refund(n int) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.tokens = min(tb.tokens+float64(n), float64(tb.burst))
+}
+
 // currentTokens 返回当前令牌数（只读查询，不修改桶状态）
 // 按经过时间补充令牌后返回，与 take() 的补令牌逻辑一致。
 func (tb *tokenBucket) currentTokens(limit, burst int, window time.Duration) int {
@@ -255,7 +357,7 @@ func (tb *tokenBucket) currentTokens(limit, burst int, window time.Duration) int
 		elapsed = 0
 	}
 	if window <= 0 {
-		return int(tb.tokens)
+		return wholeTokens(tb.tokens)
 	}
 	rate := float64(limit) / window.Seconds()
 	tokens := tb.tokens + rate*elapsed.Seconds()
@@ -263,8 +365,16 @@ func (tb *tokenBucket) currentTokens(limit, burst int, window time.Duration) int
 	if tokens > float64(burst) {
 		tokens = float64(burst)
 	}
-	return int(tokens)
+	return wholeTokens(tokens)
 }
 
-// 确保 localBackend 实现了 Backend 接口
-var _ Backend = (*localBackend)(nil)
+// wholeTokens 返回向下取整的令牌数，按 costEpsilon 吸收浮点误差（如 2.9999999999999996 记为 3）
+func wholeTokens(tokens float64) int {
+	return int(tokens + costEpsilon)
+}
+
+// 确保 localBackend 实现了 Backend 与 refunder 接口
+var (
+	_ Backend  = (*localBackend)(nil)
+	_ refunder = (*localBackend)(nil)
+)
@@ -8,17 +8,57 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+// redisRateKeyPrefix redis_rate 内部使用的键前缀，归还配额时需直接操作同一个键。
+const redisRateKeyPrefix = "rate:"
+
+// refundScript GCRA 令牌归还 Lua 脚本
+//
+// KEYS[1]: redis_rate 的 TAT（理论到达时间）键
+// ARGV[1]: rate
+// ARGV[2]: period（秒）
+// ARGV[3]: n，归还的令牌数
+//
+// 将 TAT 回拨 n 个发放间隔。TAT 不晚于当前时间即表示桶已满，此时直接删除键，
+// 保证归还后可用令牌数不超过 Burst。时间基准与 redis_rate 脚本一致。
+var refundScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+local tat = redis.call("GET", key)
+if not tat then
+  return 0
+end
+
+local jan_1_2017 = 1483228800
+local now = redis.call("TIME")
+now = (now[1] - jan_1_2017) + (now[2] / 1000000)
+
+local new_tat = tonumber(tat) - (period / rate) * n
+if new_tat <= now then
+  redis.call("DEL", key)
+  return 0
+end
+redis.call("SET", key, new_tat, "EX", math.ceil(new_tat - now))
+return 0
+`)
+
 // redisBackend 基于 Redis 的分布式限流后端
 type redisBackend struct {
-	limiter *redis_rate.Limiter
-	rdb     redis.UniversalClient
+	limiter   *redis_rate.Limiter
+	rdb       redis.UniversalClient
+	algorithm Algorithm
 }
 
 // newRedisBackend 创建 Redis 后端
-func newRedisBackend(rdb redis.UniversalClient) *redisBackend {
+func newRedisBackend(rdb redis.UniversalClient, algorithm Algorithm) *redisBackend {
 	return &redisBackend{
-		limiter: redis_rate.NewLimiter(rdb),
-		rdb:     rdb,
+		limiter:   redis_rate.NewLimiter(rdb),
+		rdb:       rdb,
+		algorithm: algorithm,
 	}
 }
 
@@ -29,6 +69,10 @@ func (b *redisBackend) Type() string {
 
 // CheckRule 检查单个规则是否允许请求通过
 func (b *redisBackend) CheckRule(ctx context.Context, key string, limit, burst int, window time.Duration, n int) (CheckResult, error) {
+	if b.algorithm == AlgoSlidingWindow {
+		return b.slidingWindowCheck(ctx, key, limit, window, n)
+	}
+
 	rateLimit := redis_rate.Limit{
 		Rate:   limit,
 		Burst:  burst,
@@ -40,23 +84,49 @@ func (b *redisBackend) CheckRule(ctx context.Context, key string, limit, burst i
 		return CheckResult{}, err
 	}
 
+	retryAfter := res.RetryAfter
+	if res.Allowed == 0 && n > burst {
+		// GCRA 对超过桶容量的请求仍给出正的 RetryAfter，但等待后依然会被拒绝；
+		// 与本地后端、滑动窗口一致返回 -1 表示永远无法满足
+		retryAfter = -1
+	}
+
 	return CheckResult{
 		Allowed:    res.Allowed > 0,
 		Limit:      limit,
+		Burst:      burst,
 		Remaining:  res.Remaining,
 		ResetAt:    time.Now().Add(res.ResetAfter),
-		RetryAfter: res.RetryAfter,
+		RetryAfter: retryAfter,
 	}, nil
 }
 
 // Reset 重置指定键的限流计数
 func (b *redisBackend) Reset(ctx context.Context, key string) error {
+	if b.algorithm == AlgoSlidingWindow {
+		return b.rdb.Del(ctx, slidingWindowKeyPrefix+key).Err()
+	}
 	return b.limiter.Reset(ctx, key)
 }
 
+// Refund 向 rule.key 归还 n 个配额，实现 refunder
+func (b *redisBackend) Refund(ctx context.Context, rule consumedRule, n int) error {
+	if b.algorithm == AlgoSlidingWindow {
+		return b.slidingWindowRefund(ctx, rule.key, rule.grant)
+	}
+	return refundScript.Run(ctx, b.rdb, []string{redisRateKeyPrefix + rule.key},
+		rule.limit, rule.window.Seconds(), n).Err()
+}
+
 // Query 查询当前配额状态（不消耗配额）
-func (b *redisBackend) Query(ctx context.Context, key string, limit, burst int, window time.Duration) (
-	effectiveLimit, remaining int, resetAt time.Time, err error) {
+func (b *redisBackend) Query(ctx context.Context, key string, limit, burst int, window time.Duration) (CheckResult, error) {
+	if b.algorithm == AlgoSlidingWindow {
+		res, err := b.slidingWindowCheck(ctx, key, limit, window, 0)
+		if err != nil {
+			return CheckResult{}, err
+		}
+		return res, nil
+	}
 
 	rateLimit := redis_rate.Limit{
 		Rate:   limit,
@@ -65,12 +135,18 @@ func (b *redisBackend) Query(ctx context.Context, key string, limit, burst int,
 	}
 
 	// 使用 AllowN(0) 来查询当前状态而不消耗配额
+	// GCRA 的 Remaining 以 Burst 为上限，即当前可立即放行的突发容量
 	res, err := b.limiter.AllowN(ctx, key, rateLimit, 0)
 	if err != nil {
-		return 0, 0, time.Time{}, err
+		return CheckResult{}, err
 	}
 
-	return limit, res.Remaining, time.Now().Add(res.ResetAfter), nil
+	return CheckResult{
+		Limit:     limit,
+		Burst:     burst,
+		Remaining: res.Remaining,
+		ResetAt:   time.Now().Add(res.ResetAfter),
+	}, nil
 }
 
 // Close 关闭后端
@@ -78,5 +154,8 @@ func (b *redisBackend) Close(_ context.Context) error {
 	return nil
 }
 
-// 确保 redisBackend 实现了 Backend 接口
-var _ Backend = (*redisBackend)(nil)
+// 确保 redisBackend 实现了 Backend 与 refunder 接口
+var (
+	_ Backend  = (*redisBackend)(nil)
+	_ refunder = (*redisBackend)(nil)
+)
@@ -0,0 +1,101 @@
+package xlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// burstRule 稳态每分钟 100 个，突发容量 20
+func burstRule() Rule {
+	return NewRuleBuilder("tenant").
+		KeyTemplate("tenant:${tenant_id}").
+		Limit(100).
+		Window(time.Minute).
+		Burst(20).
+		Build()
+}
+
+func TestBurst_IndependentOfRate(t *testing.T) {
+	_, client := setupMiniredis(t)
+	distributed, err := New(client, WithRules(burstRule()), WithFallback(""))
+	require.NoError(t, err)
+	local, err := NewLocal(WithRules(burstRule()))
+	require.NoError(t, err)
+
+	for name, limiter := range map[string]Limiter{"redis": distributed, "local": local} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := Key{Tenant: "acme"}
+
+			info, err := limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 100, info.Limit)
+			assert.Equal(t, 20, info.Burst)
+			assert.Equal(t, 20, info.Remaining, "remaining is capped by burst, not limit")
+
+			res, err := limiter.AllowN(ctx, key, 20)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+
+			// 突发容量耗尽，稳态速率 100/min 约 600ms 补充一个
+			res, err = limiter.Allow(ctx, key)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Greater(t, res.RetryAfter, 500*time.Millisecond)
+			assert.LessOrEqual(t, res.RetryAfter, time.Second)
+
+			// 超过突发容量的批量请求永远无法满足
+			res, err = limiter.AllowN(ctx, key, 21)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, time.Duration(-1), res.RetryAfter)
+		})
+	}
+}
+
+func TestBurst_DefaultsToLimit(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 30, time.Minute)))
+	require.NoError(t, err)
+
+	info, err := limiter.(Querier).Query(context.Background(), Key{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, 30, info.Burst)
+	assert.Equal(t, 30, info.Remaining)
+}
+
+func TestBurst_LocalSplitByPodCount(t *testing.T) {
+	limiter, err := NewLocal(WithRules(burstRule()), WithPodCount(4))
+	require.NoError(t, err)
+
+	info, err := limiter.(Querier).Query(context.Background(), Key{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, 25, info.Limit)
+	assert.Equal(t, 5, info.Burst)
+	assert.Equal(t, 5, info.Remaining)
+}
+
+func TestBurst_SlidingWindowReportsLimit(t *testing.T) {
+	limiter, err := NewLocal(WithRules(burstRule()), WithAlgorithm(AlgoSlidingWindow))
+	require.NoError(t, err)
+
+	info, err := limiter.(Querier).Query(context.Background(), Key{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, info.Burst)
+	assert.Equal(t, 100, info.Remaining)
+}
+
+func TestBurst_Validation(t *testing.T) {
+	rule := burstRule()
+	rule.Burst = -1
+	err := rule.Validate()
+	require.ErrorIs(t, err, ErrInvalidRule)
+	assert.Contains(t, err.Error(), "burst must be >= 1")
+
+	rule.Burst = 0
+	assert.NoError(t, rule.Validate(), "0 means unset")
+	assert.Equal(t, 100, rule.EffectiveBurst())
+}
@@ -0,0 +1,152 @@
+package xlimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/omeyang/xkit/pkg/observability/xsampling"
+)
+
+// =============================================================================
+// 规则灰度（Canary）
+// =============================================================================
+
+// canaryRule 灰度规则：命中灰度的流量使用 rule 替换同名基线规则。
+type canaryRule struct {
+	rule    Rule
+	matcher *ruleMatcher // 仅包含 rule，用于计算灰度规则自身的 Override
+	sampler *xsampling.KeyBasedSampler
+}
+
+// canaryKeyCtxKey 向 KeyBasedSampler 传递灰度分组 key 的上下文键。
+type canaryKeyCtxKey struct{}
+
+// canaryKeyFromContext 是灰度采样器的 KeyFunc。
+func canaryKeyFromContext(ctx context.Context) string {
+	if key, ok := ctx.Value(canaryKeyCtxKey{}).(string); ok {
+		return key
+	}
+	return ""
+}
+
+// newCanaryRule 创建灰度规则，percentage 取值 [0, 100]。
+func newCanaryRule(rule Rule, percentage float64) (*canaryRule, error) {
+	if math.IsNaN(percentage) || percentage < 0 || percentage > 100 {
+		return nil, fmt.Errorf("%w: canary percentage must be in [0, 100], got %v", ErrInvalidRule, percentage)
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, fmt.Errorf("canary: %w", err)
+	}
+	sampler, err := xsampling.NewKeyBasedSampler(percentage/100, canaryKeyFromContext)
+	if err != nil {
+		return nil, fmt.Errorf("%w: canary: %w", ErrInvalidRule, err)
+	}
+	return &canaryRule{
+		rule:    rule,
+		matcher: newRuleMatcher([]Rule{rule}),
+		sampler: sampler,
+	}, nil
+}
+
+// cohortKey 返回决定灰度分组的 key。
+//
+// 设计决策: 有租户时按租户分组，否则按基线规则渲染后的键分组。
+// 同一租户（或同一限流桶）的全部请求始终落在同一组，避免同一个桶被两套配额交替计数；
+// 静态模板（如全局规则）渲染结果恒定，因此只能整体切换（0% 或 100%）。
+func cohortKey(key Key, baseRendered string) string {
+	if key.Tenant != "" {
+		return key.Tenant
+	}
+	return baseRendered
+}
+
+// selected 判断 key 是否命中灰度。
+//
+// 复用 xsampling 的一致性采样：同一分组 key 在所有 Pod 中得到相同结论，
+// 灰度比例调大时已命中的分组保持命中（哈希阈值单调），便于逐步放量。
+func (c *canaryRule) selected(key Key, baseRendered string) bool {
+	ctx := context.WithValue(context.Background(), canaryKeyCtxKey{}, cohortKey(key, baseRendered))
+	return c.sampler.ShouldSample(ctx)
+}
+
+// WithCanaryRule 对部分流量灰度应用新规则
+//
+// rule.Name 必须与某条已配置的基线规则同名，percentage 为命中灰度的流量比例，取值 [0, 100]。
+// 命中灰度的请求使用 rule 替换同名基线规则，其余请求仍使用基线规则，观察效果后再全量替换。
+//
+// 分组基于 xsampling 一致性采样：Key 带租户时按租户分组，否则按基线规则渲染后的键分组，
+// 同一分组在各 Pod 中结论一致。Result.Rule 仍为规则名，可通过 WithOnAllow/WithOnDeny
+// 结合 Result.Limit 观察灰度效果。
+//
+// 灰度规则与基线规则的 KeyTemplate 相同时共享计数；不同则各自独立计数。
+// percentage 越界或规则无效时 New/NewLocal 返回 ErrInvalidRule。
+func WithCanaryRule(rule Rule, percentage float64) Option {
+	return func(o *options) {
+		canary, err := newCanaryRule(rule, percentage)
+		if err != nil {
+			if o.initErr == nil {
+				o.initErr = err
+			}
+			return
+		}
+		o.canaries = append(o.canaries, canary)
+	}
+}
+
+// validateCanaries 校验灰度规则与基线规则的对应关系
+func (o *options) validateCanaries() error {
+	seen := make(map[string]struct{}, len(o.canaries))
+	for _, c := range o.canaries {
+		name := c.rule.Name
+		if _, dup := seen[name]; dup {
+			return fmt.Errorf("%w: duplicate canary rule %q", ErrInvalidRule, name)
+		}
+		seen[name] = struct{}{}
+
+		found := false
+		for _, r := range o.config.Rules {
+			if r.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%w: canary rule %q has no base rule", ErrInvalidRule, name)
+		}
+	}
+	return nil
+}
+
+// setCanaries 注册灰度规则，基线规则未启用时忽略对应灰度。
+func (rm *ruleMatcher) setCanaries(canaries []*canaryRule) {
+	for _, c := range canaries {
+		if !rm.hasRule(c.rule.Name) {
+			continue
+		}
+		if rm.canaries == nil {
+			rm.canaries = make(map[string]*canaryRule, len(canaries))
+		}
+		rm.canaries[c.rule.Name] = c
+	}
+}
+
+// resolveRule 返回 key 实际适用的规则、计算 Override 所用的匹配器与渲染后的键。
+//
+// 未配置灰度或未命中灰度时返回基线规则；灰度规则未启用时命中的流量不受该规则限制。
+func (rm *ruleMatcher) resolveRule(ruleName string, key Key) (Rule, *ruleMatcher, string, bool) {
+	rule, found := rm.findRule(ruleName)
+	if !found {
+		return Rule{}, nil, "", false
+	}
+	rendered := key.Render(rule.KeyTemplate)
+
+	canary, ok := rm.canaries[ruleName]
+	if !ok || !canary.selected(key, rendered) {
+		return rule, rm, rendered, true
+	}
+	if !canary.rule.IsEnabled() {
+		return Rule{}, nil, "", false
+	}
+	return canary.rule, canary.matcher, key.Render(canary.rule.KeyTemplate), true
+}
@@ -0,0 +1,198 @@
+package xlimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCanaryRule_Validation(t *testing.T) {
+	base := TenantRule("tenant", 100, time.Minute)
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"negative percentage", []Option{WithRules(base), WithCanaryRule(TenantRule("tenant", 10, time.Minute), -1)}},
+		{"percentage over 100", []Option{WithRules(base), WithCanaryRule(TenantRule("tenant", 10, time.Minute), 101)}},
+		{"NaN percentage", []Option{WithRules(base), WithCanaryRule(TenantRule("tenant", 10, time.Minute), math.NaN())}},
+		{"invalid rule", []Option{WithRules(base), WithCanaryRule(TenantRule("tenant", 0, time.Minute), 10)}},
+		{"no base rule", []Option{WithRules(base), WithCanaryRule(TenantRule("other", 10, time.Minute), 10)}},
+		{"duplicate canary", []Option{
+			WithRules(base),
+			WithCanaryRule(TenantRule("tenant", 10, time.Minute), 10),
+			WithCanaryRule(TenantRule("tenant", 20, time.Minute), 10),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLocal(tt.opts...)
+			assert.ErrorIs(t, err, ErrInvalidRule)
+		})
+	}
+}
+
+func TestWithCanaryRule_FullRollout(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithCanaryRule(TenantRule("tenant", 2, time.Minute), 100),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	ctx := context.Background()
+	key := Key{Tenant: "t1"}
+	for range 2 {
+		res, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, "tenant", res.Rule)
+		assert.Equal(t, 2, res.Limit)
+	}
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed, "canary limit should apply to 100% of traffic")
+}
+
+func TestWithCanaryRule_ZeroPercent(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithCanaryRule(TenantRule("tenant", 1, time.Minute), 0),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	for i := range 50 {
+		res, err := limiter.Allow(context.Background(), Key{Tenant: fmt.Sprintf("t-%d", i)})
+		require.NoError(t, err)
+		assert.Equal(t, 100, res.Limit)
+	}
+}
+
+func TestWithCanaryRule_ConsistentByTenant(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantAPIRule("api", 1000, time.Minute)),
+		WithCanaryRule(TenantAPIRule("api", 500, time.Minute), 30),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+	querier, ok := limiter.(Querier)
+	require.True(t, ok)
+
+	ctx := context.Background()
+	canaryTenants := 0
+	const tenants = 1000
+	for i := range tenants {
+		tenant := fmt.Sprintf("tenant-%d", i)
+		first, err := querier.Query(ctx, Key{Tenant: tenant, Method: "GET", Path: "/a"})
+		require.NoError(t, err)
+		// 同一租户的不同 API 必须落在同一灰度分组
+		second, err := querier.Query(ctx, Key{Tenant: tenant, Method: "POST", Path: "/b"})
+		require.NoError(t, err)
+		assert.Equal(t, first.Limit, second.Limit, "tenant %s split across cohorts", tenant)
+		if first.Limit == 500 {
+			canaryTenants++
+		}
+	}
+	assert.InDelta(t, 0.3, float64(canaryTenants)/tenants, 0.06)
+}
+
+func TestWithCanaryRule_MonotonicRollout(t *testing.T) {
+	newLimiter := func(pct float64) Querier {
+		l, err := NewLocal(
+			WithRules(TenantRule("tenant", 100, time.Minute)),
+			WithCanaryRule(TenantRule("tenant", 10, time.Minute), pct),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close(context.Background()) }) //nolint:errcheck // test cleanup
+		q, ok := l.(Querier)
+		require.True(t, ok)
+		return q
+	}
+	small, large := newLimiter(10), newLimiter(50)
+
+	ctx := context.Background()
+	for i := range 200 {
+		key := Key{Tenant: fmt.Sprintf("t-%d", i)}
+		s, err := small.Query(ctx, key)
+		require.NoError(t, err)
+		l, err := large.Query(ctx, key)
+		require.NoError(t, err)
+		if s.Limit == 10 {
+			assert.Equal(t, 10, l.Limit, "tenant %s left canary when percentage grew", key.Tenant)
+		}
+	}
+}
+
+func TestWithCanaryRule_Overrides(t *testing.T) {
+	canary := NewRuleBuilder("tenant").
+		KeyTemplate("tenant:${tenant_id}").
+		Limit(5).
+		Window(time.Minute).
+		AddOverride("tenant:vip", 50).
+		Build()
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithCanaryRule(canary, 100),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	res, err := limiter.Allow(context.Background(), Key{Tenant: "vip"})
+	require.NoError(t, err)
+	assert.Equal(t, 50, res.Limit)
+
+	res, err = limiter.Allow(context.Background(), Key{Tenant: "normal"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, res.Limit)
+}
+
+func TestWithCanaryRule_DisabledCanaryRule(t *testing.T) {
+	canary := TenantRule("tenant", 1, time.Minute)
+	disabled := false
+	canary.Enabled = &disabled
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 1, time.Minute)),
+		WithCanaryRule(canary, 100),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	// 灰度流量不再受该规则限制
+	for range 3 {
+		res, err := limiter.Allow(context.Background(), Key{Tenant: "t1"})
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+}
+
+func TestWithCanaryRule_Reset(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithCanaryRule(NewRule("tenant", "canary:${tenant_id}", 1, time.Minute), 100),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	ctx := context.Background()
+	key := Key{Tenant: "t1"}
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "canary:t1", res.Key)
+	res, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+
+	resetter, ok := limiter.(Resetter)
+	require.True(t, ok)
+	require.NoError(t, resetter.Reset(ctx, key))
+
+	res, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "Reset should clear the canary counter")
+}
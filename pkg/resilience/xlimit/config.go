@@ -32,6 +32,32 @@ func (s FallbackStrategy) IsValid() bool {
 	}
 }
 
+// Algorithm 限流算法
+type Algorithm string
+
+const (
+	// AlgoTokenBucket 令牌桶（默认）
+	// 每个键只存储一个时间戳，内存恒定；Burst 控制突发容量，
+	// 任意 Window 内实际放行量最多可达 Limit + Burst。
+	AlgoTokenBucket Algorithm = "token_bucket"
+
+	// AlgoSlidingWindow 滑动窗口日志
+	// 精确保证"任意滚动 Window 内最多 Limit 个请求"，不使用 Burst。
+	// 代价是每个放行请求在 Redis ZSET 中占一个成员，单键内存随 Limit 线性增长
+	// （Limit=10000 时约数百 KB），适合配额较小、需要严格语义的接口。
+	AlgoSlidingWindow Algorithm = "sliding_window"
+)
+
+// IsValid 检查限流算法是否有效，空值表示默认的令牌桶
+func (a Algorithm) IsValid() bool {
+	switch a {
+	case AlgoTokenBucket, AlgoSlidingWindow, "":
+		return true
+	default:
+		return false
+	}
+}
+
 // Config 限流器配置
 type Config struct {
 	// KeyPrefix Redis 键前缀，默认为 "ratelimit:"
@@ -43,6 +69,17 @@ type Config struct {
 	// Fallback Redis 不可用时的降级策略
 	Fallback FallbackStrategy `json:"fallback" yaml:"fallback" koanf:"fallback"`
 
+	// Algorithm 限流算法，默认为令牌桶
+	Algorithm Algorithm `json:"algorithm,omitempty" yaml:"algorithm,omitempty" koanf:"algorithm"`
+
+	// Warmup 新 key 的预热时长，0 表示不预热
+	// 预热期内生效配额（Limit 与 Burst）从 10% 线性增长到 100%
+	Warmup time.Duration `json:"warmup,omitempty" yaml:"warmup,omitempty" koanf:"warmup"`
+
+	// ShadowMode 影子模式：规则照常评估，但本应拒绝的请求仍然放行，
+	// 仅记录 xlimit.shadow_denied.total 指标与日志，用于上线前验证规则
+	ShadowMode bool `json:"shadow_mode,omitempty" yaml:"shadow_mode,omitempty" koanf:"shadow_mode"`
+
 	// LocalPodCount 预期 Pod 数量，用于计算本地降级配额
 	// 本地配额 = 分布式配额 / LocalPodCount
 	LocalPodCount int `json:"local_pod_count" yaml:"local_pod_count" koanf:"local_pod_count"`
@@ -60,10 +97,18 @@ func (c Config) Validate() error {
 		return fmt.Errorf("%w: invalid fallback strategy %q", ErrInvalidRule, c.Fallback)
 	}
 
+	if !c.Algorithm.IsValid() {
+		return fmt.Errorf("%w: invalid algorithm %q", ErrInvalidRule, c.Algorithm)
+	}
+
 	if c.LocalPodCount < 0 {
 		return fmt.Errorf("%w: local_pod_count cannot be negative", ErrInvalidRule)
 	}
 
+	if c.Warmup < 0 {
+		return fmt.Errorf("%w: warmup cannot be negative", ErrInvalidRule)
+	}
+
 	seen := make(map[string]int, len(c.Rules))
 	for i, rule := range c.Rules {
 		if err := rule.Validate(); err != nil {
@@ -103,6 +148,9 @@ func (c Config) Clone() Config {
 	clone := Config{
 		KeyPrefix:     c.KeyPrefix,
 		Fallback:      c.Fallback,
+		Algorithm:     c.Algorithm,
+		Warmup:        c.Warmup,
+		ShadowMode:    c.ShadowMode,
 		LocalPodCount: c.LocalPodCount,
 		EnableMetrics: c.EnableMetrics,
 		EnableHeaders: c.EnableHeaders,
@@ -147,8 +195,10 @@ type Rule struct {
 	// Window 限流窗口时长
 	Window time.Duration `json:"window" yaml:"window" koanf:"window"`
 
-	// Burst 突发容量，允许短时间内超过 Limit 的请求数
-	// 如果为 0，则默认等于 Limit
+	// Burst 突发容量（令牌桶容量），与 Limit/Window 决定的补充速率相互独立
+	// 例如 Limit=100、Window=1m、Burst=20：稳态每分钟 100 个，瞬时最多连续放行 20 个
+	// 为 0 表示未设置，默认等于 Limit；显式设置时必须 >= 1
+	// 滑动窗口算法（AlgoSlidingWindow）忽略此字段
 	Burst int `json:"burst,omitempty" yaml:"burst,omitempty" koanf:"burst"`
 
 	// Overrides 覆盖配置，用于特定键的定制化限流
@@ -173,7 +223,7 @@ func (r Rule) Validate() error {
 		return fmt.Errorf("%w: window must be positive", ErrInvalidRule)
 	}
 	if r.Burst < 0 {
-		return fmt.Errorf("%w: burst cannot be negative", ErrInvalidRule)
+		return fmt.Errorf("%w: burst must be >= 1 when set (0 defaults to limit)", ErrInvalidRule)
 	}
 
 	for i, override := range r.Overrides {
@@ -193,8 +243,8 @@ func (r Rule) IsEnabled() bool {
 	return *r.Enabled
 }
 
-// EffectiveBurst 返回有效的突发容量
-// 如果 Burst 为 0，返回 Limit
+// EffectiveBurst 返回有效的突发容量（令牌桶容量）
+// 如果 Burst 为 0（未设置），返回 Limit
 func (r Rule) EffectiveBurst() int {
 	if r.Burst == 0 {
 		return r.Limit
@@ -214,7 +264,7 @@ type Override struct {
 	// Window 覆盖的窗口时长（可选，不设置则使用规则默认值）
 	Window time.Duration `json:"window,omitempty" yaml:"window,omitempty" koanf:"window"`
 
-	// Burst 覆盖的突发容量（可选）
+	// Burst 覆盖的突发容量（可选，0 表示等于覆盖后的 Limit）
 	Burst int `json:"burst,omitempty" yaml:"burst,omitempty" koanf:"burst"`
 }
 
@@ -230,7 +280,7 @@ func (o Override) Validate() error {
 		return fmt.Errorf("%w: window cannot be negative", ErrInvalidRule)
 	}
 	if o.Burst < 0 {
-		return fmt.Errorf("%w: burst cannot be negative", ErrInvalidRule)
+		return fmt.Errorf("%w: burst must be >= 1 when set (0 defaults to limit)", ErrInvalidRule)
 	}
 	return nil
 }
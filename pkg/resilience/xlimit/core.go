@@ -2,6 +2,7 @@ package xlimit
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync/atomic"
@@ -32,9 +33,9 @@ func newLimiterCore(backend Backend, matcher *ruleMatcher, opts *options) *limit
 	}
 }
 
-// Allow 检查是否允许单个请求通过
+// Allow 检查是否允许单个请求通过，配置了 WithCostFunc 时按请求成本扣减
 func (c *limiterCore) Allow(ctx context.Context, key Key) (*Result, error) {
-	return c.AllowN(ctx, key, 1)
+	return c.AllowN(ctx, key, c.opts.cost(ctx))
 }
 
 // AllowN 检查是否允许 n 个请求通过
@@ -48,7 +49,28 @@ func (c *limiterCore) AllowN(ctx context.Context, key Key, n int) (*Result, erro
 	if n <= 0 {
 		return nil, fmt.Errorf("%w: must be positive, got %d", ErrInvalidN, n)
 	}
+	return c.allowN(ctx, key, float64(n), nil)
+}
 
+// Reserve 预留 n 个配额，返回可通过 Cancel 归还配额的 Reservation
+//
+// 与 AllowN 共享同一流程（可观测性、回调），额外记录每条已扣减配额的规则，
+// 供 Cancel 逐条归还。
+func (c *limiterCore) Reserve(ctx context.Context, key Key, n int) (*Reservation, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: must be positive, got %d", ErrInvalidN, n)
+	}
+	var consumed []consumedRule
+	result, err := c.allowN(ctx, key, float64(n), &consumed)
+	if err != nil {
+		return nil, err
+	}
+	return newReservation(result, c.refundFunc(consumed, n)), nil
+}
+
+// allowN AllowN、AllowCost 与 Reserve 的公共实现，cost 由调用方校验为正数
+// consumed 非 nil 时记录已扣减配额的规则
+func (c *limiterCore) allowN(ctx context.Context, key Key, cost float64, consumed *[]consumedRule) (*Result, error) {
 	if c.closed.Load() {
 		return nil, ErrLimiterClosed
 	}
@@ -63,7 +85,7 @@ func (c *limiterCore) AllowN(ctx context.Context, key Key, n int) (*Result, erro
 		Kind:      xmetrics.KindInternal,
 		Attrs: []xmetrics.Attr{
 			xmetrics.String("limiter.type", limiterType),
-			xmetrics.Int("request.count", n),
+			costAttr(cost),
 		},
 	})
 
@@ -91,13 +113,16 @@ func (c *limiterCore) AllowN(ctx context.Context, key Key, n int) (*Result, erro
 	// 设计决策: 遍历所有规则，跟踪 Remaining 最小的结果（mostRestrictive）返回给调用方。
 	// 与 Query 方法返回"最受限规则"的语义保持一致，确保 HTTP 头 X-RateLimit-Remaining
 	// 反映真实的最小剩余配额，避免误导客户端。
-	lastResult, err = c.evaluateRules(ctx, key, n)
+	lastResult, err = c.evaluateRules(ctx, key, cost, consumed)
 	if err != nil {
 		return nil, err
 	}
 	if lastResult != nil && !lastResult.Allowed {
-		c.callOnDeny(ctx, key, lastResult)
-		return lastResult, nil
+		if !c.opts.config.ShadowMode {
+			c.callOnDeny(ctx, key, lastResult)
+			return lastResult, nil
+		}
+		lastResult = c.shadowAllow(ctx, limiterType, lastResult)
 	}
 
 	// 设计决策: 无匹配规则时默认放行（fail-open）。
@@ -115,16 +140,17 @@ func (c *limiterCore) AllowN(ctx context.Context, key Key, n int) (*Result, erro
 // 若某条规则拒绝请求，立即返回该拒绝结果；
 // 若所有规则通过，返回 Remaining 最小的结果；
 // 若无匹配规则，返回 (nil, nil)。
-func (c *limiterCore) evaluateRules(ctx context.Context, key Key, n int) (*Result, error) {
+// consumed 非 nil 时追加每条放行（已扣减配额）的规则。
+func (c *limiterCore) evaluateRules(ctx context.Context, key Key, cost float64, consumed *[]consumedRule) (*Result, error) {
 	var mostRestrictive *Result
 
 	for _, ruleName := range c.matcher.getAllRules() {
-		rule, found := c.matcher.findRule(ruleName)
+		rule, matcher, rendered, found := c.matcher.resolveRule(ruleName, key)
 		if !found {
 			continue
 		}
 
-		result, err := c.checkRule(ctx, key, rule, n)
+		result, err := c.checkRule(ctx, matcher, rule, rendered, cost, consumed)
 		if err != nil {
 			return nil, err
 		}
@@ -143,18 +169,27 @@ func (c *limiterCore) evaluateRules(ctx context.Context, key Key, n int) (*Resul
 
 // checkRule 检查单个规则
 //
-// 设计决策: 在入口处调用一次 key.Render，将结果传递给 getEffectiveLimit、
+// 设计决策: 由 resolveRule 调用一次 key.Render，将结果传递给 getEffectiveLimit、
 // getEffectiveBurst 和 renderKey，避免热路径上 3 次重复的模板解析和字符串分配。
-func (c *limiterCore) checkRule(ctx context.Context, key Key, rule Rule, n int) (*Result, error) {
-	rendered := key.Render(rule.KeyTemplate)
-	limit, window := c.matcher.getEffectiveLimit(rule, rendered)
-	burst := c.matcher.getEffectiveBurst(rule, rendered)
-	fullKey := c.matcher.renderKey(rendered, c.opts.config.KeyPrefix)
+// matcher 为规则所属的匹配器（灰度规则使用自身的匹配器计算 Override）。
+// consumed 非 nil 且规则放行时，记录归还配额所需的参数。
+func (c *limiterCore) checkRule(ctx context.Context, matcher *ruleMatcher, rule Rule, rendered string, cost float64,
+	consumed *[]consumedRule) (*Result, error) {
+	limit, window := matcher.getEffectiveLimit(rule, rendered)
+	burst := matcher.getEffectiveBurst(rule, rendered)
+	fullKey := matcher.renderKey(rendered, c.opts.config.KeyPrefix)
+	limit, burst, err := c.applyWarmup(ctx, fullKey, limit, burst, window)
+	if err != nil {
+		return nil, err
+	}
 
-	res, err := c.backend.CheckRule(ctx, fullKey, limit, burst, window, n)
+	res, err := c.checkBackend(ctx, fullKey, limit, burst, window, cost)
 	if err != nil {
 		return nil, err
 	}
+	if res.Allowed && consumed != nil {
+		*consumed = append(*consumed, consumedRule{key: fullKey, limit: limit, burst: burst, window: window, grant: res.grant})
+	}
 
 	return &Result{
 		Allowed:    res.Allowed,
@@ -167,26 +202,66 @@ func (c *limiterCore) checkRule(ctx context.Context, key Key, rule Rule, n int)
 	}, nil
 }
 
+// Wait 阻塞直到允许单个请求通过或 ctx 结束，配置了 WithCostFunc 时按请求成本等待
+func (c *limiterCore) Wait(ctx context.Context, key Key) error {
+	return c.WaitN(ctx, key, c.opts.cost(ctx))
+}
+
+// WaitN 阻塞直到允许 n 个请求通过或 ctx 结束
+func (c *limiterCore) WaitN(ctx context.Context, key Key, n int) error {
+	return waitN(ctx, c.AllowN, key, n)
+}
+
+// refundFunc 返回归还 consumed 中各规则 n 个配额的函数，无可归还规则时返回 nil
+//
+// 设计决策: 逐条归还并汇总错误，单条失败不影响其他规则归还。
+// 后端未实现 refunder 时 Cancel 为空操作（所有内置后端均已实现）。
+func (c *limiterCore) refundFunc(consumed []consumedRule, n int) func(context.Context) error {
+	r, ok := c.backend.(refunder)
+	if !ok || len(consumed) == 0 {
+		return nil
+	}
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, cr := range consumed {
+			if err := r.Refund(ctx, cr, n); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
 // Reset 重置指定键的限流计数
+//
+// 设计决策: 逐条重置所有匹配规则并汇总错误，单条失败不影响其他层级，
+// 事故处理时尽可能多地解除限流；无匹配规则返回 ErrNoRuleMatched，避免误以为已重置。
 func (c *limiterCore) Reset(ctx context.Context, key Key) error {
 	if c.closed.Load() {
 		return ErrLimiterClosed
 	}
 
+	var (
+		matched bool
+		errs    []error
+	)
 	for _, ruleName := range c.matcher.getAllRules() {
-		rule, found := c.matcher.findRule(ruleName)
+		_, matcher, rendered, found := c.matcher.resolveRule(ruleName, key)
 		if !found {
 			continue
 		}
+		matched = true
 
-		rendered := key.Render(rule.KeyTemplate)
-		fullKey := c.matcher.renderKey(rendered, c.opts.config.KeyPrefix)
+		fullKey := matcher.renderKey(rendered, c.opts.config.KeyPrefix)
 		if err := c.backend.Reset(ctx, fullKey); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("reset rule %q: %w", ruleName, err))
 		}
 	}
 
-	return nil
+	if !matched {
+		return ErrNoRuleMatched
+	}
+	return errors.Join(errs...)
 }
 
 // Query 查询当前配额状态（不消耗配额）
@@ -201,30 +276,34 @@ func (c *limiterCore) Query(ctx context.Context, key Key) (*QuotaInfo, error) {
 	var mostRestrictive *QuotaInfo
 
 	for _, ruleName := range c.matcher.getAllRules() {
-		rule, found := c.matcher.findRule(ruleName)
+		rule, matcher, rendered, found := c.matcher.resolveRule(ruleName, key)
 		if !found {
 			continue
 		}
 
-		rendered := key.Render(rule.KeyTemplate)
-		limit, window := c.matcher.getEffectiveLimit(rule, rendered)
-		burst := c.matcher.getEffectiveBurst(rule, rendered)
-		fullKey := c.matcher.renderKey(rendered, c.opts.config.KeyPrefix)
+		limit, window := matcher.getEffectiveLimit(rule, rendered)
+		burst := matcher.getEffectiveBurst(rule, rendered)
+		fullKey := matcher.renderKey(rendered, c.opts.config.KeyPrefix)
+		limit, burst, err := c.peekWarmup(ctx, fullKey, limit, burst, window)
+		if err != nil {
+			return nil, err
+		}
 
-		effectiveLimit, remaining, resetAt, err := c.backend.Query(ctx, fullKey, limit, burst, window)
+		res, err := c.backend.Query(ctx, fullKey, limit, burst, window)
 		if err != nil {
 			return nil, err
 		}
 
 		info := &QuotaInfo{
-			Limit:     effectiveLimit,
-			Remaining: remaining,
-			ResetAt:   resetAt,
+			Limit:     res.Limit,
+			Burst:     res.Burst,
+			Remaining: res.Remaining,
+			ResetAt:   res.ResetAt,
 			Rule:      rule.Name,
 			Key:       rendered,
 		}
 
-		if mostRestrictive == nil || remaining < mostRestrictive.Remaining {
+		if mostRestrictive == nil || info.Remaining < mostRestrictive.Remaining {
 			mostRestrictive = info
 		}
 	}
@@ -258,6 +337,29 @@ func (c *limiterCore) callOnAllow(ctx context.Context, key Key, result *Result)
 	}
 }
 
+// shadowAllow 影子模式下将拒绝结果转为放行，记录指标与日志
+//
+// 设计决策: 放行结果仍由 callOnAllow 通知，调用方看到的决策与返回值一致；
+// 原拒绝信息保留在 Rule/Key/Remaining 中，RetryAfter 清零避免中间件输出 Retry-After。
+// 与正常拒绝相同，首条拒绝的规则之后的层级不再评估。
+func (c *limiterCore) shadowAllow(ctx context.Context, limiterType string, result *Result) *Result {
+	c.opts.metrics.RecordShadowDeny(ctx, limiterType, result.Rule)
+	if c.opts.logger != nil {
+		c.opts.logger.Warn(ctx, "rate limit would be exceeded (shadow mode)",
+			slog.String("limiter_type", limiterType),
+			slog.String("rule", result.Rule),
+			slog.String("key", result.Key),
+			slog.Int("limit", result.Limit),
+			slog.Duration("retry_after", result.RetryAfter),
+		)
+	}
+	shadow := *result
+	shadow.Allowed = true
+	shadow.ShadowDenied = true
+	shadow.RetryAfter = 0
+	return &shadow
+}
+
 // callOnDeny 调用拒绝回调并记录日志
 func (c *limiterCore) callOnDeny(ctx context.Context, key Key, result *Result) {
 	if c.opts.onDeny != nil {
@@ -280,4 +382,6 @@ var (
 	_ Limiter  = (*limiterCore)(nil)
 	_ Querier  = (*limiterCore)(nil)
 	_ Resetter = (*limiterCore)(nil)
+	_ Reserver = (*limiterCore)(nil)
+	_ Waiter   = (*limiterCore)(nil)
 )
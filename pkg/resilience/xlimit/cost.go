@@ -0,0 +1,155 @@
+package xlimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis_rate/v10"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+// costScale 小数成本的定点精度：成本按 1/costScale 向上取整
+const costScale = 1000
+
+// costEpsilon 定点换算时吸收浮点表示误差（如 0.1*1000 = 100.00000000000001）
+const costEpsilon = 1e-6
+
+// maxCost 单次请求允许的最大成本，避免定点换算溢出
+const maxCost = math.MaxInt32
+
+// costChecker 支持小数成本的后端（可选能力）
+// 未实现时小数成本向上取整为整数后调用 CheckRule
+type costChecker interface {
+	// CheckRuleCost 与 CheckRule 语义一致，cost 已按 costScale 量化且不为整数
+	CheckRuleCost(ctx context.Context, key string, limit, burst int, window time.Duration, cost float64) (CheckResult, error)
+}
+
+// validateCost 校验成本并按 costScale 向上量化
+func validateCost(cost float64) (float64, error) {
+	if math.IsNaN(cost) || cost <= 0 || cost > maxCost {
+		return 0, fmt.Errorf("%w: cost must be in (0, %d], got %v", ErrInvalidN, maxCost, cost)
+	}
+	return costUnits(cost) / costScale, nil
+}
+
+// costUnits 返回 cost 对应的定点单位数（向上取整）
+func costUnits(cost float64) float64 {
+	return math.Ceil(cost*costScale - costEpsilon)
+}
+
+// wholeCost 成本为整数时返回对应的 n
+func wholeCost(cost float64) (int, bool) {
+	if cost != math.Trunc(cost) {
+		return 0, false
+	}
+	return int(cost), true
+}
+
+// costAttr 返回 span 上的请求量属性：整数成本沿用 request.count，小数成本使用 request.cost
+func costAttr(cost float64) xmetrics.Attr {
+	if n, ok := wholeCost(cost); ok {
+		return xmetrics.Int("request.count", n)
+	}
+	return xmetrics.Float64("request.cost", cost)
+}
+
+// AllowCost 检查是否允许成本为 cost 的请求通过，实现 CostLimiter
+func (c *limiterCore) AllowCost(ctx context.Context, key Key, cost float64) (*Result, error) {
+	cost, err := validateCost(cost)
+	if err != nil {
+		return nil, err
+	}
+	return c.allowN(ctx, key, cost, nil)
+}
+
+// checkBackend 按成本调用后端：整数成本走 CheckRule，小数成本优先使用 costChecker
+func (c *limiterCore) checkBackend(ctx context.Context, key string, limit, burst int, window time.Duration, cost float64) (CheckResult, error) {
+	if n, ok := wholeCost(cost); ok {
+		return c.backend.CheckRule(ctx, key, limit, burst, window, n)
+	}
+	if cc, ok := c.backend.(costChecker); ok {
+		return cc.CheckRuleCost(ctx, key, limit, burst, window, cost)
+	}
+	return c.backend.CheckRule(ctx, key, limit, burst, window, int(math.Ceil(cost)))
+}
+
+// AllowCost 检查是否允许成本为 cost 的请求通过，Redis 不可用时按降级策略处理
+//
+// 自定义降级函数只接受整数 n，小数成本向上取整后传入。
+func (f *fallbackLimiter) AllowCost(ctx context.Context, key Key, cost float64) (*Result, error) {
+	cost, err := validateCost(cost)
+	if err != nil {
+		return nil, err
+	}
+	return f.allow(ctx, key, int(math.Ceil(cost)), func(l Limiter) (*Result, error) {
+		return allowCost(ctx, l, key, cost)
+	})
+}
+
+// allowCost 对 l 执行 AllowCost；l 不支持时小数成本向上取整后调用 AllowN
+func allowCost(ctx context.Context, l Limiter, key Key, cost float64) (*Result, error) {
+	if cl, ok := l.(CostLimiter); ok {
+		return cl.AllowCost(ctx, key, cost)
+	}
+	return l.AllowN(ctx, key, int(math.Ceil(cost)))
+}
+
+// =============================================================================
+// Redis 实现
+// =============================================================================
+
+// CheckRuleCost 实现 costChecker
+//
+// 设计决策: 令牌桶将 Rate、Burst 与成本同时放大 costScale 倍后以整数调用 redis_rate。
+// GCRA 的状态是理论到达时间（TAT），每单位成本推进 Period/Rate，三者同比放大后
+// 时间增量不变，因此与整数 AllowN 共享同一个键且互相兼容；脚本内只做整数成本运算，
+// 不会因反复累加小数令牌产生漂移。滑动窗口按请求计数，成本向上取整。
+func (b *redisBackend) CheckRuleCost(ctx context.Context, key string, limit, burst int, window time.Duration, cost float64) (CheckResult, error) {
+	if b.algorithm == AlgoSlidingWindow {
+		return b.slidingWindowCheck(ctx, key, limit, window, int(math.Ceil(cost)))
+	}
+
+	rateLimit := redis_rate.Limit{
+		Rate:   limit * costScale,
+		Burst:  burst * costScale,
+		Period: window,
+	}
+	res, err := b.limiter.AllowN(ctx, key, rateLimit, int(costUnits(cost)))
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	retryAfter := res.RetryAfter
+	if res.Allowed == 0 && cost > float64(burst) {
+		retryAfter = -1
+	}
+
+	return CheckResult{
+		Allowed:    res.Allowed > 0,
+		Limit:      limit,
+		Burst:      burst,
+		Remaining:  res.Remaining / costScale,
+		ResetAt:    time.Now().Add(res.ResetAfter),
+		RetryAfter: retryAfter,
+	}, nil
+}
+
+// =============================================================================
+// 本地实现
+// =============================================================================
+
+// CheckRuleCost 实现 costChecker，令牌桶以浮点令牌数直接扣减小数成本
+func (b *localBackend) CheckRuleCost(ctx context.Context, key string, limit, burst int, window time.Duration, cost float64) (CheckResult, error) {
+	return b.check(ctx, key, limit, burst, window, cost)
+}
+
+// 确保内置实现满足小数成本相关接口
+var (
+	_ costChecker = (*redisBackend)(nil)
+	_ costChecker = (*localBackend)(nil)
+	_ CostLimiter = (*limiterCore)(nil)
+	_ CostLimiter = (*fallbackLimiter)(nil)
+)
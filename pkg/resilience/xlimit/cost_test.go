@@ -0,0 +1,173 @@
+package xlimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCost(t *testing.T) {
+	tests := []struct {
+		name string
+		cost float64
+		want float64
+	}{
+		{"whole", 3, 3},
+		{"fraction", 0.25, 0.25},
+		{"float representation", 0.1, 0.1},
+		{"rounds up", 0.0001, 0.001},
+		{"rounds up to precision", 1.0005, 1.001},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := validateCost(tt.cost)
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-12)
+		})
+	}
+
+	for _, cost := range []float64{0, -1, math.NaN(), math.Inf(1), math.MaxInt32 + 1} {
+		_, err := validateCost(cost)
+		assert.ErrorIs(t, err, ErrInvalidN, "cost %v", cost)
+	}
+}
+
+func TestAllowCost_Fractional(t *testing.T) {
+	_, client := setupMiniredis(t)
+	opts := []Option{WithRules(TenantRule("tenant", 10, time.Hour))}
+	distributed, err := New(client, append(opts, WithFallback(""))...)
+	require.NoError(t, err)
+	local, err := NewLocal(opts...)
+	require.NoError(t, err)
+
+	for name, limiter := range map[string]Limiter{"redis": distributed, "local": local} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := Key{Tenant: "acme"}
+			cl, ok := limiter.(CostLimiter)
+			require.True(t, ok)
+
+			res, err := cl.AllowCost(ctx, key, 2.5)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 7, res.Remaining, "7.5 left, reported rounded down")
+
+			// 与整数 AllowN 共享同一份配额
+			res, err = limiter.AllowN(ctx, key, 7)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+
+			info, err := limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 0, info.Remaining)
+
+			// 剩余 0.5：报告为 0，但不超过 0.5 的成本仍可放行
+			res, err = cl.AllowCost(ctx, key, 0.5)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+
+			res, err = cl.AllowCost(ctx, key, 0.5)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Positive(t, res.RetryAfter)
+
+			res, err = cl.AllowCost(ctx, Key{Tenant: "other"}, 10.5)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed)
+			assert.Equal(t, time.Duration(-1), res.RetryAfter, "cost exceeds burst")
+
+			_, err = cl.AllowCost(ctx, key, 0)
+			assert.ErrorIs(t, err, ErrInvalidN)
+		})
+	}
+}
+
+func TestAllowCost_NoDriftOnRepeatedFractions(t *testing.T) {
+	_, client := setupMiniredis(t)
+	limiter, err := New(client, WithRules(TenantRule("tenant", 1, time.Hour)), WithFallback(""))
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	// 0.1 无法用二进制浮点精确表示，十次累加后仍应恰好耗尽 1 个配额
+	for i := range 10 {
+		res, err := limiter.(CostLimiter).AllowCost(ctx, key, 0.1)
+		require.NoError(t, err)
+		require.True(t, res.Allowed, "request %d", i)
+	}
+	res, err := limiter.(CostLimiter).AllowCost(ctx, key, 0.1)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
+
+func TestTokenBucket_NoDriftOnRepeatedFractions(t *testing.T) {
+	tests := []struct {
+		burst int
+		cost  float64
+		want  int
+	}{
+		{3, 0.6, 5},
+		{2, 0.1, 20},
+		{1, 0.3, 3},
+		{10, 0.7, 14},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("burst=%d/cost=%v", tt.burst, tt.cost), func(t *testing.T) {
+			// limit 为 0 时不补令牌，等价于冻结时钟
+			tb := &tokenBucket{tokens: float64(tt.burst), burst: tt.burst, window: time.Hour, lastUpdate: time.Now()}
+			allowed := 0
+			for range tt.want + 1 {
+				if ok, _, _ := tb.takeWithParams(0, tt.burst, time.Hour, tt.cost); ok {
+					allowed++
+				}
+			}
+			assert.Equal(t, tt.want, allowed)
+			assert.GreaterOrEqual(t, tb.tokens, 0.0)
+		})
+	}
+}
+
+func TestTokenBucket_RemainingAbsorbsFloatError(t *testing.T) {
+	tb := &tokenBucket{tokens: 3, burst: 3, window: time.Hour, lastUpdate: time.Now()}
+	_, remaining, _ := tb.takeWithParams(0, 3, time.Hour, 0.6)
+	assert.Equal(t, 2, remaining)
+	for range 4 {
+		_, remaining, _ = tb.takeWithParams(0, 3, time.Hour, 0.1)
+	}
+	assert.Equal(t, 2, remaining, "3 - 0.6 - 0.4 is 2 despite float error")
+	assert.Equal(t, 2, tb.currentTokens(0, 3, time.Hour))
+}
+
+func TestAllowCost_SlidingWindowRoundsUp(t *testing.T) {
+	_, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 3, time.Minute)),
+		WithAlgorithm(AlgoSlidingWindow),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	res, err := limiter.(CostLimiter).AllowCost(ctx, Key{Tenant: "acme"}, 1.2)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining, "1.2 counts as 2 requests")
+}
+
+func TestAllowCost_FallbackLocal(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client, WithRules(TenantRule("tenant", 10, time.Hour)), WithFallback(FallbackLocal))
+	require.NoError(t, err)
+	mr.Close()
+
+	res, err := limiter.(CostLimiter).AllowCost(context.Background(), Key{Tenant: "acme"}, 2.5)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 7, res.Remaining)
+}
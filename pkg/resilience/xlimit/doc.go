@@ -22,11 +22,136 @@
 //   - API（Method + Path）：按接口限流
 //   - 资源（Resource）：按自定义资源名限流
 //
+// # 与 xtenant 集成
+//
+// NewTenantLimiter 将 Limiter 适配为 xtenant.TenantLimiter，配合
+// xtenant.WithTenantLimiter / xtenant.WithGRPCTenantLimiter 使用时，
+// 租户中间件在提取并校验租户信息后自动以 TenantID 限流，无需业务手动构建 Key。
+//
 // # 层级限流
 //
 // 支持层级限流策略（串行检查，任一层级拒绝则拒绝）：
 //   - 全局限流 → 租户限流 → API 限流
 //
+// # 突发容量
+//
+// Rule.Burst 设置令牌桶容量，与 Limit/Window 决定的补充速率相互独立：
+//
+//	xlimit.NewRuleBuilder("tenant").
+//	    KeyTemplate("tenant:${tenant_id}").
+//	    Limit(100).Window(time.Minute). // 稳态每分钟 100 个
+//	    Burst(20).                      // 瞬时最多连续放行 20 个
+//	    Build()
+//
+// Burst 为 0 时等于 Limit；显式设置时必须 >= 1。Query 返回的 QuotaInfo.Burst 为生效的容量，
+// Remaining 为当前可立即放行的请求数（不超过 Burst）。n 超过 Burst 的 AllowN 永远无法满足，
+// 返回 RetryAfter 为 -1。本地限流按 PodCount 分摊 Limit 与 Burst。
+//
+// 层级限流时每条规则各自维护独立的令牌桶，一次请求需所有层级放行，
+// 因此实际突发上限为各层级剩余容量的最小值：全局规则的 Burst 小于租户规则时，
+// 租户的突发会先被全局容量截断。各层级的 Burst 应自上而下不增。
+//
+// # 按成本限流
+//
+// AllowN 的 n 即请求成本，在每条层级规则上扣减相同数量。WithCostFunc 让 Allow/Wait
+// （包括中间件）按请求动态计算成本，使限流更贴近实际资源消耗：
+//
+//	limiter, err := xlimit.New(rdb,
+//	    xlimit.WithRules(xlimit.TenantRule("tenant", 1000, time.Minute)),
+//	    xlimit.WithCostFunc(func(ctx context.Context) int { return queryCost(ctx) }),
+//	)
+//
+// 成本小于 1 时按 1 处理。成本超过某层级 Burst 的请求永远无法放行，规则容量应覆盖最大成本。
+//
+// 成本无法用整数表达时使用 CostLimiter.AllowCost：
+//
+//	result, err := limiter.(xlimit.CostLimiter).AllowCost(ctx, key, 2.5)
+//
+// 小数成本按 0.001 精度向上取整。Redis 令牌桶以定点整数参与运算，本地令牌桶比较时吸收浮点误差，
+// 反复扣减小数不会因误差少放行（如 Burst 为 3 时恰好放行 5 次成本 0.6 的请求）；
+// Redis 令牌桶与整数 AllowN 共享同一个键。滑动窗口按请求计数，成本向上取整为整数。
+// Result.Remaining 与 QuotaInfo.Remaining 为剩余配额向下取整。
+//
+// # 预热
+//
+// WithWarmup 让新出现的限流键从保守配额起步，避免冷启动的下游缓存被首批突发打满：
+//
+//	limiter, err := xlimit.New(rdb,
+//	    xlimit.WithRules(xlimit.TenantRule("tenant", 1000, time.Minute)),
+//	    xlimit.WithWarmup(10*time.Minute),
+//	)
+//
+// 键首次出现后，生效的 Limit 与 Burst 在预热期内从 10% 线性增长到 100%，
+// Result.Limit 与 QuotaInfo.Limit 反映当前生效值。首见时间存储在 Redis 中（warmup: 前缀，
+// 使用 Redis 服务器时间），各 Pod 共享进度；键空闲超过 Warmup + Window 后记录过期，
+// 再次出现时重新预热。每次检查每条规则额外一次 Redis 调用。
+// Query 只读取首见记录，不会启动或延续预热计时；无记录的键按尚未开始预热（10%）返回。
+//
+// 降级到本地限流时，本地首见时间独立记录，预热从 10% 重新开始；
+// Redis 恢复后继续使用 Redis 中的进度。
+//
+// # 影子模式
+//
+// WithShadowMode(true)（或 Config.ShadowMode）用于在生产流量上验证新规则：
+// 规则照常评估并扣减配额，本应拒绝的请求仍返回 Allowed=true，并带有 Result.ShadowDenied，
+// 同时记录 xlimit.shadow_denied.total 指标和 Warn 日志，不触发 OnDeny 回调。
+// HTTP/gRPC 中间件在影子模式下放行所有请求。确认拒绝量符合预期后关闭影子模式即开始强制执行。
+// Redis 不可用时的 FallbackClose 拒绝不属于规则决策，不受影子模式影响。
+//
+// # 限流算法
+//
+// WithAlgorithm 选择限流算法：
+//   - AlgoTokenBucket（默认）：GCRA 令牌桶，每个限流键只占一个 Redis 键，允许 Burst 突发
+//   - AlgoSlidingWindow：滑动窗口日志，任意连续窗口内放行数严格不超过 Limit，
+//     RetryAfter 精确到最早一批请求滑出窗口的时刻；Burst 被忽略
+//
+// 滑动窗口的内存与窗口内放行的请求数成正比（Redis 中每个请求一个 ZSET 成员），
+// Limit 较大（如每分钟数万）时应优先使用令牌桶。降级到本地限流时沿用同一算法。
+//
+// # 配额预留
+//
+// Reserver.Reserve 与 AllowN 语义一致，额外返回 Reservation。
+// 批量任务中止或部分失败时调用 Cancel 归还 n 个配额：
+//
+//	res, err := limiter.(xlimit.Reserver).Reserve(ctx, key, len(batch))
+//	if err != nil || !res.OK() { ... }
+//	if err := process(batch); err != nil {
+//	    _ = res.Cancel(ctx)
+//	}
+//
+// Cancel 幂等，令牌桶归还后不超过 Burst；滑动窗口只移除本次预留写入的记录，
+// 记录已滑出窗口时不做任何操作。
+// 多规则时拒绝结果中前序规则已扣减的配额也会被 Cancel 归还。
+//
+// # 阻塞等待
+//
+// Waiter.Wait/WaitN 阻塞直到配额可用，适用于客户端出站调用节流：
+//
+//	if err := limiter.(xlimit.Waiter).Wait(ctx, key); err != nil {
+//	    return err // ctx 结束、ErrWaitExceedsDeadline 或 ErrInvalidN
+//	}
+//
+// 休眠时长取自 Result.RetryAfter，不忙等。RetryAfter 超过 ctx 截止时间时立即返回
+// ErrWaitExceedsDeadline；n 超过规则容量时返回 ErrInvalidN。
+// 带降级的限流器在 Redis 故障时按降级策略在本地等待。
+//
+// # 配额重置
+//
+// Resetter.Reset 删除 key 匹配的所有层级规则的限流计数，用于事故处理时解除误限流：
+//
+//	err := limiter.(xlimit.Resetter).Reset(ctx, xlimit.Key{Tenant: "acme"})
+//
+// 无模板变量的全局规则同样匹配，其计数被所有键共享，会一并清空。
+// Reset 是管理操作的逃生通道：通过 HTTP 暴露时必须经过鉴权，且不应在业务路径上调用。
+// 带降级的限流器同时重置 Redis 和本地计数，Redis 不可用时只重置本地。
+//
+// # 响应头
+//
+// HTTPMiddleware 默认写入 X-RateLimit-Limit / X-RateLimit-Remaining / X-RateLimit-Reset，
+// 被限流时写入 Retry-After（秒），可通过 WithMiddlewareHeaders(false) 关闭。
+// WithRateLimitHeaders(true) 额外写入 IETF 草案的 RateLimit-Limit / RateLimit-Remaining /
+// RateLimit-Reset（距重置的秒数），便于遵循草案的客户端自行退避。
+//
 // # 降级策略
 //
 // Redis 故障时支持三种降级策略：
@@ -40,6 +165,19 @@
 //
 // 本地降级时支持动态获取 Pod 数量。
 //
+// # 规则灰度
+//
+// WithCanaryRule 对部分流量灰度应用新规则，降低调整配额的风险：
+//
+//	limiter, err := xlimit.New(rdb,
+//	    xlimit.WithRules(xlimit.TenantRule("tenant", 1000, time.Minute)),
+//	    xlimit.WithCanaryRule(xlimit.TenantRule("tenant", 500, time.Minute), 10),
+//	)
+//
+// 灰度规则与基线规则同名，命中灰度的请求使用灰度规则替换基线规则。
+// 分组复用 xsampling 的一致性采样：按租户（无租户时按限流键）分组，
+// 各 Pod 结论一致，调大比例时已命中的分组保持命中。
+//
 // # 配置管理
 //
 // 支持通过 WithConfigProvider 从 xconf 加载配置。
@@ -62,6 +200,7 @@
 //   - xlimit.requests.total：请求总数 (Counter)
 //   - xlimit.denied.total：被拒绝请求数 (Counter)
 //   - xlimit.fallback.total：降级次数 (Counter)
+//   - xlimit.shadow_denied.total：影子模式下本应拒绝的请求数 (Counter)
 //   - xlimit.check.duration：检查延迟 (Histogram)
 //
 // # 已知限制
@@ -38,6 +38,12 @@ var (
 	// ErrQueryNotSupported 表示限流器不支持配额查询
 	ErrQueryNotSupported = errors.New("xlimit: query not supported")
 
+	// ErrReserveNotSupported 表示限流器不支持配额预留
+	ErrReserveNotSupported = errors.New("xlimit: reserve not supported")
+
+	// ErrWaitExceedsDeadline 表示等待配额所需时间超过 context 截止时间
+	ErrWaitExceedsDeadline = errors.New("xlimit: wait would exceed context deadline")
+
 	// ErrInvalidN 表示请求数量参数无效（必须为正整数）
 	ErrInvalidN = errors.New("xlimit: invalid request count")
 
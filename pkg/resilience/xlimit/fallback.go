@@ -27,14 +27,23 @@ func newFallbackLimiter(distributed, local Limiter, opts *options) *fallbackLimi
 	}
 }
 
-// Allow 检查是否允许单个请求通过
+// Allow 检查是否允许单个请求通过，配置了 WithCostFunc 时按请求成本扣减
 func (f *fallbackLimiter) Allow(ctx context.Context, key Key) (*Result, error) {
-	return f.AllowN(ctx, key, 1)
+	return f.AllowN(ctx, key, f.opts.cost(ctx))
 }
 
 // AllowN 检查是否允许 n 个请求通过
 func (f *fallbackLimiter) AllowN(ctx context.Context, key Key, n int) (*Result, error) {
-	result, err := f.distributed.AllowN(ctx, key, n)
+	return f.allow(ctx, key, n, func(l Limiter) (*Result, error) {
+		return l.AllowN(ctx, key, n)
+	})
+}
+
+// allow AllowN 与 AllowCost 的公共实现：check 先作用于分布式限流器，
+// Redis 不可用时按降级策略处理，本地降级时作用于本地限流器。
+// n 为传给自定义降级函数的请求数。
+func (f *fallbackLimiter) allow(ctx context.Context, key Key, n int, check func(Limiter) (*Result, error)) (*Result, error) {
+	result, err := check(f.distributed)
 	if err == nil {
 		return result, nil
 	}
@@ -44,7 +53,66 @@ func (f *fallbackLimiter) AllowN(ctx context.Context, key Key, n int) (*Result,
 		return nil, err
 	}
 
-	// 记录降级日志和指标
+	f.onFallback(ctx, key, err)
+
+	// 优先使用自定义降级函数
+	if f.customFallback != nil {
+		return f.customFallback(ctx, key, n, err)
+	}
+
+	// 执行默认降级策略
+	return f.fallback(check)
+}
+
+// Wait 阻塞直到允许单个请求通过或 ctx 结束，配置了 WithCostFunc 时按请求成本等待
+func (f *fallbackLimiter) Wait(ctx context.Context, key Key) error {
+	return f.WaitN(ctx, key, f.opts.cost(ctx))
+}
+
+// WaitN 阻塞直到允许 n 个请求通过或 ctx 结束
+//
+// 每次尝试都经过 AllowN 的降级逻辑：Redis 不可用时按降级策略在本地等待，
+// Redis 恢复后自动回到分布式配额。FallbackClose 下返回 ErrRedisUnavailable。
+func (f *fallbackLimiter) WaitN(ctx context.Context, key Key, n int) error {
+	return waitN(ctx, f.AllowN, key, n)
+}
+
+// Reserve 预留 n 个配额，Redis 不可用时按降级策略处理
+//
+// 设计决策: 仅 FallbackLocal 返回可归还的预留（归还到本地限流器）；
+// FallbackOpen/FallbackClose 与自定义降级函数未扣减任何配额，Cancel 为空操作。
+// Redis 恢复后，降级期间预留的 Cancel 仍只归还到本地，不影响分布式配额。
+func (f *fallbackLimiter) Reserve(ctx context.Context, key Key, n int) (*Reservation, error) {
+	r, ok := f.distributed.(Reserver)
+	if !ok {
+		return nil, ErrReserveNotSupported
+	}
+	res, err := r.Reserve(ctx, key, n)
+	if err == nil {
+		return res, nil
+	}
+	if !IsRedisError(err) {
+		return nil, err
+	}
+
+	f.onFallback(ctx, key, err)
+
+	if f.customFallback != nil {
+		result, ferr := f.customFallback(ctx, key, n, err)
+		return newReservation(result, nil), ferr
+	}
+	if f.strategy == FallbackOpen || f.strategy == FallbackClose {
+		result, ferr := f.fallback(func(l Limiter) (*Result, error) { return l.AllowN(ctx, key, n) })
+		return newReservation(result, nil), ferr
+	}
+	if lr, ok := f.local.(Reserver); ok {
+		return lr.Reserve(ctx, key, n)
+	}
+	return nil, ErrReserveNotSupported
+}
+
+// onFallback 记录降级日志、指标并触发降级回调
+func (f *fallbackLimiter) onFallback(ctx context.Context, key Key, err error) {
 	f.logFallback(ctx, err)
 	if f.opts.metrics != nil {
 		// 设计决策: 使用 classifyError 将错误归类为低基数标签，
@@ -52,18 +120,9 @@ func (f *fallbackLimiter) AllowN(ctx context.Context, key Key, n int) (*Result,
 		f.opts.metrics.RecordFallback(ctx, f.strategy, classifyError(err))
 	}
 
-	// 触发降级回调
 	if f.opts.onFallback != nil {
 		f.opts.onFallback(key, f.strategy, err)
 	}
-
-	// 优先使用自定义降级函数
-	if f.customFallback != nil {
-		return f.customFallback(ctx, key, n, err)
-	}
-
-	// 执行默认降级策略
-	return f.fallback(ctx, key, n)
 }
 
 // logFallback 记录降级日志
@@ -76,11 +135,11 @@ func (f *fallbackLimiter) logFallback(ctx context.Context, err error) {
 	}
 }
 
-// fallback 执行降级策略
-func (f *fallbackLimiter) fallback(ctx context.Context, key Key, n int) (*Result, error) {
+// fallback 执行降级策略，本地降级时以 check 检查本地限流器
+func (f *fallbackLimiter) fallback(check func(Limiter) (*Result, error)) (*Result, error) {
 	switch f.strategy {
 	case FallbackLocal:
-		return f.local.AllowN(ctx, key, n)
+		return check(f.local)
 
 	case FallbackOpen:
 		return &Result{
@@ -96,7 +155,7 @@ func (f *fallbackLimiter) fallback(ctx context.Context, key Key, n int) (*Result
 
 	default:
 		// 默认使用本地限流
-		return f.local.AllowN(ctx, key, n)
+		return check(f.local)
 	}
 }
 
@@ -106,7 +165,12 @@ func (f *fallbackLimiter) Reset(ctx context.Context, key Key) error {
 
 	// 使用类型断言检查 distributed 是否实现 Resetter
 	if r, ok := f.distributed.(Resetter); ok {
-		if err := r.Reset(ctx, key); err != nil && !IsRedisError(err) {
+		err := r.Reset(ctx, key)
+		if errors.Is(err, ErrNoRuleMatched) {
+			// 两个限流器共享同一组规则，本地同样不会匹配
+			return err
+		}
+		if err != nil && !IsRedisError(err) {
 			errs = append(errs, err)
 		}
 	}
@@ -169,4 +233,6 @@ var (
 	_ Limiter  = (*fallbackLimiter)(nil)
 	_ Querier  = (*fallbackLimiter)(nil)
 	_ Resetter = (*fallbackLimiter)(nil)
+	_ Reserver = (*fallbackLimiter)(nil)
+	_ Waiter   = (*fallbackLimiter)(nil)
 )
@@ -23,6 +23,7 @@ import (
 type Limiter interface {
 	// Allow 检查是否允许单个请求通过
 	// 如果被限流，返回的 Result.Allowed 为 false
+	// 配置了 WithCostFunc 时按请求成本扣减配额
 	Allow(ctx context.Context, key Key) (*Result, error)
 
 	// AllowN 检查是否允许 n 个请求通过
@@ -53,17 +54,79 @@ type Querier interface {
 
 // Resetter 配额重置接口
 //
-// 实现此接口的限流器支持手动重置配额。
+// 实现此接口的限流器支持手动重置配额，用于事故处理时解除误限流（如配置错误导致租户被限）。
 // 使用方式：
 //
 //	if r, ok := limiter.(xlimit.Resetter); ok {
 //	    err := r.Reset(ctx, key)
 //	}
+//
+// Reset 是管理操作的逃生通道，不应出现在业务请求路径上。
+// 通过 HTTP 等接口暴露时必须经过鉴权，否则任何调用方都能清空自己的限流计数。
 type Resetter interface {
-	// Reset 重置指定键的限流计数
+	// Reset 删除 key 匹配的所有层级规则的限流计数，下一次 Allow 从满配额开始
+	//
+	// 层级限流中 key 会匹配多条规则（如全局 + 租户），每条规则渲染出的键都会被重置；
+	// 全局规则的键由所有租户共享，重置某个租户时全局计数也随之清空。
+	// 单条规则重置失败不影响其他规则，错误汇总返回；无匹配规则时返回 ErrNoRuleMatched。
 	Reset(ctx context.Context, key Key) error
 }
 
+// Reserver 配额预留接口
+//
+// 实现此接口的限流器支持预留配额，并在下游工作中止时归还未使用的配额。
+// 使用方式：
+//
+//	if r, ok := limiter.(xlimit.Reserver); ok {
+//	    res, err := r.Reserve(ctx, key, len(batch))
+//	    if err != nil || !res.OK() { ... }
+//	    if processErr != nil {
+//	        _ = res.Cancel(ctx) // 归还配额
+//	    }
+//	}
+type Reserver interface {
+	// Reserve 预留 n 个配额，语义与 AllowN 一致
+	// 返回的 Reservation 可通过 Cancel 归还已扣减的配额
+	Reserve(ctx context.Context, key Key, n int) (*Reservation, error)
+}
+
+// CostLimiter 小数成本限流接口
+//
+// 实现此接口的限流器支持按小数成本扣减配额，适用于单个请求的资源消耗差异较大、
+// 无法用整数 n 精确表达的场景（如扫描数据量是普通查询 2.5 倍的请求）。
+// 使用方式：
+//
+//	if cl, ok := limiter.(xlimit.CostLimiter); ok {
+//	    result, err := cl.AllowCost(ctx, key, 2.5)
+//	}
+type CostLimiter interface {
+	// AllowCost 检查是否允许成本为 cost 的请求通过，在每条层级规则上扣减 cost 个配额
+	//
+	// cost 按 0.001 精度向上取整，必须为正数且不超过 math.MaxInt32，否则返回 ErrInvalidN。
+	// 令牌桶算法按量化后的小数成本扣减；滑动窗口按请求计数，cost 向上取整为整数。
+	// Result.Remaining 与 Query 返回的 Remaining 为剩余配额向下取整，
+	// 剩余 0.6 个配额时报告 0，但成本不超过 0.6 的请求仍会放行。
+	AllowCost(ctx context.Context, key Key, cost float64) (*Result, error)
+}
+
+// Waiter 阻塞等待接口
+//
+// 实现此接口的限流器支持阻塞等待直到配额可用，适用于客户端出站调用节流。
+// 使用方式：
+//
+//	if w, ok := limiter.(xlimit.Waiter); ok {
+//	    if err := w.Wait(ctx, key); err != nil {
+//	        return err
+//	    }
+//	}
+type Waiter interface {
+	// Wait 阻塞直到允许单个请求通过或 ctx 结束
+	Wait(ctx context.Context, key Key) error
+
+	// WaitN 阻塞直到允许 n 个请求通过或 ctx 结束
+	WaitN(ctx context.Context, key Key, n int) error
+}
+
 // =============================================================================
 // 策略接口
 // =============================================================================
@@ -84,9 +147,11 @@ type RuleProvider interface {
 
 // QuotaInfo 配额信息
 type QuotaInfo struct {
-	// Limit 配额上限
+	// Limit 配额上限（每个 Window 的稳态速率）
 	Limit int
-	// Remaining 剩余配额
+	// Burst 突发容量（令牌桶容量）；滑动窗口算法下等于 Limit
+	Burst int
+	// Remaining 剩余配额，即当前可立即放行的请求数，不超过 Burst
 	Remaining int
 	// ResetAt 配额重置时间
 	ResetAt time.Time
@@ -131,7 +196,8 @@ func New(rdb redis.UniversalClient, opts ...Option) (Limiter, error) {
 	warnLimitBelowPodCount(cfg)
 
 	matcher := newRuleMatcher(cfg.config.Rules)
-	backend := newRedisBackend(rdb)
+	matcher.setCanaries(cfg.canaries)
+	backend := newRedisBackend(rdb, cfg.config.Algorithm)
 	distributed := newLimiterCore(backend, matcher, cfg)
 
 	if cfg.config.Fallback != "" {
@@ -139,7 +205,7 @@ func New(rdb redis.UniversalClient, opts ...Option) (Limiter, error) {
 		// 多 Pod 部署下每个 Pod 按完整配额执行本地限流，总放行量可达 N 倍。
 		// 不设为硬错误是因为单 Pod 场景（开发/测试/小型服务）默认值合理。
 		warnDefaultPodCount(cfg)
-		localBackend := newLocalBackend(cfg.config.EffectivePodCount(), cfg.podCountProvider, cfg.logger)
+		localBackend := newLocalBackend(cfg.config.EffectivePodCount(), cfg.podCountProvider, cfg.logger, cfg.config.Algorithm)
 		local := newLimiterCore(localBackend, matcher, cfg)
 		return newFallbackLimiter(distributed, local, cfg), nil
 	}
@@ -175,7 +241,8 @@ func NewLocal(opts ...Option) (Limiter, error) {
 	warnLimitBelowPodCount(cfg)
 
 	matcher := newRuleMatcher(cfg.config.Rules)
-	backend := newLocalBackend(cfg.config.EffectivePodCount(), cfg.podCountProvider, cfg.logger)
+	matcher.setCanaries(cfg.canaries)
+	backend := newLocalBackend(cfg.config.EffectivePodCount(), cfg.podCountProvider, cfg.logger, cfg.config.Algorithm)
 	return newLimiterCore(backend, matcher, cfg), nil
 }
 
@@ -295,3 +295,54 @@ func TestNew_MultipleRules(t *testing.T) {
 		t.Errorf("expected rule 'tenant-api', got %q", result.Rule)
 	}
 }
+
+type costKey struct{}
+
+func TestWithCostFunc(t *testing.T) {
+	_, client := setupMiniredis(t)
+	cost := func(ctx context.Context) int {
+		c, _ := ctx.Value(costKey{}).(int)
+		return c
+	}
+	rules := WithRules(GlobalRule("global", 100, time.Minute), TenantRule("tenant", 10, time.Minute))
+	distributed, err := New(client, rules, WithCostFunc(cost), WithFallback(FallbackLocal))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	local, err := NewLocal(rules, WithCostFunc(cost))
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	for name, limiter := range map[string]Limiter{"redis": distributed, "local": local} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.WithValue(testContext(), costKey{}, 4)
+			key := Key{Tenant: name}
+
+			res, err := limiter.Allow(ctx, key)
+			if err != nil || !res.Allowed {
+				t.Fatalf("expected allowed, got %+v, %v", res, err)
+			}
+			if res.Remaining != 6 {
+				t.Errorf("expected cost 4 deducted from tenant level, remaining %d", res.Remaining)
+			}
+
+			// 成本不足 1 按 1 处理
+			res, err = limiter.Allow(testContext(), key)
+			if err != nil || res.Remaining != 5 {
+				t.Errorf("expected cost 1, got %+v, %v", res, err)
+			}
+
+			// 显式 AllowN 不调用成本函数
+			res, err = limiter.AllowN(ctx, key, 5)
+			if err != nil || !res.Allowed || res.Remaining != 0 {
+				t.Errorf("expected AllowN to use n, got %+v, %v", res, err)
+			}
+
+			res, err = limiter.Allow(ctx, key)
+			if err != nil || res.Allowed {
+				t.Errorf("expected denied after quota is spent, got %+v, %v", res, err)
+			}
+		})
+	}
+}
@@ -414,7 +414,7 @@ func TestLocalBackend_UpdateParamsOnPodCountChange(t *testing.T) {
 	podCount := 2
 	provider := &mockPodCountProvider{count: podCount}
 
-	backend := newLocalBackend(1, provider, nil)
+	backend := newLocalBackend(1, provider, nil, "")
 
 	ctx := context.Background()
 
@@ -487,7 +487,7 @@ func TestTokenBucket_UpdateParams(t *testing.T) {
 }
 
 func TestLocalBackend_MaxBucketsSafetyLimit(t *testing.T) {
-	backend := newLocalBackend(1, nil, nil)
+	backend := newLocalBackend(1, nil, nil, "")
 	ctx := context.Background()
 
 	// 填满到 maxBuckets
@@ -16,6 +16,8 @@ const (
 	metricNameDeniedTotal = "xlimit.denied.total"
 	// metricNameFallbackTotal 降级次数计数器
 	metricNameFallbackTotal = "xlimit.fallback.total"
+	// metricNameShadowDeniedTotal 影子模式下本应被拒绝的请求计数器
+	metricNameShadowDeniedTotal = "xlimit.shadow_denied.total"
 	// metricNameCheckDuration 限流检查耗时直方图
 	metricNameCheckDuration = "xlimit.check.duration"
 
@@ -30,6 +32,7 @@ type Metrics struct {
 	requestsTotal metric.Int64Counter
 	deniedTotal   metric.Int64Counter
 	fallbackTotal metric.Int64Counter
+	shadowDenied  metric.Int64Counter
 	checkDuration metric.Float64Histogram
 }
 
@@ -71,6 +74,15 @@ func NewMetrics(meterProvider metric.MeterProvider) (*Metrics, error) {
 		return nil, err
 	}
 
+	shadowDenied, err := meter.Int64Counter(
+		metricNameShadowDeniedTotal,
+		metric.WithDescription("影子模式下本应被限流拒绝的请求数"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	checkDuration, err := meter.Float64Histogram(
 		metricNameCheckDuration,
 		metric.WithDescription("限流检查耗时"),
@@ -88,6 +100,7 @@ func NewMetrics(meterProvider metric.MeterProvider) (*Metrics, error) {
 		requestsTotal: requestsTotal,
 		deniedTotal:   deniedTotal,
 		fallbackTotal: fallbackTotal,
+		shadowDenied:  shadowDenied,
 		checkDuration: checkDuration,
 	}, nil
 }
@@ -137,3 +150,22 @@ func (m *Metrics) RecordFallback(ctx context.Context, strategy FallbackStrategy,
 
 	m.fallbackTotal.Add(metricsCtx, 1, metric.WithAttributes(attrs...))
 }
+
+// RecordShadowDeny 记录影子模式下本应被拒绝的请求
+// ctx: 上下文
+// limiterType: 限流器类型（"distributed" 或 "local"）
+// rule: 本应拒绝请求的规则名称
+func (m *Metrics) RecordShadowDeny(ctx context.Context, limiterType, rule string) {
+	if m == nil {
+		return
+	}
+
+	metricsCtx := context.WithoutCancel(ctx)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("limiter_type", limiterType),
+		attribute.String("rule", rule),
+	}
+
+	m.shadowDenied.Add(metricsCtx, 1, metric.WithAttributes(attrs...))
+}
@@ -2,6 +2,8 @@ package xlimit
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -100,6 +102,7 @@ func TestMetrics_NilSafe(t *testing.T) {
 	// 这些调用不应该 panic
 	m.RecordAllow(ctx, "distributed", "test", true, time.Millisecond)
 	m.RecordFallback(ctx, FallbackLocal, "test error")
+	m.RecordShadowDeny(ctx, "local", "test")
 }
 
 func TestMetrics_CanceledContext(t *testing.T) {
@@ -123,3 +126,57 @@ func TestMetrics_CanceledContext(t *testing.T) {
 
 	assertMetricExists(t, rm, metricNameRequestsTotal)
 }
+
+func TestShadowMode(t *testing.T) {
+	reader := metric.NewManualReader()
+	provider := metric.NewMeterProvider(metric.WithReader(reader))
+	defer func() { _ = provider.Shutdown(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	_, client := setupMiniredis(t)
+	var denied int
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 1, time.Minute)),
+		WithShadowMode(true),
+		WithMeterProvider(provider),
+		WithOnDeny(func(Key, *Result) { denied++ }),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.False(t, res.ShadowDenied)
+
+	res, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "shadow mode never blocks")
+	assert.True(t, res.ShadowDenied)
+	assert.Equal(t, "tenant", res.Rule)
+	assert.Zero(t, res.RetryAfter)
+	assert.Zero(t, denied, "OnDeny is not called in shadow mode")
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	assertMetricExists(t, rm, metricNameShadowDeniedTotal)
+
+	// HTTP 中间件放行所有请求
+	handler := HTTPMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("Retry-After"))
+}
+
+func TestConfig_ShadowMode(t *testing.T) {
+	cfg := DefaultConfig()
+	assert.False(t, cfg.ShadowMode)
+	cfg.ShadowMode = true
+	assert.True(t, cfg.Clone().ShadowMode)
+}
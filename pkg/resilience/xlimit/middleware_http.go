@@ -58,9 +58,7 @@ func handleHTTPLimit(w http.ResponseWriter, r *http.Request, limiter Limiter, mo
 		// 返回 Allowed=false + ErrRedisUnavailable）。仅当 result 为空时
 		// 才 fail-open（限流器内部错误不阻塞业务请求）。
 		if result != nil && !result.Allowed {
-			if mopts.EnableHeaders {
-				result.SetHeaders(w)
-			}
+			mopts.setHeaders(w, result)
 			mopts.DenyHandler(w, r, result)
 			return true
 		}
@@ -81,9 +79,7 @@ func handleHTTPLimit(w http.ResponseWriter, r *http.Request, limiter Limiter, mo
 	}
 
 	// 添加限流头（如果启用）
-	if mopts.EnableHeaders {
-		result.SetHeaders(w)
-	}
+	mopts.setHeaders(w, result)
 
 	// 检查是否被限流
 	if !result.Allowed {
@@ -94,6 +90,16 @@ func handleHTTPLimit(w http.ResponseWriter, r *http.Request, limiter Limiter, mo
 	return false
 }
 
+// setHeaders 按选项写入限流响应头
+func (m *MiddlewareOptions) setHeaders(w http.ResponseWriter, result *Result) {
+	if m.EnableHeaders {
+		result.SetHeaders(w)
+	}
+	if m.RateLimitHeaders {
+		result.SetRateLimitHeaders(w)
+	}
+}
+
 // HTTPMiddlewareFunc 创建 HTTP 限流中间件（函数式）
 // 适用于需要 http.HandlerFunc 的场景
 func HTTPMiddlewareFunc(limiter Limiter, opts ...MiddlewareOption) func(http.HandlerFunc) http.HandlerFunc {
@@ -191,6 +191,67 @@ func TestHTTPMiddleware_DisableHeaders(t *testing.T) {
 	}
 }
 
+func TestHTTPMiddleware_RateLimitHeaders(t *testing.T) {
+	limiter := setupTestLimiter(t, 1)
+	middleware := HTTPMiddleware(limiter, WithMiddlewareHeaders(false), WithRateLimitHeaders(true))
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Header.Set("X-Tenant-ID", "ietf-tenant")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := serve()
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("RateLimit-Limit"); got != "1" {
+		t.Errorf("expected RateLimit-Limit=1, got %q", got)
+	}
+	if got := rr.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("expected RateLimit-Remaining=0, got %q", got)
+	}
+	if rr.Header().Get("RateLimit-Reset") == "" {
+		t.Error("expected RateLimit-Reset header")
+	}
+	if rr.Header().Get("Retry-After") != "" {
+		t.Error("should not set Retry-After when allowed")
+	}
+	if rr.Header().Get("X-RateLimit-Limit") != "" {
+		t.Error("legacy headers are disabled independently")
+	}
+
+	rr = serve()
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected status 429, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header when rate limited")
+	}
+}
+
+func TestHTTPMiddleware_RateLimitHeadersDisabledByDefault(t *testing.T) {
+	limiter := setupTestLimiter(t, 10)
+	handler := HTTPMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+	req.Header.Set("X-Tenant-ID", "default-tenant")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Header().Get("RateLimit-Limit") != "" {
+		t.Error("IETF headers should be opt-in")
+	}
+}
+
 func TestHTTPMiddleware_CustomKeyExtractor(t *testing.T) {
 	limiter := setupTestLimiter(t, 10)
 
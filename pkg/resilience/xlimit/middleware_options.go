@@ -17,8 +17,11 @@ type MiddlewareOptions struct {
 	// 返回 true 时跳过限流检查
 	SkipFunc func(r *http.Request) bool
 
-	// EnableHeaders 是否在响应中添加限流头
+	// EnableHeaders 是否在响应中添加限流头（X-RateLimit-* 与 Retry-After）
 	EnableHeaders bool
+
+	// RateLimitHeaders 是否在响应中添加 IETF 草案限流头（RateLimit-* 与 Retry-After）
+	RateLimitHeaders bool
 }
 
 // MiddlewareOption 中间件选项函数
@@ -90,3 +93,14 @@ func WithMiddlewareHeaders(enable bool) MiddlewareOption {
 		opts.EnableHeaders = enable
 	}
 }
+
+// WithRateLimitHeaders 设置是否启用 IETF 草案限流头（默认关闭）
+//
+// 启用后每个响应都带有 RateLimit-Limit / RateLimit-Remaining / RateLimit-Reset，
+// 被限流时带有 Retry-After，便于遵循草案的客户端主动退避。
+// 与 WithMiddlewareHeaders 相互独立，可同时启用。
+func WithRateLimitHeaders(enable bool) MiddlewareOption {
+	return func(opts *MiddlewareOptions) {
+		opts.RateLimitHeaders = enable
+	}
+}
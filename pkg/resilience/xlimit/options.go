@@ -2,6 +2,7 @@ package xlimit
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/otel/metric"
 
@@ -34,6 +35,8 @@ type options struct {
 	onFallback       func(key Key, strategy FallbackStrategy, err error)
 	customFallback   FallbackFunc
 	podCountProvider PodCountProvider
+	canaries         []*canaryRule
+	costFunc         func(ctx context.Context) int
 	initErr          error // 配置加载阶段的错误，延迟到 New/NewLocal 时返回
 }
 
@@ -44,7 +47,10 @@ func (o *options) validate() error {
 	if o.initErr != nil {
 		return o.initErr
 	}
-	return o.config.Validate()
+	if err := o.config.Validate(); err != nil {
+		return err
+	}
+	return o.validateCanaries()
 }
 
 // Option 配置选项函数
@@ -80,6 +86,35 @@ func WithFallback(strategy FallbackStrategy) Option {
 	}
 }
 
+// WithAlgorithm 设置限流算法
+// 可选值：AlgoTokenBucket（默认）, AlgoSlidingWindow
+// 本地后端（NewLocal 及 FallbackLocal 降级）使用相同算法，降级前后语义一致。
+func WithAlgorithm(algo Algorithm) Option {
+	return func(o *options) {
+		o.config.Algorithm = algo
+	}
+}
+
+// WithWarmup 设置新 key 的预热时长
+// key 首次出现后，生效配额（Limit 与 Burst）在 d 内从 10% 线性增长到完整配额，
+// 避免冷启动的下游被首批突发流量打满。d <= 0 表示不预热（默认）。
+// 分布式限流器的首见时间存储在 Redis 中，各 Pod 共享；降级到本地限流时本地独立计时，预热重新开始。
+func WithWarmup(d time.Duration) Option {
+	return func(o *options) {
+		o.config.Warmup = d
+	}
+}
+
+// WithShadowMode 设置影子模式（dry-run）
+// 开启后规则照常评估并扣减配额，但本应拒绝的请求返回 Allowed=true 与 ShadowDenied=true，
+// 同时记录 xlimit.shadow_denied.total 指标和 Warn 日志，不触发 OnDeny 回调。
+// 用于在生产流量上验证新规则配置，确认无误后关闭即切换为强制执行。
+func WithShadowMode(enabled bool) Option {
+	return func(o *options) {
+		o.config.ShadowMode = enabled
+	}
+}
+
 // WithPodCount 设置预期 Pod 数量
 // 用于计算本地降级时的配额：本地配额 = 分布式配额 / PodCount
 func WithPodCount(count int) Option {
@@ -171,6 +206,27 @@ func WithCustomFallback(fn FallbackFunc) Option {
 	}
 }
 
+// WithCostFunc 设置请求成本计算函数
+// Allow/Wait（包括 HTTP/gRPC 中间件）按 fn 返回的成本扣减配额，而非固定为 1，
+// 适用于请求消耗资源差异较大的场景（如大查询与小查询）。
+// fn 返回值小于 1 时按 1 处理；显式调用 AllowN/WaitN/Reserve 时使用传入的 n，不调用 fn。
+//
+// 设计决策: 成本在每条层级规则上扣减相同的数量，Limit/Burst 的单位随之变为"成本"。
+// 成本超过某层级 Burst 的请求永远无法放行（RetryAfter 为 -1），配置规则时需覆盖最大成本。
+func WithCostFunc(fn func(ctx context.Context) int) Option {
+	return func(o *options) {
+		o.costFunc = fn
+	}
+}
+
+// cost 返回 Allow/Wait 的请求成本，未设置 costFunc 时为 1
+func (o *options) cost(ctx context.Context) int {
+	if o.costFunc == nil {
+		return 1
+	}
+	return max(o.costFunc(ctx), 1)
+}
+
 // WithPodCountProvider 设置动态 Pod 数量提供器
 // 用于计算本地降级时的配额：本地配额 = 分布式配额 / PodCount
 // 如果设置了此选项，将优先于 WithPodCount 设置的静态值
@@ -132,6 +132,61 @@ func TestDistributedLimiter_Reset(t *testing.T) {
 	assert.True(t, result.Allowed, "should be allowed after reset")
 }
 
+func TestDistributedLimiter_ResetAllLevels(t *testing.T) {
+	_, client := setupMiniredis(t)
+
+	limiter, err := New(client,
+		WithRules(
+			GlobalRule("global", 5, time.Minute),
+			TenantAPIRule("tenant-api", 3, time.Minute),
+		),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	ctx := context.Background()
+	key := Key{Tenant: "acme", Method: "GET", Path: "/orders"}
+	other := Key{Tenant: "other", Method: "GET", Path: "/orders"}
+
+	// 租户 API 层级耗尽 3 个，全局层级再被其他租户耗尽
+	for range 3 {
+		res, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+	for range 2 {
+		res, err := limiter.Allow(ctx, other)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+	res, err := limiter.Allow(ctx, other)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	assert.Equal(t, "global", res.Rule)
+
+	// 重置同时清空全局与租户 API 两个层级，无需等待窗口
+	require.NoError(t, limiter.(Resetter).Reset(ctx, key))
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.Remaining, "tenant-api level is full again")
+	res, err = limiter.Allow(ctx, other)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "shared global level is cleared too")
+}
+
+func TestDistributedLimiter_ResetNoRuleMatched(t *testing.T) {
+	_, client := setupMiniredis(t)
+
+	limiter, err := New(client)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	err = limiter.(Resetter).Reset(context.Background(), Key{Tenant: "acme"})
+	assert.ErrorIs(t, err, ErrNoRuleMatched)
+}
+
 func TestDistributedLimiter_MultipleRules(t *testing.T) {
 	_, client := setupMiniredis(t)
 
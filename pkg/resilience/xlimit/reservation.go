@@ -0,0 +1,69 @@
+package xlimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Reservation 配额预留
+//
+// 由 Reserver.Reserve 返回，记录预留时实际扣减配额的规则。
+// 下游工作中止或部分失败时，调用 Cancel 将 n 个配额归还给这些规则。
+//
+// 设计决策: Cancel 归还全部 n 个配额，不支持部分归还。
+// 需要部分归还时，按子批次分别 Reserve，或先 Cancel 再以实际用量 AllowN。
+type Reservation struct {
+	result *Result
+	refund func(context.Context) error
+
+	once sync.Once
+	err  error
+}
+
+// newReservation 创建预留，refund 为 nil 表示无可归还的配额
+func newReservation(result *Result, refund func(context.Context) error) *Reservation {
+	return &Reservation{result: result, refund: refund}
+}
+
+// OK 返回预留是否成功（所有规则均放行）
+func (r *Reservation) OK() bool {
+	return r.result != nil && r.result.Allowed
+}
+
+// Result 返回预留时的限流结果
+func (r *Reservation) Result() *Result {
+	return r.result
+}
+
+// Cancel 归还预留的配额，幂等：仅首次调用执行归还，后续调用返回首次的结果
+//
+// 归还量不会使配额超过规则的容量上限（令牌桶不超过 Burst）；
+// 滑动窗口只移除本次预留写入的记录，记录已滑出窗口时不做任何操作。
+// 预留被拒绝时（多规则场景下前序规则可能已扣减），Cancel 同样会归还这些已扣减的配额。
+//
+// 设计决策: 配额随时间自然恢复，延迟较久的 Cancel 归还量会被容量上限截断，
+// 因此 Cancel 应在确认工作中止后尽快调用。
+func (r *Reservation) Cancel(ctx context.Context) error {
+	r.once.Do(func() {
+		if r.refund != nil {
+			r.err = r.refund(ctx)
+		}
+	})
+	return r.err
+}
+
+// consumedRule 已扣减配额的规则，记录归还所需的后端参数
+type consumedRule struct {
+	key    string
+	limit  int
+	burst  int
+	window time.Duration
+	grant  slidingGrant // 滑动窗口本次写入的记录
+}
+
+// refunder 支持归还配额的后端（可选能力）
+type refunder interface {
+	// Refund 向 rule.key 归还 n 个配额，归还后不得超过容量上限
+	Refund(ctx context.Context, rule consumedRule, n int) error
+}
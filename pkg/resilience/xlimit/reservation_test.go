@@ -0,0 +1,327 @@
+package xlimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReserve_LocalCancelRefunds(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Hour)))
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	res, err := limiter.(Reserver).Reserve(ctx, key, 6)
+	require.NoError(t, err)
+	require.True(t, res.OK())
+	assert.Equal(t, 4, res.Result().Remaining)
+
+	require.NoError(t, res.Cancel(ctx))
+	// 幂等：重复 Cancel 不会重复归还
+	require.NoError(t, res.Cancel(ctx))
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 10, info.Remaining)
+}
+
+func TestReserve_RefundCappedAtCapacity(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Hour)))
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	res, err := limiter.(Reserver).Reserve(ctx, key, 5)
+	require.NoError(t, err)
+	require.True(t, res.OK())
+	// 配额被 Reset 恢复满后再 Cancel，不得超过上限
+	require.NoError(t, limiter.(Resetter).Reset(ctx, key))
+	_, err = limiter.AllowN(ctx, key, 1)
+	require.NoError(t, err)
+	require.NoError(t, res.Cancel(ctx))
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 10, info.Remaining)
+}
+
+func TestReserve_SlidingWindowLocal(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 5, time.Hour)),
+		WithAlgorithm(AlgoSlidingWindow),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	_, err = limiter.AllowN(ctx, key, 2)
+	require.NoError(t, err)
+	res, err := limiter.(Reserver).Reserve(ctx, key, 3)
+	require.NoError(t, err)
+	require.True(t, res.OK())
+	require.NoError(t, res.Cancel(ctx))
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 3, info.Remaining)
+}
+
+func TestSlidingLog_RefundRemovesOwnEntry(t *testing.T) {
+	l := &slidingLog{}
+	first := l.take(10, time.Hour, 3)
+	second := l.take(10, time.Hour, 4)
+	l.take(10, time.Hour, 2)
+
+	l.refund(first.grant.id)
+	assert.Equal(t, 6, l.count)
+	require.Len(t, l.entries, 2)
+	assert.Equal(t, second.grant.id, l.entries[0].id)
+
+	// 重复或未知序号不做任何操作
+	l.refund(first.grant.id)
+	l.refund(0)
+	assert.Equal(t, 6, l.count)
+}
+
+func TestSlidingLog_RefundExpiredEntryIsNoop(t *testing.T) {
+	l := &slidingLog{}
+	window := 20 * time.Millisecond
+	res := l.take(2, window, 2)
+	require.True(t, res.Allowed)
+	time.Sleep(2 * window)
+
+	// 预留已滑出窗口，窗口内是之后放行的请求
+	require.True(t, l.take(2, window, 2).Allowed)
+	l.refund(res.grant.id)
+	assert.Equal(t, 2, l.count)
+	assert.False(t, l.take(2, window, 1).Allowed)
+}
+
+func TestReserve_SlidingWindowCancelKeepsLaterRequests(t *testing.T) {
+	newLimiters := map[string]func(t *testing.T) Limiter{
+		"local": func(t *testing.T) Limiter {
+			limiter, err := NewLocal(
+				WithRules(TenantRule("tenant", 5, time.Hour)),
+				WithAlgorithm(AlgoSlidingWindow),
+			)
+			require.NoError(t, err)
+			return limiter
+		},
+		"redis": func(t *testing.T) Limiter {
+			_, client := setupMiniredis(t)
+			limiter, err := New(client,
+				WithRules(TenantRule("tenant", 5, time.Hour)),
+				WithAlgorithm(AlgoSlidingWindow),
+				WithFallback(""),
+			)
+			require.NoError(t, err)
+			return limiter
+		},
+	}
+	for name, newLimiter := range newLimiters {
+		t.Run(name, func(t *testing.T) {
+			limiter := newLimiter(t)
+			ctx := context.Background()
+			key := Key{Tenant: "acme"}
+
+			res, err := limiter.(Reserver).Reserve(ctx, key, 2)
+			require.NoError(t, err)
+			require.True(t, res.OK())
+			_, err = limiter.AllowN(ctx, key, 3)
+			require.NoError(t, err)
+
+			// 只归还预留的 2 个，之后放行的 3 个保留在窗口内
+			require.NoError(t, res.Cancel(ctx))
+			info, err := limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 2, info.Remaining)
+		})
+	}
+}
+
+func TestRedisBackend_SlidingWindowRefundExpired(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	backend := newRedisBackend(client, AlgoSlidingWindow)
+	ctx := context.Background()
+	window := 50 * time.Millisecond
+
+	reserved, err := backend.CheckRule(ctx, "k", 2, 0, window, 2)
+	require.NoError(t, err)
+	require.True(t, reserved.Allowed)
+	time.Sleep(2 * window)
+
+	res, err := backend.CheckRule(ctx, "k", 2, 0, window, 2)
+	require.NoError(t, err)
+	require.True(t, res.Allowed, "reserved entries slid out of the window")
+
+	require.NoError(t, backend.Refund(ctx, consumedRule{key: "k", limit: 2, window: window, grant: reserved.grant}, 2))
+	members, err := mr.ZMembers(slidingWindowKeyPrefix + "k")
+	require.NoError(t, err)
+	assert.Len(t, members, 2, "entries written after the reservation are kept")
+}
+
+func TestReserve_DeniedRefundsEarlierRules(t *testing.T) {
+	limiter, err := NewLocal(WithRules(
+		GlobalRule("global", 6, time.Hour),
+		TenantRule("tenant", 2, time.Hour),
+	))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// global 放行并扣减 5，tenant 拒绝
+	res, err := limiter.(Reserver).Reserve(ctx, Key{Tenant: "acme"}, 5)
+	require.NoError(t, err)
+	assert.False(t, res.OK())
+	assert.Equal(t, "tenant", res.Result().Rule)
+
+	require.NoError(t, res.Cancel(ctx))
+
+	// global 配额已全部归还：3 个租户各取 2 个恰好用满
+	for _, tenant := range []string{"a", "b", "c"} {
+		r, err := limiter.AllowN(ctx, Key{Tenant: tenant}, 2)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed, tenant)
+	}
+}
+
+func TestReserve_InvalidNAndClosed(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Hour)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = limiter.(Reserver).Reserve(ctx, Key{Tenant: "a"}, 0)
+	assert.ErrorIs(t, err, ErrInvalidN)
+
+	require.NoError(t, limiter.Close(ctx))
+	_, err = limiter.(Reserver).Reserve(ctx, Key{Tenant: "a"}, 1)
+	assert.ErrorIs(t, err, ErrLimiterClosed)
+}
+
+func TestReserve_NoRuleMatched(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Hour)))
+	require.NoError(t, err)
+
+	res, err := limiter.(Reserver).Reserve(context.Background(), Key{}, 1)
+	require.NoError(t, err)
+	assert.True(t, res.OK())
+	assert.NoError(t, res.Cancel(context.Background()))
+}
+
+func TestReserve_Redis(t *testing.T) {
+	tests := []struct {
+		name string
+		algo Algorithm
+	}{
+		{"token bucket", AlgoTokenBucket},
+		{"sliding window", AlgoSlidingWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := setupMiniredis(t)
+			limiter, err := New(client,
+				WithRules(TenantRule("tenant", 10, time.Hour)),
+				WithAlgorithm(tt.algo),
+				WithFallback(""),
+			)
+			require.NoError(t, err)
+			ctx := context.Background()
+			key := Key{Tenant: "acme"}
+
+			_, err = limiter.AllowN(ctx, key, 3)
+			require.NoError(t, err)
+			res, err := limiter.(Reserver).Reserve(ctx, key, 4)
+			require.NoError(t, err)
+			require.True(t, res.OK())
+			require.NoError(t, res.Cancel(ctx))
+			require.NoError(t, res.Cancel(ctx))
+
+			info, err := limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 7, info.Remaining)
+
+			// 超量归还被截断到容量上限
+			res, err = limiter.(Reserver).Reserve(ctx, key, 1)
+			require.NoError(t, err)
+			require.NoError(t, limiter.(Resetter).Reset(ctx, key))
+			require.NoError(t, res.Cancel(ctx))
+			info, err = limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 10, info.Remaining)
+		})
+	}
+}
+
+func TestRedisBackend_RefundCapsAtBurst(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	backend := newRedisBackend(client, AlgoTokenBucket)
+	ctx := context.Background()
+
+	_, err := backend.CheckRule(ctx, "k", 10, 10, time.Hour, 2)
+	require.NoError(t, err)
+	require.NoError(t, backend.Refund(ctx, consumedRule{key: "k", limit: 10, burst: 10, window: time.Hour}, 5))
+	assert.False(t, mr.Exists(redisRateKeyPrefix+"k"), "full bucket drops the TAT key")
+
+	res, err := backend.Query(ctx, "k", 10, 10, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 10, res.Remaining)
+}
+
+func TestReserve_FallbackLocal(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 10, time.Hour)),
+		WithFallback(FallbackLocal),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+	mr.Close()
+
+	res, err := limiter.(Reserver).Reserve(ctx, key, 8)
+	require.NoError(t, err)
+	require.True(t, res.OK())
+	require.NoError(t, res.Cancel(ctx))
+
+	res, err = limiter.(Reserver).Reserve(ctx, key, 10)
+	require.NoError(t, err)
+	assert.True(t, res.OK(), "local quota refunded")
+}
+
+func TestReserve_FallbackOpenAndClose(t *testing.T) {
+	for _, strategy := range []FallbackStrategy{FallbackOpen, FallbackClose} {
+		t.Run(string(strategy), func(t *testing.T) {
+			mr, client := setupMiniredis(t)
+			limiter, err := New(client,
+				WithRules(TenantRule("tenant", 10, time.Hour)),
+				WithFallback(strategy),
+			)
+			require.NoError(t, err)
+			mr.Close()
+
+			res, err := limiter.(Reserver).Reserve(context.Background(), Key{Tenant: "acme"}, 1)
+			require.NotNil(t, res)
+			assert.Equal(t, strategy == FallbackOpen, res.OK())
+			if strategy == FallbackClose {
+				assert.ErrorIs(t, err, ErrRedisUnavailable)
+			}
+			assert.NoError(t, res.Cancel(context.Background()))
+		})
+	}
+}
+
+func TestReservation_CancelErrorIsSticky(t *testing.T) {
+	calls := 0
+	boom := errors.New("boom")
+	r := newReservation(AllowedResult(1, 0), func(context.Context) error {
+		calls++
+		return boom
+	})
+	assert.ErrorIs(t, r.Cancel(context.Background()), boom)
+	assert.ErrorIs(t, r.Cancel(context.Background()), boom)
+	assert.Equal(t, 1, calls)
+}
@@ -22,6 +22,7 @@ type Result struct {
 	ResetAt time.Time
 
 	// RetryAfter 建议重试等待时间（仅在 Allowed=false 时有意义）
+	// 为 -1 表示请求数超过规则容量，等待多久都无法满足
 	RetryAfter time.Duration
 
 	// Rule 触发限流的规则名称
@@ -29,6 +30,9 @@ type Result struct {
 
 	// Key 触发限流的键
 	Key string
+
+	// ShadowDenied 影子模式下本应被拒绝（此时 Allowed 为 true）
+	ShadowDenied bool
 }
 
 // Headers 返回标准限流响应头
@@ -43,14 +47,41 @@ func (r *Result) Headers() map[string]string {
 		"X-RateLimit-Reset":     strconv.FormatInt(r.ResetAt.Unix(), 10),
 	}
 
+	r.addRetryAfter(headers)
+	return headers
+}
+
+// RateLimitHeaders 返回 IETF 草案（draft-ietf-httpapi-ratelimit-headers）定义的限流响应头
+// - RateLimit-Limit: 配额上限
+// - RateLimit-Remaining: 剩余配额
+// - RateLimit-Reset: 距配额重置的秒数（向上取整，已过期时为 0）
+// - Retry-After: 重试等待秒数（仅在被限流时）
+//
+// 设计决策: 与 Headers 的 X-RateLimit-Reset 不同，草案规定 Reset 为相对秒数而非时间戳，
+// 客户端无需与服务端对时即可计算退避时间。
+func (r *Result) RateLimitHeaders() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(r.Limit),
+		"RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"RateLimit-Reset":     strconv.FormatInt(ceilSeconds(max(time.Until(r.ResetAt), 0)), 10),
+	}
+	r.addRetryAfter(headers)
+	return headers
+}
+
+// addRetryAfter 在被限流时写入 Retry-After
+func (r *Result) addRetryAfter(headers map[string]string) {
 	if r.RetryAfter > 0 {
-		// 设计决策: 使用 math.Ceil 向上取整，避免亚秒级等待被截断为 0，
-		// 导致客户端立即重试并放大瞬时流量。
-		retryAfterSec := int64(math.Ceil(r.RetryAfter.Seconds()))
-		headers["Retry-After"] = strconv.FormatInt(retryAfterSec, 10)
+		headers["Retry-After"] = strconv.FormatInt(ceilSeconds(r.RetryAfter), 10)
 	}
+}
 
-	return headers
+// ceilSeconds 将时长向上取整为秒
+//
+// 设计决策: 使用 math.Ceil 向上取整，避免亚秒级等待被截断为 0，
+// 导致客户端立即重试并放大瞬时流量。
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
 }
 
 // SetHeaders 将限流响应头写入 http.ResponseWriter
@@ -67,6 +98,17 @@ func (r *Result) SetHeaders(w http.ResponseWriter) {
 	}
 }
 
+// SetRateLimitHeaders 将 IETF 草案限流响应头写入 http.ResponseWriter
+// Limit <= 0 时跳过写入，原因同 SetHeaders
+func (r *Result) SetRateLimitHeaders(w http.ResponseWriter) {
+	if r.Limit <= 0 {
+		return
+	}
+	for key, value := range r.RateLimitHeaders() {
+		w.Header().Set(key, value)
+	}
+}
+
 // AllowedResult 创建一个允许通过的结果
 func AllowedResult(limit, remaining int) *Result {
 	return &Result{
@@ -119,6 +119,41 @@ func TestResult_SetHeaders(t *testing.T) {
 	}
 }
 
+func TestResult_RateLimitHeaders(t *testing.T) {
+	result := &Result{
+		Allowed:    false,
+		Limit:      100,
+		Remaining:  0,
+		ResetAt:    time.Now().Add(1500 * time.Millisecond),
+		RetryAfter: 200 * time.Millisecond,
+	}
+
+	headers := result.RateLimitHeaders()
+	if headers["RateLimit-Limit"] != "100" {
+		t.Errorf("expected RateLimit-Limit=100, got %s", headers["RateLimit-Limit"])
+	}
+	if headers["RateLimit-Remaining"] != "0" {
+		t.Errorf("expected RateLimit-Remaining=0, got %s", headers["RateLimit-Remaining"])
+	}
+	if headers["RateLimit-Reset"] != "2" {
+		t.Errorf("expected RateLimit-Reset=2 (delta seconds, rounded up), got %s", headers["RateLimit-Reset"])
+	}
+	if headers["Retry-After"] != "1" {
+		t.Errorf("expected Retry-After=1, got %s", headers["Retry-After"])
+	}
+
+	result.ResetAt = time.Now().Add(-time.Second)
+	if got := result.RateLimitHeaders()["RateLimit-Reset"]; got != "0" {
+		t.Errorf("expected RateLimit-Reset=0 after reset time, got %s", got)
+	}
+
+	recorder := httptest.NewRecorder()
+	(&Result{Allowed: true, Limit: 0}).SetRateLimitHeaders(recorder)
+	if recorder.Header().Get("RateLimit-Limit") != "" {
+		t.Error("should not set RateLimit-Limit when Limit=0")
+	}
+}
+
 func TestResult_SetHeaders_SkipsWhenNoQuota(t *testing.T) {
 	// FG-M1: FallbackOpen 或无匹配规则时 Limit=0，不应写入误导性的配额头
 	result := &Result{
@@ -14,6 +14,7 @@ type ruleMatcher struct {
 	rules     map[string]Rule
 	ruleNames []string // 保持规则顺序
 	matchers  map[string][]string
+	canaries  map[string]*canaryRule // 按规则名索引的灰度规则
 }
 
 // newRuleMatcher 创建规则匹配器
@@ -0,0 +1,249 @@
+package xlimit
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// 滑动窗口日志算法
+// =============================================================================
+
+// slidingWindowKeyPrefix 滑动窗口 ZSET 键前缀。
+// 与 redis_rate 的 "rate:" 前缀区分，切换算法时不会因键类型不同触发 WRONGTYPE。
+const slidingWindowKeyPrefix = "sliding:"
+
+// slidingWindowScript 滑动窗口日志 Lua 脚本
+//
+// KEYS[1]: ZSET 键，每个已放行请求一个成员，score 为放行时间（微秒）
+// ARGV[1]: limit，任意窗口内允许的最大请求数
+// ARGV[2]: window，窗口时长（微秒）
+// ARGV[3]: n，本次请求数；0 表示只查询不消耗
+//
+// 返回 {allowed, remaining, retry_after_us, reset_after_us}，retry_after_us 为 -1 表示 n > limit 永远无法满足；
+// 放行时追加 {now_us, base}，本次写入的成员为 "now_us:base+1" 到 "now_us:base+n"。
+//
+// 设计决策: 使用 Redis TIME 而非客户端时间，避免多 Pod 时钟偏差导致窗口错位。
+// 成员名为 "时间:序号"，同一微秒内序号取当前计数递增，保证唯一。
+var slidingWindowScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local n = tonumber(ARGV[3])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now - window)
+local count = redis.call("ZCARD", key)
+
+if n > 0 and count + n <= limit then
+  local base = count
+  for i = 1, n do
+    redis.call("ZADD", key, now, now .. ":" .. (base + i))
+  end
+  count = count + n
+  redis.call("PEXPIRE", key, math.ceil(window / 1000))
+  local newest = redis.call("ZRANGE", key, -1, -1, "WITHSCORES")
+  return {1, limit - count, 0, tonumber(newest[2]) + window - now, now, base}
+end
+
+local reset_after = 0
+if count > 0 then
+  local newest = redis.call("ZRANGE", key, -1, -1, "WITHSCORES")
+  reset_after = tonumber(newest[2]) + window - now
+end
+
+if n == 0 then
+  return {1, math.max(limit - count, 0), 0, reset_after}
+end
+if n > limit then
+  return {0, math.max(limit - count, 0), -1, reset_after}
+end
+
+-- 需要等到第 (count + n - limit) 早的请求滑出窗口
+local idx = count + n - limit - 1
+local entry = redis.call("ZRANGE", key, idx, idx, "WITHSCORES")
+local retry_after = tonumber(entry[2]) + window - now
+return {0, math.max(limit - count, 0), retry_after, reset_after}
+`)
+
+// slidingWindowCheck 执行滑动窗口脚本，n 为 0 时只查询。
+func (b *redisBackend) slidingWindowCheck(ctx context.Context, key string, limit int, window time.Duration, n int) (CheckResult, error) {
+	vals, err := slidingWindowScript.Run(ctx, b.rdb, []string{slidingWindowKeyPrefix + key},
+		limit, window.Microseconds(), n).Int64Slice()
+	if err != nil {
+		return CheckResult{}, err
+	}
+
+	now := time.Now()
+	res := CheckResult{
+		Allowed:   vals[0] == 1,
+		Limit:     limit,
+		Burst:     limit,
+		Remaining: int(vals[1]),
+		ResetAt:   now.Add(time.Duration(vals[3]) * time.Microsecond),
+	}
+	if vals[2] < 0 {
+		res.RetryAfter = -1
+	} else {
+		res.RetryAfter = time.Duration(vals[2]) * time.Microsecond
+	}
+	if res.Allowed && len(vals) == 6 {
+		res.grant = slidingGrant{at: vals[4], base: vals[5], n: n}
+	}
+	return res, nil
+}
+
+// slidingWindowRefund 移除 grant 记录的成员。
+//
+// 设计决策: 只移除本次预留写入的成员，而不是弹出最新的 n 个：
+// 预留之后其他请求写入的成员不受影响；本次成员已滑出窗口（或键被 Reset）时 ZREM 为空操作，
+// 不会误删其他请求的记录。ZREM 由客户端直接发送，不受 Lua unpack 参数个数限制。
+func (b *redisBackend) slidingWindowRefund(ctx context.Context, key string, grant slidingGrant) error {
+	if grant.n <= 0 {
+		return nil
+	}
+	prefix := strconv.FormatInt(grant.at, 10) + ":"
+	members := make([]any, grant.n)
+	for i := range members {
+		members[i] = prefix + strconv.FormatInt(grant.base+int64(i)+1, 10)
+	}
+	return b.rdb.ZRem(ctx, slidingWindowKeyPrefix+key, members...).Err()
+}
+
+// slidingGrant 滑动窗口一次放行写入的记录，Reservation.Cancel 据此只归还本次写入的配额
+//
+// Redis 后端使用 at、base、n 还原成员名；本地后端使用 id 定位记录。零值表示没有可归还的记录。
+type slidingGrant struct {
+	at   int64  // Redis 放行时间（微秒）
+	base int64  // Redis 放行前的窗口计数
+	n    int    // Redis 写入的成员数
+	id   uint64 // 本地记录序号
+}
+
+// slidingEntrySeq 本地滑动窗口记录序号，全局递增。
+// 键被 Reset 后重建的日志不会复用旧序号，过期的预留无法误删新记录。
+var slidingEntrySeq atomic.Uint64
+
+// slidingLog 本地滑动窗口日志
+//
+// 设计决策: 同一时刻放行的 n 个请求合并为一条记录（时间 + 数量），
+// 内存与窗口内放行的批次数成正比，而非与请求数成正比；
+// Redis 实现为保持脚本简单仍为每个请求一个 ZSET 成员。
+type slidingLog struct {
+	mu      sync.Mutex
+	entries []slidingEntry // 按时间升序
+	count   int            // entries 中请求数之和
+}
+
+// slidingEntry 一批同时放行的请求。
+type slidingEntry struct {
+	id uint64 // 在 mu 内分配，entries 中按 id 升序
+	at time.Time
+	n  int
+}
+
+// prune 移除已滑出窗口的记录，调用方必须持有 mu。
+func (l *slidingLog) prune(now time.Time, window time.Duration) {
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(l.entries) && !l.entries[i].at.After(cutoff) {
+		l.count -= l.entries[i].n
+		i++
+	}
+	if i > 0 {
+		l.entries = append(l.entries[:0], l.entries[i:]...)
+	}
+}
+
+// resetAfter 返回窗口清空所需时间，调用方必须持有 mu。
+func (l *slidingLog) resetAfter(now time.Time, window time.Duration) time.Duration {
+	if len(l.entries) == 0 {
+		return 0
+	}
+	return l.entries[len(l.entries)-1].at.Add(window).Sub(now)
+}
+
+// take 尝试放行 n 个请求，语义与 slidingWindowScript 一致。
+func (l *slidingLog) take(limit int, window time.Duration, n int) CheckResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now, window)
+
+	if l.count+n <= limit {
+		id := slidingEntrySeq.Add(1)
+		l.entries = append(l.entries, slidingEntry{id: id, at: now, n: n})
+		l.count += n
+		return CheckResult{
+			Allowed:   true,
+			Limit:     limit,
+			Burst:     limit,
+			Remaining: limit - l.count,
+			ResetAt:   now.Add(window),
+			grant:     slidingGrant{id: id},
+		}
+	}
+
+	res := CheckResult{
+		Limit:     max(limit, 0),
+		Burst:     max(limit, 0),
+		Remaining: max(limit-l.count, 0),
+		ResetAt:   now.Add(l.resetAfter(now, window)),
+	}
+	if n > limit {
+		res.RetryAfter = -1
+		return res
+	}
+	// 累计最早的记录，直到释放出足够的配额
+	need := l.count + n - limit
+	for _, e := range l.entries {
+		need -= e.n
+		if need <= 0 {
+			res.RetryAfter = e.at.Add(window).Sub(now)
+			break
+		}
+	}
+	return res
+}
+
+// remaining 返回当前剩余配额与重置时间（只读查询）。
+func (l *slidingLog) remaining(limit int, window time.Duration) (int, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now, window)
+	return max(limit-l.count, 0), now.Add(l.resetAfter(now, window))
+}
+
+// refund 移除序号为 id 的记录；记录已滑出窗口时不做任何操作。
+func (l *slidingLog) refund(id uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	i, found := slices.BinarySearchFunc(l.entries, id, func(e slidingEntry, id uint64) int {
+		switch {
+		case e.id < id:
+			return -1
+		case e.id > id:
+			return 1
+		}
+		return 0
+	})
+	if !found {
+		return
+	}
+	l.count -= l.entries[i].n
+	l.entries = slices.Delete(l.entries, i, i+1)
+}
@@ -0,0 +1,192 @@
+package xlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlgorithm_IsValid(t *testing.T) {
+	assert.True(t, Algorithm("").IsValid())
+	assert.True(t, AlgoTokenBucket.IsValid())
+	assert.True(t, AlgoSlidingWindow.IsValid())
+	assert.False(t, Algorithm("leaky").IsValid())
+
+	_, err := NewLocal(WithRules(TenantRule("t", 10, time.Minute)), WithAlgorithm("leaky"))
+	assert.ErrorIs(t, err, ErrInvalidRule)
+
+	cfg := DefaultConfig()
+	cfg.Algorithm = AlgoSlidingWindow
+	assert.Equal(t, AlgoSlidingWindow, cfg.Clone().Algorithm)
+}
+
+func TestSlidingWindow_Redis(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 3, time.Minute)),
+		WithAlgorithm(AlgoSlidingWindow),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	for i := range 3 {
+		res, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2-i, res.Remaining)
+	}
+	// 一个 ZSET 成员对应一个请求
+	members, err := mr.ZMembers("sliding:ratelimit:tenant:acme")
+	require.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Greater(t, res.RetryAfter, 59*time.Second)
+	assert.LessOrEqual(t, res.RetryAfter, time.Minute)
+
+	// n > limit 永远无法满足
+	res, err = limiter.AllowN(ctx, key, 4)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Duration(-1), res.RetryAfter)
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 0, info.Remaining)
+	assert.Equal(t, 3, info.Limit)
+
+	require.NoError(t, limiter.(Resetter).Reset(ctx, key))
+	assert.False(t, mr.Exists("sliding:ratelimit:tenant:acme"))
+	res, err = limiter.AllowN(ctx, key, 3)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestSlidingWindow_RedisWindowSlides(t *testing.T) {
+	_, client := setupMiniredis(t)
+	backend := newRedisBackend(client, AlgoSlidingWindow)
+	ctx := context.Background()
+	window := 200 * time.Millisecond
+
+	res, err := backend.CheckRule(ctx, "k", 2, 0, window, 1)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	time.Sleep(100 * time.Millisecond)
+	res, err = backend.CheckRule(ctx, "k", 2, 0, window, 1)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	// 窗口满：需等第一条滑出（约 100ms），而非整个窗口
+	res, err = backend.CheckRule(ctx, "k", 2, 0, window, 1)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	assert.Less(t, res.RetryAfter, 150*time.Millisecond)
+
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+	res, err = backend.CheckRule(ctx, "k", 2, 0, window, 1)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "oldest entry slid out of the window")
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestSlidingWindow_RedisFallbackToLocal(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 2, time.Minute)),
+		WithAlgorithm(AlgoSlidingWindow),
+		WithFallback(FallbackLocal),
+	)
+	require.NoError(t, err)
+	defer func() { _ = limiter.Close(context.Background()) }() //nolint:errcheck // defer cleanup
+
+	mr.Close()
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+	for range 2 {
+		res, err := limiter.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+	}
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed, "local fallback keeps sliding-window semantics")
+}
+
+func TestSlidingWindow_Local(t *testing.T) {
+	limiter, err := NewLocal(
+		WithRules(TenantRule("tenant", 4, 200*time.Millisecond)),
+		WithAlgorithm(AlgoSlidingWindow),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 4, info.Remaining)
+
+	res, err := limiter.AllowN(ctx, key, 3)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 1, res.Remaining)
+
+	// 不足时需等到最早一批滑出窗口
+	res, err = limiter.AllowN(ctx, key, 2)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Greater(t, res.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, res.RetryAfter, 200*time.Millisecond)
+
+	res, err = limiter.AllowN(ctx, key, 5)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(-1), res.RetryAfter)
+
+	info, err = limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Remaining)
+
+	time.Sleep(220 * time.Millisecond)
+	res, err = limiter.AllowN(ctx, key, 4)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	require.NoError(t, limiter.(Resetter).Reset(ctx, key))
+	res, err = limiter.AllowN(ctx, key, 4)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+}
+
+func TestSlidingLog_RetryAfterAcrossBatches(t *testing.T) {
+	l := &slidingLog{}
+	window := time.Second
+	require.True(t, l.take(5, window, 2).Allowed)
+	time.Sleep(20 * time.Millisecond)
+	require.True(t, l.take(5, window, 3).Allowed)
+
+	// 需释放 2 个：第一批（2 个）滑出即可
+	first := l.take(5, window, 2)
+	assert.False(t, first.Allowed)
+	// 需释放 3 个：要等第二批滑出
+	second := l.take(5, window, 3)
+	assert.False(t, second.Allowed)
+	assert.Greater(t, second.RetryAfter, first.RetryAfter)
+	assert.Equal(t, 5, l.count)
+}
+
+func TestLocalBackend_SlidingWindowMaxBuckets(t *testing.T) {
+	b := newLocalBackend(1, nil, nil, AlgoSlidingWindow)
+	b.bucketCount.Store(maxBuckets)
+	res, err := b.CheckRule(context.Background(), "new", 10, 10, time.Minute, 1)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+}
@@ -0,0 +1,53 @@
+package xlimit
+
+import (
+	"context"
+
+	"github.com/omeyang/xkit/pkg/context/xtenant"
+)
+
+// tenantLimiter 将 Limiter 适配为 xtenant.TenantLimiter
+type tenantLimiter struct {
+	limiter Limiter
+}
+
+// NewTenantLimiter 将 Limiter 适配为 xtenant.TenantLimiter，
+// 用于在 xtenant 中间件/拦截器中自动按租户限流。
+//
+// 限流键仅包含 Tenant 维度，应配合 TenantRule 等基于 ${tenant_id} 的规则使用；
+// 需要按 API 等更多维度限流时，请使用 HTTPMiddleware/UnaryServerInterceptor。
+//
+// 示例:
+//
+//	limiter, _ := xlimit.New(rdb, xlimit.WithRules(xlimit.TenantRule("tenant", 100, time.Second)))
+//	handler = xtenant.HTTPMiddlewareWithOptions(
+//	    xtenant.WithRequireTenantID(),
+//	    xtenant.WithTenantLimiter(xlimit.NewTenantLimiter(limiter)),
+//	)(handler)
+func NewTenantLimiter(limiter Limiter) xtenant.TenantLimiter {
+	// 设计决策: nil limiter 使用 panic（同 HTTPMiddleware，见 middleware_http.go 注释）。
+	if limiter == nil {
+		panic("xlimit: NewTenantLimiter requires a non-nil Limiter")
+	}
+	return &tenantLimiter{limiter: limiter}
+}
+
+// AllowTenant 以租户 ID 作为限流键执行限流检查
+//
+// 错误映射与 HTTPMiddleware 一致：result 携带拒绝信息（如 FallbackClose）时
+// 返回拒绝决策；其他错误原样返回，由 xtenant 中间件 fail-open。
+func (t *tenantLimiter) AllowTenant(ctx context.Context, tenantID string) (xtenant.LimitDecision, error) {
+	result, err := t.limiter.Allow(ctx, Key{Tenant: tenantID})
+	if err != nil {
+		if result != nil && !result.Allowed {
+			return xtenant.LimitDecision{RetryAfter: result.RetryAfter}, nil
+		}
+		return xtenant.LimitDecision{}, err
+	}
+
+	// 防御性检查: 第三方 Limiter 实现可能违反 err==nil 时 result 非 nil 的契约
+	if result == nil {
+		return xtenant.LimitDecision{Allowed: true}, nil
+	}
+	return xtenant.LimitDecision{Allowed: result.Allowed, RetryAfter: result.RetryAfter}, nil
+}
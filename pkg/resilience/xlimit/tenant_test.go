@@ -0,0 +1,85 @@
+package xlimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/omeyang/xkit/pkg/context/xtenant"
+)
+
+func TestNewTenantLimiter_HTTPMiddleware(t *testing.T) {
+	limiter := setupTestLimiter(t, 2)
+	handler := xtenant.HTTPMiddlewareWithOptions(
+		xtenant.WithTenantLimiter(NewTenantLimiter(limiter)),
+	)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+		req.Header.Set(xtenant.HeaderTenantID, tenantID)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	for i := range 2 {
+		if rr := serve("tenant-a"); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rr.Code)
+		}
+	}
+
+	rr := serve("tenant-a")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after quota exhausted, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on rate limited response")
+	}
+
+	// 其他租户使用独立配额
+	if rr := serve("tenant-b"); rr.Code != http.StatusOK {
+		t.Errorf("tenant-b should pass, got %d", rr.Code)
+	}
+}
+
+func TestNewTenantLimiter_AllowTenant(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		limiter     Limiter
+		wantAllowed bool
+		wantErr     bool
+	}{
+		{"fallback close denies", &mockFallbackCloseLimiter{}, false, false},
+		{"error with nil result", &mockNilResultErrorLimiter{}, false, true},
+		{"nil result without error", &mockNilResultLimiter{}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := NewTenantLimiter(tt.limiter).AllowTenant(ctx, "t1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr && !errors.Is(err, ErrRedisUnavailable) {
+				t.Errorf("expected ErrRedisUnavailable, got %v", err)
+			}
+			if decision.Allowed != tt.wantAllowed {
+				t.Errorf("expected Allowed=%v, got %v", tt.wantAllowed, decision.Allowed)
+			}
+		})
+	}
+}
+
+func TestNewTenantLimiter_NilLimiterPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for nil limiter")
+		}
+	}()
+	NewTenantLimiter(nil)
+}
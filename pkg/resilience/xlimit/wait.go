@@ -0,0 +1,54 @@
+package xlimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// minWaitInterval 被拒绝但后端未给出 RetryAfter 时的最小重试间隔，避免忙等
+const minWaitInterval = 10 * time.Millisecond
+
+// waitN Wait/WaitN 的公共实现
+//
+// 循环调用 allow，被拒绝时按 Result.RetryAfter 休眠后重试，直到放行或 ctx 结束。
+// 返回值：
+//   - nil：已放行，配额已扣减
+//   - ErrInvalidN：n 超过规则容量（RetryAfter 为 -1），永远无法满足
+//   - ErrWaitExceedsDeadline：RetryAfter 超过 ctx 截止时间，立即返回而不休眠
+//   - ctx.Err()：休眠期间 ctx 结束
+//   - allow 返回的其他错误（如 ErrLimiterClosed、FallbackClose 的 ErrRedisUnavailable）
+//
+// 设计决策: 与 x/time/rate 的预约式 Wait 不同，这里每次醒来重新调用 AllowN，
+// 不预先占用未来的配额。分布式配额无法在多 Pod 间预约，代价是竞争激烈时
+// 可能多次等待；等待总时长仍受 ctx 约束。
+func waitN(ctx context.Context, allow func(context.Context, Key, int) (*Result, error), key Key, n int) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		res, err := allow(ctx, key, n)
+		if err != nil {
+			return err
+		}
+		if res.Allowed {
+			return nil
+		}
+
+		if res.RetryAfter < 0 {
+			return fmt.Errorf("%w: n=%d exceeds capacity of rule %q", ErrInvalidN, n, res.Rule)
+		}
+		delay := max(res.RetryAfter, minWaitInterval)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return fmt.Errorf("%w: retry after %v, rule %q", ErrWaitExceedsDeadline, delay, res.Rule)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
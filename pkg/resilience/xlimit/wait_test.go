@@ -0,0 +1,155 @@
+package xlimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait_LocalBlocksUntilRefill(t *testing.T) {
+	// 10/s：令牌约每 100ms 补充一个
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Second)))
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	_, err = limiter.AllowN(ctx, key, 10)
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.NoError(t, limiter.(Waiter).Wait(ctx, key))
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+	assert.Less(t, elapsed, time.Second)
+}
+
+func TestWait_AllowedImmediately(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 10, time.Minute)))
+	require.NoError(t, err)
+
+	start := time.Now()
+	require.NoError(t, limiter.(Waiter).WaitN(context.Background(), Key{Tenant: "acme"}, 3))
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	info, err := limiter.(Querier).Query(context.Background(), Key{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, 7, info.Remaining)
+}
+
+func TestWait_ExceedsDeadlineFailsFast(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 1, time.Hour)))
+	require.NoError(t, err)
+	key := Key{Tenant: "acme"}
+	_, err = limiter.Allow(context.Background(), key)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err = limiter.(Waiter).Wait(ctx, key)
+	assert.ErrorIs(t, err, ErrWaitExceedsDeadline)
+	assert.Less(t, time.Since(start), 100*time.Millisecond, "must not sleep until the deadline")
+}
+
+func TestWait_ContextCanceled(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 1, time.Hour)))
+	require.NoError(t, err)
+	key := Key{Tenant: "acme"}
+	_, err = limiter.Allow(context.Background(), key)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	assert.ErrorIs(t, limiter.(Waiter).Wait(ctx, key), context.Canceled)
+
+	assert.ErrorIs(t, limiter.(Waiter).Wait(ctx, key), context.Canceled, "canceled before first attempt")
+}
+
+func TestWait_NExceedsCapacity(t *testing.T) {
+	tests := []struct {
+		name string
+		algo Algorithm
+	}{
+		{"token bucket", AlgoTokenBucket},
+		{"sliding window", AlgoSlidingWindow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := setupMiniredis(t)
+			limiter, err := New(client,
+				WithRules(TenantRule("tenant", 5, time.Minute)),
+				WithAlgorithm(tt.algo),
+				WithFallback(""),
+			)
+			require.NoError(t, err)
+
+			err = limiter.(Waiter).WaitN(context.Background(), Key{Tenant: "acme"}, 6)
+			assert.ErrorIs(t, err, ErrInvalidN)
+		})
+	}
+
+	local, err := NewLocal(WithRules(TenantRule("tenant", 5, time.Minute)))
+	require.NoError(t, err)
+	assert.ErrorIs(t, local.(Waiter).WaitN(context.Background(), Key{Tenant: "acme"}, 6), ErrInvalidN)
+}
+
+func TestWait_InvalidNAndClosed(t *testing.T) {
+	limiter, err := NewLocal(WithRules(TenantRule("tenant", 5, time.Minute)))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, limiter.(Waiter).WaitN(ctx, Key{Tenant: "acme"}, 0), ErrInvalidN)
+	require.NoError(t, limiter.Close(ctx))
+	assert.ErrorIs(t, limiter.(Waiter).Wait(ctx, Key{Tenant: "acme"}), ErrLimiterClosed)
+}
+
+func TestWait_RedisFallbackWaitsLocally(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 10, time.Second)),
+		WithFallback(FallbackLocal),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+	mr.Close()
+
+	_, err = limiter.AllowN(ctx, key, 10)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	assert.NoError(t, limiter.(Waiter).Wait(waitCtx, key))
+}
+
+func TestWait_FallbackCloseReturnsError(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 10, time.Second)),
+		WithFallback(FallbackClose),
+	)
+	require.NoError(t, err)
+	mr.Close()
+
+	assert.ErrorIs(t, limiter.(Waiter).Wait(context.Background(), Key{Tenant: "acme"}), ErrRedisUnavailable)
+}
+
+func TestWaitN_MinIntervalWhenNoRetryAfter(t *testing.T) {
+	calls := 0
+	allow := func(context.Context, Key, int) (*Result, error) {
+		calls++
+		if calls < 3 {
+			return &Result{Allowed: false}, nil
+		}
+		return AllowedResult(1, 0), nil
+	}
+
+	start := time.Now()
+	require.NoError(t, waitN(context.Background(), allow, Key{}, 1))
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 2*minWaitInterval)
+}
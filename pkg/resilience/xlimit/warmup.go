@@ -0,0 +1,214 @@
+package xlimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// warmupStartFraction 预热开始时生效配额占完整配额的比例
+const warmupStartFraction = 0.1
+
+// warmupKeyPrefix Redis 中首见时间键的前缀
+const warmupKeyPrefix = "warmup:"
+
+// warmupTracker 支持预热的后端（可选能力）
+type warmupTracker interface {
+	// WarmupElapsed 返回 key 自首次出现以来经过的时间，首次出现时记录并返回 0
+	// key 空闲超过 idle 后记录失效，再次出现时重新开始计时
+	WarmupElapsed(ctx context.Context, key string, idle time.Duration) (time.Duration, error)
+
+	// PeekWarmupElapsed 与 WarmupElapsed 相同但只读：不记录首见时间、不刷新空闲计时
+	// 无记录或记录已空闲失效时返回 0，即下一次 WarmupElapsed 将看到的进度
+	PeekWarmupElapsed(ctx context.Context, key string, idle time.Duration) (time.Duration, error)
+}
+
+// warmupScale 按预热进度缩放配额
+// 从 warmupStartFraction 线性增长到完整配额，结果不小于 1
+func warmupScale(v int, elapsed, warmup time.Duration) int {
+	if warmup <= 0 || elapsed >= warmup {
+		return v
+	}
+	frac := warmupStartFraction + (1-warmupStartFraction)*float64(elapsed)/float64(warmup)
+	return max(int(float64(v)*frac), 1)
+}
+
+// applyWarmup 按 key 的预热进度缩放 limit 与 burst，未启用预热或后端不支持时原样返回
+//
+// 设计决策: 首见记录的空闲失效时间取 Warmup + window，至少覆盖一个完整窗口，
+// 长期无流量的 key 恢复流量时视为冷启动，重新预热。
+func (c *limiterCore) applyWarmup(ctx context.Context, fullKey string, limit, burst int, window time.Duration) (int, int, error) {
+	return c.scaleWarmup(ctx, fullKey, limit, burst, window, warmupTracker.WarmupElapsed)
+}
+
+// peekWarmup applyWarmup 的只读版本，供 Query 使用
+//
+// 设计决策: Query 不应产生副作用，只读取首见记录；无记录的 key 视为尚未开始预热，
+// 返回下一次 Allow 将使用的起始配额，而不是由查询启动预热计时。
+func (c *limiterCore) peekWarmup(ctx context.Context, fullKey string, limit, burst int, window time.Duration) (int, int, error) {
+	return c.scaleWarmup(ctx, fullKey, limit, burst, window, warmupTracker.PeekWarmupElapsed)
+}
+
+// scaleWarmup applyWarmup 与 peekWarmup 的公共实现，elapsed 决定是否记录首见时间
+func (c *limiterCore) scaleWarmup(ctx context.Context, fullKey string, limit, burst int, window time.Duration,
+	elapsed func(warmupTracker, context.Context, string, time.Duration) (time.Duration, error)) (int, int, error) {
+	warmup := c.opts.config.Warmup
+	if warmup <= 0 {
+		return limit, burst, nil
+	}
+	tracker, ok := c.backend.(warmupTracker)
+	if !ok {
+		return limit, burst, nil
+	}
+	d, err := elapsed(tracker, ctx, fullKey, warmup+window)
+	if err != nil {
+		return 0, 0, err
+	}
+	return warmupScale(limit, d, warmup), warmupScale(burst, d, warmup), nil
+}
+
+// =============================================================================
+// Redis 实现
+// =============================================================================
+
+// warmupScript 记录并返回首见以来经过的毫秒数
+//
+// KEYS[1]: 首见时间键
+// ARGV[1]: 空闲失效时间（毫秒）
+//
+// 使用 Redis 服务器时间，避免各 Pod 时钟偏差导致预热进度不一致。
+var warmupScript = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local idle = tonumber(ARGV[1])
+
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+
+local first = tonumber(redis.call("GET", key))
+if not first or first > now then
+  redis.call("SET", key, now, "PX", idle)
+  return 0
+end
+redis.call("PEXPIRE", key, idle)
+return now - first
+`)
+
+// warmupPeekScript 只读地返回首见以来经过的毫秒数，无记录时返回 0
+//
+// KEYS[1]: 首见时间键
+//
+// 空闲失效由键的 TTL 保证，键存在即记录有效。
+var warmupPeekScript = redis.NewScript(`
+local first = tonumber(redis.call("GET", KEYS[1]))
+if not first then
+  return 0
+end
+local t = redis.call("TIME")
+local now = t[1] * 1000 + math.floor(t[2] / 1000)
+return math.max(now - first, 0)
+`)
+
+// WarmupElapsed 实现 warmupTracker，首见时间存储在 Redis 中，各 Pod 共享
+func (b *redisBackend) WarmupElapsed(ctx context.Context, key string, idle time.Duration) (time.Duration, error) {
+	ms, err := warmupScript.Run(ctx, b.rdb, []string{warmupKeyPrefix + key}, idle.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// PeekWarmupElapsed 实现 warmupTracker
+func (b *redisBackend) PeekWarmupElapsed(ctx context.Context, key string, _ time.Duration) (time.Duration, error) {
+	ms, err := warmupPeekScript.Run(ctx, b.rdb, []string{warmupKeyPrefix + key}).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// =============================================================================
+// 本地实现
+// =============================================================================
+
+// warmupEntry 本地首见记录
+type warmupEntry struct {
+	mu        sync.Mutex
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// WarmupElapsed 实现 warmupTracker，首见时间仅在本进程内有效
+//
+// 设计决策: 记录数同样受 maxBuckets 限制；超限时不再记录新 key，
+// 按已完成预热处理（返回 idle），避免安全阀触发后额外收紧配额。
+func (b *localBackend) WarmupElapsed(ctx context.Context, key string, idle time.Duration) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	now := time.Now()
+
+	val, ok := b.warmups.Load(key)
+	if !ok {
+		if !b.reserveWarmupSlot() {
+			return idle, nil
+		}
+		var loaded bool
+		val, loaded = b.warmups.LoadOrStore(key, &warmupEntry{firstSeen: now, lastSeen: now})
+		if !loaded {
+			return 0, nil
+		}
+		b.warmupCount.Add(-1)
+	}
+	e, _ := val.(*warmupEntry)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if now.Sub(e.lastSeen) > idle {
+		e.firstSeen = now
+	}
+	e.lastSeen = now
+	return now.Sub(e.firstSeen), nil
+}
+
+// PeekWarmupElapsed 实现 warmupTracker
+func (b *localBackend) PeekWarmupElapsed(ctx context.Context, key string, idle time.Duration) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	val, ok := b.warmups.Load(key)
+	if !ok {
+		return 0, nil
+	}
+	e, _ := val.(*warmupEntry)
+	now := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if now.Sub(e.lastSeen) > idle {
+		return 0, nil
+	}
+	return now.Sub(e.firstSeen), nil
+}
+
+// reserveWarmupSlot 以 CAS 预留一个首见记录名额，超过 maxBuckets 时返回 false
+func (b *localBackend) reserveWarmupSlot() bool {
+	for {
+		cur := b.warmupCount.Load()
+		if cur >= maxBuckets {
+			return false
+		}
+		if b.warmupCount.CompareAndSwap(cur, cur+1) {
+			return true
+		}
+	}
+}
+
+// 确保内置后端实现了 warmupTracker 接口
+var (
+	_ warmupTracker = (*redisBackend)(nil)
+	_ warmupTracker = (*localBackend)(nil)
+)
@@ -0,0 +1,230 @@
+package xlimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmupScale(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       int
+		elapsed time.Duration
+		want    int
+	}{
+		{"first seen", 100, 0, 10},
+		{"half way", 100, 30 * time.Minute, 55},
+		{"done", 100, time.Hour, 100},
+		{"past warmup", 100, 2 * time.Hour, 100},
+		{"at least one", 5, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, warmupScale(tt.v, tt.elapsed, time.Hour))
+		})
+	}
+	assert.Equal(t, 100, warmupScale(100, 0, 0), "no warmup")
+}
+
+func TestWarmup_NewKeyStartsConservative(t *testing.T) {
+	_, client := setupMiniredis(t)
+	opts := []Option{WithRules(TenantRule("tenant", 100, time.Minute)), WithWarmup(time.Hour)}
+	distributed, err := New(client, append(opts, WithFallback(""))...)
+	require.NoError(t, err)
+	local, err := NewLocal(opts...)
+	require.NoError(t, err)
+
+	for name, limiter := range map[string]Limiter{"redis": distributed, "local": local} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			key := Key{Tenant: "acme"}
+
+			info, err := limiter.(Querier).Query(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, 10, info.Limit)
+			assert.Equal(t, 10, info.Burst)
+
+			res, err := limiter.AllowN(ctx, key, 10)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+
+			res, err = limiter.Allow(ctx, key)
+			require.NoError(t, err)
+			assert.False(t, res.Allowed, "burst is scaled down during warmup")
+
+			// 其他 key 独立预热
+			res, err = limiter.AllowN(ctx, Key{Tenant: "other"}, 10)
+			require.NoError(t, err)
+			assert.True(t, res.Allowed)
+		})
+	}
+}
+
+func TestWarmup_RampsUpFromFirstSeenInRedis(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithWarmup(time.Hour),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	_, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	warmupKey := warmupKeyPrefix + "ratelimit:tenant:acme"
+	require.True(t, mr.Exists(warmupKey))
+	assert.Equal(t, time.Hour+time.Minute, mr.TTL(warmupKey), "idle expiry covers warmup plus one window")
+
+	// 首见时间在半小时前：生效配额为 55%
+	firstSeen := time.Now().Add(-30 * time.Minute).UnixMilli()
+	require.NoError(t, mr.Set(warmupKey, strconv.FormatInt(firstSeen, 10)))
+
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 55, info.Limit)
+
+	// 预热完成后使用完整配额
+	firstSeen = time.Now().Add(-2 * time.Hour).UnixMilli()
+	require.NoError(t, mr.Set(warmupKey, strconv.FormatInt(firstSeen, 10)))
+
+	info, err = limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 100, info.Limit)
+}
+
+func TestWarmup_QueryIsReadOnly(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithWarmup(time.Hour),
+		WithFallback(""),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+	warmupKey := warmupKeyPrefix + "ratelimit:tenant:acme"
+
+	// 无记录：按尚未开始预热返回起始配额，不记录首见时间
+	info, err := limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 10, info.Limit)
+	assert.False(t, mr.Exists(warmupKey), "query must not start the warmup clock")
+
+	// 有记录：读取进度，不刷新空闲计时
+	_, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	mr.FastForward(30 * time.Minute)
+	ttl := mr.TTL(warmupKey)
+	_, err = limiter.(Querier).Query(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, ttl, mr.TTL(warmupKey), "query must not refresh the idle expiry")
+}
+
+func TestWarmup_Disabled(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client, WithRules(TenantRule("tenant", 100, time.Minute)), WithFallback(""))
+	require.NoError(t, err)
+
+	res, err := limiter.Allow(context.Background(), Key{Tenant: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, res.Limit)
+	assert.False(t, mr.Exists(warmupKeyPrefix+"ratelimit:tenant:acme"))
+}
+
+func TestWarmup_FallbackRestartsLocally(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	limiter, err := New(client,
+		WithRules(TenantRule("tenant", 100, time.Minute)),
+		WithWarmup(time.Hour),
+		WithFallback(FallbackLocal),
+	)
+	require.NoError(t, err)
+	ctx := context.Background()
+	key := Key{Tenant: "acme"}
+
+	// Redis 中已完成预热
+	firstSeen := time.Now().Add(-2 * time.Hour).UnixMilli()
+	require.NoError(t, mr.Set(warmupKeyPrefix+"ratelimit:tenant:acme", strconv.FormatInt(firstSeen, 10)))
+	res, err := limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 100, res.Limit)
+
+	// 降级后本地首见时间独立，重新预热
+	mr.Close()
+	res, err = limiter.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, 10, res.Limit)
+}
+
+func TestLocalBackend_WarmupElapsed(t *testing.T) {
+	b := newLocalBackend(1, nil, nil, AlgoTokenBucket)
+	ctx := context.Background()
+
+	elapsed, err := b.WarmupElapsed(ctx, "k", time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, elapsed)
+
+	time.Sleep(10 * time.Millisecond)
+	elapsed, err = b.WarmupElapsed(ctx, "k", time.Hour)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 10*time.Millisecond)
+
+	// 空闲超过 idle 后重新计时
+	time.Sleep(10 * time.Millisecond)
+	elapsed, err = b.WarmupElapsed(ctx, "k", 5*time.Millisecond)
+	require.NoError(t, err)
+	assert.Zero(t, elapsed)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = b.WarmupElapsed(cancelled, "k", time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLocalBackend_PeekWarmupElapsed(t *testing.T) {
+	b := newLocalBackend(1, nil, nil, AlgoTokenBucket)
+	ctx := context.Background()
+
+	elapsed, err := b.PeekWarmupElapsed(ctx, "k", time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, elapsed)
+	_, ok := b.warmups.Load("k")
+	assert.False(t, ok, "peek does not record first seen")
+
+	_, err = b.WarmupElapsed(ctx, "k", time.Hour)
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	elapsed, err = b.PeekWarmupElapsed(ctx, "k", time.Hour)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, elapsed, 10*time.Millisecond)
+
+	// 空闲失效的记录按未开始处理，且不刷新 lastSeen
+	elapsed, err = b.PeekWarmupElapsed(ctx, "k", 5*time.Millisecond)
+	require.NoError(t, err)
+	assert.Zero(t, elapsed)
+	elapsed, err = b.WarmupElapsed(ctx, "k", 5*time.Millisecond)
+	require.NoError(t, err)
+	assert.Zero(t, elapsed, "peek did not keep the idle record alive")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = b.PeekWarmupElapsed(cancelled, "k", time.Hour)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestConfig_ValidateWarmup(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Warmup = -time.Second
+	assert.ErrorIs(t, cfg.Validate(), ErrInvalidRule)
+
+	cfg.Warmup = time.Minute
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, time.Minute, cfg.Clone().Warmup)
+}
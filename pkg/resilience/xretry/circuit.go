@@ -0,0 +1,76 @@
+package xretry
+
+import (
+	"errors"
+	"fmt"
+
+	retry "github.com/avast/retry-go/v5"
+)
+
+// ErrCircuitOpen 表示因下游熔断器开路而停止重试。
+// 返回的错误同时包装最后一次执行的错误，可用 errors.Is 分别判断。
+var ErrCircuitOpen = errors.New("xretry: circuit open, retry aborted")
+
+// CircuitChecker 熔断状态检查接口
+//
+// *xbreaker.Breaker 与 *xbreaker.ManagedBreaker 实现此接口。
+// 设计决策: 在 xretry 中定义最小接口而非直接依赖 xbreaker，
+// 因为 xbreaker 已依赖 xretry（BreakerRetryer），反向依赖会形成循环；
+// 同时便于接入其他熔断实现或在测试中注入。
+type CircuitChecker interface {
+	// IsOpen 报告熔断器当前是否开路（拒绝请求）
+	IsOpen() bool
+}
+
+// WithCircuitBreaker 设置熔断感知：每次失败后、每次重试前检查熔断器，
+// 开路时立即停止重试并返回包装 ErrCircuitOpen 的错误，不再等待退避。
+// 传入 nil 会被静默忽略。
+//
+// 首次尝试不检查熔断器：是否放行首次请求由熔断器自身（如 xbreaker.Breaker.Do）决定，
+// 这里只避免对已知故障的下游浪费重试。
+//
+// 与 xbreaker.BreakerRetryer 的区别：BreakerRetryer 让每次尝试都经过熔断器并被其统计；
+// WithCircuitBreaker 只读取状态，适用于熔断器由其他调用路径（如共享的下游客户端）维护的场景。
+func WithCircuitBreaker(cb CircuitChecker) RetryerOption {
+	return func(r *Retryer) {
+		if cb != nil {
+			r.circuit = cb
+		}
+	}
+}
+
+// circuitGuard 单次 Do 调用内的熔断检查状态，nil 表示未配置熔断感知
+type circuitGuard struct {
+	cb      CircuitChecker
+	lastErr error
+}
+
+// newCircuitGuard 为一次 Do 调用创建熔断检查状态
+func (r *Retryer) newCircuitGuard() *circuitGuard {
+	if r.circuit == nil {
+		return nil
+	}
+	return &circuitGuard{cb: r.circuit}
+}
+
+// beforeRetry 在重试前检查熔断器（首次尝试不检查），开路时返回不可恢复错误。
+// 覆盖退避等待期间熔断器开路的情况。
+func (g *circuitGuard) beforeRetry() error {
+	if g == nil || g.lastErr == nil || !g.cb.IsOpen() {
+		return nil
+	}
+	return retry.Unrecoverable(fmt.Errorf("%w: %w", ErrCircuitOpen, g.lastErr))
+}
+
+// afterAttempt 记录本次尝试的错误；可恢复的失败发生后熔断器已开路时立即终止，
+// 不进入退避等待。不可恢复错误原样返回，避免误标为熔断导致的终止。
+func (g *circuitGuard) afterAttempt(err error) error {
+	if g == nil || err == nil || !IsRecoverable(err) {
+		return err
+	}
+	g.lastErr = err
+	if g.cb.IsOpen() {
+		return retry.Unrecoverable(fmt.Errorf("%w: %w", ErrCircuitOpen, err))
+	}
+	return err
+}
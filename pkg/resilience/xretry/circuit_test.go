@@ -0,0 +1,155 @@
+package xretry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCircuit 可控的熔断状态
+type fakeCircuit struct {
+	open atomic.Bool
+}
+
+func (c *fakeCircuit) IsOpen() bool { return c.open.Load() }
+
+func TestWithCircuitBreaker_StopsWhenOpenAfterFailure(t *testing.T) {
+	cb := &fakeCircuit{}
+	r := NewRetryer(
+		WithRetryPolicy(NewFixedRetry(5)),
+		WithBackoffPolicy(NewFixedBackoff(time.Hour)),
+		WithCircuitBreaker(cb),
+	)
+	errDown := errors.New("downstream down")
+	var attempts int
+
+	start := time.Now()
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		cb.open.Store(true) // 本次失败使熔断器开路
+		return errDown
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, errDown)
+	assert.Less(t, time.Since(start), time.Second, "must not wait for backoff")
+}
+
+func TestWithCircuitBreaker_StopsWhenOpenDuringBackoff(t *testing.T) {
+	cb := &fakeCircuit{}
+	var attempts int
+	r := NewRetryer(
+		WithRetryPolicy(NewFixedRetry(5)),
+		WithBackoffPolicy(NewFixedBackoff(10*time.Millisecond)),
+		WithCircuitBreaker(cb),
+		WithOnRetry(func(int, error) { cb.open.Store(true) }),
+	)
+	errDown := errors.New("downstream down")
+
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return errDown
+	})
+
+	assert.Equal(t, 1, attempts)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, errDown)
+}
+
+func TestWithCircuitBreaker_FirstAttemptNotChecked(t *testing.T) {
+	cb := &fakeCircuit{}
+	cb.open.Store(true)
+	r := NewRetryer(WithCircuitBreaker(cb))
+	var attempts int
+
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithCircuitBreaker_ClosedRetriesNormally(t *testing.T) {
+	cb := &fakeCircuit{}
+	r := NewRetryer(
+		WithRetryPolicy(NewFixedRetry(3)),
+		WithBackoffPolicy(NewNoBackoff()),
+		WithCircuitBreaker(cb),
+	)
+	var attempts int
+
+	err := r.Do(context.Background(), func(context.Context) error {
+		attempts++
+		return errors.New("temporary")
+	})
+
+	assert.Equal(t, 3, attempts)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestWithCircuitBreaker_PermanentErrorNotWrapped(t *testing.T) {
+	cb := &fakeCircuit{}
+	cb.open.Store(true)
+	r := NewRetryer(WithCircuitBreaker(cb))
+	errBad := errors.New("bad request")
+
+	err := r.Do(context.Background(), func(context.Context) error {
+		return Unrecoverable(errBad)
+	})
+
+	assert.ErrorIs(t, err, errBad)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestWithCircuitBreaker_DoWithResult(t *testing.T) {
+	cb := &fakeCircuit{}
+	r := NewRetryer(
+		WithRetryPolicy(NewFixedRetry(5)),
+		WithBackoffPolicy(NewNoBackoff()),
+		WithCircuitBreaker(cb),
+	)
+	var attempts int
+
+	_, err := DoWithResult(context.Background(), r, func(context.Context) (int, error) {
+		attempts++
+		if attempts == 2 {
+			cb.open.Store(true)
+		}
+		return 0, errors.New("temporary")
+	})
+
+	assert.Equal(t, 2, attempts)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+}
+
+func TestWithCircuitBreaker_Retrier(t *testing.T) {
+	cb := &fakeCircuit{}
+	r := NewRetryer(
+		WithRetryPolicy(NewFixedRetry(5)),
+		WithBackoffPolicy(NewNoBackoff()),
+		WithCircuitBreaker(cb),
+	)
+	var attempts int
+
+	err := r.Retrier(context.Background()).Do(func() error {
+		attempts++
+		cb.open.Store(true)
+		return errors.New("temporary")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWithCircuitBreaker_NilIgnored(t *testing.T) {
+	r := NewRetryer(WithCircuitBreaker(nil))
+	assert.Nil(t, r.circuit)
+}
@@ -79,6 +79,19 @@
 //
 // 详细用法参见各函数文档和 example_test.go。
 //
+// # 熔断感知
+//
+// WithCircuitBreaker 在每次失败后、每次重试前检查熔断器，开路时立即停止重试，
+// 返回同时包装 ErrCircuitOpen 和最后一次错误的错误：
+//
+//	breaker := xbreaker.NewBreaker("inventory")
+//	r := xretry.NewRetryer(xretry.WithCircuitBreaker(breaker))
+//	err := r.Do(ctx, callInventory)
+//	if errors.Is(err, xretry.ErrCircuitOpen) { /* 下游已熔断 */ }
+//
+// 集成接口为 CircuitChecker（IsOpen() bool），*xbreaker.Breaker 已实现。
+// 如需每次尝试都经过熔断器并被其统计，使用 xbreaker.BreakerRetryer。
+//
 // # 抖动（Jitter）
 //
 // ExponentialBackoff 默认 jitter=0.1（±10% 乘性抖动）。
@@ -43,6 +43,7 @@ type Retryer struct {
 	retryPolicy   RetryPolicy
 	backoffPolicy BackoffPolicy
 	onRetry       func(attempt int, err error)
+	circuit       CircuitChecker
 }
 
 // RetryerOption 执行器配置选项
@@ -109,6 +110,7 @@ func (r *Retryer) Do(ctx context.Context, fn func(ctx context.Context) error) er
 	}
 	// 构建 retry-go 的选项
 	opts := r.buildOptions(ctx)
+	guard := r.newCircuitGuard()
 
 	// 执行重试
 	// 设计决策: 在 fn 执行前检查 ctx，防止 0 延迟场景下 retry-go 的
@@ -118,7 +120,10 @@ func (r *Retryer) Do(ctx context.Context, fn func(ctx context.Context) error) er
 		if err := ctx.Err(); err != nil {
 			return retry.Unrecoverable(err)
 		}
-		return fn(ctx)
+		if err := guard.beforeRetry(); err != nil {
+			return err
+		}
+		return guard.afterAttempt(fn(ctx))
 	})
 }
 
@@ -141,14 +146,19 @@ func DoWithResult[T any](ctx context.Context, r *Retryer, fn func(ctx context.Co
 	}
 	// 构建 retry-go 的选项
 	opts := r.buildOptions(ctx)
+	guard := r.newCircuitGuard()
 
 	// 执行重试（同 Do：fn 前检查 ctx 防止 0 延迟取消竞争）
 	return retry.NewWithData[T](opts...).Do(func() (T, error) {
+		var zero T
 		if err := ctx.Err(); err != nil {
-			var zero T
 			return zero, retry.Unrecoverable(err)
 		}
-		return fn(ctx)
+		if err := guard.beforeRetry(); err != nil {
+			return zero, err
+		}
+		result, err := fn(ctx)
+		return result, guard.afterAttempt(err)
 	})
 }
 
@@ -202,6 +212,10 @@ func (r *Retryer) buildOptions(ctx context.Context) []Option {
 		if maxAttempts > 0 && count >= maxAttempts {
 			return false
 		}
+		// 熔断器开路时停止重试（Do 路径已由 circuitGuard 提前终止，此处覆盖 Retrier() 路径）
+		if r.circuit != nil && r.circuit.IsOpen() {
+			return false
+		}
 		// 委托给 RetryPolicy.ShouldRetry，传递完整的 ctx 和 attempt 参数
 		return retryPolicy.ShouldRetry(ctx, count, err)
 	}))
@@ -9,7 +9,7 @@
 //
 // # 核心组件
 //
-//   - Redis：暴露 go-redis UniversalClient，提供分布式锁
+//   - Redis：暴露 go-redis UniversalClient，提供分布式锁、原子 GetOrSet 与按前缀批量删除
 //   - Memory：暴露 ristretto Cache，提供统计信息
 //   - Loader：Cache-Aside 模式加载器，内置 singleflight + 分布式锁防击穿
 //
@@ -23,7 +23,7 @@
 //
 // # Context 安全
 //
-// 所有接受 context.Context 的公开入口方法（Load, LoadHash, Lock）
+// 所有接受 context.Context 的公开入口方法（Load, LoadHash, Lock, GetOrSet, DeletePrefix）
 // 均在入口处检查 nil context，传入 nil 会返回 ErrNilContext 而非 panic。
 //
 // # Loader Context 处理
@@ -55,6 +55,21 @@
 // 使用 WithTTLJitter 可为缓存 TTL 添加随机抖动，防止大量 key 同时过期（缓存雪崩）。
 // 例如 WithTTLJitter(0.1) 时，1 小时 TTL 会被随机化到约 57-63 分钟。
 //
+// # 提前刷新（Refresh-ahead）
+//
+// 使用 WithRefreshAhead 可让热点 key 在过期前被后台异步刷新，避免过期瞬间的阻塞回源。
+// 例如 WithRefreshAhead(0.2) 时，剩余 TTL 不足 ttl 的 20% 的命中会立即返回缓存值，
+// 并触发一次后台刷新（与回源共享 singleflight 及分布式锁，受 LoadTimeout 约束）。
+// 后台刷新失败时保留旧值，仅记录日志。仅作用于 Load，LoadHash 不受影响。
+//
+// # 版本号一致性
+//
+// LoadVersioned 写入时在缓存值头部附带版本号，读取时与 Redis 中的当前版本号
+// （"{VersionKeyPrefix}{key}"，默认前缀 "ver:"）比对，不一致即视为过期并重新加载。
+// 数据源更新后调用 BumpVersion，所有实例的下一次读取都会回源，比等待 TTL 更精确。
+// 版本号在回源前读取，回源期间发生的 BumpVersion 会使本次写入立即过期，避免旧数据被当作新版本缓存。
+// 版本化缓存值与 Load 写入的格式不兼容，同一 key 不应混用两种方法。
+//
 // # 分布式锁
 //
 // 锁 key 格式：lock:{prefix}{key}
@@ -12,7 +12,7 @@ var (
 
 	// ErrNilContext 表示传入的 context 为 nil。
 	// go-redis 内部会直接使用 ctx 而不做 nil 检查，传入 nil 会导致 panic。
-	// 所有接受 context.Context 的公开入口方法（Load, LoadHash, Lock）
+	// 所有接受 context.Context 的公开入口方法（Load, LoadHash, Lock, GetOrSet, DeletePrefix）
 	// 均在入口处进行 fail-fast 检查。
 	ErrNilContext = errors.New("xcache: nil context")
 
@@ -34,6 +34,9 @@ var (
 
 	// ErrInvalidLockTTL 表示锁的 TTL 无效。
 	ErrInvalidLockTTL = errors.New("xcache: lock TTL must be positive")
+
+	// ErrInvalidTTL 表示缓存写入的 TTL 无效（为负值）。
+	ErrInvalidTTL = errors.New("xcache: TTL must not be negative")
 )
 
 // =============================================================================
@@ -69,6 +69,21 @@ type Loader interface {
 	// 注意：singleflight 去重基于 key+field 组合，不包含 ttl。
 	// 同一 key+field 的并发请求（即使 ttl 不同）只会触发一次回源。
 	LoadHash(ctx context.Context, key, field string, loader LoadFunc, ttl time.Duration) ([]byte, error)
+
+	// LoadVersioned 与 Load 相同，但缓存值带版本号，读取时与 Redis 中的当前版本号比对。
+	// 版本不一致视为过期，按未命中处理（singleflight、分布式锁流程与 Load 一致）。
+	//
+	// 版本号存储在 "{VersionKeyPrefix}{key}"，不存在时视为版本 0，由 BumpVersion 递增。
+	// 回源前读取版本号并随值写入：回源期间发生的 BumpVersion 会使本次写入立即过期，
+	// 因此数据源更新后调用 BumpVersion，所有实例下次读取都会重新加载，而无需等待 TTL。
+	//
+	// 缓存值带 9 字节版本头，与 Load 写入的格式不兼容，同一 key 不应混用两种方法。
+	// 每次读取多一次版本号 GET（同一 Pipeline 内），适合配置等读多写少、需要较强一致性的数据。
+	LoadVersioned(ctx context.Context, key string, loader LoadFunc, ttl time.Duration) ([]byte, error)
+
+	// BumpVersion 递增 key 的版本号并返回新版本，使所有实例中该 key 的版本化缓存失效。
+	// 应在数据源更新成功后调用。版本号 key 不设置过期时间。
+	BumpVersion(ctx context.Context, key string) (int64, error)
 }
 
 // =============================================================================
@@ -160,6 +175,18 @@ type LoaderOptions struct {
 	// 默认为 true（滑动过期）。
 	HashTTLRefresh bool
 
+	// RefreshAhead 控制 Load 命中时提前异步刷新的阈值比例，取值范围 [0.0, 1.0)。
+	// 当命中 key 的剩余 TTL 低于 RefreshAhead * ttl 时，立即返回缓存值，
+	// 同时在后台触发一次回源并重写缓存，避免热点 key 过期瞬间的阻塞回源。
+	// 例如 RefreshAhead=0.2、ttl=1h 时，剩余 TTL 不足 12 分钟的命中会触发后台刷新。
+	// 默认为 0（不启用）。仅作用于 Load，LoadHash 不受影响。
+	RefreshAhead float64
+
+	// VersionKeyPrefix LoadVersioned/BumpVersion 使用的版本号 key 前缀。
+	// 版本号 key 格式为 "{VersionKeyPrefix}{key}"。
+	// 默认为 "ver:"。
+	VersionKeyPrefix string
+
 	// OnCacheSetError 缓存写入失败回调钩子。
 	// 当缓存写入失败时调用，用于监控告警或自定义处理。
 	// 默认为 nil，仅记录日志。
@@ -178,6 +205,9 @@ func (o *LoaderOptions) validate() error {
 	if o.ExternalLock != nil && !o.EnableDistributedLock {
 		return fmt.Errorf("%w: ExternalLock is set but EnableDistributedLock is false", ErrInvalidConfig)
 	}
+	if o.RefreshAhead < 0 || o.RefreshAhead >= 1 {
+		return fmt.Errorf("%w: RefreshAhead (%v) must be in [0, 1)", ErrInvalidConfig, o.RefreshAhead)
+	}
 	if o.EnableDistributedLock && o.DistributedLockTTL <= 0 {
 		return fmt.Errorf("%w: %w", ErrInvalidConfig, ErrInvalidLockTTL)
 	}
@@ -201,6 +231,7 @@ func defaultLoaderOptions() *LoaderOptions {
 		LoadTimeout:              RecommendedLoadTimeout,        // 默认启用超时保护，防止 goroutine 泄漏
 		MaxRetryAttempts:         10,
 		HashTTLRefresh:           true,
+		VersionKeyPrefix:         "ver:",
 		Logger:                   slog.Default(),
 	}
 }
@@ -311,6 +342,32 @@ func WithHashTTLRefresh(refresh bool) LoaderOption {
 	}
 }
 
+// WithRefreshAhead 设置 Load 的提前刷新阈值比例，fraction 范围为 [0.0, 1.0)，
+// 超出范围时 NewLoader 返回 ErrInvalidConfig。
+//
+// 启用后，Load 命中时会在同一 roundtrip 内读取 key 的剩余 TTL（GET + PTTL Pipeline）；
+// 剩余 TTL 低于 fraction * ttl 时立即返回缓存值，并在后台异步刷新：
+//   - 后台刷新与未命中回源共享同一 singleflight key，同一 key 同时最多一个刷新在进行
+//   - 启用分布式锁时，刷新同样需要获取锁；锁被其他实例持有时放弃本次刷新，
+//     不等待、不回源（key 恰在刷新期间过期时按未命中处理）
+//   - 刷新使用脱离调用方取消链的 context，受 LoadTimeout 约束，不阻塞调用方
+//   - 刷新失败时保留旧值（不删除、不改写 TTL），仅记录 Warn 日志，
+//     旧值自然过期前的后续命中会再次尝试刷新
+//
+// ttl <= 0 的 Load 调用不触发提前刷新。默认为 0（不启用）。
+func WithRefreshAhead(fraction float64) LoaderOption {
+	return func(o *LoaderOptions) {
+		o.RefreshAhead = fraction
+	}
+}
+
+// WithVersionKeyPrefix 设置版本号 key 的前缀，用于 LoadVersioned/BumpVersion。
+func WithVersionKeyPrefix(prefix string) LoaderOption {
+	return func(o *LoaderOptions) {
+		o.VersionKeyPrefix = prefix
+	}
+}
+
 // WithOnCacheSetError 设置缓存写入失败回调钩子。
 // 当缓存写入失败时调用，用于监控告警或自定义处理。
 // 注意：此钩子在请求路径上同步执行，应避免耗时操作。
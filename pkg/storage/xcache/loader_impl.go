@@ -154,6 +154,9 @@ type loader struct {
 	cache   Redis
 	options *LoaderOptions
 	group   singleflight.Group
+
+	// versionGroup LoadVersioned 专用的 singleflight 组
+	versionGroup singleflight.Group
 }
 
 // newLoader 创建 Loader 实例。
@@ -179,8 +182,8 @@ func (l *loader) Load(ctx context.Context, key string, loadFn LoadFunc, ttl time
 		return nil, ErrNilLoader
 	}
 
-	// 1. 尝试从缓存获取
-	value, err := l.cache.Client().Get(ctx, key).Bytes()
+	// 1. 尝试从缓存获取（启用 RefreshAhead 时命中可能触发后台刷新）
+	value, err := l.getWithRefreshAhead(ctx, key, loadFn, ttl)
 	if err == nil {
 		return value, nil
 	}
@@ -292,6 +295,94 @@ func (l *loader) loadWithSingleflight(ctx context.Context, key string, loadFn Lo
 	}
 }
 
+// getWithRefreshAhead 查询缓存，未启用 RefreshAhead 时等价于 GET。
+// 启用时通过 Pipeline 在同一 roundtrip 内读取剩余 TTL，
+// 命中且剩余 TTL 低于 RefreshAhead * ttl 时触发后台刷新，当前调用直接返回缓存值。
+func (l *loader) getWithRefreshAhead(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	if l.options.RefreshAhead <= 0 || ttl <= 0 {
+		return l.cache.Client().Get(ctx, key).Bytes()
+	}
+
+	pipe := l.cache.Client().Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	pttlCmd := pipe.PTTL(ctx, key)
+	// Exec 返回首个失败命令的错误（包括 redis.Nil），各命令结果由 cmd 自行携带，此处忽略。
+	_, _ = pipe.Exec(ctx)
+
+	value, err := getCmd.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// PTTL 失败或 key 无过期时间（-1）时不刷新，不影响本次命中。
+	remaining, ttlErr := pttlCmd.Result()
+	if ttlErr == nil && remaining > 0 && remaining < time.Duration(float64(ttl)*l.options.RefreshAhead) {
+		l.refreshAsync(ctx, key, loadFn, ttl)
+	}
+	return value, nil
+}
+
+// refreshAsync 在后台异步刷新缓存，不阻塞调用方。
+//
+// 设计决策: 复用 Load 的 singleflight 组与 key，而非单独的刷新组。
+// 这样同一 key 同时最多一个刷新在进行，且刷新期间恰好过期引发的未命中请求
+// 会合并到该刷新上等待新值，而不是再次回源。
+// DoChan 返回的 channel 带 1 个缓冲，丢弃不读不会导致 goroutine 泄漏。
+func (l *loader) refreshAsync(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) {
+	l.group.DoChan(key, func() (any, error) {
+		refreshCtx, cancel := contextWithIndependentTimeout(ctx, 0)
+		defer cancel()
+		value, err := l.refresh(refreshCtx, key, loadFn, ttl)
+		if err != nil {
+			// 刷新失败保留旧值：缓存中的 key 未被修改，过期前的命中继续返回旧值。
+			l.logWarn("xcache: refresh-ahead failed, keeping stale value", "key", key, "error", err)
+		}
+		return value, err
+	})
+}
+
+// refresh 执行一次提前刷新：跳过缓存 double-check，直接回源并重写缓存。
+// 启用分布式锁时需先获取锁，获取失败时放弃本次刷新，见 skipRefresh。
+func (l *loader) refresh(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	if !l.options.EnableDistributedLock {
+		return l.loadAndCache(ctx, key, loadFn, ttl)
+	}
+
+	lockKey := l.options.DistributedLockKeyPrefix + key
+	unlock, lockErr := l.acquireLock(ctx, lockKey)
+	if lockErr != nil {
+		return l.skipRefresh(ctx, key, loadFn, ttl, lockErr)
+	}
+
+	defer func() {
+		unlockCtx, unlockCancel := context.WithTimeout(contextDetached(ctx), unlockTimeout)
+		defer unlockCancel()
+		if unlockErr := unlock(unlockCtx); unlockErr != nil {
+			l.logUnlockError(lockKey, unlockErr)
+		}
+	}()
+
+	return l.loadAndCache(ctx, key, loadFn, ttl)
+}
+
+// skipRefresh 在刷新未获取到分布式锁时放弃本次刷新，不回源。
+//
+// 设计决策: 锁被其他实例持有时只读取一次缓存（旧值或其他实例刷新后的新值），
+// 不进入 waitAndRetryGet 的退避等待，也不在读取失败时降级回源；
+// 仅当 key 在刷新期间已过期时按未命中处理——此时同 key 的未命中调用可能
+// 经 singleflight 共享本次结果，需要与 loadWithLock 一致地等待并回源。
+// 锁的运行时错误同样放弃刷新，由 refreshAsync 记录 Warn 日志。
+func (l *loader) skipRefresh(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration, lockErr error) ([]byte, error) {
+	if !errors.Is(lockErr, ErrLockFailed) {
+		return nil, lockErr
+	}
+	value, err := l.cache.Client().Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return l.waitAndRetryGet(ctx, key, loadFn, ttl)
+	}
+	return value, err
+}
+
 // loadWithDistLock 可选使用分布式锁加载。
 func (l *loader) loadWithDistLock(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
 	// 再次检查缓存（double-check）
@@ -1110,3 +1110,189 @@ func TestWithMaxRetryAttempts_ExactUpperBound(t *testing.T) {
 	WithMaxRetryAttempts(MaxMaxRetryAttempts)(opts)
 	assert.Equal(t, MaxMaxRetryAttempts, opts.MaxRetryAttempts)
 }
+
+// =============================================================================
+// RefreshAhead 测试
+// =============================================================================
+
+func TestNewLoader_InvalidRefreshAhead_ReturnsError(t *testing.T) {
+	cache, _ := newTestRedis(t)
+
+	for _, fraction := range []float64{-0.1, 1, 1.5} {
+		_, err := NewLoader(cache, WithRefreshAhead(fraction))
+		assert.ErrorIs(t, err, ErrInvalidConfig, "fraction=%v", fraction)
+	}
+}
+
+func TestLoader_Load_WithRefreshAhead_NearExpiry_RefreshesInBackground(t *testing.T) {
+	// Given - 缓存命中，剩余 TTL 低于阈值
+	cache, mr := newTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Client().Set(ctx, "hot", "stale", 10*time.Second).Err())
+	mr.FastForward(9 * time.Second) // 剩余 1s < 0.2 * 10s
+
+	loader, err := NewLoader(cache, WithRefreshAhead(0.2))
+	require.NoError(t, err)
+
+	var loadCount atomic.Int32
+	loadFn := func(ctx context.Context) ([]byte, error) {
+		loadCount.Add(1)
+		return []byte("fresh"), nil
+	}
+
+	// When
+	value, err := loader.Load(ctx, "hot", loadFn, 10*time.Second)
+
+	// Then - 立即返回旧值，后台刷新写入新值并重置 TTL
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stale"), value)
+	assert.Eventually(t, func() bool {
+		v, getErr := mr.Get("hot")
+		return getErr == nil && v == "fresh"
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, int32(1), loadCount.Load())
+	assert.Greater(t, mr.TTL("hot"), 5*time.Second)
+}
+
+func TestLoader_Load_WithRefreshAhead_FarFromExpiry_DoesNotRefresh(t *testing.T) {
+	// Given - 剩余 TTL 充足
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Client().Set(ctx, "cold", "cached", 10*time.Second).Err())
+
+	loader, err := NewLoader(cache, WithRefreshAhead(0.2))
+	require.NoError(t, err)
+
+	var loadCount atomic.Int32
+	loadFn := func(ctx context.Context) ([]byte, error) {
+		loadCount.Add(1)
+		return []byte("fresh"), nil
+	}
+
+	// When
+	value, err := loader.Load(ctx, "cold", loadFn, 10*time.Second)
+
+	// Then
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cached"), value)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(0), loadCount.Load())
+}
+
+func TestLoader_Load_WithRefreshAhead_RefreshFails_KeepsStaleValue(t *testing.T) {
+	// Given
+	cache, mr := newTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Client().Set(ctx, "hot", "stale", 10*time.Second).Err())
+	mr.FastForward(9 * time.Second)
+
+	loader, err := NewLoader(cache, WithRefreshAhead(0.2))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	loadFn := func(ctx context.Context) ([]byte, error) {
+		defer close(done)
+		return nil, errors.New("backend down")
+	}
+
+	// When
+	value, err := loader.Load(ctx, "hot", loadFn, 10*time.Second)
+
+	// Then - 调用方不感知刷新失败，缓存中仍为旧值
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stale"), value)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("background refresh not triggered")
+	}
+	assert.Eventually(t, func() bool {
+		v, getErr := mr.Get("hot")
+		return getErr == nil && v == "stale"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLoader_Load_WithRefreshAhead_LockHeldElsewhere_SkipsReload(t *testing.T) {
+	// Given - 其他实例持有该 key 的分布式锁
+	cache, mr := newTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Client().Set(ctx, "hot", "stale", 10*time.Second).Err())
+	mr.FastForward(9 * time.Second)
+
+	unlock, err := cache.Lock(ctx, "loader:hot", time.Minute)
+	require.NoError(t, err)
+	defer func() { _ = unlock(ctx) }()
+
+	loader, err := NewLoader(cache, WithRefreshAhead(0.2), WithDistributedLock(true))
+	require.NoError(t, err)
+
+	var loadCount atomic.Int32
+	loadFn := func(ctx context.Context) ([]byte, error) {
+		loadCount.Add(1)
+		return []byte("fresh"), nil
+	}
+
+	// When
+	value, err := loader.Load(ctx, "hot", loadFn, 10*time.Second)
+
+	// Then - 锁竞争时放弃刷新，读取到的仍是旧值
+	require.NoError(t, err)
+	assert.Equal(t, []byte("stale"), value)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), loadCount.Load())
+	v, err := mr.Get("hot")
+	require.NoError(t, err)
+	assert.Equal(t, "stale", v)
+}
+
+func TestLoader_Refresh_LockContended_DoesNotLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		lockErr error
+		getErr  string
+	}{
+		{"GET 失败不降级回源", ErrLockFailed, "boom"},
+		{"锁运行时错误放弃刷新", errors.New("lock backend down"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache, mr := newTestRedis(t)
+			ctx := context.Background()
+			require.NoError(t, cache.Client().Set(ctx, "hot", "stale", 10*time.Second).Err())
+
+			l, err := NewLoader(cache, WithRefreshAhead(0.2), WithDistributedLock(true),
+				WithExternalLock(func(context.Context, string, time.Duration) (Unlocker, error) {
+					return nil, tt.lockErr
+				}))
+			require.NoError(t, err)
+			if tt.getErr != "" {
+				mr.SetError(tt.getErr)
+			}
+
+			var loadCount atomic.Int32
+			loadFn := func(ctx context.Context) ([]byte, error) {
+				loadCount.Add(1)
+				return []byte("fresh"), nil
+			}
+
+			_, err = l.(*loader).refresh(ctx, "hot", loadFn, 10*time.Second)
+			assert.Error(t, err)
+			assert.Equal(t, int32(0), loadCount.Load())
+		})
+	}
+}
+
+func TestLoader_Refresh_LockContended_ExpiredKeyFallsBackToMissPath(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	l, err := NewLoader(cache, WithDistributedLock(true), WithMaxRetryAttempts(1),
+		WithExternalLock(func(context.Context, string, time.Duration) (Unlocker, error) {
+			return nil, ErrLockFailed
+		}))
+	require.NoError(t, err)
+
+	value, err := l.(*loader).refresh(context.Background(), "gone", func(context.Context) ([]byte, error) {
+		return []byte("fresh"), nil
+	}, 10*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("fresh"), value)
+}
@@ -0,0 +1,253 @@
+package xcache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// =============================================================================
+// 版本号一致性（LoadVersioned / BumpVersion）
+// =============================================================================
+
+const (
+	// versionedMarker 版本化缓存值的格式标记，用于区分 Load 写入的裸值。
+	versionedMarker byte = 0x01
+
+	// versionedHeaderLen 版本化缓存值的头部长度：1 字节标记 + 8 字节大端版本号。
+	versionedHeaderLen = 1 + 8
+)
+
+// encodeVersioned 将版本号编码到缓存值头部。
+func encodeVersioned(version int64, value []byte) []byte {
+	buf := make([]byte, versionedHeaderLen+len(value))
+	buf[0] = versionedMarker
+	binary.BigEndian.PutUint64(buf[1:versionedHeaderLen], uint64(version)) //nolint:gosec // 版本号由 INCR 生成，恒为非负
+	copy(buf[versionedHeaderLen:], value)
+	return buf
+}
+
+// decodeVersioned 解析缓存值中的版本号，格式不符时 ok 为 false。
+func decodeVersioned(raw []byte) (version int64, value []byte, ok bool) {
+	if len(raw) < versionedHeaderLen || raw[0] != versionedMarker {
+		return 0, nil, false
+	}
+	return int64(binary.BigEndian.Uint64(raw[1:versionedHeaderLen])), raw[versionedHeaderLen:], true //nolint:gosec // 与 encodeVersioned 对称
+}
+
+// versionKey 返回 key 对应的版本号存储 key。
+func (l *loader) versionKey(key string) string {
+	return l.options.VersionKeyPrefix + key
+}
+
+// parseVersion 解析版本号 GET 结果，版本号 key 不存在视为版本 0。
+func parseVersion(cmd *redis.StringCmd) (int64, error) {
+	version, err := cmd.Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// BumpVersion 递增 key 的版本号，使所有实例中该 key 的版本化缓存失效。
+func (l *loader) BumpVersion(ctx context.Context, key string) (int64, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if l.cache == nil {
+		return 0, ErrNilClient
+	}
+	if key == "" {
+		return 0, ErrEmptyKey
+	}
+	version, err := l.cache.Client().Incr(ctx, l.versionKey(key)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("xcache: bump version: %w", err)
+	}
+	return version, nil
+}
+
+// LoadVersioned 加载带版本号校验的缓存数据。
+func (l *loader) LoadVersioned(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if l.cache == nil {
+		return nil, ErrNilClient
+	}
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if loadFn == nil {
+		return nil, ErrNilLoader
+	}
+
+	// 1. 同一 roundtrip 读取缓存值与当前版本号，版本一致才视为命中
+	value, err := l.getVersioned(ctx, key)
+	if err == nil {
+		return value, nil
+	}
+
+	if !errors.Is(err, redis.Nil) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if isCacheLifecycleErr(err) {
+			return nil, err
+		}
+	}
+
+	// 2. 未命中、版本过期或 Redis 错误，使用 singleflight 或直接回源
+	if l.options.EnableSingleflight {
+		return l.loadVersionedWithSingleflight(ctx, key, loadFn, ttl)
+	}
+	return l.loadVersionedWithDistLock(ctx, key, loadFn, ttl)
+}
+
+// getVersioned 读取缓存值并校验版本号。
+// 未命中或版本不一致时返回 redis.Nil，与 GET 未命中语义一致，便于复用等待重试流程。
+func (l *loader) getVersioned(ctx context.Context, key string) ([]byte, error) {
+	pipe := l.cache.Client().Pipeline()
+	getCmd := pipe.Get(ctx, key)
+	verCmd := pipe.Get(ctx, l.versionKey(key))
+	// Exec 返回首个失败命令的错误（包括 redis.Nil），各命令结果由 cmd 自行携带，此处忽略。
+	_, _ = pipe.Exec(ctx)
+
+	raw, err := getCmd.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	current, err := parseVersion(verCmd)
+	if err != nil {
+		return nil, err
+	}
+	version, value, ok := decodeVersioned(raw)
+	if !ok || version != current {
+		return nil, redis.Nil
+	}
+	return value, nil
+}
+
+// loadVersionedWithSingleflight 使用 singleflight 加载版本化数据。
+//
+// 设计决策: 使用独立的 singleflight 组，避免同一 key 被 Load 与 LoadVersioned
+// 混用时两种编码的结果相互串用。
+func (l *loader) loadVersionedWithSingleflight(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	ch := l.versionGroup.DoChan(key, func() (any, error) {
+		sfCtx, sfCancel := contextWithIndependentTimeout(ctx, 0)
+		defer sfCancel()
+		return l.loadVersionedWithDistLock(sfCtx, key, loadFn, ttl)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		value, ok := result.Val.([]byte)
+		if !ok {
+			return nil, errUnexpectedResultType
+		}
+		return value, nil
+	}
+}
+
+// loadVersionedWithDistLock 可选使用分布式锁加载版本化数据。
+// 流程与 loadWithDistLock 一致：double-check → 可选加锁 → 加锁后再检查 → 回源写入。
+func (l *loader) loadVersionedWithDistLock(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	if value, done, err := l.checkCacheVersioned(ctx, key, loadFn, ttl); done {
+		return value, err
+	}
+	if !l.options.EnableDistributedLock {
+		return l.loadVersionedAndCache(ctx, key, loadFn, ttl)
+	}
+
+	lockKey := l.options.DistributedLockKeyPrefix + key
+	unlock, lockErr := l.acquireLock(ctx, lockKey)
+	if lockErr != nil {
+		return l.handleLockError(lockErr, lockKey, func() ([]byte, error) {
+			return l.waitAndRetry(ctx,
+				func(ctx context.Context) ([]byte, error) {
+					return l.getVersioned(ctx, key)
+				},
+				func(ctx context.Context) ([]byte, error) {
+					return l.loadVersionedAndCache(ctx, key, loadFn, ttl)
+				},
+			)
+		})
+	}
+
+	defer func() {
+		unlockCtx, unlockCancel := context.WithTimeout(contextDetached(ctx), unlockTimeout)
+		defer unlockCancel()
+		if unlockErr := unlock(unlockCtx); unlockErr != nil {
+			l.logUnlockError(lockKey, unlockErr)
+		}
+	}()
+
+	if value, done, err := l.checkCacheVersioned(ctx, key, loadFn, ttl); done {
+		return value, err
+	}
+	return l.loadVersionedAndCache(ctx, key, loadFn, ttl)
+}
+
+// checkCacheVersioned 检查版本化缓存，返回 (value, done, error)，语义同 checkCacheGet。
+func (l *loader) checkCacheVersioned(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, bool, error) {
+	value, err := l.getVersioned(ctx, key)
+	if err == nil {
+		return value, true, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, true, ctxErr
+		}
+		if isCacheLifecycleErr(err) {
+			return nil, true, err
+		}
+		val, loadErr := l.loadVersionedAndCache(ctx, key, loadFn, ttl)
+		return val, true, loadErr
+	}
+	return nil, false, nil
+}
+
+// loadVersionedAndCache 回源并以回源前读取的版本号写入缓存。
+//
+// 设计决策: 版本号在回源之前读取。若回源期间其他实例 BumpVersion，
+// 写入的值带旧版本号，下次读取即判定过期并重新加载，不会把旧数据当作新版本缓存。
+// 读取版本号失败时仍返回回源结果但不写缓存，避免写入版本未知的数据。
+func (l *loader) loadVersionedAndCache(ctx context.Context, key string, loadFn LoadFunc, ttl time.Duration) ([]byte, error) {
+	version, verErr := parseVersion(l.cache.Client().Get(ctx, l.versionKey(key)))
+
+	loadCtx, cancel := applyLoadTimeout(ctx, l.options.LoadTimeout)
+	defer cancel()
+
+	value, err := safeLoadFn(loadCtx, loadFn)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheTTL := l.applyTTLJitter(ttl)
+	if cacheTTL < 0 {
+		return value, nil
+	}
+	if verErr != nil {
+		l.logWarn("xcache: read version failed, skip cache write", "key", key, "error", verErr)
+		l.onCacheSetError(ctx, key, verErr)
+		return value, nil
+	}
+
+	writeCtx, writeCancel := context.WithTimeout(contextDetached(ctx), defaultOperationTimeout)
+	defer writeCancel()
+
+	if setErr := l.cache.Client().Set(writeCtx, key, encodeVersioned(version, value), cacheTTL).Err(); setErr != nil {
+		l.logWarn("xcache: cache set failed", "key", key, "error", setErr)
+		l.onCacheSetError(writeCtx, key, setErr)
+	}
+	return value, nil
+}
@@ -0,0 +1,252 @@
+package xcache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// LoadVersioned / BumpVersion 测试
+// =============================================================================
+
+func TestEncodeDecodeVersioned(t *testing.T) {
+	raw := encodeVersioned(42, []byte("payload"))
+	version, value, ok := decodeVersioned(raw)
+	require.True(t, ok)
+	assert.Equal(t, int64(42), version)
+	assert.Equal(t, []byte("payload"), value)
+
+	_, _, ok = decodeVersioned([]byte("plain value"))
+	assert.False(t, ok, "values written by Load must not decode")
+	_, _, ok = decodeVersioned([]byte{versionedMarker, 0})
+	assert.False(t, ok, "truncated header must not decode")
+}
+
+func TestLoader_LoadVersioned_CachesUntilVersionBumped(t *testing.T) {
+	// Given
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	loadFn := func(context.Context) ([]byte, error) {
+		n := calls.Add(1)
+		return []byte{'v', byte('0' + n)}, nil
+	}
+
+	// When: 首次加载后再次读取
+	v1, err := loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	v1Again, err := loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+
+	// Then: 版本未变，命中缓存
+	assert.Equal(t, []byte("v1"), v1)
+	assert.Equal(t, v1, v1Again)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// When: 递增版本号
+	version, err := loader.BumpVersion(ctx, "cfg")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	// Then: 缓存过期，重新加载
+	v2, err := loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2"), v2)
+	assert.Equal(t, int32(2), calls.Load())
+
+	raw, err := cache.Client().Get(ctx, "cfg").Bytes()
+	require.NoError(t, err)
+	stored, _, ok := decodeVersioned(raw)
+	require.True(t, ok)
+	assert.Equal(t, int64(1), stored)
+}
+
+func TestLoader_LoadVersioned_BumpFromAnotherInstance(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	reader, err := NewLoader(cache)
+	require.NoError(t, err)
+	writer, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	data := []byte("old")
+	loadFn := func(context.Context) ([]byte, error) { return data, nil }
+
+	got, err := reader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), got)
+
+	data = []byte("new")
+	_, err = writer.BumpVersion(ctx, "cfg")
+	require.NoError(t, err)
+
+	got, err = reader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new"), got)
+}
+
+func TestLoader_LoadVersioned_BumpDuringLoadInvalidatesWrite(t *testing.T) {
+	// Given: 回源期间发生 BumpVersion
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	loadFn := func(ctx context.Context) ([]byte, error) {
+		if calls.Add(1) == 1 {
+			_, bumpErr := loader.BumpVersion(ctx, "cfg")
+			require.NoError(t, bumpErr)
+		}
+		return []byte("data"), nil
+	}
+
+	_, err = loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+
+	// Then: 首次写入带旧版本号，下次读取重新加载
+	_, err = loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+
+	_, err = loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestLoader_LoadVersioned_IgnoresUnversionedValue(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	require.NoError(t, cache.Client().Set(ctx, "cfg", "raw", 0).Err())
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	got, err := loader.LoadVersioned(ctx, "cfg", func(context.Context) ([]byte, error) {
+		return []byte("fresh"), nil
+	}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("fresh"), got)
+}
+
+func TestLoader_LoadVersioned_Singleflight(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	loadFn := func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		<-release
+		return []byte("v"), nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			got, loadErr := loader.LoadVersioned(context.Background(), "cfg", loadFn, time.Hour)
+			assert.NoError(t, loadErr)
+			assert.Equal(t, []byte("v"), got)
+		})
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLoader_LoadVersioned_WithDistributedLock(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	ctx := context.Background()
+	loader, err := NewLoader(cache, WithSingleflight(false), WithDistributedLock(true))
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	loadFn := func(context.Context) ([]byte, error) {
+		calls.Add(1)
+		return []byte("v"), nil
+	}
+	for range 3 {
+		got, loadErr := loader.LoadVersioned(ctx, "cfg", loadFn, time.Hour)
+		require.NoError(t, loadErr)
+		assert.Equal(t, []byte("v"), got)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestLoader_LoadVersioned_NegativeTTLSkipsCache(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	_, err = loader.LoadVersioned(context.Background(), "cfg", func(context.Context) ([]byte, error) {
+		return []byte("v"), nil
+	}, -1)
+	require.NoError(t, err)
+	assert.False(t, mr.Exists("cfg"))
+}
+
+func TestLoader_LoadVersioned_LoadError(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	loadErr := errors.New("backend down")
+	_, err = loader.LoadVersioned(context.Background(), "cfg", func(context.Context) ([]byte, error) {
+		return nil, loadErr
+	}, time.Hour)
+	require.ErrorIs(t, err, loadErr)
+	assert.False(t, mr.Exists("cfg"))
+}
+
+func TestLoader_LoadVersioned_CustomVersionKeyPrefix(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	loader, err := NewLoader(cache, WithVersionKeyPrefix("myver:"))
+	require.NoError(t, err)
+
+	version, err := loader.BumpVersion(context.Background(), "cfg")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	assert.True(t, mr.Exists("myver:cfg"))
+}
+
+func TestLoader_LoadVersioned_InvalidArgs(t *testing.T) {
+	cache, _ := newTestRedis(t)
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+	loadFn := func(context.Context) ([]byte, error) { return nil, nil }
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err = loader.LoadVersioned(nil, "k", loadFn, time.Hour)
+	assert.ErrorIs(t, err, ErrNilContext)
+	_, err = loader.LoadVersioned(context.Background(), "", loadFn, time.Hour)
+	assert.ErrorIs(t, err, ErrEmptyKey)
+	_, err = loader.LoadVersioned(context.Background(), "k", nil, time.Hour)
+	assert.ErrorIs(t, err, ErrNilLoader)
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err = loader.BumpVersion(nil, "k")
+	assert.ErrorIs(t, err, ErrNilContext)
+	_, err = loader.BumpVersion(context.Background(), "")
+	assert.ErrorIs(t, err, ErrEmptyKey)
+}
+
+func TestLoader_BumpVersion_RedisError(t *testing.T) {
+	cache, mr := newTestRedis(t)
+	loader, err := NewLoader(cache)
+	require.NoError(t, err)
+
+	mr.SetError("boom")
+	_, err = loader.BumpVersion(context.Background(), "cfg")
+	assert.Error(t, err)
+}
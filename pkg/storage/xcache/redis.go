@@ -25,6 +25,37 @@ type Redis interface {
 	// 如果获取失败，返回 ErrLockFailed。
 	Lock(ctx context.Context, key string, ttl time.Duration) (Unlocker, error)
 
+	// GetOrSet 原子地"不存在则写入"，并返回 key 当前存储的值。
+	// 适用于无需回源函数、singleflight 与分布式锁的简单场景（如幂等标记、首写胜出）。
+	//
+	// 返回值说明：
+	//   - (value, true, nil): key 不存在，本次写入成功，返回传入的 value。
+	//   - (existing, false, nil): key 已存在，未写入，返回已存储的值。
+	//
+	// ttl 说明：
+	//   - ttl > 0: 写入时设置指定过期时间。
+	//   - ttl == 0: 不设置过期（key 永不过期）。
+	//   - ttl < 0: 返回 ErrInvalidTTL。
+	//
+	// 空 key 返回 ErrEmptyKey。已存在的 key 不会被修改（包括 TTL）。
+	// Lua 模式下使用单个脚本保证原子性；Compat 模式下使用 SETNX + GET，
+	// 两步之间 key 恰好过期时会重试，详见 WithScriptMode。
+	GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error)
+
+	// DeletePrefix 删除所有以 prefix 开头的 key，返回实际删除的数量。
+	// 用于清空一个逻辑命名空间（如 "user:123:"）。
+	//
+	// 使用 SCAN（而非 KEYS）分批遍历，每批通过 Pipeline 发送单 key UNLINK，
+	// 避免阻塞 Redis 主线程。每批大小由 WithScanBatchSize 配置（作为 SCAN COUNT 提示）。
+	// prefix 中的 glob 特殊字符（* ? [ ] \）会被转义，按字面前缀匹配。
+	//
+	// Redis Cluster：SCAN 仅遍历单个节点，DeletePrefix 会通过 ForEachMaster 遍历所有主节点；
+	// 单 key UNLINK 避免跨 slot 的 CROSSSLOT 错误。其他分片客户端（如 Ring）仅遍历其路由到的节点。
+	//
+	// 非原子操作：遍历期间新写入的匹配 key 可能不会被删除。
+	// 出错时返回已删除的数量与错误。空 prefix 返回 ErrEmptyKey（防止误删整个库）。
+	DeletePrefix(ctx context.Context, prefix string) (int64, error)
+
 	// Client 返回底层的 redis.UniversalClient。
 	// 用于执行所有 Redis 操作。
 	Client() redis.UniversalClient
@@ -52,6 +83,10 @@ type RedisOptions struct {
 	// 默认为 0，表示不重试。
 	LockRetryCount int
 
+	// ScanBatchSize DeletePrefix 每批 SCAN 的 COUNT 提示值，同时决定每批 UNLINK 的规模。
+	// 默认为 DefaultScanBatchSize (500)。
+	ScanBatchSize int
+
 	// ScriptMode Redis 脚本执行模式。
 	// 默认为 ScriptModeAuto，构造时探测一次。
 	// 显式指定 ScriptModeLua 或 ScriptModeCompat 跳过探测。
@@ -67,6 +102,7 @@ func defaultRedisOptions() *RedisOptions {
 		LockKeyPrefix:     "lock:",
 		LockRetryInterval: 0,
 		LockRetryCount:    0,
+		ScanBatchSize:     DefaultScanBatchSize,
 	}
 }
 
@@ -84,6 +120,27 @@ const MaxLockRetryCount = 100
 // MaxLockRetryInterval 是 LockRetryInterval 的上界，防止单次重试间隔过长。
 const MaxLockRetryInterval = 10 * time.Second
 
+// DefaultScanBatchSize 是 DeletePrefix 默认的每批 SCAN COUNT。
+const DefaultScanBatchSize = 500
+
+// MaxScanBatchSize 是 ScanBatchSize 的上界，防止单批 Pipeline 过大占用过多内存与带宽。
+const MaxScanBatchSize = 10000
+
+// WithScanBatchSize 设置 DeletePrefix 每批 SCAN 的 COUNT 提示值。
+// n 会被钳位到 [1, MaxScanBatchSize] 范围内；非正值被忽略（保持默认值）。
+//
+// 注意：COUNT 仅是提示，Redis 单次返回的 key 数量可能略多或略少。
+func WithScanBatchSize(n int) RedisOption {
+	return func(o *RedisOptions) {
+		if n > 0 {
+			if n > MaxScanBatchSize {
+				n = MaxScanBatchSize
+			}
+			o.ScanBatchSize = n
+		}
+	}
+}
+
 // WithScriptMode 设置 Redis 脚本执行模式。
 //
 // 默认为 ScriptModeAuto，NewRedis() 会在构造时探测一次。
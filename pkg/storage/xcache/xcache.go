@@ -4,8 +4,10 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -28,6 +30,27 @@ var unlockScript = redis.NewScript(`
 	end
 `)
 
+// getOrSetScript 是原子"不存在则写入并返回当前值"的 Lua 脚本。
+// ARGV[1] 为写入值，ARGV[2] 为过期毫秒数（0 表示不过期）。
+// 返回 {1, value} 表示本次写入，{0, existing} 表示 key 已存在。
+var getOrSetScript = redis.NewScript(`
+	local existing = redis.call("GET", KEYS[1])
+	if existing then
+		return {0, existing}
+	end
+	local px = tonumber(ARGV[2])
+	if px > 0 then
+		redis.call("SET", KEYS[1], ARGV[1], "PX", px)
+	else
+		redis.call("SET", KEYS[1], ARGV[1])
+	end
+	return {1, ARGV[1]}
+`)
+
+// getOrSetCompatAttempts 是 Compat 模式下 GetOrSet 的最大尝试次数。
+// SETNX 失败后 GET 前 key 恰好过期时需要重试，连续多次命中该窗口几乎不可能。
+const getOrSetCompatAttempts = 3
+
 // =============================================================================
 // 工厂函数
 // =============================================================================
@@ -122,6 +145,7 @@ func NewMemoryFromClient(client *ristretto.Cache[string, []byte]) (Memory, error
 //   - cache 为 nil → 返回 ErrNilClient
 //   - EnableDistributedLock 为 true 但 DistributedLockTTL ≤ 0 → 返回 ErrInvalidConfig
 //   - ExternalLock 非 nil 但 EnableDistributedLock 被禁用 → 返回 ErrInvalidConfig
+//   - RefreshAhead 不在 [0, 1) 范围内 → 返回 ErrInvalidConfig
 //
 // 生命周期说明：
 //   - Loader 不持有需要释放的资源，无需调用 Close
@@ -292,7 +316,7 @@ func (w *redisWrapper) unlock(ctx context.Context, key, value string) error {
 func (w *redisWrapper) unlockCompat(ctx context.Context, key, value string) error {
 	val, err := w.client.Get(ctx, key).Result()
 	if err != nil {
-		if err == redis.Nil {
+		if errors.Is(err, redis.Nil) {
 			return ErrLockExpired
 		}
 		return err
@@ -311,6 +335,163 @@ func (w *redisWrapper) unlockCompat(ctx context.Context, key, value string) erro
 	return nil
 }
 
+func (w *redisWrapper) GetOrSet(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	if ctx == nil {
+		return nil, false, ErrNilContext
+	}
+	if w.closed.Load() {
+		return nil, false, ErrClosed
+	}
+	if key == "" {
+		return nil, false, ErrEmptyKey
+	}
+	if ttl < 0 {
+		return nil, false, ErrInvalidTTL
+	}
+
+	if w.scriptMode == rediscompat.ScriptModeCompat {
+		return w.getOrSetCompat(ctx, key, value, ttl)
+	}
+
+	// 设计决策: 不足 1ms 的正 TTL 向上取整为 1ms，与 Compat 模式 SETNX 的 PX 取整一致；
+	// 直接截断为 0 会被脚本视为"永不过期"。
+	px := ttl.Milliseconds()
+	if ttl > 0 && px == 0 {
+		px = 1
+	}
+	result, err := getOrSetScript.Run(ctx, w.client, []string{key}, value, px).Slice()
+	if err != nil {
+		return nil, false, err
+	}
+	return parseGetOrSetResult(result)
+}
+
+// parseGetOrSetResult 解析 getOrSetScript 的返回值 {flag, value}。
+func parseGetOrSetResult(result []any) ([]byte, bool, error) {
+	if len(result) != 2 {
+		return nil, false, fmt.Errorf("xcache: unexpected GetOrSet result length %d", len(result))
+	}
+	flag, ok := result[0].(int64)
+	if !ok {
+		return nil, false, fmt.Errorf("xcache: unexpected GetOrSet flag type %T", result[0])
+	}
+	stored, ok := result[1].(string)
+	if !ok {
+		return nil, false, fmt.Errorf("xcache: unexpected GetOrSet value type %T", result[1])
+	}
+	return []byte(stored), flag == 1, nil
+}
+
+// getOrSetCompat 使用 SETNX + GET 实现 GetOrSet（兼容模式）。
+//
+// 竞态分析：SETNX 失败到 GET 之间 key 可能过期，此时 GET 返回 redis.Nil，
+// 重新尝试 SETNX 即可；不存在读到"半写入"值的可能。
+func (w *redisWrapper) getOrSetCompat(ctx context.Context, key string, value []byte, ttl time.Duration) ([]byte, bool, error) {
+	for range getOrSetCompatAttempts {
+		set, err := w.client.SetNX(ctx, key, value, ttl).Result()
+		if err != nil {
+			return nil, false, err
+		}
+		if set {
+			return value, true, nil
+		}
+
+		existing, err := w.client.Get(ctx, key).Bytes()
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return nil, false, err
+		}
+	}
+	return nil, false, fmt.Errorf("xcache: GetOrSet key %q kept expiring between SETNX and GET", key)
+}
+
+func (w *redisWrapper) DeletePrefix(ctx context.Context, prefix string) (int64, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if w.closed.Load() {
+		return 0, ErrClosed
+	}
+	if prefix == "" {
+		return 0, ErrEmptyKey
+	}
+
+	pattern := escapeGlob(prefix) + "*"
+
+	// 设计决策: Cluster 模式下 SCAN 只遍历单个节点，需逐个主节点扫描。
+	// ForEachMaster 并发执行回调，计数使用原子累加。
+	if cc, ok := w.client.(*redis.ClusterClient); ok {
+		var total atomic.Int64
+		err := cc.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			n, err := w.deleteByScan(ctx, node, pattern)
+			total.Add(n)
+			return err
+		})
+		return total.Load(), err
+	}
+	return w.deleteByScan(ctx, w.client, pattern)
+}
+
+// deleteByScan 在单个节点上 SCAN 匹配 pattern 的 key 并分批 UNLINK。
+func (w *redisWrapper) deleteByScan(ctx context.Context, client redis.Cmdable, pattern string) (int64, error) {
+	var (
+		cursor  uint64
+		deleted int64
+	)
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, int64(w.options.ScanBatchSize)).Result()
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) > 0 {
+			n, err := unlinkKeys(ctx, client, keys)
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+// unlinkKeys 通过 Pipeline 逐 key 发送 UNLINK，返回实际删除的数量。
+// 逐 key 而非单条多 key UNLINK，避免 Cluster 下同节点不同 slot 触发 CROSSSLOT。
+func unlinkKeys(ctx context.Context, client redis.Cmdable, keys []string) (int64, error) {
+	pipe := client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.Unlink(ctx, key)
+	}
+	_, execErr := pipe.Exec(ctx)
+
+	var deleted int64
+	for _, cmd := range cmds {
+		if n, err := cmd.Result(); err == nil {
+			deleted += n
+		}
+	}
+	return deleted, execErr
+}
+
+// escapeGlob 转义 Redis glob 模式中的特殊字符，使 prefix 按字面匹配。
+func escapeGlob(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func (w *redisWrapper) Client() redis.UniversalClient {
 	return w.client
 }
@@ -3,6 +3,7 @@ package xcache
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -826,3 +827,203 @@ func TestWithScriptMode_SetsOption(t *testing.T) {
 	WithScriptMode(rediscompat.ScriptModeCompat)(opts)
 	assert.Equal(t, rediscompat.ScriptModeCompat, opts.ScriptMode)
 }
+
+// =============================================================================
+// GetOrSet 测试
+// =============================================================================
+
+func TestRedisWrapper_GetOrSet(t *testing.T) {
+	modes := map[string]func(t *testing.T) (Redis, *miniredis.Miniredis){
+		"lua":    newTestRedisCache,
+		"compat": newTestRedisCacheCompat,
+	}
+	for name, newCache := range modes {
+		t.Run(name, func(t *testing.T) {
+			t.Run("SetsWhenAbsent", func(t *testing.T) {
+				cache, mr := newCache(t)
+				ctx := context.Background()
+
+				stored, set, err := cache.GetOrSet(ctx, "gos", []byte("v1"), time.Minute)
+				require.NoError(t, err)
+				assert.True(t, set)
+				assert.Equal(t, []byte("v1"), stored)
+				assert.Equal(t, time.Minute, mr.TTL("gos"))
+			})
+
+			t.Run("ReturnsExistingWhenPresent", func(t *testing.T) {
+				cache, mr := newCache(t)
+				ctx := context.Background()
+				require.NoError(t, mr.Set("gos", "old"))
+				mr.SetTTL("gos", time.Hour)
+
+				stored, set, err := cache.GetOrSet(ctx, "gos", []byte("new"), time.Minute)
+				require.NoError(t, err)
+				assert.False(t, set)
+				assert.Equal(t, []byte("old"), stored)
+				assert.Equal(t, time.Hour, mr.TTL("gos"), "existing TTL must not change")
+			})
+
+			t.Run("ZeroTTLNeverExpires", func(t *testing.T) {
+				cache, mr := newCache(t)
+
+				_, set, err := cache.GetOrSet(context.Background(), "gos", []byte("v"), 0)
+				require.NoError(t, err)
+				assert.True(t, set)
+				assert.Equal(t, time.Duration(0), mr.TTL("gos"))
+			})
+
+			t.Run("SubMillisecondTTLExpires", func(t *testing.T) {
+				cache, mr := newCache(t)
+
+				_, set, err := cache.GetOrSet(context.Background(), "gos", []byte("v"), 500*time.Microsecond)
+				require.NoError(t, err)
+				assert.True(t, set)
+				assert.Equal(t, time.Millisecond, mr.TTL("gos"), "sub-millisecond TTL is rounded up to 1ms")
+			})
+
+			t.Run("EmptyValue", func(t *testing.T) {
+				cache, _ := newCache(t)
+				ctx := context.Background()
+
+				_, set, err := cache.GetOrSet(ctx, "gos", nil, time.Minute)
+				require.NoError(t, err)
+				assert.True(t, set)
+
+				stored, set, err := cache.GetOrSet(ctx, "gos", []byte("other"), time.Minute)
+				require.NoError(t, err)
+				assert.False(t, set)
+				assert.Empty(t, stored)
+			})
+		})
+	}
+}
+
+func TestRedisWrapper_GetOrSet_InvalidArgs(t *testing.T) {
+	cache, _ := newTestRedisCache(t)
+	ctx := context.Background()
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, _, err := cache.GetOrSet(nil, "key", []byte("v"), time.Minute)
+	assert.ErrorIs(t, err, ErrNilContext)
+
+	_, _, err = cache.GetOrSet(ctx, "", []byte("v"), time.Minute)
+	assert.ErrorIs(t, err, ErrEmptyKey)
+
+	_, _, err = cache.GetOrSet(ctx, "key", []byte("v"), -time.Second)
+	assert.ErrorIs(t, err, ErrInvalidTTL)
+
+	require.NoError(t, cache.Close(ctx))
+	_, _, err = cache.GetOrSet(ctx, "key", []byte("v"), time.Minute)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestRedisWrapper_GetOrSet_ConnectionError(t *testing.T) {
+	cache, mr := newTestRedisCacheCompat(t)
+	mr.SetError("injected error")
+	defer mr.SetError("")
+
+	_, _, err := cache.GetOrSet(context.Background(), "key", []byte("v"), time.Minute)
+	assert.Error(t, err)
+}
+
+func TestParseGetOrSetResult_UnexpectedShape(t *testing.T) {
+	_, _, err := parseGetOrSetResult([]any{int64(1)})
+	assert.Error(t, err)
+
+	_, _, err = parseGetOrSetResult([]any{"1", "v"})
+	assert.Error(t, err)
+
+	_, _, err = parseGetOrSetResult([]any{int64(1), int64(2)})
+	assert.Error(t, err)
+}
+
+// =============================================================================
+// DeletePrefix 测试
+// =============================================================================
+
+func TestRedisWrapper_DeletePrefix_DeletesMatchingKeys(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	// 小批量迫使多轮 SCAN
+	cache, err := NewRedis(client, WithScanBatchSize(3))
+	require.NoError(t, err)
+	defer func() { _ = cache.Close(context.Background()) }()
+
+	for i := range 10 {
+		require.NoError(t, mr.Set(fmt.Sprintf("ns:a:%d", i), "v"))
+	}
+	require.NoError(t, mr.Set("ns:b:1", "v"))
+	require.NoError(t, mr.Set("other", "v"))
+
+	deleted, err := cache.DeletePrefix(context.Background(), "ns:a:")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), deleted)
+	assert.ElementsMatch(t, []string{"ns:b:1", "other"}, mr.Keys())
+}
+
+func TestRedisWrapper_DeletePrefix_EscapesGlobChars(t *testing.T) {
+	cache, mr := newTestRedisCache(t)
+
+	require.NoError(t, mr.Set("a*:1", "v"))
+	require.NoError(t, mr.Set("ab:1", "v"))
+	require.NoError(t, mr.Set("a?[x]:1", "v"))
+
+	deleted, err := cache.DeletePrefix(context.Background(), "a*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	deleted, err = cache.DeletePrefix(context.Background(), "a?[x]")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, []string{"ab:1"}, mr.Keys())
+}
+
+func TestRedisWrapper_DeletePrefix_NoMatch(t *testing.T) {
+	cache, _ := newTestRedisCache(t)
+
+	deleted, err := cache.DeletePrefix(context.Background(), "missing:")
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestRedisWrapper_DeletePrefix_InvalidArgs(t *testing.T) {
+	cache, _ := newTestRedisCache(t)
+	ctx := context.Background()
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err := cache.DeletePrefix(nil, "p:")
+	assert.ErrorIs(t, err, ErrNilContext)
+
+	_, err = cache.DeletePrefix(ctx, "")
+	assert.ErrorIs(t, err, ErrEmptyKey)
+
+	require.NoError(t, cache.Close(ctx))
+	_, err = cache.DeletePrefix(ctx, "p:")
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestRedisWrapper_DeletePrefix_ScanError(t *testing.T) {
+	cache, mr := newTestRedisCache(t)
+	mr.SetError("injected error")
+	defer mr.SetError("")
+
+	_, err := cache.DeletePrefix(context.Background(), "p:")
+	assert.Error(t, err)
+}
+
+func TestWithScanBatchSize_Clamps(t *testing.T) {
+	opts := defaultRedisOptions()
+	assert.Equal(t, DefaultScanBatchSize, opts.ScanBatchSize)
+
+	WithScanBatchSize(0)(opts)
+	assert.Equal(t, DefaultScanBatchSize, opts.ScanBatchSize)
+
+	WithScanBatchSize(MaxScanBatchSize + 1)(opts)
+	assert.Equal(t, MaxScanBatchSize, opts.ScanBatchSize)
+
+	WithScanBatchSize(42)(opts)
+	assert.Equal(t, 42, opts.ScanBatchSize)
+}
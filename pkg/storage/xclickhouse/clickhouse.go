@@ -79,6 +79,25 @@ type ClickHouse interface {
 	// table 是目标表名，rows 是待插入的数据切片。
 	// 关闭后调用返回 ErrClosed。
 	BatchInsert(ctx context.Context, table string, rows []any, opts BatchOptions) (*BatchResult, error)
+
+	// ReloadDictionary 重新加载字典（SYSTEM RELOAD DICTIONARY name）。
+	// name 格式与表名一致（name、db.name、`name`、`db`.`name`）。
+	// 该语句同步等待加载完成，加载失败时返回服务端异常；
+	// 缺少 SYSTEM RELOAD DICTIONARY 权限时返回包装了 ErrAccessDenied 的错误。
+	// 计入 Stats().QueryCount 和慢查询检测。关闭后调用返回 ErrClosed。
+	ReloadDictionary(ctx context.Context, name string) error
+
+	// DictionaryStatus 查询字典状态（system.dictionaries）。
+	// 未指定数据库时优先匹配当前数据库，其次匹配 XML 配置字典（database 为空）。
+	// 字典不存在时返回 ErrDictionaryNotFound。
+	// 计入 Stats().QueryCount 和慢查询检测。关闭后调用返回 ErrClosed。
+	DictionaryStatus(ctx context.Context, name string) (*DictionaryStatus, error)
+
+	// RefreshView 立即触发可刷新物化视图的刷新（SYSTEM REFRESH VIEW name）。
+	// 仅适用于带 REFRESH 子句的物化视图；普通物化视图随写入实时更新，无需刷新。
+	// 缺少权限时返回包装了 ErrAccessDenied 的错误。
+	// 计入 Stats().QueryCount 和慢查询检测。关闭后调用返回 ErrClosed。
+	RefreshView(ctx context.Context, name string) error
 }
 
 // =============================================================================
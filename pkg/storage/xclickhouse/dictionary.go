@@ -0,0 +1,217 @@
+package xclickhouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/omeyang/xkit/internal/storageopt"
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+)
+
+// =============================================================================
+// 字典与物化视图运维辅助
+// =============================================================================
+
+// codeAccessDenied 是 ClickHouse ACCESS_DENIED 异常码。
+// 执行 SYSTEM RELOAD DICTIONARY / SYSTEM REFRESH VIEW 需要对应的 SYSTEM 权限，
+// 权限不足时服务端返回此异常码。
+const codeAccessDenied int32 = 497
+
+// DictionaryStatus 字典状态，来自 system.dictionaries。
+type DictionaryStatus struct {
+	// Database 是字典所属数据库。通过 XML 配置定义的字典为空字符串。
+	Database string
+
+	// Name 是字典名称。
+	Name string
+
+	// Status 是字典加载状态，取值为 ClickHouse 的 status 枚举：
+	// NOT_LOADED、LOADED、FAILED、LOADING、LOADED_AND_RELOADING、FAILED_AND_RELOADING。
+	Status string
+
+	// ElementCount 是字典中的条目数。
+	ElementCount uint64
+
+	// BytesAllocated 是字典占用的内存字节数。
+	BytesAllocated uint64
+
+	// LoadingStartTime 是最近一次加载的开始时间。
+	LoadingStartTime time.Time
+
+	// LastSuccessfulUpdateTime 是最近一次成功加载的完成时间。
+	LastSuccessfulUpdateTime time.Time
+
+	// LoadingDuration 是最近一次加载的耗时。
+	LoadingDuration time.Duration
+
+	// LastException 是最近一次加载失败的异常信息，加载成功时为空。
+	LastException string
+}
+
+// Loaded 报告字典当前是否有可用数据（LOADED 或 LOADED_AND_RELOADING）。
+func (s *DictionaryStatus) Loaded() bool {
+	return s.Status == "LOADED" || s.Status == "LOADED_AND_RELOADING"
+}
+
+// ReloadDictionary 重新加载字典（SYSTEM RELOAD DICTIONARY）。
+func (w *clickhouseWrapper) ReloadDictionary(ctx context.Context, name string) error {
+	if w.closed.Load() {
+		return ErrClosed
+	}
+	if err := validateObjectName(name); err != nil {
+		return err
+	}
+
+	statement := "SYSTEM RELOAD DICTIONARY " + name
+	return w.runSystemOp(ctx, "reload_dictionary", statement, func(ctx context.Context) error {
+		if err := w.conn.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("reload dictionary %s failed: %w", name, classifyServerError(err))
+		}
+		return nil
+	})
+}
+
+// RefreshView 立即刷新可刷新物化视图（SYSTEM REFRESH VIEW）。
+func (w *clickhouseWrapper) RefreshView(ctx context.Context, name string) error {
+	if w.closed.Load() {
+		return ErrClosed
+	}
+	if err := validateObjectName(name); err != nil {
+		return err
+	}
+
+	statement := "SYSTEM REFRESH VIEW " + name
+	return w.runSystemOp(ctx, "refresh_view", statement, func(ctx context.Context) error {
+		if err := w.conn.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("refresh view %s failed: %w", name, classifyServerError(err))
+		}
+		return nil
+	})
+}
+
+// dictionaryStatusQuery 查询单个字典状态。
+// 未指定数据库时同时匹配当前数据库和空数据库（XML 配置字典），
+// ORDER BY database DESC 使当前数据库中的同名字典优先。
+const dictionaryStatusQuery = `SELECT database, name, toString(status), element_count, bytes_allocated,
+	loading_start_time, last_successful_update_time, loading_duration, last_exception
+FROM system.dictionaries
+WHERE name = ? AND %s
+ORDER BY database DESC
+LIMIT 1`
+
+// DictionaryStatus 查询字典状态。
+func (w *clickhouseWrapper) DictionaryStatus(ctx context.Context, name string) (*DictionaryStatus, error) {
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+	if err := validateObjectName(name); err != nil {
+		return nil, err
+	}
+
+	database, dictName := splitObjectName(name)
+	cond, args := "database IN (currentDatabase(), '')", []any{dictName}
+	if database != "" {
+		cond = "database = ?"
+		args = append(args, database)
+	}
+	query := fmt.Sprintf(dictionaryStatusQuery, cond)
+
+	var status DictionaryStatus
+	err := w.runSystemOp(ctx, "dictionary_status", query, func(ctx context.Context) error {
+		var loadingSeconds float32
+		scanErr := w.conn.QueryRow(ctx, query, args...).Scan(
+			&status.Database, &status.Name, &status.Status, &status.ElementCount, &status.BytesAllocated,
+			&status.LoadingStartTime, &status.LastSuccessfulUpdateTime, &loadingSeconds, &status.LastException,
+		)
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			return fmt.Errorf("dictionary %s: %w", name, ErrDictionaryNotFound)
+		}
+		if scanErr != nil {
+			return fmt.Errorf("dictionary status %s failed: %w", name, classifyServerError(scanErr))
+		}
+		status.LoadingDuration = time.Duration(float64(loadingSeconds) * float64(time.Second))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// runSystemOp 为运维类操作统一提供追踪、查询统计和慢查询检测。
+func (w *clickhouseWrapper) runSystemOp(ctx context.Context, operation, statement string, fn func(context.Context) error) (err error) {
+	start := time.Now()
+	ctx, span := xmetrics.Start(ctx, w.options.Observer, xmetrics.SpanOptions{
+		Component: clickhouseComponent,
+		Operation: operation,
+		Kind:      xmetrics.KindClient,
+		Attrs: []xmetrics.Attr{
+			xmetrics.String("db.system", "clickhouse"),
+		},
+	})
+	defer func() {
+		duration := storageopt.MeasureOperation(start)
+		slow := w.maybeSlowQuery(ctx, SlowQueryInfo{
+			Query:    statement,
+			Duration: duration,
+		})
+
+		var attrs []xmetrics.Attr
+		if slow {
+			attrs = append(attrs,
+				xmetrics.Bool("slow", true),
+				xmetrics.Int64("slow_threshold_ms", w.options.SlowQueryThreshold.Milliseconds()),
+			)
+		}
+		span.End(xmetrics.Result{Err: err, Attrs: attrs})
+	}()
+
+	w.queryCounter.IncQuery()
+	if err = fn(ctx); err != nil {
+		w.queryCounter.IncQueryError()
+	}
+	return err
+}
+
+// validateObjectName 校验字典/视图名称，规则与表名一致。
+func validateObjectName(name string) error {
+	if name == "" {
+		return ErrEmptyObjectName
+	}
+	if !tableNamePattern.MatchString(name) {
+		return ErrInvalidObjectName
+	}
+	return nil
+}
+
+// splitObjectName 将已校验的名称拆分为数据库名和对象名，并去除反引号。
+// 未指定数据库时 database 为空。
+func splitObjectName(name string) (database, object string) {
+	if strings.HasPrefix(name, "`") {
+		// 反引号内可包含点号，按 "`.`" 分隔
+		if db, obj, ok := strings.Cut(name, "`.`"); ok {
+			return strings.TrimPrefix(db, "`"), strings.TrimSuffix(obj, "`")
+		}
+		return "", strings.Trim(name, "`")
+	}
+	if db, obj, ok := strings.Cut(name, "."); ok {
+		return db, obj
+	}
+	return "", name
+}
+
+// classifyServerError 将权限不足的服务端异常包装为 ErrAccessDenied，
+// 同时保留原始异常，调用方可通过 errors.As 获取 *proto.Exception。
+func classifyServerError(err error) error {
+	var ex *proto.Exception
+	if errors.As(err, &ex) && ex.Code == codeAccessDenied {
+		return fmt.Errorf("%w: %w", ErrAccessDenied, err)
+	}
+	return err
+}
@@ -0,0 +1,231 @@
+package xclickhouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// =============================================================================
+// 字典与物化视图运维辅助测试
+// =============================================================================
+
+func TestReloadDictionary_Success(t *testing.T) {
+	conn := newMockConn()
+	var executed string
+	conn.execFunc = func(_ context.Context, query string, _ ...any) error {
+		executed = query
+		return nil
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	require.NoError(t, w.ReloadDictionary(context.Background(), "db.user_dict"))
+
+	assert.Equal(t, "SYSTEM RELOAD DICTIONARY db.user_dict", executed)
+	assert.Equal(t, int64(1), w.queryCounter.QueryCount())
+	assert.Equal(t, int64(0), w.queryCounter.QueryErrors())
+}
+
+func TestReloadDictionary_AccessDenied(t *testing.T) {
+	conn := newMockConn()
+	ex := &proto.Exception{Code: codeAccessDenied, Name: "DB::Exception", Message: "Not enough privileges"}
+	conn.execFunc = func(_ context.Context, _ string, _ ...any) error {
+		return ex
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	err := w.ReloadDictionary(context.Background(), "user_dict")
+
+	require.ErrorIs(t, err, ErrAccessDenied)
+	var got *proto.Exception
+	require.True(t, errors.As(err, &got))
+	assert.Same(t, ex, got)
+	assert.Equal(t, int64(1), w.queryCounter.QueryErrors())
+}
+
+func TestReloadDictionary_OtherError(t *testing.T) {
+	conn := newMockConn()
+	conn.execFunc = func(_ context.Context, _ string, _ ...any) error {
+		return &proto.Exception{Code: 36, Message: "Dictionary not found"}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	err := w.ReloadDictionary(context.Background(), "user_dict")
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrAccessDenied)
+	assert.Equal(t, int64(1), w.queryCounter.QueryErrors())
+}
+
+func TestReloadDictionary_InvalidName(t *testing.T) {
+	w := &clickhouseWrapper{conn: newMockConn(), options: defaultOptions()}
+
+	assert.ErrorIs(t, w.ReloadDictionary(context.Background(), ""), ErrEmptyObjectName)
+	assert.ErrorIs(t, w.ReloadDictionary(context.Background(), "dict; DROP TABLE t"), ErrInvalidObjectName)
+	assert.Equal(t, int64(0), w.queryCounter.QueryCount())
+}
+
+func TestReloadDictionary_SlowQueryHook(t *testing.T) {
+	conn := newMockConn()
+	conn.execFunc = func(_ context.Context, _ string, _ ...any) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	var captured SlowQueryInfo
+	opts := defaultOptions()
+	opts.SlowQueryThreshold = 5 * time.Millisecond
+	opts.SlowQueryHook = func(_ context.Context, info SlowQueryInfo) {
+		captured = info
+	}
+	detector, err := newSlowQueryDetector(opts)
+	require.NoError(t, err)
+	w := &clickhouseWrapper{conn: conn, options: opts, slowQueryDetector: detector}
+
+	require.NoError(t, w.ReloadDictionary(context.Background(), "user_dict"))
+
+	assert.Equal(t, "SYSTEM RELOAD DICTIONARY user_dict", captured.Query)
+	assert.Equal(t, int64(1), w.slowQueryCounter.Count())
+}
+
+func TestRefreshView_Success(t *testing.T) {
+	conn := newMockConn()
+	var executed string
+	conn.execFunc = func(_ context.Context, query string, _ ...any) error {
+		executed = query
+		return nil
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	require.NoError(t, w.RefreshView(context.Background(), "`db`.`daily_mv`"))
+
+	assert.Equal(t, "SYSTEM REFRESH VIEW `db`.`daily_mv`", executed)
+	assert.Equal(t, int64(1), w.queryCounter.QueryCount())
+}
+
+func TestRefreshView_AccessDenied(t *testing.T) {
+	conn := newMockConn()
+	conn.execFunc = func(_ context.Context, _ string, _ ...any) error {
+		return &proto.Exception{Code: codeAccessDenied}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	assert.ErrorIs(t, w.RefreshView(context.Background(), "daily_mv"), ErrAccessDenied)
+}
+
+func TestDictionaryStatus_Success(t *testing.T) {
+	conn := newMockConn()
+	loadedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var gotQuery string
+	var gotArgs []any
+	conn.queryRowFunc = func(_ context.Context, query string, args ...any) Row {
+		gotQuery, gotArgs = query, args
+		return &mockRow{scanFunc: func(dest ...any) error {
+			require.Len(t, dest, 9)
+			*dest[0].(*string) = "db"
+			*dest[1].(*string) = "user_dict"
+			*dest[2].(*string) = "LOADED"
+			*dest[3].(*uint64) = 42
+			*dest[4].(*uint64) = 1024
+			*dest[5].(*time.Time) = loadedAt
+			*dest[6].(*time.Time) = loadedAt.Add(time.Second)
+			*dest[7].(*float32) = 1.5
+			return nil
+		}}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	status, err := w.DictionaryStatus(context.Background(), "db.user_dict")
+
+	require.NoError(t, err)
+	assert.Contains(t, gotQuery, "FROM system.dictionaries")
+	assert.Contains(t, gotQuery, "database = ?")
+	assert.Equal(t, []any{"user_dict", "db"}, gotArgs)
+	assert.Equal(t, "db", status.Database)
+	assert.Equal(t, "user_dict", status.Name)
+	assert.True(t, status.Loaded())
+	assert.Equal(t, uint64(42), status.ElementCount)
+	assert.Equal(t, uint64(1024), status.BytesAllocated)
+	assert.Equal(t, loadedAt, status.LoadingStartTime)
+	assert.Equal(t, 1500*time.Millisecond, status.LoadingDuration)
+	assert.Empty(t, status.LastException)
+	assert.Equal(t, int64(1), w.queryCounter.QueryCount())
+}
+
+func TestDictionaryStatus_CurrentDatabase(t *testing.T) {
+	conn := newMockConn()
+	var gotQuery string
+	var gotArgs []any
+	conn.queryRowFunc = func(_ context.Context, query string, args ...any) Row {
+		gotQuery, gotArgs = query, args
+		return &mockRow{}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	_, err := w.DictionaryStatus(context.Background(), "user_dict")
+
+	require.NoError(t, err)
+	assert.Contains(t, gotQuery, "database IN (currentDatabase(), '')")
+	assert.Equal(t, []any{"user_dict"}, gotArgs)
+}
+
+func TestDictionaryStatus_NotFound(t *testing.T) {
+	conn := newMockConn()
+	conn.queryRowFunc = func(_ context.Context, _ string, _ ...any) Row {
+		return &mockRow{err: sql.ErrNoRows}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	status, err := w.DictionaryStatus(context.Background(), "missing")
+
+	assert.Nil(t, status)
+	assert.ErrorIs(t, err, ErrDictionaryNotFound)
+	assert.Equal(t, int64(1), w.queryCounter.QueryErrors())
+}
+
+func TestDictionaryStatus_AccessDenied(t *testing.T) {
+	conn := newMockConn()
+	conn.queryRowFunc = func(_ context.Context, _ string, _ ...any) Row {
+		return &mockRow{err: &proto.Exception{Code: codeAccessDenied}}
+	}
+	w := &clickhouseWrapper{conn: conn, options: defaultOptions()}
+
+	_, err := w.DictionaryStatus(context.Background(), "user_dict")
+
+	assert.ErrorIs(t, err, ErrAccessDenied)
+}
+
+func TestDictionaryOps_Closed(t *testing.T) {
+	w := &clickhouseWrapper{conn: newMockConn(), options: defaultOptions()}
+	w.closed.Store(true)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, w.ReloadDictionary(ctx, "d"), ErrClosed)
+	assert.ErrorIs(t, w.RefreshView(ctx, "v"), ErrClosed)
+	_, err := w.DictionaryStatus(ctx, "d")
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestSplitObjectName(t *testing.T) {
+	tests := []struct {
+		name, database, object string
+	}{
+		{"dict", "", "dict"},
+		{"db.dict", "db", "dict"},
+		{"`dict`", "", "dict"},
+		{"`my.db`.`my-dict`", "my.db", "my-dict"},
+		{"`a.b`", "", "a.b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, obj := splitObjectName(tt.name)
+			assert.Equal(t, tt.database, db)
+			assert.Equal(t, tt.object, obj)
+		})
+	}
+}
@@ -17,6 +17,8 @@
 //   - Stats()：统计信息
 //   - QueryPage()：分页查询（关闭后返回 ErrClosed，统计为 2 次查询，PageSize 上限 MaxPageSize）
 //   - BatchInsert()：批量插入（关闭后返回 ErrClosed，context 取消时中止当前批次，不发送部分数据，BatchSize 上限 MaxBatchSize）
+//   - ReloadDictionary()/DictionaryStatus()：字典刷新与状态查询（关闭后返回 ErrClosed）
+//   - RefreshView()：触发可刷新物化视图刷新（关闭后返回 ErrClosed）
 //   - Close()：幂等关闭（多次调用安全，第二次起返回 ErrClosed）
 //
 // # 已知限制
@@ -42,6 +44,17 @@
 //
 // 相关错误：ErrQueryContainsFormat, ErrQueryContainsSettings, ErrQueryContainsLimitOffset
 //
+// ## 字典与物化视图
+//
+// ReloadDictionary/RefreshView 封装 SYSTEM RELOAD DICTIONARY / SYSTEM REFRESH VIEW，
+// DictionaryStatus 查询 system.dictionaries 中的加载状态、条目数和最近异常，
+// 适用于依赖字典做 JOIN 的查询在数据源更新后主动刷新并确认生效。
+// 三者均计入 Stats().QueryCount 并参与慢查询检测（SYSTEM 语句同步等待加载完成，
+// 大字典的刷新耗时可能触发慢查询告警）。
+//
+// 名称校验规则与表名一致。SYSTEM 语句需要对应权限，权限不足时返回的错误
+// 可用 errors.Is(err, ErrAccessDenied) 判断，原始异常可用 errors.As 获取 *proto.Exception。
+//
 // ## 批量插入限制
 //
 // BatchSize 受 MaxBatchSize（默认 100000）限制，超过时返回 ErrBatchSizeTooLarge。
@@ -82,4 +82,18 @@ var (
 	// 大偏移量在 ClickHouse 中会导致扫描放大和性能下降。
 	// 如需大数据量分页，请使用 Client() 实现游标分页。
 	ErrOffsetTooLarge = errors.New("xclickhouse: offset exceeds maximum allowed, use cursor-based pagination via Client()")
+
+	// ErrEmptyObjectName 表示字典或视图名称为空。
+	ErrEmptyObjectName = errors.New("xclickhouse: empty dictionary or view name")
+
+	// ErrInvalidObjectName 表示字典或视图名称包含非法字符。
+	// 支持的格式与表名一致：name、db.name、`name`、`db`.`name`。
+	ErrInvalidObjectName = errors.New("xclickhouse: invalid dictionary or view name (supported: name, db.name, `name`, `db`.`name`)")
+
+	// ErrDictionaryNotFound 表示 system.dictionaries 中不存在指定字典。
+	ErrDictionaryNotFound = errors.New("xclickhouse: dictionary not found")
+
+	// ErrAccessDenied 表示当前用户缺少执行操作所需的权限（ClickHouse ACCESS_DENIED）。
+	// 返回的错误同时包装了原始 *proto.Exception，可通过 errors.As 获取异常详情。
+	ErrAccessDenied = errors.New("xclickhouse: access denied")
 )
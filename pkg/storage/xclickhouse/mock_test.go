@@ -31,6 +31,7 @@ type mockConn struct {
 	queryFunc       func(ctx context.Context, query string, args ...any) (driver.Rows, error)
 	prepareBatchErr error
 	batchFunc       func(ctx context.Context, query string) driver.Batch
+	execFunc        func(ctx context.Context, query string, args ...any) error
 	stats           driver.Stats
 }
 
@@ -70,7 +71,10 @@ func (m *mockConn) PrepareBatch(ctx context.Context, query string, _ ...driver.P
 	return &mockBatch{}, nil
 }
 
-func (m *mockConn) Exec(_ context.Context, _ string, _ ...any) error {
+func (m *mockConn) Exec(ctx context.Context, query string, args ...any) error {
+	if m.execFunc != nil {
+		return m.execFunc(ctx, query, args...)
+	}
 	return nil
 }
 
@@ -0,0 +1,144 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// ChunkError 批量操作中单个分块（一个事务）的失败信息。
+type ChunkError struct {
+	// Keys 该分块包含的键，该分块内的操作均未生效。
+	Keys []string
+	// Err 失败原因。
+	Err error
+}
+
+// BatchError 批量操作中部分分块失败。
+//
+// 分块之间不具备原子性：未出现在 Chunks 中的分块已成功提交，
+// 调用方可仅对 FailedKeys() 重试。
+type BatchError struct {
+	// Chunks 失败的分块，按提交顺序排列。
+	Chunks []ChunkError
+}
+
+// Error 实现 error 接口。
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "xetcd: %d batch chunk(s) failed", len(e.Chunks))
+	if len(e.Chunks) > 0 {
+		fmt.Fprintf(&b, ", first: %v", e.Chunks[0].Err)
+	}
+	return b.String()
+}
+
+// Unwrap 返回各分块的错误，支持 errors.Is/As 匹配任一分块的失败原因。
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Chunks))
+	for i, c := range e.Chunks {
+		errs[i] = c.Err
+	}
+	return errs
+}
+
+// FailedKeys 返回所有失败分块包含的键。
+func (e *BatchError) FailedKeys() []string {
+	var keys []string
+	for _, c := range e.Chunks {
+		keys = append(keys, c.Keys...)
+	}
+	return keys
+}
+
+// PutBatch 批量写入键值对，按 WithMaxTxnOps 分块，每块通过一个事务提交。
+//
+// 相比逐个 Put，往返次数从 len(kvs) 降到 ceil(len(kvs)/maxTxnOps)。
+// 原子性仅限于分块内部：单个分块要么全部生效、要么全部不生效，
+// 分块之间互不影响，某个分块失败不会中止后续分块。
+// 需要整体原子写入时，应将 WithMaxTxnOps 设为不小于 len(kvs)（受服务端限制约束），
+// 或通过 RawClient() 自行构造事务。
+//
+// 键按字典序排序后分块，保证相同输入的分块方式确定。
+// 全部成功返回 nil；部分分块失败返回 *BatchError；
+// ctx 取消后剩余分块不再提交，同样计入 *BatchError。
+// WithOperationTimeout 对每个分块单独计时。
+func (c *Client) PutBatch(ctx context.Context, kvs map[string][]byte) error {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(kvs))
+	for k := range kvs {
+		if k == "" {
+			return ErrEmptyKey
+		}
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return c.commitChunks(ctx, keys, func(key string) clientv3.Op {
+		return clientv3.OpPut(key, string(kvs[key]))
+	})
+}
+
+// DeleteBatch 批量删除键，分块与错误语义同 PutBatch。
+//
+// 不存在的键被忽略，不视为错误。重复的键只删除一次。
+func (c *Client) DeleteBatch(ctx context.Context, keys []string) error {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return err
+	}
+	if slices.Contains(keys, "") {
+		return ErrEmptyKey
+	}
+	// etcd 拒绝同一事务中重复操作同一个键（duplicate key given in txn request）
+	sorted := slices.Compact(slices.Sorted(slices.Values(keys)))
+
+	return c.commitChunks(ctx, sorted, func(key string) clientv3.Op {
+		return clientv3.OpDelete(key)
+	})
+}
+
+// commitChunks 将 keys 按 maxTxnOps 分块，逐块构造事务提交，收集失败分块。
+func (c *Client) commitChunks(ctx context.Context, keys []string, op func(key string) clientv3.Op) error {
+	var failed []ChunkError
+	for chunk := range slices.Chunk(keys, c.txnOpsLimit()) {
+		if err := ctx.Err(); err != nil {
+			failed = append(failed, ChunkError{Keys: chunk, Err: err})
+			continue
+		}
+		ops := make([]clientv3.Op, len(chunk))
+		for i, key := range chunk {
+			ops[i] = op(key)
+		}
+		if err := c.commitChunk(ctx, ops); err != nil {
+			failed = append(failed, ChunkError{Keys: chunk, Err: err})
+		}
+	}
+	if len(failed) > 0 {
+		return &BatchError{Chunks: failed}
+	}
+	return nil
+}
+
+// commitChunk 以单个无条件事务提交一组操作。
+func (c *Client) commitChunk(ctx context.Context, ops []clientv3.Op) error {
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	if _, err := c.client.Txn(ctx).Then(ops...).Commit(); err != nil {
+		return fmt.Errorf("xetcd: batch txn (%d ops): %w", len(ops), err)
+	}
+	return nil
+}
+
+// txnOpsLimit 返回单个事务的最大操作数。
+func (c *Client) txnOpsLimit() int {
+	if c.maxTxnOps > 0 {
+		return c.maxTxnOps
+	}
+	return DefaultMaxTxnOps
+}
@@ -0,0 +1,68 @@
+package xetcd
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBatchError(t *testing.T) {
+	errA := errors.New("chunk a failed")
+	errB := errors.New("chunk b failed")
+	e := &BatchError{Chunks: []ChunkError{
+		{Keys: []string{"/a/1", "/a/2"}, Err: errA},
+		{Keys: []string{"/b/1"}, Err: errB},
+	}}
+
+	if !strings.Contains(e.Error(), "2 batch chunk(s) failed") || !strings.Contains(e.Error(), errA.Error()) {
+		t.Errorf("Error() = %q", e.Error())
+	}
+	if !errors.Is(e, errA) || !errors.Is(e, errB) {
+		t.Error("BatchError should unwrap to every chunk error")
+	}
+	if got := e.FailedKeys(); !slices.Equal(got, []string{"/a/1", "/a/2", "/b/1"}) {
+		t.Errorf("FailedKeys() = %v", got)
+	}
+}
+
+func TestBatch_EmptyKey(t *testing.T) {
+	c := newTestClientForPreconditions()
+	ctx := context.Background()
+
+	if err := c.PutBatch(ctx, map[string][]byte{"": []byte("v")}); err != ErrEmptyKey {
+		t.Errorf("PutBatch() = %v, want %v", err, ErrEmptyKey)
+	}
+	if err := c.DeleteBatch(ctx, []string{"/a", ""}); err != ErrEmptyKey {
+		t.Errorf("DeleteBatch() = %v, want %v", err, ErrEmptyKey)
+	}
+}
+
+func TestBatch_EmptyInput(t *testing.T) {
+	c := newTestClientForPreconditions()
+	ctx := context.Background()
+
+	if err := c.PutBatch(ctx, nil); err != nil {
+		t.Errorf("PutBatch(nil) = %v, want nil", err)
+	}
+	if err := c.DeleteBatch(ctx, nil); err != nil {
+		t.Errorf("DeleteBatch(nil) = %v, want nil", err)
+	}
+}
+
+func TestBatch_ContextCanceled(t *testing.T) {
+	c := newTestClientForPreconditions()
+	c.maxTxnOps = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := c.DeleteBatch(ctx, []string{"/a", "/b", "/c"})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("DeleteBatch() = %v, want *BatchError", err)
+	}
+	if len(batchErr.Chunks) != 2 || !errors.Is(err, context.Canceled) {
+		t.Errorf("chunks = %+v, want 2 canceled chunks", batchErr.Chunks)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
 )
 
 // Client etcd 客户端封装。
@@ -19,7 +22,11 @@ import (
 type Client struct {
 	client    etcdClient // 使用接口以支持测试时的 mock 注入
 	rawClient *clientv3.Client
-	config    *Config // 保留已规范化的配置副本，用于调试和未来扩展（如 Reconnect、config 审计）
+	config    *Config           // 保留已规范化的配置副本，用于调试和未来扩展（如 Reconnect、config 审计）
+	opTimeout time.Duration     // 单次 KV 操作的默认超时，0 表示不设置
+	maxTxnOps int               // 批量操作单个事务的最大操作数，0 表示使用 DefaultMaxTxnOps
+	observer  xmetrics.Observer // 可选的观测接口，nil 表示不观测
+	connState connStateSource   // 底层 gRPC 连接状态来源，WatchConnectionState 使用
 	closed    atomic.Bool
 	closeCh   chan struct{}  // 关闭信号通道，用于通知 Watch goroutine 退出
 	watchWg   sync.WaitGroup // 追踪活跃的 Watch goroutine，确保 Close 时等待退出
@@ -131,12 +138,20 @@ func NewClient(config *Config, opts ...Option) (*Client, error) {
 		}
 	}
 
-	return &Client{
+	c := &Client{
 		client:    rawClient,
 		rawClient: rawClient,
 		config:    cfg,
+		opTimeout: o.opTimeout,
+		maxTxnOps: o.maxTxnOps,
+		observer:  o.observer,
 		closeCh:   make(chan struct{}),
-	}, nil
+	}
+	// 避免将 nil *grpc.ClientConn 存为非 nil 接口
+	if conn := rawClient.ActiveConnection(); conn != nil {
+		c.connState = conn
+	}
+	return c, nil
 }
 
 // RawClient 返回原生 etcd 客户端。
@@ -207,3 +222,12 @@ func (c *Client) checkPreconditions(ctx context.Context) error {
 	}
 	return c.checkClosed()
 }
+
+// withOperationTimeout 按 WithOperationTimeout 配置为单次操作派生带超时的 context。
+// 未配置超时时原样返回 ctx 和空操作的 cancel，调用方统一 defer cancel()。
+func (c *Client) withOperationTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.opTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.opTimeout)
+}
@@ -0,0 +1,146 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// ConnState etcd 连接状态。
+type ConnState int
+
+const (
+	// ConnStateConnected 连接可用（gRPC Ready，或 Idle 空闲但可随时发起请求）。
+	ConnStateConnected ConnState = iota + 1
+	// ConnStateDisconnected 连接不可用（gRPC Connecting / TransientFailure，正在建连或重连）。
+	ConnStateDisconnected
+)
+
+// String 返回连接状态的字符串表示。
+func (s ConnState) String() string {
+	switch s {
+	case ConnStateConnected:
+		return "CONNECTED"
+	case ConnStateDisconnected:
+		return "DISCONNECTED"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int(s))
+	}
+}
+
+// DefaultConnStateDebounce 默认连接状态去抖时间。
+const DefaultConnStateDebounce = time.Second
+
+// connStateSource 底层连接状态来源，用于依赖注入和测试。
+// *grpc.ClientConn 实现了此接口。
+type connStateSource interface {
+	GetState() connectivity.State
+	WaitForStateChange(ctx context.Context, sourceState connectivity.State) bool
+}
+
+// connStateOptions 连接状态监听选项。
+type connStateOptions struct {
+	debounce time.Duration
+}
+
+// ConnStateOption 连接状态监听选项函数。
+type ConnStateOption func(*connStateOptions)
+
+// WithConnStateDebounce 设置状态去抖时间，默认 DefaultConnStateDebounce（1 秒）。
+// 新状态需持续 d 才会回调；期间状态变回已通知的状态则不回调。
+// d <= 0 表示不去抖，每次状态变化立即回调。
+func WithConnStateDebounce(d time.Duration) ConnStateOption {
+	return func(o *connStateOptions) {
+		o.debounce = max(d, 0)
+	}
+}
+
+// WatchConnectionState 监听底层 gRPC 连接的状态变化，状态变化时调用 callback。
+//
+// 首次回调为订阅后稳定下来的当前状态，之后仅在状态变化时回调，
+// 便于依赖 etcd 的组件在连接断开时主动降级、恢复后取消降级，而非等到操作失败才感知。
+// callback 在单个后台 goroutine 中串行调用，不应长时间阻塞。
+//
+// 重连由 etcd 客户端（gRPC）自动完成，期间的 Connecting / TransientFailure 均视为
+// ConnStateDisconnected；重连过程中的短暂抖动由去抖时间（见 WithConnStateDebounce）吸收。
+//
+// ctx 取消或 Client 关闭时停止监听。callback 为 nil 返回 ErrNilCallback。
+func (c *Client) WatchConnectionState(ctx context.Context, callback func(ConnState), opts ...ConnStateOption) error {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return err
+	}
+	if callback == nil {
+		return ErrNilCallback
+	}
+	if c.connState == nil {
+		return ErrNotInitialized
+	}
+
+	o := &connStateOptions{debounce: DefaultConnStateDebounce}
+	for _, opt := range opts {
+		if opt == nil {
+			return ErrNilOption
+		}
+		opt(o)
+	}
+
+	return c.registerWatchGoroutine(func() {
+		c.runConnStateLoop(ctx, callback, o.debounce)
+	})
+}
+
+// runConnStateLoop 运行连接状态监听循环。
+//
+// 设计决策: 以 WaitForStateChange 驱动而非轮询，状态变化即时唤醒；
+// 去抖通过"带超时等待下一次变化"实现——超时未变化即认为新状态已稳定。
+func (c *Client) runConnStateLoop(ctx context.Context, callback func(ConnState), debounce time.Duration) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var reported ConnState
+	for {
+		raw := c.connState.GetState()
+		if raw == connectivity.Shutdown {
+			return
+		}
+		state := toConnState(raw)
+		if state == reported {
+			if !c.connState.WaitForStateChange(ctx, raw) {
+				return
+			}
+			continue
+		}
+		if debounce > 0 {
+			dctx, dcancel := context.WithTimeout(ctx, debounce)
+			changed := c.connState.WaitForStateChange(dctx, raw)
+			dcancel()
+			if changed {
+				continue // 未稳定，按最新状态重新计时
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		reported = state
+		callback(state)
+	}
+}
+
+// toConnState 将 gRPC 连接状态映射为 ConnState。
+func toConnState(s connectivity.State) ConnState {
+	switch s {
+	case connectivity.Ready, connectivity.Idle:
+		return ConnStateConnected
+	default:
+		return ConnStateDisconnected
+	}
+}
@@ -0,0 +1,242 @@
+package xetcd
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/connectivity"
+)
+
+// fakeConnState 可手动切换状态的 connStateSource。
+type fakeConnState struct {
+	mu      sync.Mutex
+	state   connectivity.State
+	changed chan struct{}
+}
+
+func newFakeConnState(s connectivity.State) *fakeConnState {
+	return &fakeConnState{state: s, changed: make(chan struct{})}
+}
+
+func (f *fakeConnState) GetState() connectivity.State {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.state
+}
+
+func (f *fakeConnState) WaitForStateChange(ctx context.Context, source connectivity.State) bool {
+	for {
+		f.mu.Lock()
+		if f.state != source {
+			f.mu.Unlock()
+			return true
+		}
+		ch := f.changed
+		f.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return false
+		case <-ch:
+		}
+	}
+}
+
+func (f *fakeConnState) set(s connectivity.State) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.state = s
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+// closableEtcdClient 允许 Close 的 noopEtcdClient，用于验证 Client.Close 与监听 goroutine 的协作。
+type closableEtcdClient struct {
+	noopEtcdClient
+}
+
+func (c *closableEtcdClient) Close() error { return nil }
+
+func newConnStateTestClient(src connStateSource) *Client {
+	return &Client{
+		client:    &closableEtcdClient{},
+		config:    &Config{Endpoints: []string{"localhost:2379"}},
+		connState: src,
+		closeCh:   make(chan struct{}),
+	}
+}
+
+// recvState 等待下一次回调。
+func recvState(t *testing.T, ch <-chan ConnState) ConnState {
+	t.Helper()
+	select {
+	case s := <-ch:
+		return s
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for connection state callback")
+		return 0
+	}
+}
+
+func expectNoState(t *testing.T, ch <-chan ConnState, wait time.Duration) {
+	t.Helper()
+	select {
+	case s := <-ch:
+		t.Fatalf("unexpected callback: %v", s)
+	case <-time.After(wait):
+	}
+}
+
+func TestWatchConnectionState_Preconditions(t *testing.T) {
+	c := newConnStateTestClient(newFakeConnState(connectivity.Ready))
+	cb := func(ConnState) {}
+
+	//nolint:staticcheck // 测试 nil ctx 防御
+	if err := c.WatchConnectionState(nil, cb); err != ErrNilContext {
+		t.Errorf("nil ctx = %v, want ErrNilContext", err)
+	}
+	if err := c.WatchConnectionState(context.Background(), nil); err != ErrNilCallback {
+		t.Errorf("nil callback = %v, want ErrNilCallback", err)
+	}
+	if err := c.WatchConnectionState(context.Background(), cb, nil); err != ErrNilOption {
+		t.Errorf("nil option = %v, want ErrNilOption", err)
+	}
+	if err := newConnStateTestClient(nil).WatchConnectionState(context.Background(), cb); err != ErrNotInitialized {
+		t.Errorf("nil source = %v, want ErrNotInitialized", err)
+	}
+
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := c.WatchConnectionState(context.Background(), cb); err != ErrClientClosed {
+		t.Errorf("closed client = %v, want ErrClientClosed", err)
+	}
+}
+
+func TestWatchConnectionState_Transitions(t *testing.T) {
+	src := newFakeConnState(connectivity.Ready)
+	c := newConnStateTestClient(src)
+	defer func() { _ = c.Close(context.Background()) }()
+
+	states := make(chan ConnState, 16)
+	if err := c.WatchConnectionState(context.Background(), func(s ConnState) { states <- s },
+		WithConnStateDebounce(0)); err != nil {
+		t.Fatalf("WatchConnectionState: %v", err)
+	}
+
+	if s := recvState(t, states); s != ConnStateConnected {
+		t.Fatalf("initial state = %v, want CONNECTED", s)
+	}
+
+	src.set(connectivity.TransientFailure)
+	if s := recvState(t, states); s != ConnStateDisconnected {
+		t.Fatalf("state = %v, want DISCONNECTED", s)
+	}
+
+	// 重连过程中 TransientFailure → Connecting 仍属于断开，不重复回调
+	src.set(connectivity.Connecting)
+	expectNoState(t, states, 50*time.Millisecond)
+
+	src.set(connectivity.Ready)
+	if s := recvState(t, states); s != ConnStateConnected {
+		t.Fatalf("state = %v, want CONNECTED", s)
+	}
+
+	// Ready → Idle 仍属于可用
+	src.set(connectivity.Idle)
+	expectNoState(t, states, 50*time.Millisecond)
+}
+
+func TestWatchConnectionState_Debounce(t *testing.T) {
+	src := newFakeConnState(connectivity.Ready)
+	c := newConnStateTestClient(src)
+	defer func() { _ = c.Close(context.Background()) }()
+
+	states := make(chan ConnState, 16)
+	if err := c.WatchConnectionState(context.Background(), func(s ConnState) { states <- s },
+		WithConnStateDebounce(100*time.Millisecond)); err != nil {
+		t.Fatalf("WatchConnectionState: %v", err)
+	}
+	if s := recvState(t, states); s != ConnStateConnected {
+		t.Fatalf("initial state = %v, want CONNECTED", s)
+	}
+
+	// 短暂抖动被吸收
+	src.set(connectivity.TransientFailure)
+	time.Sleep(20 * time.Millisecond)
+	src.set(connectivity.Ready)
+	expectNoState(t, states, 200*time.Millisecond)
+
+	// 持续断开超过去抖时间后回调
+	start := time.Now()
+	src.set(connectivity.TransientFailure)
+	if s := recvState(t, states); s != ConnStateDisconnected {
+		t.Fatalf("state = %v, want DISCONNECTED", s)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("callback after %v, want >= debounce", elapsed)
+	}
+}
+
+func TestWatchConnectionState_Stop(t *testing.T) {
+	t.Run("ctx 取消", func(t *testing.T) {
+		c := newConnStateTestClient(newFakeConnState(connectivity.Ready))
+		ctx, cancel := context.WithCancel(context.Background())
+		states := make(chan ConnState, 16)
+		if err := c.WatchConnectionState(ctx, func(s ConnState) { states <- s }, WithConnStateDebounce(0)); err != nil {
+			t.Fatalf("WatchConnectionState: %v", err)
+		}
+		recvState(t, states)
+		cancel()
+		// Close 等待监听 goroutine 退出
+		if err := c.Close(context.Background()); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	})
+
+	t.Run("Client 关闭", func(t *testing.T) {
+		c := newConnStateTestClient(newFakeConnState(connectivity.Connecting))
+		if err := c.WatchConnectionState(context.Background(), func(ConnState) {}); err != nil {
+			t.Fatalf("WatchConnectionState: %v", err)
+		}
+		done := make(chan struct{})
+		go func() {
+			_ = c.Close(context.Background())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatal("Close blocked by connection state watcher")
+		}
+	})
+
+	t.Run("连接 Shutdown", func(t *testing.T) {
+		src := newFakeConnState(connectivity.Ready)
+		c := newConnStateTestClient(src)
+		states := make(chan ConnState, 16)
+		if err := c.WatchConnectionState(context.Background(), func(s ConnState) { states <- s }, WithConnStateDebounce(0)); err != nil {
+			t.Fatalf("WatchConnectionState: %v", err)
+		}
+		recvState(t, states)
+		src.set(connectivity.Shutdown)
+		expectNoState(t, states, 50*time.Millisecond)
+		if err := c.Close(context.Background()); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	})
+}
+
+func TestConnState_String(t *testing.T) {
+	tests := map[ConnState]string{
+		ConnStateConnected:    "CONNECTED",
+		ConnStateDisconnected: "DISCONNECTED",
+		ConnState(0):          "UNKNOWN(0)",
+	}
+	for s, want := range tests {
+		if got := s.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(s), got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Increment 原子地将 key 上的计数器增加 delta，返回增加后的值。
+// delta 可以为负数（即自减）。
+//
+// 计数器以十进制字符串存储（如 "42"），可直接用 Get/etcdctl 查看。
+// key 不存在时视为 0，即首次 Increment 返回 delta。
+//
+// 实现基于 Txn 的 CAS 循环：
+//   - key 不存在时比较 CreateRevision == 0，防止并发首写互相覆盖
+//   - key 存在时比较 ModRevision，保证读-改-写期间未被他人修改
+//   - 比较失败时 Else 分支随事务返回最新值，直接用于下一轮重试，无需额外 Get
+//
+// 每次冲突意味着其他调用方已成功写入，因此整体总能推进；
+// 重试不设次数上限，由 ctx 的超时/取消控制最长等待时间。
+//
+// 已存在的 key 若绑定了租约（如 PutWithTTL 写入），自增后保留原租约。
+//
+// 错误：
+//   - ErrEmptyKey: key 为空
+//   - ErrInvalidCounter: 现有值不是合法的十进制 int64（不会被覆盖）
+//   - ErrCounterOverflow: 结果超出 int64 范围（不会写入）
+func (c *Client) Increment(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return 0, err
+	}
+	if key == "" {
+		return 0, ErrEmptyKey
+	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.client.Get(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("xetcd: increment %q: %w", key, err)
+	}
+
+	for {
+		var (
+			cur  int64
+			cmp  clientv3.Cmp
+			opts []clientv3.OpOption
+		)
+		if len(resp.Kvs) == 0 {
+			cmp = clientv3.Compare(clientv3.CreateRevision(key), "=", 0)
+		} else {
+			kv := resp.Kvs[0]
+			cur, err = parseCounter(kv.Value)
+			if err != nil {
+				return 0, fmt.Errorf("xetcd: increment %q: %w", key, err)
+			}
+			cmp = clientv3.Compare(clientv3.ModRevision(key), "=", kv.ModRevision)
+			// WithIgnoreLease 要求 key 已存在，仅在此分支使用
+			if kv.Lease != 0 {
+				opts = append(opts, clientv3.WithIgnoreLease())
+			}
+		}
+
+		next, err := addCounter(cur, delta)
+		if err != nil {
+			return 0, fmt.Errorf("xetcd: increment %q: %w", key, err)
+		}
+
+		txnResp, err := c.client.Txn(ctx).
+			If(cmp).
+			Then(clientv3.OpPut(key, strconv.FormatInt(next, 10), opts...)).
+			Else(clientv3.OpGet(key)).
+			Commit()
+		if err != nil {
+			return 0, fmt.Errorf("xetcd: increment %q: %w", key, err)
+		}
+		if txnResp.Succeeded {
+			return next, nil
+		}
+
+		// 并发冲突：使用 Else 分支返回的最新值重试
+		resp = (*clientv3.GetResponse)(txnResp.Responses[0].GetResponseRange())
+	}
+}
+
+// parseCounter 解析计数器值。
+func parseCounter(value []byte) (int64, error) {
+	n, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCounter, value)
+	}
+	return n, nil
+}
+
+// addCounter 计算 cur + delta，溢出时返回 ErrCounterOverflow。
+func addCounter(cur, delta int64) (int64, error) {
+	if (delta > 0 && cur > math.MaxInt64-delta) || (delta < 0 && cur < math.MinInt64-delta) {
+		return 0, ErrCounterOverflow
+	}
+	return cur + delta, nil
+}
@@ -0,0 +1,89 @@
+package xetcd
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseCounter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"zero", "0", 0, false},
+		{"positive", "42", 42, false},
+		{"negative", "-7", -7, false},
+		{"max", "9223372036854775807", math.MaxInt64, false},
+		{"empty", "", 0, true},
+		{"not number", "abc", 0, true},
+		{"float", "1.5", 0, true},
+		{"out of range", "9223372036854775808", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCounter([]byte(tt.value))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCounter) {
+					t.Errorf("parseCounter(%q) err = %v, want ErrInvalidCounter", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCounter(%q) unexpected err: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseCounter(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		cur      int64
+		delta    int64
+		want     int64
+		overflow bool
+	}{
+		{"simple", 1, 2, 3, false},
+		{"decrement", 1, -2, -1, false},
+		{"to max", math.MaxInt64 - 1, 1, math.MaxInt64, false},
+		{"to min", math.MinInt64 + 1, -1, math.MinInt64, false},
+		{"overflow", math.MaxInt64, 1, 0, true},
+		{"underflow", math.MinInt64, -1, 0, true},
+		{"large delta", 1, math.MaxInt64, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := addCounter(tt.cur, tt.delta)
+			if tt.overflow {
+				if !errors.Is(err, ErrCounterOverflow) {
+					t.Errorf("addCounter(%d, %d) err = %v, want ErrCounterOverflow", tt.cur, tt.delta, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("addCounter(%d, %d) unexpected err: %v", tt.cur, tt.delta, err)
+			}
+			if got != tt.want {
+				t.Errorf("addCounter(%d, %d) = %d, want %d", tt.cur, tt.delta, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncrement_EmptyKey(t *testing.T) {
+	c := &Client{
+		client:  &noopEtcdClient{},
+		config:  &Config{Endpoints: []string{"localhost:2379"}},
+		closeCh: make(chan struct{}),
+	}
+	if _, err := c.Increment(context.Background(), "", 1); !errors.Is(err, ErrEmptyKey) {
+		t.Errorf("Increment empty key: err = %v, want ErrEmptyKey", err)
+	}
+}
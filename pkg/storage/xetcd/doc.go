@@ -1,10 +1,16 @@
 // Package xetcd 提供 etcd 客户端封装。
 //
 // xetcd 是 xkit 存储模块的一部分，提供：
-//   - 简化的 KV 操作 (Get/Put/Delete/List/Exists/Count)
+//   - 简化的 KV 操作 (Get/GetOrDefault/Put/Delete/List/Exists/Count)
+//   - ListKV 支持分页（WithLimit/WithFromKey）与排序（WithSort）的前缀列举
 //   - PutWithTTL 带租约的键值写入
+//   - Increment 基于 Txn CAS 的原子计数器
+//   - CompareAndSwap/CompareAndSwapRevision/PutIfAbsent 常用的条件写入
+//   - PutBatch/DeleteBatch 按 WithMaxTxnOps 分块、每块一个事务的批量写入与删除
+//   - Register 基于租约续约的服务注册（自动重新注册、静默中断检测）
 //   - Watch 功能，监听键值变化
-//   - WatchWithRetry 带自动重连和指数退避（含随机抖动）的 Watch
+//   - WatchWithRetry 带自动重连和指数退避（含随机抖动）的 Watch，支持 compaction 恢复
+//   - WatchConnectionState 监听底层 gRPC 连接的断开/恢复（带去抖），便于上层主动降级
 //   - 与 xdlock 分布式锁的集成
 //
 // # 错误处理
@@ -13,27 +19,34 @@
 // 返回 ErrNilContext，避免 nil ctx 传递到 etcd 客户端导致 panic。
 // Close 方法例外：ctx 参数当前仅用于未来扩展（D-02 决策），nil 时不返回错误。
 //
+// WithOperationTimeout 可为每次 KV 操作设置默认超时，避免调用方传入无 deadline 的 ctx
+// 时在 etcd 不可达的情况下长时间阻塞。
+//
 // # 设计边界
 //
 // 设计决策: xetcd 定位为简化的 KV + Watch 封装，不提供以下高级功能：
-//   - 事务（Txn/CAS/PutIfAbsent）：通过 RawClient() 使用原生 etcd 事务 API
-//   - 租约续约（KeepAlive）：通过 RawClient() 使用原生租约 API
+//   - 通用事务（多 key、多分支 Txn）：通过 RawClient() 使用原生 etcd 事务 API
+//     （仅内置 CAS/PutIfAbsent/Increment 这类单 key 条件写入，比较失败返回 false 而非错误；
+//     PutBatch/DeleteBatch 的事务只用于减少往返，不带条件，也不保证跨分块原子性）
+//   - 通用租约续约（KeepAlive）：通过 RawClient() 使用原生租约 API
+//     （Register 仅覆盖"单键 + 单租约"的服务注册场景）
 //
-// 这些功能的使用场景（服务注册、分布式选主等）需要更复杂的生命周期管理，
+// 这些功能的使用场景（多键共享租约、分布式选主等）需要更复杂的生命周期管理，
 // 由调用方根据具体需求直接操作 RawClient() 更为灵活。
 //
 // 设计决策: xetcd 不内建连接重试和周期性健康探测。
 // 初始化重试和持续健康检查属于上层框架的职责（如服务启动编排、健康检查端点），
 // xetcd 作为基础客户端封装不应假设调用方的重试策略。
 // WithHealthCheck 提供一次性创建阶段检查，满足 fail-fast 需求。
-//
-// 设计决策: xetcd 不内建可观测性（Trace/Metrics）。
-// etcd 官方客户端已通过 gRPC interceptor 提供基础的 RPC 级别追踪和指标。
-// 当前 xetcd 不暴露 gRPC DialOption 注入口，如需自定义 interceptor，
-// 请通过 RawClient() 获取原生客户端后使用 gRPC 拦截器，
-// 或直接使用 clientv3.New 构造带 DialOptions 的客户端。
-// 若未来需要统一可观测性，可通过 WithDialOptions/WithTracer/WithMeter Option 扩展，
-// 当前阶段避免引入 xmetrics 依赖以保持包的轻量性。
+// WatchConnectionState 只被动观察 etcd 客户端自身的 gRPC 连接状态（重连由 gRPC 完成），
+// 不发起额外探测请求，因此不与此决策冲突。
+//
+// 设计决策: xetcd 的可观测性为可选项（opt-in）。
+// 通过 WithObserver 注入 xmetrics.Observer 后，Get/Put/Delete/List 会创建 span
+// 并记录统一的调用计数与耗时直方图（component="xetcd"，operation 为 get/put/delete/list）；
+// 未设置时不产生任何观测开销。其余操作（Watch、Txn、批量操作等）不在观测范围内。
+// etcd 官方客户端也可通过 gRPC interceptor 提供 RPC 级别的追踪和指标，
+// 如需自定义 interceptor，请直接使用 clientv3.New 构造带 DialOptions 的客户端。
 //
 // # 与 xdlock 集成
 //
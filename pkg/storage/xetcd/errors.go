@@ -44,6 +44,11 @@ var (
 	// WatchWithRetry 在耗尽重试次数后，通过错误事件发送此错误。
 	ErrMaxRetriesExceeded = errors.New("xetcd: max retries exceeded")
 
+	// ErrWatchCompacted Watch 的恢复点已被 compaction 清除。
+	// 仅在通过 WithCompactionRecovery(false) 禁用自动恢复时，
+	// 由 WatchWithRetry 以错误事件发送，随后关闭通道。
+	ErrWatchCompacted = errors.New("xetcd: watch revision compacted")
+
 	// ErrNilOption 选项函数为空。
 	// 传入 nil 的 Option 或 WatchOption 会导致 nil function call panic，
 	// 此错误用于防御性校验。与 xconf.ErrNilOption 保持一致。
@@ -54,6 +59,26 @@ var (
 	// 避免 nil 指针 panic。
 	ErrNotInitialized = errors.New("xetcd: client not initialized, use NewClient to create")
 
+	// ErrInvalidCounter 计数器键的现有值不是合法的十进制 int64。
+	// Increment 不会覆盖非数字值，避免误毁其他用途的数据。
+	ErrInvalidCounter = errors.New("xetcd: counter value is not a valid int64")
+
+	// ErrCounterOverflow 计数器自增后超出 int64 范围。
+	ErrCounterOverflow = errors.New("xetcd: counter overflow")
+
+	// ErrInvalidTTL TTL 必须为正数。
+	// Register 依赖租约实现自动过期，ttl <= 0 无法构成有效注册。
+	ErrInvalidTTL = errors.New("xetcd: ttl must be positive")
+
+	// ErrLeaseLost 注册租约丢失且重新注册失败。
+	// 通常由网络分区、etcd 不可达或租约被外部撤销引起，
+	// 通过 Registration.Err() 返回（Done() 关闭后）。
+	ErrLeaseLost = errors.New("xetcd: lease lost")
+
+	// ErrNilCallback 回调函数为空。
+	// WatchConnectionState 传入 nil callback 时返回此错误。
+	ErrNilCallback = errors.New("xetcd: callback must not be nil")
+
 	// errNilKv 内部错误：收到 Kv 为 nil 的 etcd 事件。
 	// 正常协议中不应出现，但防御性处理以避免 goroutine panic。
 	errNilKv = errors.New("xetcd: received event with nil Kv")
@@ -2,11 +2,14 @@ package xetcd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
 )
 
 // Get 获取键值。
@@ -23,6 +26,22 @@ func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
 	return value, err
 }
 
+// GetOrDefault 获取键值，键不存在时返回 def。
+//
+// 仅在 RPC 失败等真实错误时返回 error，调用方无需手动区分 ErrKeyNotFound。
+// 键存在但值为空时原样返回空值，不会被替换为 def。
+// 返回的 def 即调用方传入的切片本身，不做拷贝。
+func (c *Client) GetOrDefault(ctx context.Context, key string, def []byte) ([]byte, error) {
+	value, err := c.Get(ctx, key)
+	if errors.Is(err, ErrKeyNotFound) {
+		return def, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
 // GetWithRevision 获取键值和版本号。
 //
 // 参数：
@@ -33,13 +52,18 @@ func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
 //   - []byte: 键值
 //   - int64: 版本号（ModRevision）
 //   - error: 获取失败时返回错误
-func (c *Client) GetWithRevision(ctx context.Context, key string) ([]byte, int64, error) {
+func (c *Client) GetWithRevision(ctx context.Context, key string) (_ []byte, _ int64, err error) {
 	if err := c.checkPreconditions(ctx); err != nil {
 		return nil, 0, err
 	}
 	if key == "" {
 		return nil, 0, ErrEmptyKey
 	}
+	ctx, span := c.startSpan(ctx, opGet, attrKey, key)
+	defer func() { endGetSpan(span, err) }()
+
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Get(ctx, key)
 	if err != nil {
@@ -55,15 +79,20 @@ func (c *Client) GetWithRevision(ctx context.Context, key string) ([]byte, int64
 }
 
 // Put 写入键值。
-func (c *Client) Put(ctx context.Context, key string, value []byte) error {
+func (c *Client) Put(ctx context.Context, key string, value []byte) (err error) {
 	if err := c.checkPreconditions(ctx); err != nil {
 		return err
 	}
 	if key == "" {
 		return ErrEmptyKey
 	}
+	ctx, span := c.startSpan(ctx, opPut, attrKey, key)
+	defer func() { span.End(xmetrics.Result{Err: err}) }()
 
-	_, err := c.client.Put(ctx, key, string(value))
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	_, err = c.client.Put(ctx, key, string(value))
 	if err != nil {
 		return fmt.Errorf("xetcd: put %q: %w", key, err)
 	}
@@ -81,6 +110,8 @@ func (c *Client) PutWithTTL(ctx context.Context, key string, value []byte, ttl t
 	if key == "" {
 		return ErrEmptyKey
 	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 	// 设计决策: ttl <= 0 时降级为普通 Put（永不过期），而非返回错误。
 	// 这简化了调用方的动态 TTL 计算场景（计算结果可能为 0），
 	// 同时与 Go 标准库零值行为一致（如 time.Duration 零值 = 无等待）。
@@ -112,15 +143,20 @@ func (c *Client) PutWithTTL(ctx context.Context, key string, value []byte, ttl t
 }
 
 // Delete 删除键值。键不存在时不返回错误。
-func (c *Client) Delete(ctx context.Context, key string) error {
+func (c *Client) Delete(ctx context.Context, key string) (err error) {
 	if err := c.checkPreconditions(ctx); err != nil {
 		return err
 	}
 	if key == "" {
 		return ErrEmptyKey
 	}
+	ctx, span := c.startSpan(ctx, opDelete, attrKey, key)
+	defer func() { span.End(xmetrics.Result{Err: err}) }()
 
-	_, err := c.client.Delete(ctx, key)
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	_, err = c.client.Delete(ctx, key)
 	if err != nil {
 		return fmt.Errorf("xetcd: delete %q: %w", key, err)
 	}
@@ -135,6 +171,8 @@ func (c *Client) DeleteWithPrefix(ctx context.Context, prefix string) (int64, er
 	if prefix == "" {
 		return 0, ErrEmptyKey
 	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Delete(ctx, prefix, clientv3.WithPrefix())
 	if err != nil {
@@ -147,25 +185,31 @@ func (c *Client) DeleteWithPrefix(ctx context.Context, prefix string) (int64, er
 //
 // ⚠️ 注意：此方法一次性加载所有匹配的键值到内存中，
 // 不适用于前缀下有大量 key 的场景（如数万个服务实例），可能导致内存暴涨。
-// 大量 key 场景请使用 RawClient() 配合 clientv3.WithLimit/clientv3.WithRange 自行分页。
+// 大量 key 场景请使用 ListKV 配合 WithLimit/WithFromKey 分页。
 //
-// 设计决策: 不在 List 内部添加结果集大小限制（如 WithLimit），
-// 因为 xetcd 定位为简化的 KV 封装，分页逻辑涉及游标管理和多次请求，
-// 超出了简化 API 的设计边界。需要分页的场景应使用 RawClient()。
-func (c *Client) List(ctx context.Context, prefix string) (map[string][]byte, error) {
+// 设计决策: 不在 List 内部添加结果集大小限制，保持其"一次取全量"的简单语义；
+// 分页与排序由 ListKV 提供，游标（上一页最后一个 key）由调用方管理。
+func (c *Client) List(ctx context.Context, prefix string) (result map[string][]byte, err error) {
 	if err := c.checkPreconditions(ctx); err != nil {
 		return nil, err
 	}
 	if prefix == "" {
 		return nil, ErrEmptyKey
 	}
+	ctx, span := c.startSpan(ctx, opList, attrPrefix, prefix)
+	defer func() {
+		span.End(xmetrics.Result{Err: err, Attrs: []xmetrics.Attr{xmetrics.Int(attrCount, len(result))}})
+	}()
+
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix())
 	if err != nil {
 		return nil, fmt.Errorf("xetcd: list %q: %w", prefix, err)
 	}
 
-	result := make(map[string][]byte, len(resp.Kvs))
+	result = make(map[string][]byte, len(resp.Kvs))
 	for _, kv := range resp.Kvs {
 		result[string(kv.Key)] = kv.Value
 	}
@@ -180,6 +224,8 @@ func (c *Client) ListKeys(ctx context.Context, prefix string) ([]string, error)
 	if prefix == "" {
 		return nil, ErrEmptyKey
 	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
 	if err != nil {
@@ -201,6 +247,8 @@ func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
 	if key == "" {
 		return false, ErrEmptyKey
 	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Get(ctx, key, clientv3.WithCountOnly())
 	if err != nil {
@@ -217,6 +265,8 @@ func (c *Client) Count(ctx context.Context, prefix string) (int64, error) {
 	if prefix == "" {
 		return 0, ErrEmptyKey
 	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
 
 	resp, err := c.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithCountOnly())
 	if err != nil {
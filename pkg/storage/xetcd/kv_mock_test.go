@@ -96,6 +96,65 @@ func TestKV_Get_Error(t *testing.T) {
 	}
 }
 
+// TestKV_GetOrDefault 测试 GetOrDefault 的默认值语义。
+func TestKV_GetOrDefault(t *testing.T) {
+	def := []byte("default")
+	rpcErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		resp    *clientv3.GetResponse
+		err     error
+		want    string
+		wantErr error
+	}{
+		{
+			name: "present",
+			resp: &clientv3.GetResponse{Kvs: []*mvccpb.KeyValue{{Key: []byte("k"), Value: []byte("v")}}},
+			want: "v",
+		},
+		{
+			name: "present but empty",
+			resp: &clientv3.GetResponse{Kvs: []*mvccpb.KeyValue{{Key: []byte("k")}}},
+			want: "",
+		},
+		{
+			name: "not found",
+			resp: &clientv3.GetResponse{},
+			want: "default",
+		},
+		{
+			name:    "rpc error",
+			err:     rpcErr,
+			wantErr: rpcErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockClient := NewMocketcdClient(ctrl)
+			c := newTestClient(t, mockClient)
+			ctx := context.Background()
+
+			mockClient.EXPECT().Get(ctx, "k").Return(tt.resp, tt.err)
+
+			got, err := c.GetOrDefault(ctx, "k", def)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetOrDefault() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got != nil {
+					t.Errorf("GetOrDefault() = %q on error, want nil", got)
+				}
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("GetOrDefault() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestKV_GetWithRevision_Success 测试 GetWithRevision 成功。
 func TestKV_GetWithRevision_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -792,6 +851,13 @@ func TestKV_NilContext(t *testing.T) {
 		}
 	})
 
+	t.Run("GetOrDefault", func(t *testing.T) {
+		_, err := c.GetOrDefault(nil, "key", []byte("d")) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("GetOrDefault(nil, key, d) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
 	t.Run("GetWithRevision", func(t *testing.T) {
 		_, _, err := c.GetWithRevision(nil, "key") //nolint:staticcheck // 测试 nil ctx 防御
 		if err != ErrNilContext {
@@ -854,6 +920,55 @@ func TestKV_NilContext(t *testing.T) {
 			t.Errorf("Count(nil, prefix) = %v, want %v", err, ErrNilContext)
 		}
 	})
+
+	t.Run("Increment", func(t *testing.T) {
+		_, err := c.Increment(nil, "key", 1) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("Increment(nil, key, 1) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("CompareAndSwap", func(t *testing.T) {
+		_, err := c.CompareAndSwap(nil, "key", []byte("a"), []byte("b")) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("CompareAndSwap(nil, ...) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("CompareAndSwapRevision", func(t *testing.T) {
+		_, err := c.CompareAndSwapRevision(nil, "key", 1, []byte("b")) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("CompareAndSwapRevision(nil, ...) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("ListKV", func(t *testing.T) {
+		_, err := c.ListKV(nil, "prefix") //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("ListKV(nil, prefix) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("PutBatch", func(t *testing.T) {
+		err := c.PutBatch(nil, map[string][]byte{"key": nil}) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("PutBatch(nil, kvs) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("DeleteBatch", func(t *testing.T) {
+		err := c.DeleteBatch(nil, []string{"key"}) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("DeleteBatch(nil, keys) = %v, want %v", err, ErrNilContext)
+		}
+	})
+
+	t.Run("PutIfAbsent", func(t *testing.T) {
+		_, err := c.PutIfAbsent(nil, "key", []byte("v")) //nolint:staticcheck // 测试 nil ctx 防御
+		if err != ErrNilContext {
+			t.Errorf("PutIfAbsent(nil, ...) = %v, want %v", err, ErrNilContext)
+		}
+	})
 }
 
 // TestClient_RawClient 测试 RawClient 返回 nil（使用 mock 时）。
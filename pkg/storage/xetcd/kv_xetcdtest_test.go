@@ -3,6 +3,10 @@ package xetcd_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -77,3 +81,478 @@ func TestKV_Integration_Delete(t *testing.T) {
 		t.Errorf("Get after Delete: err = %v, want ErrKeyNotFound", err)
 	}
 }
+
+func TestKV_Integration_Increment(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	got, err := c.Increment(ctx, "counter", 5)
+	if err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if got != 5 {
+		t.Errorf("first Increment = %d, want 5", got)
+	}
+	if got, err = c.Increment(ctx, "counter", -2); err != nil || got != 3 {
+		t.Errorf("Increment(-2) = %d, %v; want 3, nil", got, err)
+	}
+	raw, err := c.Get(ctx, "counter")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(raw) != "3" {
+		t.Errorf("stored value = %q, want %q", raw, "3")
+	}
+}
+
+func TestKV_Integration_IncrementConcurrent(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	const workers, perWorker = 8, 10
+	ctx := context.Background()
+	errCh := make(chan error, workers)
+	for range workers {
+		go func() {
+			for range perWorker {
+				if _, err := c.Increment(ctx, "concurrent", 1); err != nil {
+					errCh <- err
+					return
+				}
+			}
+			errCh <- nil
+		}()
+	}
+	for range workers {
+		if err := <-errCh; err != nil {
+			t.Fatalf("Increment: %v", err)
+		}
+	}
+
+	raw, err := c.Get(ctx, "concurrent")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if want := strconv.Itoa(workers * perWorker); string(raw) != want {
+		t.Errorf("counter = %q, want %q", raw, want)
+	}
+}
+
+func TestKV_Integration_IncrementInvalidAndOverflow(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := c.Put(ctx, "text", []byte("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := c.Increment(ctx, "text", 1); !errors.Is(err, xetcd.ErrInvalidCounter) {
+		t.Errorf("Increment non-numeric: err = %v, want ErrInvalidCounter", err)
+	}
+
+	if err := c.Put(ctx, "max", []byte(strconv.FormatInt(math.MaxInt64, 10))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := c.Increment(ctx, "max", 1); !errors.Is(err, xetcd.ErrCounterOverflow) {
+		t.Errorf("Increment overflow: err = %v, want ErrCounterOverflow", err)
+	}
+}
+
+func TestKV_Integration_IncrementKeepsLease(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := c.PutWithTTL(ctx, "leased", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("PutWithTTL: %v", err)
+	}
+	if _, err := c.Increment(ctx, "leased", 1); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	resp, err := c.RawClient().Get(ctx, "leased")
+	if err != nil {
+		t.Fatalf("raw Get: %v", err)
+	}
+	if len(resp.Kvs) != 1 || resp.Kvs[0].Lease == 0 {
+		t.Errorf("lease lost after Increment: %+v", resp.Kvs)
+	}
+}
+
+func TestKV_Integration_CompareAndSwap(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	swapped, err := c.CompareAndSwap(ctx, "cas", []byte("a"), []byte("b"))
+	if err != nil || swapped {
+		t.Fatalf("CAS on missing key = %v, %v; want false, nil", swapped, err)
+	}
+
+	if err := c.Put(ctx, "cas", []byte("a")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if swapped, err = c.CompareAndSwap(ctx, "cas", []byte("x"), []byte("b")); err != nil || swapped {
+		t.Errorf("CAS mismatch = %v, %v; want false, nil", swapped, err)
+	}
+	if swapped, err = c.CompareAndSwap(ctx, "cas", []byte("a"), []byte("b")); err != nil || !swapped {
+		t.Fatalf("CAS match = %v, %v; want true, nil", swapped, err)
+	}
+	got, err := c.Get(ctx, "cas")
+	if err != nil || string(got) != "b" {
+		t.Errorf("Get after CAS = %q, %v; want %q", got, err, "b")
+	}
+}
+
+func TestKV_Integration_CompareAndSwapRevision(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if swapped, err := c.CompareAndSwapRevision(ctx, "rev", 0, []byte("v1")); err != nil || !swapped {
+		t.Fatalf("CASRevision(0) on missing key = %v, %v; want true, nil", swapped, err)
+	}
+	_, rev, err := c.GetWithRevision(ctx, "rev")
+	if err != nil {
+		t.Fatalf("GetWithRevision: %v", err)
+	}
+	if err := c.Put(ctx, "rev", []byte("v1")); err != nil { // 值不变但版本前进
+		t.Fatalf("Put: %v", err)
+	}
+	if swapped, err := c.CompareAndSwapRevision(ctx, "rev", rev, []byte("v2")); err != nil || swapped {
+		t.Errorf("CASRevision stale = %v, %v; want false, nil", swapped, err)
+	}
+	_, rev, err = c.GetWithRevision(ctx, "rev")
+	if err != nil {
+		t.Fatalf("GetWithRevision: %v", err)
+	}
+	if swapped, err := c.CompareAndSwapRevision(ctx, "rev", rev, []byte("v2")); err != nil || !swapped {
+		t.Errorf("CASRevision current = %v, %v; want true, nil", swapped, err)
+	}
+}
+
+func TestKV_Integration_PutIfAbsent(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if ok, err := c.PutIfAbsent(ctx, "once", []byte("first")); err != nil || !ok {
+		t.Fatalf("first PutIfAbsent = %v, %v; want true, nil", ok, err)
+	}
+	if ok, err := c.PutIfAbsent(ctx, "once", []byte("second")); err != nil || ok {
+		t.Errorf("second PutIfAbsent = %v, %v; want false, nil", ok, err)
+	}
+	got, err := c.Get(ctx, "once")
+	if err != nil || string(got) != "first" {
+		t.Errorf("Get = %q, %v; want %q", got, err, "first")
+	}
+}
+
+func TestKV_Integration_CompareAndSwapKeepsLease(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := c.PutWithTTL(ctx, "leased-cas", []byte("a"), time.Minute); err != nil {
+		t.Fatalf("PutWithTTL: %v", err)
+	}
+	if swapped, err := c.CompareAndSwap(ctx, "leased-cas", []byte("a"), []byte("b")); err != nil || !swapped {
+		t.Fatalf("CAS = %v, %v; want true, nil", swapped, err)
+	}
+	resp, err := c.RawClient().Get(ctx, "leased-cas")
+	if err != nil {
+		t.Fatalf("raw Get: %v", err)
+	}
+	if len(resp.Kvs) != 1 || resp.Kvs[0].Lease == 0 {
+		t.Errorf("lease lost after CAS: %+v", resp.Kvs)
+	}
+}
+
+func TestKV_Integration_Register(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reg, err := c.Register(ctx, "/services/api/1", []byte("10.0.0.1:80"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	got, err := c.Get(ctx, "/services/api/1")
+	if err != nil || string(got) != "10.0.0.1:80" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+
+	if err := reg.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if reg.Err() != nil {
+		t.Errorf("Err() = %v, want nil", reg.Err())
+	}
+	if _, err := c.Get(ctx, "/services/api/1"); !errors.Is(err, xetcd.ErrKeyNotFound) {
+		t.Errorf("Get after Close: err = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestKV_Integration_RegisterContextCancel(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reg, err := c.Register(ctx, "/services/api/2", []byte("x"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	cancel()
+
+	select {
+	case <-reg.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed after ctx cancel")
+	}
+	if !errors.Is(reg.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", reg.Err())
+	}
+	if _, err := c.Get(context.Background(), "/services/api/2"); !errors.Is(err, xetcd.ErrKeyNotFound) {
+		t.Errorf("Get after cancel: err = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestKV_Integration_RegisterLeaseRevoked(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reg, err := c.Register(ctx, "/services/api/3", []byte("x"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	defer func() { _ = reg.Close(ctx) }()
+
+	oldLease := reg.LeaseID()
+	if _, err := c.RawClient().Revoke(ctx, oldLease); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if reg.LeaseID() != oldLease {
+			if _, err := c.Get(ctx, "/services/api/3"); err == nil {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("registration was not restored after lease revoke")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestKV_Integration_RegisterClientClose(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	reg, err := c.Register(context.Background(), "/services/api/4", []byte("x"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := c.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-reg.Done():
+	default:
+		t.Fatal("Client.Close should wait for registration to stop")
+	}
+	if !errors.Is(reg.Err(), xetcd.ErrClientClosed) {
+		t.Errorf("Err() = %v, want ErrClientClosed", reg.Err())
+	}
+}
+
+func TestKV_Integration_ListKVPaging(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, k := range []string{"/cfg/a", "/cfg/b", "/cfg/c", "/cfg/d", "/cfg/e", "/cfh/x"} {
+		if err := c.Put(ctx, k, []byte("v"+k)); err != nil {
+			t.Fatalf("Put %s: %v", k, err)
+		}
+	}
+
+	var (
+		keys []string
+		last string
+	)
+	for {
+		page, err := c.ListKV(ctx, "/cfg/", xetcd.WithLimit(2), xetcd.WithFromKey(last))
+		if err != nil {
+			t.Fatalf("ListKV: %v", err)
+		}
+		for _, kv := range page {
+			keys = append(keys, kv.Key)
+			if string(kv.Value) != "v"+kv.Key || kv.ModRevision == 0 {
+				t.Errorf("unexpected kv: %+v", kv)
+			}
+		}
+		if len(page) < 2 {
+			break
+		}
+		last = page[len(page)-1].Key
+	}
+	if got, want := strings.Join(keys, ","), "/cfg/a,/cfg/b,/cfg/c,/cfg/d,/cfg/e"; got != want {
+		t.Errorf("paged keys = %s, want %s", got, want)
+	}
+}
+
+func TestKV_Integration_ListKVSort(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	for _, k := range []string{"/s/b", "/s/a", "/s/c"} {
+		if err := c.Put(ctx, k, nil); err != nil {
+			t.Fatalf("Put %s: %v", k, err)
+		}
+	}
+
+	desc, err := c.ListKV(ctx, "/s/", xetcd.WithSort(xetcd.SortByKey, xetcd.SortDescend))
+	if err != nil {
+		t.Fatalf("ListKV: %v", err)
+	}
+	if len(desc) != 3 || desc[0].Key != "/s/c" || desc[2].Key != "/s/a" {
+		t.Errorf("descending by key = %+v", desc)
+	}
+
+	byRev, err := c.ListKV(ctx, "/s/", xetcd.WithSort(xetcd.SortByModRevision, xetcd.SortAscend), xetcd.WithLimit(1))
+	if err != nil {
+		t.Fatalf("ListKV: %v", err)
+	}
+	if len(byRev) != 1 || byRev[0].Key != "/s/b" {
+		t.Errorf("oldest by mod revision = %+v, want /s/b", byRev)
+	}
+
+	beyond, err := c.ListKV(ctx, "/s/", xetcd.WithFromKey("/t"))
+	if err != nil {
+		t.Fatalf("ListKV beyond prefix: %v", err)
+	}
+	if len(beyond) != 0 {
+		t.Errorf("expected empty result beyond prefix, got %+v", beyond)
+	}
+}
+
+func TestKV_Integration_PutDeleteBatch(t *testing.T) {
+	c, cleanup := newXetcdClient(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	// 超过 DefaultMaxTxnOps，验证分块提交
+	kvs := make(map[string][]byte, 300)
+	keys := make([]string, 0, 300)
+	for i := range 300 {
+		k := "/batch/" + strconv.Itoa(i)
+		kvs[k] = []byte(strconv.Itoa(i))
+		keys = append(keys, k)
+	}
+
+	if err := c.PutBatch(ctx, kvs); err != nil {
+		t.Fatalf("PutBatch: %v", err)
+	}
+	if n, err := c.Count(ctx, "/batch/"); err != nil || n != 300 {
+		t.Fatalf("Count = %d, %v, want 300", n, err)
+	}
+	if v, err := c.Get(ctx, "/batch/42"); err != nil || string(v) != "42" {
+		t.Errorf("Get /batch/42 = %q, %v", v, err)
+	}
+
+	// 包含重复键与不存在的键
+	if err := c.DeleteBatch(ctx, append(keys[:200:200], "/batch/0", "/batch/missing")); err != nil {
+		t.Fatalf("DeleteBatch: %v", err)
+	}
+	if n, err := c.Count(ctx, "/batch/"); err != nil || n != 100 {
+		t.Errorf("Count after delete = %d, %v, want 100", n, err)
+	}
+}
+
+func TestKV_Integration_PutBatchChunkError(t *testing.T) {
+	srv, err := xetcdtest.New()
+	if err != nil {
+		t.Fatalf("xetcdtest.New: %v", err)
+	}
+	defer srv.Close()
+	// 分块大小超过服务端 --max-txn-ops（默认 128），事务会被拒绝
+	c, err := xetcd.NewClient(&xetcd.Config{
+		Endpoints:   srv.Endpoints(),
+		DialTimeout: 5 * time.Second,
+	}, xetcd.WithMaxTxnOps(200))
+	if err != nil {
+		t.Fatalf("xetcd.NewClient: %v", err)
+	}
+	defer func() { _ = c.Close(context.Background()) }()
+
+	kvs := make(map[string][]byte, 210)
+	for i := range 210 {
+		kvs[fmt.Sprintf("/chunk/%03d", i)] = nil
+	}
+
+	err = c.PutBatch(context.Background(), kvs)
+	var batchErr *xetcd.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("PutBatch = %v, want *BatchError", err)
+	}
+	// 第一块（200 个）被拒绝，第二块（10 个）成功
+	if len(batchErr.Chunks) != 1 || len(batchErr.FailedKeys()) != 200 {
+		t.Errorf("failed chunks = %d, failed keys = %d", len(batchErr.Chunks), len(batchErr.FailedKeys()))
+	}
+	if n, err := c.Count(context.Background(), "/chunk/"); err != nil || n != 10 {
+		t.Errorf("Count = %d, %v, want 10", n, err)
+	}
+}
+
+func TestWatchConnectionState_Integration(t *testing.T) {
+	srv, err := xetcdtest.New()
+	if err != nil {
+		t.Fatalf("xetcdtest.New: %v", err)
+	}
+	c, err := xetcd.NewClient(&xetcd.Config{
+		Endpoints:   srv.Endpoints(),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		srv.Close()
+		t.Fatalf("xetcd.NewClient: %v", err)
+	}
+	defer func() {
+		if err := c.Close(context.Background()); err != nil {
+			t.Logf("xetcd.Client.Close: %v", err)
+		}
+	}()
+
+	states := make(chan xetcd.ConnState, 16)
+	if err := c.WatchConnectionState(context.Background(), func(s xetcd.ConnState) { states <- s },
+		xetcd.WithConnStateDebounce(50*time.Millisecond)); err != nil {
+		t.Fatalf("WatchConnectionState: %v", err)
+	}
+	// 触发一次请求，确保连接从 Idle/Connecting 进入 Ready
+	if _, err := c.Exists(context.Background(), "conn-state"); err != nil {
+		t.Fatalf("Exists: %v", err)
+	}
+
+	wait := func(want xetcd.ConnState) {
+		t.Helper()
+		deadline := time.After(10 * time.Second)
+		for {
+			select {
+			case s := <-states:
+				if s == want {
+					return
+				}
+			case <-deadline:
+				t.Fatalf("timeout waiting for %v", want)
+			}
+		}
+	}
+	wait(xetcd.ConnStateConnected)
+
+	srv.Close()
+	wait(xetcd.ConnStateDisconnected)
+}
@@ -0,0 +1,176 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// KeyValue 表示一个键值对及其版本信息。
+type KeyValue struct {
+	// Key 键名。
+	Key string
+	// Value 键值。
+	Value []byte
+	// ModRevision 最后一次修改的版本号，可用于 CompareAndSwapRevision。
+	ModRevision int64
+}
+
+// SortTarget 排序字段。
+type SortTarget int
+
+const (
+	// SortByKey 按键名排序。
+	SortByKey SortTarget = iota
+	// SortByModRevision 按最后修改版本排序。
+	SortByModRevision
+	// SortByCreateRevision 按创建版本排序。
+	SortByCreateRevision
+)
+
+// SortOrder 排序方向。
+type SortOrder int
+
+const (
+	// SortAscend 升序。
+	SortAscend SortOrder = iota
+	// SortDescend 降序。
+	SortDescend
+)
+
+// listOptions ListKV 选项。
+type listOptions struct {
+	limit      int64
+	afterKey   string
+	sortTarget SortTarget
+	sortOrder  SortOrder
+	sorted     bool
+}
+
+// ListOption ListKV 选项函数。
+type ListOption func(*listOptions)
+
+// WithLimit 限制单次返回的最大键数量。非正值表示不限制。
+func WithLimit(n int64) ListOption {
+	return func(o *listOptions) {
+		if n > 0 {
+			o.limit = n
+		}
+	}
+}
+
+// WithFromKey 从 lastKey 之后开始列出（不包含 lastKey 本身），用于分页。
+// 传入上一页最后一个键即可获取下一页。
+// 分页依赖按键名升序（默认顺序），与 WithSort 的其他排序同时使用时结果无意义。
+func WithFromKey(lastKey string) ListOption {
+	return func(o *listOptions) {
+		o.afterKey = lastKey
+	}
+}
+
+// WithSort 设置结果排序。默认按键名升序。
+func WithSort(target SortTarget, order SortOrder) ListOption {
+	return func(o *listOptions) {
+		o.sortTarget = target
+		o.sortOrder = order
+		o.sorted = true
+	}
+}
+
+// ListKV 列出指定前缀下的键值对，支持分页与排序。
+//
+// 与 List 不同，ListKV 返回有序切片并包含 ModRevision，
+// 配合 WithLimit + WithFromKey 可分页遍历大量 key，避免一次性加载全部数据：
+//
+//	var last string
+//	for {
+//	    page, err := client.ListKV(ctx, "/config/", xetcd.WithLimit(500), xetcd.WithFromKey(last))
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // 处理 page ...
+//	    if len(page) < 500 {
+//	        break
+//	    }
+//	    last = page[len(page)-1].Key
+//	}
+//
+// 一致性：每次 ListKV 请求由 etcd 在单个 revision 快照上完成（线性一致读），
+// 同一页内的数据互相一致；但跨页请求可能读到不同 revision，
+// 分页期间发生的写入可能只体现在后续页中。需要跨页一致快照时，
+// 请通过 RawClient() 配合 clientv3.WithRev 固定 revision。
+func (c *Client) ListKV(ctx context.Context, prefix string, opts ...ListOption) ([]KeyValue, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		return nil, ErrEmptyKey
+	}
+
+	o := &listOptions{}
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, ErrNilOption
+		}
+		opt(o)
+	}
+
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	start := prefix
+	// 追加 "\x00" 得到字典序上紧邻 afterKey 的下一个键，实现"不包含 afterKey"
+	if next := o.afterKey + "\x00"; o.afterKey != "" && next > start {
+		start = next
+	}
+	resp, err := c.client.Get(ctx, start, buildListOptions(prefix, o)...)
+	if err != nil {
+		return nil, fmt.Errorf("xetcd: list kv %q: %w", prefix, err)
+	}
+
+	result := make([]KeyValue, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		result = append(result, KeyValue{
+			Key:         string(kv.Key),
+			Value:       kv.Value,
+			ModRevision: kv.ModRevision,
+		})
+	}
+	return result, nil
+}
+
+// buildListOptions 构建 etcd Get 选项：范围上界固定为前缀结束位置。
+func buildListOptions(prefix string, o *listOptions) []clientv3.OpOption {
+	etcdOpts := []clientv3.OpOption{
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)),
+	}
+	if o.limit > 0 {
+		etcdOpts = append(etcdOpts, clientv3.WithLimit(o.limit))
+	}
+	if o.sorted {
+		etcdOpts = append(etcdOpts, clientv3.WithSort(toEtcdSortTarget(o.sortTarget), toEtcdSortOrder(o.sortOrder)))
+	} else {
+		// 显式按 key 升序，保证分页语义不依赖服务端默认行为
+		etcdOpts = append(etcdOpts, clientv3.WithSort(clientv3.SortByKey, clientv3.SortAscend))
+	}
+	return etcdOpts
+}
+
+func toEtcdSortTarget(t SortTarget) clientv3.SortTarget {
+	switch t {
+	case SortByModRevision:
+		return clientv3.SortByModRevision
+	case SortByCreateRevision:
+		return clientv3.SortByCreateRevision
+	default:
+		return clientv3.SortByKey
+	}
+}
+
+func toEtcdSortOrder(o SortOrder) clientv3.SortOrder {
+	if o == SortDescend {
+		return clientv3.SortDescend
+	}
+	return clientv3.SortAscend
+}
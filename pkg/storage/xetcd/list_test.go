@@ -0,0 +1,39 @@
+package xetcd
+
+import (
+	"context"
+	"testing"
+)
+
+func TestListKV_Validation(t *testing.T) {
+	c := &Client{
+		client:  &noopEtcdClient{},
+		config:  &Config{Endpoints: []string{"localhost:2379"}},
+		closeCh: make(chan struct{}),
+	}
+
+	if _, err := c.ListKV(context.Background(), ""); err != ErrEmptyKey {
+		t.Errorf("ListKV(empty prefix) = %v, want ErrEmptyKey", err)
+	}
+	if _, err := c.ListKV(context.Background(), "/p/", nil); err != ErrNilOption {
+		t.Errorf("ListKV(nil option) = %v, want ErrNilOption", err)
+	}
+}
+
+func TestListOptions(t *testing.T) {
+	o := &listOptions{}
+	WithLimit(0)(o)
+	if o.limit != 0 {
+		t.Errorf("WithLimit(0) should be ignored, got %d", o.limit)
+	}
+	WithLimit(10)(o)
+	WithFromKey("/p/b")(o)
+	WithSort(SortByModRevision, SortDescend)(o)
+	if o.limit != 10 || o.afterKey != "/p/b" || !o.sorted ||
+		o.sortTarget != SortByModRevision || o.sortOrder != SortDescend {
+		t.Errorf("unexpected options: %+v", o)
+	}
+	if got := len(buildListOptions("/p/", o)); got != 3 {
+		t.Errorf("buildListOptions len = %d, want 3", got)
+	}
+}
@@ -101,6 +101,20 @@ func (mr *MocketcdKVMockRecorder) Put(ctx, key, val any, opts ...any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MocketcdKV)(nil).Put), varargs...)
 }
 
+// Txn mocks base method.
+func (m *MocketcdKV) Txn(ctx context.Context) v3.Txn {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Txn", ctx)
+	ret0, _ := ret[0].(v3.Txn)
+	return ret0
+}
+
+// Txn indicates an expected call of Txn.
+func (mr *MocketcdKVMockRecorder) Txn(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Txn", reflect.TypeOf((*MocketcdKV)(nil).Txn), ctx)
+}
+
 // MocketcdLease is a mock of etcdLease interface.
 type MocketcdLease struct {
 	ctrl     *gomock.Controller
@@ -140,6 +154,21 @@ func (mr *MocketcdLeaseMockRecorder) Grant(ctx, ttl any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MocketcdLease)(nil).Grant), ctx, ttl)
 }
 
+// KeepAlive mocks base method.
+func (m *MocketcdLease) KeepAlive(ctx context.Context, id v3.LeaseID) (<-chan *v3.LeaseKeepAliveResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeepAlive", ctx, id)
+	ret0, _ := ret[0].(<-chan *v3.LeaseKeepAliveResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KeepAlive indicates an expected call of KeepAlive.
+func (mr *MocketcdLeaseMockRecorder) KeepAlive(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepAlive", reflect.TypeOf((*MocketcdLease)(nil).KeepAlive), ctx, id)
+}
+
 // Revoke mocks base method.
 func (m *MocketcdLease) Revoke(ctx context.Context, id v3.LeaseID) (*v3.LeaseRevokeResponse, error) {
 	m.ctrl.T.Helper()
@@ -291,19 +320,19 @@ func (mr *MocketcdClientMockRecorder) Grant(ctx, ttl any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Grant", reflect.TypeOf((*MocketcdClient)(nil).Grant), ctx, ttl)
 }
 
-// Revoke mocks base method.
-func (m *MocketcdClient) Revoke(ctx context.Context, id v3.LeaseID) (*v3.LeaseRevokeResponse, error) {
+// KeepAlive mocks base method.
+func (m *MocketcdClient) KeepAlive(ctx context.Context, id v3.LeaseID) (<-chan *v3.LeaseKeepAliveResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Revoke", ctx, id)
-	ret0, _ := ret[0].(*v3.LeaseRevokeResponse)
+	ret := m.ctrl.Call(m, "KeepAlive", ctx, id)
+	ret0, _ := ret[0].(<-chan *v3.LeaseKeepAliveResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Revoke indicates an expected call of Revoke.
-func (mr *MocketcdClientMockRecorder) Revoke(ctx, id any) *gomock.Call {
+// KeepAlive indicates an expected call of KeepAlive.
+func (mr *MocketcdClientMockRecorder) KeepAlive(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MocketcdClient)(nil).Revoke), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeepAlive", reflect.TypeOf((*MocketcdClient)(nil).KeepAlive), ctx, id)
 }
 
 // Put mocks base method.
@@ -326,6 +355,35 @@ func (mr *MocketcdClientMockRecorder) Put(ctx, key, val any, opts ...any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MocketcdClient)(nil).Put), varargs...)
 }
 
+// Revoke mocks base method.
+func (m *MocketcdClient) Revoke(ctx context.Context, id v3.LeaseID) (*v3.LeaseRevokeResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, id)
+	ret0, _ := ret[0].(*v3.LeaseRevokeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MocketcdClientMockRecorder) Revoke(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MocketcdClient)(nil).Revoke), ctx, id)
+}
+
+// Txn mocks base method.
+func (m *MocketcdClient) Txn(ctx context.Context) v3.Txn {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Txn", ctx)
+	ret0, _ := ret[0].(v3.Txn)
+	return ret0
+}
+
+// Txn indicates an expected call of Txn.
+func (mr *MocketcdClientMockRecorder) Txn(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Txn", reflect.TypeOf((*MocketcdClient)(nil).Txn), ctx)
+}
+
 // Watch mocks base method.
 func (m *MocketcdClient) Watch(ctx context.Context, key string, opts ...v3.OpOption) v3.WatchChan {
 	m.ctrl.T.Helper()
@@ -20,12 +20,18 @@ func (n *noopEtcdClient) Put(context.Context, string, string, ...clientv3.OpOpti
 func (n *noopEtcdClient) Delete(context.Context, string, ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
 	panic("noopEtcdClient.Delete should not be called")
 }
+func (n *noopEtcdClient) Txn(context.Context) clientv3.Txn {
+	panic("noopEtcdClient.Txn should not be called")
+}
 func (n *noopEtcdClient) Grant(context.Context, int64) (*clientv3.LeaseGrantResponse, error) {
 	panic("noopEtcdClient.Grant should not be called")
 }
 func (n *noopEtcdClient) Revoke(context.Context, clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
 	panic("noopEtcdClient.Revoke should not be called")
 }
+func (n *noopEtcdClient) KeepAlive(context.Context, clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	panic("noopEtcdClient.KeepAlive should not be called")
+}
 func (n *noopEtcdClient) Watch(context.Context, string, ...clientv3.OpOption) clientv3.WatchChan {
 	panic("noopEtcdClient.Watch should not be called")
 }
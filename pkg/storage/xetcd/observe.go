@@ -0,0 +1,53 @@
+package xetcd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+// 可观测性常量
+const (
+	etcdComponent = "xetcd"
+
+	opGet    = "get"
+	opPut    = "put"
+	opDelete = "delete"
+	opList   = "list"
+
+	attrKey    = "xetcd.key"
+	attrPrefix = "xetcd.prefix"
+	attrFound  = "xetcd.found"
+	attrCount  = "xetcd.count"
+)
+
+// startSpan 开始一次 KV 操作的观测。
+// 未配置 Observer 时直接返回原 ctx 和空 Span，不构造属性切片，保持零开销。
+// 设计决策: key 仅作为 span 属性，不进入 metrics 维度（xmetrics 的 metrics 只有
+// component/operation/status 三维），避免高基数标签。
+func (c *Client) startSpan(ctx context.Context, operation, attrName, key string) (context.Context, xmetrics.Span) {
+	if c.observer == nil {
+		return ctx, xmetrics.NoopSpan{}
+	}
+	return xmetrics.Start(ctx, c.observer, xmetrics.SpanOptions{
+		Component: etcdComponent,
+		Operation: operation,
+		Kind:      xmetrics.KindClient,
+		Attrs: []xmetrics.Attr{
+			xmetrics.String("db.system", "etcd"),
+			xmetrics.String(attrName, key),
+		},
+	})
+}
+
+// endGetSpan 结束 Get 的观测。
+// 设计决策: ErrKeyNotFound 是正常的查询结果，记为成功并附加 xetcd.found=false，
+// 避免"查询不存在的键"抬高错误率指标。
+func endGetSpan(span xmetrics.Span, err error) {
+	if errors.Is(err, ErrKeyNotFound) {
+		span.End(xmetrics.Result{Attrs: []xmetrics.Attr{xmetrics.Bool(attrFound, false)}})
+		return
+	}
+	span.End(xmetrics.Result{Err: err})
+}
@@ -0,0 +1,140 @@
+package xetcd
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/mock/gomock"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+)
+
+// recordingObserver 记录 span 的开始参数与结束结果。
+type recordingObserver struct {
+	mu      sync.Mutex
+	starts  []xmetrics.SpanOptions
+	results []xmetrics.Result
+}
+
+func (o *recordingObserver) Start(ctx context.Context, opts xmetrics.SpanOptions) (context.Context, xmetrics.Span) {
+	o.mu.Lock()
+	o.starts = append(o.starts, opts)
+	o.mu.Unlock()
+	return ctx, recordingSpan{o: o}
+}
+
+type recordingSpan struct{ o *recordingObserver }
+
+func (s recordingSpan) End(result xmetrics.Result) {
+	s.o.mu.Lock()
+	s.o.results = append(s.o.results, result)
+	s.o.mu.Unlock()
+}
+
+func attrOf(attrs []xmetrics.Attr, key string) any {
+	for _, a := range attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+// TestClient_Observer 测试 Get/Put/Delete/List 的观测输出。
+func TestClient_Observer(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	obs := &recordingObserver{}
+	c := newTestClient(t, mockClient)
+	c.observer = obs
+	ctx := context.Background()
+	rpcErr := errors.New("unavailable")
+
+	mockClient.EXPECT().Get(gomock.Any(), "k1").Return(&clientv3.GetResponse{
+		Kvs: []*mvccpb.KeyValue{{Key: []byte("k1"), Value: []byte("v")}},
+	}, nil)
+	mockClient.EXPECT().Get(gomock.Any(), "missing").Return(&clientv3.GetResponse{}, nil)
+	mockClient.EXPECT().Put(gomock.Any(), "k1", "v").Return(nil, rpcErr)
+	mockClient.EXPECT().Delete(gomock.Any(), "k1").Return(&clientv3.DeleteResponse{}, nil)
+	mockClient.EXPECT().Get(gomock.Any(), "/p/", gomock.Any()).Return(&clientv3.GetResponse{
+		Kvs: []*mvccpb.KeyValue{{Key: []byte("/p/a")}, {Key: []byte("/p/b")}},
+	}, nil)
+
+	if _, err := c.Get(ctx, "k1"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("Get() error = %v, want ErrKeyNotFound", err)
+	}
+	if err := c.Put(ctx, "k1", []byte("v")); !errors.Is(err, rpcErr) {
+		t.Fatalf("Put() error = %v, want %v", err, rpcErr)
+	}
+	if err := c.Delete(ctx, "k1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := c.List(ctx, "/p/"); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	wantOps := []string{opGet, opGet, opPut, opDelete, opList}
+	if len(obs.starts) != len(wantOps) || len(obs.results) != len(wantOps) {
+		t.Fatalf("got %d starts / %d results, want %d", len(obs.starts), len(obs.results), len(wantOps))
+	}
+	for i, op := range wantOps {
+		s := obs.starts[i]
+		if s.Component != etcdComponent || s.Operation != op || s.Kind != xmetrics.KindClient {
+			t.Errorf("span %d = %s/%s, want %s/%s", i, s.Component, s.Operation, etcdComponent, op)
+		}
+	}
+
+	if got := attrOf(obs.starts[0].Attrs, attrKey); got != "k1" {
+		t.Errorf("get key attr = %v, want k1", got)
+	}
+	if obs.results[0].Err != nil {
+		t.Errorf("get result err = %v, want nil", obs.results[0].Err)
+	}
+	// 键不存在记为成功
+	if obs.results[1].Err != nil || attrOf(obs.results[1].Attrs, attrFound) != false {
+		t.Errorf("not-found result = %+v, want success with found=false", obs.results[1])
+	}
+	if !errors.Is(obs.results[2].Err, rpcErr) {
+		t.Errorf("put result err = %v, want %v", obs.results[2].Err, rpcErr)
+	}
+	if got := attrOf(obs.starts[4].Attrs, attrPrefix); got != "/p/" {
+		t.Errorf("list prefix attr = %v, want /p/", got)
+	}
+	if got := attrOf(obs.results[4].Attrs, attrCount); got != 2 {
+		t.Errorf("list count attr = %v, want 2", got)
+	}
+}
+
+// TestClient_Observer_SkipsInvalidInput 测试参数校验失败时不产生 span。
+func TestClient_Observer_SkipsInvalidInput(t *testing.T) {
+	obs := &recordingObserver{}
+	c := newTestClient(t, nil)
+	c.observer = obs
+
+	if err := c.Put(context.Background(), "", nil); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("Put() error = %v, want ErrEmptyKey", err)
+	}
+	if len(obs.starts) != 0 {
+		t.Errorf("got %d spans, want 0", len(obs.starts))
+	}
+}
+
+// TestWithObserver 测试 WithObserver 选项。
+func TestWithObserver(t *testing.T) {
+	o := defaultOptions()
+	if o.observer != nil {
+		t.Errorf("default observer = %v, want nil", o.observer)
+	}
+	obs := &recordingObserver{}
+	WithObserver(obs)(o)
+	if o.observer != obs {
+		t.Error("WithObserver should set observer")
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/tls"
 	"time"
+
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
 )
 
 // defaultHealthCheckKey 默认健康检查 key。
@@ -19,6 +21,9 @@ type options struct {
 	healthTimeout  time.Duration
 	healthCheckKey string
 	tlsConfig      *tls.Config
+	opTimeout      time.Duration
+	maxTxnOps      int
+	observer       xmetrics.Observer
 }
 
 // defaultOptions 返回默认选项。
@@ -80,3 +85,46 @@ func WithTLS(config *tls.Config) Option {
 		o.tlsConfig = config
 	}
 }
+
+// WithOperationTimeout 设置单次 KV 操作（Get/Put/Delete/List/Increment/CompareAndSwap 等）的默认超时。
+// 默认为 0，表示不额外设置超时，完全由调用方 ctx 控制。
+//
+// 设置后每次操作都会派生一个带超时的 context；调用方 ctx 的 deadline 更早时以调用方为准。
+// Increment 的 CAS 重试循环整体受此超时约束，而非每轮重试单独计时。
+// 不影响 Watch/WatchWithRetry（长连接操作由调用方 ctx 控制生命周期）。
+// 非正值被忽略。
+func WithOperationTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.opTimeout = timeout
+		}
+	}
+}
+
+// DefaultMaxTxnOps 单个事务的默认最大操作数，与 etcd 服务端 --max-txn-ops 默认值一致。
+const DefaultMaxTxnOps = 128
+
+// WithMaxTxnOps 设置 PutBatch/DeleteBatch 单个事务包含的最大操作数，默认 DefaultMaxTxnOps。
+// 服务端调大 --max-txn-ops 后可相应调大以减少往返次数；
+// 超过服务端限制会导致事务被拒绝（too many operations in txn request）。
+// 非正值被忽略。
+func WithMaxTxnOps(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxTxnOps = n
+		}
+	}
+}
+
+// WithObserver 设置统一观测接口（metrics/tracing），为 Get/Put/Delete/List 创建 span
+// 并记录 xmetrics 统一的调用计数与耗时直方图（component="xetcd"，
+// operation 为 get/put/delete/list）。
+//
+// 默认不设置（nil），KV 操作不产生任何观测开销。
+// 键名（或 List 的前缀）仅作为 span 属性 xetcd.key / xetcd.prefix，不进入 metrics 维度。
+// Get 的 ErrKeyNotFound 记为成功并附加 xetcd.found=false。
+func WithObserver(observer xmetrics.Observer) Option {
+	return func(o *options) {
+		o.observer = observer
+	}
+}
@@ -149,3 +149,56 @@ func TestDefaultOptions(t *testing.T) {
 		t.Error("tlsConfig should be nil by default")
 	}
 }
+
+func TestWithOperationTimeout(t *testing.T) {
+	o := defaultOptions()
+	if o.opTimeout != 0 {
+		t.Errorf("default opTimeout = %v, want 0", o.opTimeout)
+	}
+
+	WithOperationTimeout(2 * time.Second)(o)
+	if o.opTimeout != 2*time.Second {
+		t.Errorf("opTimeout = %v, want 2s", o.opTimeout)
+	}
+
+	WithOperationTimeout(-time.Second)(o)
+	if o.opTimeout != 2*time.Second {
+		t.Errorf("negative timeout should be ignored, got %v", o.opTimeout)
+	}
+}
+
+func TestWithMaxTxnOps(t *testing.T) {
+	o := defaultOptions()
+	WithMaxTxnOps(512)(o)
+	if o.maxTxnOps != 512 {
+		t.Errorf("maxTxnOps = %d, want 512", o.maxTxnOps)
+	}
+	WithMaxTxnOps(0)(o)
+	if o.maxTxnOps != 512 {
+		t.Errorf("non-positive value should be ignored, got %d", o.maxTxnOps)
+	}
+
+	if got := (&Client{}).txnOpsLimit(); got != DefaultMaxTxnOps {
+		t.Errorf("zero-value client limit = %d, want %d", got, DefaultMaxTxnOps)
+	}
+}
+
+func TestClient_WithOperationTimeoutContext(t *testing.T) {
+	c := &Client{}
+	ctx, cancel := c.withOperationTimeout(context.Background())
+	defer cancel()
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("no timeout configured, ctx should have no deadline")
+	}
+
+	c.opTimeout = time.Second
+	ctx2, cancel2 := c.withOperationTimeout(context.Background())
+	defer cancel2()
+	deadline, ok := ctx2.Deadline()
+	if !ok {
+		t.Fatal("expected deadline")
+	}
+	if remaining := time.Until(deadline); remaining <= 0 || remaining > time.Second {
+		t.Errorf("unexpected remaining %v", remaining)
+	}
+}
@@ -0,0 +1,298 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Registration 表示一次基于租约的服务注册。
+//
+// 注册期间后台 goroutine 持续为租约续约；租约意外丢失时自动重新注册。
+// Registration 是并发安全的。
+type Registration interface {
+	// Key 返回注册的键名。
+	Key() string
+
+	// LeaseID 返回当前租约 ID。重新注册后会变化。
+	// 可用于将其他键绑定到同一租约（通过 RawClient 配合 clientv3.WithLease）。
+	LeaseID() clientv3.LeaseID
+
+	// Done 返回一个通道，在注册终止时关闭。
+	// 终止原因通过 Err() 获取。
+	Done() <-chan struct{}
+
+	// Err 返回注册终止的原因，Done() 关闭前返回 nil：
+	//   - 调用 Close: nil
+	//   - Register 的 ctx 被取消: ctx.Err()
+	//   - Client 已关闭: ErrClientClosed
+	//   - 续约中断且重新注册失败: 包装 ErrLeaseLost 的错误
+	Err() error
+
+	// Close 停止续约并撤销租约（键随之删除），等待后台 goroutine 退出。
+	// ctx 仅控制等待时间，超时返回 ctx.Err()（撤销仍会在后台完成）。
+	// 重复调用是安全的。
+	Close(ctx context.Context) error
+}
+
+// 重新注册参数。
+// 设计决策: 重试次数有限而非无限重试。持续失败通常意味着网络分区或 etcd 不可用，
+// 此时应尽快通过 Done() 通知调用方（如停止对外服务、触发告警），
+// 而不是在后台静默重试让调用方误以为注册仍然有效。
+const (
+	reRegisterAttempts    = 3
+	maxReRegisterInterval = time.Second
+)
+
+// Register 以 ttl 租约写入 key=value，并在后台持续续约，用于服务注册。
+//
+// 生命周期：
+//   - ctx 取消或调用 Registration.Close 时停止续约并撤销租约，键随之删除
+//   - Client.Close 会等待注册 goroutine 退出，退出前撤销租约
+//   - 租约丢失（过期、被外部撤销）时自动重新授予租约并写入，LeaseID 随之变化
+//   - 重新注册连续失败 reRegisterAttempts 次后终止，Done() 关闭，Err() 返回 ErrLeaseLost
+//
+// 静默中断检测：除了 KeepAlive 通道关闭外，若超过租约 TTL 仍未收到任何续约响应
+// （如网络分区导致响应丢失），同样视为租约丢失。此时服务端租约很可能已经过期，
+// 先尝试重新注册，失败则通过 Done() 通知调用方。
+//
+// ttl 向上取整到秒，且受 etcd 服务端最小 TTL 约束；ttl <= 0 返回 ErrInvalidTTL。
+//
+// 示例：
+//
+//	reg, err := client.Register(ctx, "/services/api/node-1", []byte(addr), 10*time.Second)
+//	if err != nil {
+//	    return err
+//	}
+//	defer reg.Close(context.Background())
+//
+//	select {
+//	case <-reg.Done():
+//	    log.Printf("registration stopped: %v", reg.Err())
+//	case <-ctx.Done():
+//	}
+func (c *Client) Register(ctx context.Context, key string, value []byte, ttl time.Duration) (Registration, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if ttl <= 0 {
+		return nil, ErrInvalidTTL
+	}
+
+	r := &registration{
+		c:          c,
+		key:        key,
+		value:      string(value),
+		ttlSeconds: max(int64(math.Ceil(ttl.Seconds())), 1),
+		done:       make(chan struct{}),
+	}
+	if err := r.grantAndPut(ctx); err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	if err := c.registerWatchGoroutine(func() {
+		r.run(runCtx)
+	}); err != nil {
+		cancel()
+		c.tryRevokeLease(r.LeaseID())
+		return nil, err
+	}
+	return r, nil
+}
+
+// registration Registration 的实现。
+type registration struct {
+	c          *Client
+	key        string
+	value      string
+	ttlSeconds int64
+
+	leaseID atomic.Int64
+	cancel  context.CancelFunc
+	closed  atomic.Bool // 是否由 Close 主动终止
+	done    chan struct{}
+
+	errMu sync.Mutex
+	err   error
+}
+
+func (r *registration) Key() string { return r.key }
+
+func (r *registration) LeaseID() clientv3.LeaseID { return clientv3.LeaseID(r.leaseID.Load()) }
+
+func (r *registration) Done() <-chan struct{} { return r.done }
+
+func (r *registration) Err() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return r.err
+}
+
+func (r *registration) Close(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	r.closed.Store(true)
+	r.cancel()
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// grantAndPut 授予新租约并以该租约写入键值，成功后更新 LeaseID。
+func (r *registration) grantAndPut(ctx context.Context) error {
+	ctx, cancel := r.c.withOperationTimeout(ctx)
+	defer cancel()
+
+	lease, err := r.c.client.Grant(ctx, r.ttlSeconds)
+	if err != nil {
+		return fmt.Errorf("xetcd: register %q: grant lease: %w", r.key, err)
+	}
+	if _, err := r.c.client.Put(ctx, r.key, r.value, clientv3.WithLease(lease.ID)); err != nil {
+		r.c.tryRevokeLease(lease.ID)
+		return fmt.Errorf("xetcd: register %q: %w", r.key, err)
+	}
+	r.leaseID.Store(int64(lease.ID))
+	return nil
+}
+
+// run 续约主循环：续约 → 租约丢失 → 重新注册 → 续约 ...，直到终止。
+func (r *registration) run(ctx context.Context) {
+	defer close(r.done)
+	defer r.cancel()
+
+	for {
+		lostErr := r.keepAlive(ctx)
+		if lostErr == nil {
+			r.stop(ctx)
+			return
+		}
+		if err := r.reRegister(ctx, lostErr); err != nil {
+			if r.stopping(ctx) {
+				r.stop(ctx)
+				return
+			}
+			r.setErr(err)
+			return
+		}
+	}
+}
+
+// keepAlive 为当前租约续约，直到租约丢失（返回非 nil 错误）或注册被停止（返回 nil）。
+func (r *registration) keepAlive(ctx context.Context) error {
+	kaCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	leaseID := r.LeaseID()
+	kaCh, err := r.c.client.KeepAlive(kaCtx, leaseID)
+	if err != nil {
+		if r.stopping(ctx) {
+			return nil
+		}
+		return fmt.Errorf("%w: keepalive lease %x: %w", ErrLeaseLost, leaseID, err)
+	}
+
+	// 看门狗：超过 TTL 未收到续约响应即视为租约丢失
+	watchdog := time.NewTimer(time.Duration(r.ttlSeconds) * time.Second)
+	defer watchdog.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-r.c.closeCh:
+			return nil
+		case resp, ok := <-kaCh:
+			if !ok {
+				if r.stopping(ctx) {
+					return nil
+				}
+				return fmt.Errorf("%w: keepalive channel closed for lease %x", ErrLeaseLost, leaseID)
+			}
+			ttl := r.ttlSeconds
+			if resp != nil && resp.TTL > 0 {
+				ttl = resp.TTL
+			}
+			watchdog.Reset(time.Duration(ttl) * time.Second)
+		case <-watchdog.C:
+			return fmt.Errorf("%w: no keepalive response within %ds for lease %x", ErrLeaseLost, r.ttlSeconds, leaseID)
+		}
+	}
+}
+
+// reRegister 租约丢失后重新注册，最多尝试 reRegisterAttempts 次。
+func (r *registration) reRegister(ctx context.Context, lostErr error) error {
+	// 旧租约可能仍存活（如仅续约流中断），best-effort 撤销避免残留两份注册
+	r.c.tryRevokeLease(r.LeaseID())
+
+	interval := min(time.Duration(r.ttlSeconds)*time.Second/3, maxReRegisterInterval)
+	lastErr := lostErr
+	for attempt := range reRegisterAttempts {
+		if attempt > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-r.c.closeCh:
+				timer.Stop()
+				return ErrClientClosed
+			case <-timer.C:
+			}
+		}
+		if r.stopping(ctx) {
+			return ErrClientClosed
+		}
+		err := r.grantAndPut(ctx)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+	}
+	return fmt.Errorf("%w: re-register %q failed after %d attempts: %w", ErrLeaseLost, r.key, reRegisterAttempts, lastErr)
+}
+
+// stopping 判断注册是否因 ctx 取消、Close 或 Client 关闭而停止。
+func (r *registration) stopping(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+	select {
+	case <-r.c.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop 撤销租约并记录终止原因。
+func (r *registration) stop(ctx context.Context) {
+	r.c.tryRevokeLease(r.LeaseID())
+	switch {
+	case r.closed.Load():
+		// Close 主动终止，Err 保持 nil
+	case r.c.isClosed():
+		r.setErr(ErrClientClosed)
+	default:
+		r.setErr(context.Cause(ctx))
+	}
+}
+
+func (r *registration) setErr(err error) {
+	r.errMu.Lock()
+	r.err = err
+	r.errMu.Unlock()
+}
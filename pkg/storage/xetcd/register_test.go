@@ -0,0 +1,127 @@
+package xetcd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegister_Preconditions(t *testing.T) {
+	c := &Client{
+		client:  &noopEtcdClient{},
+		config:  &Config{Endpoints: []string{"localhost:2379"}},
+		closeCh: make(chan struct{}),
+	}
+
+	//nolint:staticcheck // 测试 nil ctx 防御
+	if _, err := c.Register(nil, "key", nil, time.Second); err != ErrNilContext {
+		t.Errorf("Register(nil ctx) = %v, want ErrNilContext", err)
+	}
+	if _, err := c.Register(context.Background(), "", nil, time.Second); err != ErrEmptyKey {
+		t.Errorf("Register(empty key) = %v, want ErrEmptyKey", err)
+	}
+	if _, err := c.Register(context.Background(), "key", nil, 0); err != ErrInvalidTTL {
+		t.Errorf("Register(ttl=0) = %v, want ErrInvalidTTL", err)
+	}
+}
+
+func TestRegister_GrantError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	mockClient.EXPECT().Grant(gomock.Any(), int64(2)).Return(nil, errors.New("grant failed"))
+
+	if _, err := c.Register(context.Background(), "key", []byte("v"), 1500*time.Millisecond); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestRegister_PutErrorRevokesLease(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	mockClient.EXPECT().Grant(gomock.Any(), int64(1)).Return(&clientv3.LeaseGrantResponse{ID: 7, TTL: 1}, nil)
+	mockClient.EXPECT().Put(gomock.Any(), "key", "v", gomock.Any()).Return(nil, errors.New("put failed"))
+	mockClient.EXPECT().Revoke(gomock.Any(), clientv3.LeaseID(7)).Return(&clientv3.LeaseRevokeResponse{}, nil)
+
+	if _, err := c.Register(context.Background(), "key", []byte("v"), time.Second); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestRegister_SilentKeepAliveStop 续约通道既不响应也不关闭（模拟网络分区），
+// 看门狗应在 TTL 后判定租约丢失，重新注册失败后通过 Done() 通知。
+func TestRegister_SilentKeepAliveStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	silent := make(chan *clientv3.LeaseKeepAliveResponse)
+	gomock.InOrder(
+		mockClient.EXPECT().Grant(gomock.Any(), int64(1)).Return(&clientv3.LeaseGrantResponse{ID: 1, TTL: 1}, nil),
+		mockClient.EXPECT().Grant(gomock.Any(), int64(1)).Return(nil, errors.New("unreachable")).Times(reRegisterAttempts),
+	)
+	mockClient.EXPECT().Put(gomock.Any(), "svc", "addr", gomock.Any()).Return(&clientv3.PutResponse{}, nil)
+	mockClient.EXPECT().KeepAlive(gomock.Any(), clientv3.LeaseID(1)).Return((<-chan *clientv3.LeaseKeepAliveResponse)(silent), nil)
+	mockClient.EXPECT().Revoke(gomock.Any(), gomock.Any()).Return(nil, errors.New("unreachable")).AnyTimes()
+
+	reg, err := c.Register(context.Background(), "svc", []byte("addr"), time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	select {
+	case <-reg.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Done not closed after keepalive stopped")
+	}
+	if !errors.Is(reg.Err(), ErrLeaseLost) {
+		t.Errorf("Err() = %v, want ErrLeaseLost", reg.Err())
+	}
+}
+
+// TestRegister_KeepAliveClosedReRegisters 续约通道关闭（租约过期）后应重新注册并继续续约。
+func TestRegister_KeepAliveClosedReRegisters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	expired := make(chan *clientv3.LeaseKeepAliveResponse)
+	close(expired)
+	healthy := make(chan *clientv3.LeaseKeepAliveResponse)
+
+	gomock.InOrder(
+		mockClient.EXPECT().Grant(gomock.Any(), int64(5)).Return(&clientv3.LeaseGrantResponse{ID: 1, TTL: 5}, nil),
+		mockClient.EXPECT().Grant(gomock.Any(), int64(5)).Return(&clientv3.LeaseGrantResponse{ID: 2, TTL: 5}, nil),
+	)
+	mockClient.EXPECT().Put(gomock.Any(), "svc", "addr", gomock.Any()).Return(&clientv3.PutResponse{}, nil).Times(2)
+	mockClient.EXPECT().KeepAlive(gomock.Any(), clientv3.LeaseID(1)).Return((<-chan *clientv3.LeaseKeepAliveResponse)(expired), nil)
+	mockClient.EXPECT().KeepAlive(gomock.Any(), clientv3.LeaseID(2)).Return((<-chan *clientv3.LeaseKeepAliveResponse)(healthy), nil)
+	mockClient.EXPECT().Revoke(gomock.Any(), gomock.Any()).Return(&clientv3.LeaseRevokeResponse{}, nil).AnyTimes()
+
+	reg, err := c.Register(context.Background(), "svc", []byte("addr"), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for reg.LeaseID() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("LeaseID = %x, want 2 after re-register", reg.LeaseID())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := reg.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if reg.Err() != nil {
+		t.Errorf("Err() after Close = %v, want nil", reg.Err())
+	}
+}
@@ -0,0 +1,97 @@
+package xetcd
+
+import (
+	"context"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// CompareAndSwap 当 key 的当前值等于 expected 时原子地写入 newValue。
+//
+// 返回：
+//   - (true, nil): 比较成功，已写入 newValue
+//   - (false, nil): 当前值不等于 expected，或 key 不存在，未写入
+//   - (false, err): 请求失败
+//
+// 比较失败不视为错误，调用方可根据返回值决定重读重试或放弃。
+// key 已绑定的租约会被保留（等价于 WithIgnoreLease），不会因 CAS 变为永不过期。
+//
+// 需要基于版本号（而非值）的乐观并发控制时，
+// 使用 GetWithRevision 读取 ModRevision 后调用 CompareAndSwapRevision。
+func (c *Client) CompareAndSwap(ctx context.Context, key string, expected, newValue []byte) (bool, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return false, err
+	}
+	if key == "" {
+		return false, ErrEmptyKey
+	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	// Value 比较对不存在的 key 恒为 false，因此无需额外的 CreateRevision 条件
+	resp, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value(key), "=", string(expected))).
+		Then(clientv3.OpPut(key, string(newValue), clientv3.WithIgnoreLease())).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("xetcd: compare and swap %q: %w", key, err)
+	}
+	return resp.Succeeded, nil
+}
+
+// CompareAndSwapRevision 当 key 的 ModRevision 等于 revision 时原子地写入 newValue。
+//
+// revision 通常来自 GetWithRevision，用于"读-改-写"的乐观并发控制：
+// 期间只要 key 被修改过（即使值改回原样），比较都会失败，不存在 ABA 问题。
+// revision 为 0 表示期望 key 不存在，等价于 PutIfAbsent。
+//
+// 比较失败返回 (false, nil)。key 已绑定的租约会被保留。
+func (c *Client) CompareAndSwapRevision(ctx context.Context, key string, revision int64, newValue []byte) (bool, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return false, err
+	}
+	if key == "" {
+		return false, ErrEmptyKey
+	}
+	if revision == 0 {
+		return c.PutIfAbsent(ctx, key, newValue)
+	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(key), "=", revision)).
+		Then(clientv3.OpPut(key, string(newValue), clientv3.WithIgnoreLease())).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("xetcd: compare and swap %q at revision %d: %w", key, revision, err)
+	}
+	return resp.Succeeded, nil
+}
+
+// PutIfAbsent 仅当 key 不存在时写入 value。
+//
+// 基于 CreateRevision == 0 比较实现，多个调用方并发写入时只有一个成功，
+// 适用于首写胜出、幂等标记、简单选主等场景。
+//
+// 返回 (true, nil) 表示本次写入成功；key 已存在时返回 (false, nil)，原值不变。
+func (c *Client) PutIfAbsent(ctx context.Context, key string, value []byte) (bool, error) {
+	if err := c.checkPreconditions(ctx); err != nil {
+		return false, err
+	}
+	if key == "" {
+		return false, ErrEmptyKey
+	}
+	ctx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	resp, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(value))).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("xetcd: put if absent %q: %w", key, err)
+	}
+	return resp.Succeeded, nil
+}
@@ -87,6 +87,11 @@ type Event struct {
 	// 此时 Revision 字段包含最后成功处理的版本号，便于恢复。
 	// 若 Revision 为 0，表示 Watch 在处理任何事件前就失败了。
 	Error error
+
+	// Synthetic 为 true 表示该事件不是 etcd 的真实变更，而是 WatchWithRetry
+	// 在 compaction 恢复时重新读取当前状态生成的 PUT 事件（见 WithCompactionResync）。
+	// 此时 Revision 为键的 ModRevision，可能早于之前已收到的事件。
+	Synthetic bool
 }
 
 // DefaultWatchBufferSize 默认 Watch 事件通道缓冲区大小。
@@ -100,6 +105,10 @@ type watchOptions struct {
 	prefix     bool
 	revision   int64
 	bufferSize int
+
+	// 以下仅对 WatchWithRetry 生效
+	noCompactionRecovery bool
+	compactionResync     bool
 }
 
 // WatchOption Watch 选项函数。
@@ -130,6 +139,35 @@ func WithBufferSize(size int) WatchOption {
 	}
 }
 
+// WithCompactionRecovery 设置 WatchWithRetry 遇到 compaction 时是否自动恢复，默认启用。
+//
+// 启用时，恢复点已被 compaction 清除后，WatchWithRetry 从压缩版本号继续监听，
+// 并通过 RetryConfig.OnCompacted 通知调用方发生了事件缺口。
+// 禁用时，WatchWithRetry 发送包装 ErrWatchCompacted 的错误事件后关闭通道，
+// 适合不能容忍任何事件丢失、需要自行全量重建状态的严格消费者。
+//
+// 对 Watch 无效（Watch 遇到任何错误都会关闭通道）。
+func WithCompactionRecovery(enabled bool) WatchOption {
+	return func(o *watchOptions) {
+		o.noCompactionRecovery = !enabled
+	}
+}
+
+// WithCompactionResync 在 WatchWithRetry 从 compaction 恢复时重新读取当前状态，
+// 以 Synthetic=true 的 PUT 事件逐个发出，然后从读取时的版本之后继续监听。
+//
+// 读取的是快照而非缺口内的变更历史：缺口期间被删除的键不会产生 DELETE 事件，
+// 需要精确删除语义的调用方应以收到的 Synthetic 事件重建本地状态，
+// 移除未出现在重建结果中的键。
+//
+// 重新读取失败时按 RetryConfig 退避重试，成功前不会恢复监听。
+// 禁用 WithCompactionRecovery 时此选项无效。对 Watch 无效。
+func WithCompactionResync() WatchOption {
+	return func(o *watchOptions) {
+		o.compactionResync = true
+	}
+}
+
 // Watch 监听键值变化，返回事件通道。
 // 通过 context 取消监听，取消时关闭通道。
 // 使用 WithPrefix() 监听前缀下所有键的变化。
@@ -366,6 +404,17 @@ type RetryConfig struct {
 	//   - lastRevision: 最后成功处理的 revision，可用于日志或恢复确认
 	//     值为 0 表示尚未成功处理任何事件（首次连接就失败）
 	OnRetry func(attempt int, err error, nextBackoff time.Duration, lastRevision int64)
+
+	// OnCompacted 恢复点已被 compaction 清除时的回调，在自动恢复之前调用。
+	// 与 OnRetry 相同，在内部 goroutine 中调用。
+	//
+	// 参数：
+	//   - lastRevision: 最后成功处理的 revision
+	//   - compactRevision: etcd 压缩到的版本号
+	//
+	// 版本号位于 (lastRevision, compactRevision) 区间的事件已无法获取，
+	// 调用方可据此告警或触发全量同步。禁用 WithCompactionRecovery 时不调用。
+	OnCompacted func(lastRevision, compactRevision int64)
 }
 
 // DefaultRetryConfig 返回默认的重试配置。
@@ -391,6 +440,12 @@ func DefaultRetryConfig() RetryConfig {
 // 重连将从 etcd 当前时间点开始，断线窗口内的变更可能丢失。
 // 如需严格不丢事件，调用方应通过 WithRevision 指定已知的起始版本号。
 //
+// Compaction 恢复：
+// 若恢复点对应的历史已被 etcd compaction 清除，默认从压缩版本号立即继续监听
+// （不退避、不计入重试次数），并调用 RetryConfig.OnCompacted 告知事件缺口。
+// 配合 WithCompactionResync 可在恢复时补发当前状态；
+// 通过 WithCompactionRecovery(false) 可改为发送 ErrWatchCompacted 错误事件后关闭通道。
+//
 // 使用示例：
 //
 //	events, err := client.WatchWithRetry(ctx, "/prefix/",
@@ -435,7 +490,7 @@ func (c *Client) WatchWithRetry(ctx context.Context, key string, cfg RetryConfig
 
 	// 启动带重试的 watch goroutine（生命周期锁内注册，与 Close 互斥）
 	if err := c.registerWatchGoroutine(func() {
-		c.runWatchWithRetry(ctx, key, cfg, opts, eventCh)
+		c.runWatchWithRetry(ctx, key, cfg, opts, o, eventCh)
 	}); err != nil {
 		close(eventCh)
 		return nil, err
@@ -483,7 +538,7 @@ func validateRetryConfig(cfg *RetryConfig) error {
 }
 
 // runWatchWithRetry 运行带重试的 watch 循环。
-func (c *Client) runWatchWithRetry(ctx context.Context, key string, cfg RetryConfig, opts []WatchOption, eventCh chan<- Event) {
+func (c *Client) runWatchWithRetry(ctx context.Context, key string, cfg RetryConfig, opts []WatchOption, o *watchOptions, eventCh chan<- Event) {
 	defer close(eventCh)
 
 	state := &watchRetryState{
@@ -495,6 +550,16 @@ func (c *Client) runWatchWithRetry(ctx context.Context, key string, cfg RetryCon
 			return
 		}
 
+		if state.needResync {
+			if err := c.resyncAfterCompaction(ctx, key, o, state, eventCh); err != nil {
+				if c.handleWatchRetry(ctx, cfg, state, err) {
+					c.sendMaxRetriesErrorIfNeeded(ctx, cfg, state, eventCh)
+					return
+				}
+				continue
+			}
+		}
+
 		watchOpts := c.buildRetryWatchOptions(opts, state)
 		// 设计决策: 复用公开 Watch 方法而非提取 watchInternal。
 		// 重试是低频操作（秒级退避），双重前置条件检查的开销可忽略。
@@ -515,6 +580,21 @@ func (c *Client) runWatchWithRetry(ctx context.Context, key string, cfg RetryCon
 			return
 		}
 
+		if compactRev > 0 {
+			if o.noCompactionRecovery {
+				c.sendErrorEvent(ctx, eventCh,
+					fmt.Errorf("%w: %w", ErrWatchCompacted, disconnectErrOrDefault(disconnectErr)),
+					state.lastRevision, compactRev)
+				return
+			}
+			if cfg.OnCompacted != nil {
+				cfg.OnCompacted(state.lastRevision, compactRev)
+			}
+			// compaction 不是连接故障，立即从压缩版本（或重新读取的快照版本）恢复
+			state.needResync = o.compactionResync
+			continue
+		}
+
 		retryErr := disconnectErrOrDefault(disconnectErr)
 		if c.handleWatchRetry(ctx, cfg, state, retryErr) {
 			c.sendMaxRetriesErrorIfNeeded(ctx, cfg, state, eventCh)
@@ -560,6 +640,46 @@ type watchRetryState struct {
 	compactRevision int64 // 最近一次 compaction 错误的压缩版本号
 	backoff         time.Duration
 	retryCount      int
+	needResync      bool // compaction 后待重新读取当前状态
+}
+
+// resyncAfterCompaction 读取当前状态并以 Synthetic 事件发出，
+// 成功后将恢复点设为读取时的 revision，后续 Watch 从其下一个版本开始。
+func (c *Client) resyncAfterCompaction(ctx context.Context, key string, o *watchOptions, state *watchRetryState, eventCh chan<- Event) error {
+	getCtx, cancel := c.withOperationTimeout(ctx)
+	defer cancel()
+
+	var getOpts []clientv3.OpOption
+	if o.prefix {
+		getOpts = append(getOpts, clientv3.WithPrefix())
+	}
+	resp, err := c.client.Get(getCtx, key, getOpts...)
+	if err != nil {
+		return fmt.Errorf("xetcd: resync %q after compaction: %w", key, err)
+	}
+
+	for _, kv := range resp.Kvs {
+		event := Event{
+			Type:      EventPut,
+			Key:       string(kv.Key),
+			Value:     kv.Value,
+			Revision:  kv.ModRevision,
+			Synthetic: true,
+		}
+		if !c.forwardEvent(ctx, eventCh, event) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return ErrClientClosed
+		}
+	}
+
+	if resp.Header != nil {
+		state.lastRevision = resp.Header.Revision
+	}
+	state.compactRevision = 0
+	state.needResync = false
+	return nil
 }
 
 // shouldStopWatch 检查是否应停止 watch。
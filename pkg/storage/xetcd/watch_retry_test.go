@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.uber.org/mock/gomock"
@@ -1276,3 +1277,197 @@ func TestForwardEvent(t *testing.T) {
 		}
 	})
 }
+
+// compactionWatchMock 第一次 Watch 发送 rev=50 的事件后返回 compaction 错误，
+// 之后的 Watch 记录起始 revision 并保持打开。
+func compactionWatchMock(t *testing.T, mockClient *MocketcdClient, key string, compactRev int64, startRevs chan<- int64) {
+	t.Helper()
+	first := make(chan clientv3.WatchResponse, 2)
+	first <- clientv3.WatchResponse{
+		Events: []*clientv3.Event{{
+			Type: mvccpb.PUT,
+			Kv:   &mvccpb.KeyValue{Key: []byte(key), Value: []byte("v1"), ModRevision: 50},
+		}},
+	}
+	first <- clientv3.WatchResponse{CompactRevision: compactRev}
+
+	var calls atomic.Int32
+	mockClient.EXPECT().
+		Watch(gomock.Any(), key, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, k string, opts ...clientv3.OpOption) clientv3.WatchChan {
+			if calls.Add(1) == 1 {
+				return first
+			}
+			startRevs <- clientv3.OpGet(k, opts...).Rev()
+			return make(chan clientv3.WatchResponse)
+		}).
+		AnyTimes()
+}
+
+// TestWatchWithRetry_CompactionRecovery 测试默认从压缩版本立即恢复并回调 OnCompacted。
+func TestWatchWithRetry_CompactionRecovery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := "/app/config"
+	startRevs := make(chan int64, 1)
+	compactionWatchMock(t, mockClient, key, 80, startRevs)
+
+	var gotLast, gotCompact atomic.Int64
+	var retried atomic.Bool
+	cfg := RetryConfig{
+		// 退避很长：若 compaction 走退避路径，测试会超时
+		InitialBackoff: time.Hour,
+		OnRetry: func(int, error, time.Duration, int64) {
+			retried.Store(true)
+		},
+		OnCompacted: func(lastRevision, compactRevision int64) {
+			gotLast.Store(lastRevision)
+			gotCompact.Store(compactRevision)
+		},
+	}
+
+	eventCh, err := c.WatchWithRetry(ctx, key, cfg)
+	if err != nil {
+		t.Fatalf("WatchWithRetry() error = %v", err)
+	}
+	if event := <-eventCh; event.Revision != 50 {
+		t.Fatalf("event.Revision = %d, want 50", event.Revision)
+	}
+
+	select {
+	case rev := <-startRevs:
+		if rev != 80 {
+			t.Errorf("resumed at revision %d, want 80", rev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for watch to resume after compaction")
+	}
+	if gotLast.Load() != 50 || gotCompact.Load() != 80 {
+		t.Errorf("OnCompacted(%d, %d), want (50, 80)", gotLast.Load(), gotCompact.Load())
+	}
+	if retried.Load() {
+		t.Error("compaction recovery should not go through OnRetry")
+	}
+}
+
+// TestWatchWithRetry_CompactionRecoveryDisabled 测试禁用恢复时发送 ErrWatchCompacted 并关闭通道。
+func TestWatchWithRetry_CompactionRecoveryDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := "/app/config"
+	startRevs := make(chan int64, 1)
+	compactionWatchMock(t, mockClient, key, 80, startRevs)
+
+	var compacted atomic.Bool
+	cfg := RetryConfig{
+		InitialBackoff: time.Millisecond,
+		OnCompacted:    func(int64, int64) { compacted.Store(true) },
+	}
+	eventCh, err := c.WatchWithRetry(ctx, key, cfg, WithCompactionRecovery(false))
+	if err != nil {
+		t.Fatalf("WatchWithRetry() error = %v", err)
+	}
+
+	var events []Event
+	timeout := time.After(2 * time.Second)
+	for done := false; !done; {
+		select {
+		case event, ok := <-eventCh:
+			if !ok {
+				done = true
+				break
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatal("timeout waiting for channel close")
+		}
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	last := events[1]
+	if !errors.Is(last.Error, ErrWatchCompacted) {
+		t.Errorf("last.Error = %v, want ErrWatchCompacted", last.Error)
+	}
+	if last.Revision != 50 || last.CompactRevision != 80 {
+		t.Errorf("last = {Revision: %d, CompactRevision: %d}, want {50, 80}", last.Revision, last.CompactRevision)
+	}
+	if compacted.Load() {
+		t.Error("OnCompacted should not be called when recovery is disabled")
+	}
+	if len(startRevs) != 0 {
+		t.Error("watch should not be re-established when recovery is disabled")
+	}
+}
+
+// TestWatchWithRetry_CompactionResync 测试恢复时补发当前状态并从快照版本之后继续监听。
+func TestWatchWithRetry_CompactionResync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := NewMocketcdClient(ctrl)
+	c := newTestClient(t, mockClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key := "/app/"
+	startRevs := make(chan int64, 1)
+	compactionWatchMock(t, mockClient, key, 80, startRevs)
+
+	getCalls := 0
+	mockClient.EXPECT().
+		Get(gomock.Any(), key, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, k string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+			getCalls++
+			if getCalls == 1 {
+				return nil, errors.New("etcd unavailable")
+			}
+			return &clientv3.GetResponse{
+				Header: &etcdserverpb.ResponseHeader{Revision: 120},
+				Kvs: []*mvccpb.KeyValue{
+					{Key: []byte("/app/a"), Value: []byte("1"), ModRevision: 90},
+					{Key: []byte("/app/b"), Value: []byte("2"), ModRevision: 110},
+				},
+			}, nil
+		}).
+		Times(2)
+
+	cfg := RetryConfig{InitialBackoff: time.Millisecond}
+	eventCh, err := c.WatchWithRetry(ctx, key, cfg, WithPrefix(), WithCompactionResync())
+	if err != nil {
+		t.Fatalf("WatchWithRetry() error = %v", err)
+	}
+	if event := <-eventCh; event.Synthetic {
+		t.Error("real event should not be marked synthetic")
+	}
+
+	for _, want := range []string{"/app/a", "/app/b"} {
+		select {
+		case event := <-eventCh:
+			if !event.Synthetic || event.Type != EventPut || event.Key != want {
+				t.Errorf("event = %+v, want synthetic PUT %s", event, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for synthetic event %s", want)
+		}
+	}
+
+	select {
+	case rev := <-startRevs:
+		if rev != 121 {
+			t.Errorf("resumed at revision %d, want 121", rev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for watch to resume after resync")
+	}
+}
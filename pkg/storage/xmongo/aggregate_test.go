@@ -0,0 +1,142 @@
+package xmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestAggregateStream_Preconditions(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+	handler := func(context.Context, bson.Raw) error { return nil }
+
+	//nolint:staticcheck // SA1012: 测试 nil ctx 防御
+	err := w.AggregateStream(nil, nil, nil, handler, AggregateOptions{})
+	assert.ErrorIs(t, err, ErrNilContext)
+
+	err = w.AggregateStream(context.Background(), nil, nil, handler, AggregateOptions{})
+	assert.ErrorIs(t, err, ErrNilCollection)
+
+	err = w.aggregateStream(context.Background(), &mongo.Collection{}, nil, nil, AggregateOptions{})
+	assert.ErrorIs(t, err, ErrNilHandler)
+
+	w.closed.Store(true)
+	err = w.AggregateStream(context.Background(), nil, nil, handler, AggregateOptions{})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestAggregateStreamInternal_Success(t *testing.T) {
+	coll := &cursorCollectionOps{
+		collName: "orders",
+		docs: []any{
+			bson.M{"_id": "alice", "total": 300},
+			bson.M{"_id": "bob", "total": 450},
+			bson.M{"_id": "charlie", "total": 250},
+		},
+	}
+	w := &mongoWrapper{options: defaultOptions()}
+
+	var ids []string
+	err := w.aggregateStreamInternal(context.Background(), coll, nil, func(_ context.Context, doc bson.Raw) error {
+		var row struct {
+			ID    string `bson:"_id"`
+			Total int32  `bson:"total"`
+		}
+		if err := bson.Unmarshal(doc, &row); err != nil {
+			return err
+		}
+		ids = append(ids, row.ID)
+		return nil
+	}, AggregateOptions{BatchSize: 2, AllowDiskUse: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob", "charlie"}, ids)
+}
+
+func TestAggregateStreamInternal_HandlerError(t *testing.T) {
+	coll := &cursorCollectionOps{docs: []any{bson.M{"n": 1}, bson.M{"n": 2}, bson.M{"n": 3}}}
+	w := &mongoWrapper{options: defaultOptions()}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := w.aggregateStreamInternal(context.Background(), coll, mongo.Pipeline{}, func(context.Context, bson.Raw) error {
+		calls++
+		if calls == 2 {
+			return errStop
+		}
+		return nil
+	}, AggregateOptions{})
+	require.ErrorIs(t, err, errStop)
+	assert.Contains(t, err.Error(), "after 1 docs")
+	assert.Equal(t, 2, calls, "handler 出错后应停止遍历")
+}
+
+func TestAggregateStreamInternal_AggregateError(t *testing.T) {
+	mock := newMockCollectionOps()
+	mock.aggErr = errMockAggregate
+	w := &mongoWrapper{options: defaultOptions()}
+
+	err := w.aggregateStreamInternal(context.Background(), mock, nil, func(context.Context, bson.Raw) error {
+		t.Fatal("handler should not be called")
+		return nil
+	}, AggregateOptions{})
+	require.ErrorIs(t, err, errMockAggregate)
+	assert.Contains(t, err.Error(), "xmongo aggregate_stream")
+}
+
+func TestAggregateStreamInternal_SlowQuery(t *testing.T) {
+	var captured SlowQueryInfo
+	opts := defaultOptions()
+	opts.SlowQueryThreshold = time.Millisecond
+	opts.SlowQueryHook = func(_ context.Context, info SlowQueryInfo) { captured = info }
+	detector, err := newSlowQueryDetector(opts)
+	require.NoError(t, err)
+	w := &mongoWrapper{options: opts, slowQueryDetector: detector}
+
+	coll := &cursorCollectionOps{collName: "orders", docs: []any{bson.M{"n": 1}}}
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"n": 1}}}}
+	err = w.aggregateStreamInternal(context.Background(), coll, pipeline, func(context.Context, bson.Raw) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}, AggregateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "aggregateStream", captured.Operation)
+	assert.Equal(t, "orders", captured.Collection)
+	assert.Equal(t, pipeline, captured.Filter)
+	assert.Equal(t, int64(1), w.slowQueryCounter.Count())
+}
+
+func TestIsCursorNotFound(t *testing.T) {
+	notFound := mongo.CommandError{Code: cursorNotFoundCode, Name: "CursorNotFound"}
+	assert.True(t, isCursorNotFound(notFound))
+	assert.True(t, isCursorNotFound(fmt.Errorf("wrapped: %w", notFound)))
+	assert.False(t, isCursorNotFound(mongo.CommandError{Code: 11000}))
+	assert.False(t, isCursorNotFound(errMockAggregate))
+}
+
+func TestBuildAggregateOptions(t *testing.T) {
+	apply := func(opts AggregateOptions) *options.AggregateOptions {
+		var out options.AggregateOptions
+		for _, set := range buildAggregateOptions(opts).List() {
+			require.NoError(t, set(&out))
+		}
+		return &out
+	}
+
+	empty := apply(AggregateOptions{})
+	assert.Nil(t, empty.BatchSize)
+	assert.Nil(t, empty.AllowDiskUse)
+
+	full := apply(AggregateOptions{BatchSize: 50, AllowDiskUse: true})
+	require.NotNil(t, full.BatchSize)
+	assert.Equal(t, int32(50), *full.BatchSize)
+	require.NotNil(t, full.AllowDiskUse)
+	assert.True(t, *full.AllowDiskUse)
+}
@@ -0,0 +1,215 @@
+package xmongo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// =============================================================================
+// 查询结果缓存
+// =============================================================================
+
+const (
+	// DefaultQueryCacheTTL 查询结果缓存默认过期时间。
+	DefaultQueryCacheTTL = time.Minute
+
+	// queryCacheKeyPrefix 查询缓存 key 前缀。
+	// 完整格式：xmongo:qc:{db.coll}:g{generation}:{sha256(filter+分页参数)}
+	queryCacheKeyPrefix = "xmongo:qc:"
+)
+
+// FindPageCached 带缓存的分页查询。
+func (w *mongoWrapper) FindPageCached(ctx context.Context, coll *mongo.Collection, filter any, opts PageOptions) (*PageResult, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+	if coll == nil {
+		return nil, ErrNilCollection
+	}
+	return w.findPageCached(ctx, adaptCollection(coll), filter, opts, func(ctx context.Context) (*PageResult, error) {
+		return w.findPage(ctx, coll, filter, opts)
+	})
+}
+
+// InvalidateCache 使集合的全部查询缓存失效。
+func (w *mongoWrapper) InvalidateCache(ctx context.Context, coll *mongo.Collection) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if w.closed.Load() {
+		return ErrClosed
+	}
+	if coll == nil {
+		return ErrNilCollection
+	}
+	if !w.cacheEnabled() {
+		return ErrCacheNotConfigured
+	}
+	return w.invalidateCache(ctx, adaptCollection(coll))
+}
+
+// cacheEnabled 是否配置了查询缓存。
+func (w *mongoWrapper) cacheEnabled() bool {
+	return w.options.QueryCache != nil && w.options.QueryCacheLoader != nil
+}
+
+// findPageCached 带缓存的分页查询内部实现，query 为缓存未命中时的实际查询，便于测试注入。
+func (w *mongoWrapper) findPageCached(ctx context.Context, coll collectionOperations, filter any,
+	opts PageOptions, query func(context.Context) (*PageResult, error)) (*PageResult, error) {
+	if !w.cacheEnabled() {
+		return nil, ErrCacheNotConfigured
+	}
+	// 参数错误不应进入缓存层（否则 Loader 的分布式锁、singleflight 都会白跑一轮）
+	if _, err := validatePageOptions(opts); err != nil {
+		return nil, err
+	}
+
+	ns := cacheNamespace(coll)
+	gen, err := w.cacheGeneration(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := queryHash(filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	key := queryCacheKeyPrefix + ns + ":g" + gen + ":" + hash
+
+	data, err := w.options.QueryCacheLoader.Load(ctx, key, func(ctx context.Context) ([]byte, error) {
+		result, qerr := query(ctx)
+		if qerr != nil {
+			return nil, qerr
+		}
+		return bson.Marshal(result)
+	}, w.options.QueryCacheTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	var result PageResult
+	if err := bson.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%w: decode %s: %w", ErrCacheCorrupted, ns, err)
+	}
+	if result.Data == nil {
+		result.Data = []bson.M{}
+	}
+	return &result, nil
+}
+
+// invalidateCache 递增集合的缓存代数，旧代数下的缓存不再被读取，由 TTL 自然淘汰。
+func (w *mongoWrapper) invalidateCache(ctx context.Context, coll collectionOperations) error {
+	ns := cacheNamespace(coll)
+	if err := w.options.QueryCache.Client().Incr(ctx, cacheGenerationKey(ns)).Err(); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrCacheInvalidate, ns, err)
+	}
+	return nil
+}
+
+// cacheGeneration 读取集合当前的缓存代数，不存在时为 "0"。
+func (w *mongoWrapper) cacheGeneration(ctx context.Context, ns string) (string, error) {
+	gen, err := w.options.QueryCache.Client().Get(ctx, cacheGenerationKey(ns)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("xmongo find_page_cached generation %s: %w", ns, err)
+	}
+	return gen, nil
+}
+
+// cacheNamespace 返回 "db.coll" 形式的缓存命名空间。
+func cacheNamespace(coll collectionOperations) string {
+	info := buildSlowQueryInfoFromOps(coll, "", nil)
+	return info.Database + "." + info.Collection
+}
+
+// cacheGenerationKey 返回集合缓存代数的 key。
+//
+// 设计决策: 代数 key 不设置过期时间。若代数 key 先于数据 key 过期，
+// 代数回到 0 后可能重新命中失效前写入的旧代数数据。
+func cacheGenerationKey(ns string) string {
+	return queryCacheKeyPrefix + ns + ":gen"
+}
+
+// queryHash 计算查询条件与分页参数的哈希。
+//
+// 设计决策: 对 map 类型（bson.M、map[string]any）按 key 排序后再编码。
+// driver 按 map 迭代顺序编码，同一个 bson.M 过滤条件每次编码结果可能不同，
+// 不排序会导致同一查询落到不同的缓存 key 上、命中率下降。
+// map 的字段顺序本身就不确定，排序不会改变查询语义；bson.D 保持原顺序
+// （嵌入文档的精确匹配、排序条件都依赖字段顺序）。
+func queryHash(filter any, opts PageOptions) (string, error) {
+	// 与 findPageInternal 一致：nil 与空过滤条件等价，共享同一缓存
+	if filter == nil {
+		filter = bson.D{}
+	}
+	doc := bson.D{
+		{Key: "filter", Value: canonicalize(filter)},
+		{Key: "page", Value: opts.Page},
+		{Key: "size", Value: opts.PageSize},
+		{Key: "sort", Value: opts.Sort},
+		{Key: "projection", Value: opts.Projection},
+	}
+	raw, err := bson.MarshalExtJSON(doc, true, false)
+	if err != nil {
+		return "", fmt.Errorf("xmongo find_page_cached encode filter: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalize 将 map 递归转换为按 key 排序的 bson.D，其余类型原样返回。
+func canonicalize(v any) any {
+	switch t := v.(type) {
+	case bson.M:
+		return sortedDoc(t)
+	case map[string]any:
+		return sortedDoc(t)
+	case bson.D:
+		out := make(bson.D, len(t))
+		for i, e := range t {
+			out[i] = bson.E{Key: e.Key, Value: canonicalize(e.Value)}
+		}
+		return out
+	case bson.A:
+		out := make(bson.A, len(t))
+		for i, e := range t {
+			out[i] = canonicalize(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = canonicalize(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// sortedDoc 将 map 转换为按 key 排序的 bson.D。
+func sortedDoc(m map[string]any) bson.D {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make(bson.D, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, bson.E{Key: k, Value: canonicalize(m[k])})
+	}
+	return out
+}
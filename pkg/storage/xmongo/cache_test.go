@@ -0,0 +1,228 @@
+package xmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/omeyang/xkit/pkg/storage/xcache"
+)
+
+// newCachedWrapper 创建启用查询缓存的 wrapper（miniredis 后端）。
+func newCachedWrapper(t *testing.T) (*mongoWrapper, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	cache, err := xcache.NewRedis(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cache.Close(context.Background()) })
+	loader, err := xcache.NewLoader(cache)
+	require.NoError(t, err)
+
+	opts := defaultOptions()
+	WithQueryCache(cache, loader)(opts)
+	return &mongoWrapper{options: opts}, mr
+}
+
+func TestWrapper_FindPageCached_Validation(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err := w.FindPageCached(nil, nil, nil, PageOptions{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, ErrNilContext)
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	assert.ErrorIs(t, w.InvalidateCache(nil, nil), ErrNilContext)
+
+	_, err = w.FindPageCached(context.Background(), nil, nil, PageOptions{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, ErrNilCollection)
+	assert.ErrorIs(t, w.InvalidateCache(context.Background(), nil), ErrNilCollection)
+
+	_, err = w.findPageCached(context.Background(), newMockCollectionOps(), nil, PageOptions{Page: 1, PageSize: 10}, nil)
+	assert.ErrorIs(t, err, ErrCacheNotConfigured)
+
+	w.closed.Store(true)
+	_, err = w.FindPageCached(context.Background(), nil, nil, PageOptions{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.ErrorIs(t, w.InvalidateCache(context.Background(), nil), ErrClosed)
+}
+
+func TestWrapper_FindPageCached_HitAndInvalidate(t *testing.T) {
+	w, _ := newCachedWrapper(t)
+	ctx := context.Background()
+	coll := &cursorCollectionOps{
+		docs:     []any{bson.M{"_id": "1", "n": int32(1)}, bson.M{"_id": "2", "n": int32(2)}},
+		count:    2,
+		collName: "users",
+	}
+	opts := PageOptions{Page: 1, PageSize: 10, Sort: bson.D{{Key: "_id", Value: 1}}}
+
+	calls := 0
+	query := func(ctx context.Context) (*PageResult, error) {
+		calls++
+		return w.findPageInternal(ctx, coll, bson.M{"status": "active"}, opts)
+	}
+
+	first, err := w.findPageCached(ctx, coll, bson.M{"status": "active"}, opts, query)
+	require.NoError(t, err)
+	second, err := w.findPageCached(ctx, coll, bson.M{"status": "active"}, opts, query)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "second query must hit the cache")
+	assert.Equal(t, first, second)
+	assert.Equal(t, int64(2), second.Total)
+	assert.Equal(t, int32(2), second.Data[1]["n"], "BSON types survive the cache round trip")
+
+	// 不同分页参数使用不同 key
+	_, err = w.findPageCached(ctx, coll, bson.M{"status": "active"}, PageOptions{Page: 2, PageSize: 10}, query)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	require.NoError(t, w.invalidateCache(ctx, coll))
+	_, err = w.findPageCached(ctx, coll, bson.M{"status": "active"}, opts, query)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "invalidation must force a reload")
+}
+
+func TestWrapper_FindPageCached_Errors(t *testing.T) {
+	w, mr := newCachedWrapper(t)
+	ctx := context.Background()
+	coll := newMockCollectionOps()
+	opts := PageOptions{Page: 1, PageSize: 10}
+
+	_, err := w.findPageCached(ctx, coll, nil, PageOptions{Page: 0, PageSize: 10}, nil)
+	assert.ErrorIs(t, err, ErrInvalidPage)
+
+	// 查询错误透传且不写入缓存
+	calls := 0
+	query := func(context.Context) (*PageResult, error) {
+		calls++
+		return nil, errMockFind
+	}
+	for range 2 {
+		_, err = w.findPageCached(ctx, coll, nil, opts, query)
+		assert.ErrorIs(t, err, errMockFind)
+	}
+	assert.Equal(t, 2, calls)
+
+	// 缓存内容被破坏
+	hash, err := queryHash(nil, opts)
+	require.NoError(t, err)
+	require.NoError(t, mr.Set(queryCacheKeyPrefix+cacheNamespace(coll)+":g0:"+hash, "garbage"))
+	_, err = w.findPageCached(ctx, coll, nil, opts, query)
+	assert.ErrorIs(t, err, ErrCacheCorrupted)
+
+	// Redis 不可用
+	mr.Close()
+	_, err = w.findPageCached(ctx, coll, nil, opts, query)
+	assert.Error(t, err)
+	assert.ErrorIs(t, w.invalidateCache(ctx, coll), ErrCacheInvalidate)
+}
+
+func TestWrapper_BulkInsertInvalidatesCache(t *testing.T) {
+	w, mr := newCachedWrapper(t)
+	ctx := context.Background()
+	coll := &cursorCollectionOps{collName: "events"}
+	genKey := cacheGenerationKey(cacheNamespace(coll))
+
+	_, err := w.bulkInsertInternal(ctx, coll, []any{bson.M{"a": 1}}, BulkOptions{})
+	require.NoError(t, err)
+	gen, err := mr.Get(genKey)
+	require.NoError(t, err)
+	assert.Equal(t, "1", gen)
+
+	// 全部写入失败时不失效
+	failing := newMockCollectionOps()
+	failing.collName = "events"
+	failing.insertErr = errMockInsert
+	_, err = w.bulkInsertInternal(ctx, failing, []any{bson.M{"a": 1}}, BulkOptions{})
+	require.ErrorIs(t, err, errMockInsert)
+	gen, err = mr.Get(genKey)
+	require.NoError(t, err)
+	assert.Equal(t, "1", gen)
+
+	// 失效失败时仍返回写入结果
+	mr.Close()
+	result, err := w.bulkInsertInternal(ctx, coll, []any{bson.M{"a": 1}}, BulkOptions{})
+	assert.ErrorIs(t, err, ErrCacheInvalidate)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(1), result.InsertedCount)
+	assert.Empty(t, result.Errors)
+}
+
+func TestQueryHash(t *testing.T) {
+	opts := PageOptions{Page: 1, PageSize: 10}
+	hash := func(filter any, o PageOptions) string {
+		h, err := queryHash(filter, o)
+		require.NoError(t, err)
+		return h
+	}
+
+	// map 字段顺序不影响 key
+	m := bson.M{"a": 1, "b": 2, "c": 3, "d": bson.M{"x": 1, "y": 2}}
+	for range 20 {
+		assert.Equal(t, hash(m, opts), hash(bson.M{"d": bson.M{"y": 2, "x": 1}, "c": 3, "b": 2, "a": 1}, opts))
+	}
+	assert.Equal(t, hash(nil, opts), hash(bson.D{}, opts))
+
+	// bson.D 顺序有语义，保留
+	assert.NotEqual(t,
+		hash(bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 2}}, opts),
+		hash(bson.D{{Key: "b", Value: 2}, {Key: "a", Value: 1}}, opts))
+	assert.NotEqual(t, hash(m, opts), hash(m, PageOptions{Page: 2, PageSize: 10}))
+	assert.NotEqual(t, hash(m, opts), hash(m, PageOptions{Page: 1, PageSize: 10, Sort: bson.D{{Key: "a", Value: -1}}}))
+
+	_, err := queryHash(bson.M{"ch": make(chan int)}, opts)
+	assert.Error(t, err)
+}
+
+func TestWithQueryCache(t *testing.T) {
+	opts := defaultOptions()
+	assert.Equal(t, DefaultQueryCacheTTL, opts.QueryCacheTTL)
+
+	WithQueryCache(nil, nil)(opts)
+	assert.Nil(t, opts.QueryCache)
+	assert.Nil(t, opts.QueryCacheLoader)
+
+	WithQueryCacheTTL(-time.Second)(opts)
+	assert.Equal(t, DefaultQueryCacheTTL, opts.QueryCacheTTL)
+	WithQueryCacheTTL(10 * time.Second)(opts)
+	assert.Equal(t, 10*time.Second, opts.QueryCacheTTL)
+}
+
+func TestWrapper_QueryCache_PublicAPI(t *testing.T) {
+	// mongo.Connect 不会立即建连，足以构造 Collection 覆盖公开方法
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+	coll := client.Database("db").Collection("items")
+	ctx := context.Background()
+
+	plain := &mongoWrapper{options: defaultOptions()}
+	_, err = plain.FindPageCached(ctx, coll, nil, PageOptions{Page: 1, PageSize: 10})
+	assert.ErrorIs(t, err, ErrCacheNotConfigured)
+	assert.ErrorIs(t, plain.InvalidateCache(ctx, coll), ErrCacheNotConfigured)
+
+	w, mr := newCachedWrapper(t)
+	require.NoError(t, w.InvalidateCache(ctx, coll))
+	gen, err := mr.Get(cacheGenerationKey("db.items"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", gen)
+
+	// 预置缓存后 FindPageCached 直接命中，不访问 MongoDB
+	cached, err := bson.Marshal(&PageResult{Data: []bson.M{{"_id": "x"}}, Total: 1, Page: 1, PageSize: 10, TotalPages: 1})
+	require.NoError(t, err)
+	hash, err := queryHash(nil, PageOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.NoError(t, mr.Set(queryCacheKeyPrefix+"db.items:g1:"+hash, string(cached)))
+
+	result, err := w.FindPageCached(ctx, coll, nil, PageOptions{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Total)
+	assert.Equal(t, "x", result.Data[0]["_id"])
+}
@@ -5,7 +5,7 @@
 // xmongo 不包装底层客户端的所有 API，而是提供：
 //   - 统一的工厂方法（New）和便捷连接函数（Connect）
 //   - 底层客户端直接暴露（Client() 方法）
-//   - 增值功能（健康检查、统计、分页查询、批量插入、慢查询检测）
+//   - 增值功能（健康检查、统计、分页查询、批量插入、upsert、慢查询检测）
 //
 // 通过 Client() 直接执行的操作不会进入统计和慢查询检测。
 //
@@ -16,6 +16,9 @@
 //   - Stats()：统计信息
 //   - FindPage()：分页查询（支持排序、字段投影，PageSize 上限 MaxPageSize=10000）
 //   - BulkInsert()：批量插入（支持 context 取消，BatchSize 上限 10000）
+//   - Upsert()/BulkUpsert()：存在则更新、不存在则插入，返回是否插入及新文档 _id
+//   - AggregateStream()：流式处理聚合结果（逐条回调 handler，不一次性物化结果集）
+//   - FindPageCached()/InvalidateCache()：基于 xcache 的分页查询结果缓存
 //   - 慢查询检测：支持同步（SlowQueryHook）和异步（AsyncSlowQueryHook）回调
 //
 // Close() 可安全重复调用，首次关闭执行断连，后续调用返回 ErrClosed。
@@ -30,8 +33,9 @@
 //
 // # 超时兜底
 //
-// FindPage 和 BulkInsert 默认自带兜底超时（查询 30 秒，写入 60 秒），
+// FindPage、AggregateStream、BulkInsert 和 Upsert/BulkUpsert 默认自带兜底超时（查询 30 秒，写入 60 秒），
 // 仅当调用方 context 没有 deadline 时生效；已设置 deadline 的 context 不受影响。
+// AggregateStream 的兜底超时覆盖整个遍历过程，导出等长时间遍历应显式设置 deadline。
 //
 // 可通过 WithQueryTimeout / WithWriteTimeout 调整兜底超时：
 //
@@ -47,6 +51,19 @@
 //	    xmongo.WithWriteTimeout(0),  // 禁用 BulkInsert 兜底超时
 //	)
 //
+// # 聚合流式处理
+//
+// AggregateStream 逐条读取聚合 cursor 并调用 handler，适合导出报表等需要遍历
+// 全部聚合结果、又不希望一次性物化到内存的场景：
+//
+//	err := m.AggregateStream(ctx, coll, pipeline, func(ctx context.Context, doc bson.Raw) error {
+//	    return writer.Write(doc)
+//	}, xmongo.AggregateOptions{BatchSize: 500, AllowDiskUse: true})
+//
+// 服务端 cursor 空闲超过 cursorTimeoutMillis（默认 10 分钟）会被回收，此时返回包装
+// ErrCursorTimeout 的错误。handler 较慢时应减小 BatchSize，使 getMore 更频繁。
+// 慢查询检测的耗时包含 handler 执行时间。
+//
 // # 便捷连接
 //
 // Connect 函数提供从 URI 一步创建 Mongo 实例的能力。
@@ -64,9 +81,66 @@
 // configFn 为 nil 时使用 URI 中的默认配置。
 // 已有 *mongo.Client 的场景仍应使用 New()。
 //
+// # 读写分离
+//
+// WithReadFromSecondary 让 FindPage 自动使用 SecondaryPreferred 读偏好，
+// BulkInsert 等写操作始终由主节点处理，无需在每个 Collection 上手动设置：
+//
+//	m, _ := xmongo.New(client, xmongo.WithReadFromSecondary())
+//
+// 一致性权衡：从节点存在复制延迟，启用后 FindPage 不保证读到刚写入的数据；
+// 计数与查询可能路由到不同节点，Total 与 Data 可能短暂不一致。
+// 对实时性敏感的读取应使用未启用此选项的实例。
+//
+// # 查询缓存
+//
+// WithQueryCache 接入 xcache 后，FindPageCached 以「集合 + filter + 分页参数」的哈希为 key
+// 缓存分页结果，未命中时通过 xcache.Loader 回源（可复用其 singleflight、分布式锁防击穿）：
+//
+//	m, _ := xmongo.New(client,
+//	    xmongo.WithQueryCache(cache, loader),
+//	    xmongo.WithQueryCacheTTL(30*time.Second),
+//	)
+//	page, err := m.FindPageCached(ctx, coll, bson.M{"status": "active"}, opts)
+//
+// 失效策略：每个集合维护一个缓存代数，key 中带有代数，InvalidateCache 递增代数即可
+// 让该集合全部缓存失效，旧 key 由 TTL 淘汰，无需 SCAN/DEL。BulkInsert、Upsert、BulkUpsert
+// 写入成功（含部分成功）后自动失效；失效失败时返回包装 ErrCacheInvalidate 的错误，
+// 此时文档已写入，调用方可根据返回的结果判断，只需重试 InvalidateCache。
+//
+// 设计决策: 按集合整体失效而非按文档精确失效。分页结果依赖 filter、排序与 skip，
+// 任意一次写入都可能改变任意一页的内容和 Total，无法低成本判断哪些 key 受影响。
+//
+// 一致性权衡：
+//   - 通过 Client() 执行的写入（更新、删除等）xmongo 无法感知，需随后调用 InvalidateCache，
+//     否则最多读到 QueryCacheTTL 之前的数据
+//   - 写入完成到失效完成之间的短暂窗口内，读取仍可能命中旧缓存
+//   - 失效前已读到旧代数的并发查询会把结果写到旧代数 key 下，失效后的读取不会命中它们
+//   - 多个服务共用同一集合时，须共用同一 Redis 才能互相感知失效
+//   - 对实时性敏感的查询应继续使用 FindPage
+//
+// # Upsert
+//
+// Upsert 封装 UpdateOne + SetUpsert(true)，纳入统计与慢查询检测，并返回是否插入：
+//
+//	res, err := m.Upsert(ctx, coll,
+//	    bson.M{"user_id": uid},
+//	    bson.M{"$set": bson.M{"name": name}, "$setOnInsert": bson.M{"created_at": now}},
+//	)
+//	if res.Inserted { ... } // res.UpsertedID 为新文档 _id
+//
+// BulkUpsert 按 BulkOptions 分批执行多个 upsert，UpsertedIDs 以原始 models 下标为 key：
+//
+//	res, err := m.BulkUpsert(ctx, coll, []xmongo.UpsertModel{
+//	    {Filter: bson.M{"sku": "a"}, Update: bson.M{"$inc": bson.M{"stock": 1}}},
+//	    {Filter: bson.M{"sku": "b"}, Update: bson.M{"$inc": bson.M{"stock": 2}}},
+//	}, xmongo.BulkOptions{})
+//
+// filter 字段上应建立唯一索引，否则并发 upsert 同一个不存在的文档可能插入重复文档。
+//
 // # Write Concern / Read Preference
 //
-// xmongo 不提供 Write Concern 和 Read Preference 的配置入口。
+// 除 WithReadFromSecondary 外，xmongo 不提供 Write Concern 和 Read Preference 的配置入口。
 // 这些属性应在创建 Collection 时通过 Client() 设置：
 //
 //	coll := m.Client().Database("mydb",
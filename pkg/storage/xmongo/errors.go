@@ -16,7 +16,7 @@ var (
 	ErrNilClient = errors.New("xmongo: nil client")
 
 	// ErrNilContext 表示传入的 context 为 nil。
-	// 所有接受 context 的公开方法（Health、FindPage、BulkInsert）在入口处检查此条件。
+	// 所有接受 context 的公开方法（Health、FindPage、FindPageCached、InvalidateCache、BulkInsert、Upsert、BulkUpsert、AggregateStream）在入口处检查此条件。
 	// Close 是例外：nil context 会被替换为 context.Background()，因为关闭操作不应因 nil ctx 而失败。
 	ErrNilContext = errors.New("xmongo: context must not be nil")
 
@@ -57,19 +57,49 @@ var (
 	ErrSkipTooLarge = errors.New("xmongo: skip exceeds maximum limit, use cursor pagination instead")
 )
 
+// =============================================================================
+// 聚合查询错误
+// =============================================================================
+
+var (
+	// ErrNilHandler 表示传入的 AggregateHandler 为 nil。
+	ErrNilHandler = errors.New("xmongo: nil handler")
+
+	// ErrCursorTimeout 表示服务端 cursor 已因空闲超时被回收（CursorNotFound）。
+	// 通常由 handler 处理过慢导致，可减小 AggregateOptions.BatchSize 后重试。
+	ErrCursorTimeout = errors.New("xmongo: cursor timed out on server")
+)
+
 // =============================================================================
 // 批量写入错误
 // =============================================================================
 
 var (
-	// ErrEmptyDocs 表示文档列表为空。
+	// ErrEmptyDocs 表示文档列表（或 BulkUpsert 的操作列表）为空。
 	ErrEmptyDocs = errors.New("xmongo: empty documents")
 )
 
+// =============================================================================
+// 查询缓存错误
+// =============================================================================
+
+var (
+	// ErrCacheNotConfigured 表示未通过 WithQueryCache 配置查询缓存，
+	// 却调用了 FindPageCached 或 InvalidateCache。
+	ErrCacheNotConfigured = errors.New("xmongo: query cache not configured")
+
+	// ErrCacheInvalidate 表示递增集合缓存代数失败，该集合的旧缓存在 TTL 内仍可能被读取。
+	ErrCacheInvalidate = errors.New("xmongo: query cache invalidate failed")
+
+	// ErrCacheCorrupted 表示缓存中的查询结果无法解码（如被其他程序写入了同名 key）。
+	ErrCacheCorrupted = errors.New("xmongo: query cache corrupted")
+)
+
 // BulkBatchError 包装单个批次的写入错误，附带该批次在原始文档切片中的起始偏移。
 //
-// 背景: BulkInsert 将 docs 分批调用 InsertMany；mongo-driver 的 BulkWriteException.WriteErrors[].Index
-// 是相对于当前 batch 的局部索引。调用方需要把局部索引加上 BatchOffset 才能定位到原始 docs 中的全局位置，
+// 背景: BulkInsert / BulkUpsert 将 docs（或 models）分批调用 InsertMany / BulkWrite；
+// mongo-driver 的 BulkWriteException.WriteErrors[].Index 是相对于当前 batch 的局部索引。
+// 调用方需要把局部索引加上 BatchOffset 才能定位到原始 docs 中的全局位置，
 // 否则重试失败项时会映射到错误的文档。
 type BulkBatchError struct {
 	// BatchIndex 批次序号（从 0 开始）。
@@ -81,12 +111,19 @@ type BulkBatchError struct {
 	BatchSize int
 	// Err 底层错误（通常是 mongo.BulkWriteException，也可能是 context.Canceled 等）。
 	Err error
+
+	// op 批量操作名称，空值表示 bulk_insert。
+	op string
 }
 
 // Error 实现 error 接口。
 func (e *BulkBatchError) Error() string {
-	return fmt.Sprintf("xmongo bulk_insert batch %d [offset=%d size=%d]: %v",
-		e.BatchIndex, e.BatchOffset, e.BatchSize, e.Err)
+	op := e.op
+	if op == "" {
+		op = "bulk_insert"
+	}
+	return fmt.Sprintf("xmongo %s batch %d [offset=%d size=%d]: %v",
+		op, e.BatchIndex, e.BatchOffset, e.BatchSize, e.Err)
 }
 
 // Unwrap 返回底层错误，支持 errors.Is/errors.As 向下匹配 BulkWriteException 等。
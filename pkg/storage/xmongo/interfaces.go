@@ -26,8 +26,12 @@ type collectionOperations interface {
 	CountDocuments(ctx context.Context, filter any, opts ...options.Lister[options.CountOptions]) (int64, error)
 	Find(ctx context.Context, filter any, opts ...options.Lister[options.FindOptions]) (*mongo.Cursor, error)
 	InsertMany(ctx context.Context, documents []any, opts ...options.Lister[options.InsertManyOptions]) (*mongo.InsertManyResult, error)
+	UpdateOne(ctx context.Context, filter any, update any, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error)
+	BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error)
+	Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error)
 	Database() *mongo.Database
 	Name() string
+	Clone(opts ...options.Lister[options.CollectionOptions]) collectionOperations
 }
 
 // =============================================================================
@@ -51,10 +55,26 @@ func (a *collectionAdapter) InsertMany(ctx context.Context, documents []any, opt
 	return a.coll.InsertMany(ctx, documents, opts...)
 }
 
+func (a *collectionAdapter) UpdateOne(ctx context.Context, filter any, update any, opts ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return a.coll.UpdateOne(ctx, filter, update, opts...)
+}
+
+func (a *collectionAdapter) BulkWrite(ctx context.Context, models []mongo.WriteModel, opts ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error) {
+	return a.coll.BulkWrite(ctx, models, opts...)
+}
+
+func (a *collectionAdapter) Aggregate(ctx context.Context, pipeline any, opts ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return a.coll.Aggregate(ctx, pipeline, opts...)
+}
+
 func (a *collectionAdapter) Database() *mongo.Database {
 	return a.coll.Database()
 }
 
+func (a *collectionAdapter) Clone(opts ...options.Lister[options.CollectionOptions]) collectionOperations {
+	return &collectionAdapter{coll: a.coll.Clone(opts...)}
+}
+
 func (a *collectionAdapter) Name() string {
 	return a.coll.Name()
 }
@@ -45,6 +45,13 @@ type mockCollectionOps struct {
 	findErr      error
 	insertResult *mongo.InsertManyResult
 	insertErr    error
+	updateResult *mongo.UpdateResult
+	updateErr    error
+	bulkResults  []*mongo.BulkWriteResult // 按调用顺序返回，耗尽后返回全部 upsert 的结果
+	bulkErrs     []error                  // 按调用顺序返回的错误
+	bulkBatches  []int                    // 每次 BulkWrite 的操作数
+	aggCursor    *mongo.Cursor
+	aggErr       error
 	collName     string
 	mockDB       *mongo.Database
 }
@@ -72,6 +79,40 @@ func (m *mockCollectionOps) InsertMany(_ context.Context, documents []any, _ ...
 	return &mongo.InsertManyResult{InsertedIDs: ids}, nil
 }
 
+func (m *mockCollectionOps) UpdateOne(_ context.Context, _ any, _ any, _ ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	if m.updateResult != nil {
+		return m.updateResult, nil
+	}
+	// 默认返回插入了新文档
+	return &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: bson.NewObjectID()}, nil
+}
+
+func (m *mockCollectionOps) BulkWrite(_ context.Context, models []mongo.WriteModel, _ ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error) {
+	call := len(m.bulkBatches)
+	m.bulkBatches = append(m.bulkBatches, len(models))
+
+	var err error
+	if call < len(m.bulkErrs) {
+		err = m.bulkErrs[call]
+	}
+	if call < len(m.bulkResults) {
+		return m.bulkResults[call], err
+	}
+	// 默认每个操作都插入了新文档
+	ids := make(map[int64]any, len(models))
+	for i := range models {
+		ids[int64(i)] = bson.NewObjectID()
+	}
+	return &mongo.BulkWriteResult{UpsertedCount: int64(len(models)), UpsertedIDs: ids}, err
+}
+
+func (m *mockCollectionOps) Aggregate(_ context.Context, _ any, _ ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return m.aggCursor, m.aggErr
+}
+
 func (m *mockCollectionOps) Database() *mongo.Database {
 	return m.mockDB
 }
@@ -80,6 +121,10 @@ func (m *mockCollectionOps) Name() string {
 	return m.collName
 }
 
+func (m *mockCollectionOps) Clone(_ ...options.Lister[options.CollectionOptions]) collectionOperations {
+	return m
+}
+
 // =============================================================================
 // 辅助构造函数
 // =============================================================================
@@ -109,10 +154,14 @@ func newMockCollectionOps() *mockCollectionOps {
 // =============================================================================
 
 // cursorCollectionOps 使用 mongo.NewCursorFromDocuments 返回可解码的 cursor。
+// Clone 返回带集合级读偏好的副本并记录在 clones 中，Find 时捕获实际查询使用的读偏好。
 type cursorCollectionOps struct {
-	docs     []any
-	count    int64
-	collName string
+	docs         []any
+	count        int64
+	collName     string
+	readPref     *readpref.ReadPref
+	findReadPref *readpref.ReadPref
+	clones       []*cursorCollectionOps
 }
 
 func (c *cursorCollectionOps) CountDocuments(_ context.Context, _ any, _ ...options.Lister[options.CountOptions]) (int64, error) {
@@ -120,6 +169,7 @@ func (c *cursorCollectionOps) CountDocuments(_ context.Context, _ any, _ ...opti
 }
 
 func (c *cursorCollectionOps) Find(_ context.Context, _ any, _ ...options.Lister[options.FindOptions]) (*mongo.Cursor, error) {
+	c.findReadPref = c.readPref
 	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
 }
 
@@ -131,6 +181,18 @@ func (c *cursorCollectionOps) InsertMany(_ context.Context, documents []any, _ .
 	return &mongo.InsertManyResult{InsertedIDs: ids}, nil
 }
 
+func (c *cursorCollectionOps) UpdateOne(_ context.Context, _ any, _ any, _ ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (c *cursorCollectionOps) BulkWrite(_ context.Context, models []mongo.WriteModel, _ ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error) {
+	return &mongo.BulkWriteResult{MatchedCount: int64(len(models)), ModifiedCount: int64(len(models))}, nil
+}
+
+func (c *cursorCollectionOps) Aggregate(_ context.Context, _ any, _ ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
+}
+
 func (c *cursorCollectionOps) Database() *mongo.Database {
 	return nil
 }
@@ -139,6 +201,21 @@ func (c *cursorCollectionOps) Name() string {
 	return c.collName
 }
 
+func (c *cursorCollectionOps) Clone(opts ...options.Lister[options.CollectionOptions]) collectionOperations {
+	var collOpts options.CollectionOptions
+	for _, opt := range opts {
+		for _, set := range opt.List() {
+			_ = set(&collOpts)
+		}
+	}
+	clone := *c
+	clone.readPref = collOpts.ReadPreference
+	clone.findReadPref = nil
+	clone.clones = nil
+	c.clones = append(c.clones, &clone)
+	return &clone
+}
+
 // =============================================================================
 // 错误定义
 // =============================================================================
@@ -149,4 +226,7 @@ var (
 	errMockCount      = errors.New("mock count error")
 	errMockFind       = errors.New("mock find error")
 	errMockInsert     = errors.New("mock insert error")
+	errMockAggregate  = errors.New("mock aggregate error")
+	errMockUpdate     = errors.New("mock update error")
+	errMockBulkWrite  = errors.New("mock bulk write error")
 )
@@ -57,6 +57,46 @@ type Mongo interface {
 	// MongoDB 官方 Collection.BulkWrite 接受 WriteModel 列表，支持 Insert/Update/Delete/Replace
 	// 四种混合操作。为避免语义混淆，此处使用更精确的命名。
 	BulkInsert(ctx context.Context, coll *mongo.Collection, docs []any, opts BulkOptions) (*BulkResult, error)
+
+	// Upsert 存在则更新，不存在则插入。
+	// 等价于 UpdateOne + SetUpsert(true)，纳入统计、慢查询检测与写入兜底超时，
+	// 返回的 UpsertResult.Inserted 标识本次是否插入了新文档。
+	//
+	// update 需使用更新操作符（如 bson.M{"$set": ...}）；插入时新文档由 filter 中的
+	// 等值条件与 update 共同生成，需要仅在插入时写入的字段请使用 $setOnInsert。
+	// 并发 upsert 同一个不存在的文档时，filter 字段上没有唯一索引可能插入重复文档。
+	Upsert(ctx context.Context, coll *mongo.Collection, filter, update any) (*UpsertResult, error)
+
+	// BulkUpsert 批量 upsert。
+	// 将 models 分批通过 BulkWrite 执行（每个操作为开启 upsert 的 UpdateOne），
+	// 分批、有序/无序语义与 BulkInsert 一致，models 为空时返回 ErrEmptyDocs。
+	// 即使返回错误，result 仍可能包含部分成功的统计。
+	BulkUpsert(ctx context.Context, coll *mongo.Collection, models []UpsertModel, opts BulkOptions) (*BulkUpsertResult, error)
+
+	// AggregateStream 流式处理聚合结果。
+	// 逐条读取 cursor 并调用 handler，内存占用与结果集大小无关，
+	// 适合导出报表等需要遍历全部聚合结果的场景。
+	//
+	// handler 返回错误时停止遍历并返回该错误；服务端 cursor 因空闲超时被回收时
+	// 返回包装 ErrCursorTimeout 的错误。pipeline 为 nil 时按空管道处理。
+	//
+	// 兜底超时（QueryTimeout）覆盖整个遍历过程而非单次 getMore，
+	// 长时间导出应传入带 deadline 的 context 或通过 WithQueryTimeout(0) 禁用兜底。
+	AggregateStream(ctx context.Context, coll *mongo.Collection, pipeline any, handler AggregateHandler, opts AggregateOptions) error
+
+	// FindPageCached 带缓存的分页查询，语义与 FindPage 相同。
+	// 缓存 key 由集合、filter 与分页参数的哈希生成，未命中时通过 xcache.Loader 回源 FindPage。
+	// 未通过 WithQueryCache 配置缓存时返回 ErrCacheNotConfigured。
+	//
+	// 一致性权衡：缓存结果最多陈旧 QueryCacheTTL。经 BulkInsert、Upsert、BulkUpsert 的写入会自动失效该集合缓存；
+	// 通过 Client() 直接执行的写入（更新、删除等）不会被感知，需调用方随后调用 InvalidateCache。
+	// 详见包文档「查询缓存」一节。
+	FindPageCached(ctx context.Context, coll *mongo.Collection, filter any, opts PageOptions) (*PageResult, error)
+
+	// InvalidateCache 使集合的全部查询缓存失效。
+	// 通过递增集合的缓存代数实现，O(1) 且不扫描 key，旧缓存由 TTL 自然淘汰。
+	// 未配置缓存时返回 ErrCacheNotConfigured。
+	InvalidateCache(ctx context.Context, coll *mongo.Collection) error
 }
 
 // =============================================================================
@@ -116,6 +156,27 @@ type PageResult struct {
 	TotalPages int64
 }
 
+// =============================================================================
+// 聚合流式处理类型
+// =============================================================================
+
+// AggregateHandler 处理单条聚合结果。
+// doc 仅在本次调用内有效，需跨调用保留时应复制或通过 bson.Unmarshal 解码。
+type AggregateHandler func(ctx context.Context, doc bson.Raw) error
+
+// AggregateOptions 聚合流式处理选项。
+type AggregateOptions struct {
+	// BatchSize 每次从服务端拉取的文档数，0 表示使用服务端默认值。
+	//
+	// 服务端 cursor 空闲超过 cursorTimeoutMillis（默认 10 分钟）会被回收。
+	// handler 处理较慢时应减小 BatchSize，使 getMore 更频繁、cursor 保持活跃。
+	BatchSize int32
+
+	// AllowDiskUse 允许聚合阶段（如 $sort、$group）使用磁盘临时文件，
+	// 避免大结果集触发服务端 100MB 内存限制。
+	AllowDiskUse bool
+}
+
 // =============================================================================
 // 批量写入类型
 // =============================================================================
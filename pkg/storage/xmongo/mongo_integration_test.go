@@ -250,6 +250,20 @@ func TestMongo_FindPage_Integration(t *testing.T) {
 		assert.Len(t, page.Data, 10)
 	})
 
+	t.Run("读写分离", func(t *testing.T) {
+		// 单节点部署下 SecondaryPreferred 回退到主节点，查询结果与默认一致
+		secondary, err := New(client, WithReadFromSecondary())
+		require.NoError(t, err)
+
+		page, err := secondary.FindPage(ctx, coll, bson.M{}, PageOptions{
+			Page:     1,
+			PageSize: 10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), page.Total)
+		assert.Len(t, page.Data, 10)
+	})
+
 	t.Run("第二页", func(t *testing.T) {
 		page, err := wrapper.FindPage(ctx, coll, bson.M{}, PageOptions{
 			Page:     2,
@@ -475,6 +489,64 @@ func TestMongo_BulkInsert_Integration(t *testing.T) {
 	})
 }
 
+func TestMongo_Upsert_Integration(t *testing.T) {
+	client, cleanup := setupMongo(t)
+	defer cleanup()
+
+	wrapper, err := New(client)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	coll := client.Database("test_upsert").Collection("upsert_items")
+	t.Cleanup(func() {
+		coll.Drop(context.Background())
+	})
+
+	t.Run("单条 upsert", func(t *testing.T) {
+		coll.Drop(ctx)
+		filter := bson.M{"sku": "a"}
+
+		result, err := wrapper.Upsert(ctx, coll, filter, bson.M{"$set": bson.M{"stock": 1}})
+		require.NoError(t, err)
+		assert.True(t, result.Inserted)
+		assert.NotNil(t, result.UpsertedID)
+
+		result, err = wrapper.Upsert(ctx, coll, filter, bson.M{"$set": bson.M{"stock": 2}})
+		require.NoError(t, err)
+		assert.False(t, result.Inserted)
+		assert.Equal(t, int64(1), result.MatchedCount)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+
+		count, err := coll.CountDocuments(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("批量 upsert", func(t *testing.T) {
+		coll.Drop(ctx)
+		_, err := coll.InsertOne(ctx, bson.M{"sku": "s-1", "stock": 0})
+		require.NoError(t, err)
+
+		models := make([]UpsertModel, 5)
+		for i := range models {
+			models[i] = UpsertModel{
+				Filter: bson.M{"sku": fmt.Sprintf("s-%d", i)},
+				Update: bson.M{"$inc": bson.M{"stock": 1}},
+			}
+		}
+		result, err := wrapper.BulkUpsert(ctx, coll, models, BulkOptions{BatchSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.UpsertedCount)
+		assert.Equal(t, int64(1), result.MatchedCount)
+		assert.NotContains(t, result.UpsertedIDs, 1, "s-1 already exists")
+		assert.Contains(t, result.UpsertedIDs, 4)
+
+		count, err := coll.CountDocuments(ctx, bson.M{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), count)
+	})
+}
+
 // =============================================================================
 // 索引操作测试
 // =============================================================================
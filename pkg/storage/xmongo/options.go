@@ -6,6 +6,7 @@ import (
 
 	"github.com/omeyang/xkit/internal/storageopt"
 	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+	"github.com/omeyang/xkit/pkg/storage/xcache"
 )
 
 // =============================================================================
@@ -115,6 +116,21 @@ type Options struct {
 
 	// Observer 是统一观测接口（metrics/tracing）。
 	Observer xmetrics.Observer
+
+	// ReadFromSecondary 为 true 时 FindPage 使用 SecondaryPreferred 读偏好。
+	// 默认为 false，沿用 Collection 自身的读偏好。
+	ReadFromSecondary bool
+
+	// QueryCache 查询结果缓存使用的 Redis，用于读写集合的缓存代数。
+	// 与 QueryCacheLoader 同时设置时 FindPageCached 才可用。
+	QueryCache xcache.Redis
+
+	// QueryCacheLoader 查询结果缓存加载器，负责缓存读取、回源与防击穿。
+	QueryCacheLoader xcache.Loader
+
+	// QueryCacheTTL 查询结果缓存过期时间。
+	// 默认为 DefaultQueryCacheTTL（1 分钟），也是未显式失效时的最大陈旧时间。
+	QueryCacheTTL time.Duration
 }
 
 // Option 定义配置 MongoDB 包装器的函数类型。
@@ -159,6 +175,7 @@ func defaultOptions() *Options {
 		QueryTimeout:            DefaultQueryTimeout,
 		WriteTimeout:            DefaultWriteTimeout,
 		Observer:                xmetrics.NoopObserver{},
+		QueryCacheTTL:           DefaultQueryCacheTTL,
 	}
 }
 
@@ -242,6 +259,22 @@ func WithWriteTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithReadFromSecondary 启用读写分离：FindPage 自动使用 SecondaryPreferred 读偏好，
+// 优先从从节点读取，无可用从节点时回退到主节点。
+//
+// 写操作（BulkInsert）始终由主节点处理（MongoDB 的写入不受读偏好影响）。
+// 此选项覆盖传入 Collection 上设置的读偏好；需要其他读偏好（如 Nearest）时
+// 不要启用此选项，直接在 Collection 上设置。
+//
+// 一致性权衡：从节点复制存在延迟，FindPage 可能读不到刚写入的数据（无 read-your-writes 保证）；
+// FindPage 的计数与查询是两次独立操作，可能路由到不同节点，Total 与 Data 可能不一致。
+// 对实时性敏感的查询应使用未启用此选项的实例，或在会话中使用因果一致性。
+func WithReadFromSecondary() Option {
+	return func(o *Options) {
+		o.ReadFromSecondary = true
+	}
+}
+
 // WithObserver 设置统一观测接口。
 func WithObserver(observer xmetrics.Observer) Option {
 	return func(o *Options) {
@@ -250,3 +283,32 @@ func WithObserver(observer xmetrics.Observer) Option {
 		}
 	}
 }
+
+// WithQueryCache 启用查询结果缓存，供 FindPageCached 使用。
+//
+// cache 用于存储集合的缓存代数（失效计数），loader 负责结果的读取与回源，
+// 通常由同一个 cache 创建：
+//
+//	cache, _ := xcache.NewRedis(rdb)
+//	loader, _ := xcache.NewLoader(cache, xcache.WithSingleflight(true))
+//	m, _ := xmongo.New(client, xmongo.WithQueryCache(cache, loader))
+//
+// 任一参数为 nil 时忽略此选项。
+func WithQueryCache(cache xcache.Redis, loader xcache.Loader) Option {
+	return func(o *Options) {
+		if cache != nil && loader != nil {
+			o.QueryCache = cache
+			o.QueryCacheLoader = loader
+		}
+	}
+}
+
+// WithQueryCacheTTL 设置查询结果缓存过期时间。
+// 非正值被忽略（保持默认值 DefaultQueryCacheTTL）。
+func WithQueryCacheTTL(ttl time.Duration) Option {
+	return func(o *Options) {
+		if ttl > 0 {
+			o.QueryCacheTTL = ttl
+		}
+	}
+}
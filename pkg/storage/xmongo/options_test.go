@@ -150,6 +150,15 @@ func TestWithObserver_Nil(t *testing.T) {
 	assert.Equal(t, original, opts.Observer)
 }
 
+func TestWithReadFromSecondary(t *testing.T) {
+	opts := defaultOptions()
+	assert.False(t, opts.ReadFromSecondary)
+
+	WithReadFromSecondary()(opts)
+
+	assert.True(t, opts.ReadFromSecondary)
+}
+
 func TestOptionsChaining(t *testing.T) {
 	var hookCalled bool
 	hook := func(_ context.Context, _ SlowQueryInfo) {
@@ -0,0 +1,237 @@
+package xmongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/omeyang/xkit/internal/storageopt"
+	"github.com/omeyang/xkit/pkg/observability/xmetrics"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// =============================================================================
+// Upsert 类型
+// =============================================================================
+
+// UpsertResult 单条 upsert 结果。
+type UpsertResult struct {
+	// Inserted 是否插入了新文档（filter 未匹配到任何文档）。
+	Inserted bool
+
+	// UpsertedID 新插入文档的 _id，Inserted 为 false 时为 nil。
+	UpsertedID any
+
+	// MatchedCount 匹配到的文档数（0 或 1）。
+	MatchedCount int64
+
+	// ModifiedCount 实际被修改的文档数。
+	// 匹配到文档但更新内容与原值相同时为 0。
+	ModifiedCount int64
+}
+
+// UpsertModel 批量 upsert 中的单个操作。
+type UpsertModel struct {
+	// Filter 匹配条件，与 UpdateOne 的 filter 相同。
+	Filter any
+
+	// Update 更新文档，需使用更新操作符（如 bson.M{"$set": ...}）。
+	Update any
+}
+
+// BulkUpsertResult 批量 upsert 结果。
+//
+// 与 BulkResult 相同，即使返回的 error 不为 nil，result 仍可能包含部分成功的统计。
+type BulkUpsertResult struct {
+	// MatchedCount 匹配到已有文档的操作数。
+	MatchedCount int64
+
+	// ModifiedCount 实际被修改的文档数。
+	ModifiedCount int64
+
+	// UpsertedCount 插入新文档的操作数。
+	UpsertedCount int64
+
+	// UpsertedIDs 插入的新文档 _id，key 为该操作在原始 models 中的下标。
+	UpsertedIDs map[int]any
+
+	// Errors 写入过程中的错误列表，类型为 *BulkBatchError，语义同 BulkResult.Errors。
+	Errors []error
+}
+
+// =============================================================================
+// 公开方法
+// =============================================================================
+
+// Upsert 存在则更新，不存在则插入。
+func (w *mongoWrapper) Upsert(ctx context.Context, coll *mongo.Collection, filter, update any) (*UpsertResult, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+	if coll == nil {
+		return nil, ErrNilCollection
+	}
+	return w.upsertInternal(ctx, adaptCollection(coll), filter, update)
+}
+
+// BulkUpsert 批量 upsert。
+func (w *mongoWrapper) BulkUpsert(ctx context.Context, coll *mongo.Collection, models []UpsertModel, opts BulkOptions) (*BulkUpsertResult, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if w.closed.Load() {
+		return nil, ErrClosed
+	}
+	if coll == nil {
+		return nil, ErrNilCollection
+	}
+	if len(models) == 0 {
+		return nil, ErrEmptyDocs
+	}
+	return w.bulkUpsertInternal(ctx, adaptCollection(coll), models, opts)
+}
+
+// =============================================================================
+// 内部实现
+// =============================================================================
+
+// startWrite 为写操作添加兜底超时并开启 span，返回的 finish 负责慢查询检测与结束 span。
+func (w *mongoWrapper) startWrite(ctx context.Context, coll collectionOperations, operation, spanName string, filter any) (context.Context, func(err error)) {
+	ctx, cancel := applyTimeout(ctx, w.options.WriteTimeout)
+
+	info := buildSlowQueryInfoFromOps(coll, operation, filter)
+	start := time.Now()
+	ctx, span := xmetrics.Start(ctx, w.options.Observer, xmetrics.SpanOptions{
+		Component: mongoComponent,
+		Operation: spanName,
+		Kind:      xmetrics.KindClient,
+		Attrs: []xmetrics.Attr{
+			xmetrics.String("db.system", "mongodb"),
+			xmetrics.String("db.name", info.Database),
+			xmetrics.String("db.collection", info.Collection),
+		},
+	})
+
+	return ctx, func(err error) {
+		defer cancel()
+		info.Duration = storageopt.MeasureOperation(start)
+		slow := w.maybeSlowQuery(ctx, info)
+
+		var attrs []xmetrics.Attr
+		if slow {
+			attrs = append(attrs,
+				xmetrics.Bool("slow", true),
+				xmetrics.Int64("slow_threshold_ms", w.options.SlowQueryThreshold.Milliseconds()),
+			)
+		}
+		span.End(xmetrics.Result{Err: err, Attrs: attrs})
+	}
+}
+
+// upsertInternal 单条 upsert 内部实现，使用接口便于测试。
+func (w *mongoWrapper) upsertInternal(ctx context.Context, coll collectionOperations, filter, update any) (result *UpsertResult, err error) {
+	ctx, finish := w.startWrite(ctx, coll, "upsert", "upsert", filter)
+	defer func() { finish(err) }()
+
+	res, err := coll.UpdateOne(ctx, filter, update, options.UpdateOne().SetUpsert(true))
+	if err != nil {
+		return nil, fmt.Errorf("xmongo upsert: %w", err)
+	}
+
+	result = &UpsertResult{
+		Inserted:      res.UpsertedCount > 0,
+		UpsertedID:    res.UpsertedID,
+		MatchedCount:  res.MatchedCount,
+		ModifiedCount: res.ModifiedCount,
+	}
+
+	// 有文档变更时使该集合的查询缓存失效
+	if (result.Inserted || result.ModifiedCount > 0) && w.cacheEnabled() {
+		if invErr := w.invalidateCache(ctx, coll); invErr != nil {
+			return result, invErr
+		}
+	}
+	return result, nil
+}
+
+// bulkUpsertInternal 批量 upsert 内部实现，使用接口便于测试。
+func (w *mongoWrapper) bulkUpsertInternal(ctx context.Context, coll collectionOperations, models []UpsertModel, opts BulkOptions) (result *BulkUpsertResult, err error) {
+	ctx, finish := w.startWrite(ctx, coll, "bulkUpsert", "bulk_upsert", nil)
+	defer func() { finish(err) }()
+
+	batchSize := normalizeBatchSize(opts.BatchSize)
+	writeOpts := options.BulkWrite().SetOrdered(opts.Ordered)
+	result = &BulkUpsertResult{}
+
+	for i := 0; i < len(models); i += batchSize {
+		end := min(i+batchSize, len(models))
+		batchErr := &BulkBatchError{BatchIndex: i / batchSize, BatchOffset: i, BatchSize: end - i, op: "bulk_upsert"}
+
+		// 每批次开始前检查 context 是否已取消，避免无效工作
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			batchErr.Err = fmt.Errorf("context canceled before batch: %w", ctxErr)
+			result.Errors = append(result.Errors, batchErr)
+			break
+		}
+
+		res, writeErr := coll.BulkWrite(ctx, buildUpsertModels(models[i:end]), writeOpts)
+		result.merge(res, i)
+		if writeErr == nil {
+			continue
+		}
+		batchErr.Err = writeErr
+		result.Errors = append(result.Errors, batchErr)
+		// 有序模式遇到错误停止；无序模式下 context 已取消时同样停止
+		if opts.Ordered || ctx.Err() != nil {
+			break
+		}
+	}
+
+	var resultErr error
+	if len(result.Errors) > 0 {
+		resultErr = errors.Join(result.Errors...)
+	}
+
+	// 有文档变更时使该集合的查询缓存失效（部分失败也可能已写入部分文档）
+	if result.UpsertedCount+result.ModifiedCount > 0 && w.cacheEnabled() {
+		if invErr := w.invalidateCache(ctx, coll); invErr != nil {
+			resultErr = errors.Join(resultErr, invErr)
+		}
+	}
+	return result, resultErr
+}
+
+// buildUpsertModels 将 UpsertModel 转换为开启 upsert 的 UpdateOneModel。
+func buildUpsertModels(models []UpsertModel) []mongo.WriteModel {
+	writeModels := make([]mongo.WriteModel, len(models))
+	for i, m := range models {
+		writeModels[i] = mongo.NewUpdateOneModel().
+			SetFilter(m.Filter).
+			SetUpdate(m.Update).
+			SetUpsert(true)
+	}
+	return writeModels
+}
+
+// merge 累加单个批次的写入结果，offset 为本批在原始 models 中的起始下标。
+// res 可能为 nil（如网络错误）；BulkWriteException 时 res 仍携带部分成功的统计。
+func (r *BulkUpsertResult) merge(res *mongo.BulkWriteResult, offset int) {
+	if res == nil {
+		return
+	}
+	r.MatchedCount += res.MatchedCount
+	r.ModifiedCount += res.ModifiedCount
+	r.UpsertedCount += res.UpsertedCount
+	for idx, id := range res.UpsertedIDs {
+		if r.UpsertedIDs == nil {
+			r.UpsertedIDs = make(map[int]any, len(res.UpsertedIDs))
+		}
+		r.UpsertedIDs[offset+int(idx)] = id
+	}
+}
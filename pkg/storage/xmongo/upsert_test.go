@@ -0,0 +1,211 @@
+package xmongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func upsertModels(n int) []UpsertModel {
+	models := make([]UpsertModel, n)
+	for i := range models {
+		models[i] = UpsertModel{
+			Filter: bson.M{"seq": i},
+			Update: bson.M{"$set": bson.M{"seq": i}},
+		}
+	}
+	return models
+}
+
+func TestWrapper_Upsert_Validation(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+	ctx := context.Background()
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err := w.Upsert(nil, nil, bson.M{}, bson.M{})
+	assert.ErrorIs(t, err, ErrNilContext)
+	_, err = w.Upsert(ctx, nil, bson.M{}, bson.M{})
+	assert.ErrorIs(t, err, ErrNilCollection)
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err = w.BulkUpsert(nil, nil, upsertModels(1), BulkOptions{})
+	assert.ErrorIs(t, err, ErrNilContext)
+	_, err = w.BulkUpsert(ctx, nil, upsertModels(1), BulkOptions{})
+	assert.ErrorIs(t, err, ErrNilCollection)
+	_, err = w.BulkUpsert(ctx, &mongo.Collection{}, nil, BulkOptions{})
+	assert.ErrorIs(t, err, ErrEmptyDocs)
+
+	w.closed.Store(true)
+	_, err = w.Upsert(ctx, nil, bson.M{}, bson.M{})
+	assert.ErrorIs(t, err, ErrClosed)
+	_, err = w.BulkUpsert(ctx, nil, upsertModels(1), BulkOptions{})
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestWrapper_UpsertInternal(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+	ctx := context.Background()
+
+	t.Run("inserted", func(t *testing.T) {
+		mock := newMockCollectionOps()
+		id := bson.NewObjectID()
+		mock.updateResult = &mongo.UpdateResult{UpsertedCount: 1, UpsertedID: id}
+
+		result, err := w.upsertInternal(ctx, mock, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 1}})
+		require.NoError(t, err)
+		assert.True(t, result.Inserted)
+		assert.Equal(t, id, result.UpsertedID)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		mock := newMockCollectionOps()
+		mock.updateResult = &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}
+
+		result, err := w.upsertInternal(ctx, mock, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 2}})
+		require.NoError(t, err)
+		assert.False(t, result.Inserted)
+		assert.Nil(t, result.UpsertedID)
+		assert.Equal(t, int64(1), result.MatchedCount)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mock := newMockCollectionOps()
+		mock.updateErr = errMockUpdate
+
+		result, err := w.upsertInternal(ctx, mock, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 1}})
+		assert.ErrorIs(t, err, errMockUpdate)
+		assert.Nil(t, result)
+	})
+}
+
+func TestWrapper_UpsertInternal_SlowQuery(t *testing.T) {
+	var captured SlowQueryInfo
+	opts := &Options{
+		SlowQueryThreshold: time.Nanosecond,
+		SlowQueryHook: func(_ context.Context, info SlowQueryInfo) {
+			captured = info
+		},
+	}
+	detector, err := newSlowQueryDetector(opts)
+	require.NoError(t, err)
+	w := &mongoWrapper{options: opts, slowQueryDetector: detector}
+
+	filter := bson.M{"k": 1}
+	_, err = w.upsertInternal(context.Background(), newMockCollectionOps(), filter, bson.M{"$set": bson.M{"v": 1}})
+	require.NoError(t, err)
+	assert.Equal(t, "upsert", captured.Operation)
+	assert.Equal(t, filter, captured.Filter)
+	assert.Equal(t, "test_collection", captured.Collection)
+
+	_, err = w.bulkUpsertInternal(context.Background(), newMockCollectionOps(), upsertModels(2), BulkOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "bulkUpsert", captured.Operation)
+	assert.Equal(t, int64(2), w.Stats().SlowQueries)
+}
+
+func TestWrapper_BulkUpsertInternal_Batches(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+	mock := newMockCollectionOps()
+
+	result, err := w.bulkUpsertInternal(context.Background(), mock, upsertModels(5), BulkOptions{BatchSize: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, mock.bulkBatches)
+	assert.Equal(t, int64(5), result.UpsertedCount)
+	// UpsertedIDs 以原始 models 下标为 key
+	assert.Len(t, result.UpsertedIDs, 5)
+	for i := range 5 {
+		assert.Contains(t, result.UpsertedIDs, i)
+	}
+	assert.Empty(t, result.Errors)
+}
+
+func TestWrapper_BulkUpsertInternal_PartialFailure(t *testing.T) {
+	id := bson.NewObjectID()
+	partial := &mongo.BulkWriteResult{MatchedCount: 1, ModifiedCount: 1, UpsertedCount: 1, UpsertedIDs: map[int64]any{1: id}}
+
+	t.Run("unordered continues", func(t *testing.T) {
+		w := &mongoWrapper{options: defaultOptions()}
+		mock := newMockCollectionOps()
+		mock.bulkResults = []*mongo.BulkWriteResult{nil, partial}
+		mock.bulkErrs = []error{nil, errMockBulkWrite}
+
+		result, err := w.bulkUpsertInternal(context.Background(), mock, upsertModels(7), BulkOptions{BatchSize: 3})
+		require.ErrorIs(t, err, errMockBulkWrite)
+		assert.Equal(t, []int{3, 3, 1}, mock.bulkBatches)
+		// 第 1 批 nil 结果不计数，第 2 批部分成功，第 3 批默认全部插入
+		assert.Equal(t, int64(2), result.UpsertedCount)
+		assert.Equal(t, int64(1), result.ModifiedCount)
+		assert.Equal(t, id, result.UpsertedIDs[3+1])
+		assert.Contains(t, result.UpsertedIDs, 6)
+
+		require.Len(t, result.Errors, 1)
+		var be *BulkBatchError
+		require.True(t, errors.As(result.Errors[0], &be))
+		assert.Equal(t, 1, be.BatchIndex)
+		assert.Equal(t, 3, be.BatchOffset)
+		assert.Equal(t, 3, be.BatchSize)
+		assert.Contains(t, be.Error(), "bulk_upsert batch 1")
+	})
+
+	t.Run("ordered stops", func(t *testing.T) {
+		w := &mongoWrapper{options: defaultOptions()}
+		mock := newMockCollectionOps()
+		mock.bulkErrs = []error{errMockBulkWrite}
+
+		result, err := w.bulkUpsertInternal(context.Background(), mock, upsertModels(7), BulkOptions{BatchSize: 3, Ordered: true})
+		require.ErrorIs(t, err, errMockBulkWrite)
+		assert.Equal(t, []int{3}, mock.bulkBatches)
+		assert.Len(t, result.Errors, 1)
+	})
+}
+
+func TestWrapper_BulkUpsertInternal_ContextCanceled(t *testing.T) {
+	w := &mongoWrapper{options: defaultOptions()}
+	mock := newMockCollectionOps()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := w.bulkUpsertInternal(ctx, mock, upsertModels(3), BulkOptions{})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, mock.bulkBatches)
+	assert.Len(t, result.Errors, 1)
+}
+
+func TestWrapper_UpsertInvalidatesCache(t *testing.T) {
+	w, mr := newCachedWrapper(t)
+	ctx := context.Background()
+	coll := &cursorCollectionOps{collName: "events"}
+	genKey := cacheGenerationKey(cacheNamespace(coll))
+
+	_, err := w.upsertInternal(ctx, coll, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 1}})
+	require.NoError(t, err)
+	_, err = w.bulkUpsertInternal(ctx, coll, upsertModels(2), BulkOptions{})
+	require.NoError(t, err)
+	gen, err := mr.Get(genKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2", gen)
+
+	// 匹配但未修改时不失效
+	unchanged := newMockCollectionOps()
+	unchanged.collName = "events"
+	unchanged.updateResult = &mongo.UpdateResult{MatchedCount: 1}
+	_, err = w.upsertInternal(ctx, unchanged, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 1}})
+	require.NoError(t, err)
+	gen, err = mr.Get(genKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2", gen)
+
+	// 失效失败时仍返回写入结果
+	mr.Close()
+	result, err := w.upsertInternal(ctx, coll, bson.M{"k": 1}, bson.M{"$set": bson.M{"v": 1}})
+	assert.ErrorIs(t, err, ErrCacheInvalidate)
+	require.NotNil(t, result)
+	assert.Equal(t, int64(1), result.ModifiedCount)
+}
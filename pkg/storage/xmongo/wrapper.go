@@ -184,6 +184,17 @@ func (w *mongoWrapper) BulkInsert(ctx context.Context, coll *mongo.Collection, d
 	return w.bulkInsert(ctx, coll, docs, opts)
 }
 
+// AggregateStream 流式处理聚合结果。
+func (w *mongoWrapper) AggregateStream(ctx context.Context, coll *mongo.Collection, pipeline any, handler AggregateHandler, opts AggregateOptions) error {
+	if ctx == nil {
+		return ErrNilContext
+	}
+	if w.closed.Load() {
+		return ErrClosed
+	}
+	return w.aggregateStream(ctx, coll, pipeline, handler, opts)
+}
+
 // =============================================================================
 // 慢查询检测
 // =============================================================================
@@ -273,6 +284,11 @@ func (w *mongoWrapper) findPageInternal(ctx context.Context, coll collectionOper
 		filter = bson.D{}
 	}
 
+	// 读写分离：在操作层面设置读偏好，不修改调用方的 Collection
+	if w.options.ReadFromSecondary {
+		coll = coll.Clone(options.Collection().SetReadPreference(readpref.SecondaryPreferred()))
+	}
+
 	// 当调用方未设置 deadline 且配置了 QueryTimeout 时，添加超时兜底
 	var cancel context.CancelFunc
 	ctx, cancel = applyTimeout(ctx, w.options.QueryTimeout)
@@ -383,12 +399,7 @@ func (w *mongoWrapper) bulkInsertInternal(ctx context.Context, coll collectionOp
 	ctx, cancel = applyTimeout(ctx, w.options.WriteTimeout)
 	defer cancel()
 
-	batchSize := opts.BatchSize
-	if batchSize < 1 {
-		batchSize = defaultBatchSize
-	} else if batchSize > maxBatchSize {
-		batchSize = maxBatchSize
-	}
+	batchSize := normalizeBatchSize(opts.BatchSize)
 
 	info := buildSlowQueryInfoFromOps(coll, "bulkInsert", nil)
 
@@ -426,12 +437,27 @@ func (w *mongoWrapper) bulkInsertInternal(ctx context.Context, coll collectionOp
 		resultErr = errors.Join(errs...)
 	}
 
+	// 有文档写入时使该集合的查询缓存失效（部分失败也可能已写入部分文档）
+	if insertedCount > 0 && w.cacheEnabled() {
+		if invErr := w.invalidateCache(ctx, coll); invErr != nil {
+			resultErr = errors.Join(resultErr, invErr)
+		}
+	}
+
 	return &BulkResult{
 		InsertedCount: insertedCount,
 		Errors:        errs,
 	}, resultErr
 }
 
+// normalizeBatchSize 返回有效的每批文档数：未设置时使用默认值，超过上限时截断。
+func normalizeBatchSize(n int) int {
+	if n < 1 {
+		return defaultBatchSize
+	}
+	return min(n, maxBatchSize)
+}
+
 // executeBatches 执行分批插入操作。
 func (w *mongoWrapper) executeBatches(ctx context.Context, coll collectionOperations, docs []any, batchSize int, ordered bool) (int64, []error) {
 	var insertedCount int64
@@ -501,3 +527,106 @@ func (w *mongoWrapper) executeSingleBatch(ctx context.Context, coll collectionOp
 
 	return insertedCount, wrappedErr, false
 }
+
+// cursorNotFoundCode MongoDB CursorNotFound 错误码，cursor 被服务端回收（通常为空闲超时）时返回。
+const cursorNotFoundCode = 43
+
+// aggregateStream 聚合流式处理实现。
+func (w *mongoWrapper) aggregateStream(ctx context.Context, coll *mongo.Collection, pipeline any, handler AggregateHandler, opts AggregateOptions) error {
+	if coll == nil {
+		return ErrNilCollection
+	}
+	if handler == nil {
+		return ErrNilHandler
+	}
+
+	// 读写分离：聚合为只读操作，与 FindPage 一致
+	if w.options.ReadFromSecondary {
+		coll = coll.Clone(options.Collection().SetReadPreference(readpref.SecondaryPreferred()))
+	}
+
+	return w.aggregateStreamInternal(ctx, adaptCollection(coll), pipeline, handler, opts)
+}
+
+// aggregateStreamInternal 聚合流式处理内部实现，使用接口便于测试。
+func (w *mongoWrapper) aggregateStreamInternal(ctx context.Context, coll collectionOperations, pipeline any, handler AggregateHandler, opts AggregateOptions) (err error) {
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	// 当调用方未设置 deadline 且配置了 QueryTimeout 时，添加超时兜底
+	var cancel context.CancelFunc
+	ctx, cancel = applyTimeout(ctx, w.options.QueryTimeout)
+	defer cancel()
+
+	info := buildSlowQueryInfoFromOps(coll, "aggregateStream", pipeline)
+
+	var processed int64
+	start := time.Now()
+	ctx, span := xmetrics.Start(ctx, w.options.Observer, xmetrics.SpanOptions{
+		Component: mongoComponent,
+		Operation: "aggregate_stream",
+		Kind:      xmetrics.KindClient,
+		Attrs: []xmetrics.Attr{
+			xmetrics.String("db.system", "mongodb"),
+			xmetrics.String("db.name", info.Database),
+			xmetrics.String("db.collection", info.Collection),
+		},
+	})
+	defer func() {
+		// 设计决策: 慢查询耗时包含 handler 执行时间。流式处理中 getMore 与 handler 交替进行，
+		// 无法剥离；需要区分时可在 handler 内自行计时。
+		info.Duration = storageopt.MeasureOperation(start)
+		slow := w.maybeSlowQuery(ctx, info)
+
+		attrs := []xmetrics.Attr{xmetrics.Int64("xmongo.docs", processed)}
+		if slow {
+			attrs = append(attrs,
+				xmetrics.Bool("slow", true),
+				xmetrics.Int64("slow_threshold_ms", w.options.SlowQueryThreshold.Milliseconds()),
+			)
+		}
+		span.End(xmetrics.Result{Err: err, Attrs: attrs})
+	}()
+
+	cursor, err := coll.Aggregate(ctx, pipeline, buildAggregateOptions(opts))
+	if err != nil {
+		return fmt.Errorf("xmongo aggregate_stream %s.%s: %w", info.Database, info.Collection, err)
+	}
+	// 设计决策: 使用独立 context 关闭 cursor。ctx 超时或取消后仍需通知服务端释放游标，
+	// 否则游标会占用服务端资源直到空闲超时。
+	//nolint:errcheck,gosec // cursor.Close 是清理操作，结果已交给 handler 处理
+	defer func() { cursor.Close(context.WithoutCancel(ctx)) }()
+
+	for cursor.Next(ctx) {
+		if err = handler(ctx, cursor.Current); err != nil {
+			return fmt.Errorf("xmongo aggregate_stream handler after %d docs: %w", processed, err)
+		}
+		processed++
+	}
+	if err = cursor.Err(); err != nil {
+		if isCursorNotFound(err) {
+			err = fmt.Errorf("%w: %w", ErrCursorTimeout, err)
+		}
+		return fmt.Errorf("xmongo aggregate_stream %s.%s after %d docs: %w", info.Database, info.Collection, processed, err)
+	}
+	return nil
+}
+
+// buildAggregateOptions 构建聚合流式处理的 AggregateOptions。
+func buildAggregateOptions(opts AggregateOptions) *options.AggregateOptionsBuilder {
+	aggOpts := options.Aggregate()
+	if opts.BatchSize > 0 {
+		aggOpts = aggOpts.SetBatchSize(opts.BatchSize)
+	}
+	if opts.AllowDiskUse {
+		aggOpts = aggOpts.SetAllowDiskUse(true)
+	}
+	return aggOpts
+}
+
+// isCursorNotFound 判断错误是否为服务端 cursor 已被回收。
+func isCursorNotFound(err error) bool {
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorCode(cursorNotFoundCode)
+}
@@ -35,6 +35,18 @@ func (b *benchCollectionOps) InsertMany(_ context.Context, documents []any, _ ..
 	return &mongo.InsertManyResult{InsertedIDs: ids}, nil
 }
 
+func (b *benchCollectionOps) UpdateOne(_ context.Context, _ any, _ any, _ ...options.Lister[options.UpdateOneOptions]) (*mongo.UpdateResult, error) {
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+func (b *benchCollectionOps) BulkWrite(_ context.Context, models []mongo.WriteModel, _ ...options.Lister[options.BulkWriteOptions]) (*mongo.BulkWriteResult, error) {
+	return &mongo.BulkWriteResult{MatchedCount: int64(len(models)), ModifiedCount: int64(len(models))}, nil
+}
+
+func (b *benchCollectionOps) Aggregate(_ context.Context, _ any, _ ...options.Lister[options.AggregateOptions]) (*mongo.Cursor, error) {
+	return mongo.NewCursorFromDocuments(b.docs, nil, nil)
+}
+
 func (b *benchCollectionOps) Database() *mongo.Database {
 	return nil
 }
@@ -43,6 +55,10 @@ func (b *benchCollectionOps) Name() string {
 	return b.collName
 }
 
+func (b *benchCollectionOps) Clone(_ ...options.Lister[options.CollectionOptions]) collectionOperations {
+	return b
+}
+
 func BenchmarkFindPageInternal(b *testing.B) {
 	docs := []any{
 		bson.M{"id": 1, "name": "a"},
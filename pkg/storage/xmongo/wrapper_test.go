@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 // =============================================================================
@@ -1001,6 +1002,34 @@ func TestWrapper_FindPageInternal_Success(t *testing.T) {
 	assert.Len(t, result.Data, 2)
 }
 
+func TestWrapper_FindPageInternal_ReadPreference(t *testing.T) {
+	t.Run("ReadFromSecondary 使用 SecondaryPreferred", func(t *testing.T) {
+		mock := &cursorCollectionOps{docs: []any{bson.M{"_id": "1"}}, count: 1}
+		opts := defaultOptions()
+		opts.ReadFromSecondary = true
+		w := &mongoWrapper{options: opts}
+
+		_, err := w.findPageInternal(context.Background(), mock, nil, PageOptions{Page: 1, PageSize: 10})
+		require.NoError(t, err)
+
+		// 查询在副本上执行，调用方的集合不受影响
+		require.Len(t, mock.clones, 1)
+		require.NotNil(t, mock.clones[0].findReadPref)
+		assert.Equal(t, readpref.SecondaryPreferredMode, mock.clones[0].findReadPref.Mode())
+		assert.Nil(t, mock.readPref)
+	})
+
+	t.Run("默认不修改读偏好", func(t *testing.T) {
+		mock := &cursorCollectionOps{docs: []any{bson.M{"_id": "1"}}, count: 1}
+		w := &mongoWrapper{options: defaultOptions()}
+
+		_, err := w.findPageInternal(context.Background(), mock, nil, PageOptions{Page: 1, PageSize: 10})
+		require.NoError(t, err)
+		assert.Empty(t, mock.clones)
+		assert.Nil(t, mock.findReadPref)
+	})
+}
+
 func TestWrapper_FindPageInternal_WithSlowQuery(t *testing.T) {
 	docs := []any{bson.M{"_id": "1"}}
 	mock := &cursorCollectionOps{
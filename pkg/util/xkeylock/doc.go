@@ -30,4 +30,7 @@
 //   - 泄漏检测：若调用方获取 Handle 后遗忘 Unlock，lockEntry 将静默驻留。
 //     建议通过 Len() 监控活跃 key 数——若持续单调递增，说明存在 Handle
 //     未释放的编程错误。配合 WithMaxKeys(n) 可设置硬上限防止无限膨胀
+//   - 持有者追踪：Holders() 按获取时间列出持有中的锁，WithHolderInfo 可从
+//     Acquire 的 ctx 提取 trace_id、调用方等信息一并展示，用于定位泄漏的 Handle；
+//     只保存提取出的字符串，Unlock 时清除
 package xkeylock
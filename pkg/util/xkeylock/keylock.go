@@ -3,8 +3,22 @@ package xkeylock
 import (
 	"context"
 	"io"
+	"time"
 )
 
+// HolderInfo 描述一次锁获取的持有者，用于调试和泄漏排查。
+type HolderInfo struct {
+	// Key 锁的 key。
+	Key string
+
+	// Info 由 [WithHolderInfo] 从 Acquire 的 ctx 提取的持有者描述（如 trace_id、调用方）。
+	// 未配置 WithHolderInfo 或通过 TryAcquire 获取时为空。
+	Info string
+
+	// AcquiredAt 获取锁的时间。
+	AcquiredAt time.Time
+}
+
 // Handle 表示一次成功的锁获取。
 // Unlock 是幂等的：第一次调用释放锁并返回 nil，后续调用返回 [ErrLockNotHeld]。
 type Handle interface {
@@ -15,6 +29,10 @@ type Handle interface {
 	// Key 返回锁的 key。
 	// 即使在 Unlock 之后调用，Key 仍返回原始 key 值。
 	Key() string
+
+	// Holder 返回获取锁时记录的持有者信息。
+	// 与 Key 相同，Unlock 之后仍返回原始值。
+	Holder() HolderInfo
 }
 
 // Locker 提供基于 key 的进程内互斥锁。
@@ -63,6 +81,11 @@ type Locker interface {
 	// 监控/指标采集场景推荐使用 Len（单次原子读取，无锁开销）。
 	// Close 后仍可安全调用，返回值随已持有 Handle 的释放逐渐归零。
 	Keys() []string
+
+	// Holders 返回当前持有中的锁（不含等待者），按获取时间升序排列，仅用于调试。
+	// 持有时间异常长的条目通常是遗忘 Unlock 的 Handle，配合 [WithHolderInfo]
+	// 可定位是哪个请求持有该锁。返回值是快照，不保证跨分片原子性。
+	Holders() []HolderInfo
 }
 
 // New 创建一个新的 Locker 实例。
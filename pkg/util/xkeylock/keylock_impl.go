@@ -1,10 +1,13 @@
 package xkeylock
 
 import (
+	"cmp"
 	"context"
 	"hash/maphash"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
@@ -59,6 +62,8 @@ type lockEntry struct {
 	// refcnt 跟踪引用此条目的 goroutine 数量（持有者 + 等待者）。
 	// 归零时条目从 map 中删除。
 	refcnt atomic.Int32
+	// holder 当前持有者，获取成功后设置，Unlock 时先于释放 ch 清除，供 Holders 读取。
+	holder atomic.Pointer[handle]
 }
 
 // handle 实现 Handle 接口。
@@ -67,6 +72,9 @@ type handle struct {
 	key   string
 	entry *lockEntry
 	done  atomic.Bool
+	// info 和 acquiredAt 创建后不再修改，Holders 可无锁读取。
+	info       string
+	acquiredAt time.Time
 }
 
 func newKeyLockImpl(opts options) *keyLockImpl {
@@ -132,6 +140,13 @@ func (kl *keyLockImpl) releaseRef(key string, entry *lockEntry) {
 	}
 }
 
+// newHandle 创建 Handle 并登记为 entry 的当前持有者（调用方必须已持有锁）。
+func (kl *keyLockImpl) newHandle(key string, entry *lockEntry, info string) *handle {
+	h := &handle{kl: kl, key: key, entry: entry, info: info, acquiredAt: time.Now()}
+	entry.holder.Store(h)
+	return h
+}
+
 func (kl *keyLockImpl) Acquire(ctx context.Context, key string) (Handle, error) {
 	if ctx == nil {
 		return nil, ErrNilContext
@@ -162,7 +177,11 @@ func (kl *keyLockImpl) Acquire(ctx context.Context, key string) (Handle, error)
 			kl.releaseRef(key, entry)
 			return nil, ErrClosed
 		}
-		return &handle{kl: kl, key: key, entry: entry}, nil
+		var info string
+		if kl.opts.holderInfo != nil {
+			info = kl.opts.holderInfo(ctx)
+		}
+		return kl.newHandle(key, entry, info), nil
 	case <-ctx.Done(): // 超时或取消
 		kl.releaseRef(key, entry)
 		return nil, ctx.Err()
@@ -193,7 +212,7 @@ func (kl *keyLockImpl) TryAcquire(key string) (Handle, error) {
 			kl.releaseRef(key, entry)
 			return nil, ErrClosed
 		}
-		return &handle{kl: kl, key: key, entry: entry}, nil
+		return kl.newHandle(key, entry, ""), nil
 	default: // 锁被占用
 		kl.releaseRef(key, entry)
 		if kl.testHookAfterDefaultReleaseRef != nil {
@@ -227,6 +246,24 @@ func (kl *keyLockImpl) Keys() []string {
 	return keys
 }
 
+func (kl *keyLockImpl) Holders() []HolderInfo {
+	var holders []HolderInfo
+	for i := range kl.shards {
+		s := &kl.shards[i]
+		s.mu.Lock()
+		for _, e := range s.entries {
+			if h := e.holder.Load(); h != nil {
+				holders = append(holders, h.Holder())
+			}
+		}
+		s.mu.Unlock()
+	}
+	slices.SortFunc(holders, func(a, b HolderInfo) int {
+		return cmp.Or(a.AcquiredAt.Compare(b.AcquiredAt), cmp.Compare(a.Key, b.Key))
+	})
+	return holders
+}
+
 func (kl *keyLockImpl) Close() error {
 	if !kl.closed.CompareAndSwap(false, true) {
 		return ErrClosed
@@ -241,6 +278,8 @@ func (h *handle) Unlock() error {
 	if !h.done.CompareAndSwap(false, true) {
 		return ErrLockNotHeld
 	}
+	// 先清除持有者再释放 ch，避免覆盖下一个持有者的登记。
+	h.entry.holder.CompareAndSwap(h, nil)
 	<-h.entry.ch
 	h.kl.releaseRef(h.key, h.entry)
 	// 释放引用，防止长期持有 Handle 时阻止 GC 回收 keyLockImpl。
@@ -253,6 +292,10 @@ func (h *handle) Key() string {
 	return h.key
 }
 
+func (h *handle) Holder() HolderInfo {
+	return HolderInfo{Key: h.key, Info: h.info, AcquiredAt: h.acquiredAt}
+}
+
 // 编译期接口检查。
 var (
 	_ Locker = (*keyLockImpl)(nil)
@@ -484,6 +484,74 @@ func TestLen(t *testing.T) {
 	assert.Equal(t, 0, kl.Len())
 }
 
+type holderCtxKey struct{}
+
+func TestHolders(t *testing.T) {
+	kl := newForTest(t, WithHolderInfo(func(ctx context.Context) string {
+		v, _ := ctx.Value(holderCtxKey{}).(string)
+		return v
+	}))
+	defer func() { require.NoError(t, kl.Close()) }()
+
+	assert.Empty(t, kl.Holders())
+
+	ctx := context.WithValue(context.Background(), holderCtxKey{}, "trace_id=t1")
+	h1, err := kl.Acquire(ctx, "a")
+	require.NoError(t, err)
+	h2, err := kl.TryAcquire("b")
+	require.NoError(t, err)
+
+	assert.Equal(t, "a", h1.Holder().Key)
+	assert.Equal(t, "trace_id=t1", h1.Holder().Info)
+	assert.False(t, h1.Holder().AcquiredAt.IsZero())
+	assert.Empty(t, h2.Holder().Info, "TryAcquire 没有 ctx，不提取持有者信息")
+
+	holders := kl.Holders()
+	require.Len(t, holders, 2)
+	assert.Equal(t, h1.Holder(), holders[0], "按获取时间升序")
+	assert.Equal(t, h2.Holder(), holders[1])
+
+	// 等待者不出现在 Holders 中
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = kl.Acquire(waitCtx, "a")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, kl.Holders(), 2)
+
+	// Unlock 清除持有者记录，Handle 仍保留原始信息
+	require.NoError(t, h1.Unlock())
+	assert.Equal(t, []HolderInfo{h2.Holder()}, kl.Holders())
+	assert.Equal(t, "trace_id=t1", h1.Holder().Info)
+
+	require.NoError(t, h2.Unlock())
+	assert.Empty(t, kl.Holders())
+}
+
+func TestHoldersNextHolder(t *testing.T) {
+	kl := newForTest(t, WithHolderInfo(func(ctx context.Context) string {
+		v, _ := ctx.Value(holderCtxKey{}).(string)
+		return v
+	}))
+	defer func() { require.NoError(t, kl.Close()) }()
+
+	h1, err := kl.Acquire(context.WithValue(context.Background(), holderCtxKey{}, "first"), "k")
+	require.NoError(t, err)
+
+	acquired := make(chan Handle, 1)
+	go func() {
+		h, err := kl.Acquire(context.WithValue(context.Background(), holderCtxKey{}, "second"), "k")
+		assert.NoError(t, err)
+		acquired <- h
+	}()
+
+	require.NoError(t, h1.Unlock())
+	h2 := <-acquired
+	holders := kl.Holders()
+	require.Len(t, holders, 1)
+	assert.Equal(t, "second", holders[0].Info)
+	require.NoError(t, h2.Unlock())
+}
+
 func TestCloseWakesWaiters(t *testing.T) {
 	kl := newForTest(t)
 
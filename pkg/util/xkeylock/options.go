@@ -1,6 +1,9 @@
 package xkeylock
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 const (
 	defaultShardCount = 32
@@ -14,6 +17,7 @@ type options struct {
 	maxKeys    int
 	shardCount int
 	shardMask  uint64 // validate() 计算，供 getShard 使用
+	holderInfo func(ctx context.Context) string
 }
 
 func defaultOptions() options {
@@ -45,6 +49,25 @@ func WithShardCount(n int) Option {
 	}
 }
 
+// WithHolderInfo 设置持有者信息提取函数。
+// Acquire 成功时以调用方 ctx 调用 fn，结果记录在 [HolderInfo.Info] 中，
+// 通过 Handle.Holder 和 Locker.Holders 展示，帮助定位"是哪个请求持有这个锁"。
+// TryAcquire 没有 ctx，不调用 fn。fn 为 nil 时不提取（默认）。
+//
+// 设计决策: 只保存 fn 返回的字符串而非 ctx 本身，避免锁持有期间 ctx 中的值无法回收；
+// Unlock 时记录随之清除。fn 在每次 Acquire 成功时同步调用，应快速返回。
+//
+// 示例:
+//
+//	kl, err := xkeylock.New(xkeylock.WithHolderInfo(func(ctx context.Context) string {
+//	    return "trace_id=" + xctx.TraceID(ctx)
+//	}))
+func WithHolderInfo(fn func(ctx context.Context) string) Option {
+	return func(o *options) {
+		o.holderInfo = fn
+	}
+}
+
 func (o *options) validate() error {
 	sc := o.shardCount
 	if sc <= 0 || sc > maxShardCount || sc&(sc-1) != 0 {
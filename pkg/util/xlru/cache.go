@@ -74,12 +74,27 @@ func WithOnEvicted[K comparable, V any](fn func(key K, value V)) Option[K, V] {
 	}
 }
 
+// entry 是缓存内部存储的条目，记录过期时间以支持 Dump 持久化剩余 TTL。
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time // 零值表示不过期
+	// restored 表示条目由 Load 恢复，剩余 TTL 可能短于底层 LRU 的 TTL，
+	// 读取时需要额外检查 expiresAt。Set 写入的条目由底层 LRU 负责过期。
+	restored bool
+}
+
+// expired 报告由 Load 恢复的条目是否已过期。
+func (e entry[V]) expired(now time.Time) bool {
+	return e.restored && !now.Before(e.expiresAt)
+}
+
 // Cache 是带 TTL 的 LRU 缓存。
 // 必须通过 [New] 函数创建，零值不可用（方法调用会 panic）。
 // 所有方法都是并发安全的。
 // 调用 Close 后，所有读操作返回零值/false，写操作静默忽略。
 type Cache[K comparable, V any] struct {
-	lru       *expirable.LRU[K, V]
+	lru       *expirable.LRU[K, entry[V]]
+	ttl       time.Duration
 	closed    atomic.Bool
 	closeOnce sync.Once
 }
@@ -103,20 +118,49 @@ func New[K comparable, V any](cfg Config, opts ...Option[K, V]) (*Cache[K, V], e
 	}
 
 	// 创建 expirable LRU
-	lru := expirable.NewLRU(cfg.Size, o.onEvicted, cfg.TTL)
+	var onEvicted expirable.EvictCallback[K, entry[V]]
+	if o.onEvicted != nil {
+		onEvicted = func(key K, e entry[V]) {
+			o.onEvicted(key, e.value)
+		}
+	}
+	lru := expirable.NewLRU(cfg.Size, onEvicted, cfg.TTL)
 
 	return &Cache[K, V]{
 		lru: lru,
+		ttl: cfg.TTL,
 	}, nil
 }
 
+// newEntry 创建 Set 写入的条目。
+func (c *Cache[K, V]) newEntry(value V) entry[V] {
+	e := entry[V]{value: value}
+	if c.ttl > 0 {
+		e.expiresAt = time.Now().Add(c.ttl)
+	}
+	return e
+}
+
+// peekEntry 获取未过期的条目，不更新 LRU 顺序。
+func (c *Cache[K, V]) peekEntry(key K) (entry[V], bool) {
+	e, ok := c.lru.Peek(key)
+	if !ok || e.expired(time.Now()) {
+		return entry[V]{}, false
+	}
+	return e, true
+}
+
 // Get 获取缓存值。
 // 如果键不存在、已过期或缓存已关闭，返回零值和 false。
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	if c.closed.Load() {
 		return value, false
 	}
-	return c.lru.Get(key)
+	e, ok := c.lru.Get(key)
+	if !ok || e.expired(time.Now()) {
+		return value, false
+	}
+	return e.value, true
 }
 
 // Set 设置缓存值。返回值表示是否触发了 LRU 淘汰（eviction），而非操作是否成功。
@@ -131,7 +175,7 @@ func (c *Cache[K, V]) Set(key K, value V) bool {
 	if c.closed.Load() {
 		return false
 	}
-	return c.lru.Add(key, value)
+	return c.lru.Add(key, c.newEntry(value))
 }
 
 // Delete 删除缓存条目。
@@ -175,7 +219,7 @@ func (c *Cache[K, V]) Contains(key K) bool {
 	if c.closed.Load() {
 		return false
 	}
-	_, ok := c.lru.Peek(key)
+	_, ok := c.peekEntry(key)
 	return ok
 }
 
@@ -186,7 +230,8 @@ func (c *Cache[K, V]) Peek(key K) (value V, ok bool) {
 	if c.closed.Load() {
 		return value, false
 	}
-	return c.lru.Peek(key)
+	e, ok := c.peekEntry(key)
+	return e.value, ok
 }
 
 // Keys 返回所有键的切片，按从最旧到最新的顺序排列。
@@ -20,6 +20,23 @@
 // 可选配置通过 Option 函数提供：
 //   - WithOnEvicted：设置条目被淘汰时的回调函数
 //
+// # 持久化与恢复
+//
+// Dump 将未过期的条目及其剩余 TTL 写入 io.Writer，Load 读回并写入缓存，
+// 用于进程重启时恢复热数据、缩短冷缓存期：
+//
+//	// 退出前
+//	out, _ := os.Create("cache.dump")
+//	err := cache.Dump(out)
+//
+//	// 启动后
+//	in, _ := os.Open("cache.dump")
+//	n, err := cache.Load(in)
+//
+// 数据使用 encoding/gob 编码，要求键和值可被 gob 序列化（导出字段；接口值需 gob.Register）。
+// 剩余 TTL 会扣除导出到加载之间的停机时间，并按当前 Config.TTL 截断。
+// 数据头携带格式版本，旧版本进程读取新格式数据时返回 ErrUnsupportedDumpVersion。
+//
 // # 使用场景
 //
 // xlru 适合以下场景：
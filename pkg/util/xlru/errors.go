@@ -14,4 +14,13 @@ var (
 
 	// ErrTTLTooSmall 表示正 TTL 值低于最小阈值 (100ns)。
 	ErrTTLTooSmall = errors.New("xlru: positive TTL must be at least 100ns")
+
+	// ErrClosed 表示缓存已关闭。
+	ErrClosed = errors.New("xlru: cache is closed")
+
+	// ErrInvalidDump 表示 Load 读取的数据不是有效的 Dump 输出。
+	ErrInvalidDump = errors.New("xlru: invalid dump data")
+
+	// ErrUnsupportedDumpVersion 表示 Dump 数据版本高于当前实现支持的版本。
+	ErrUnsupportedDumpVersion = errors.New("xlru: unsupported dump version")
 )
@@ -0,0 +1,135 @@
+package xlru
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"time"
+)
+
+// dumpMagic 标识 xlru 导出数据，用于快速拒绝无关输入。
+const dumpMagic = "xlru"
+
+// dumpVersion 当前导出格式版本。
+//
+// 设计决策: 格式基于 encoding/gob，字段按名称匹配，新增可选字段无需提升版本；
+// 仅在字段语义变化时提升版本。Load 接受不高于当前版本的数据，
+// 旧版本进程读取新版本数据时返回 ErrUnsupportedDumpVersion 而非误读。
+const dumpVersion = 1
+
+// dumpHeader 导出数据头。
+type dumpHeader struct {
+	Magic    string
+	Version  int
+	DumpedAt int64 // 导出时刻（Unix 纳秒），Load 据此扣除停机期间流逝的时间
+}
+
+// dumpEntry 导出的单个条目。
+type dumpEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration // 导出时的剩余 TTL，0 表示不过期
+}
+
+// Dump 将未过期的缓存条目序列化写入 w，用于进程重启时通过 Load 恢复热数据。
+//
+// 条目按从最旧到最新的顺序写入，同时记录每个条目的剩余 TTL。
+// 键和值使用 encoding/gob 编码：值类型的导出字段才会被序列化，
+// 接口类型的值需要预先调用 gob.Register 注册具体类型。
+//
+// Dump 不更新 LRU 顺序。导出期间的并发写入可能部分可见（非快照语义）。
+// 如果缓存已关闭，返回 ErrClosed。
+func (c *Cache[K, V]) Dump(w io.Writer) error {
+	if c.closed.Load() {
+		return ErrClosed
+	}
+
+	now := time.Now()
+	keys := c.lru.Keys()
+	entries := make([]dumpEntry[K, V], 0, len(keys))
+	for _, key := range keys {
+		e, ok := c.lru.Peek(key)
+		if !ok || e.expired(now) {
+			continue
+		}
+		var ttl time.Duration
+		if !e.expiresAt.IsZero() {
+			if ttl = e.expiresAt.Sub(now); ttl <= 0 {
+				continue
+			}
+		}
+		entries = append(entries, dumpEntry[K, V]{Key: key, Value: e.value, TTL: ttl})
+	}
+
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(dumpHeader{Magic: dumpMagic, Version: dumpVersion, DumpedAt: now.UnixNano()}); err != nil {
+		return fmt.Errorf("xlru: encode dump header: %w", err)
+	}
+	if err := enc.Encode(entries); err != nil {
+		return fmt.Errorf("xlru: encode dump entries: %w", err)
+	}
+	return nil
+}
+
+// Load 从 r 读取 Dump 导出的数据并写入缓存，返回恢复的条目数。
+//
+// TTL 处理：
+//   - 剩余 TTL 扣除导出到加载之间流逝的时间，已过期的条目被跳过
+//   - 剩余 TTL 超过当前 Config.TTL 时按 Config.TTL 截断
+//   - 导出时不过期的条目按当前 Config.TTL 重新计算过期时间
+//
+// Load 合并写入而非替换：同名键被覆盖，条目数超过 Size 时按 LRU 淘汰最旧条目
+// （触发 OnEvicted 回调）。数据格式无效时返回 ErrInvalidDump，
+// 版本高于当前实现时返回 ErrUnsupportedDumpVersion，两种情况下缓存均保持不变。
+// 如果缓存已关闭，返回 ErrClosed。
+func (c *Cache[K, V]) Load(r io.Reader) (int, error) {
+	if c.closed.Load() {
+		return 0, ErrClosed
+	}
+
+	dec := gob.NewDecoder(r)
+	var h dumpHeader
+	if err := dec.Decode(&h); err != nil {
+		return 0, fmt.Errorf("%w: decode header: %w", ErrInvalidDump, err)
+	}
+	if h.Magic != dumpMagic {
+		return 0, fmt.Errorf("%w: unexpected magic %q", ErrInvalidDump, h.Magic)
+	}
+	if h.Version < 1 || h.Version > dumpVersion {
+		return 0, fmt.Errorf("%w: got %d, support <= %d", ErrUnsupportedDumpVersion, h.Version, dumpVersion)
+	}
+
+	var entries []dumpEntry[K, V]
+	if err := dec.Decode(&entries); err != nil {
+		return 0, fmt.Errorf("%w: decode entries: %w", ErrInvalidDump, err)
+	}
+
+	now := time.Now()
+	elapsed := max(0, now.Sub(time.Unix(0, h.DumpedAt)))
+	restored := 0
+	for _, de := range entries {
+		e, ok := c.restoreEntry(de, elapsed, now)
+		if !ok {
+			continue
+		}
+		c.lru.Add(de.Key, e)
+		restored++
+	}
+	return restored, nil
+}
+
+// restoreEntry 根据导出的剩余 TTL 构造条目，已过期时返回 false。
+func (c *Cache[K, V]) restoreEntry(de dumpEntry[K, V], elapsed time.Duration, now time.Time) (entry[V], bool) {
+	if de.TTL <= 0 {
+		return c.newEntry(de.Value), true
+	}
+	remaining := de.TTL - elapsed
+	if remaining <= 0 {
+		return entry[V]{}, false
+	}
+	// 剩余时间不短于底层 LRU 的 TTL 时交由底层过期，与 Set 写入的条目一致
+	if c.ttl > 0 && remaining >= c.ttl {
+		return c.newEntry(de.Value), true
+	}
+	return entry[V]{value: de.Value, expiresAt: now.Add(remaining), restored: true}, true
+}
@@ -0,0 +1,236 @@
+package xlru
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type persistValue struct {
+	Name  string
+	Count int
+}
+
+func newPersistCache[V any](t *testing.T, cfg Config) *Cache[string, V] {
+	t.Helper()
+	c, err := New[string, V](cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c
+}
+
+func TestDumpLoad_RoundTrip(t *testing.T) {
+	src := newPersistCache[persistValue](t, Config{Size: 10, TTL: time.Hour})
+	src.Set("a", persistValue{Name: "a", Count: 1})
+	src.Set("b", persistValue{Name: "b", Count: 2})
+	src.Set("c", persistValue{Name: "c", Count: 3})
+	src.Get("a") // a 变为最新
+
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	dst := newPersistCache[persistValue](t, Config{Size: 10, TTL: time.Hour})
+	n, err := dst.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Load restored %d entries, want 3", n)
+	}
+	// LRU 顺序保持：从最旧到最新
+	if keys := dst.Keys(); !slices.Equal(keys, []string{"b", "c", "a"}) {
+		t.Errorf("Keys() = %v, want [b c a]", keys)
+	}
+	if v, ok := dst.Get("b"); !ok || v.Count != 2 {
+		t.Errorf("Get(b) = %v, %v; want Count=2", v, ok)
+	}
+}
+
+func TestDumpLoad_RemainingTTL(t *testing.T) {
+	src := newPersistCache[int](t, Config{Size: 10, TTL: 200 * time.Millisecond})
+	src.Set("short", 1)
+
+	time.Sleep(120 * time.Millisecond)
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	// 目标缓存 TTL 更长，恢复的条目仍按剩余时间（约 80ms）过期
+	dst := newPersistCache[int](t, Config{Size: 10, TTL: time.Hour})
+	if _, err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, ok := dst.Get("short"); !ok {
+		t.Fatal("restored entry should be present before remaining TTL elapses")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if _, ok := dst.Get("short"); ok {
+		t.Error("restored entry should expire after remaining TTL")
+	}
+	if dst.Contains("short") {
+		t.Error("Contains should honor restored TTL")
+	}
+	if _, ok := dst.Peek("short"); ok {
+		t.Error("Peek should honor restored TTL")
+	}
+}
+
+func TestDumpLoad_TTLCappedByConfig(t *testing.T) {
+	src := newPersistCache[int](t, Config{Size: 10, TTL: time.Hour})
+	src.Set("k", 1)
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	dst := newPersistCache[int](t, Config{Size: 10, TTL: 50 * time.Millisecond})
+	if _, err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if _, ok := dst.Get("k"); ok {
+		t.Error("restored entry should be capped by the loading cache's TTL")
+	}
+}
+
+func TestDumpLoad_SkipsExpiredDuringDowntime(t *testing.T) {
+	src := newPersistCache[int](t, Config{Size: 10, TTL: 50 * time.Millisecond})
+	src.Set("k", 1)
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	dst := newPersistCache[int](t, Config{Size: 10, TTL: time.Hour})
+	n, err := dst.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if n != 0 || dst.Len() != 0 {
+		t.Errorf("Load restored %d entries (Len=%d), want 0", n, dst.Len())
+	}
+}
+
+func TestDumpLoad_NoExpiry(t *testing.T) {
+	src := newPersistCache[int](t, Config{Size: 10})
+	src.Set("k", 1)
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	dst := newPersistCache[int](t, Config{Size: 10})
+	if _, err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if v, ok := dst.Get("k"); !ok || v != 1 {
+		t.Errorf("Get(k) = %v, %v; want 1, true", v, ok)
+	}
+}
+
+func TestLoad_MergesAndEvicts(t *testing.T) {
+	src := newPersistCache[int](t, Config{Size: 10, TTL: time.Hour})
+	for i, k := range []string{"a", "b", "c"} {
+		src.Set(k, i)
+	}
+	var buf bytes.Buffer
+	if err := src.Dump(&buf); err != nil {
+		t.Fatalf("Dump failed: %v", err)
+	}
+
+	var evicted []string
+	dst, err := New[string, int](Config{Size: 2, TTL: time.Hour}, WithOnEvicted(func(k string, _ int) {
+		evicted = append(evicted, k)
+	}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer dst.Close()
+	dst.Set("a", 100)
+
+	if _, err := dst.Load(&buf); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if keys := dst.Keys(); !slices.Equal(keys, []string{"b", "c"}) {
+		t.Errorf("Keys() = %v, want [b c]", keys)
+	}
+	if !slices.Equal(evicted, []string{"a"}) {
+		t.Errorf("evicted = %v, want [a]", evicted)
+	}
+}
+
+func TestLoad_InvalidData(t *testing.T) {
+	encode := func(vals ...any) *bytes.Buffer {
+		var buf bytes.Buffer
+		enc := gob.NewEncoder(&buf)
+		for _, v := range vals {
+			if err := enc.Encode(v); err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+		}
+		return &buf
+	}
+
+	tests := []struct {
+		name    string
+		data    *bytes.Buffer
+		wantErr error
+	}{
+		{"empty", &bytes.Buffer{}, ErrInvalidDump},
+		{"garbage", bytes.NewBufferString("not a dump"), ErrInvalidDump},
+		{"wrong magic", encode(dumpHeader{Magic: "other", Version: 1}), ErrInvalidDump},
+		{"future version", encode(dumpHeader{Magic: dumpMagic, Version: dumpVersion + 1}), ErrUnsupportedDumpVersion},
+		{"missing entries", encode(dumpHeader{Magic: dumpMagic, Version: dumpVersion}), ErrInvalidDump},
+		{"mismatched value type", encode(
+			dumpHeader{Magic: dumpMagic, Version: dumpVersion},
+			[]dumpEntry[string, string]{{Key: "k", Value: "v"}},
+		), ErrInvalidDump},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newPersistCache[int](t, Config{Size: 10})
+			c.Set("keep", 1)
+			_, err := c.Load(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %v", err, tt.wantErr)
+			}
+			if v, ok := c.Get("keep"); !ok || v != 1 || c.Len() != 1 {
+				t.Error("cache should be unchanged after failed Load")
+			}
+		})
+	}
+}
+
+func TestDumpLoad_Closed(t *testing.T) {
+	c := newPersistCache[int](t, Config{Size: 10})
+	c.Close()
+
+	if err := c.Dump(&bytes.Buffer{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Dump() error = %v, want ErrClosed", err)
+	}
+	if _, err := c.Load(&bytes.Buffer{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Load() error = %v, want ErrClosed", err)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestDump_WriteError(t *testing.T) {
+	c := newPersistCache[int](t, Config{Size: 10})
+	c.Set("k", 1)
+	if err := c.Dump(failingWriter{}); err == nil {
+		t.Error("Dump should return writer error")
+	}
+}
@@ -0,0 +1,233 @@
+package xmac
+
+import (
+	"slices"
+	"sync"
+)
+
+// multicastBit 是 MAC 地址首字节最低位（I/G 位）在 48 位数值中的位置掩码。
+// 该位为 1 表示组播地址。
+const multicastBit = uint64(1) << 40
+
+// Allocator 从指定地址范围中分配 MAC 地址，维护已用/可用状态。
+//
+// 典型场景：虚拟化平台为虚拟网卡分配 MAC。Allocator 只分配可用于网卡的单播地址，
+// 自动跳过范围内的组播地址（首字节最低位为 1，包括广播）和全零地址。
+//
+// 分配策略为"下一个可用"（next-fit）：游标从上次分配的位置继续向后查找，
+// 到达范围末尾后回绕。相比总是从头分配最小空闲地址，刚释放的地址不会被立即复用，
+// 降低交换机 MAC 表与对端 ARP 缓存残留旧映射造成的冲突。
+//
+// Allocator 是并发安全的。状态仅保存在内存中，进程重启后需通过 [Allocator.Reserve]
+// 恢复已分配地址（如从数据库加载）。
+type Allocator struct {
+	from, to uint64 // 范围（包含两端）
+
+	mu     sync.Mutex
+	cursor uint64              // 下次查找的起点
+	used   map[uint64]struct{} // 已分配地址
+	total  uint64              // 范围内可分配地址总数
+}
+
+// AllocatorStats 地址池使用情况。
+type AllocatorStats struct {
+	// Total 范围内可分配的地址总数（已排除组播与全零地址）。
+	Total uint64
+	// Allocated 已分配数量。
+	Allocated uint64
+	// Available 剩余可分配数量。
+	Available uint64
+}
+
+// NewAllocator 创建覆盖 [from, to]（包含两端）的地址分配器。
+//
+// 如果 from > to，或范围内没有可分配的单播地址，返回 [ErrInvalidRange]。
+//
+// 示例：
+//
+//	// 本地管理地址段（首字节 0x02）用于虚拟网卡
+//	alloc, err := xmac.NewAllocator(
+//	    xmac.MustParse("02:00:00:00:00:01"),
+//	    xmac.MustParse("02:00:00:ff:ff:ff"),
+//	)
+//	addr, err := alloc.Allocate()
+//	defer alloc.Release(addr)
+func NewAllocator(from, to Addr) (*Allocator, error) {
+	lo, hi := addrToUint64(from), addrToUint64(to)
+	if lo > hi {
+		return nil, ErrInvalidRange
+	}
+	total := allocatableCount(lo, hi)
+	if total == 0 {
+		return nil, ErrInvalidRange
+	}
+	return &Allocator{
+		from:   lo,
+		to:     hi,
+		cursor: lo,
+		used:   make(map[uint64]struct{}),
+		total:  total,
+	}, nil
+}
+
+// Allocate 返回下一个可用地址并将其标记为已分配。
+// 地址池耗尽时返回 [ErrPoolExhausted]。
+func (a *Allocator) Allocate() (Addr, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if uint64(len(a.used)) >= a.total {
+		return Addr{}, ErrPoolExhausted
+	}
+
+	// 仍有空闲地址，因此最多扫描一圈必然命中
+	v := a.cursor
+	for {
+		v = a.skipUnallocatable(v)
+		if _, ok := a.used[v]; !ok {
+			break
+		}
+		v = a.advance(v)
+	}
+	a.used[v] = struct{}{}
+	a.cursor = a.advance(v)
+	return uint64ToAddr(v), nil
+}
+
+// Reserve 将指定地址标记为已分配，用于恢复持久化的分配状态或预留静态地址。
+//
+// 错误：
+//   - [ErrOutOfRange]: 地址不在范围内，或为组播/全零地址
+//   - [ErrAddrInUse]: 地址已被分配
+func (a *Allocator) Reserve(addr Addr) error {
+	v := addrToUint64(addr)
+	if !a.allocatable(v) {
+		return ErrOutOfRange
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.used[v]; ok {
+		return ErrAddrInUse
+	}
+	a.used[v] = struct{}{}
+	return nil
+}
+
+// Release 归还已分配的地址。
+//
+// 错误：
+//   - [ErrOutOfRange]: 地址不在范围内
+//   - [ErrNotAllocated]: 地址未被分配（重复释放）
+func (a *Allocator) Release(addr Addr) error {
+	v := addrToUint64(addr)
+	if !a.allocatable(v) {
+		return ErrOutOfRange
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.used[v]; !ok {
+		return ErrNotAllocated
+	}
+	delete(a.used, v)
+	return nil
+}
+
+// IsAllocated 报告 addr 是否已被分配。
+func (a *Allocator) IsAllocated(addr Addr) bool {
+	v := addrToUint64(addr)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.used[v]
+	return ok
+}
+
+// Allocated 返回所有已分配地址，按地址升序排列。
+func (a *Allocator) Allocated() []Addr {
+	a.mu.Lock()
+	vals := make([]uint64, 0, len(a.used))
+	for v := range a.used {
+		vals = append(vals, v)
+	}
+	a.mu.Unlock()
+
+	slices.Sort(vals)
+	addrs := make([]Addr, len(vals))
+	for i, v := range vals {
+		addrs[i] = uint64ToAddr(v)
+	}
+	return addrs
+}
+
+// Stats 返回地址池使用情况。
+func (a *Allocator) Stats() AllocatorStats {
+	a.mu.Lock()
+	allocated := uint64(len(a.used))
+	a.mu.Unlock()
+	return AllocatorStats{
+		Total:     a.total,
+		Allocated: allocated,
+		Available: a.total - allocated,
+	}
+}
+
+// allocatable 报告 v 是否在范围内且为可分配的单播非零地址。
+func (a *Allocator) allocatable(v uint64) bool {
+	return v >= a.from && v <= a.to && v&multicastBit == 0 && v != 0
+}
+
+// advance 返回 v 的下一个位置，越过范围末尾时回绕到起点。
+func (a *Allocator) advance(v uint64) uint64 {
+	if v >= a.to {
+		return a.from
+	}
+	return v + 1
+}
+
+// skipUnallocatable 从 v 开始返回第一个可分配地址（必要时回绕）。
+// 组播地址以 2^40 为单位成块出现，整块跳过而非逐个递增。
+// 调用方须保证范围内存在可分配地址。
+func (a *Allocator) skipUnallocatable(v uint64) uint64 {
+	for !a.allocatable(v) {
+		switch {
+		case v&multicastBit != 0:
+			// 跳到下一个 I/G 位为 0 的块起点
+			next := (v | (multicastBit - 1)) + 1
+			if next > a.to {
+				v = a.from
+			} else {
+				v = next
+			}
+		default:
+			v = a.advance(v)
+		}
+	}
+	return v
+}
+
+// allocatableCount 计算 [lo, hi] 中单播非零地址的数量。
+func allocatableCount(lo, hi uint64) uint64 {
+	n := unicastBelow(hi+1) - unicastBelow(lo)
+	if lo == 0 {
+		n-- // 排除全零地址
+	}
+	return n
+}
+
+// unicastBelow 计算 [0, n) 中 I/G 位为 0 的数值个数。
+// I/G 位（第 40 位）以 2^41 为周期，每个周期前半段为单播。
+func unicastBelow(n uint64) uint64 {
+	const period = multicastBit << 1
+	return (n/period)*multicastBit + min(n%period, multicastBit)
+}
+
+// uint64ToAddr 将 48 位数值转换为 MAC 地址，与 addrToUint64 互逆。
+func uint64ToAddr(v uint64) Addr {
+	return Addr{bytes: [6]byte{
+		byte(v >> 40), byte(v >> 32), byte(v >> 24),
+		byte(v >> 16), byte(v >> 8), byte(v),
+	}}
+}
@@ -0,0 +1,214 @@
+package xmac
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestNewAllocator_InvalidRange(t *testing.T) {
+	if _, err := NewAllocator(MustParse("02:00:00:00:00:02"), MustParse("02:00:00:00:00:01")); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("from > to: err = %v, want ErrInvalidRange", err)
+	}
+	// 单个组播地址，没有可分配的单播地址
+	if _, err := NewAllocator(MustParse("01:00:5e:00:00:01"), MustParse("01:00:5e:00:00:01")); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("multicast-only range: err = %v, want ErrInvalidRange", err)
+	}
+}
+
+func TestAllocator_AllocateSequentialAndExhaust(t *testing.T) {
+	a, err := NewAllocator(MustParse("02:00:00:00:00:01"), MustParse("02:00:00:00:00:03"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+
+	want := []string{"02:00:00:00:00:01", "02:00:00:00:00:02", "02:00:00:00:00:03"}
+	for _, w := range want {
+		got, err := a.Allocate()
+		if err != nil {
+			t.Fatalf("Allocate: %v", err)
+		}
+		if got.String() != w {
+			t.Errorf("Allocate = %s, want %s", got, w)
+		}
+	}
+	if _, err := a.Allocate(); !errors.Is(err, ErrPoolExhausted) {
+		t.Errorf("Allocate on full pool: err = %v, want ErrPoolExhausted", err)
+	}
+
+	stats := a.Stats()
+	if stats.Total != 3 || stats.Allocated != 3 || stats.Available != 0 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestAllocator_ReleaseNotReusedImmediately(t *testing.T) {
+	a, err := NewAllocator(MustParse("02:00:00:00:00:01"), MustParse("02:00:00:00:00:03"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	first, _ := a.Allocate()
+	if err := a.Release(first); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+
+	// next-fit：刚释放的地址排在剩余空闲地址之后
+	second, _ := a.Allocate()
+	if second == first {
+		t.Errorf("released address %s reused immediately", first)
+	}
+	third, _ := a.Allocate()
+	fourth, err := a.Allocate()
+	if err != nil {
+		t.Fatalf("Allocate after wrap: %v", err)
+	}
+	if fourth != first {
+		t.Errorf("wrap-around Allocate = %s, want %s (third=%s)", fourth, first, third)
+	}
+}
+
+func TestAllocator_ReleaseErrors(t *testing.T) {
+	a, err := NewAllocator(MustParse("02:00:00:00:00:01"), MustParse("02:00:00:00:00:0f"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+
+	if err := a.Release(MustParse("02:00:00:00:01:00")); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Release out of range: err = %v, want ErrOutOfRange", err)
+	}
+	if err := a.Release(MustParse("02:00:00:00:00:05")); !errors.Is(err, ErrNotAllocated) {
+		t.Errorf("Release unallocated: err = %v, want ErrNotAllocated", err)
+	}
+}
+
+func TestAllocator_Reserve(t *testing.T) {
+	a, err := NewAllocator(MustParse("02:00:00:00:00:01"), MustParse("02:00:00:00:00:03"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+
+	reserved := MustParse("02:00:00:00:00:01")
+	if err := a.Reserve(reserved); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if err := a.Reserve(reserved); !errors.Is(err, ErrAddrInUse) {
+		t.Errorf("Reserve twice: err = %v, want ErrAddrInUse", err)
+	}
+	if err := a.Reserve(MustParse("03:00:00:00:00:01")); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Reserve out of range: err = %v, want ErrOutOfRange", err)
+	}
+	if !a.IsAllocated(reserved) {
+		t.Error("reserved address should be allocated")
+	}
+
+	got, err := a.Allocate()
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if got == reserved {
+		t.Error("Allocate returned a reserved address")
+	}
+
+	allocated := a.Allocated()
+	if len(allocated) != 2 || allocated[0] != reserved || allocated[1] != got {
+		t.Errorf("Allocated = %v", allocated)
+	}
+}
+
+func TestAllocator_SkipsMulticastAndZero(t *testing.T) {
+	// 范围跨越 00:ff:ff:ff:ff:ff → 01:xx（组播）→ 02:00:00:00:00:00
+	a, err := NewAllocator(MustParse("00:ff:ff:ff:ff:ff"), MustParse("02:00:00:00:00:00"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	if total := a.Stats().Total; total != 2 {
+		t.Fatalf("Total = %d, want 2", total)
+	}
+
+	first, _ := a.Allocate()
+	second, err := a.Allocate()
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	if first.String() != "00:ff:ff:ff:ff:ff" || second.String() != "02:00:00:00:00:00" {
+		t.Errorf("Allocate = %s, %s", first, second)
+	}
+	if err := a.Reserve(MustParse("01:00:00:00:00:01")); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Reserve multicast: err = %v, want ErrOutOfRange", err)
+	}
+
+	z, err := NewAllocator(Addr{}, MustParse("00:00:00:00:00:02"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	if got, _ := z.Allocate(); got.String() != "00:00:00:00:00:01" {
+		t.Errorf("zero address should be skipped, got %s", got)
+	}
+}
+
+func TestAllocatableCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		lo, hi uint64
+		want   uint64
+	}{
+		{"single unicast", 2, 2, 1},
+		{"zero excluded", 0, 3, 3},
+		{"whole first octet 00", 0, multicastBit - 1, multicastBit - 1},
+		{"multicast block only", multicastBit, 2*multicastBit - 1, 0},
+		{"full space", 0, 1<<48 - 1, 1<<47 - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allocatableCount(tt.lo, tt.hi); got != tt.want {
+				t.Errorf("allocatableCount(%d, %d) = %d, want %d", tt.lo, tt.hi, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocator_Concurrent(t *testing.T) {
+	a, err := NewAllocator(MustParse("02:00:00:00:00:01"), MustParse("02:00:00:00:04:00"))
+	if err != nil {
+		t.Fatalf("NewAllocator: %v", err)
+	}
+	total := int(a.Stats().Total)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[Addr]struct{}, total)
+	)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				addr, err := a.Allocate()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				if _, dup := seen[addr]; dup {
+					t.Errorf("duplicate allocation %s", addr)
+				}
+				seen[addr] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != total {
+		t.Errorf("allocated %d addresses, want %d", len(seen), total)
+	}
+}
+
+func TestUint64ToAddr_RoundTrip(t *testing.T) {
+	for _, s := range []string{"00:00:00:00:00:01", "aa:bb:cc:dd:ee:ff", "ff:ff:ff:ff:ff:ff"} {
+		addr := MustParse(s)
+		if got := uint64ToAddr(addrToUint64(addr)); got != addr {
+			t.Errorf("round trip %s = %s", s, got)
+		}
+	}
+}
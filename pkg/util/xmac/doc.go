@@ -7,6 +7,7 @@
 //   - 地址属性判断（单播/多播、本地/全局管理）
 //   - JSON/Text/Binary/SQL 序列化支持
 //   - 地址运算（Next/Prev）
+//   - 地址池分配（[Allocator]，并发安全，自动跳过组播与全零地址）
 //
 // # 快速示例
 //
@@ -25,4 +25,19 @@ var (
 
 	// ErrNilReceiver 表示在 nil *Addr 接收者上调用了需要写入的方法。
 	ErrNilReceiver = errors.New("xmac: nil Addr receiver")
+
+	// ErrInvalidRange 表示 [NewAllocator] 的地址范围无效（from > to 或不含可分配地址）。
+	ErrInvalidRange = errors.New("xmac: invalid address range")
+
+	// ErrPoolExhausted 表示 [Allocator] 已无可分配地址。
+	ErrPoolExhausted = errors.New("xmac: address pool exhausted")
+
+	// ErrOutOfRange 表示地址不属于 [Allocator] 的可分配范围。
+	ErrOutOfRange = errors.New("xmac: address out of allocator range")
+
+	// ErrAddrInUse 表示地址已被分配。
+	ErrAddrInUse = errors.New("xmac: address already allocated")
+
+	// ErrNotAllocated 表示归还的地址未被分配。
+	ErrNotAllocated = errors.New("xmac: address not allocated")
 )
@@ -295,6 +295,39 @@ func BenchmarkWireRangeToIPRange(b *testing.B) {
 	}
 }
 
+// =============================================================================
+// IPSet 二进制格式基准测试
+// =============================================================================
+
+func BenchmarkIPSetBinary(b *testing.B) {
+	// 构建 100 万个不重叠范围的 IPSet（GeoIP 量级）
+	var sb netipx.IPSetBuilder
+	for i := range uint32(1_000_000) {
+		sb.AddRange(netipx.IPRangeFrom(AddrFromUint32(i*4096+1), AddrFromUint32(i*4096+200)))
+	}
+	set, _ := sb.IPSet()
+	data := MarshalIPSet(set)
+	target := AddrFromUint32(500_000*4096 + 100)
+
+	b.Run("Marshal", func(b *testing.B) {
+		buf := make([]byte, 0, len(data))
+		for b.Loop() {
+			buf = AppendIPSet(buf[:0], set)
+		}
+	})
+	b.Run("NewIPSetView", func(b *testing.B) {
+		for b.Loop() {
+			_, _ = NewIPSetView(data)
+		}
+	})
+	b.Run("IPSetView.Contains", func(b *testing.B) {
+		v, _ := NewIPSetView(data)
+		for b.Loop() {
+			_ = v.Contains(target)
+		}
+	})
+}
+
 // =============================================================================
 // MergeRanges 基准测试
 // =============================================================================
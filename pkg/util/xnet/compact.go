@@ -0,0 +1,215 @@
+package xnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"slices"
+	"sort"
+
+	"go4.org/netipx"
+)
+
+// IPSetBinaryVersion 是 [MarshalIPSet] 输出的二进制格式版本。
+//
+// 格式变更（字段含义、布局）时递增版本号；读取端拒绝不认识的版本，
+// 返回 [ErrInvalidIPSetData]，避免按错误布局解释数据。
+const IPSetBinaryVersion = 1
+
+// ipSetMagic 二进制格式的魔数
+const ipSetMagic = "XNIP"
+
+const (
+	// ipSetHeaderSize 头部长度：魔数(4) + 版本(1) + 保留(3) + IPv4 条数(4) + IPv6 条数(4)
+	ipSetHeaderSize = 16
+	// ipv4RangeSize 单条 IPv4 范围长度：起止地址各 4 字节
+	ipv4RangeSize = 8
+	// ipv6RangeSize 单条 IPv6 范围长度：起止地址各 16 字节
+	ipv6RangeSize = 32
+)
+
+// MarshalIPSet 将 [*netipx.IPSet] 编码为紧凑的二进制格式。
+// set 为 nil 时编码为空集合。
+//
+// 格式（版本 1，多字节整数为小端序，地址为网络字节序）：
+//
+//	偏移  长度     内容
+//	0     4        魔数 "XNIP"
+//	4     1        版本号（IPSetBinaryVersion）
+//	5     3        保留，必须为 0
+//	8     4        IPv4 范围条数 n4
+//	12    4        IPv6 范围条数 n6
+//	16    8*n4     IPv4 范围：From(4) To(4)，按地址升序
+//	...   32*n6    IPv6 范围：From(16) To(16)，按地址升序
+//
+// 每条范围固定 8/32 字节，相比 JSON 形式的 [WireRange] 通常小 4~5 倍。
+// IPv4-mapped IPv6 地址（如 ::ffff:10.0.0.1）与 [*netipx.IPSet] 一致归入 IPv6 段。
+//
+// 设计决策: 采用定长记录 + 大端地址的布局，地址字节序与数值序一致，
+// 可直接对原始字节做二分查找；两段起始偏移均按 8 字节对齐。
+// 数据可以 mmap 后交给 [NewIPSetView] 原地查询，无需解码成 [*netipx.IPSet]。
+func MarshalIPSet(set *netipx.IPSet) []byte {
+	return AppendIPSet(nil, set)
+}
+
+// AppendIPSet 将 set 的二进制编码追加到 dst 并返回扩展后的切片，格式见 [MarshalIPSet]。
+func AppendIPSet(dst []byte, set *netipx.IPSet) []byte {
+	var ranges []netipx.IPRange
+	if set != nil {
+		ranges = set.Ranges()
+	}
+	// Ranges() 按地址升序返回，IPv4 全部排在 IPv6 之前
+	n4 := sort.Search(len(ranges), func(i int) bool { return !ranges[i].From().Is4() })
+	n6 := len(ranges) - n4
+
+	dst = append(dst, ipSetMagic...)
+	dst = append(dst, IPSetBinaryVersion, 0, 0, 0)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(n4))
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(n6))
+	dst = slices.Grow(dst, n4*ipv4RangeSize+n6*ipv6RangeSize)
+	for _, r := range ranges {
+		if r.From().Is4() {
+			from, to := r.From().As4(), r.To().As4()
+			dst = append(dst, from[:]...)
+			dst = append(dst, to[:]...)
+			continue
+		}
+		from, to := r.From().As16(), r.To().As16()
+		dst = append(dst, from[:]...)
+		dst = append(dst, to[:]...)
+	}
+	return dst
+}
+
+// UnmarshalIPSet 从 [MarshalIPSet] 的输出解码 [*netipx.IPSet]。
+// 数据格式或版本不合法时返回 [ErrInvalidIPSetData]。
+func UnmarshalIPSet(data []byte) (*netipx.IPSet, error) {
+	v, err := NewIPSetView(data)
+	if err != nil {
+		return nil, err
+	}
+	return v.ToIPSet()
+}
+
+// IPSetView 是 [MarshalIPSet] 编码数据的只读视图，直接在原始字节上做二分查找。
+//
+// 适用于 GeoIP、威胁情报等数百万条范围的数据集：文件 mmap 后构造视图即可查询，
+// 不需要把全部范围解码到堆上。视图不复制 data，调用方需保证 data 在视图使用期间
+// 不被修改或释放（如 munmap）。视图是只读的，可并发使用。
+type IPSetView struct {
+	v4 []byte
+	v6 []byte
+}
+
+// NewIPSetView 校验 data 并创建只读视图。
+//
+// 设计决策: 构造时线性校验每条范围 From <= To 且段内严格升序、互不重叠，
+// 以保证二分查找结果正确。校验只做字节比较、不分配内存，百万条范围约在毫秒级完成；
+// 相比损坏数据导致静默误判（ACL/黑名单漏判），这一次性开销是值得的。
+func NewIPSetView(data []byte) (*IPSetView, error) {
+	if len(data) < ipSetHeaderSize {
+		return nil, fmt.Errorf("%w: data too short (%d bytes)", ErrInvalidIPSetData, len(data))
+	}
+	if string(data[:4]) != ipSetMagic {
+		return nil, fmt.Errorf("%w: bad magic %q", ErrInvalidIPSetData, data[:4])
+	}
+	if data[4] != IPSetBinaryVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidIPSetData, data[4])
+	}
+	if data[5] != 0 || data[6] != 0 || data[7] != 0 {
+		return nil, fmt.Errorf("%w: reserved bytes must be zero", ErrInvalidIPSetData)
+	}
+
+	n4 := uint64(binary.LittleEndian.Uint32(data[8:12]))
+	n6 := uint64(binary.LittleEndian.Uint32(data[12:16]))
+	size4, size6 := n4*ipv4RangeSize, n6*ipv6RangeSize
+	if want := ipSetHeaderSize + size4 + size6; uint64(len(data)) != want {
+		return nil, fmt.Errorf("%w: length %d does not match header (want %d)", ErrInvalidIPSetData, len(data), want)
+	}
+
+	v := &IPSetView{
+		v4: data[ipSetHeaderSize : ipSetHeaderSize+size4],
+		v6: data[ipSetHeaderSize+size4:],
+	}
+	if err := validateSection(v.v4, ipv4RangeSize); err != nil {
+		return nil, fmt.Errorf("%w: IPv4 %w", ErrInvalidIPSetData, err)
+	}
+	if err := validateSection(v.v6, ipv6RangeSize); err != nil {
+		return nil, fmt.Errorf("%w: IPv6 %w", ErrInvalidIPSetData, err)
+	}
+	return v, nil
+}
+
+// validateSection 校验定长范围段：每条 From <= To，且与前一条严格升序、互不重叠
+func validateSection(sec []byte, size int) error {
+	half := size / 2
+	var prevTo []byte
+	for i := 0; i < len(sec); i += size {
+		from, to := sec[i:i+half], sec[i+half:i+size]
+		if bytes.Compare(from, to) > 0 {
+			return fmt.Errorf("range [%d]: start > end", i/size)
+		}
+		if prevTo != nil && bytes.Compare(prevTo, from) >= 0 {
+			return fmt.Errorf("range [%d]: not sorted or overlaps previous range", i/size)
+		}
+		prevTo = to
+	}
+	return nil
+}
+
+// Len 返回视图中的范围条数（IPv4 与 IPv6 之和）
+func (v *IPSetView) Len() int {
+	return len(v.v4)/ipv4RangeSize + len(v.v6)/ipv6RangeSize
+}
+
+// Contains 报告 addr 是否在集合中，O(log n) 且不分配内存。
+//
+// 语义与 [*netipx.IPSet.Contains] 一致：带 zone 的地址和无效地址返回 false，
+// IPv4-mapped IPv6 地址只匹配 IPv6 段中的范围。
+func (v *IPSetView) Contains(addr netip.Addr) bool {
+	if !addr.IsValid() || addr.Zone() != "" {
+		return false
+	}
+	if addr.Is4() {
+		a := addr.As4()
+		return sectionContains(v.v4, ipv4RangeSize, a[:])
+	}
+	a := addr.As16()
+	return sectionContains(v.v6, ipv6RangeSize, a[:])
+}
+
+// sectionContains 在升序定长范围段中二分查找 key
+func sectionContains(sec []byte, size int, key []byte) bool {
+	half := size / 2
+	n := len(sec) / size
+	// 找到第一条 From > key 的范围，其前一条是唯一可能包含 key 的范围
+	i := sort.Search(n, func(i int) bool {
+		return bytes.Compare(sec[i*size:i*size+half], key) > 0
+	})
+	if i == 0 {
+		return false
+	}
+	off := (i - 1) * size
+	return bytes.Compare(key, sec[off+half:off+size]) <= 0
+}
+
+// ToIPSet 将视图解码为 [*netipx.IPSet]，返回的集合不引用视图底层数据
+func (v *IPSetView) ToIPSet() (*netipx.IPSet, error) {
+	var b netipx.IPSetBuilder
+	for i := 0; i < len(v.v4); i += ipv4RangeSize {
+		from := netip.AddrFrom4([4]byte(v.v4[i : i+4]))
+		to := netip.AddrFrom4([4]byte(v.v4[i+4 : i+ipv4RangeSize]))
+		b.AddRange(netipx.IPRangeFrom(from, to))
+	}
+	for i := 0; i < len(v.v6); i += ipv6RangeSize {
+		from := netip.AddrFrom16([16]byte(v.v6[i : i+16]))
+		to := netip.AddrFrom16([16]byte(v.v6[i+16 : i+ipv6RangeSize]))
+		b.AddRange(netipx.IPRangeFrom(from, to))
+	}
+	set, err := b.IPSet()
+	if err != nil {
+		return nil, fmt.Errorf("build IPSet: %w", err)
+	}
+	return set, nil
+}
@@ -0,0 +1,159 @@
+package xnet
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/netipx"
+)
+
+func TestMarshalIPSet_RoundTrip(t *testing.T) {
+	set := mustIPSet(t,
+		"10.0.0.0/8",
+		"192.168.1.1-192.168.1.100",
+		"2001:db8::/32",
+	)
+	// ParseRange 会把 IPv4-mapped 归一化为 IPv4，这里直接加入 IPv4-mapped 范围
+	var b netipx.IPSetBuilder
+	b.AddSet(set)
+	b.AddRange(netipx.IPRangeFrom(
+		netip.MustParseAddr("::ffff:172.16.0.1"),
+		netip.MustParseAddr("::ffff:172.16.0.9"),
+	))
+	set, err := b.IPSet()
+	require.NoError(t, err)
+
+	data := MarshalIPSet(set)
+	got, err := UnmarshalIPSet(data)
+	require.NoError(t, err)
+	assert.Equal(t, set.Ranges(), got.Ranges())
+
+	v, err := NewIPSetView(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(set.Ranges()), v.Len())
+}
+
+func TestMarshalIPSet_Empty(t *testing.T) {
+	for _, set := range []*netipx.IPSet{nil, mustIPSet(t)} {
+		data := MarshalIPSet(set)
+		assert.Len(t, data, ipSetHeaderSize)
+
+		got, err := UnmarshalIPSet(data)
+		require.NoError(t, err)
+		assert.Empty(t, got.Ranges())
+
+		v, err := NewIPSetView(data)
+		require.NoError(t, err)
+		assert.Equal(t, 0, v.Len())
+		assert.False(t, v.Contains(netip.MustParseAddr("10.0.0.1")))
+	}
+}
+
+func TestMarshalIPSet_Layout(t *testing.T) {
+	data := MarshalIPSet(mustIPSet(t, "10.0.0.1-10.0.0.2", "2001:db8::/64"))
+
+	assert.Equal(t, "XNIP", string(data[:4]))
+	assert.Equal(t, byte(IPSetBinaryVersion), data[4])
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(data[8:12]))
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(data[12:16]))
+	assert.Equal(t, []byte{10, 0, 0, 1, 10, 0, 0, 2}, data[16:24])
+	assert.Len(t, data, ipSetHeaderSize+ipv4RangeSize+ipv6RangeSize)
+}
+
+func TestMarshalIPSet_Compact(t *testing.T) {
+	var b netipx.IPSetBuilder
+	for i := range 1000 {
+		from := AddrFromUint32(uint32(i) << 12)
+		to := AddrFromUint32(uint32(i)<<12 | 0x7ff)
+		b.AddRange(netipx.IPRangeFrom(from, to))
+	}
+	set, err := b.IPSet()
+	require.NoError(t, err)
+
+	js, err := json.Marshal(WireRangesFromSet(set))
+	require.NoError(t, err)
+	data := MarshalIPSet(set)
+	assert.Less(t, len(data)*4, len(js), "binary %d bytes, json %d bytes", len(data), len(js))
+}
+
+func TestAppendIPSet(t *testing.T) {
+	prefix := []byte("prefix")
+	data := AppendIPSet(prefix, mustIPSet(t, "10.0.0.0/24"))
+	assert.Equal(t, "prefix", string(data[:len(prefix)]))
+
+	got, err := UnmarshalIPSet(data[len(prefix):])
+	require.NoError(t, err)
+	assert.Equal(t, mustIPSet(t, "10.0.0.0/24").Ranges(), got.Ranges())
+}
+
+func TestIPSetView_Contains(t *testing.T) {
+	set := mustIPSet(t,
+		"10.0.0.0/24",
+		"10.0.2.0/24",
+		"192.168.1.1",
+		"0.0.0.0",
+		"255.255.255.255",
+		"2001:db8::/64",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	)
+	v, err := NewIPSetView(MarshalIPSet(set))
+	require.NoError(t, err)
+
+	addrs := []string{
+		"10.0.0.0", "10.0.0.255", "10.0.1.0", "10.0.2.128", "10.0.3.0",
+		"192.168.1.0", "192.168.1.1", "192.168.1.2",
+		"0.0.0.0", "0.0.0.1", "255.255.255.254", "255.255.255.255",
+		"2001:db8::1", "2001:db8:0:1::", "2001:db7::1", "::",
+		"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+		"::ffff:10.0.0.1", // IPv4-mapped 只匹配 IPv6 段
+		"fe80::1%eth0",
+	}
+	for _, s := range addrs {
+		addr := netip.MustParseAddr(s)
+		assert.Equal(t, set.Contains(addr), v.Contains(addr), s)
+	}
+	assert.False(t, v.Contains(netip.Addr{}))
+}
+
+func TestNewIPSetView_Invalid(t *testing.T) {
+	valid := MarshalIPSet(mustIPSet(t, "10.0.0.1-10.0.0.2", "10.0.0.10-10.0.0.20", "2001:db8::/64"))
+
+	corrupt := func(fn func(b []byte) []byte) []byte {
+		b := append([]byte(nil), valid...)
+		return fn(b)
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short header", valid[:ipSetHeaderSize-1]},
+		{"bad magic", corrupt(func(b []byte) []byte { b[0] = 'Y'; return b })},
+		{"unsupported version", corrupt(func(b []byte) []byte { b[4] = IPSetBinaryVersion + 1; return b })},
+		{"reserved bytes", corrupt(func(b []byte) []byte { b[6] = 1; return b })},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0)},
+		{"huge count", corrupt(func(b []byte) []byte {
+			binary.LittleEndian.PutUint32(b[8:12], 0xffffffff)
+			return b
+		})},
+		{"start > end", corrupt(func(b []byte) []byte { b[16+3] = 3; return b })},
+		{"unsorted", corrupt(func(b []byte) []byte {
+			copy(b[16:24], []byte{10, 0, 0, 30, 10, 0, 0, 40})
+			return b
+		})},
+		{"overlapping", corrupt(func(b []byte) []byte { b[16+7] = 10; return b })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIPSetView(tt.data)
+			require.ErrorIs(t, err, ErrInvalidIPSetData)
+			_, err = UnmarshalIPSet(tt.data)
+			assert.ErrorIs(t, err, ErrInvalidIPSetData)
+		})
+	}
+}
@@ -11,7 +11,10 @@
 //   - format.go: FullIP 全长格式化（"192.168.001.001"）、标准化、校验
 //   - parse.go: 解析单 IP/CIDR/掩码/范围格式为 [netipx.IPRange]，批量解析为 [*netipx.IPSet]
 //   - wire.go: [WireRange] JSON/BSON/YAML 序列化的 IP 范围结构
+//   - compact.go: [*netipx.IPSet] 的紧凑二进制格式 [MarshalIPSet] 及 mmap 友好的只读视图 [IPSetView]
 //   - contains.go: IP 范围包含判断、合并、大小计算、CIDR 转换等
+//   - matcher.go: 基于 [*netipx.IPSet] 的黑白名单匹配器 [Matcher]（deny 优先，支持热更新）
+//   - reverse.go: IP 范围的并发反向 DNS 解析 [ReverseLookupRange]（资产盘点、网络审计）
 //
 // # 快速示例
 //
@@ -162,6 +165,34 @@
 //	r, _ = xnet.ParseRange("192.168.1.1-192.168.1.100")
 //	prefixes := xnet.RangeToPrefixes(r)   // 分解为多个 CIDR 块
 //
+// # 二进制格式
+//
+// GeoIP、威胁情报等数百万条范围的数据集使用 [MarshalIPSet] 编码为紧凑的二进制格式，
+// 格式带魔数与版本号（[IPSetBinaryVersion]），布局详见 [MarshalIPSet]：
+//
+//	data := xnet.MarshalIPSet(set)
+//	_ = os.WriteFile("geoip.xnip", data, 0o644)
+//
+// 加载时可完整解码为 [*netipx.IPSet]，也可在原始字节（如 mmap 映射的文件）上直接查询：
+//
+//	set, err := xnet.UnmarshalIPSet(data)        // 解码到堆上
+//	view, err := xnet.NewIPSetView(mmapped)      // 零拷贝视图，O(log n) 查询
+//	view.Contains(netip.MustParseAddr("1.2.3.4"))
+//
+// 格式或版本不合法时返回 [ErrInvalidIPSetData]。
+//
+// # 反向 DNS 批量解析
+//
+// [ReverseLookupRange] 对范围内 IP 并发做 PTR 解析，并发度有界（基于 xpool）：
+//
+//	r, _ := xnet.ParseRange("10.0.0.0/24")
+//	names, err := xnet.ReverseLookupRange(ctx, r, nil, 16) // nil 使用 net.DefaultResolver
+//	for addr, hosts := range names { ... }
+//	if errors.Is(err, xnet.ErrReverseLookup) { /* 部分地址解析失败，names 仍包含成功部分 */ }
+//
+// 无 PTR 记录的地址不计为失败。单次最多解析 [MaxReverseLookupRange] 个地址，
+// 超出返回 [ErrRangeTooLarge]。
+//
 // # 从 gobase/mutils 迁移
 //
 // gobase mutils/iputils.go → xnet 的核心 API 映射：
@@ -17,4 +17,16 @@ var (
 
 	// ErrOverflow 表示 IP 地址算术运算溢出。
 	ErrOverflow = errors.New("xnet: address arithmetic overflow")
+
+	// ErrRangeTooLarge 表示 IP 范围超过操作允许的最大地址数。
+	ErrRangeTooLarge = errors.New("xnet: IP range too large")
+
+	// ErrReverseLookup 表示反向 DNS 解析失败（不含无 PTR 记录的情况）。
+	ErrReverseLookup = errors.New("xnet: reverse lookup failed")
+
+	// ErrInvalidIPSetData 表示 IPSet 二进制数据格式或版本不合法。
+	ErrInvalidIPSetData = errors.New("xnet: invalid IPSet binary data")
+
+	// ErrNilContext 表示传入的 context 为 nil。
+	ErrNilContext = errors.New("xnet: nil context")
 )
@@ -131,3 +131,17 @@ func ExampleWireRangeFrom() {
 	// Output:
 	// {"start":"192.168.1.1","end":"192.168.1.100"}
 }
+
+func ExampleMatcher() {
+	allow, _ := xnet.ParseRanges([]string{"10.0.0.0/8"})
+	deny, _ := xnet.ParseRanges([]string{"10.0.0.1"})
+	m := xnet.NewMatcher(allow, deny)
+
+	fmt.Println(m.Match(netip.MustParseAddr("10.1.2.3")))
+	fmt.Println(m.Match(netip.MustParseAddr("10.0.0.1")))
+	fmt.Println(m.Match(netip.MustParseAddr("192.168.1.1")))
+	// Output:
+	// allow
+	// deny
+	// deny
+}
@@ -0,0 +1,105 @@
+package xnet
+
+import (
+	"net/netip"
+	"sync/atomic"
+
+	"go4.org/netipx"
+)
+
+// Decision 表示 [Matcher] 的访问控制决策。
+type Decision int
+
+const (
+	// DecisionDeny 拒绝访问。
+	DecisionDeny Decision = iota
+	// DecisionAllow 允许访问。
+	DecisionAllow
+)
+
+// String 返回决策名称。
+func (d Decision) String() string {
+	if d == DecisionAllow {
+		return "allow"
+	}
+	return "deny"
+}
+
+// matcherRules 一组不可变的黑白名单规则，热更新时整体替换。
+type matcherRules struct {
+	allow *netipx.IPSet
+	deny  *netipx.IPSet
+}
+
+// Matcher 基于 [*netipx.IPSet] 的 IP 黑白名单匹配器。
+//
+// 决策规则（按顺序）：
+//  1. 命中 deny 名单 → [DecisionDeny]（deny 优先于 allow）
+//  2. allow 为 nil（黑名单模式）→ [DecisionAllow]
+//  3. 命中 allow 名单 → [DecisionAllow]
+//  4. 其他 → [DecisionDeny]（白名单模式下默认拒绝）
+//
+// 无效地址（零值 [netip.Addr]）始终返回 [DecisionDeny]。
+// IPv4-mapped IPv6 地址（如 "::ffff:10.0.0.1"）按纯 IPv4 匹配，
+// 与 [ParseRange] 的归一化行为一致，避免同一客户端因地址格式不同得到不同决策。
+// 带 zone ID 的 IPv6 地址按去除 zone 后的地址匹配。
+//
+// Matcher 是并发安全的。[Matcher.Update] 原子替换整组规则，
+// 并发的 [Matcher.Match] 要么看到旧规则、要么看到新规则，不会看到混合状态。
+//
+// 设计决策: 零值可用，等价于 NewMatcher(nil, nil)（无任何规则，有效地址均允许），
+// 与 allow/deny 为 nil 的语义一致，避免未初始化的 Matcher 在 Match 时 panic。
+type Matcher struct {
+	rules atomic.Pointer[matcherRules]
+}
+
+// NewMatcher 创建 IP 黑白名单匹配器。
+//
+// allow 为 nil 表示不启用白名单（未命中 deny 的地址均允许）；
+// 空的非 nil IPSet 表示白名单为空（未命中 deny 的地址均拒绝）。
+// deny 为 nil 等价于空黑名单。
+//
+// 示例：
+//
+//	allow, _ := xnet.ParseRanges([]string{"10.0.0.0/8", "192.168.0.0/16"})
+//	deny, _ := xnet.ParseRanges([]string{"10.0.0.1"})
+//	m := xnet.NewMatcher(allow, deny)
+//	m.Match(netip.MustParseAddr("10.1.2.3")) // DecisionAllow
+//	m.Match(netip.MustParseAddr("10.0.0.1")) // DecisionDeny
+func NewMatcher(allow, deny *netipx.IPSet) *Matcher {
+	m := &Matcher{}
+	m.Update(allow, deny)
+	return m
+}
+
+// Update 原子替换黑白名单，参数语义同 [NewMatcher]。
+// 调用方不应在传入后继续修改 IPSet 的来源 builder（IPSet 本身不可变）。
+func (m *Matcher) Update(allow, deny *netipx.IPSet) {
+	m.rules.Store(&matcherRules{allow: allow, deny: deny})
+}
+
+// Match 返回 addr 的访问控制决策。
+func (m *Matcher) Match(addr netip.Addr) Decision {
+	if !addr.IsValid() {
+		return DecisionDeny
+	}
+	addr = addr.Unmap().WithZone("")
+
+	r := m.rules.Load()
+	if r == nil {
+		// 零值 Matcher 尚未设置规则
+		return DecisionAllow
+	}
+	if r.deny != nil && r.deny.Contains(addr) {
+		return DecisionDeny
+	}
+	if r.allow == nil || r.allow.Contains(addr) {
+		return DecisionAllow
+	}
+	return DecisionDeny
+}
+
+// Allowed 报告 addr 是否被允许，等价于 Match(addr) == DecisionAllow。
+func (m *Matcher) Allowed(addr netip.Addr) bool {
+	return m.Match(addr) == DecisionAllow
+}
@@ -0,0 +1,109 @@
+package xnet
+
+import (
+	"net/netip"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/netipx"
+)
+
+func mustIPSet(t *testing.T, specs ...string) *netipx.IPSet {
+	t.Helper()
+	set, err := ParseRanges(specs)
+	require.NoError(t, err)
+	return set
+}
+
+func TestMatcher_Match(t *testing.T) {
+	allow := mustIPSet(t, "10.0.0.0/8", "2001:db8::/32")
+	deny := mustIPSet(t, "10.0.0.1", "10.9.0.0/16")
+
+	tests := []struct {
+		name  string
+		allow *netipx.IPSet
+		deny  *netipx.IPSet
+		addr  string
+		want  Decision
+	}{
+		{"whitelist hit", allow, deny, "10.1.2.3", DecisionAllow},
+		{"whitelist miss", allow, deny, "192.168.1.1", DecisionDeny},
+		{"deny overrides allow", allow, deny, "10.0.0.1", DecisionDeny},
+		{"deny range overrides allow", allow, deny, "10.9.1.1", DecisionDeny},
+		{"ipv6 whitelist hit", allow, deny, "2001:db8::1", DecisionAllow},
+		{"ipv4-mapped normalized", allow, deny, "::ffff:10.1.2.3", DecisionAllow},
+		{"ipv4-mapped deny normalized", allow, deny, "::ffff:10.0.0.1", DecisionDeny},
+		{"zone stripped", allow, deny, "2001:db8::1%eth0", DecisionAllow},
+		{"blacklist mode miss", nil, deny, "192.168.1.1", DecisionAllow},
+		{"blacklist mode hit", nil, deny, "10.0.0.1", DecisionDeny},
+		{"no rules", nil, nil, "8.8.8.8", DecisionAllow},
+		{"empty whitelist", mustIPSet(t), nil, "8.8.8.8", DecisionDeny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(tt.allow, tt.deny)
+			addr := netip.MustParseAddr(tt.addr)
+			assert.Equal(t, tt.want, m.Match(addr))
+			assert.Equal(t, tt.want == DecisionAllow, m.Allowed(addr))
+		})
+	}
+}
+
+func TestMatcher_InvalidAddr(t *testing.T) {
+	m := NewMatcher(nil, nil)
+	assert.Equal(t, DecisionDeny, m.Match(netip.Addr{}))
+}
+
+func TestMatcher_ZeroValue(t *testing.T) {
+	var m Matcher
+	assert.Equal(t, DecisionAllow, m.Match(netip.MustParseAddr("10.0.0.1")))
+	assert.Equal(t, DecisionDeny, m.Match(netip.Addr{}))
+
+	m.Update(nil, mustIPSet(t, "10.0.0.0/24"))
+	assert.False(t, m.Allowed(netip.MustParseAddr("10.0.0.1")))
+}
+
+func TestMatcher_Update(t *testing.T) {
+	addr := netip.MustParseAddr("10.0.0.1")
+	m := NewMatcher(nil, nil)
+	assert.True(t, m.Allowed(addr))
+
+	m.Update(nil, mustIPSet(t, "10.0.0.0/24"))
+	assert.False(t, m.Allowed(addr))
+
+	m.Update(mustIPSet(t, "10.0.0.1"), nil)
+	assert.True(t, m.Allowed(addr))
+}
+
+func TestMatcher_ConcurrentUpdate(t *testing.T) {
+	addr := netip.MustParseAddr("10.0.0.1")
+	hit := mustIPSet(t, "10.0.0.0/24")
+	m := NewMatcher(nil, nil)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			if i%2 == 0 {
+				m.Update(nil, hit)
+			} else {
+				m.Update(nil, nil)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			_ = m.Match(addr)
+		}
+	}()
+	wg.Wait()
+}
+
+func TestDecision_String(t *testing.T) {
+	assert.Equal(t, "allow", DecisionAllow.String())
+	assert.Equal(t, "deny", DecisionDeny.String())
+}
@@ -0,0 +1,125 @@
+package xnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"slices"
+	"sync"
+
+	"github.com/omeyang/xkit/pkg/util/xpool"
+	"go4.org/netipx"
+)
+
+// MaxReverseLookupRange 是 [ReverseLookupRange] 单次允许解析的最大地址数。
+// 防止误传大范围（如 IPv6 /64）导致海量 DNS 查询。
+const MaxReverseLookupRange = 1 << 16 // 65536
+
+// Resolver 反向 DNS 解析器，[*net.Resolver] 满足此接口。
+// 测试或需要自定义 DNS 服务器时可注入其他实现。
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// ReverseLookupRange 对范围内的每个 IP 并发做反向 DNS（PTR）解析，
+// 返回有 PTR 记录的地址及其域名（保留解析器返回的原始格式，通常带结尾的 "."）。
+//
+// 参数：
+//   - resolver 为 nil 时使用 [net.DefaultResolver]
+//   - concurrency 为并发解析数，小于 1 时按 1 处理，不超过范围地址数
+//
+// 无 PTR 记录（[net.DNSError.IsNotFound]）不视为错误，地址不出现在结果中。
+// 其他解析失败（超时、服务器错误等）汇总为包装 [ErrReverseLookup] 的错误，
+// 与成功部分的结果一同返回，按地址升序排列。
+// 单次查询超时由 resolver 自身控制，整体超时/取消由 ctx 控制：
+// ctx 结束后未开始的查询被跳过，返回的错误包含 ctx.Err()。
+//
+// 设计决策: 并发复用 [xpool.Pool]，队列容量等于地址数，Submit 不会因队列满失败；
+// 范围超过 [MaxReverseLookupRange] 时直接返回 [ErrRangeTooLarge]，不做部分解析。
+func ReverseLookupRange(ctx context.Context, r netipx.IPRange, resolver Resolver, concurrency int) (map[netip.Addr][]string, error) {
+	if ctx == nil {
+		return nil, ErrNilContext
+	}
+	if !r.IsValid() {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRange, r)
+	}
+	size := RangeSize(r)
+	if !size.IsInt64() || size.Int64() > MaxReverseLookupRange {
+		return nil, fmt.Errorf("%w: %s addresses, max %d", ErrRangeTooLarge, size, MaxReverseLookupRange)
+	}
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	n := int(size.Int64())
+	concurrency = min(max(concurrency, 1), n)
+
+	var (
+		mu       sync.Mutex
+		results  = make(map[netip.Addr][]string)
+		failures []reverseLookupFailure
+	)
+	pool, err := xpool.New(concurrency, n, func(addr netip.Addr) {
+		if ctx.Err() != nil {
+			return
+		}
+		names, err := resolver.LookupAddr(ctx, addr.String())
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			if len(names) > 0 {
+				results[addr] = names
+			}
+		case isNotFound(err), ctx.Err() != nil:
+			// 无 PTR 记录不是错误；ctx 结束导致的失败由 joinFailures 统一以 ctx.Err() 报告
+		default:
+			failures = append(failures, reverseLookupFailure{addr: addr, err: err})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for addr := r.From(); ; addr = addr.Next() {
+		if err := pool.Submit(addr); err != nil {
+			return nil, errors.Join(err, pool.Close())
+		}
+		if addr == r.To() {
+			break
+		}
+	}
+	if err := pool.Close(); err != nil {
+		return nil, err
+	}
+
+	return results, joinFailures(ctx, failures)
+}
+
+// reverseLookupFailure 单个地址的解析失败。
+type reverseLookupFailure struct {
+	addr netip.Addr
+	err  error
+}
+
+// isNotFound 判断错误是否表示无 PTR 记录。
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// joinFailures 按地址升序汇总解析失败，ctx 已结束时追加 ctx.Err()。
+func joinFailures(ctx context.Context, failures []reverseLookupFailure) error {
+	slices.SortFunc(failures, func(a, b reverseLookupFailure) int {
+		return a.addr.Compare(b.addr)
+	})
+	errs := make([]error, 0, len(failures)+1)
+	for _, f := range failures {
+		errs = append(errs, fmt.Errorf("%w: %s: %w", ErrReverseLookup, f.addr, f.err))
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package xnet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go4.org/netipx"
+)
+
+// fakeResolver 按地址返回预设结果，并记录最大并发数。
+type fakeResolver struct {
+	names map[string][]string
+	errs  map[string]error
+	delay time.Duration
+
+	inflight    atomic.Int32
+	maxInflight atomic.Int32
+	calls       atomic.Int32
+	mu          sync.Mutex
+}
+
+func (f *fakeResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	f.calls.Add(1)
+	cur := f.inflight.Add(1)
+	defer f.inflight.Add(-1)
+	f.mu.Lock()
+	if cur > f.maxInflight.Load() {
+		f.maxInflight.Store(cur)
+	}
+	f.mu.Unlock()
+
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if err, ok := f.errs[addr]; ok {
+		return nil, err
+	}
+	if names, ok := f.names[addr]; ok {
+		return names, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: addr, IsNotFound: true}
+}
+
+func mustRange(t *testing.T, s string) netipx.IPRange {
+	t.Helper()
+	r, err := ParseRange(s)
+	require.NoError(t, err)
+	return r
+}
+
+func TestReverseLookupRange(t *testing.T) {
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "10.0.0.3", IsTimeout: true}
+	resolver := &fakeResolver{
+		names: map[string][]string{
+			"10.0.0.1": {"gw.example.com."},
+			"10.0.0.2": {"db.example.com.", "db-primary.example.com."},
+		},
+		errs:  map[string]error{"10.0.0.3": timeout},
+		delay: 5 * time.Millisecond,
+	}
+
+	got, err := ReverseLookupRange(context.Background(), mustRange(t, "10.0.0.0/29"), resolver, 3)
+
+	assert.Equal(t, map[netip.Addr][]string{
+		netip.MustParseAddr("10.0.0.1"): {"gw.example.com."},
+		netip.MustParseAddr("10.0.0.2"): {"db.example.com.", "db-primary.example.com."},
+	}, got)
+	require.ErrorIs(t, err, ErrReverseLookup)
+	assert.ErrorIs(t, err, timeout)
+	assert.Contains(t, err.Error(), "10.0.0.3")
+	assert.EqualValues(t, 8, resolver.calls.Load())
+	assert.LessOrEqual(t, resolver.maxInflight.Load(), int32(3))
+}
+
+func TestReverseLookupRange_NoFailures(t *testing.T) {
+	resolver := &fakeResolver{names: map[string][]string{"2001:db8::1": {"v6.example.com."}}}
+
+	got, err := ReverseLookupRange(context.Background(), mustRange(t, "2001:db8::-2001:db8::3"), resolver, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v6.example.com."}, got[netip.MustParseAddr("2001:db8::1")])
+	assert.Len(t, got, 1)
+}
+
+func TestReverseLookupRange_FailuresSortedByAddr(t *testing.T) {
+	boom := errors.New("servfail")
+	resolver := &fakeResolver{errs: map[string]error{"10.0.0.9": boom, "10.0.0.2": boom}}
+
+	_, err := ReverseLookupRange(context.Background(), mustRange(t, "10.0.0.0/28"), resolver, 8)
+
+	require.Error(t, err)
+	msg := err.Error()
+	assert.Less(t, strings.Index(msg, "10.0.0.2"), strings.Index(msg, "10.0.0.9"))
+}
+
+func TestReverseLookupRange_ContextCanceled(t *testing.T) {
+	resolver := &fakeResolver{delay: time.Second}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	got, err := ReverseLookupRange(ctx, mustRange(t, "10.0.0.0/24"), resolver, 4)
+
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrReverseLookup, "ctx errors are not reported per address")
+	assert.Empty(t, got)
+	assert.LessOrEqual(t, resolver.calls.Load(), int32(8))
+}
+
+func TestReverseLookupRange_InvalidInput(t *testing.T) {
+	resolver := &fakeResolver{}
+
+	//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+	_, err := ReverseLookupRange(nil, mustRange(t, "10.0.0.1"), resolver, 1)
+	assert.ErrorIs(t, err, ErrNilContext)
+
+	_, err = ReverseLookupRange(context.Background(), netipx.IPRange{}, resolver, 1)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = ReverseLookupRange(context.Background(), mustRange(t, "10.0.0.0/15"), resolver, 1)
+	assert.ErrorIs(t, err, ErrRangeTooLarge)
+
+	_, err = ReverseLookupRange(context.Background(), mustRange(t, "2001:db8::/64"), resolver, 1)
+	assert.ErrorIs(t, err, ErrRangeTooLarge)
+	assert.Zero(t, resolver.calls.Load())
+}
@@ -12,6 +12,8 @@
 //   - 可注入自定义日志记录器（WithLogger）
 //   - 多实例场景下可设置名称以区分日志来源（WithName）
 //   - panic 日志默认安全（仅记录 task 类型），可通过 WithLogTaskValue 启用完整值输出
+//   - 单任务执行超时（WithTaskTimeout + NewWithContext），不响应 context 的任务记录告警
+//   - Shutdown 超时后剩余任务持久化（WithTaskPersistence + Restore），重启后恢复
 //
 // # 注意事项
 //
@@ -19,8 +21,7 @@
 //   - Close 会等待所有队列中的任务处理完成
 //   - Close/Shutdown 不可在 handler 内调用，否则会死锁
 //   - handler 不应无限阻塞；若 handler 因外部依赖永久阻塞，对应的 worker
-//     goroutine 将无法退出。如需支持取消，可在 T 中嵌入 context.Context
-//     或使用闭包捕获取消信号
+//     goroutine 将无法退出。如需支持取消，可使用 NewWithContext 配合 WithTaskTimeout
 //   - 任务处理器应设计为幂等的，因为同一逻辑任务可能被多次提交
 //   - panic 的任务不会被重试——仅记录日志后丢弃；
 //     panic 恢复日志默认仅记录 task 类型（避免敏感信息泄露），
@@ -37,6 +38,21 @@
 // 残留的 worker goroutine 仍在后台运行，会继续处理剩余任务直到耗尽后退出。
 // 调用方可通过 Done() 返回的 channel 等待所有 worker 最终完成。
 //
+// # 任务持久化
+//
+// 配置 WithTaskPersistence(store) 后，Shutdown(ctx) 超时时队列中的剩余任务不再由后台 worker 处理，
+// 而是以 JSON 序列化后保存到 TaskStore（内置 FileTaskStore，Redis 等可自行实现），
+// 正在执行的任务继续执行完成。重启后调用 Restore(ctx) 将任务重新提交到队列。
+// 持久化失败时 Shutdown 返回的错误同时包含 ctx 错误与 ErrPersistFailed。
+//
+// # 任务超时
+//
+// WithTaskTimeout(d) 为每个任务创建独立的带超时 context，超时后 context 被取消。
+// NewWithContext 的 handler 接收该 context，应监听 ctx.Done() 及时返回以释放 worker。
+// Go 无法强制终止 goroutine，超时后再经过 d 仍未返回的任务会记录一条 warn 日志
+// （遵循 WithLogTaskValue 的脱敏策略），便于定位忽略 context 的慢任务。
+// New 的 handler 不接收 context，WithTaskTimeout 对其仅有告警效果。
+//
 // # 设计选择说明
 //
 // 设计决策: New 返回 *Pool[T] 而非接口：
@@ -44,9 +60,10 @@
 //     但 xpool 作为轻量级工具包，不需要多实现替换，返回具体类型更简洁
 //   - 编译期通过 io.Closer 断言确保关闭契约
 //
-// handler 签名为 func(T) 而非 func(context.Context, T)：
+// New 的 handler 签名为 func(T) 而非 func(context.Context, T)：
 //   - 保持 API 简洁，适用于大多数场景
-//   - 需要 context 传播的用户可在 T 中嵌入 context.Context 或使用闭包
+//   - 需要任务超时取消时使用 NewWithContext，其 handler 接收每个任务独立的 context
+//   - 需要传播调用方 context（如 trace）的用户可在 T 中嵌入 context.Context 或使用闭包
 //   - 参见注意事项中关于 handler 不应永久阻塞的说明
 //
 // Submit 队列满时返回 ErrQueueFull：
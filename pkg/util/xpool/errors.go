@@ -20,4 +20,13 @@ var (
 
 	// ErrNilContext 表示 context 参数为 nil。
 	ErrNilContext = errors.New("xpool: nil context")
+
+	// ErrNoTaskStore 表示未通过 WithTaskPersistence 配置任务存储。
+	ErrNoTaskStore = errors.New("xpool: task store not configured")
+
+	// ErrPersistFailed 表示剩余任务持久化失败。
+	ErrPersistFailed = errors.New("xpool: failed to persist tasks")
+
+	// ErrEmptyPath 表示文件任务存储的路径为空。
+	ErrEmptyPath = errors.New("xpool: task store path cannot be empty")
 )
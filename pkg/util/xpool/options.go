@@ -1,6 +1,9 @@
 package xpool
 
-import "log/slog"
+import (
+	"log/slog"
+	"time"
+)
 
 // Option 定义 Pool 可选配置函数类型。
 type Option func(*options)
@@ -9,6 +12,8 @@ type options struct {
 	logger       *slog.Logger
 	name         string
 	logTaskValue bool
+	taskTimeout  time.Duration
+	store        TaskStore
 }
 
 func defaultOptions() options {
@@ -45,3 +50,35 @@ func WithLogTaskValue() Option {
 		o.logTaskValue = true
 	}
 }
+
+// WithTaskTimeout 为每个任务设置执行超时。
+//
+// 每个任务在独立的带超时 context 中执行，超时后 context 被取消。
+// 只有 [NewWithContext] 创建的 pool 能把 context 传给 handler，handler 需响应 ctx.Done()；
+// worker 无法强制中断 handler，不响应 context 的任务在超时后再经过 d 仍未返回时记录 warn 日志，
+// 用于发现长期占用 worker 的慢任务。[New] 创建的 pool 仅有告警效果。
+// 默认不限制。d <= 0 将被忽略。
+func WithTaskTimeout(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.taskTimeout = d
+		}
+	}
+}
+
+// WithTaskPersistence 设置 Shutdown 超时后剩余任务的持久化存储。
+//
+// Shutdown(ctx) 在 ctx 到期前未处理完队列时，剩余任务不再由后台 worker 继续处理，
+// 而是序列化后保存到 store（如本地文件 [FileTaskStore] 或自行实现的 Redis 存储），
+// 重启后通过 [Pool.Restore] 重新提交。适用于不能丢失的任务（如待发送消息）。
+//
+// 任务使用 encoding/json 序列化，T 的字段须可被 JSON 编解码（未导出字段不会保存）。
+// 持久化只发生在 Shutdown 超时时；Close 或未超时的 Shutdown 会正常处理完所有任务。
+// store 为 nil 时忽略。
+func WithTaskPersistence(store TaskStore) Option {
+	return func(o *options) {
+		if store != nil {
+			o.store = store
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package xpool
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// persistTimeout Shutdown 超时后持久化剩余任务的超时时间。
+// Shutdown 的 ctx 此时已到期，持久化使用独立的超时 context。
+const persistTimeout = 5 * time.Second
+
+// TaskStore 持久化 Shutdown 超时后未处理的任务，供重启后恢复。
+//
+// 任务以序列化后的字节切片传递，实现方无需关心任务类型。
+// 实现必须并发安全。
+type TaskStore interface {
+	// Save 追加保存任务。已保存但尚未 Load 的任务不得被覆盖。
+	Save(ctx context.Context, tasks [][]byte) error
+
+	// Load 取出全部已保存的任务并从存储中移除，无任务时返回空切片。
+	Load(ctx context.Context) ([][]byte, error)
+}
+
+// stashTask 暂存中止处理后从队列取出的任务。
+func (p *Pool[T]) stashTask(task T) {
+	p.stashMu.Lock()
+	p.stash = append(p.stash, task)
+	p.stashMu.Unlock()
+}
+
+// stashedCount 返回已暂存的任务数。
+func (p *Pool[T]) stashedCount() int64 {
+	p.stashMu.Lock()
+	defer p.stashMu.Unlock()
+	return int64(len(p.stash))
+}
+
+// persistRemaining 中止剩余任务的处理，将队列中的任务持久化到 TaskStore。
+//
+// 设计决策: worker 与本方法并发从已关闭的队列取任务，中止后取到的任务一律暂存；
+// 通过 accepted == started + stashed 等待所有已入队任务都有了去向，
+// 避免 worker 刚取出任务、尚未暂存时就保存导致任务丢失。
+// 等待期间双方都不会阻塞（队列已关闭，取出后立即归类），因此很快收敛。
+// 正在执行的任务无法中断，它们会继续执行完成，不会被持久化。
+func (p *Pool[T]) persistRemaining(ctx context.Context) error {
+	p.aborted.Store(true)
+	for task := range p.queue {
+		p.stashTask(task)
+	}
+	for p.started.Load()+p.stashedCount() < p.accepted.Load() {
+		runtime.Gosched()
+	}
+
+	p.stashMu.Lock()
+	tasks := p.stash
+	p.stash = nil
+	p.stashMu.Unlock()
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	encoded := make([][]byte, 0, len(tasks))
+	for _, task := range tasks {
+		data, err := json.Marshal(task)
+		if err != nil {
+			attrs := p.appendTaskAttrs([]slog.Attr{slog.Any("error", err)}, task)
+			p.opts.logger.LogAttrs(ctx, slog.LevelError,
+				"xpool: failed to encode task for persistence, dropping", attrs...)
+			continue
+		}
+		encoded = append(encoded, data)
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), persistTimeout)
+	defer cancel()
+	if err := p.opts.store.Save(saveCtx, encoded); err != nil {
+		return fmt.Errorf("%w: %d tasks: %w", ErrPersistFailed, len(encoded), err)
+	}
+	p.opts.logger.LogAttrs(ctx, slog.LevelWarn, "xpool: persisted unfinished tasks on shutdown",
+		p.appendPoolAttr([]slog.Attr{slog.Int("tasks", len(encoded))})...)
+	return nil
+}
+
+// Restore 从 [WithTaskPersistence] 配置的 TaskStore 取出任务并重新提交到队列。
+//
+// 通常在进程启动、New 之后调用一次。返回成功提交的任务数。
+// 无法反序列化的任务记录错误日志后丢弃；队列容量不足时，未提交的任务会写回 TaskStore，
+// 并返回 [ErrQueueFull]，可在队列消化后再次调用 Restore。
+// 未配置 TaskStore 时返回 [ErrNoTaskStore]，ctx 为 nil 时返回 [ErrNilContext]。
+func (p *Pool[T]) Restore(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, ErrNilContext
+	}
+	if p.opts.store == nil {
+		return 0, ErrNoTaskStore
+	}
+	raw, err := p.opts.store.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("xpool: load persisted tasks: %w", err)
+	}
+
+	restored := 0
+	for i, data := range raw {
+		var task T
+		if err := json.Unmarshal(data, &task); err != nil {
+			p.opts.logger.LogAttrs(ctx, slog.LevelError, "xpool: failed to decode persisted task, dropping",
+				p.appendPoolAttr([]slog.Attr{slog.Any("error", err)})...)
+			continue
+		}
+		if err := p.Submit(task); err != nil {
+			// 写回剩余任务，避免 Load 已移除的任务丢失
+			if saveErr := p.opts.store.Save(ctx, raw[i:]); saveErr != nil {
+				return restored, errors.Join(err, fmt.Errorf("%w: %w", ErrPersistFailed, saveErr))
+			}
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// FileTaskStore 基于本地文件的 [TaskStore] 实现。
+//
+// 每个任务 base64 编码后占一行，Save 追加写入并 fsync，Load 读取后删除文件。
+// 仅保证单进程内并发安全，多个进程不应共享同一文件。
+type FileTaskStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileTaskStore 创建文件任务存储。path 为空时返回 [ErrEmptyPath]。
+func NewFileTaskStore(path string) (*FileTaskStore, error) {
+	if path == "" {
+		return nil, ErrEmptyPath
+	}
+	return &FileTaskStore{path: path}, nil
+}
+
+// Save 实现 [TaskStore]，将任务追加写入文件。
+func (s *FileTaskStore) Save(_ context.Context, tasks [][]byte) (err error) {
+	if len(tasks) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("xpool: open task file: %w", err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("xpool: close task file: %w", closeErr)
+		}
+	}()
+
+	var buf bytes.Buffer
+	for _, task := range tasks {
+		buf.WriteString(base64.StdEncoding.EncodeToString(task))
+		buf.WriteByte('\n')
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("xpool: write task file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("xpool: sync task file: %w", err)
+	}
+	return nil
+}
+
+// Load 实现 [TaskStore]，读取全部任务后删除文件。文件不存在时返回空切片。
+func (s *FileTaskStore) Load(_ context.Context) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("xpool: read task file: %w", err)
+	}
+
+	var tasks [][]byte
+	for line := range bytes.SplitSeq(data, []byte{'\n'}) {
+		if len(line) == 0 {
+			continue
+		}
+		task, err := base64.StdEncoding.DecodeString(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("xpool: decode task file: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := os.Remove(s.path); err != nil {
+		return nil, fmt.Errorf("xpool: remove task file: %w", err)
+	}
+	return tasks, nil
+}
+
+// 编译期接口检查。
+var _ TaskStore = (*FileTaskStore)(nil)
@@ -0,0 +1,228 @@
+package xpool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryTaskStore 内存 TaskStore，用于测试。
+type memoryTaskStore struct {
+	mu      sync.Mutex
+	tasks   [][]byte
+	saveErr error
+}
+
+func (s *memoryTaskStore) Save(_ context.Context, tasks [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saveErr != nil {
+		return s.saveErr
+	}
+	s.tasks = append(s.tasks, tasks...)
+	return nil
+}
+
+func (s *memoryTaskStore) Load(_ context.Context) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tasks := s.tasks
+	s.tasks = nil
+	return tasks, nil
+}
+
+func (s *memoryTaskStore) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.tasks)
+}
+
+type persistTask struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+func TestWorkerPool_PersistOnShutdownTimeout(t *testing.T) {
+	store := &memoryTaskStore{}
+	release := make(chan struct{})
+	var processed atomic.Int32
+
+	pool := newPoolForTest(t, 1, 10, func(task persistTask) {
+		if task.ID == 0 {
+			<-release
+		}
+		processed.Add(1)
+	}, WithTaskPersistence(store))
+
+	for i := range 5 {
+		require.NoError(t, pool.Submit(persistTask{ID: i, Body: "msg"}))
+	}
+	// 等待第一个任务开始执行，其余任务排队
+	require.Eventually(t, func() bool { return pool.QueueLen() == 4 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pool.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+	<-pool.Done()
+	assert.Equal(t, int32(1), processed.Load(), "queued tasks must be persisted instead of processed")
+	assert.Equal(t, 4, store.len())
+
+	// 重启后恢复
+	var mu sync.Mutex
+	var restoredIDs []int
+	var wg sync.WaitGroup
+	wg.Add(4)
+	restoredPool := newPoolForTest(t, 2, 10, func(task persistTask) {
+		mu.Lock()
+		restoredIDs = append(restoredIDs, task.ID)
+		mu.Unlock()
+		wg.Done()
+	}, WithTaskPersistence(store))
+	defer func() { require.NoError(t, restoredPool.Close()) }()
+
+	n, err := restoredPool.Restore(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 4, n)
+	wg.Wait()
+	assert.ElementsMatch(t, []int{1, 2, 3, 4}, restoredIDs)
+	assert.Equal(t, 0, store.len())
+}
+
+func TestWorkerPool_PersistNotUsedWithoutTimeout(t *testing.T) {
+	store := &memoryTaskStore{}
+	var processed atomic.Int32
+	pool := newPoolForTest(t, 2, 10, func(int) { processed.Add(1) }, WithTaskPersistence(store))
+	for i := range 5 {
+		require.NoError(t, pool.Submit(i))
+	}
+	require.NoError(t, pool.Close())
+	assert.Equal(t, int32(5), processed.Load())
+	assert.Equal(t, 0, store.len())
+}
+
+func TestWorkerPool_PersistSaveError(t *testing.T) {
+	saveErr := errors.New("disk full")
+	store := &memoryTaskStore{saveErr: saveErr}
+	release := make(chan struct{})
+	pool := newPoolForTest(t, 1, 10, func(int) { <-release }, WithTaskPersistence(store))
+
+	require.NoError(t, pool.Submit(0))
+	require.NoError(t, pool.Submit(1))
+	require.Eventually(t, func() bool { return pool.QueueLen() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := pool.Shutdown(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrPersistFailed)
+	assert.ErrorIs(t, err, saveErr)
+
+	close(release)
+	<-pool.Done()
+}
+
+func TestWorkerPool_PersistUnencodableTask(t *testing.T) {
+	store := &memoryTaskStore{}
+	release := make(chan struct{})
+	pool := newPoolForTest(t, 1, 10, func(func()) { <-release }, WithTaskPersistence(store),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	require.NoError(t, pool.Submit(func() {}))
+	require.NoError(t, pool.Submit(func() {}))
+	require.Eventually(t, func() bool { return pool.QueueLen() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, pool.Shutdown(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 0, store.len(), "tasks that cannot be encoded are dropped")
+
+	close(release)
+	<-pool.Done()
+}
+
+func TestWorkerPool_Restore(t *testing.T) {
+	t.Run("nil context", func(t *testing.T) {
+		pool := newPoolForTest(t, 1, 1, func(int) {}, WithTaskPersistence(&memoryTaskStore{}))
+		defer func() { require.NoError(t, pool.Close()) }()
+		//nolint:staticcheck // SA1012: 故意传入 nil context 测试 fail-fast 校验
+		_, err := pool.Restore(nil)
+		assert.ErrorIs(t, err, ErrNilContext)
+	})
+
+	t.Run("no store", func(t *testing.T) {
+		pool := newPoolForTest(t, 1, 1, func(int) {})
+		defer func() { require.NoError(t, pool.Close()) }()
+		_, err := pool.Restore(context.Background())
+		assert.ErrorIs(t, err, ErrNoTaskStore)
+	})
+
+	t.Run("queue full writes back remaining", func(t *testing.T) {
+		store := &memoryTaskStore{tasks: [][]byte{[]byte("1"), []byte("2"), []byte("3")}}
+		release := make(chan struct{})
+		pool := newPoolForTest(t, 1, 1, func(int) { <-release }, WithTaskPersistence(store))
+
+		n, err := pool.Restore(context.Background())
+		assert.ErrorIs(t, err, ErrQueueFull)
+		assert.GreaterOrEqual(t, n, 1)
+		assert.Equal(t, 3-n, store.len(), "unsubmitted tasks must be saved back")
+
+		close(release)
+		require.NoError(t, pool.Close())
+	})
+
+	t.Run("undecodable task is dropped", func(t *testing.T) {
+		store := &memoryTaskStore{tasks: [][]byte{[]byte("not-json"), []byte("7")}}
+		got := make(chan int, 1)
+		pool := newPoolForTest(t, 1, 2, func(n int) { got <- n }, WithTaskPersistence(store),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+		defer func() { require.NoError(t, pool.Close()) }()
+
+		n, err := pool.Restore(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, 7, <-got)
+	})
+}
+
+func TestFileTaskStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tasks.log")
+
+	_, err := NewFileTaskStore("")
+	assert.ErrorIs(t, err, ErrEmptyPath)
+
+	store, err := NewFileTaskStore(path)
+	require.NoError(t, err)
+
+	tasks, err := store.Load(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "missing file means no tasks")
+
+	require.NoError(t, store.Save(ctx, [][]byte{[]byte(`{"id":1}`), []byte("line\nbreak")}))
+	require.NoError(t, store.Save(ctx, [][]byte{[]byte(`{"id":2}`)}))
+	require.NoError(t, store.Save(ctx, nil))
+
+	tasks, err = store.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte(`{"id":1}`), []byte("line\nbreak"), []byte(`{"id":2}`)}, tasks)
+
+	_, statErr := os.Stat(path)
+	assert.True(t, os.IsNotExist(statErr), "Load removes the file")
+
+	require.NoError(t, os.WriteFile(path, []byte("!!!\n"), 0o600))
+	_, err = store.Load(ctx)
+	assert.Error(t, err)
+}
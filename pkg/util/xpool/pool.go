@@ -2,12 +2,14 @@ package xpool
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"runtime/debug"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -27,13 +29,20 @@ var closedCh = func() <-chan struct{} {
 type Pool[T any] struct {
 	workers     int
 	queueSize   int
-	handler     func(T)
+	handler     func(context.Context, T)
 	queue       chan T
 	wg          sync.WaitGroup
 	submitMu    sync.RWMutex // 保护 queue 发送操作，防止 send-on-closed-channel
 	closed      atomic.Bool
 	workersDone chan struct{} // 所有 worker 退出后关闭
 	opts        options
+
+	// 以下字段仅在配置 WithTaskPersistence 时使用
+	aborted  atomic.Bool  // Shutdown 超时后置位，worker 不再处理新取出的任务
+	accepted atomic.Int64 // 成功入队的任务数
+	started  atomic.Int64 // 已开始处理的任务数
+	stashMu  sync.Mutex
+	stash    []T // 中止后从队列取出、待持久化的任务
 }
 
 // New 创建并启动 worker pool。
@@ -42,10 +51,29 @@ type Pool[T any] struct {
 //   - workers: worker 数量，必须在 [1, 65536] 范围内，否则返回 [ErrInvalidWorkers]
 //   - queueSize: 任务队列大小，必须在 [1, 16777216] 范围内，否则返回 [ErrInvalidQueueSize]
 //   - handler: 任务处理函数，不能为 nil，否则返回 [ErrNilHandler]
+//
+// handler 不接收 context，[WithTaskTimeout] 无法取消其执行，只能在超时未返回时记录告警；
+// 需要超时取消时请使用 [NewWithContext]。
 func New[T any](workers, queueSize int, handler func(T), opts ...Option) (*Pool[T], error) {
 	if handler == nil {
 		return nil, ErrNilHandler
 	}
+	return newPool(workers, queueSize, func(_ context.Context, task T) { handler(task) }, opts)
+}
+
+// NewWithContext 创建并启动 worker pool，handler 接收每个任务独立的 context。
+//
+// 参数约束与 [New] 相同。配置 [WithTaskTimeout] 时，ctx 在超时后被取消，
+// handler 应监听 ctx.Done() 及时返回，以释放 worker；未配置时 ctx 永不取消。
+func NewWithContext[T any](workers, queueSize int, handler func(context.Context, T), opts ...Option) (*Pool[T], error) {
+	if handler == nil {
+		return nil, ErrNilHandler
+	}
+	return newPool(workers, queueSize, handler, opts)
+}
+
+// newPool 校验参数并启动 worker。
+func newPool[T any](workers, queueSize int, handler func(context.Context, T), opts []Option) (*Pool[T], error) {
 	if workers < 1 || workers > maxWorkers {
 		return nil, fmt.Errorf("%w: got %d, must be in [1, %d]", ErrInvalidWorkers, workers, maxWorkers)
 	}
@@ -79,7 +107,18 @@ func New[T any](workers, queueSize int, handler func(T), opts ...Option) (*Pool[
 // worker 是工作协程。
 // 从 queue 中读取任务直到 channel 关闭（优雅关闭）。
 func (p *Pool[T]) worker() {
+	if p.opts.store == nil {
+		for task := range p.queue {
+			p.safeHandle(task)
+		}
+		return
+	}
 	for task := range p.queue {
+		if p.aborted.Load() {
+			p.stashTask(task)
+			continue
+		}
+		p.started.Add(1)
 		p.safeHandle(task)
 	}
 }
@@ -97,19 +136,70 @@ func (p *Pool[T]) safeHandle(task T) {
 				slog.Any("panic", r),
 				slog.String("stack", string(debug.Stack())),
 			)
-			if p.opts.logTaskValue {
-				attrs = append(attrs, slog.Any("task", task))
-			} else {
-				attrs = append(attrs, slog.String("task_type", fmt.Sprintf("%T", task)))
-			}
-			if p.opts.name != "" {
-				attrs = append(attrs, slog.String("pool", p.opts.name))
-			}
+			attrs = p.appendTaskAttrs(attrs, task)
 			p.opts.logger.LogAttrs(context.Background(), slog.LevelError,
 				"xpool: worker panic recovered", attrs...)
 		}
 	}()
-	p.handler(task)
+
+	if p.opts.taskTimeout <= 0 {
+		p.handler(context.Background(), task)
+		return
+	}
+	p.handleWithTimeout(task)
+}
+
+// handleWithTimeout 在带超时的独立 context 中执行 handler。
+//
+// 超时后 ctx 被取消；若 handler 在超时后再经过一个 taskTimeout 仍未返回，
+// 视为不响应 context，记录一次告警。worker 无法强制中断 handler，只能等待其返回。
+func (p *Pool[T]) handleWithTimeout(task T) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.taskTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// defer 按后进先出执行：stop 先于 cancel 调用，正常返回时 cancel 不会触发告警检查
+	stop := context.AfterFunc(ctx, func() {
+		timer := time.NewTimer(p.opts.taskTimeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			p.logUnresponsiveTask(task)
+		}
+	})
+	defer stop()
+
+	p.handler(ctx, task)
+}
+
+// logUnresponsiveTask 记录超时后仍未返回的任务。
+func (p *Pool[T]) logUnresponsiveTask(task T) {
+	attrs := make([]slog.Attr, 0, 3) // 预分配：timeout + task/task_type + pool（可选）
+	attrs = append(attrs, slog.Duration("timeout", p.opts.taskTimeout))
+	attrs = p.appendTaskAttrs(attrs, task)
+	p.opts.logger.LogAttrs(context.Background(), slog.LevelWarn,
+		"xpool: task still running after timeout, handler may ignore context", attrs...)
+}
+
+// appendTaskAttrs 追加任务标识和 pool 名称日志属性，遵循 WithLogTaskValue 的脱敏策略。
+func (p *Pool[T]) appendTaskAttrs(attrs []slog.Attr, task T) []slog.Attr {
+	if p.opts.logTaskValue {
+		attrs = append(attrs, slog.Any("task", task))
+	} else {
+		attrs = append(attrs, slog.String("task_type", fmt.Sprintf("%T", task)))
+	}
+	return p.appendPoolAttr(attrs)
+}
+
+// appendPoolAttr 追加 pool 名称日志属性（如已设置）。
+func (p *Pool[T]) appendPoolAttr(attrs []slog.Attr) []slog.Attr {
+	if p.opts.name != "" {
+		attrs = append(attrs, slog.String("pool", p.opts.name))
+	}
+	return attrs
 }
 
 // Submit 提交任务到 worker pool。
@@ -125,6 +215,9 @@ func (p *Pool[T]) Submit(task T) error {
 
 	select {
 	case p.queue <- task:
+		if p.opts.store != nil {
+			p.accepted.Add(1)
+		}
 		return nil
 	default:
 		return ErrQueueFull
@@ -144,6 +237,9 @@ func (p *Pool[T]) Submit(task T) error {
 //   - ctx 超时返回后，残留的 worker goroutine 仍在后台运行，
 //     会继续处理队列中的剩余任务直到耗尽后自行退出；
 //     可通过 [Pool.Done] 等待所有 worker 最终完成
+//   - 配置 [WithTaskPersistence] 时，ctx 到期后不再处理队列中的剩余任务，
+//     而是将其持久化到 TaskStore（正在执行的任务继续执行完成）；
+//     持久化失败时返回同时包含 ctx 错误与 [ErrPersistFailed] 的错误
 func (p *Pool[T]) Shutdown(ctx context.Context) error {
 	if ctx == nil {
 		return ErrNilContext
@@ -173,6 +269,11 @@ func (p *Pool[T]) Shutdown(ctx context.Context) error {
 	case <-p.workersDone:
 		return nil
 	case <-ctx.Done():
+		if p.opts.store != nil {
+			if err := p.persistRemaining(ctx); err != nil {
+				return errors.Join(ctx.Err(), err)
+			}
+		}
 		return ctx.Err()
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log/slog"
 	"math"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -601,3 +602,105 @@ func TestWorkerPool_DoneAfterShutdownTimeout(t *testing.T) {
 		t.Fatal("Done() should close after workers finish")
 	}
 }
+
+// lockedBuffer 并发安全的日志缓冲区（超时告警由后台 goroutine 写入）。
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWorkerPool_NewWithContextNilHandler(t *testing.T) {
+	_, err := NewWithContext[int](1, 10, nil)
+	assert.ErrorIs(t, err, ErrNilHandler)
+}
+
+func TestWorkerPool_TaskTimeoutCancelsContext(t *testing.T) {
+	errs := make(chan error, 1)
+	pool, err := NewWithContext(1, 10, func(ctx context.Context, _ int) {
+		_, hasDeadline := ctx.Deadline()
+		if !hasDeadline {
+			errs <- nil
+			return
+		}
+		<-ctx.Done()
+		errs <- ctx.Err()
+	}, WithTaskTimeout(20*time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Submit(1))
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("task context should be cancelled after timeout")
+	}
+	require.NoError(t, pool.Close())
+}
+
+func TestWorkerPool_NoTaskTimeout(t *testing.T) {
+	deadlines := make(chan bool, 1)
+	pool, err := NewWithContext(1, 10, func(ctx context.Context, _ int) {
+		_, ok := ctx.Deadline()
+		deadlines <- ok
+	}, WithTaskTimeout(0))
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Submit(1))
+	require.NoError(t, pool.Close())
+	assert.False(t, <-deadlines, "context should have no deadline without WithTaskTimeout")
+}
+
+func TestWorkerPool_TaskTimeoutUnresponsive(t *testing.T) {
+	var buf lockedBuffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	release := make(chan struct{})
+
+	// New 的 handler 无法感知 context，超时后仅能告警
+	pool := newPoolForTest(t, 1, 10, func(_ int) {
+		<-release
+	}, WithLogger(logger), WithName("slow"), WithTaskTimeout(10*time.Millisecond))
+
+	require.NoError(t, pool.Submit(1))
+	assert.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "task still running after timeout")
+	}, time.Second, 5*time.Millisecond)
+
+	close(release)
+	require.NoError(t, pool.Close())
+
+	logOutput := buf.String()
+	assert.Contains(t, logOutput, "level=WARN")
+	assert.Contains(t, logOutput, "timeout=10ms")
+	assert.Contains(t, logOutput, "task_type=int")
+	assert.Contains(t, logOutput, "pool=slow")
+}
+
+func TestWorkerPool_TaskTimeoutResponsiveNoWarning(t *testing.T) {
+	var buf lockedBuffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	pool, err := NewWithContext(1, 10, func(ctx context.Context, _ int) {
+		<-ctx.Done()
+	}, WithLogger(logger), WithTaskTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Submit(1))
+	require.NoError(t, pool.Submit(2))
+	require.NoError(t, pool.Close())
+
+	// 等待超过告警宽限期，确认及时返回的任务不会告警
+	time.Sleep(30 * time.Millisecond)
+	assert.NotContains(t, buf.String(), "task still running after timeout")
+}